@@ -0,0 +1,76 @@
+import { ref, type Ref } from 'vue';
+import type { ArticleHighlight, ArticleTag } from '@/types/models';
+import { applyHighlights } from '@/utils/highlights';
+import { proxyImagesInHtml, isMediaCacheEnabled } from '@/utils/mediaProxy';
+
+interface HighlightResult {
+  language: string;
+  highlights: ArticleHighlight[];
+  is_too_short: boolean;
+}
+
+export function useArticleHighlights() {
+  const highlightedContent: Ref<string> = ref('');
+  const isLoadingHighlights = ref(false);
+  const tags: Ref<ArticleTag[]> = ref([]);
+
+  // Load key sentences and mark them in the article content.
+  // Highlights are computed locally by the backend and never use an AI provider.
+  async function loadHighlights(articleId: number, length: string): Promise<boolean> {
+    isLoadingHighlights.value = true;
+    try {
+      const [highlightRes, contentRes] = await Promise.all([
+        fetch(`/api/articles/highlights?id=${articleId}&length=${length}`),
+        fetch(`/api/articles/content?id=${articleId}`),
+      ]);
+      if (!highlightRes.ok || !contentRes.ok) {
+        return false;
+      }
+
+      const result: HighlightResult = await highlightRes.json();
+      const data = await contentRes.json();
+
+      // Offsets refer to the original content, so mark it before proxying images
+      let content = applyHighlights(data.content || '', result.highlights || []);
+      const cacheEnabled = await isMediaCacheEnabled();
+      if (cacheEnabled && content) {
+        content = proxyImagesInHtml(content, data.feed_url || '');
+      }
+
+      highlightedContent.value = content;
+      return result.highlights?.length > 0;
+    } catch (e) {
+      console.error('Error loading highlights:', e);
+      return false;
+    } finally {
+      isLoadingHighlights.value = false;
+    }
+  }
+
+  // Load keyphrase tags of an article
+  async function loadTags(articleId: number): Promise<void> {
+    try {
+      const res = await fetch(`/api/articles/tags?id=${articleId}`);
+      if (res.ok) {
+        const data = await res.json();
+        tags.value = data.tags || [];
+      }
+    } catch (e) {
+      console.error('Error loading article tags:', e);
+    }
+  }
+
+  function clearHighlights(): void {
+    highlightedContent.value = '';
+    tags.value = [];
+  }
+
+  return {
+    highlightedContent,
+    isLoadingHighlights,
+    tags,
+    loadHighlights,
+    loadTags,
+    clearHighlights,
+  };
+}
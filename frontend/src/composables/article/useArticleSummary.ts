@@ -113,12 +113,15 @@ export function useArticleSummary() {
       } else {
         // Handle API errors properly
         let errorMessage = `${t('summaryGenerationFailed')}: ${res.status} ${res.statusText}`;
+        let isTooShort = false;
 
         try {
           const errorData = await res.json();
           if (errorData.error) {
             errorMessage = errorData.error;
           }
+          // Articles without content are reported as too short to summarize
+          isTooShort = errorData.is_too_short === true;
         } catch (jsonError) {
           // If we can't parse JSON, use the status text
           console.error('Error parsing error response:', jsonError);
@@ -130,7 +133,7 @@ export function useArticleSummary() {
         const errorResult: SummaryResult = {
           summary: '',
           sentence_count: 0,
-          is_too_short: false,
+          is_too_short: isTooShort,
           error: errorMessage,
         };
         summaryCache.value.set(article.id, errorResult);
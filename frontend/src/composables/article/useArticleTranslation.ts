@@ -1,5 +1,6 @@
-import { ref, type Ref } from 'vue';
+import { ref, computed, type Ref } from 'vue';
 import { useI18n } from 'vue-i18n';
+import { useAppStore } from '@/stores/app';
 import type { Article } from '@/types/models';
 
 interface TranslationSettings {
@@ -9,6 +10,7 @@ interface TranslationSettings {
 
 export function useArticleTranslation() {
   const { t } = useI18n();
+  const store = useAppStore();
   const translationSettings = ref<TranslationSettings>({
     enabled: false,
     targetLang: 'en',
@@ -30,6 +32,28 @@ export function useArticleTranslation() {
     }
   }
 
+  // Resolve the translation settings of an article, applying the overrides of its feed
+  // the same way the backend resolves the AI policy of a feed
+  function resolveTranslation(article: Article | null | undefined): TranslationSettings {
+    const feed = article ? store.feeds.find((f) => f.id === article.feed_id) : undefined;
+    let enabled = translationSettings.value.enabled;
+    if (feed?.translation_mode === 'enabled') {
+      enabled = true;
+    } else if (feed?.translation_mode === 'disabled') {
+      enabled = false;
+    }
+    return {
+      enabled,
+      targetLang: feed?.translation_target_language || translationSettings.value.targetLang,
+    };
+  }
+
+  // Whether any article can be auto-translated, globally or through a feed override
+  const translationActive = computed(
+    () =>
+      translationSettings.value.enabled || store.feeds.some((f) => f.translation_mode === 'enabled')
+  );
+
   // Setup intersection observer for auto-translation
   function setupIntersectionObserver(listRef: HTMLElement | null, articles: Article[]): void {
     if (observer) {
@@ -43,8 +67,14 @@ export function useArticleTranslation() {
             const articleId = parseInt((entry.target as HTMLElement).dataset.articleId || '0');
             const article = articles.find((a) => a.id === articleId);
 
-            // Only translate if article exists, has no translation, and is not already being translated
-            if (article && !article.translated_title && !translatingArticles.value.has(articleId)) {
+            // Only translate if translation is enabled for the article, it has no translation,
+            // and is not already being translated
+            if (
+              article &&
+              resolveTranslation(article).enabled &&
+              !article.translated_title &&
+              !translatingArticles.value.has(articleId)
+            ) {
               translateArticle(article);
             }
           }
@@ -58,7 +88,7 @@ export function useArticleTranslation() {
     );
 
     // Automatically observe all current article elements
-    if (listRef && translationSettings.value.enabled) {
+    if (listRef && translationActive.value) {
       // Use setTimeout to ensure DOM is updated
       setTimeout(() => {
         const cards = listRef.querySelectorAll('[data-article-id]');
@@ -80,7 +110,7 @@ export function useArticleTranslation() {
         body: JSON.stringify({
           article_id: article.id,
           title: article.title,
-          target_language: translationSettings.value.targetLang,
+          target_language: resolveTranslation(article).targetLang,
         }),
       });
 
@@ -107,7 +137,7 @@ export function useArticleTranslation() {
 
   // Observe an article element
   function observeArticle(el: Element | null): void {
-    if (el && observer && translationSettings.value.enabled) {
+    if (el && observer && translationActive.value) {
       observer.observe(el);
     }
   }
@@ -117,12 +147,12 @@ export function useArticleTranslation() {
     translationSettings.value = { enabled, targetLang };
 
     // Disconnect observer if translation is disabled
-    if (!enabled && observer) {
+    if (!translationActive.value && observer) {
       observer.disconnect();
       observer = null;
     }
     // Re-observe if translation is enabled
-    else if (enabled && observer) {
+    else if (translationActive.value && observer) {
       setTimeout(() => {
         const cards = document.querySelectorAll('[data-article-id]');
         cards.forEach((card) => observer?.observe(card));
@@ -140,7 +170,9 @@ export function useArticleTranslation() {
 
   return {
     translationSettings,
+    translationActive,
     translatingArticles,
+    resolveTranslation,
     loadTranslationSettings,
     setupIntersectionObserver,
     translateArticle,
@@ -14,13 +14,18 @@ import { settingsDefaults } from '@/config/defaults';
 export function generateInitialSettings(): SettingsData {
   return {
     ai_api_key: settingsDefaults.ai_api_key,
+    ai_budget_alert_threshold: settingsDefaults.ai_budget_alert_threshold,
     ai_chat_enabled: settingsDefaults.ai_chat_enabled,
+    ai_cost_budget: settingsDefaults.ai_cost_budget,
     ai_custom_headers: settingsDefaults.ai_custom_headers,
     ai_endpoint: settingsDefaults.ai_endpoint,
     ai_model: settingsDefaults.ai_model,
+    ai_price_table: settingsDefaults.ai_price_table,
     ai_summary_prompt: settingsDefaults.ai_summary_prompt,
     ai_translation_prompt: settingsDefaults.ai_translation_prompt,
+    ai_usage_auto_reset: settingsDefaults.ai_usage_auto_reset,
     ai_usage_limit: settingsDefaults.ai_usage_limit,
+    ai_usage_period: settingsDefaults.ai_usage_period,
     ai_usage_tokens: settingsDefaults.ai_usage_tokens,
     auto_cleanup_enabled: settingsDefaults.auto_cleanup_enabled,
     auto_show_all_content: settingsDefaults.auto_show_all_content,
@@ -32,6 +37,12 @@ export function generateInitialSettings(): SettingsData {
     deepl_api_key: settingsDefaults.deepl_api_key,
     deepl_endpoint: settingsDefaults.deepl_endpoint,
     default_view_mode: settingsDefaults.default_view_mode,
+    embedding_api_key: settingsDefaults.embedding_api_key,
+    embedding_dedup_enabled: settingsDefaults.embedding_dedup_enabled,
+    embedding_dedup_threshold: settingsDefaults.embedding_dedup_threshold,
+    embedding_enabled: settingsDefaults.embedding_enabled,
+    embedding_endpoint: settingsDefaults.embedding_endpoint,
+    embedding_model: settingsDefaults.embedding_model,
     freshrss_api_password: settingsDefaults.freshrss_api_password,
     freshrss_auto_sync_interval: settingsDefaults.freshrss_auto_sync_interval,
     freshrss_enabled: settingsDefaults.freshrss_enabled,
@@ -52,13 +63,24 @@ export function generateInitialSettings(): SettingsData {
     media_cache_enabled: settingsDefaults.media_cache_enabled,
     media_cache_max_age_days: settingsDefaults.media_cache_max_age_days,
     media_cache_max_size_mb: settingsDefaults.media_cache_max_size_mb,
+    media_proxy_allowed_hosts: settingsDefaults.media_proxy_allowed_hosts,
+    media_proxy_denied_hosts: settingsDefaults.media_proxy_denied_hosts,
     media_proxy_fallback: settingsDefaults.media_proxy_fallback,
+    media_proxy_max_size_mb: settingsDefaults.media_proxy_max_size_mb,
+    media_proxy_private_networks: settingsDefaults.media_proxy_private_networks,
     network_bandwidth_mbps: settingsDefaults.network_bandwidth_mbps,
     network_latency_ms: settingsDefaults.network_latency_ms,
     network_speed: settingsDefaults.network_speed,
     obsidian_enabled: settingsDefaults.obsidian_enabled,
     obsidian_vault: settingsDefaults.obsidian_vault,
     obsidian_vault_path: settingsDefaults.obsidian_vault_path,
+    offline_prefetch_categories: settingsDefaults.offline_prefetch_categories,
+    offline_prefetch_enabled: settingsDefaults.offline_prefetch_enabled,
+    offline_prefetch_favorites: settingsDefaults.offline_prefetch_favorites,
+    offline_prefetch_max_size_mb: settingsDefaults.offline_prefetch_max_size_mb,
+    offline_prefetch_read_later: settingsDefaults.offline_prefetch_read_later,
+    offline_prefetch_unread: settingsDefaults.offline_prefetch_unread,
+    podcast_download_max_size_mb: settingsDefaults.podcast_download_max_size_mb,
     proxy_enabled: settingsDefaults.proxy_enabled,
     proxy_host: settingsDefaults.proxy_host,
     proxy_password: settingsDefaults.proxy_password,
@@ -82,6 +104,8 @@ export function generateInitialSettings(): SettingsData {
     translation_enabled: settingsDefaults.translation_enabled,
     translation_provider: settingsDefaults.translation_provider,
     update_interval: settingsDefaults.update_interval,
+    video_transcripts_enabled: settingsDefaults.video_transcripts_enabled,
+    websub_callback_url: settingsDefaults.websub_callback_url,
     window_height: settingsDefaults.window_height,
     window_maximized: settingsDefaults.window_maximized,
     window_width: settingsDefaults.window_width,
@@ -97,13 +121,19 @@ export function generateInitialSettings(): SettingsData {
 export function parseSettingsData(data: Record<string, string>): SettingsData {
   return {
     ai_api_key: data.ai_api_key || settingsDefaults.ai_api_key,
+    ai_budget_alert_threshold:
+      data.ai_budget_alert_threshold || settingsDefaults.ai_budget_alert_threshold,
     ai_chat_enabled: data.ai_chat_enabled === 'true',
+    ai_cost_budget: data.ai_cost_budget || settingsDefaults.ai_cost_budget,
     ai_custom_headers: data.ai_custom_headers || settingsDefaults.ai_custom_headers,
     ai_endpoint: data.ai_endpoint || settingsDefaults.ai_endpoint,
     ai_model: data.ai_model || settingsDefaults.ai_model,
+    ai_price_table: data.ai_price_table || settingsDefaults.ai_price_table,
     ai_summary_prompt: data.ai_summary_prompt || settingsDefaults.ai_summary_prompt,
     ai_translation_prompt: data.ai_translation_prompt || settingsDefaults.ai_translation_prompt,
+    ai_usage_auto_reset: data.ai_usage_auto_reset === 'true',
     ai_usage_limit: data.ai_usage_limit || settingsDefaults.ai_usage_limit,
+    ai_usage_period: data.ai_usage_period || settingsDefaults.ai_usage_period,
     ai_usage_tokens: data.ai_usage_tokens || settingsDefaults.ai_usage_tokens,
     auto_cleanup_enabled: data.auto_cleanup_enabled === 'true',
     auto_show_all_content: data.auto_show_all_content === 'true',
@@ -115,6 +145,13 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
     deepl_api_key: data.deepl_api_key || settingsDefaults.deepl_api_key,
     deepl_endpoint: data.deepl_endpoint || settingsDefaults.deepl_endpoint,
     default_view_mode: data.default_view_mode || settingsDefaults.default_view_mode,
+    embedding_api_key: data.embedding_api_key || settingsDefaults.embedding_api_key,
+    embedding_dedup_enabled: data.embedding_dedup_enabled === 'true',
+    embedding_dedup_threshold:
+      data.embedding_dedup_threshold || settingsDefaults.embedding_dedup_threshold,
+    embedding_enabled: data.embedding_enabled === 'true',
+    embedding_endpoint: data.embedding_endpoint || settingsDefaults.embedding_endpoint,
+    embedding_model: data.embedding_model || settingsDefaults.embedding_model,
     freshrss_api_password: data.freshrss_api_password || settingsDefaults.freshrss_api_password,
     freshrss_auto_sync_interval:
       parseInt(data.freshrss_auto_sync_interval) || settingsDefaults.freshrss_auto_sync_interval,
@@ -142,13 +179,31 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
       parseInt(data.media_cache_max_age_days) || settingsDefaults.media_cache_max_age_days,
     media_cache_max_size_mb:
       parseInt(data.media_cache_max_size_mb) || settingsDefaults.media_cache_max_size_mb,
+    media_proxy_allowed_hosts:
+      data.media_proxy_allowed_hosts || settingsDefaults.media_proxy_allowed_hosts,
+    media_proxy_denied_hosts:
+      data.media_proxy_denied_hosts || settingsDefaults.media_proxy_denied_hosts,
     media_proxy_fallback: data.media_proxy_fallback === 'true',
+    media_proxy_max_size_mb:
+      parseInt(data.media_proxy_max_size_mb) || settingsDefaults.media_proxy_max_size_mb,
+    media_proxy_private_networks:
+      data.media_proxy_private_networks || settingsDefaults.media_proxy_private_networks,
     network_bandwidth_mbps: data.network_bandwidth_mbps || settingsDefaults.network_bandwidth_mbps,
     network_latency_ms: data.network_latency_ms || settingsDefaults.network_latency_ms,
     network_speed: data.network_speed || settingsDefaults.network_speed,
     obsidian_enabled: data.obsidian_enabled === 'true',
     obsidian_vault: data.obsidian_vault || settingsDefaults.obsidian_vault,
     obsidian_vault_path: data.obsidian_vault_path || settingsDefaults.obsidian_vault_path,
+    offline_prefetch_categories:
+      data.offline_prefetch_categories || settingsDefaults.offline_prefetch_categories,
+    offline_prefetch_enabled: data.offline_prefetch_enabled === 'true',
+    offline_prefetch_favorites: data.offline_prefetch_favorites === 'true',
+    offline_prefetch_max_size_mb:
+      parseInt(data.offline_prefetch_max_size_mb) || settingsDefaults.offline_prefetch_max_size_mb,
+    offline_prefetch_read_later: data.offline_prefetch_read_later === 'true',
+    offline_prefetch_unread: data.offline_prefetch_unread === 'true',
+    podcast_download_max_size_mb:
+      parseInt(data.podcast_download_max_size_mb) || settingsDefaults.podcast_download_max_size_mb,
     proxy_enabled: data.proxy_enabled === 'true',
     proxy_host: data.proxy_host || settingsDefaults.proxy_host,
     proxy_password: data.proxy_password || settingsDefaults.proxy_password,
@@ -173,6 +228,8 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
     translation_enabled: data.translation_enabled === 'true',
     translation_provider: data.translation_provider || settingsDefaults.translation_provider,
     update_interval: parseInt(data.update_interval) || settingsDefaults.update_interval,
+    video_transcripts_enabled: data.video_transcripts_enabled === 'true',
+    websub_callback_url: data.websub_callback_url || settingsDefaults.websub_callback_url,
     window_height: data.window_height || settingsDefaults.window_height,
     window_maximized: data.window_maximized || settingsDefaults.window_maximized,
     window_width: data.window_width || settingsDefaults.window_width,
@@ -188,15 +245,22 @@ export function parseSettingsData(data: Record<string, string>): SettingsData {
 export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<string, string> {
   return {
     ai_api_key: settingsRef.value.ai_api_key ?? settingsDefaults.ai_api_key,
+    ai_budget_alert_threshold:
+      settingsRef.value.ai_budget_alert_threshold ?? settingsDefaults.ai_budget_alert_threshold,
     ai_chat_enabled: (
       settingsRef.value.ai_chat_enabled ?? settingsDefaults.ai_chat_enabled
     ).toString(),
+    ai_cost_budget: settingsRef.value.ai_cost_budget ?? settingsDefaults.ai_cost_budget,
     ai_custom_headers: settingsRef.value.ai_custom_headers ?? settingsDefaults.ai_custom_headers,
     ai_endpoint: settingsRef.value.ai_endpoint ?? settingsDefaults.ai_endpoint,
     ai_model: settingsRef.value.ai_model ?? settingsDefaults.ai_model,
+    ai_price_table: settingsRef.value.ai_price_table ?? settingsDefaults.ai_price_table,
     ai_summary_prompt: settingsRef.value.ai_summary_prompt ?? settingsDefaults.ai_summary_prompt,
     ai_translation_prompt:
       settingsRef.value.ai_translation_prompt ?? settingsDefaults.ai_translation_prompt,
+    ai_usage_auto_reset: (
+      settingsRef.value.ai_usage_auto_reset ?? settingsDefaults.ai_usage_auto_reset
+    ).toString(),
     ai_usage_limit: settingsRef.value.ai_usage_limit ?? settingsDefaults.ai_usage_limit,
     ai_usage_tokens: settingsRef.value.ai_usage_tokens ?? settingsDefaults.ai_usage_tokens,
     auto_cleanup_enabled: (
@@ -213,6 +277,17 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     deepl_api_key: settingsRef.value.deepl_api_key ?? settingsDefaults.deepl_api_key,
     deepl_endpoint: settingsRef.value.deepl_endpoint ?? settingsDefaults.deepl_endpoint,
     default_view_mode: settingsRef.value.default_view_mode ?? settingsDefaults.default_view_mode,
+    embedding_api_key: settingsRef.value.embedding_api_key ?? settingsDefaults.embedding_api_key,
+    embedding_dedup_enabled: (
+      settingsRef.value.embedding_dedup_enabled ?? settingsDefaults.embedding_dedup_enabled
+    ).toString(),
+    embedding_dedup_threshold:
+      settingsRef.value.embedding_dedup_threshold ?? settingsDefaults.embedding_dedup_threshold,
+    embedding_enabled: (
+      settingsRef.value.embedding_enabled ?? settingsDefaults.embedding_enabled
+    ).toString(),
+    embedding_endpoint: settingsRef.value.embedding_endpoint ?? settingsDefaults.embedding_endpoint,
+    embedding_model: settingsRef.value.embedding_model ?? settingsDefaults.embedding_model,
     freshrss_api_password:
       settingsRef.value.freshrss_api_password ?? settingsDefaults.freshrss_api_password,
     freshrss_auto_sync_interval: (
@@ -259,9 +334,18 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     media_cache_max_size_mb: (
       settingsRef.value.media_cache_max_size_mb ?? settingsDefaults.media_cache_max_size_mb
     ).toString(),
+    media_proxy_allowed_hosts:
+      settingsRef.value.media_proxy_allowed_hosts ?? settingsDefaults.media_proxy_allowed_hosts,
+    media_proxy_denied_hosts:
+      settingsRef.value.media_proxy_denied_hosts ?? settingsDefaults.media_proxy_denied_hosts,
     media_proxy_fallback: (
       settingsRef.value.media_proxy_fallback ?? settingsDefaults.media_proxy_fallback
     ).toString(),
+    media_proxy_max_size_mb: (
+      settingsRef.value.media_proxy_max_size_mb ?? settingsDefaults.media_proxy_max_size_mb
+    ).toString(),
+    media_proxy_private_networks:
+      settingsRef.value.media_proxy_private_networks ?? settingsDefaults.media_proxy_private_networks,
     network_bandwidth_mbps:
       settingsRef.value.network_bandwidth_mbps ?? settingsDefaults.network_bandwidth_mbps,
     network_latency_ms: settingsRef.value.network_latency_ms ?? settingsDefaults.network_latency_ms,
@@ -272,6 +356,26 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     obsidian_vault: settingsRef.value.obsidian_vault ?? settingsDefaults.obsidian_vault,
     obsidian_vault_path:
       settingsRef.value.obsidian_vault_path ?? settingsDefaults.obsidian_vault_path,
+    offline_prefetch_categories:
+      settingsRef.value.offline_prefetch_categories ?? settingsDefaults.offline_prefetch_categories,
+    offline_prefetch_enabled: (
+      settingsRef.value.offline_prefetch_enabled ?? settingsDefaults.offline_prefetch_enabled
+    ).toString(),
+    offline_prefetch_favorites: (
+      settingsRef.value.offline_prefetch_favorites ?? settingsDefaults.offline_prefetch_favorites
+    ).toString(),
+    offline_prefetch_max_size_mb: (
+      settingsRef.value.offline_prefetch_max_size_mb ?? settingsDefaults.offline_prefetch_max_size_mb
+    ).toString(),
+    offline_prefetch_read_later: (
+      settingsRef.value.offline_prefetch_read_later ?? settingsDefaults.offline_prefetch_read_later
+    ).toString(),
+    offline_prefetch_unread: (
+      settingsRef.value.offline_prefetch_unread ?? settingsDefaults.offline_prefetch_unread
+    ).toString(),
+    podcast_download_max_size_mb: (
+      settingsRef.value.podcast_download_max_size_mb ?? settingsDefaults.podcast_download_max_size_mb
+    ).toString(),
     proxy_enabled: (settingsRef.value.proxy_enabled ?? settingsDefaults.proxy_enabled).toString(),
     proxy_host: settingsRef.value.proxy_host ?? settingsDefaults.proxy_host,
     proxy_password: settingsRef.value.proxy_password ?? settingsDefaults.proxy_password,
@@ -313,5 +417,10 @@ export function buildAutoSavePayload(settingsRef: Ref<SettingsData>): Record<str
     update_interval: (
       settingsRef.value.update_interval ?? settingsDefaults.update_interval
     ).toString(),
+    video_transcripts_enabled: (
+      settingsRef.value.video_transcripts_enabled ?? settingsDefaults.video_transcripts_enabled
+    ).toString(),
+    websub_callback_url:
+      settingsRef.value.websub_callback_url ?? settingsDefaults.websub_callback_url,
   };
 }
@@ -0,0 +1,236 @@
+import { ref, computed, onUnmounted, type Ref } from 'vue';
+import { useAppStore } from '@/stores/app';
+import { useI18n } from 'vue-i18n';
+import type { DiscoveredFeed, ProgressCounts, ProgressState } from './useDiscoverAllFeeds';
+
+export interface FeedRecommendation extends DiscoveredFeed {
+  domain: string;
+  link_count: number;
+  feed_count: number;
+  feed_ids: number[];
+}
+
+interface RecommendationState extends ProgressState {
+  recommendations?: FeedRecommendation[];
+}
+
+export function useFeedRecommendations() {
+  const store = useAppStore();
+  const { t } = useI18n();
+
+  const isLoading = ref(false);
+  const recommendations: Ref<FeedRecommendation[]> = ref([]);
+  const selectedFeeds: Ref<Set<number>> = ref(new Set());
+  const errorMessage = ref('');
+  const progressMessage = ref('');
+  const progressDetail = ref('');
+  const progressCounts: Ref<ProgressCounts> = ref({ current: 0, total: 0, found: 0 });
+  const isSubscribing = ref(false);
+  let pollInterval: ReturnType<typeof setInterval> | null = null;
+
+  function getHostname(url: string): string {
+    try {
+      return new URL(url).hostname;
+    } catch {
+      return url;
+    }
+  }
+
+  function stopPolling() {
+    if (pollInterval) {
+      clearInterval(pollInterval);
+      pollInterval = null;
+    }
+  }
+
+  // Explains why a feed is recommended, naming up to three of the feeds linking to it
+  const reasons = computed(() =>
+    recommendations.value.map((rec) => {
+      const reason = t('recommendationReason', { links: rec.link_count, feeds: rec.feed_count });
+      const titles = (rec.feed_ids || [])
+        .map((id) => store.feeds.find((f) => f.id === id)?.title)
+        .filter((title): title is string => !!title)
+        .slice(0, 3);
+      if (titles.length === 0) {
+        return reason;
+      }
+      return `${reason} · ${t('recommendationLinkedFrom', { feeds: titles.join(', ') })}`;
+    })
+  );
+
+  async function startRecommendations() {
+    isLoading.value = true;
+    errorMessage.value = '';
+    recommendations.value = [];
+    selectedFeeds.value.clear();
+    progressMessage.value = t('collectingLinks');
+    progressDetail.value = '';
+    progressCounts.value = { current: 0, total: 0, found: 0 };
+    stopPolling();
+
+    try {
+      await fetch('/api/feeds/recommendations/clear', { method: 'POST' });
+
+      const startResponse = await fetch('/api/feeds/recommendations/start', { method: 'POST' });
+      if (!startResponse.ok) {
+        const errorText = await startResponse.text();
+        throw new Error(errorText || 'Failed to start recommendations');
+      }
+
+      pollInterval = setInterval(async () => {
+        try {
+          const progressResponse = await fetch('/api/feeds/recommendations/progress');
+          if (!progressResponse.ok) {
+            throw new Error('Failed to get progress');
+          }
+
+          const state = (await progressResponse.json()) as RecommendationState;
+
+          if (state.progress) {
+            const progress = state.progress;
+            switch (progress.stage) {
+              case 'starting':
+              case 'collecting_links':
+                progressMessage.value = t('collectingLinks');
+                progressDetail.value = '';
+                break;
+              case 'checking_rss':
+                progressMessage.value = t('checkingRssFeed');
+                progressDetail.value = progress.detail ? getHostname(progress.detail) : '';
+                break;
+              default:
+                progressMessage.value = progress.message || t('discovering');
+                progressDetail.value = '';
+            }
+            progressCounts.value.current = progress.current || 0;
+            progressCounts.value.total = progress.total || 0;
+            progressCounts.value.found = progress.found_count || 0;
+          }
+
+          if (state.is_complete) {
+            stopPolling();
+
+            if (state.error) {
+              errorMessage.value = state.error;
+            } else {
+              recommendations.value = state.recommendations || [];
+              if (recommendations.value.length === 0) {
+                errorMessage.value = t('noRecommendationsFound');
+              }
+            }
+
+            isLoading.value = false;
+            progressMessage.value = '';
+            progressDetail.value = '';
+
+            await fetch('/api/feeds/recommendations/clear', { method: 'POST' });
+          }
+        } catch (pollError) {
+          console.error('Polling error:', pollError);
+          // Don't stop polling on transient errors
+        }
+      }, 500);
+    } catch (error) {
+      console.error('Recommendations error:', error);
+      errorMessage.value = t('discoveryFailed') + ': ' + (error as Error).message;
+      isLoading.value = false;
+      progressMessage.value = '';
+      progressDetail.value = '';
+      stopPolling();
+    }
+  }
+
+  function toggleFeedSelection(index: number) {
+    if (selectedFeeds.value.has(index)) {
+      selectedFeeds.value.delete(index);
+    } else {
+      selectedFeeds.value.add(index);
+    }
+  }
+
+  function selectAll() {
+    if (selectedFeeds.value.size === recommendations.value.length) {
+      selectedFeeds.value.clear();
+    } else {
+      recommendations.value.forEach((_, index) => selectedFeeds.value.add(index));
+    }
+  }
+
+  const hasSelection = computed(() => selectedFeeds.value.size > 0);
+  const allSelected = computed(
+    () =>
+      recommendations.value.length > 0 && selectedFeeds.value.size === recommendations.value.length
+  );
+
+  async function subscribeSelected() {
+    if (!hasSelection.value) return;
+
+    isSubscribing.value = true;
+    const subscribePromises = [];
+
+    for (const index of selectedFeeds.value) {
+      const feed = recommendations.value[index];
+      subscribePromises.push(
+        fetch('/api/feeds/add', {
+          method: 'POST',
+          headers: { 'Content-Type': 'application/json' },
+          body: JSON.stringify({
+            url: feed.rss_feed,
+            category: '',
+            title: feed.name,
+          }),
+        })
+      );
+    }
+
+    try {
+      const results = await Promise.allSettled(subscribePromises);
+      const successful = results.filter((r) => r.status === 'fulfilled').length;
+      const failed = results.filter((r) => r.status === 'rejected').length;
+
+      await store.fetchFeeds();
+
+      if (failed === 0) {
+        window.showToast(t('feedsSubscribedSuccess', { count: successful }), 'success');
+      } else {
+        window.showToast(t('feedsSubscribedPartial', { successful, failed }), 'warning');
+      }
+    } catch (error) {
+      console.error('Subscription error:', error);
+      window.showToast(t('errorSubscribingFeeds'), 'error');
+    } finally {
+      isSubscribing.value = false;
+    }
+  }
+
+  function cleanup() {
+    stopPolling();
+    fetch('/api/feeds/recommendations/clear', { method: 'POST' }).catch(() => {});
+  }
+
+  onUnmounted(() => {
+    cleanup();
+  });
+
+  return {
+    // State
+    isLoading,
+    recommendations,
+    reasons,
+    selectedFeeds,
+    errorMessage,
+    progressMessage,
+    progressDetail,
+    progressCounts,
+    isSubscribing,
+    hasSelection,
+    allSelected,
+
+    // Functions
+    startRecommendations,
+    toggleFeedSelection,
+    selectAll,
+    subscribeSelected,
+    cleanup,
+  };
+}
@@ -51,6 +51,13 @@ export function useFeedForm(feed?: Feed) {
   // Auto expand content mode
   const autoExpandContent = ref<'global' | 'enabled' | 'disabled'>('global');
 
+  // Translation and summary policy
+  const translationMode = ref<'global' | 'enabled' | 'disabled'>('global');
+  const translationTargetLanguage = ref('');
+  const summaryProvider = ref<'global' | 'local' | 'ai'>('global');
+  const summaryLength = ref<'global' | 'short' | 'medium' | 'long'>('global');
+  const pregenerateOnFetch = ref(false);
+
   // Proxy settings
   const proxyMode = ref<ProxyMode>('global');
   const proxyType = ref('http');
@@ -214,6 +221,15 @@ export function useFeedForm(feed?: Feed) {
     autoExpandContent.value =
       (feed.auto_expand_content as 'global' | 'enabled' | 'disabled') || 'global';
 
+    // Initialize translation and summary policy
+    translationMode.value =
+      (feed.translation_mode as 'global' | 'enabled' | 'disabled') || 'global';
+    translationTargetLanguage.value = feed.translation_target_language || '';
+    summaryProvider.value = (feed.summary_provider as 'global' | 'local' | 'ai') || 'global';
+    summaryLength.value =
+      (feed.summary_length as 'global' | 'short' | 'medium' | 'long') || 'global';
+    pregenerateOnFetch.value = feed.pregenerate_on_fetch || false;
+
     // Determine feed type based on feed properties
     if (feed.script_path) {
       feedType.value = 'script';
@@ -301,6 +317,11 @@ export function useFeedForm(feed?: Feed) {
     emailFolder.value = 'INBOX';
     articleViewMode.value = 'global';
     autoExpandContent.value = 'global';
+    translationMode.value = 'global';
+    translationTargetLanguage.value = '';
+    summaryProvider.value = 'global';
+    summaryLength.value = 'global';
+    pregenerateOnFetch.value = false;
     proxyMode.value = 'global';
     proxyType.value = 'http';
     proxyHost.value = '';
@@ -367,6 +388,11 @@ export function useFeedForm(feed?: Feed) {
     emailFolder,
     articleViewMode,
     autoExpandContent,
+    translationMode,
+    translationTargetLanguage,
+    summaryProvider,
+    summaryLength,
+    pregenerateOnFetch,
     proxyMode,
     proxyType,
     proxyHost,
@@ -212,6 +212,11 @@ export function useFeedManagement() {
           xpath_item_uid: feed.xpath_item_uid,
           article_view_mode: feed.article_view_mode,
           auto_expand_content: feed.auto_expand_content,
+          translation_mode: feed.translation_mode,
+          translation_target_language: feed.translation_target_language,
+          summary_provider: feed.summary_provider,
+          summary_length: feed.summary_length,
+          pregenerate_on_fetch: feed.pregenerate_on_fetch,
         }),
       });
     });
@@ -1,4 +1,4 @@
-import { computed, type ComputedRef } from 'vue';
+import { computed, ref, type ComputedRef } from 'vue';
 import { useAppStore } from '@/stores/app';
 
 export interface Condition {
@@ -23,6 +23,26 @@ export interface ActionOption {
   labelKey: string;
 }
 
+// Language codes reported by the backend language detector, and 'unknown' for articles
+// whose language could not be detected. Shared by all rule editors and loaded once.
+const articleLanguages = ref<string[]>([]);
+let articleLanguagesLoaded = false;
+
+async function loadArticleLanguages() {
+  if (articleLanguagesLoaded) return;
+  articleLanguagesLoaded = true;
+
+  try {
+    const res = await fetch('/api/rules/languages');
+    if (!res.ok) throw new Error(`HTTP ${res.status}`);
+    articleLanguages.value = await res.json();
+  } catch (e) {
+    console.error('Error loading article languages:', e);
+    // Retry the next time a rule editor is opened
+    articleLanguagesLoaded = false;
+  }
+}
+
 export function useRuleOptions() {
   const store = useAppStore();
 
@@ -44,6 +64,7 @@ export function useRuleOptions() {
       multiSelect: false,
       booleanField: true,
     },
+    { value: 'article_language', labelKey: 'articleLanguage', multiSelect: true },
     { value: 'published_after', labelKey: 'publishedAfter', multiSelect: false },
     { value: 'published_before', labelKey: 'publishedBefore', multiSelect: false },
     { value: 'is_read', labelKey: 'readStatus', multiSelect: false, booleanField: true },
@@ -108,6 +129,8 @@ export function useRuleOptions() {
     return Array.from(types);
   });
 
+  loadArticleLanguages();
+
   return {
     fieldOptions,
     textOperatorOptions,
@@ -116,6 +139,7 @@ export function useRuleOptions() {
     feedNames,
     feedCategories,
     feedTypes,
+    articleLanguages,
   };
 }
 
@@ -125,7 +149,12 @@ export function isDateField(field: string): boolean {
 }
 
 export function isMultiSelectField(field: string): boolean {
-  return field === 'feed_name' || field === 'feed_category' || field === 'feed_type';
+  return (
+    field === 'feed_name' ||
+    field === 'feed_category' ||
+    field === 'feed_type' ||
+    field === 'article_language'
+  );
 }
 
 export function isBooleanField(field: string): boolean {
@@ -29,9 +29,14 @@ const en: TranslationMessages = {
   aiApiKeyDesc: 'API key for AI services (optional)',
   aiApiKeyMissing: 'API key is missing or invalid. Please configure it in settings.',
   aiApiKeyPlaceholder: 'Enter your API key',
+  aiBudgetAlertThreshold: 'Budget Alert Threshold',
+  aiBudgetAlertThresholdDesc: 'Warn when this percentage of the monthly budget has been spent',
   aiChat: 'AI Chat',
   aiChatEnabled: 'AI Chat',
   aiChatEnabledDesc: 'Chat with AI for answers to article-related questions',
+  aiCostBudget: 'Monthly Cost Budget',
+  aiCostBudgetDesc: 'Maximum AI spending per month, computed from the price table (0 = no budget)',
+  aiCostThisMonth: 'Cost This Month',
   clearAllChats: 'Clear Chat History',
   clearAllChatsDesc: 'Delete all AI chat sessions',
   clearAllChatsButton: 'Clear',
@@ -87,6 +92,9 @@ const en: TranslationMessages = {
     'Configure global AI settings for translation and summarization features. These settings apply simultaneously to translation, summarization, and chat functions when an AI provider is selected.',
   aiSettingsIncomplete: 'AI settings incomplete',
   aiSummary: 'AI Summary',
+  aiPriceTable: 'Price Table',
+  aiPriceTableDesc: 'Prices per 1M prompt and completion tokens, by model or by feature, as JSON',
+  aiPriceTablePlaceholder: '{"models": {"gpt-4o-mini": {"prompt": 0.15, "completion": 0.6}}}',
   aiSummaryPrompt: 'Summary Prompt',
   aiSummaryPromptDesc: 'Custom system prompt for AI summarization',
   aiSummaryPromptPlaceholder:
@@ -101,6 +109,8 @@ const en: TranslationMessages = {
   aiTranslationPromptPlaceholder:
     'You are a translator. Translate the given text accurately. Output ONLY the translated text, nothing else.',
   aiUsage: 'AI Usage',
+  aiUsageAutoReset: 'Reset Monthly',
+  aiUsageAutoResetDesc: 'Automatically reset the token counter at the start of each month',
   aiUsageFallback: 'Using free alternative due to AI limit',
   aiUsageLimit: 'Usage Limit',
   aiUsageLimitDesc:
@@ -180,6 +190,28 @@ const en: TranslationMessages = {
   cleanDatabaseTitle: 'Clean Database',
   cleaning: 'Cleaning...',
   cleanupMediaCache: 'Clean Now',
+  backupAndRestore: 'Backup & Restore',
+  backupAndRestoreDesc:
+    'Export feeds, articles, settings, custom CSS and scripts to a single archive, or restore one on another machine',
+  exportBackup: 'Export',
+  restoreBackup: 'Restore',
+  exporting: 'Exporting...',
+  restoring: 'Restoring...',
+  backupPassphrase: 'Backup Passphrase',
+  backupPassphraseExportMessage:
+    'API keys and passwords in the backup are encrypted with this passphrase. You will need it to restore the backup.',
+  backupPassphraseImportMessage: 'Enter the passphrase used when the backup was exported.',
+  backupPassphraseRequired: 'A passphrase is required',
+  restoreBackupConfirm:
+    'Restoring a backup replaces all current feeds, articles and settings. Continue?',
+  backupExported: 'Backup exported',
+  backupRestored: 'Backup restored, reloading...',
+  importFromReaders: 'Import from Other Readers',
+  importFromReadersDesc:
+    'Import starred and read later items from Feedly, Inoreader, Google Takeout, Miniflux, FreshRSS, Pocket or Instapaper exports',
+  importItems: 'Import',
+  importingItems: 'Importing...',
+  readerImportSuccess: 'Imported {created} new items, updated {updated} existing articles',
   clear: 'Clear',
   clearedReadLater: 'Read Later list cleared',
   clearFilters: 'Clear Filters',
@@ -292,6 +324,24 @@ const en: TranslationMessages = {
   editFeed: 'Edit Feed',
   editRule: 'Edit Rule',
   editSubscription: 'Edit Subscription',
+  embeddings: 'Embeddings',
+  embeddingEnabled: 'Enable Embeddings',
+  embeddingEnabledDesc:
+    'Compute article embeddings to find related articles, search by meaning and group the same story from different feeds',
+  embeddingEndpoint: 'Embedding Endpoint',
+  embeddingEndpointDesc:
+    'OpenAI-compatible /embeddings endpoint, or an Ollama /api/embed endpoint for local models',
+  embeddingApiKey: 'Embedding API Key',
+  embeddingApiKeyDesc: 'Leave empty to use the AI API key',
+  embeddingModel: 'Embedding Model',
+  embeddingModelDesc: 'e.g. text-embedding-3-small or nomic-embed-text',
+  embeddingDedupEnabled: 'Group Duplicate Stories',
+  embeddingDedupEnabledDesc:
+    'Show the same story published by several feeds once in the article list, with its alternates',
+  embeddingDedupThreshold: 'Duplicate Similarity',
+  embeddingDedupThresholdDesc:
+    'Similarity (0.5-1) above which two articles count as the same story. Higher is stricter',
+  storyAlternates: '+{count} sources',
   enableProxy: 'Enable Proxy',
   enableProxyDesc: 'Use a proxy server for fetching feeds and articles',
   enableSummary: 'Enable Auto Summary',
@@ -336,6 +386,7 @@ const en: TranslationMessages = {
   feedType: 'Feed Type',
   isFreshRSSFeed: 'Is FreshRSS Feed',
   isImageModeFeed: 'Is Image Mode Feed',
+  articleLanguage: 'Article Language',
   regex: 'Regex',
   rssFeed: 'RSS Feed',
   feedProxy: 'Feed Proxy',
@@ -510,6 +561,28 @@ const en: TranslationMessages = {
   mediaCacheMaxAgeDesc: 'Delete cached media older than this many days',
   mediaCacheMaxSize: 'Max Cache Size',
   mediaCacheMaxSizeDesc: 'Maximum media cache size',
+  offlinePrefetchEnabled: 'Offline Reading',
+  offlinePrefetchEnabledDesc:
+    'Download the full content and images of selected articles after each refresh, so they can be read without a network connection',
+  offlinePrefetchReadLater: 'Read Later Articles',
+  offlinePrefetchReadLaterDesc: 'Download articles saved for later',
+  offlinePrefetchFavorites: 'Favorite Articles',
+  offlinePrefetchFavoritesDesc: 'Download favorite articles',
+  offlinePrefetchUnread: 'Unread Articles',
+  offlinePrefetchUnreadDesc: 'Download the newest unread articles',
+  offlinePrefetchCategories: 'Unread Categories',
+  offlinePrefetchCategoriesDesc:
+    'Only download unread articles of these categories, separated by commas (empty = all)',
+  offlinePrefetchCategoriesPlaceholder: 'e.g. News, Tech',
+  offlinePrefetchMaxSize: 'Max Offline Storage',
+  offlinePrefetchMaxSizeDesc: 'Stop downloading when articles and images use this much disk space',
+  offlinePrefetchNow: 'Download Now',
+  offlinePrefetchNowDesc:
+    'Download articles for offline reading without waiting for the next refresh',
+  offlinePrefetchStarted: 'Downloading articles for offline reading',
+  offlinePrefetchFailed: 'Failed to download articles for offline reading',
+  offlineArticles: 'Articles available offline',
+  downloadNow: 'Download Now',
   articleContentCacheCleanup: 'Clean Article Content Cache',
   articleContentCacheCleanupDesc: 'Clear all cached article content',
   cleanupArticleContentCache: 'Clean Now',
@@ -575,6 +648,25 @@ const en: TranslationMessages = {
   pleaseWait: 'Please wait, this may take a few minutes',
   plugins: 'Plugins',
   podcastAudio: 'Podcast Audio',
+  addToUpNext: 'Add to Up Next',
+  removeFromUpNext: 'Remove from Up Next',
+  addedToUpNext: 'Added to Up Next',
+  removedFromUpNext: 'Removed from Up Next',
+  downloadEpisode: 'Save Offline',
+  downloadEpisodeDesc: 'Download the episode in the background for offline listening',
+  downloadingEpisode: 'Downloading',
+  episodeDownloaded: 'Available offline',
+  episodeDownloadFailed: 'Failed to download episode',
+  retryDownload: 'Retry Download',
+  deleteDownload: 'Delete Download',
+  chapters: 'Chapters',
+  transcript: 'Transcript',
+  explicit: 'Explicit',
+  episodeTrailer: 'Trailer',
+  episodeBonus: 'Bonus',
+  podcastDownloadMaxSize: 'Podcast Downloads Size',
+  podcastDownloadMaxSizeDesc:
+    'Maximum disk space used by downloaded episodes. Played episodes are removed first',
   preparing: 'Preparing',
   preparingDiscovery: 'Preparing discovery',
   pressKey: 'Press key...',
@@ -601,6 +693,21 @@ const en: TranslationMessages = {
   retryTimeout: 'Timeout',
   retryTimeoutDesc: 'Time to wait before marking refresh as failed',
   feedRefreshSettings: 'Feed Refresh Settings',
+  fetchSecurity: 'Proxy Security',
+  privateNetworks: 'Private Networks',
+  privateNetworksDesc:
+    'Whether the media and webpage proxies may reach localhost, LAN and cloud metadata addresses',
+  privateNetworksAuto: 'Auto (block in server mode)',
+  privateNetworksBlock: 'Block',
+  privateNetworksAllow: 'Allow',
+  allowedHosts: 'Allowed Hosts',
+  allowedHostsDesc:
+    'Only proxy these hosts if set. Comma-separated domains, IP addresses or CIDR ranges; listed ranges may be private',
+  deniedHosts: 'Denied Hosts',
+  deniedHostsDesc: 'Never proxy these hosts. Comma-separated domains, IP addresses or CIDR ranges',
+  hostListPlaceholder: 'example.com, 10.0.0.0/8',
+  maxProxySize: 'Maximum Response Size',
+  maxProxySizeDesc: 'Larger media and webpages are not proxied',
   publishedBefore: 'Published On/Before',
   readLater: 'Read Later',
   readingAndDisplay: 'Reading & Display',
@@ -616,6 +723,7 @@ const en: TranslationMessages = {
   refreshModeDesc: 'Choose how often to refresh all subscriptions',
   activeFeeds: 'Refreshing',
   queuedFeeds: 'Queued',
+  throttledUntil: 'Throttled until {time}',
   more: 'more...',
   releaseNotes: 'Release Notes',
   removeAction: 'Remove Action',
@@ -634,6 +742,11 @@ const en: TranslationMessages = {
   autoExpandContentDesc: 'Override global full-text fetch and auto-expand settings for this feed',
   enabled: 'Enabled',
   disabled: 'Disabled',
+  feedTranslation: 'Translation',
+  feedTranslationDesc: 'Override global translation settings for this feed',
+  feedTargetLanguagePlaceholder: 'Language code, e.g. en (empty = global)',
+  pregenerateOnFetch: 'Pre-generate on Refresh',
+  pregenerateOnFetchDesc: 'Translate titles and generate summaries for new articles during refresh',
   required: 'Required',
   requiredField: 'This field is required',
   resetToDefault: 'Reset to Default',
@@ -641,6 +754,48 @@ const en: TranslationMessages = {
   rssUrl: 'RSS URL',
   rssUrlDescription: 'Subscribe to RSS/Atom feeds',
   rssUrlPlaceholder: 'https://example.com/rss',
+  feedCandidatesFound: 'This page offers {count} feed(s), choose one to subscribe',
+  feedCandidateItems: '{count} items',
+  feedCandidateLastPost: 'last post {date}',
+  noPostDate: 'unknown',
+  feedRecommendations: 'Recommended Feeds',
+  feedRecommendationsDesc: 'Find the blogs most often linked from the articles of your feeds',
+  findRecommendations: 'Find recommendations',
+  feedHealth: 'Feed Health',
+  feedHealthDesc: 'Fetch success, latency and activity of your feeds over the last {days} days',
+  feedHealthProblems: 'Problems',
+  feedHealthAll: 'All',
+  feedHealthNoProblems: 'All feeds are healthy',
+  feedHealthHealthy: 'Healthy',
+  feedHealthUnknown: 'Not fetched yet',
+  feedHealthFailing: 'Failing',
+  feedHealthDead: 'Dead',
+  feedHealthStale: 'Stale',
+  feedHealthPaused: 'Paused',
+  feedHealthGone: 'Gone',
+  feedHealthBackoff: 'retrying after {time}',
+  feedHealthSuccessRate: '{rate}% success',
+  feedHealthMedianLatency: '{ms} ms median',
+  feedHealthDaysSinceNewItem: '{days} days since a new item',
+  feedHealthNoNewItem: 'no new item yet',
+  feedHealthFailures: '{count} failures in a row',
+  pauseSelected: 'Pause Selected',
+  resumeSelected: 'Resume Selected',
+  unsubscribeSelected: 'Unsubscribe Selected',
+  feedsPausedSuccess: 'Paused {count} feeds',
+  feedsResumedSuccess: 'Resumed {count} feeds',
+  feedChanges: 'Automatic Changes',
+  feedChangesDesc:
+    'Feeds that moved permanently, are gone or keep failing are handled automatically. Undo a change to keep the feed as it was.',
+  feedChangeRedirect: 'Moved permanently, URL updated from {from} to {to}',
+  feedChangeGone: 'Gone (410), no longer refreshed on schedule',
+  feedChangeBackoff: 'Failing repeatedly, refreshing less often',
+  undoFeedChange: 'Undo',
+  feedChangeUndone: 'Change undone',
+  collectingLinks: 'Collecting links from your articles',
+  noRecommendationsFound: 'No recommendations found, read more articles and try again',
+  recommendationReason: 'Linked {links} times from {feeds} of your feeds',
+  recommendationLinkedFrom: 'Linked from {feeds}',
   ruleActions: 'Actions',
   ruleAppliedSuccess: 'Rule applied to {count} articles',
   ruleCondition: 'Condition',
@@ -732,6 +887,10 @@ const en: TranslationMessages = {
   summaryProcessingTip: 'AI summary processing may take some time, please be patient',
   summaryProvider: 'Summary Provider',
   summaryProviderDesc: 'Choose how to generate article summaries',
+  highlightKeySentences: 'Highlights',
+  highlightKeySentencesDesc: 'Highlight the key sentences of the article (computed locally)',
+  keyphraseTag: 'Keyphrase',
+  noHighlightsFound: 'The article is too short to highlight key sentences',
   summaryTooShort: 'Article is too short to generate a meaningful summary',
   summaryTriggerMode: 'Trigger Mode',
   summaryTriggerModeAuto: 'Auto Trigger',
@@ -829,6 +988,11 @@ const en: TranslationMessages = {
   xpathType: 'XPath Type',
   yes: 'Yes',
   youtubeVideo: 'YouTube Video',
+  videoTranscriptsEnabled: 'Video Transcripts',
+  videoTranscriptsEnabledDesc:
+    'Add the captions of new YouTube videos to their content, so they can be searched and summarized',
+  videoViews: '{count} views',
+  videoTranscriptAvailable: 'Transcript below',
   zoomIn: 'Zoom In',
   zoomOut: 'Zoom Out',
   findInPagePlaceholder: 'Find in article...',
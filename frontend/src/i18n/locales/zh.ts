@@ -29,9 +29,14 @@ const zh: TranslationMessages = {
   aiApiKeyDesc: 'AI 服务的 API 密钥（可选）',
   aiApiKeyMissing: 'API 密钥缺失或无效。请在设置中配置。',
   aiApiKeyPlaceholder: '输入您的 API 密钥',
+  aiBudgetAlertThreshold: '预算提醒阈值',
+  aiBudgetAlertThresholdDesc: '当月花费达到预算的该百分比时发出提醒',
   aiChat: 'AI 聊天',
   aiChatEnabled: 'AI 聊天',
   aiChatEnabledDesc: '和 AI 聊天，回答有关文章的问题',
+  aiCostBudget: '每月费用预算',
+  aiCostBudgetDesc: '每月 AI 花费上限，按价格表计算（0 表示不设预算）',
+  aiCostThisMonth: '本月费用',
   clearAllChats: '清空对话记录',
   clearAllChatsDesc: '删除所有 AI 对话记录',
   clearAllChatsButton: '清空',
@@ -84,6 +89,9 @@ const zh: TranslationMessages = {
     '配置翻译和摘要功能使用的全局 AI 设置。这些设置在选择 AI 提供商时同时应用于翻译、摘要和聊天功能。',
   aiSettingsIncomplete: 'AI 设置不完整',
   aiSummary: 'AI 摘要',
+  aiPriceTable: '价格表',
+  aiPriceTableDesc: '按模型或功能设置每百万输入/输出 Token 的价格（JSON 格式）',
+  aiPriceTablePlaceholder: '{"models": {"gpt-4o-mini": {"prompt": 0.15, "completion": 0.6}}}',
   aiSummaryPrompt: '摘要提示词',
   aiSummaryPromptDesc: 'AI 摘要的自定义系统提示词',
   aiSummaryPromptPlaceholder:
@@ -98,6 +106,8 @@ const zh: TranslationMessages = {
   aiTranslationPromptPlaceholder:
     '你是一个翻译器。准确翻译给定的文本。只输出翻译的文本，不要输出其他内容。',
   aiUsage: 'AI 使用量',
+  aiUsageAutoReset: '每月重置',
+  aiUsageAutoResetDesc: '每月初自动将 Token 计数清零',
   aiUsageFallback: '由于 AI 限制，正在使用免费替代方案',
   aiUsageLimit: '使用上限',
   aiUsageLimitDesc: '允许的最大 Token 数（0 = 无限制）。达到上限后将回退到免费替代方案。',
@@ -177,6 +187,25 @@ const zh: TranslationMessages = {
   cleaning: '清理中...',
 
   cleanupMediaCache: '立即清理',
+  backupAndRestore: '备份与恢复',
+  backupAndRestoreDesc: '将订阅、文章、设置、自定义 CSS 和脚本导出为单个归档，或在其他设备上恢复',
+  exportBackup: '导出',
+  restoreBackup: '恢复',
+  exporting: '导出中...',
+  restoring: '恢复中...',
+  backupPassphrase: '备份密码',
+  backupPassphraseExportMessage: '备份中的 API 密钥和密码将使用此密码加密，恢复备份时需要输入该密码。',
+  backupPassphraseImportMessage: '请输入导出备份时使用的密码。',
+  backupPassphraseRequired: '必须输入密码',
+  restoreBackupConfirm: '恢复备份将替换当前所有订阅、文章和设置。是否继续？',
+  backupExported: '备份已导出',
+  backupRestored: '备份已恢复，正在重新加载...',
+  importFromReaders: '从其他阅读器导入',
+  importFromReadersDesc:
+    '从 Feedly、Inoreader、Google Takeout、Miniflux、FreshRSS、Pocket 或 Instapaper 的导出文件导入收藏和稍后阅读的文章',
+  importItems: '导入',
+  importingItems: '导入中...',
+  readerImportSuccess: '已导入 {created} 篇新文章，更新了 {updated} 篇已有文章',
   clear: '清除',
   clearedReadLater: '稍后阅读列表已清空',
   clearFilters: '清除过滤',
@@ -288,6 +317,20 @@ const zh: TranslationMessages = {
   editFeed: '编辑订阅',
   editRule: '编辑规则',
   editSubscription: '编辑订阅',
+  embeddings: '向量嵌入',
+  embeddingEnabled: '启用向量嵌入',
+  embeddingEnabledDesc: '计算文章向量，用于查找相关文章、语义搜索以及合并不同订阅中的同一新闻',
+  embeddingEndpoint: '嵌入接口地址',
+  embeddingEndpointDesc: '兼容 OpenAI 的 /embeddings 接口，或用于本地模型的 Ollama /api/embed 接口',
+  embeddingApiKey: '嵌入 API 密钥',
+  embeddingApiKeyDesc: '留空则使用 AI API 密钥',
+  embeddingModel: '嵌入模型',
+  embeddingModelDesc: '例如 text-embedding-3-small 或 nomic-embed-text',
+  embeddingDedupEnabled: '合并重复新闻',
+  embeddingDedupEnabledDesc: '多个订阅发布的同一新闻在文章列表中只显示一次，并保留其他来源',
+  embeddingDedupThreshold: '重复相似度',
+  embeddingDedupThresholdDesc: '两篇文章被视为同一新闻的相似度阈值（0.5-1），越高越严格',
+  storyAlternates: '+{count} 个来源',
   enableProxy: '启用代理',
   enableProxyDesc: '使用代理服务器获取订阅和文章',
   enableSummary: '启用自动摘要',
@@ -329,6 +372,7 @@ const zh: TranslationMessages = {
   feedType: '订阅源模式',
   isFreshRSSFeed: '是否为 FreshRSS 订阅源',
   isImageModeFeed: '是否为图片库模式订阅源',
+  articleLanguage: '文章语言',
   regex: '正则表达式',
   rssFeed: 'RSS 订阅',
   feedProxy: '订阅源代理',
@@ -501,6 +545,25 @@ const zh: TranslationMessages = {
   mediaCacheMaxAgeDesc: '删除超过此天数的缓存媒体',
   mediaCacheMaxSize: '最大缓存大小',
   mediaCacheMaxSizeDesc: '媒体缓存最大大小',
+  offlinePrefetchEnabled: '离线阅读',
+  offlinePrefetchEnabledDesc: '每次刷新后下载所选文章的全文和图片，无网络连接时也可阅读',
+  offlinePrefetchReadLater: '稍后阅读的文章',
+  offlinePrefetchReadLaterDesc: '下载保存为稍后阅读的文章',
+  offlinePrefetchFavorites: '收藏的文章',
+  offlinePrefetchFavoritesDesc: '下载收藏的文章',
+  offlinePrefetchUnread: '未读文章',
+  offlinePrefetchUnreadDesc: '下载最新的未读文章',
+  offlinePrefetchCategories: '未读文章分类',
+  offlinePrefetchCategoriesDesc: '仅下载这些分类中的未读文章，以逗号分隔（留空 = 全部）',
+  offlinePrefetchCategoriesPlaceholder: '例如：新闻, 科技',
+  offlinePrefetchMaxSize: '最大离线存储',
+  offlinePrefetchMaxSizeDesc: '文章和图片占用的磁盘空间达到此大小时停止下载',
+  offlinePrefetchNow: '立即下载',
+  offlinePrefetchNowDesc: '立即下载离线阅读文章，无需等待下次刷新',
+  offlinePrefetchStarted: '正在下载离线阅读文章',
+  offlinePrefetchFailed: '下载离线阅读文章失败',
+  offlineArticles: '可离线阅读的文章',
+  downloadNow: '立即下载',
   articleContentCacheCleanup: '清理文章内容缓存',
   articleContentCacheCleanupDesc: '清空所有缓存的文章正文内容',
   cleanupArticleContentCache: '立即清理',
@@ -564,6 +627,24 @@ const zh: TranslationMessages = {
   pleaseWait: '请稍候，这可能需要几分钟时间',
   plugins: '插件',
   podcastAudio: '播客音频',
+  addToUpNext: '添加到待播',
+  removeFromUpNext: '从待播中移除',
+  addedToUpNext: '已添加到待播',
+  removedFromUpNext: '已从待播中移除',
+  downloadEpisode: '离线保存',
+  downloadEpisodeDesc: '在后台下载节目以便离线收听',
+  downloadingEpisode: '下载中',
+  episodeDownloaded: '可离线收听',
+  episodeDownloadFailed: '下载节目失败',
+  retryDownload: '重试下载',
+  deleteDownload: '删除下载',
+  chapters: '章节',
+  transcript: '文字稿',
+  explicit: '露骨内容',
+  episodeTrailer: '预告',
+  episodeBonus: '特别节目',
+  podcastDownloadMaxSize: '播客下载空间',
+  podcastDownloadMaxSizeDesc: '已下载节目可使用的最大磁盘空间，已播放的节目会优先被删除',
   pleaseSelectFeeds: '请选择订阅源',
   preparing: '准备中',
   preparingDiscovery: '正在准备发现',
@@ -591,6 +672,19 @@ const zh: TranslationMessages = {
   retryTimeout: '超时时间',
   retryTimeoutDesc: '在宣告刷新失败前等待响应的时间',
   feedRefreshSettings: '订阅源刷新设置',
+  fetchSecurity: '代理安全',
+  privateNetworks: '私有网络',
+  privateNetworksDesc: '媒体和网页代理是否可以访问本机、局域网和云元数据地址',
+  privateNetworksAuto: '自动（服务器模式下禁止）',
+  privateNetworksBlock: '禁止',
+  privateNetworksAllow: '允许',
+  allowedHosts: '允许的主机',
+  allowedHostsDesc: '设置后仅代理这些主机。以逗号分隔的域名、IP 地址或 CIDR 网段；列出的网段可以是私有地址',
+  deniedHosts: '禁止的主机',
+  deniedHostsDesc: '从不代理这些主机。以逗号分隔的域名、IP 地址或 CIDR 网段',
+  hostListPlaceholder: 'example.com, 10.0.0.0/8',
+  maxProxySize: '最大响应大小',
+  maxProxySizeDesc: '超过此大小的媒体和网页不会被代理',
   publishedBefore: '发布于此日期及之前',
   readLater: '稍后阅读',
   readingAndDisplay: '阅读与显示',
@@ -609,6 +703,7 @@ const zh: TranslationMessages = {
   activeTasks: '执行中任务',
   queuedTasks: '排队任务',
   immediateTasks: '立即任务',
+  throttledUntil: '限流至 {time}',
   tasksRunning: '运行中...',
   tasksWaiting: '等待中...',
   more: '更多...',
@@ -629,6 +724,11 @@ const zh: TranslationMessages = {
   autoExpandContentDesc: '覆盖此订阅源的全局全文提取和自动展开设置',
   enabled: '启用',
   disabled: '禁用',
+  feedTranslation: '翻译',
+  feedTranslationDesc: '覆盖此订阅源的全局翻译设置',
+  feedTargetLanguagePlaceholder: '语言代码，例如 en（留空使用全局设置）',
+  pregenerateOnFetch: '刷新时预生成',
+  pregenerateOnFetchDesc: '刷新时为新文章翻译标题并生成摘要',
   required: '必填',
   requiredField: '此项为必填项',
   resetToDefault: '恢复默认',
@@ -636,6 +736,47 @@ const zh: TranslationMessages = {
   rssUrl: 'RSS 地址',
   rssUrlDescription: '订阅 RSS/Atom 订阅源',
   rssUrlPlaceholder: 'https://example.com/rss',
+  feedCandidatesFound: '此页面提供 {count} 个订阅源，请选择一个进行订阅',
+  feedCandidateItems: '{count} 篇文章',
+  feedCandidateLastPost: '最近更新 {date}',
+  noPostDate: '未知',
+  feedRecommendations: '推荐订阅源',
+  feedRecommendationsDesc: '查找你的订阅源文章中最常链接的博客',
+  findRecommendations: '查找推荐',
+  feedHealth: '订阅源健康',
+  feedHealthDesc: '最近 {days} 天订阅源的抓取成功率、延迟和活跃度',
+  feedHealthProblems: '有问题',
+  feedHealthAll: '全部',
+  feedHealthNoProblems: '所有订阅源都正常',
+  feedHealthHealthy: '正常',
+  feedHealthUnknown: '尚未抓取',
+  feedHealthFailing: '失败中',
+  feedHealthDead: '已失效',
+  feedHealthStale: '不再更新',
+  feedHealthPaused: '已暂停',
+  feedHealthGone: '已下线',
+  feedHealthBackoff: '将于 {time} 重试',
+  feedHealthSuccessRate: '成功率 {rate}%',
+  feedHealthMedianLatency: '中位延迟 {ms} 毫秒',
+  feedHealthDaysSinceNewItem: '{days} 天没有新文章',
+  feedHealthNoNewItem: '还没有新文章',
+  feedHealthFailures: '连续失败 {count} 次',
+  pauseSelected: '暂停所选',
+  resumeSelected: '恢复所选',
+  unsubscribeSelected: '取消订阅所选',
+  feedsPausedSuccess: '已暂停 {count} 个订阅源',
+  feedsResumedSuccess: '已恢复 {count} 个订阅源',
+  feedChanges: '自动更改',
+  feedChangesDesc: '永久迁移、已失效或持续失败的订阅源会被自动处理。撤销更改可保持订阅源原样。',
+  feedChangeRedirect: '已永久迁移，URL 已从 {from} 更新为 {to}',
+  feedChangeGone: '已下线 (410)，不再定时刷新',
+  feedChangeBackoff: '持续失败，已降低刷新频率',
+  undoFeedChange: '撤销',
+  feedChangeUndone: '更改已撤销',
+  collectingLinks: '正在从文章中收集链接',
+  noRecommendationsFound: '未找到推荐，请阅读更多文章后再试',
+  recommendationReason: '被你的 {feeds} 个订阅源链接了 {links} 次',
+  recommendationLinkedFrom: '链接来源：{feeds}',
   ruleActions: '操作',
   ruleAppliedSuccess: '规则已应用于 {count} 篇文章',
   ruleCondition: '条件',
@@ -726,6 +867,10 @@ const zh: TranslationMessages = {
   summaryProcessingTip: 'AI摘要处理可能需要一些时间，请耐心等待',
   summaryProvider: '摘要提供商',
   summaryProviderDesc: '选择如何生成文章摘要',
+  highlightKeySentences: '重点',
+  highlightKeySentencesDesc: '高亮文章中的关键句子（本地计算）',
+  keyphraseTag: '关键词',
+  noHighlightsFound: '文章太短，无法标出关键句子',
   summaryTooShort: '文章太短，无法生成有效摘要',
   summaryTriggerMode: '触发方式',
   summaryTriggerModeAuto: '自动触发',
@@ -824,6 +969,10 @@ const zh: TranslationMessages = {
   xpathType: 'XPath 类型',
   yes: '是',
   youtubeVideo: 'YouTube 视频',
+  videoTranscriptsEnabled: '视频字幕',
+  videoTranscriptsEnabledDesc: '将新 YouTube 视频的字幕添加到内容中，以便搜索和生成摘要',
+  videoViews: '{count} 次观看',
+  videoTranscriptAvailable: '字幕见下文',
   zoomIn: '放大',
   zoomOut: '缩小',
   findInPagePlaceholder: '在文章中查找...',
@@ -19,6 +19,89 @@ export interface Article {
   is_read_later: boolean;
   summary?: string; // Cached AI-generated summary
   freshrss_item_id?: string; // FreshRSS/Google Reader item ID
+  lang?: string; // Detected language (ISO 639-1 code)
+  alternate_count?: number; // Near-duplicate articles from other feeds clustered into this story
+}
+
+export interface ArticleTag {
+  tag: string;
+  score: number;
+  source: string; // e.g. 'keyphrase' for tags extracted by the local summarizer
+}
+
+export interface ArticleHighlight {
+  text: string;
+  start: number; // Code point offset in the article content
+  end: number;
+  score: number;
+}
+
+export interface PodcastEpisode {
+  article_id: number;
+  mime_type: string;
+  file_size: number; // Enclosure length in bytes (0 if unknown)
+  duration: number; // Seconds (0 if unknown)
+  episode: number;
+  season: number;
+  episode_type: string; // 'full', 'trailer' or 'bonus'
+  explicit: boolean;
+  image_url: string;
+  chapters_url: string;
+  transcript_url: string;
+  transcript_type: string;
+}
+
+export interface PlaybackPosition {
+  article_id: number;
+  position: number; // Seconds from the start
+  completed: boolean;
+  updated_at: string;
+}
+
+export interface PodcastDownload {
+  article_id: number;
+  url: string;
+  file_name?: string;
+  status: 'queued' | 'downloading' | 'completed' | 'failed';
+  total_size: number;
+  downloaded_size: number;
+  error?: string;
+  created_at: string;
+  completed_at?: string;
+}
+
+export interface PodcastChapter {
+  startTime: number;
+  title?: string;
+  img?: string;
+  url?: string;
+}
+
+export interface VideoThumbnail {
+  url: string;
+  width?: number;
+  height?: number;
+}
+
+export interface VideoInfo {
+  article_id: number;
+  video_id: string; // YouTube video ID, empty for other platforms
+  duration: number; // Seconds (0 if unknown)
+  views: number;
+  rating_count: number;
+  rating_average: number;
+  thumbnails: VideoThumbnail[] | null; // Sorted from smallest to largest
+  transcript_lang?: string; // Language of the captions, empty if none
+}
+
+// A feed offered by a page URL, returned when adding a subscription
+export interface FeedCandidate {
+  url: string;
+  title: string;
+  type: string; // 'rss', 'atom' or 'json'
+  source: 'link' | 'platform' | 'path';
+  item_count: number;
+  last_post_at?: string;
 }
 
 export interface Feed {
@@ -52,6 +135,12 @@ export interface Feed {
   xpath_item_uid?: string;
   article_view_mode?: string; // Article view mode override ('global', 'webpage', 'rendered')
   auto_expand_content?: string; // Auto expand content mode ('global', 'enabled', 'disabled')
+  // Per-feed translation and summary policy
+  translation_mode?: string; // Translation override ('global', 'enabled', 'disabled')
+  translation_target_language?: string; // Target language override (empty = global)
+  summary_provider?: string; // Summary provider override ('global', 'local', 'ai')
+  summary_length?: string; // Summary length override ('global', 'short', 'medium', 'long')
+  pregenerate_on_fetch?: boolean; // Translate and summarize new articles during refresh
   // Email/Newsletter support
   email_address?: string;
   email_imap_server?: string;
@@ -62,12 +151,77 @@ export interface Feed {
   // FreshRSS integration
   is_freshrss_source?: boolean; // Whether this feed is from FreshRSS sync
   freshrss_stream_id?: string; // FreshRSS stream ID (e.g., "feed/http://...")
+  // Feed health
+  is_paused?: boolean; // Whether scheduled refreshes skip this feed
+  is_gone?: boolean; // Answered 410 Gone, so no longer refreshed on schedule
+  consecutive_failures?: number;
+  backoff_until?: string; // Scheduled refreshes are skipped until then after repeated failures
   // Statistics
   latest_article_time?: string; // Latest article publish time
   articles_per_month?: number; // Average articles per month (calculated from last 90 days)
   last_update_status?: string; // Last update status ("success" or "failed")
 }
 
+export type FeedHealthStatus =
+  | 'healthy'
+  | 'unknown'
+  | 'failing'
+  | 'dead'
+  | 'stale'
+  | 'paused'
+  | 'gone';
+
+export interface FeedHealth {
+  feed_id: number;
+  title: string;
+  url: string;
+  category: string;
+  status: FeedHealthStatus;
+  is_paused: boolean;
+  is_gone: boolean;
+  backoff_until?: string;
+  attempts: number; // Fetch attempts in the health window
+  failures: number;
+  success_rate: number; // 0 to 1
+  median_latency_ms: number; // Median duration of the successful attempts
+  consecutive_failures: number;
+  failing_since?: string;
+  last_fetch?: string;
+  last_success?: string;
+  last_new_item?: string;
+  days_since_new_item?: number;
+  last_error_class?: string; // e.g. "timeout", "dns", "http_4xx", "parse"
+  last_error?: string;
+}
+
+export interface FeedFetch {
+  id: number;
+  feed_id: number;
+  fetched_at: string;
+  reason: string; // e.g. "scheduled_global", "manual_refresh"
+  duration_ms: number;
+  status_code?: number;
+  bytes: number;
+  new_items: number;
+  error_class?: string;
+  error?: string;
+  redirect_url?: string; // Target of the permanent redirects followed
+}
+
+export type FeedChangeKind = 'redirect' | 'gone' | 'backoff';
+
+// A change made to a feed automatically, which can be undone
+export interface FeedChange {
+  id: number;
+  feed_id: number;
+  feed_title: string;
+  kind: FeedChangeKind;
+  old_value: string; // Previous URL of a redirect
+  new_value: string; // New URL of a redirect
+  created_at: string;
+  undone_at?: string;
+}
+
 export interface UnreadCounts {
   total: number;
   feedCounts: Record<number, number>;
@@ -88,12 +242,16 @@ export interface PoolTaskInfo {
   feed_title: string;
   reason: number; // TaskReason enum value
   created_at: string;
+  host?: string;
+  throttled_until?: string; // Set while the host asked to slow down
 }
 
 export interface QueueTaskInfo {
   feed_id: number;
   feed_title: string;
   position: number;
+  host?: string;
+  throttled_until?: string; // Set while the host asked to slow down
 }
 
 export interface UpdateInfo {
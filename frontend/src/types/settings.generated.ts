@@ -6,13 +6,18 @@
 
 export interface SettingsData {
   ai_api_key: string;
+  ai_budget_alert_threshold: string;
   ai_chat_enabled: boolean;
+  ai_cost_budget: string;
   ai_custom_headers: string;
   ai_endpoint: string;
   ai_model: string;
+  ai_price_table: string;
   ai_summary_prompt: string;
   ai_translation_prompt: string;
+  ai_usage_auto_reset: boolean;
   ai_usage_limit: string;
+  ai_usage_period: string;
   ai_usage_tokens: string;
   auto_cleanup_enabled: boolean;
   auto_show_all_content: boolean;
@@ -24,6 +29,12 @@ export interface SettingsData {
   deepl_api_key: string;
   deepl_endpoint: string;
   default_view_mode: string;
+  embedding_api_key: string;
+  embedding_dedup_enabled: boolean;
+  embedding_dedup_threshold: string;
+  embedding_enabled: boolean;
+  embedding_endpoint: string;
+  embedding_model: string;
   freshrss_api_password: string;
   freshrss_auto_sync_interval: number;
   freshrss_enabled: boolean;
@@ -44,13 +55,24 @@ export interface SettingsData {
   media_cache_enabled: boolean;
   media_cache_max_age_days: number;
   media_cache_max_size_mb: number;
+  media_proxy_allowed_hosts: string;
+  media_proxy_denied_hosts: string;
   media_proxy_fallback: boolean;
+  media_proxy_max_size_mb: number;
+  media_proxy_private_networks: string;
   network_bandwidth_mbps: string;
   network_latency_ms: string;
   network_speed: string;
   obsidian_enabled: boolean;
   obsidian_vault: string;
   obsidian_vault_path: string;
+  offline_prefetch_categories: string;
+  offline_prefetch_enabled: boolean;
+  offline_prefetch_favorites: boolean;
+  offline_prefetch_max_size_mb: number;
+  offline_prefetch_read_later: boolean;
+  offline_prefetch_unread: boolean;
+  podcast_download_max_size_mb: number;
   proxy_enabled: boolean;
   proxy_host: string;
   proxy_password: string;
@@ -74,6 +96,8 @@ export interface SettingsData {
   translation_enabled: boolean;
   translation_provider: string;
   update_interval: number;
+  video_transcripts_enabled: boolean;
+  websub_callback_url: string;
   window_height: string;
   window_maximized: string;
   window_width: string;
@@ -0,0 +1,58 @@
+/**
+ * Highlight utilities for MrRSS
+ * Marks key sentences in article HTML using offsets computed by the backend
+ */
+
+import type { ArticleHighlight } from '@/types/models';
+
+/**
+ * Wrap highlighted ranges of article HTML in <mark> elements.
+ * Offsets count Unicode code points in the HTML, as returned by /api/articles/highlights.
+ * Only text between tags is wrapped, so the markup of the article stays valid.
+ * @param html Article content the highlights were computed from
+ * @param highlights Highlights sorted by start offset
+ * @returns HTML with key sentences marked
+ */
+export function applyHighlights(html: string, highlights: ArticleHighlight[]): string {
+  if (!html || highlights.length === 0) {
+    return html;
+  }
+
+  const chars = Array.from(html);
+  let result = '';
+  let pos = 0;
+
+  for (const highlight of highlights) {
+    const start = Math.max(highlight.start, pos);
+    const end = Math.min(highlight.end, chars.length);
+    if (start >= end) continue;
+
+    result += chars.slice(pos, start).join('');
+
+    let text = '';
+    let inTag = false;
+    for (let i = start; i < end; i++) {
+      const ch = chars[i];
+      if (!inTag && ch === '<') {
+        result += wrapText(text);
+        text = '';
+        inTag = true;
+      }
+      if (inTag) {
+        result += ch;
+        if (ch === '>') inTag = false;
+      } else {
+        text += ch;
+      }
+    }
+    result += wrapText(text);
+    pos = end;
+  }
+
+  return result + chars.slice(pos).join('');
+}
+
+function wrapText(text: string): string {
+  if (!text.trim()) return text;
+  return `<mark class="key-sentence">${text}</mark>`;
+}
@@ -10,9 +10,10 @@ let mediaCachePromise: Promise<boolean> | null = null;
  * Convert a media URL to use the proxy endpoint
  * @param url Original media URL
  * @param referer Optional referer URL for anti-hotlinking
+ * @param width Optional width in pixels to request a scaled-down thumbnail
  * @returns Proxied URL
  */
-export function getProxiedMediaUrl(url: string, referer?: string): string {
+export function getProxiedMediaUrl(url: string, referer?: string, width?: number): string {
   if (!url) return '';
 
   // Don't proxy data URLs or blob URLs
@@ -35,6 +36,9 @@ export function getProxiedMediaUrl(url: string, referer?: string): string {
   if (referer) {
     params.set('referer', referer);
   }
+  if (width) {
+    params.set('w', String(width));
+  }
 
   return `/api/media/proxy?${params.toString()}`;
 }
@@ -17,7 +17,10 @@ require (
 	github.com/mmcdole/gofeed v1.3.0
 	github.com/wailsapp/wails/v3 v3.0.0-alpha.48
 	golang.org/x/crypto v0.46.0
+	golang.org/x/image v0.25.0
 	golang.org/x/net v0.48.0
+	golang.org/x/sync v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
 	modernc.org/sqlite v1.42.2
 )
 
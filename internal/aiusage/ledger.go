@@ -0,0 +1,244 @@
+package aiusage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/metrics"
+)
+
+// Features recorded in the usage ledger.
+const (
+	FeatureTranslation = "translation"
+	FeatureSummary     = "summary"
+	FeatureChat        = "chat"
+	FeatureEmbedding   = "embedding"
+)
+
+// Budget alert levels reported by GetBudgetStatus.
+const (
+	BudgetLevelNone     = "none"     // No budget configured
+	BudgetLevelOK       = "ok"       // Spending below the alert threshold
+	BudgetLevelWarning  = "warning"  // Spending at or above the alert threshold
+	BudgetLevelExceeded = "exceeded" // Spending at or above the budget
+)
+
+// Usage holds the token counts of a single AI request.
+type Usage struct {
+	PromptTokens     int64
+	CompletionTokens int64
+	// Cached marks results served without an API request (cache hit or skipped),
+	// which consume no tokens and are not recorded.
+	Cached bool
+}
+
+// Total returns the total number of tokens.
+func (u Usage) Total() int64 {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// IsZero reports whether no token counts are available.
+func (u Usage) IsZero() bool {
+	return u.PromptTokens == 0 && u.CompletionTokens == 0
+}
+
+// LedgerStore persists individual AI requests for history and cost reporting.
+type LedgerStore interface {
+	RecordAIUsage(feature, provider, model string, promptTokens, completionTokens int64, cost float64, estimated bool) error
+	GetAIUsageCostSince(since time.Time) (float64, error)
+}
+
+// Price is the cost per one million prompt and completion tokens.
+type Price struct {
+	Prompt     float64 `json:"prompt"`
+	Completion float64 `json:"completion"`
+}
+
+// PriceTable maps models and features to token prices.
+// Feature prices take precedence over model prices; the "default" model entry
+// prices any model without its own entry.
+type PriceTable struct {
+	Models   map[string]Price `json:"models"`
+	Features map[string]Price `json:"features"`
+}
+
+// ParsePriceTable parses the JSON price table stored in ai_price_table.
+// An empty string yields an empty table (all requests cost nothing).
+func ParsePriceTable(raw string) (PriceTable, error) {
+	var table PriceTable
+	if strings.TrimSpace(raw) == "" {
+		return table, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &table); err != nil {
+		return PriceTable{}, fmt.Errorf("invalid AI price table: %w", err)
+	}
+	return table, nil
+}
+
+// Cost calculates the cost of a request for the given feature and model.
+func (p PriceTable) Cost(feature, model string, usage Usage) float64 {
+	price, ok := p.Features[feature]
+	if !ok {
+		price, ok = p.lookupModel(model)
+	}
+	if !ok {
+		return 0
+	}
+	return (float64(usage.PromptTokens)*price.Prompt + float64(usage.CompletionTokens)*price.Completion) / 1_000_000
+}
+
+// lookupModel finds the price of a model, matching names case-insensitively.
+func (p PriceTable) lookupModel(model string) (Price, bool) {
+	if price, ok := p.Models[model]; ok {
+		return price, true
+	}
+	for name, price := range p.Models {
+		if strings.EqualFold(name, model) {
+			return price, true
+		}
+	}
+	price, ok := p.Models["default"]
+	return price, ok
+}
+
+// BudgetStatus describes month-to-date AI spending against the configured budget.
+type BudgetStatus struct {
+	Budget    float64 `json:"budget"`    // Monthly budget (0 = no budget)
+	Spent     float64 `json:"spent"`     // Cost recorded since the start of the month
+	Percent   float64 `json:"percent"`   // Spent as a percentage of the budget
+	Threshold float64 `json:"threshold"` // Alert threshold in percent
+	Level     string  `json:"level"`     // "none", "ok", "warning" or "exceeded"
+}
+
+// TrackUsage records a request of an AI feature made with the configured AI model.
+// Token counts reported by the API are used when available; otherwise they are
+// estimated from the prompt and completion text. The request is added to the
+// running counter and, if a ledger is available, stored with its cost.
+func (t *Tracker) TrackUsage(feature string, usage Usage, promptText, completionText string) {
+	model, _ := t.settings.GetSetting("ai_model")
+	endpoint, _ := t.settings.GetSetting("ai_endpoint")
+	t.TrackModelUsage(feature, endpoint, model, usage, promptText, completionText)
+}
+
+// TrackModelUsage records a request like TrackUsage for features that use their own
+// endpoint and model (such as embeddings).
+func (t *Tracker) TrackModelUsage(feature, endpoint, model string, usage Usage, promptText, completionText string) {
+	if usage.Cached {
+		return
+	}
+
+	estimated := false
+	if usage.IsZero() {
+		usage.PromptTokens = EstimateTokens(promptText)
+		usage.CompletionTokens = EstimateTokens(completionText)
+		estimated = true
+	}
+
+	metrics.AddAITokens(feature, usage.PromptTokens, usage.CompletionTokens)
+
+	if err := t.AddUsage(usage.Total()); err != nil {
+		log.Printf("Warning: failed to track AI usage: %v", err)
+	}
+
+	if t.ledger == nil {
+		return
+	}
+
+	cost := t.GetPriceTable().Cost(feature, model, usage)
+
+	if err := t.ledger.RecordAIUsage(feature, providerFromEndpoint(endpoint), model, usage.PromptTokens, usage.CompletionTokens, cost, estimated); err != nil {
+		log.Printf("Warning: failed to record AI usage: %v", err)
+		return
+	}
+
+	if cost > 0 {
+		t.checkBudgetAlert()
+	}
+}
+
+// GetPriceTable returns the configured price table.
+// Invalid tables are logged and treated as empty.
+func (t *Tracker) GetPriceTable() PriceTable {
+	raw, _ := t.settings.GetSetting("ai_price_table")
+	table, err := ParsePriceTable(raw)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return table
+}
+
+// GetBudgetStatus returns month-to-date spending against the monthly cost budget.
+func (t *Tracker) GetBudgetStatus() BudgetStatus {
+	status := BudgetStatus{Level: BudgetLevelNone}
+
+	budgetStr, _ := t.settings.GetSetting("ai_cost_budget")
+	status.Budget, _ = strconv.ParseFloat(budgetStr, 64)
+	thresholdStr, _ := t.settings.GetSetting("ai_budget_alert_threshold")
+	status.Threshold, _ = strconv.ParseFloat(thresholdStr, 64)
+	if status.Threshold <= 0 || status.Threshold > 100 {
+		status.Threshold = 80
+	}
+
+	if t.ledger != nil {
+		spent, err := t.ledger.GetAIUsageCostSince(t.MonthStart())
+		if err != nil {
+			log.Printf("Warning: failed to get AI usage cost: %v", err)
+		}
+		status.Spent = spent
+	}
+
+	if status.Budget <= 0 {
+		return status
+	}
+
+	status.Percent = status.Spent / status.Budget * 100
+	switch {
+	case status.Percent >= 100:
+		status.Level = BudgetLevelExceeded
+	case status.Percent >= status.Threshold:
+		status.Level = BudgetLevelWarning
+	default:
+		status.Level = BudgetLevelOK
+	}
+	return status
+}
+
+// MonthStart returns the start of the current calendar month in local time.
+func (t *Tracker) MonthStart() time.Time {
+	now := t.now()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+}
+
+// checkBudgetAlert logs an alert when spending crosses the warning threshold or the budget.
+func (t *Tracker) checkBudgetAlert() {
+	status := t.GetBudgetStatus()
+
+	t.mu.Lock()
+	previous := t.alertLevel
+	t.alertLevel = status.Level
+	t.mu.Unlock()
+
+	if status.Level == previous {
+		return
+	}
+	switch status.Level {
+	case BudgetLevelWarning:
+		log.Printf("AI budget alert: %.2f of %.2f spent this month (%.0f%%)", status.Spent, status.Budget, status.Percent)
+	case BudgetLevelExceeded:
+		log.Printf("AI budget exceeded: %.2f of %.2f spent this month", status.Spent, status.Budget)
+	}
+}
+
+// providerFromEndpoint derives a provider name from the AI endpoint host.
+func providerFromEndpoint(endpoint string) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Hostname() == "" {
+		return "unknown"
+	}
+	return parsed.Hostname()
+}
@@ -18,16 +18,24 @@ type SettingsProvider interface {
 // Tracker tracks AI usage (tokens) and enforces rate limits.
 type Tracker struct {
 	settings    SettingsProvider
+	ledger      LedgerStore // Optional: per-request usage ledger (nil if unsupported)
 	mu          sync.RWMutex
 	lastRequest time.Time
 	minInterval time.Duration // Minimum interval between AI requests
+	alertLevel  string        // Last reported budget alert level
+	now         func() time.Time
 }
 
 // NewTracker creates a new AI usage tracker.
+// If the settings provider also implements LedgerStore, every tracked request
+// is recorded in the usage ledger.
 func NewTracker(settings SettingsProvider) *Tracker {
+	ledger, _ := settings.(LedgerStore)
 	return &Tracker{
 		settings:    settings,
+		ledger:      ledger,
 		minInterval: 500 * time.Millisecond, // Default: max 2 requests per second
+		now:         time.Now,
 	}
 }
 
@@ -70,6 +78,10 @@ func (t *Tracker) WaitForRateLimit() {
 
 // GetCurrentUsage returns the current token usage.
 func (t *Tracker) GetCurrentUsage() (int64, error) {
+	t.mu.Lock()
+	t.checkMonthlyReset()
+	t.mu.Unlock()
+
 	usageStr, err := t.settings.GetSetting("ai_usage_tokens")
 	if err != nil {
 		return 0, err
@@ -117,6 +129,8 @@ func (t *Tracker) AddUsage(tokens int64) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	t.checkMonthlyReset()
+
 	// Get current usage inside the lock to prevent race condition
 	usageStr, err := t.settings.GetSetting("ai_usage_tokens")
 	var current int64
@@ -135,6 +149,33 @@ func (t *Tracker) ResetUsage() error {
 	return t.settings.SetSetting("ai_usage_tokens", "0")
 }
 
+// checkMonthlyReset resets the usage counter when a new calendar month starts.
+// The month the counter belongs to is stored in ai_usage_period. Auto reset can be
+// turned off with ai_usage_auto_reset. Callers must hold t.mu.
+func (t *Tracker) checkMonthlyReset() {
+	if autoReset, _ := t.settings.GetSetting("ai_usage_auto_reset"); autoReset == "false" {
+		return
+	}
+
+	period := t.now().Format("2006-01")
+	stored, _ := t.settings.GetSetting("ai_usage_period")
+	if stored == period {
+		return
+	}
+
+	// The first run only records the period; later month changes reset the counter
+	if stored != "" {
+		if err := t.settings.SetSetting("ai_usage_tokens", "0"); err != nil {
+			log.Printf("Warning: failed to reset monthly AI usage: %v", err)
+			return
+		}
+		log.Printf("AI usage counter reset for new month %s", period)
+	}
+	if err := t.settings.SetSetting("ai_usage_period", period); err != nil {
+		log.Printf("Warning: failed to store AI usage period: %v", err)
+	}
+}
+
 // EstimateTokens estimates the number of tokens in a text.
 // Uses a simple heuristic: ~4 characters per token for English, ~1.5 characters per token for CJK.
 func EstimateTokens(text string) int64 {
@@ -249,26 +290,12 @@ func isCJK(r rune) bool {
 	return false
 }
 
-// TrackTranslation tracks token usage for a translation operation.
+// TrackTranslation tracks estimated token usage for a translation operation.
 func (t *Tracker) TrackTranslation(sourceText, translatedText string) {
-	// Estimate tokens for both input and output
-	inputTokens := EstimateTokens(sourceText)
-	outputTokens := EstimateTokens(translatedText)
-
-	totalTokens := inputTokens + outputTokens
-	if err := t.AddUsage(totalTokens); err != nil {
-		log.Printf("Warning: failed to track AI usage: %v", err)
-	}
+	t.TrackUsage(FeatureTranslation, Usage{}, sourceText, translatedText)
 }
 
-// TrackSummary tracks token usage for a summarization operation.
+// TrackSummary tracks estimated token usage for a summarization operation.
 func (t *Tracker) TrackSummary(content, summary string) {
-	// Estimate tokens for both input and output
-	inputTokens := EstimateTokens(content)
-	outputTokens := EstimateTokens(summary)
-
-	totalTokens := inputTokens + outputTokens
-	if err := t.AddUsage(totalTokens); err != nil {
-		log.Printf("Warning: failed to track AI usage: %v", err)
-	}
+	t.TrackUsage(FeatureSummary, Usage{}, content, summary)
 }
@@ -0,0 +1,152 @@
+package aiusage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type ledgerEntry struct {
+	feature, provider, model string
+	prompt, completion       int64
+	cost                     float64
+	estimated                bool
+}
+
+// fakeStore implements SettingsProvider and LedgerStore in memory.
+type fakeStore struct {
+	settings map[string]string
+	entries  []ledgerEntry
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{settings: map[string]string{}}
+}
+
+func (s *fakeStore) GetSetting(key string) (string, error) { return s.settings[key], nil }
+
+func (s *fakeStore) SetSetting(key, value string) error {
+	s.settings[key] = value
+	return nil
+}
+
+func (s *fakeStore) RecordAIUsage(feature, provider, model string, promptTokens, completionTokens int64, cost float64, estimated bool) error {
+	s.entries = append(s.entries, ledgerEntry{feature, provider, model, promptTokens, completionTokens, cost, estimated})
+	return nil
+}
+
+func (s *fakeStore) GetAIUsageCostSince(since time.Time) (float64, error) {
+	var total float64
+	for _, e := range s.entries {
+		total += e.cost
+	}
+	return total, nil
+}
+
+func TestMonthlyReset(t *testing.T) {
+	store := newFakeStore()
+	tracker := NewTracker(store)
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	if err := tracker.AddUsage(100); err != nil {
+		t.Fatalf("AddUsage error: %v", err)
+	}
+	if usage, _ := tracker.GetCurrentUsage(); usage != 100 {
+		t.Fatalf("expected usage 100, got %d", usage)
+	}
+
+	now = time.Date(2026, 2, 1, 0, 0, 1, 0, time.UTC)
+	if usage, _ := tracker.GetCurrentUsage(); usage != 0 {
+		t.Fatalf("expected usage to reset in new month, got %d", usage)
+	}
+	if store.settings["ai_usage_period"] != "2026-02" {
+		t.Fatalf("expected period 2026-02, got %q", store.settings["ai_usage_period"])
+	}
+
+	store.settings["ai_usage_auto_reset"] = "false"
+	tracker.AddUsage(50)
+	now = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if usage, _ := tracker.GetCurrentUsage(); usage != 50 {
+		t.Fatalf("expected usage to be kept with auto reset disabled, got %d", usage)
+	}
+}
+
+func TestTrackUsage(t *testing.T) {
+	store := newFakeStore()
+	store.settings["ai_model"] = "gpt-4o-mini"
+	store.settings["ai_endpoint"] = "https://api.openai.com/v1/chat/completions"
+	store.settings["ai_price_table"] = `{"models":{"GPT-4o-mini":{"prompt":0.15,"completion":0.6}},"features":{"chat":{"prompt":1,"completion":2}}}`
+	tracker := NewTracker(store)
+
+	tracker.TrackUsage(FeatureTranslation, Usage{PromptTokens: 1000000, CompletionTokens: 1000000}, "", "")
+	tracker.TrackUsage(FeatureChat, Usage{PromptTokens: 500000, CompletionTokens: 250000}, "", "")
+	tracker.TrackUsage(FeatureSummary, Usage{}, "some article content to summarize", "a summary")
+	tracker.TrackUsage(FeatureTranslation, Usage{Cached: true}, "cached", "cached")
+
+	if len(store.entries) != 3 {
+		t.Fatalf("expected 3 ledger entries, got %d", len(store.entries))
+	}
+
+	translation := store.entries[0]
+	if translation.provider != "api.openai.com" || translation.model != "gpt-4o-mini" || translation.estimated {
+		t.Errorf("unexpected translation entry: %+v", translation)
+	}
+	if math.Abs(translation.cost-0.75) > 1e-9 {
+		t.Errorf("expected model price cost 0.75, got %v", translation.cost)
+	}
+	if chat := store.entries[1]; math.Abs(chat.cost-1.0) > 1e-9 {
+		t.Errorf("expected feature price cost 1.0, got %v", chat.cost)
+	}
+	if summary := store.entries[2]; !summary.estimated || summary.prompt == 0 || summary.completion == 0 {
+		t.Errorf("expected estimated summary usage, got %+v", summary)
+	}
+
+	want := int64(2000000 + 750000 + store.entries[2].prompt + store.entries[2].completion)
+	if usage, _ := tracker.GetCurrentUsage(); usage != want {
+		t.Errorf("expected usage %d, got %d", want, usage)
+	}
+}
+
+func TestGetBudgetStatus(t *testing.T) {
+	store := newFakeStore()
+	tracker := NewTracker(store)
+
+	if status := tracker.GetBudgetStatus(); status.Level != BudgetLevelNone {
+		t.Fatalf("expected no budget, got %+v", status)
+	}
+
+	store.settings["ai_cost_budget"] = "10"
+	store.settings["ai_budget_alert_threshold"] = "80"
+	tests := []struct {
+		spent float64
+		level string
+	}{
+		{5, BudgetLevelOK},
+		{3, BudgetLevelWarning},
+		{2, BudgetLevelExceeded},
+	}
+	for _, tt := range tests {
+		store.entries = append(store.entries, ledgerEntry{cost: tt.spent})
+		if status := tracker.GetBudgetStatus(); status.Level != tt.level {
+			t.Errorf("after spending %v: expected level %s, got %+v", tt.spent, tt.level, status)
+		}
+	}
+}
+
+func TestParsePriceTable(t *testing.T) {
+	table, err := ParsePriceTable(`{"models":{"default":{"prompt":2,"completion":4}}}`)
+	if err != nil {
+		t.Fatalf("ParsePriceTable error: %v", err)
+	}
+	if cost := table.Cost(FeatureSummary, "unknown-model", Usage{PromptTokens: 1000000}); cost != 2 {
+		t.Errorf("expected default price cost 2, got %v", cost)
+	}
+
+	if _, err := ParsePriceTable("not json"); err == nil {
+		t.Error("expected error for invalid price table")
+	}
+	if table, err := ParsePriceTable(""); err != nil || table.Cost(FeatureChat, "any", Usage{PromptTokens: 100}) != 0 {
+		t.Errorf("expected empty table to price nothing, got %+v, %v", table, err)
+	}
+}
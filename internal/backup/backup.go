@@ -0,0 +1,276 @@
+// Package backup creates and restores portable archives of the MrRSS data.
+// An archive contains a snapshot of the database, the settings, the custom
+// article CSS and the scripts directory. Secrets are encrypted with a
+// user-supplied passphrase instead of the machine-specific key, so that an
+// archive can be restored on another machine.
+package backup
+
+import (
+	"archive/zip"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MrRSS/internal/crypto"
+	"MrRSS/internal/database"
+	"MrRSS/internal/version"
+)
+
+// FormatVersion is the version of the archive format
+const FormatVersion = 1
+
+// Names of the entries in an archive
+const (
+	manifestEntry = "manifest.json"
+	databaseEntry = "mrrss.db"
+	settingsEntry = "settings.json"
+	cssEntry      = "custom.css"
+	scriptsEntry  = "scripts/"
+)
+
+// passphraseCheck is encrypted into the manifest to verify the passphrase on import
+const passphraseCheck = "MrRSS backup"
+
+// maxEntrySize limits the size of files extracted from an archive
+const maxEntrySize = 4 << 30
+
+var (
+	// ErrInvalidArchive is returned when a file is not a MrRSS backup archive
+	ErrInvalidArchive = errors.New("invalid backup archive")
+	// ErrUnsupportedVersion is returned when an archive was created by a newer version
+	ErrUnsupportedVersion = errors.New("backup archive was created by a newer version of MrRSS")
+	// ErrWrongPassphrase is returned when the passphrase does not match the archive
+	ErrWrongPassphrase = errors.New("incorrect backup passphrase")
+)
+
+// Manifest describes a backup archive
+type Manifest struct {
+	FormatVersion   int       `json:"format_version"`
+	AppVersion      string    `json:"app_version"`
+	CreatedAt       time.Time `json:"created_at"`
+	PassphraseCheck string    `json:"passphrase_check"`
+	CustomCSSFile   string    `json:"custom_css_file,omitempty"`
+}
+
+// Export writes a backup archive of the database and the files in dataDir to w.
+// Secrets are re-encrypted under passphrase.
+func Export(db *database.DB, dataDir, passphrase string, w io.Writer) error {
+	if passphrase == "" {
+		return crypto.ErrEmptyPassphrase
+	}
+	db.WaitForReady()
+
+	tempDir, err := os.MkdirTemp("", "mrrss-backup-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Snapshot the database without blocking writers for the duration of the export
+	snapshotPath := filepath.Join(tempDir, databaseEntry)
+	if _, err := db.Exec(`VACUUM INTO ?`, snapshotPath); err != nil {
+		return fmt.Errorf("failed to snapshot database: %w", err)
+	}
+
+	settings, err := exportSecrets(snapshotPath, passphrase)
+	if err != nil {
+		return err
+	}
+
+	check, err := crypto.EncryptWithPassphrase(passphraseCheck, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt passphrase check: %w", err)
+	}
+	manifest := Manifest{
+		FormatVersion:   FormatVersion,
+		AppVersion:      version.Version,
+		CreatedAt:       time.Now().UTC(),
+		PassphraseCheck: check,
+		CustomCSSFile:   settings["custom_css_file"],
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeJSONEntry(zw, manifestEntry, manifest); err != nil {
+		return err
+	}
+	if err := writeJSONEntry(zw, settingsEntry, settings); err != nil {
+		return err
+	}
+	if err := writeFileEntry(zw, databaseEntry, snapshotPath); err != nil {
+		return err
+	}
+
+	if manifest.CustomCSSFile != "" {
+		cssPath := filepath.Join(dataDir, filepath.Base(manifest.CustomCSSFile))
+		if _, err := os.Stat(cssPath); err == nil {
+			if err := writeFileEntry(zw, cssEntry, cssPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	scriptsDir := filepath.Join(dataDir, "scripts")
+	err = filepath.Walk(scriptsDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(scriptsDir, path)
+		if err != nil {
+			return err
+		}
+		return writeFileEntry(zw, scriptsEntry+filepath.ToSlash(rel), path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add scripts: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// exportSecrets re-encrypts the secrets of a database snapshot under passphrase.
+// Settings are moved out of the snapshot and returned, so that they are restored
+// through the settings of the archive only.
+func exportSecrets(snapshotPath, passphrase string) (map[string]string, error) {
+	snapshot, err := sql.Open("sqlite", snapshotPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database snapshot: %w", err)
+	}
+	defer snapshot.Close()
+
+	rows, err := snapshot.Query(`SELECT key, value FROM settings`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+	settings := make(map[string]string)
+	for rows.Next() {
+		var key string
+		var value sql.NullString
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read settings: %w", err)
+		}
+		settings[key] = value.String
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	for key, value := range settings {
+		if !crypto.IsEncrypted(value) {
+			continue
+		}
+		plaintext, err := crypto.Decrypt(value)
+		if err != nil {
+			// The secret can no longer be read on this machine either
+			settings[key] = ""
+			continue
+		}
+		if settings[key], err = crypto.EncryptWithPassphrase(plaintext, passphrase); err != nil {
+			return nil, fmt.Errorf("failed to encrypt setting %s: %w", key, err)
+		}
+	}
+
+	// IMAP passwords are stored in the feeds table
+	if err := rewriteEmailPasswords(snapshot, func(password string) (string, error) {
+		return crypto.EncryptWithPassphrase(password, passphrase)
+	}); err != nil {
+		return nil, err
+	}
+
+	if _, err := snapshot.Exec(`DELETE FROM settings`); err != nil {
+		return nil, fmt.Errorf("failed to clear snapshot settings: %w", err)
+	}
+	if _, err := snapshot.Exec(`VACUUM`); err != nil {
+		return nil, fmt.Errorf("failed to compact database snapshot: %w", err)
+	}
+	return settings, nil
+}
+
+// rewriteEmailPasswords replaces every IMAP password of the feeds in db with transform(password).
+func rewriteEmailPasswords(db *sql.DB, transform func(string) (string, error)) error {
+	rows, err := db.Query(`SELECT id, email_password FROM feeds WHERE COALESCE(email_password, '') != ''`)
+	if err != nil {
+		return fmt.Errorf("failed to read email passwords: %w", err)
+	}
+	passwords := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var password string
+		if err := rows.Scan(&id, &password); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read email passwords: %w", err)
+		}
+		passwords[id] = password
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read email passwords: %w", err)
+	}
+
+	for id, password := range passwords {
+		rewritten, err := transform(password)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(`UPDATE feeds SET email_password = ? WHERE id = ?`, rewritten, id); err != nil {
+			return fmt.Errorf("failed to update email password: %w", err)
+		}
+	}
+	return nil
+}
+
+func writeJSONEntry(zw *zip.Writer, name string, v interface{}) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
+
+func writeFileEntry(zw *zip.Writer, name, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer src.Close()
+
+	dst, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	return nil
+}
+
+// entryName cleans the name of an archive entry and rejects names that escape
+// the directory they are extracted to.
+func entryName(name string) (string, bool) {
+	name = filepath.ToSlash(filepath.Clean(filepath.FromSlash(name)))
+	if name == "." || strings.HasPrefix(name, "../") || name == ".." || strings.HasPrefix(name, "/") || filepath.IsAbs(name) {
+		return "", false
+	}
+	return name, true
+}
@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func setupDB(t *testing.T) (*database.DB, string) {
+	t.Helper()
+	dataDir := t.TempDir()
+	db, err := database.NewDB(filepath.Join(dataDir, "rss.db"))
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, dataDir
+}
+
+func TestExportImport(t *testing.T) {
+	source, sourceDir := setupDB(t)
+
+	feedID, err := source.AddFeed(&models.Feed{Title: "Mail", URL: "email://me@example.com", EmailPassword: "imap-secret"})
+	if err != nil {
+		t.Fatalf("AddFeed() error = %v", err)
+	}
+	if err := source.SaveArticle(&models.Article{FeedID: feedID, Title: "Hello", URL: "https://example.com/1", PublishedAt: time.Now()}); err != nil {
+		t.Fatalf("SaveArticle() error = %v", err)
+	}
+	if err := source.SetEncryptedSetting("ai_api_key", "sk-secret"); err != nil {
+		t.Fatalf("SetEncryptedSetting() error = %v", err)
+	}
+	if err := source.SetSetting("custom_css_file", "custom_article.css"); err != nil {
+		t.Fatalf("SetSetting() error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "custom_article.css"), []byte("p { color: red; }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(sourceDir, "scripts", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sourceDir, "scripts", "sub", "feed.py"), []byte("print('rss')"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var archive bytes.Buffer
+	if err := Export(source, sourceDir, "passphrase", &archive); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if bytes.Contains(archive.Bytes(), []byte("sk-secret")) || bytes.Contains(archive.Bytes(), []byte("imap-secret")) {
+		t.Fatal("archive contains a plain text secret")
+	}
+
+	target, targetDir := setupDB(t)
+	reader := bytes.NewReader(archive.Bytes())
+
+	if _, err := Import(target, targetDir, "wrong", reader, reader.Size()); err != ErrWrongPassphrase {
+		t.Fatalf("expected ErrWrongPassphrase, got %v", err)
+	}
+
+	manifest, err := Import(target, targetDir, "passphrase", reader, reader.Size())
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		t.Errorf("unexpected manifest: %+v", manifest)
+	}
+
+	if key, err := target.GetEncryptedSetting("ai_api_key"); err != nil || key != "sk-secret" {
+		t.Errorf("restored ai_api_key = %q, %v", key, err)
+	}
+	feeds, err := target.GetFeeds()
+	if err != nil || len(feeds) != 1 || feeds[0].EmailPassword != "imap-secret" {
+		t.Fatalf("restored feeds = %+v, %v", feeds, err)
+	}
+	var count int
+	if err := target.QueryRow(`SELECT COUNT(*) FROM articles`).Scan(&count); err != nil || count != 1 {
+		t.Errorf("restored %d articles, %v", count, err)
+	}
+
+	if css, err := os.ReadFile(filepath.Join(targetDir, "custom_article.css")); err != nil || string(css) != "p { color: red; }" {
+		t.Errorf("restored css = %q, %v", css, err)
+	}
+	if script, err := os.ReadFile(filepath.Join(targetDir, "scripts", "sub", "feed.py")); err != nil || string(script) != "print('rss')" {
+		t.Errorf("restored script = %q, %v", script, err)
+	}
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	db, dataDir := setupDB(t)
+	reader := bytes.NewReader([]byte("not a zip file"))
+	if _, err := Import(db, dataDir, "passphrase", reader, reader.Size()); err != ErrInvalidArchive {
+		t.Fatalf("expected ErrInvalidArchive, got %v", err)
+	}
+}
+
+func TestEntryName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"scripts/feed.py", "scripts/feed.py", true},
+		{"scripts/../manifest.json", "manifest.json", true},
+		{"../outside", "", false},
+		{"/etc/passwd", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := entryName(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("entryName(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package backup
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"MrRSS/internal/crypto"
+	"MrRSS/internal/database"
+)
+
+// Import restores a backup archive into db and dataDir. The data of the archive
+// replaces the current data. Secrets are decrypted with passphrase and encrypted
+// again with the key of this machine.
+func Import(db *database.DB, dataDir, passphrase string, r io.ReaderAt, size int64) (*Manifest, error) {
+	db.WaitForReady()
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+	entries := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		if name, ok := entryName(f.Name); ok {
+			entries[name] = f
+		}
+	}
+
+	manifest := &Manifest{}
+	if err := readJSONEntry(entries[manifestEntry], manifest); err != nil {
+		return nil, err
+	}
+	if manifest.FormatVersion > FormatVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	if check, err := crypto.DecryptWithPassphrase(manifest.PassphraseCheck, passphrase); err != nil || check != passphraseCheck {
+		return nil, ErrWrongPassphrase
+	}
+
+	settings := make(map[string]string)
+	if err := readJSONEntry(entries[settingsEntry], &settings); err != nil {
+		return nil, err
+	}
+	if entries[databaseEntry] == nil {
+		return nil, ErrInvalidArchive
+	}
+
+	// Decrypt everything before changing any data, so a damaged archive leaves the current data intact
+	for key, value := range settings {
+		if !crypto.IsPassphraseEncrypted(value) {
+			continue
+		}
+		plaintext, err := crypto.DecryptWithPassphrase(value, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt setting %s: %w", key, err)
+		}
+		if settings[key], err = crypto.Encrypt(plaintext); err != nil {
+			return nil, fmt.Errorf("failed to encrypt setting %s: %w", key, err)
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "mrrss-restore-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	snapshotPath := filepath.Join(tempDir, databaseEntry)
+	if err := extractEntry(entries[databaseEntry], snapshotPath); err != nil {
+		return nil, err
+	}
+	if err := importSecrets(snapshotPath, passphrase); err != nil {
+		return nil, err
+	}
+
+	if err := restoreTables(db, snapshotPath); err != nil {
+		return nil, err
+	}
+	for key, value := range settings {
+		if err := db.SetSetting(key, value); err != nil {
+			return nil, fmt.Errorf("failed to restore setting %s: %w", key, err)
+		}
+	}
+
+	if manifest.CustomCSSFile != "" && entries[cssEntry] != nil {
+		cssPath := filepath.Join(dataDir, filepath.Base(manifest.CustomCSSFile))
+		if err := extractEntry(entries[cssEntry], cssPath); err != nil {
+			return nil, err
+		}
+	}
+
+	scriptsDir := filepath.Join(dataDir, "scripts")
+	for name, f := range entries {
+		if !strings.HasPrefix(name, scriptsEntry) || f.FileInfo().IsDir() {
+			continue
+		}
+		path := filepath.Join(scriptsDir, filepath.FromSlash(strings.TrimPrefix(name, scriptsEntry)))
+		if err := extractEntry(f, path); err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// importSecrets decrypts the IMAP passwords of a database snapshot with passphrase.
+func importSecrets(snapshotPath, passphrase string) error {
+	snapshot, err := sql.Open("sqlite", snapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database snapshot: %w", err)
+	}
+	defer snapshot.Close()
+
+	if err := snapshot.Ping(); err != nil {
+		return ErrInvalidArchive
+	}
+
+	return rewriteEmailPasswords(snapshot, func(password string) (string, error) {
+		if !crypto.IsPassphraseEncrypted(password) {
+			return password, nil
+		}
+		plaintext, err := crypto.DecryptWithPassphrase(password, passphrase)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt email password: %w", err)
+		}
+		return plaintext, nil
+	})
+}
+
+// restoreTables replaces the content of the tables of db with the tables of the
+// snapshot, in a single transaction. Only the columns known to both databases are
+// copied, so archives of older versions can be restored after migrations.
+func restoreTables(db *database.DB, snapshotPath string) error {
+	ctx := context.Background()
+
+	// ATTACH applies to a single connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `ATTACH DATABASE ? AS backup`, snapshotPath); err != nil {
+		return fmt.Errorf("failed to attach database snapshot: %w", err)
+	}
+	defer conn.ExecContext(ctx, `DETACH DATABASE backup`)
+
+	tables, err := queryStrings(ctx, conn, `SELECT name FROM backup.sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name != 'settings'`)
+	if err != nil {
+		return fmt.Errorf("failed to list snapshot tables: %w", err)
+	}
+
+	tableColumns := make(map[string][]string, len(tables))
+	for _, table := range tables {
+		columns, err := commonColumns(ctx, conn, table)
+		if err != nil {
+			return err
+		}
+		if len(columns) > 0 {
+			// Tables that no longer exist have no columns in common
+			tableColumns[table] = columns
+		}
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for table, columns := range tableColumns {
+		quoted := quoteIdentifier(table)
+		columnList := strings.Join(columns, ", ")
+		if _, err := tx.ExecContext(ctx, `DELETE FROM main.`+quoted); err != nil {
+			return fmt.Errorf("failed to clear table %s: %w", table, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO main.%s (%s) SELECT %s FROM backup.%s`, quoted, columnList, columnList, quoted)); err != nil {
+			return fmt.Errorf("failed to restore table %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+	return nil
+}
+
+// commonColumns returns the quoted names of the columns a table has in both databases.
+func commonColumns(ctx context.Context, conn *sql.Conn, table string) ([]string, error) {
+	mainColumns, err := queryStrings(ctx, conn, `SELECT name FROM pragma_table_info(?, 'main')`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	backupColumns, err := queryStrings(ctx, conn, `SELECT name FROM pragma_table_info(?, 'backup')`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+
+	inBackup := make(map[string]bool, len(backupColumns))
+	for _, column := range backupColumns {
+		inBackup[column] = true
+	}
+	var columns []string
+	for _, column := range mainColumns {
+		if inBackup[column] {
+			columns = append(columns, quoteIdentifier(column))
+		}
+	}
+	return columns, nil
+}
+
+func queryStrings(ctx context.Context, conn *sql.Conn, query string, args ...interface{}) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func readJSONEntry(f *zip.File, v interface{}) error {
+	if f == nil {
+		return ErrInvalidArchive
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return ErrInvalidArchive
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(io.LimitReader(rc, maxEntrySize)).Decode(v); err != nil {
+		return ErrInvalidArchive
+	}
+	return nil
+}
+
+func extractEntry(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	dst, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, io.LimitReader(rc, maxEntrySize)); err != nil {
+		return fmt.Errorf("failed to extract %s: %w", f.Name, err)
+	}
+	return nil
+}
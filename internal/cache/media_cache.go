@@ -4,23 +4,58 @@ package cache
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
-	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/netguard"
+)
+
+// DefaultMaxFileSize is the largest file cached, in bytes, unless SetClient sets another limit
+const DefaultMaxFileSize = 50 * 1024 * 1024
+
+const (
+	// touchInterval limits how often the last access time of a cached file is updated
+	touchInterval = 10 * time.Minute
+	// tempPrefix marks files that are still being written
+	tempPrefix = ".download-"
+	// staleTempAge is the age after which unfinished files are removed
+	staleTempAge = time.Hour
 )
 
-// MediaCache handles caching of images and videos to work around anti-hotlinking
+// downloads coalesces concurrent requests for the same file. It is shared by
+// all MediaCache instances, as handlers create one per request.
+var downloads singleflight.Group
+
+// MediaCache handles caching of images and videos to work around anti-hotlinking.
+// Files are stored in the cache directory and indexed in the database, which
+// tracks their size and last access for least recently used eviction.
 type MediaCache struct {
 	cacheDir string
+	db       *database.DB
+	client   *http.Client
+	maxSize  int64
+}
+
+// CachedFile is a file in the media cache
+type CachedFile struct {
+	URLHash     string // Identifies the URL in the cache index
+	Path        string
+	ContentType string
+	Size        int64
 }
 
 // NewMediaCache creates a new media cache instance
-func NewMediaCache(cacheDir string) (*MediaCache, error) {
+func NewMediaCache(cacheDir string, db *database.DB) (*MediaCache, error) {
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create cache directory: %w", err)
@@ -28,9 +63,20 @@ func NewMediaCache(cacheDir string) (*MediaCache, error) {
 
 	return &MediaCache{
 		cacheDir: cacheDir,
+		db:       db,
+		maxSize:  DefaultMaxFileSize,
 	}, nil
 }
 
+// SetClient sets the HTTP client used for downloads and the largest file
+// cached, in bytes. maxSize <= 0 keeps DefaultMaxFileSize.
+func (mc *MediaCache) SetClient(client *http.Client, maxSize int64) {
+	mc.client = client
+	if maxSize > 0 {
+		mc.maxSize = maxSize
+	}
+}
+
 // GetCachedPath returns the cached file path for a given URL (using extension from URL)
 func (mc *MediaCache) GetCachedPath(url string) string {
 	hash := hashURL(url)
@@ -66,55 +112,113 @@ func (mc *MediaCache) findCachedFile(url string) (string, bool) {
 
 // Exists checks if a media file is already cached (regardless of extension)
 func (mc *MediaCache) Exists(url string) bool {
+	if _, found := mc.lookup(hashURL(url), 0); found {
+		return true
+	}
 	_, found := mc.findCachedFile(url)
 	return found
 }
 
-// Get retrieves cached media or downloads it if not cached
+// Get retrieves cached media or downloads it if not cached.
+// Fetch should be preferred for large files, as Get reads the file into memory.
 func (mc *MediaCache) Get(url, referer string) ([]byte, string, error) {
-	// Check if already cached
-	cachedPath, found := mc.findCachedFile(url)
-	if found {
-		data, err := os.ReadFile(cachedPath)
-		if err != nil {
-			return nil, "", fmt.Errorf("failed to read cached file: %w", err)
+	file, err := mc.Fetch(url, referer)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read cached file: %w", err)
+	}
+	return data, file.ContentType, nil
+}
+
+// Fetch returns the cached file of a URL, downloading it if it is not cached.
+// Concurrent requests for the same URL share a single download.
+func (mc *MediaCache) Fetch(url, referer string) (*CachedFile, error) {
+	hash := hashURL(url)
+	if file, found := mc.lookup(hash, 0); found {
+		return file, nil
+	}
+
+	// Adopt files cached before they were indexed
+	if path, found := mc.findCachedFile(url); found {
+		return mc.adopt(hash, url, path)
+	}
+
+	result, err, _ := downloads.Do(mc.cacheDir+"|"+hash, func() (interface{}, error) {
+		// The download may have completed while waiting
+		if file, found := mc.lookup(hash, 0); found {
+			return file, nil
 		}
-		contentType := getContentTypeFromPath(cachedPath)
-		return data, contentType, nil
+		return mc.download(url, referer, hash)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download media: %w", err)
+	}
+	return result.(*CachedFile), nil
+}
+
+// lookup returns an indexed file and records the access
+func (mc *MediaCache) lookup(hash string, width int) (*CachedFile, bool) {
+	entry, err := mc.db.GetMediaCacheEntry(hash, width)
+	if err != nil || entry == nil {
+		return nil, false
 	}
 
-	// Download and cache
-	data, contentType, err := mc.download(url, referer)
+	path := filepath.Join(mc.cacheDir, entry.FileName)
+	info, err := os.Stat(path)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to download media: %w", err)
+		// The file was removed outside of the cache
+		mc.db.DeleteMediaCacheEntry(entry.FileName)
+		return nil, false
 	}
 
-	// Determine better file extension from Content-Type if available
-	if contentType != "" {
-		betterExt := getExtensionFromContentType(contentType)
-		if betterExt != "" {
-			// Update cached path with correct extension
-			cachedPath = filepath.Join(mc.cacheDir, hashURL(url)+betterExt)
+	if time.Since(entry.LastAccess) > touchInterval {
+		if err := mc.db.TouchMediaCacheEntry(hash, width); err != nil {
+			fmt.Printf("Failed to update media cache entry %s: %v\n", entry.FileName, err)
 		}
 	}
 
-	// Save to cache
-	if err := os.WriteFile(cachedPath, data, 0644); err != nil {
-		return nil, "", fmt.Errorf("failed to cache media: %w", err)
+	contentType := entry.ContentType
+	if contentType == "" {
+		contentType = getContentTypeFromPath(path)
 	}
+	return &CachedFile{URLHash: hash, Path: path, ContentType: contentType, Size: info.Size()}, true
+}
 
-	return data, contentType, nil
+// adopt adds an existing file of the cache directory to the index
+func (mc *MediaCache) adopt(hash, url, path string) (*CachedFile, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached file: %w", err)
+	}
+	file := &CachedFile{URLHash: hash, Path: path, ContentType: getContentTypeFromPath(path), Size: info.Size()}
+	err = mc.db.SaveMediaCacheEntry(database.MediaCacheEntry{
+		URLHash:     hash,
+		FileName:    filepath.Base(path),
+		URL:         url,
+		ContentType: file.ContentType,
+		Size:        file.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
 }
 
-// download fetches media from the given URL with proper headers
-func (mc *MediaCache) download(url, referer string) ([]byte, string, error) {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
+// download fetches media from the given URL with proper headers and streams it into the cache
+func (mc *MediaCache) download(url, referer, hash string) (*CachedFile, error) {
+	client := mc.client
+	if client == nil {
+		client = &http.Client{
+			Timeout: 30 * time.Second,
+		}
 	}
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers to bypass anti-hotlinking - try multiple user agents
@@ -141,91 +245,228 @@ func (mc *MediaCache) download(url, referer string) ([]byte, string, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to fetch media: %w", err)
+		return nil, fmt.Errorf("failed to fetch media: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	contentType := resp.Header.Get("Content-Type")
 	if contentType == "" {
 		contentType = getContentTypeFromPath(url)
 	}
+	// Only media is cached, so the proxy cannot be used to fetch pages or API responses
+	if !netguard.IsMediaType(contentType) {
+		return nil, fmt.Errorf("%w: %s", netguard.ErrContentType, contentType)
+	}
+	if resp.ContentLength > mc.maxSize {
+		return nil, netguard.ErrResponseTooLarge
+	}
 
-	return data, contentType, nil
-}
+	// Prefer the extension of the Content-Type over the one of the URL
+	ext := getExtensionFromContentType(contentType)
+	if ext == "" {
+		ext = getExtensionFromURL(url)
+	}
+	fileName := hash + ext
 
-// CleanupOldFiles removes cached files older than the specified age
-func (mc *MediaCache) CleanupOldFiles(maxAgeDays int) (int, error) {
-	var cutoffTime time.Time
-	count := 0
+	size, err := mc.writeFile(fileName, resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	err = mc.db.SaveMediaCacheEntry(database.MediaCacheEntry{
+		URLHash:     hash,
+		FileName:    fileName,
+		URL:         url,
+		ContentType: contentType,
+		Size:        size,
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if maxAgeDays <= 0 {
-		// Special case: remove all files regardless of age
-		cutoffTime = time.Now().Add(time.Hour) // Future time to match all files
-	} else {
-		cutoffTime = time.Now().AddDate(0, 0, -maxAgeDays)
+	return &CachedFile{URLHash: hash, Path: filepath.Join(mc.cacheDir, fileName), ContentType: contentType, Size: size}, nil
+}
+
+// writeFile streams r into a file of the cache directory. The file is written
+// under a temporary name and renamed when complete, so readers never see a
+// partial file. Files larger than the size limit are rejected.
+func (mc *MediaCache) writeFile(fileName string, r io.Reader) (int64, error) {
+	tmp, err := os.CreateTemp(mc.cacheDir, tempPrefix+"*")
+	if err != nil {
+		return 0, fmt.Errorf("failed to cache media: %w", err)
 	}
+	tmpPath := tmp.Name()
 
-	entries, err := os.ReadDir(mc.cacheDir)
+	size, err := io.Copy(tmp, io.LimitReader(r, mc.maxSize+1))
+	closeErr := tmp.Close()
+	if err == nil && size > mc.maxSize {
+		err = netguard.ErrResponseTooLarge
+	}
+	if err == nil && closeErr != nil {
+		err = closeErr
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+		os.Remove(tmpPath)
+		if errors.Is(err, netguard.ErrResponseTooLarge) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to cache media: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, filepath.Join(mc.cacheDir, fileName)); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("failed to cache media: %w", err)
 	}
+	return size, nil
+}
+
+// remove deletes a cached file and its index entry
+func (mc *MediaCache) remove(entry database.MediaCacheEntry) error {
+	if err := os.Remove(filepath.Join(mc.cacheDir, entry.FileName)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return mc.db.DeleteMediaCacheEntry(entry.FileName)
+}
 
+// syncIndex brings the index in line with the cache directory. Files that are
+// not indexed, such as those cached by earlier versions, are added with their
+// modification time as last access, and entries of missing files are removed.
+func (mc *MediaCache) syncIndex() error {
+	entries, err := mc.db.GetMediaCacheEntries()
+	if err != nil {
+		return err
+	}
+	indexed := make(map[string]bool, len(entries))
+	keys := make(map[string]bool, len(entries))
 	for _, entry := range entries {
-		if entry.IsDir() {
+		indexed[entry.FileName] = true
+		keys[entry.URLHash+"|"+strconv.Itoa(entry.Width)] = true
+	}
+
+	dirEntries, err := os.ReadDir(mc.cacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	present := make(map[string]bool, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() {
 			continue
 		}
-
-		filePath := filepath.Join(mc.cacheDir, entry.Name())
-		info, err := entry.Info()
+		info, err := dirEntry.Info()
 		if err != nil {
 			continue
 		}
+		if strings.HasPrefix(name, tempPrefix) {
+			// Left behind by an interrupted download
+			if time.Since(info.ModTime()) > staleTempAge {
+				os.Remove(filepath.Join(mc.cacheDir, name))
+			}
+			continue
+		}
+		present[name] = true
+		if indexed[name] {
+			continue
+		}
 
-		if info.ModTime().Before(cutoffTime) {
-			if err := os.Remove(filePath); err == nil {
-				count++
+		hash, width := parseCacheFileName(name)
+		key := hash + "|" + strconv.Itoa(width)
+		if keys[key] {
+			// Another file already caches this URL, with a different extension
+			os.Remove(filepath.Join(mc.cacheDir, name))
+			continue
+		}
+		keys[key] = true
+		err = mc.db.SaveMediaCacheEntry(database.MediaCacheEntry{
+			URLHash:     hash,
+			Width:       width,
+			FileName:    name,
+			ContentType: getContentTypeFromPath(name),
+			Size:        info.Size(),
+			LastAccess:  info.ModTime(),
+			CreatedAt:   info.ModTime(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		if !present[entry.FileName] {
+			if err := mc.db.DeleteMediaCacheEntry(entry.FileName); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
 
-	return count, nil
+// ensureIndex builds the index from the cache directory if it is empty
+func (mc *MediaCache) ensureIndex() error {
+	count, _, err := mc.db.GetMediaCacheStats()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return mc.syncIndex()
 }
 
-// GetCacheSize returns the total size of cached files in bytes
-func (mc *MediaCache) GetCacheSize() (int64, error) {
-	var totalSize int64
+// CleanupOldFiles removes cached files that have not been accessed within the
+// specified age. Files pinned for offline reading are kept, unless maxAgeDays
+// is 0 and all files are removed. It also reconciles the index with the cache
+// directory.
+func (mc *MediaCache) CleanupOldFiles(maxAgeDays int) (int, error) {
+	var cutoffTime time.Time
+	count := 0
+
+	if maxAgeDays <= 0 {
+		// Special case: remove all files regardless of age
+		cutoffTime = time.Now().Add(time.Hour) // Future time to match all files
+	} else {
+		cutoffTime = time.Now().AddDate(0, 0, -maxAgeDays)
+	}
 
-	entries, err := os.ReadDir(mc.cacheDir)
+	if err := mc.syncIndex(); err != nil {
+		return 0, err
+	}
+	entries, err := mc.db.GetMediaCacheEntries()
 	if err != nil {
-		return 0, fmt.Errorf("failed to read cache directory: %w", err)
+		return 0, err
 	}
 
+	// Entries are ordered by last access, oldest first
 	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+		if !entry.LastAccess.Before(cutoffTime) {
+			break
 		}
-
-		info, err := entry.Info()
-		if err != nil {
+		if entry.Pinned && maxAgeDays > 0 {
 			continue
 		}
-
-		totalSize += info.Size()
+		if err := mc.remove(entry); err == nil {
+			count++
+		}
 	}
 
-	return totalSize, nil
+	return count, nil
 }
 
-// CleanupBySize removes oldest files until cache is under the size limit
+// GetCacheSize returns the total size of cached files in bytes
+func (mc *MediaCache) GetCacheSize() (int64, error) {
+	if err := mc.ensureIndex(); err != nil {
+		return 0, err
+	}
+	_, size, err := mc.db.GetMediaCacheStats()
+	return size, err
+}
+
+// CleanupBySize removes least recently used files until cache is under the size limit.
+// Files pinned for offline reading are not removed.
 func (mc *MediaCache) CleanupBySize(maxSizeMB int) (int, error) {
 	maxSize := int64(maxSizeMB) * 1024 * 1024
 	currentSize, err := mc.GetCacheSize()
@@ -237,50 +478,23 @@ func (mc *MediaCache) CleanupBySize(maxSizeMB int) (int, error) {
 		return 0, nil
 	}
 
-	// Get all files with their modification times
-	type fileInfo struct {
-		path    string
-		modTime time.Time
-		size    int64
-	}
-
-	var files []fileInfo
-	entries, err := os.ReadDir(mc.cacheDir)
+	entries, err := mc.db.GetMediaCacheEntries()
 	if err != nil {
-		return 0, fmt.Errorf("failed to read cache directory: %w", err)
-	}
-
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		info, err := entry.Info()
-		if err != nil {
-			continue
-		}
-
-		files = append(files, fileInfo{
-			path:    filepath.Join(mc.cacheDir, entry.Name()),
-			modTime: info.ModTime(),
-			size:    info.Size(),
-		})
+		return 0, err
 	}
 
-	// Sort by modification time (oldest first) using built-in sort for better performance
-	sort.Slice(files, func(i, j int) bool {
-		return files[i].modTime.Before(files[j].modTime)
-	})
-
-	// Remove oldest files until under limit
+	// Remove least recently used files until under limit
 	count := 0
-	for _, f := range files {
+	for _, entry := range entries {
 		if currentSize <= maxSize {
 			break
 		}
+		if entry.Pinned {
+			continue
+		}
 
-		if err := os.Remove(f.path); err == nil {
-			currentSize -= f.size
+		if err := mc.remove(entry); err == nil {
+			currentSize -= entry.Size
 			count++
 		}
 	}
@@ -288,6 +502,17 @@ func (mc *MediaCache) CleanupBySize(maxSizeMB int) (int, error) {
 	return count, nil
 }
 
+// parseCacheFileName returns the URL hash and variant width of a cached file name
+func parseCacheFileName(name string) (string, int) {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if idx := strings.LastIndex(base, "_w"); idx != -1 {
+		if width, err := strconv.Atoi(base[idx+2:]); err == nil && width > 0 {
+			return base[:idx], width
+		}
+	}
+	return base, 0
+}
+
 // hashURL creates a SHA256 hash of the URL for use as filename
 func hashURL(url string) string {
 	h := sha256.New()
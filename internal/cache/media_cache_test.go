@@ -1,19 +1,45 @@
 package cache
 
 import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/netguard"
 )
 
-func TestMediaCache_BasicOperations(t *testing.T) {
-	dir := t.TempDir()
+func newTestMediaCache(t *testing.T) (*MediaCache, *database.DB) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
 
-	mc, err := NewMediaCache(dir)
+	mc, err := NewMediaCache(t.TempDir(), db)
 	if err != nil {
 		t.Fatalf("NewMediaCache failed: %v", err)
 	}
+	return mc, db
+}
+
+func TestMediaCache_BasicOperations(t *testing.T) {
+	mc, _ := newTestMediaCache(t)
+	dir := mc.cacheDir
 
 	url := "https://example.com/image.jpg?query=1"
 	path := mc.GetCachedPath(url)
@@ -70,3 +96,166 @@ func TestGetExtensionAndContentTypeHelpers(t *testing.T) {
 		t.Fatalf("unexpected ext: %s", ext)
 	}
 }
+
+func TestMediaCache_CoalescesDownloads(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("image data"))
+	}))
+	defer server.Close()
+
+	mc, _ := newTestMediaCache(t)
+	url := server.URL + "/a.png"
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			file, err := mc.Fetch(url, "")
+			if err == nil && file.Size != int64(len("image data")) {
+				err = errors.New("unexpected size")
+			}
+			errs <- err
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Fetch failed: %v", err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected 1 download, got %d", got)
+	}
+}
+
+func TestMediaCache_MaxFileSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		// Flush before writing so the response has no Content-Length
+		w.(http.Flusher).Flush()
+		w.Write(bytes.Repeat([]byte("x"), 2048))
+	}))
+	defer server.Close()
+
+	mc, _ := newTestMediaCache(t)
+	mc.SetClient(server.Client(), 1024)
+	if _, err := mc.Fetch(server.URL+"/big.jpg", ""); !errors.Is(err, netguard.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	entries, _ := os.ReadDir(mc.cacheDir)
+	if len(entries) != 0 {
+		t.Errorf("expected no files left in cache, got %d", len(entries))
+	}
+}
+
+func TestMediaCache_CleanupBySizeEvictsLeastRecentlyUsed(t *testing.T) {
+	mc, db := newTestMediaCache(t)
+
+	data := bytes.Repeat([]byte("x"), 600*1024)
+	for i, name := range []string{"old", "recent"} {
+		fileName := hashURL(name) + ".jpg"
+		if err := os.WriteFile(filepath.Join(mc.cacheDir, fileName), data, 0644); err != nil {
+			t.Fatalf("write cached file: %v", err)
+		}
+		err := db.SaveMediaCacheEntry(database.MediaCacheEntry{
+			URLHash:    hashURL(name),
+			FileName:   fileName,
+			Size:       int64(len(data)),
+			LastAccess: time.Now().Add(time.Duration(i-2) * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("SaveMediaCacheEntry failed: %v", err)
+		}
+	}
+
+	size, err := mc.GetCacheSize()
+	if err != nil || size != int64(2*len(data)) {
+		t.Fatalf("GetCacheSize() = %d, %v", size, err)
+	}
+
+	removed, err := mc.CleanupBySize(1)
+	if err != nil {
+		t.Fatalf("CleanupBySize failed: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 file removed, got %d", removed)
+	}
+	if _, err := os.Stat(filepath.Join(mc.cacheDir, hashURL("old")+".jpg")); !os.IsNotExist(err) {
+		t.Error("expected least recently used file to be removed")
+	}
+	if _, err := os.Stat(filepath.Join(mc.cacheDir, hashURL("recent")+".jpg")); err != nil {
+		t.Error("expected recently used file to be kept")
+	}
+}
+
+func TestMediaCache_FetchVariant(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for x := 0; x < 400; x++ {
+		for y := 0; y < 200; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode image: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(buf.Bytes())
+	}))
+	defer server.Close()
+
+	mc, _ := newTestMediaCache(t)
+	url := server.URL + "/photo.png"
+
+	file, err := mc.FetchVariant(url, "", 150)
+	if err != nil {
+		t.Fatalf("FetchVariant failed: %v", err)
+	}
+	if file.ContentType != "image/jpeg" {
+		t.Errorf("expected opaque variant to be JPEG, got %s", file.ContentType)
+	}
+	f, err := os.Open(file.Path)
+	if err != nil {
+		t.Fatalf("open variant: %v", err)
+	}
+	defer f.Close()
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatalf("decode variant: %v", err)
+	}
+	if config.Width != 160 || config.Height != 80 {
+		t.Errorf("expected 160x80 variant, got %dx%d", config.Width, config.Height)
+	}
+
+	// Variants wider than the image are served from the original
+	file, err = mc.FetchVariant(url, "", 800)
+	if err != nil {
+		t.Fatalf("FetchVariant failed: %v", err)
+	}
+	if file.ContentType != "image/png" || file.Size != int64(buf.Len()) {
+		t.Errorf("expected original image, got %s of %d bytes", file.ContentType, file.Size)
+	}
+}
+
+func TestVariantWidth(t *testing.T) {
+	tests := map[int]int{1: 32, 100: 128, 160: 160, 161: 192, 10000: 2048}
+	for requested, want := range tests {
+		if got := VariantWidth(requested); got != want {
+			t.Errorf("VariantWidth(%d) = %d, want %d", requested, got, want)
+		}
+	}
+}
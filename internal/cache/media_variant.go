@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // Register the GIF decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Register the WebP decoder
+
+	"MrRSS/internal/database"
+)
+
+// Requested thumbnail widths are rounded up to a multiple of variantWidthStep
+// and limited to this range, so that only a few variants are cached per image.
+const (
+	MinVariantWidth  = 32
+	MaxVariantWidth  = 2048
+	variantWidthStep = 32
+)
+
+// maxVariantPixels is the largest image that is decoded for scaling, which
+// protects against images that decompress to huge bitmaps.
+const maxVariantPixels = 50_000_000
+
+// variantJPEGQuality is the quality of scaled images without transparency
+const variantJPEGQuality = 85
+
+// VariantWidth returns the width of the thumbnail variant served for a requested width
+func VariantWidth(width int) int {
+	if width < MinVariantWidth {
+		width = MinVariantWidth
+	}
+	if width > MaxVariantWidth {
+		width = MaxVariantWidth
+	}
+	return (width + variantWidthStep - 1) / variantWidthStep * variantWidthStep
+}
+
+// FetchVariant returns the image of a URL scaled down to the given width,
+// creating and caching the variant on first use. The original file is returned
+// if it is not a JPEG, PNG, GIF or WebP image, or not wider than the variant.
+func (mc *MediaCache) FetchVariant(url, referer string, width int) (*CachedFile, error) {
+	width = VariantWidth(width)
+	hash := hashURL(url)
+	if file, found := mc.lookup(hash, width); found {
+		return file, nil
+	}
+
+	original, err := mc.Fetch(url, referer)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err, _ := downloads.Do(fmt.Sprintf("%s|%s_w%d", mc.cacheDir, hash, width), func() (interface{}, error) {
+		if file, found := mc.lookup(hash, width); found {
+			return file, nil
+		}
+		return mc.createVariant(original, url, hash, width)
+	})
+	if err != nil {
+		// The original can still be displayed
+		fmt.Printf("Failed to scale media %s: %v\n", url, err)
+		return original, nil
+	}
+	return result.(*CachedFile), nil
+}
+
+// createVariant scales the original file down to width and caches the result
+func (mc *MediaCache) createVariant(original *CachedFile, url, hash string, width int) (*CachedFile, error) {
+	f, err := os.Open(original.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached file: %w", err)
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		// Not a raster image that can be scaled, such as SVG or video
+		return original, nil
+	}
+	if config.Width <= width || config.Width*config.Height > maxVariantPixels {
+		return original, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	height := config.Height * width / config.Width
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	// Keep transparency in PNG, everything else is smaller as JPEG
+	var buf bytes.Buffer
+	ext, contentType := ".jpg", "image/jpeg"
+	if dst.Opaque() {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: variantJPEGQuality})
+	} else {
+		ext, contentType = ".png", "image/png"
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s_w%d%s", hash, width, ext)
+	size, err := mc.writeFile(fileName, &buf)
+	if err != nil {
+		return nil, err
+	}
+	err = mc.db.SaveMediaCacheEntry(database.MediaCacheEntry{
+		URLHash:     hash,
+		Width:       width,
+		FileName:    fileName,
+		URL:         url,
+		ContentType: contentType,
+		Size:        size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachedFile{URLHash: hash, Path: filepath.Join(mc.cacheDir, fileName), ContentType: contentType, Size: size}, nil
+}
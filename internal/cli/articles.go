@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/tabwriter"
+
+	"MrRSS/internal/models"
+)
+
+func runArticles(e *env, args []string) error {
+	sub, args, err := e.subcommand("articles", args, "search", "mark-read")
+	if err != nil {
+		return err
+	}
+	if sub == "search" {
+		return articlesSearch(e, args)
+	}
+	return articlesMarkRead(e, args)
+}
+
+// articlesSearch prints the articles whose title or summary contain the search text
+func articlesSearch(e *env, args []string) error {
+	fs := e.flags("articles search", "articles search [flags] TEXT")
+	limit := fs.Int("limit", 50, "Maximum number of articles to print")
+	unread := fs.Bool("unread", false, "Only search unread articles")
+	asJSON := fs.Bool("json", false, "Print the articles as JSON")
+	if err := e.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if e.remote() != nil {
+		return errLocalOnly
+	}
+
+	db, err := e.openDB()
+	if err != nil {
+		return err
+	}
+	filter := "all"
+	if *unread {
+		filter = "unread"
+	}
+	articles, err := db.SearchArticles(fs.Arg(0), filter, *limit, 0)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		if articles == nil {
+			articles = []models.Article{}
+		}
+		return enc.Encode(articles)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPUBLISHED\tREAD\tFEED\tTITLE")
+	for _, a := range articles {
+		published := ""
+		if !a.PublishedAt.IsZero() {
+			published = a.PublishedAt.Local().Format("2006-01-02 15:04")
+		}
+		read := ""
+		if a.IsRead {
+			read = "yes"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", a.ID, published, read, a.FeedTitle, a.Title)
+	}
+	return tw.Flush()
+}
+
+// articlesMarkRead marks articles as read: the given articles, or all articles
+// of a feed, of a category or of all feeds
+func articlesMarkRead(e *env, args []string) error {
+	fs := e.flags("articles mark-read", "articles mark-read [flags] ID... | -feed ID | -category NAME | -all")
+	feedID := fs.Int64("feed", 0, "Mark all articles of this feed as read")
+	category := fs.String("category", "", "Mark all articles of this category as read")
+	all := fs.Bool("all", false, "Mark all articles as read")
+	unread := fs.Bool("unread", false, "Mark the given articles as unread instead")
+	if err := e.parse(fs, args, 0, -1); err != nil {
+		return err
+	}
+
+	bulk := 0
+	for _, set := range []bool{*feedID > 0, *category != "", *all} {
+		if set {
+			bulk++
+		}
+	}
+	if bulk > 1 || (bulk == 1) == (fs.NArg() > 0) || (bulk == 1 && *unread) {
+		fs.Usage()
+		return errUsage
+	}
+
+	if bulk == 1 {
+		return markAllRead(e, *feedID, *category)
+	}
+
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+	c := e.remote()
+	if c == nil {
+		if _, err := e.openDB(); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		if c != nil {
+			query := url.Values{"id": {strconv.FormatInt(id, 10)}, "read": {strconv.FormatBool(!*unread)}}
+			err = c.call(http.MethodPost, "/articles/read", query, nil, nil)
+		} else {
+			err = e.db.MarkArticleRead(id, !*unread)
+		}
+		if err != nil {
+			return fmt.Errorf("article %d: %w", id, err)
+		}
+	}
+	state := "read"
+	if *unread {
+		state = "unread"
+	}
+	fmt.Fprintf(e.stdout, "Marked %d articles as %s\n", len(ids), state)
+	return nil
+}
+
+// markAllRead marks all articles of a feed, of a category or of all feeds as read
+func markAllRead(e *env, feedID int64, category string) error {
+	if c := e.remote(); c != nil {
+		query := url.Values{}
+		if feedID > 0 {
+			query.Set("feed_id", strconv.FormatInt(feedID, 10))
+		} else if category != "" {
+			query.Set("category", category)
+		}
+		if err := c.call(http.MethodPost, "/articles/mark-all-read", query, nil, nil); err != nil {
+			return err
+		}
+	} else {
+		db, err := e.openDB()
+		if err != nil {
+			return err
+		}
+		switch {
+		case feedID > 0:
+			err = db.MarkAllAsReadForFeed(feedID)
+		case category != "":
+			err = db.MarkAllAsReadForCategory(category)
+		default:
+			err = db.MarkAllAsRead()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(e.stdout, "Marked articles as read")
+	return nil
+}
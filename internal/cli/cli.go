@@ -0,0 +1,201 @@
+// Package cli implements the command-line interface of the server binary.
+//
+// Commands work on the database directly, so they can run from cron while the server
+// is stopped, or on a running instance through its API when -server is given.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/translation"
+	"MrRSS/internal/utils"
+)
+
+// command is a top-level CLI command
+type command struct {
+	name    string
+	summary string
+	run     func(e *env, args []string) error
+}
+
+var commands = []command{
+	{"feeds", "Manage subscriptions: add, list, rm, refresh", runFeeds},
+	{"opml", "Import or export subscriptions as OPML: import, export", runOPML},
+	{"articles", "Search articles and mark them as read: search, mark-read", runArticles},
+	{"rules", "Apply the automation rules: apply", runRules},
+	{"settings", "Read and change settings: get, set", runSettings},
+	{"backup", "Write an encrypted backup archive", runBackup},
+	{"vacuum", "Remove orphaned data and compact the database", runVacuum},
+	{"provision", "Make the database match a configuration file", runProvision},
+}
+
+// errUsage reports invalid arguments; the usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+// errLocalOnly is returned by commands that have no API equivalent
+var errLocalOnly = errors.New("not available with -server, run it on the server's database")
+
+// IsCommand reports whether name is a CLI command rather than a server flag
+func IsCommand(name string) bool {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return true
+		}
+	}
+	return name == "help"
+}
+
+// Run runs the command given by args, without the program name, and returns the exit code
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+		defer e.close()
+
+		err := cmd.run(e, args[1:])
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+			return 2
+		default:
+			fmt.Fprintf(stderr, "mrrss-server %s: %v\n", cmd.name, err)
+			return 1
+		}
+	}
+
+	fmt.Fprintf(stderr, "mrrss-server: unknown command %q\n\n", args[0])
+	printUsage(stderr)
+	return 2
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: mrrss-server <command> [subcommand] [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nEvery command accepts -db PATH to use another database, and all but")
+	fmt.Fprintln(w, "articles search, vacuum and provision accept -server URL to work on a running instance.")
+	fmt.Fprintln(w, "Run 'mrrss-server <command> <subcommand> -h' for the flags of a command.")
+}
+
+// env holds the state shared by the commands of one run
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	dbPath string
+	server string
+
+	db      *database.DB
+	fetcher *feed.Fetcher
+}
+
+// flags creates the flag set of a command, with the -db and -server flags
+func (e *env) flags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.dbPath, "db", "", "Path of the database (default: the data directory's database)")
+	fs.StringVar(&e.server, "server", "", "URL of a running MrRSS server to work on, e.g. http://localhost:1234")
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: mrrss-server %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of a command and checks the number of arguments left
+func (e *env) parse(fs *flag.FlagSet, args []string, minArgs, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < minArgs || (maxArgs >= 0 && fs.NArg() > maxArgs) {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// remote returns the API client when the command works on a running server
+func (e *env) remote() *client {
+	if e.server == "" {
+		return nil
+	}
+	return newClient(e.server)
+}
+
+// openDB opens the database, initializing the schema like the server does
+func (e *env) openDB() (*database.DB, error) {
+	if e.db != nil {
+		return e.db, nil
+	}
+
+	if e.dbPath == "" {
+		path, err := utils.GetDBPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database path: %w", err)
+		}
+		e.dbPath = path
+	}
+
+	db, err := database.NewDB(e.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.Init(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	e.db = db
+	return db, nil
+}
+
+// openFetcher returns a feed fetcher working on the database
+func (e *env) openFetcher() (*feed.Fetcher, error) {
+	if e.fetcher != nil {
+		return e.fetcher, nil
+	}
+	db, err := e.openDB()
+	if err != nil {
+		return nil, err
+	}
+	e.fetcher = feed.NewFetcher(db, translation.NewDynamicTranslatorWithCache(db, db))
+	return e.fetcher, nil
+}
+
+func (e *env) close() {
+	if e.db != nil {
+		e.db.Close()
+	}
+}
+
+// subcommand splits the subcommand from its arguments, printing the usage when it is unknown
+func (e *env) subcommand(name string, args []string, subcommands ...string) (string, []string, error) {
+	if len(args) > 0 {
+		for _, sub := range subcommands {
+			if args[0] == sub {
+				return sub, args[1:], nil
+			}
+		}
+		if args[0] != "-h" && args[0] != "--help" && args[0] != "help" {
+			fmt.Fprintf(e.stderr, "mrrss-server %s: unknown subcommand %q\n", name, args[0])
+		}
+	}
+	fmt.Fprintf(e.stderr, "Usage: mrrss-server %s <%s> [flags] [arguments]\n", name, strings.Join(subcommands, "|"))
+	return "", nil, errUsage
+}
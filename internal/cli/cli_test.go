@@ -0,0 +1,291 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/handlers/routes"
+	"MrRSS/internal/models"
+)
+
+const testRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>CLI Feed</title><link>https://cli.example/</link>
+<item><title>Scripting subscriptions</title><link>https://cli.example/1</link><description>From cron</description></item>
+<item><title>Another post</title><link>https://cli.example/2</link></item>
+</channel></rss>`
+
+// run runs a CLI command and returns its exit code and output
+func run(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := Run(args, strings.NewReader(""), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(testRSS))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestUsage(t *testing.T) {
+	if code, out, _ := run(t); code != 0 || !strings.Contains(out, "feeds") {
+		t.Errorf("help: code %d, output %q", code, out)
+	}
+	if code, _, errOut := run(t, "nope"); code != 2 || !strings.Contains(errOut, "unknown command") {
+		t.Errorf("unknown command: code %d, stderr %q", code, errOut)
+	}
+	if code, _, errOut := run(t, "feeds", "frobnicate"); code != 2 || !strings.Contains(errOut, "unknown subcommand") {
+		t.Errorf("unknown subcommand: code %d, stderr %q", code, errOut)
+	}
+	if code, _, _ := run(t, "settings", "set", "theme"); code != 2 {
+		t.Errorf("missing argument: code %d, want 2", code)
+	}
+	if !IsCommand("feeds") || IsCommand("-port") {
+		t.Error("IsCommand does not tell commands from server flags")
+	}
+}
+
+func TestFeedsAndArticles(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mrrss.db")
+	srv := newFeedServer(t)
+
+	code, out, errOut := run(t, "feeds", "add", "-db", dbPath, "-category", "Tech", srv.URL+"/feed.xml")
+	if code != 0 || !strings.Contains(out, "CLI Feed") {
+		t.Fatalf("feeds add: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+
+	code, out, _ = run(t, "feeds", "list", "-db", dbPath)
+	if code != 0 || !strings.Contains(out, "CLI Feed") || !strings.Contains(out, "Tech") {
+		t.Fatalf("feeds list: code %d, output %q", code, out)
+	}
+
+	code, out, errOut = run(t, "feeds", "refresh", "-db", dbPath)
+	if code != 0 || !strings.Contains(out, "Refreshed 1 feeds, 0 failed") {
+		t.Fatalf("feeds refresh: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+
+	code, out, _ = run(t, "articles", "search", "-db", dbPath, "-json", "scripting")
+	if code != 0 || !strings.Contains(out, `"title": "Scripting subscriptions"`) || strings.Contains(out, "Another post") {
+		t.Fatalf("articles search: code %d, output %q", code, out)
+	}
+
+	code, _, _ = run(t, "articles", "mark-read", "-db", dbPath, "-all")
+	if code != 0 {
+		t.Fatalf("articles mark-read: code %d", code)
+	}
+	code, out, _ = run(t, "articles", "search", "-db", dbPath, "-unread", "post")
+	if code != 0 || strings.Contains(out, "Another post") {
+		t.Fatalf("articles search -unread after mark-read: code %d, output %q", code, out)
+	}
+
+	// Search has no API equivalent
+	if code, _, errOut := run(t, "articles", "search", "-server", srv.URL, "post"); code != 1 || !strings.Contains(errOut, "not available") {
+		t.Errorf("remote search: code %d, stderr %q", code, errOut)
+	}
+
+	code, out, _ = run(t, "feeds", "rm", "-db", dbPath, "1")
+	if code != 0 || !strings.Contains(out, "Removed feed 1") {
+		t.Fatalf("feeds rm: code %d, output %q", code, out)
+	}
+	if _, out, _ = run(t, "feeds", "list", "-db", dbPath); strings.Contains(out, "CLI Feed") {
+		t.Errorf("feed still listed after rm: %q", out)
+	}
+}
+
+func TestOPMLRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mrrss.db")
+	opmlPath := filepath.Join(dir, "subscriptions.opml")
+	os.WriteFile(opmlPath, []byte(`<?xml version="1.0"?>
+<opml version="1.0"><body>
+<outline text="News"><outline type="rss" text="Example" xmlUrl="https://example.com/feed.xml"/></outline>
+</body></opml>`), 0644)
+
+	code, out, errOut := run(t, "opml", "import", "-db", dbPath, opmlPath)
+	if code != 0 || !strings.Contains(out, "Imported 1 of 1 feeds") {
+		t.Fatalf("opml import: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+
+	code, out, _ = run(t, "opml", "export", "-db", dbPath)
+	if code != 0 || !strings.Contains(out, `xmlUrl="https://example.com/feed.xml"`) {
+		t.Fatalf("opml export: code %d, output %q", code, out)
+	}
+}
+
+func TestSettingsAndRules(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "mrrss.db")
+
+	if code, _, errOut := run(t, "settings", "set", "-db", dbPath, "deepl_api_key", "s3cret"); code != 0 {
+		t.Fatalf("settings set: code %d, stderr %q", code, errOut)
+	}
+	if code, out, _ := run(t, "settings", "get", "-db", dbPath, "deepl_api_key"); code != 0 || out != "s3cret\n" {
+		t.Errorf("settings get: code %d, output %q", code, out)
+	}
+	// Secrets are masked when listing all settings
+	if _, out, _ := run(t, "settings", "get", "-db", dbPath); strings.Contains(out, "s3cret") || !strings.Contains(out, maskedSecret) {
+		t.Errorf("secret not masked: %q", out)
+	}
+	if code, _, errOut := run(t, "settings", "set", "-db", dbPath, "update_interval", "often"); code != 1 || !strings.Contains(errOut, "integer") {
+		t.Errorf("invalid value: code %d, stderr %q", code, errOut)
+	}
+
+	// The value is stored encrypted
+	db, err := database.NewDB(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := db.GetSetting("deepl_api_key")
+	db.Close()
+	if stored == "" || stored == "s3cret" {
+		t.Errorf("deepl_api_key stored as %q, want encrypted", stored)
+	}
+
+	rulesJSON := `[{"id":1,"name":"Hide ads","enabled":true,"conditions":[{"field":"article_title","operator":"contains","value":"ad"}],"actions":["hide"]}]`
+	if code, _, errOut := run(t, "settings", "set", "-db", dbPath, "rules", rulesJSON); code != 0 {
+		t.Fatalf("settings set rules: code %d, stderr %q", code, errOut)
+	}
+	code, out, errOut := run(t, "rules", "apply", "-db", dbPath)
+	if code != 0 || !strings.Contains(out, `Rule "Hide ads": 0 articles`) {
+		t.Errorf("rules apply: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if code, _, _ := run(t, "rules", "apply", "-db", dbPath, "-name", "Missing"); code != 1 {
+		t.Errorf("rules apply with unknown rule: code %d, want 1", code)
+	}
+}
+
+func TestBackupAndVacuum(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mrrss.db")
+	archive := filepath.Join(dir, "backup.zip")
+
+	t.Setenv(backupPassphraseEnv, "")
+	if code, _, errOut := run(t, "backup", "-db", dbPath, "-o", archive); code != 1 || !strings.Contains(errOut, "passphrase is required") {
+		t.Errorf("backup without passphrase: code %d, stderr %q", code, errOut)
+	}
+
+	t.Setenv(backupPassphraseEnv, "correct horse")
+	code, out, errOut := run(t, "backup", "-db", dbPath, "-o", archive)
+	if code != 0 || !strings.Contains(out, archive) {
+		t.Fatalf("backup: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if info, err := os.Stat(archive); err != nil || info.Size() == 0 {
+		t.Errorf("backup archive not written: %v", err)
+	}
+
+	if code, out, _ := run(t, "vacuum", "-db", dbPath); code != 0 || !strings.Contains(out, "Database compacted") {
+		t.Errorf("vacuum: code %d, output %q", code, out)
+	}
+}
+
+func TestProvision(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mrrss.db")
+	cfgPath := filepath.Join(dir, "mrrss.yaml")
+	cfg := "settings:\n  update_interval: 45\nfeeds:\n  - url: https://provision.example/feed.xml\n    category: Tech\n"
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("MRRSS_SETTING_DEEPL_API_KEY", "from-env")
+
+	code, out, errOut := run(t, "provision", "-db", dbPath, "-dry-run", cfgPath)
+	if code != 0 || !strings.Contains(out, "+ feed https://provision.example/feed.xml") || !strings.Contains(out, "3 changes to make") {
+		t.Fatalf("provision -dry-run: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if _, out, _ := run(t, "feeds", "list", "-db", dbPath); strings.Contains(out, "provision.example") {
+		t.Errorf("dry run added a feed: %q", out)
+	}
+
+	if code, out, errOut := run(t, "provision", "-db", dbPath, cfgPath); code != 0 || !strings.Contains(out, "3 changes made") {
+		t.Fatalf("provision: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if _, out, _ := run(t, "settings", "get", "-db", dbPath, "deepl_api_key"); out != "from-env\n" {
+		t.Errorf("deepl_api_key = %q", out)
+	}
+	if _, out, _ := run(t, "provision", "-db", dbPath, cfgPath); !strings.Contains(out, "Database matches the configuration") {
+		t.Errorf("second provision: %q", out)
+	}
+
+	if err := os.WriteFile(cfgPath, []byte("settings:\n  update_interval: often\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, errOut := run(t, "provision", "-db", dbPath, cfgPath); code != 1 || !strings.Contains(errOut, "integer") {
+		t.Errorf("invalid config: code %d, stderr %q", code, errOut)
+	}
+}
+
+func TestRemoteCommands(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	h := core.NewHandler(db, feed.NewFetcher(db, nil), nil)
+	mux := http.NewServeMux()
+	routes.Register(mux, h)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	if _, err := db.AddFeed(&models.Feed{Title: "Remote Feed", URL: "https://remote.example/feed.xml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	code, out, errOut := run(t, "feeds", "list", "-server", srv.URL)
+	if code != 0 || !strings.Contains(out, "Remote Feed") {
+		t.Fatalf("remote feeds list: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+
+	if code, _, errOut := run(t, "settings", "set", "-server", srv.URL, "theme", "dark"); code != 0 {
+		t.Fatalf("remote settings set: code %d, stderr %q", code, errOut)
+	}
+	if theme, _ := db.GetSetting("theme"); theme != "dark" {
+		t.Errorf("theme = %q after remote set, want dark", theme)
+	}
+	if code, out, _ := run(t, "settings", "get", "-server", srv.URL, "theme"); code != 0 || out != "dark\n" {
+		t.Errorf("remote settings get: code %d, output %q", code, out)
+	}
+
+	// Errors of the API are reported with their status
+	code, _, errOut = run(t, "feeds", "add", "-server", srv.URL, srv.URL+"/not-a-feed")
+	if code != 1 || !strings.Contains(errOut, "server error") {
+		t.Errorf("remote feeds add error: code %d, stderr %q", code, errOut)
+	}
+
+	// A page offering feeds is not subscribed to, its feeds are listed instead
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/feed.xml" {
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(testRSS))
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="/feed.xml"></head><body></body></html>`))
+	}))
+	defer site.Close()
+	code, out, errOut = run(t, "feeds", "add", "-server", srv.URL, site.URL+"/blog")
+	if code != 1 || strings.Contains(out, "Added") || !strings.Contains(errOut, site.URL+"/feed.xml") {
+		t.Errorf("remote feeds add of a page: code %d, stdout %q, stderr %q", code, out, errOut)
+	}
+	if feeds, _ := db.GetFeeds(); len(feeds) != 1 {
+		t.Errorf("expected the page not to be subscribed to, got %d feeds", len(feeds))
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// apiPrefix is the prefix of the versioned API, whose errors share one JSON format
+const apiPrefix = "/api/v1"
+
+// client calls the API of a running MrRSS server
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newClient(server string) *client {
+	return &client{
+		baseURL:    strings.TrimSuffix(server, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+}
+
+// call sends a request to an API path, with a body encoded by encodeBody.
+// The response is decoded into out when it is not nil.
+func (c *client) call(method, path string, query url.Values, body interface{}, out interface{}) error {
+	resp, err := c.send(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// download sends a request to an API path and copies the response body to w
+func (c *client) download(method, path string, query url.Values, body interface{}, w io.Writer) error {
+	resp, err := c.send(method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return fmt.Errorf("failed to read response of %s: %w", path, err)
+	}
+	return nil
+}
+
+// send sends a request and turns error responses into errors
+func (c *client) send(method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	target := c.baseURL + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	reader, contentType, err := encodeBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach server: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		return nil, apiError(resp)
+	}
+	return resp, nil
+}
+
+// upload is a request body sent as a file of a multipart form
+type upload struct {
+	field    string
+	filename string
+	data     []byte
+}
+
+// encodeBody encodes a request body: an upload as a multipart form, anything else as JSON
+func encodeBody(body interface{}) (io.Reader, string, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, "", nil
+	case upload:
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		part, err := mw.CreateFormFile(b.field, b.filename)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(b.data); err != nil {
+			return nil, "", err
+		}
+		if err := mw.Close(); err != nil {
+			return nil, "", err
+		}
+		return &buf, mw.FormDataContentType(), nil
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, "", err
+		}
+		return bytes.NewReader(data), "application/json", nil
+	}
+}
+
+// apiError reads the error envelope of the versioned API
+func apiError(resp *http.Response) error {
+	var envelope struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(data, &envelope) == nil && envelope.Error.Message != "" {
+		return fmt.Errorf("server error %d: %s", resp.StatusCode, envelope.Error.Message)
+	}
+	return fmt.Errorf("server error %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"text/tabwriter"
+
+	"MrRSS/internal/discovery"
+	"MrRSS/internal/models"
+)
+
+func runFeeds(e *env, args []string) error {
+	sub, args, err := e.subcommand("feeds", args, "add", "list", "rm", "refresh")
+	if err != nil {
+		return err
+	}
+	switch sub {
+	case "add":
+		return feedsAdd(e, args)
+	case "list":
+		return feedsList(e, args)
+	case "rm":
+		return feedsRemove(e, args)
+	default:
+		return feedsRefresh(e, args)
+	}
+}
+
+// feedsAdd subscribes to feeds and fetches their articles
+func feedsAdd(e *env, args []string) error {
+	fs := e.flags("feeds add", "feeds add [flags] URL...")
+	category := fs.String("category", "", "Category of the feeds")
+	title := fs.String("title", "", "Title of the feed instead of the feed's own title")
+	noRefresh := fs.Bool("no-refresh", false, "Do not fetch the articles of the new feeds")
+	if err := e.parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+
+	if c := e.remote(); c != nil {
+		for _, feedURL := range fs.Args() {
+			req := map[string]string{"url": feedURL, "category": *category, "title": *title}
+			if err := addRemoteFeed(e, c, req); err != nil {
+				return fmt.Errorf("%s: %w", feedURL, err)
+			}
+			fmt.Fprintf(e.stdout, "Added %s\n", feedURL)
+		}
+		return nil
+	}
+
+	fetcher, err := e.openFetcher()
+	if err != nil {
+		return err
+	}
+	for _, feedURL := range fs.Args() {
+		feedID, err := fetcher.AddSubscription(feedURL, *category, *title)
+		if err != nil {
+			return fmt.Errorf("%s: %w", feedURL, err)
+		}
+		feed, err := e.db.GetFeedByID(feedID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "Added feed %d: %s\n", feed.ID, feed.Title)
+
+		if !*noRefresh {
+			fetcher.FetchFeed(context.Background(), *feed)
+		}
+	}
+	return nil
+}
+
+// addRemoteFeed subscribes to a feed through the API. A page URL offering feeds is not
+// subscribed to: the feeds it offers are listed so that one of them can be added instead.
+func addRemoteFeed(e *env, c *client, req map[string]string) error {
+	resp, err := c.send(http.MethodPost, "/feeds/add", nil, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMultipleChoices {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	var choices struct {
+		Candidates []discovery.FeedCandidate `json:"candidates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&choices); err != nil {
+		return fmt.Errorf("failed to decode feed candidates: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(e.stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "URL\tTYPE\tITEMS\tTITLE")
+	for _, candidate := range choices.Candidates {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\n", candidate.URL, candidate.Type, candidate.ItemCount, candidate.Title)
+	}
+	tw.Flush()
+	return fmt.Errorf("not a feed, but a page offering %d feeds: add one of the feeds listed above", len(choices.Candidates))
+}
+
+// feedsList prints the subscriptions, as a table or as JSON
+func feedsList(e *env, args []string) error {
+	fs := e.flags("feeds list", "feeds list [flags]")
+	category := fs.String("category", "", "Only list the feeds of this category")
+	asJSON := fs.Bool("json", false, "Print the feeds as JSON")
+	if err := e.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var feeds []models.Feed
+	if c := e.remote(); c != nil {
+		if err := c.call(http.MethodGet, "/feeds", nil, nil, &feeds); err != nil {
+			return err
+		}
+	} else {
+		db, err := e.openDB()
+		if err != nil {
+			return err
+		}
+		if feeds, err = db.GetFeeds(); err != nil {
+			return err
+		}
+	}
+
+	if *category != "" {
+		var filtered []models.Feed
+		for _, f := range feeds {
+			if f.Category == *category {
+				filtered = append(filtered, f)
+			}
+		}
+		feeds = filtered
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		if feeds == nil {
+			feeds = []models.Feed{}
+		}
+		return enc.Encode(feeds)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTITLE\tCATEGORY\tURL\tLAST ERROR")
+	for _, f := range feeds {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", f.ID, f.Title, f.Category, f.URL, f.LastError)
+	}
+	return tw.Flush()
+}
+
+// feedsRemove deletes subscriptions with their articles
+func feedsRemove(e *env, args []string) error {
+	fs := e.flags("feeds rm", "feeds rm [flags] ID...")
+	if err := e.parse(fs, args, 1, -1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	c := e.remote()
+	if c == nil {
+		if _, err := e.openDB(); err != nil {
+			return err
+		}
+	}
+	for _, id := range ids {
+		if c != nil {
+			err = c.call(http.MethodPost, "/feeds/delete", url.Values{"id": {strconv.FormatInt(id, 10)}}, nil, nil)
+		} else {
+			err = e.db.DeleteFeed(id)
+		}
+		if err != nil {
+			return fmt.Errorf("feed %d: %w", id, err)
+		}
+		fmt.Fprintf(e.stdout, "Removed feed %d\n", id)
+	}
+	return nil
+}
+
+// feedsRefresh fetches the articles of the given feeds, or of all feeds.
+// Locally the feeds are fetched one after the other and failures are reported;
+// a running server refreshes them in the background.
+func feedsRefresh(e *env, args []string) error {
+	fs := e.flags("feeds refresh", "feeds refresh [flags] [ID...]")
+	if err := e.parse(fs, args, 0, -1); err != nil {
+		return err
+	}
+	ids, err := parseIDs(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if c := e.remote(); c != nil {
+		if len(ids) == 0 {
+			if err := c.call(http.MethodPost, "/refresh", nil, nil, nil); err != nil {
+				return err
+			}
+		}
+		for _, id := range ids {
+			if err := c.call(http.MethodPost, "/feeds/refresh", url.Values{"id": {strconv.FormatInt(id, 10)}}, nil, nil); err != nil {
+				return fmt.Errorf("feed %d: %w", id, err)
+			}
+		}
+		fmt.Fprintln(e.stdout, "Refresh started")
+		return nil
+	}
+
+	fetcher, err := e.openFetcher()
+	if err != nil {
+		return err
+	}
+
+	var feeds []models.Feed
+	if len(ids) == 0 {
+		all, err := e.db.GetFeeds()
+		if err != nil {
+			return err
+		}
+		// FreshRSS feeds are refreshed by syncing
+		for _, f := range all {
+			if !f.IsFreshRSSSource {
+				feeds = append(feeds, f)
+			}
+		}
+	}
+	for _, id := range ids {
+		f, err := e.db.GetFeedByID(id)
+		if err != nil {
+			return fmt.Errorf("feed %d: %w", id, err)
+		}
+		feeds = append(feeds, *f)
+	}
+
+	failed := 0
+	for _, f := range feeds {
+		fetcher.FetchFeed(context.Background(), f)
+		if updated, err := e.db.GetFeedByID(f.ID); err == nil && updated.LastError != "" {
+			failed++
+			fmt.Fprintf(e.stderr, "Feed %d (%s): %s\n", f.ID, f.Title, updated.LastError)
+		}
+	}
+
+	fmt.Fprintf(e.stdout, "Refreshed %d feeds, %d failed\n", len(feeds)-failed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d feeds failed to refresh", failed)
+	}
+	return nil
+}
+
+// parseIDs parses article or feed IDs
+func parseIDs(args []string) ([]int64, error) {
+	ids := make([]int64, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid ID %q", arg)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
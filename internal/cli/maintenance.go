@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MrRSS/internal/backup"
+	"MrRSS/internal/utils"
+)
+
+// backupPassphraseEnv is the environment variable holding the backup passphrase,
+// so that it does not show in the process list or in crontabs
+const backupPassphraseEnv = "MRRSS_BACKUP_PASSPHRASE"
+
+// runBackup writes a backup archive, which can be restored from the settings
+func runBackup(e *env, args []string) error {
+	fs := e.flags("backup", "backup [flags]")
+	output := fs.String("o", "", "File to write the archive to (default: mrrss-backup-DATE.zip)")
+	passphraseFile := fs.String("passphrase-file", "", "File whose first line is the passphrase (default: $"+backupPassphraseEnv+")")
+	if err := e.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	passphrase, err := readPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+	if passphrase == "" {
+		return fmt.Errorf("a passphrase is required: set %s or use -passphrase-file", backupPassphraseEnv)
+	}
+	if *output == "" {
+		*output = fmt.Sprintf("mrrss-backup-%s.zip", time.Now().Format("20060102-150405"))
+	}
+
+	// Write to a temp file first so that a failed backup does not leave a broken archive
+	archive, err := os.CreateTemp(filepath.Dir(*output), ".mrrss-backup-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if c := e.remote(); c != nil {
+		err = c.download(http.MethodPost, "/backup/export", nil, map[string]string{"passphrase": passphrase}, archive)
+	} else {
+		err = exportBackup(e, passphrase, archive)
+	}
+	if err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+	if err := os.Rename(archive.Name(), *output); err != nil {
+		return fmt.Errorf("failed to write backup file: %w", err)
+	}
+
+	fmt.Fprintf(e.stdout, "Backup written to %s\n", *output)
+	return nil
+}
+
+// exportBackup writes a backup archive of the database and of the data directory it is in
+func exportBackup(e *env, passphrase string, archive *os.File) error {
+	db, err := e.openDB()
+	if err != nil {
+		return err
+	}
+	dataDir := filepath.Dir(e.dbPath)
+	if dataDir == "." {
+		if dataDir, err = utils.GetDataDir(); err != nil {
+			return fmt.Errorf("failed to get data directory: %w", err)
+		}
+	}
+	return backup.Export(db, dataDir, passphrase, archive)
+}
+
+// readPassphrase reads the backup passphrase from a file or from the environment
+func readPassphrase(path string) (string, error) {
+	if path == "" {
+		return os.Getenv(backupPassphraseEnv), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(scanner.Text(), "\r"), nil
+}
+
+// runVacuum removes orphaned data and compacts the database file
+func runVacuum(e *env, args []string) error {
+	fs := e.flags("vacuum", "vacuum [flags]")
+	if err := e.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	if e.remote() != nil {
+		return errLocalOnly
+	}
+
+	db, err := e.openDB()
+	if err != nil {
+		return err
+	}
+	before := fileSize(e.dbPath)
+	if err := db.Vacuum(); err != nil {
+		return err
+	}
+	after := fileSize(e.dbPath)
+
+	fmt.Fprintf(e.stdout, "Database compacted from %.1f MB to %.1f MB\n", float64(before)/(1<<20), float64(after)/(1<<20))
+	return nil
+}
+
+// fileSize returns the size of a file, or 0 when it cannot be read
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/opml"
+)
+
+func runOPML(e *env, args []string) error {
+	sub, args, err := e.subcommand("opml", args, "import", "export")
+	if err != nil {
+		return err
+	}
+	if sub == "import" {
+		return opmlImport(e, args)
+	}
+	return opmlExport(e, args)
+}
+
+// opmlImport subscribes to the feeds of an OPML file, or of stdin with "-".
+// Feeds already subscribed to are updated with the imported configuration.
+func opmlImport(e *env, args []string) error {
+	fs := e.flags("opml import", "opml import [flags] FILE|-")
+	if err := e.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	name := fs.Arg(0)
+	if name == "-" {
+		data, err = io.ReadAll(e.stdin)
+		name = "subscriptions.opml"
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if c := e.remote(); c != nil {
+		var result struct {
+			Imported int `json:"imported"`
+			Total    int `json:"total"`
+		}
+		body := upload{field: "file", filename: filepath.Base(name), data: data}
+		if err := c.call(http.MethodPost, "/opml/import", nil, body, &result); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "Imported %d of %d feeds\n", result.Imported, result.Total)
+		return nil
+	}
+
+	feeds, err := opml.Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse OPML: %w", err)
+	}
+	fetcher, err := e.openFetcher()
+	if err != nil {
+		return err
+	}
+
+	imported := 0
+	for _, f := range feeds {
+		// The feed configuration (XPath, script, proxy, ...) is imported as is
+		if _, err := fetcher.ImportSubscription(f); err != nil {
+			fmt.Fprintf(e.stderr, "Error importing feed %s: %v\n", f.URL, err)
+			continue
+		}
+		imported++
+	}
+	fmt.Fprintf(e.stdout, "Imported %d of %d feeds\n", imported, len(feeds))
+	return nil
+}
+
+// opmlExport writes the subscriptions as OPML to a file or stdout.
+// FreshRSS feeds are left out as they are managed by the FreshRSS server.
+func opmlExport(e *env, args []string) error {
+	fs := e.flags("opml export", "opml export [flags]")
+	output := fs.String("o", "", "File to write the OPML to (default: stdout)")
+	includeSecrets := fs.Bool("include-secrets", false, "Export secrets such as email passwords")
+	if err := e.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var data []byte
+	if c := e.remote(); c != nil {
+		query := url.Values{}
+		if *includeSecrets {
+			query.Set("include_secrets", "true")
+		}
+		var buf bytes.Buffer
+		if err := c.download(http.MethodGet, "/opml/export", query, nil, &buf); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	} else {
+		db, err := e.openDB()
+		if err != nil {
+			return err
+		}
+		feeds, err := db.GetFeeds()
+		if err != nil {
+			return err
+		}
+		var localFeeds []models.Feed
+		for _, f := range feeds {
+			if !f.IsFreshRSSSource {
+				localFeeds = append(localFeeds, f)
+			}
+		}
+		data, err = opml.GenerateWithOptions(localFeeds, opml.GenerateOptions{IncludeSecrets: *includeSecrets})
+		if err != nil {
+			return err
+		}
+	}
+
+	if *output == "" {
+		_, err := e.stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "Exported subscriptions to %s\n", *output)
+	return nil
+}
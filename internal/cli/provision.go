@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"MrRSS/internal/provision"
+)
+
+// runProvision makes the database match a configuration file, or with -dry-run
+// prints the changes that would be made. MRRSS_SETTING_<KEY> variables override settings.
+func runProvision(e *env, args []string) error {
+	fs := e.flags("provision", "provision [flags] FILE")
+	dryRun := fs.Bool("dry-run", false, "Print the changes without making them")
+	if err := e.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if e.remote() != nil {
+		return errLocalOnly
+	}
+
+	cfg, err := provision.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := cfg.ApplyEnv(os.Environ()); err != nil {
+		return err
+	}
+
+	db, err := e.openDB()
+	if err != nil {
+		return err
+	}
+	changes, err := provision.Reconcile(db, cfg, *dryRun)
+	for _, c := range changes {
+		fmt.Fprintln(e.stdout, c)
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(changes) == 0:
+		fmt.Fprintln(e.stdout, "Database matches the configuration")
+	case *dryRun:
+		fmt.Fprintf(e.stdout, "%d changes to make\n", len(changes))
+	default:
+		fmt.Fprintf(e.stdout, "%d changes made\n", len(changes))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"MrRSS/internal/rules"
+)
+
+func runRules(e *env, args []string) error {
+	_, args, err := e.subcommand("rules", args, "apply")
+	if err != nil {
+		return err
+	}
+	return rulesApply(e, args)
+}
+
+// rulesApply applies rules to the existing articles: the enabled saved rules,
+// one saved rule chosen by ID or name, or the rules of a JSON file
+func rulesApply(e *env, args []string) error {
+	fs := e.flags("rules apply", "rules apply [flags] [FILE]")
+	id := fs.Int64("id", 0, "Only apply the saved rule with this ID, even if disabled")
+	name := fs.String("name", "", "Only apply the saved rule with this name, even if disabled")
+	if err := e.parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+
+	var selected []rules.Rule
+	if fs.NArg() == 1 {
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if selected, err = parseRules(data); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", fs.Arg(0), err)
+		}
+	} else {
+		saved, err := savedRules(e)
+		if err != nil {
+			return err
+		}
+		for _, rule := range saved {
+			if (*id == 0 && *name == "" && rule.Enabled) || (*id != 0 && rule.ID == *id) || (*name != "" && rule.Name == *name) {
+				selected = append(selected, rule)
+			}
+		}
+		if len(selected) == 0 && (*id != 0 || *name != "") {
+			return fmt.Errorf("no saved rule matches")
+		}
+	}
+
+	c := e.remote()
+	var engine *rules.Engine
+	if c == nil {
+		db, err := e.openDB()
+		if err != nil {
+			return err
+		}
+		engine = rules.NewEngine(db)
+	}
+
+	for _, rule := range selected {
+		if len(rule.Actions) == 0 {
+			fmt.Fprintf(e.stderr, "Skipping rule %q: no actions\n", rule.Name)
+			continue
+		}
+		var affected int
+		var err error
+		if c != nil {
+			var result struct {
+				Affected int `json:"affected"`
+			}
+			err = c.call(http.MethodPost, "/rules/apply", nil, rule, &result)
+			affected = result.Affected
+		} else {
+			affected, err = engine.ApplyRule(rule)
+		}
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		fmt.Fprintf(e.stdout, "Rule %q: %d articles\n", rule.Name, affected)
+	}
+	return nil
+}
+
+// savedRules returns the rules saved in the settings
+func savedRules(e *env) ([]rules.Rule, error) {
+	value, err := getSetting(e, "rules")
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return nil, nil
+	}
+	return parseRules([]byte(value))
+}
+
+// parseRules parses a JSON rule or array of rules
+func parseRules(data []byte) ([]rules.Rule, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var rule rules.Rule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			return nil, err
+		}
+		return []rules.Rule{rule}, nil
+	}
+	var list []rules.Rule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
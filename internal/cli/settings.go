@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+
+	"MrRSS/internal/config"
+)
+
+// maskedSecret replaces the values of encrypted settings when all settings are printed
+const maskedSecret = "********"
+
+func runSettings(e *env, args []string) error {
+	sub, args, err := e.subcommand("settings", args, "get", "set")
+	if err != nil {
+		return err
+	}
+	if sub == "get" {
+		return settingsGet(e, args)
+	}
+	return settingsSet(e, args)
+}
+
+// settingsGet prints settings. Without keys all settings are printed, with the
+// values of encrypted settings masked unless -show-secrets is given.
+func settingsGet(e *env, args []string) error {
+	fs := e.flags("settings get", "settings get [flags] [KEY...]")
+	showSecrets := fs.Bool("show-secrets", false, "Print the values of encrypted settings when printing all settings")
+	asJSON := fs.Bool("json", false, "Print the settings as a JSON object")
+	if err := e.parse(fs, args, 0, -1); err != nil {
+		return err
+	}
+
+	keys := fs.Args()
+	for _, key := range keys {
+		if !config.IsSettingKey(key) {
+			return fmt.Errorf("unknown setting %q", key)
+		}
+	}
+	mask := len(keys) == 0 && !*showSecrets
+	if len(keys) == 0 {
+		keys = config.SettingsKeys()
+	}
+
+	values, err := getSettings(e, keys)
+	if err != nil {
+		return err
+	}
+	if mask {
+		for _, key := range config.EncryptedSettingsKeys() {
+			if values[key] != "" {
+				values[key] = maskedSecret
+			}
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(values)
+	}
+	if len(keys) == 1 {
+		fmt.Fprintln(e.stdout, values[keys[0]])
+		return nil
+	}
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", key, values[key])
+	}
+	return tw.Flush()
+}
+
+// settingsSet changes a setting. Encrypted settings are stored encrypted.
+func settingsSet(e *env, args []string) error {
+	fs := e.flags("settings set", "settings set [flags] KEY VALUE")
+	if err := e.parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	key, value := fs.Arg(0), fs.Arg(1)
+	if err := config.ValidateSetting(key, value); err != nil {
+		return err
+	}
+
+	if c := e.remote(); c != nil {
+		// The API leaves plain settings unchanged when their value is empty
+		if value == "" && !config.IsEncryptedSetting(key) {
+			return fmt.Errorf("cannot clear %s on a running server", key)
+		}
+		// The API saves the whole settings object, so change the current settings
+		var values map[string]string
+		if err := c.call(http.MethodGet, "/settings", nil, nil, &values); err != nil {
+			return err
+		}
+		values[key] = value
+		if err := c.call(http.MethodPost, "/settings", nil, values, nil); err != nil {
+			return err
+		}
+	} else {
+		db, err := e.openDB()
+		if err != nil {
+			return err
+		}
+		if config.IsEncryptedSetting(key) {
+			err = db.SetEncryptedSetting(key, value)
+		} else {
+			err = db.SetSetting(key, value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save %s: %w", key, err)
+		}
+	}
+
+	fmt.Fprintf(e.stdout, "Set %s\n", key)
+	return nil
+}
+
+// getSetting returns the value of one setting, decrypted if needed
+func getSetting(e *env, key string) (string, error) {
+	values, err := getSettings(e, []string{key})
+	if err != nil {
+		return "", err
+	}
+	return values[key], nil
+}
+
+// getSettings returns the values of settings, decrypted if needed
+func getSettings(e *env, keys []string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+
+	if c := e.remote(); c != nil {
+		var all map[string]string
+		if err := c.call(http.MethodGet, "/settings", nil, nil, &all); err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			values[key] = all[key]
+		}
+		return values, nil
+	}
+
+	db, err := e.openDB()
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		var value string
+		if config.IsEncryptedSetting(key) {
+			value, err = db.GetEncryptedSetting(key)
+		} else {
+			value, err = db.GetSetting(key)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			// Settings that were never saved use their default
+			value = config.GetString(key)
+		} else if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
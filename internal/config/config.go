@@ -18,80 +18,104 @@ var defaultsJSON []byte
 
 // Defaults holds all default settings values
 type Defaults struct {
-	AIAPIKey                 string `json:"ai_api_key"`
-	AIChatEnabled            bool   `json:"ai_chat_enabled"`
-	AICustomHeaders          string `json:"ai_custom_headers"`
-	AIEndpoint               string `json:"ai_endpoint"`
-	AIModel                  string `json:"ai_model"`
-	AISummaryPrompt          string `json:"ai_summary_prompt"`
-	AITranslationPrompt      string `json:"ai_translation_prompt"`
-	AIUsageLimit             string `json:"ai_usage_limit"`
-	AIUsageTokens            string `json:"ai_usage_tokens"`
-	AutoCleanupEnabled       bool   `json:"auto_cleanup_enabled"`
-	AutoShowAllContent       bool   `json:"auto_show_all_content"`
-	AutoUpdate               bool   `json:"auto_update"`
-	BaiduAppId               string `json:"baidu_app_id"`
-	BaiduSecretKey           string `json:"baidu_secret_key"`
-	CloseToTray              bool   `json:"close_to_tray"`
-	CustomCssFile            string `json:"custom_css_file"`
-	DeeplAPIKey              string `json:"deepl_api_key"`
-	DeeplEndpoint            string `json:"deepl_endpoint"`
-	DefaultViewMode          string `json:"default_view_mode"`
-	FreshRSSAPIPassword      string `json:"freshrss_api_password"`
-	FreshRSSAutoSyncInterval int    `json:"freshrss_auto_sync_interval"`
-	FreshRSSEnabled          bool   `json:"freshrss_enabled"`
-	FreshRSSLastSyncTime     string `json:"freshrss_last_sync_time"`
-	FreshRSSServerUrl        string `json:"freshrss_server_url"`
-	FreshRSSSyncOnStartup    bool   `json:"freshrss_sync_on_startup"`
-	FreshRSSUsername         string `json:"freshrss_username"`
-	FullTextFetchEnabled     bool   `json:"full_text_fetch_enabled"`
-	GoogleTranslateEndpoint  string `json:"google_translate_endpoint"`
-	HoverMarkAsRead          bool   `json:"hover_mark_as_read"`
-	ImageGalleryEnabled      bool   `json:"image_gallery_enabled"`
-	Language                 string `json:"language"`
-	LastGlobalRefresh        string `json:"last_global_refresh"`
-	LastNetworkTest          string `json:"last_network_test"`
-	MaxArticleAgeDays        int    `json:"max_article_age_days"`
-	MaxCacheSizeMb           int    `json:"max_cache_size_mb"`
-	MaxConcurrentRefreshes   string `json:"max_concurrent_refreshes"`
-	MediaCacheEnabled        bool   `json:"media_cache_enabled"`
-	MediaCacheMaxAgeDays     int    `json:"media_cache_max_age_days"`
-	MediaCacheMaxSizeMb      int    `json:"media_cache_max_size_mb"`
-	MediaProxyFallback       bool   `json:"media_proxy_fallback"`
-	NetworkBandwidthMbps     string `json:"network_bandwidth_mbps"`
-	NetworkLatencyMs         string `json:"network_latency_ms"`
-	NetworkSpeed             string `json:"network_speed"`
-	ObsidianEnabled          bool   `json:"obsidian_enabled"`
-	ObsidianVault            string `json:"obsidian_vault"`
-	ObsidianVaultPath        string `json:"obsidian_vault_path"`
-	ProxyEnabled             bool   `json:"proxy_enabled"`
-	ProxyHost                string `json:"proxy_host"`
-	ProxyPassword            string `json:"proxy_password"`
-	ProxyPort                string `json:"proxy_port"`
-	ProxyType                string `json:"proxy_type"`
-	ProxyUsername            string `json:"proxy_username"`
-	RefreshMode              string `json:"refresh_mode"`
-	RetryTimeoutSeconds      int    `json:"retry_timeout_seconds"`
-	Rules                    string `json:"rules"`
-	Shortcuts                string `json:"shortcuts"`
-	ShortcutsEnabled         bool   `json:"shortcuts_enabled"`
-	ShowArticlePreviewImages bool   `json:"show_article_preview_images"`
-	ShowHiddenArticles       bool   `json:"show_hidden_articles"`
-	StartupOnBoot            bool   `json:"startup_on_boot"`
-	SummaryEnabled           bool   `json:"summary_enabled"`
-	SummaryLength            string `json:"summary_length"`
-	SummaryProvider          string `json:"summary_provider"`
-	SummaryTriggerMode       string `json:"summary_trigger_mode"`
-	TargetLanguage           string `json:"target_language"`
-	Theme                    string `json:"theme"`
-	TranslationEnabled       bool   `json:"translation_enabled"`
-	TranslationProvider      string `json:"translation_provider"`
-	UpdateInterval           int    `json:"update_interval"`
-	WindowHeight             string `json:"window_height"`
-	WindowMaximized          string `json:"window_maximized"`
-	WindowWidth              string `json:"window_width"`
-	WindowX                  string `json:"window_x"`
-	WindowY                  string `json:"window_y"`
+	AIAPIKey                  string `json:"ai_api_key"`
+	AIBudgetAlertThreshold    string `json:"ai_budget_alert_threshold"`
+	AIChatEnabled             bool   `json:"ai_chat_enabled"`
+	AICostBudget              string `json:"ai_cost_budget"`
+	AICustomHeaders           string `json:"ai_custom_headers"`
+	AIEndpoint                string `json:"ai_endpoint"`
+	AIModel                   string `json:"ai_model"`
+	AIPriceTable              string `json:"ai_price_table"`
+	AISummaryPrompt           string `json:"ai_summary_prompt"`
+	AITranslationPrompt       string `json:"ai_translation_prompt"`
+	AIUsageAutoReset          bool   `json:"ai_usage_auto_reset"`
+	AIUsageLimit              string `json:"ai_usage_limit"`
+	AIUsagePeriod             string `json:"ai_usage_period"`
+	AIUsageTokens             string `json:"ai_usage_tokens"`
+	AutoCleanupEnabled        bool   `json:"auto_cleanup_enabled"`
+	AutoShowAllContent        bool   `json:"auto_show_all_content"`
+	AutoUpdate                bool   `json:"auto_update"`
+	BaiduAppId                string `json:"baidu_app_id"`
+	BaiduSecretKey            string `json:"baidu_secret_key"`
+	CloseToTray               bool   `json:"close_to_tray"`
+	CustomCssFile             string `json:"custom_css_file"`
+	DeeplAPIKey               string `json:"deepl_api_key"`
+	DeeplEndpoint             string `json:"deepl_endpoint"`
+	DefaultViewMode           string `json:"default_view_mode"`
+	EmbeddingAPIKey           string `json:"embedding_api_key"`
+	EmbeddingDedupEnabled     bool   `json:"embedding_dedup_enabled"`
+	EmbeddingDedupThreshold   string `json:"embedding_dedup_threshold"`
+	EmbeddingEnabled          bool   `json:"embedding_enabled"`
+	EmbeddingEndpoint         string `json:"embedding_endpoint"`
+	EmbeddingModel            string `json:"embedding_model"`
+	FreshRSSAPIPassword       string `json:"freshrss_api_password"`
+	FreshRSSAutoSyncInterval  int    `json:"freshrss_auto_sync_interval"`
+	FreshRSSEnabled           bool   `json:"freshrss_enabled"`
+	FreshRSSLastSyncTime      string `json:"freshrss_last_sync_time"`
+	FreshRSSServerUrl         string `json:"freshrss_server_url"`
+	FreshRSSSyncOnStartup     bool   `json:"freshrss_sync_on_startup"`
+	FreshRSSUsername          string `json:"freshrss_username"`
+	FullTextFetchEnabled      bool   `json:"full_text_fetch_enabled"`
+	GoogleTranslateEndpoint   string `json:"google_translate_endpoint"`
+	HoverMarkAsRead           bool   `json:"hover_mark_as_read"`
+	ImageGalleryEnabled       bool   `json:"image_gallery_enabled"`
+	Language                  string `json:"language"`
+	LastGlobalRefresh         string `json:"last_global_refresh"`
+	LastNetworkTest           string `json:"last_network_test"`
+	MaxArticleAgeDays         int    `json:"max_article_age_days"`
+	MaxCacheSizeMb            int    `json:"max_cache_size_mb"`
+	MaxConcurrentRefreshes    string `json:"max_concurrent_refreshes"`
+	MediaCacheEnabled         bool   `json:"media_cache_enabled"`
+	MediaCacheMaxAgeDays      int    `json:"media_cache_max_age_days"`
+	MediaCacheMaxSizeMb       int    `json:"media_cache_max_size_mb"`
+	MediaProxyAllowedHosts    string `json:"media_proxy_allowed_hosts"`
+	MediaProxyDeniedHosts     string `json:"media_proxy_denied_hosts"`
+	MediaProxyFallback        bool   `json:"media_proxy_fallback"`
+	MediaProxyMaxSizeMb       int    `json:"media_proxy_max_size_mb"`
+	MediaProxyPrivateNetworks string `json:"media_proxy_private_networks"`
+	NetworkBandwidthMbps      string `json:"network_bandwidth_mbps"`
+	NetworkLatencyMs          string `json:"network_latency_ms"`
+	NetworkSpeed              string `json:"network_speed"`
+	ObsidianEnabled           bool   `json:"obsidian_enabled"`
+	ObsidianVault             string `json:"obsidian_vault"`
+	ObsidianVaultPath         string `json:"obsidian_vault_path"`
+	OfflinePrefetchCategories string `json:"offline_prefetch_categories"`
+	OfflinePrefetchEnabled    bool   `json:"offline_prefetch_enabled"`
+	OfflinePrefetchFavorites  bool   `json:"offline_prefetch_favorites"`
+	OfflinePrefetchMaxSizeMb  int    `json:"offline_prefetch_max_size_mb"`
+	OfflinePrefetchReadLater  bool   `json:"offline_prefetch_read_later"`
+	OfflinePrefetchUnread     bool   `json:"offline_prefetch_unread"`
+	PodcastDownloadMaxSizeMb  int    `json:"podcast_download_max_size_mb"`
+	ProxyEnabled              bool   `json:"proxy_enabled"`
+	ProxyHost                 string `json:"proxy_host"`
+	ProxyPassword             string `json:"proxy_password"`
+	ProxyPort                 string `json:"proxy_port"`
+	ProxyType                 string `json:"proxy_type"`
+	ProxyUsername             string `json:"proxy_username"`
+	RefreshMode               string `json:"refresh_mode"`
+	RetryTimeoutSeconds       int    `json:"retry_timeout_seconds"`
+	Rules                     string `json:"rules"`
+	Shortcuts                 string `json:"shortcuts"`
+	ShortcutsEnabled          bool   `json:"shortcuts_enabled"`
+	ShowArticlePreviewImages  bool   `json:"show_article_preview_images"`
+	ShowHiddenArticles        bool   `json:"show_hidden_articles"`
+	StartupOnBoot             bool   `json:"startup_on_boot"`
+	SummaryEnabled            bool   `json:"summary_enabled"`
+	SummaryLength             string `json:"summary_length"`
+	SummaryProvider           string `json:"summary_provider"`
+	SummaryTriggerMode        string `json:"summary_trigger_mode"`
+	TargetLanguage            string `json:"target_language"`
+	Theme                     string `json:"theme"`
+	TranslationEnabled        bool   `json:"translation_enabled"`
+	TranslationProvider       string `json:"translation_provider"`
+	UpdateInterval            int    `json:"update_interval"`
+	VideoTranscriptsEnabled   bool   `json:"video_transcripts_enabled"`
+	WebsubCallbackUrl         string `json:"websub_callback_url"`
+	WindowHeight              string `json:"window_height"`
+	WindowMaximized           string `json:"window_maximized"`
+	WindowWidth               string `json:"window_width"`
+	WindowX                   string `json:"window_x"`
+	WindowY                   string `json:"window_y"`
 }
 
 var defaults Defaults
@@ -112,20 +136,30 @@ func GetString(key string) string {
 	switch key {
 	case "ai_api_key":
 		return defaults.AIAPIKey
+	case "ai_budget_alert_threshold":
+		return defaults.AIBudgetAlertThreshold
 	case "ai_chat_enabled":
 		return strconv.FormatBool(defaults.AIChatEnabled)
+	case "ai_cost_budget":
+		return defaults.AICostBudget
 	case "ai_custom_headers":
 		return defaults.AICustomHeaders
 	case "ai_endpoint":
 		return defaults.AIEndpoint
 	case "ai_model":
 		return defaults.AIModel
+	case "ai_price_table":
+		return defaults.AIPriceTable
 	case "ai_summary_prompt":
 		return defaults.AISummaryPrompt
 	case "ai_translation_prompt":
 		return defaults.AITranslationPrompt
+	case "ai_usage_auto_reset":
+		return strconv.FormatBool(defaults.AIUsageAutoReset)
 	case "ai_usage_limit":
 		return defaults.AIUsageLimit
+	case "ai_usage_period":
+		return defaults.AIUsagePeriod
 	case "ai_usage_tokens":
 		return defaults.AIUsageTokens
 	case "auto_cleanup_enabled":
@@ -148,6 +182,18 @@ func GetString(key string) string {
 		return defaults.DeeplEndpoint
 	case "default_view_mode":
 		return defaults.DefaultViewMode
+	case "embedding_api_key":
+		return defaults.EmbeddingAPIKey
+	case "embedding_dedup_enabled":
+		return strconv.FormatBool(defaults.EmbeddingDedupEnabled)
+	case "embedding_dedup_threshold":
+		return defaults.EmbeddingDedupThreshold
+	case "embedding_enabled":
+		return strconv.FormatBool(defaults.EmbeddingEnabled)
+	case "embedding_endpoint":
+		return defaults.EmbeddingEndpoint
+	case "embedding_model":
+		return defaults.EmbeddingModel
 	case "freshrss_api_password":
 		return defaults.FreshRSSAPIPassword
 	case "freshrss_auto_sync_interval":
@@ -188,8 +234,16 @@ func GetString(key string) string {
 		return strconv.Itoa(defaults.MediaCacheMaxAgeDays)
 	case "media_cache_max_size_mb":
 		return strconv.Itoa(defaults.MediaCacheMaxSizeMb)
+	case "media_proxy_allowed_hosts":
+		return defaults.MediaProxyAllowedHosts
+	case "media_proxy_denied_hosts":
+		return defaults.MediaProxyDeniedHosts
 	case "media_proxy_fallback":
 		return strconv.FormatBool(defaults.MediaProxyFallback)
+	case "media_proxy_max_size_mb":
+		return strconv.Itoa(defaults.MediaProxyMaxSizeMb)
+	case "media_proxy_private_networks":
+		return defaults.MediaProxyPrivateNetworks
 	case "network_bandwidth_mbps":
 		return defaults.NetworkBandwidthMbps
 	case "network_latency_ms":
@@ -202,6 +256,20 @@ func GetString(key string) string {
 		return defaults.ObsidianVault
 	case "obsidian_vault_path":
 		return defaults.ObsidianVaultPath
+	case "offline_prefetch_categories":
+		return defaults.OfflinePrefetchCategories
+	case "offline_prefetch_enabled":
+		return strconv.FormatBool(defaults.OfflinePrefetchEnabled)
+	case "offline_prefetch_favorites":
+		return strconv.FormatBool(defaults.OfflinePrefetchFavorites)
+	case "offline_prefetch_max_size_mb":
+		return strconv.Itoa(defaults.OfflinePrefetchMaxSizeMb)
+	case "offline_prefetch_read_later":
+		return strconv.FormatBool(defaults.OfflinePrefetchReadLater)
+	case "offline_prefetch_unread":
+		return strconv.FormatBool(defaults.OfflinePrefetchUnread)
+	case "podcast_download_max_size_mb":
+		return strconv.Itoa(defaults.PodcastDownloadMaxSizeMb)
 	case "proxy_enabled":
 		return strconv.FormatBool(defaults.ProxyEnabled)
 	case "proxy_host":
@@ -248,6 +316,10 @@ func GetString(key string) string {
 		return defaults.TranslationProvider
 	case "update_interval":
 		return strconv.Itoa(defaults.UpdateInterval)
+	case "video_transcripts_enabled":
+		return strconv.FormatBool(defaults.VideoTranscriptsEnabled)
+	case "websub_callback_url":
+		return defaults.WebsubCallbackUrl
 	case "window_height":
 		return defaults.WindowHeight
 	case "window_maximized":
@@ -20,3 +20,21 @@ func TestGetString_UnknownKey(t *testing.T) {
 		t.Fatalf("expected empty string for unknown key, got %q", v)
 	}
 }
+
+func TestValidateSetting(t *testing.T) {
+	valid := [][2]string{{"update_interval", "15"}, {"translation_enabled", "false"}, {"theme", "dark"}}
+	for _, kv := range valid {
+		if err := ValidateSetting(kv[0], kv[1]); err != nil {
+			t.Errorf("ValidateSetting(%q, %q) = %v, want nil", kv[0], kv[1], err)
+		}
+	}
+	invalid := [][2]string{{"update_interval", "often"}, {"translation_enabled", "yes"}, {"no_such_setting", "1"}}
+	for _, kv := range invalid {
+		if err := ValidateSetting(kv[0], kv[1]); err == nil {
+			t.Errorf("ValidateSetting(%q, %q) = nil, want error", kv[0], kv[1])
+		}
+	}
+	if !IsEncryptedSetting("ai_api_key") || IsEncryptedSetting("theme") {
+		t.Error("IsEncryptedSetting does not follow the schema")
+	}
+}
@@ -7,5 +7,10 @@ package config
 
 // SettingsKeys returns all valid setting keys
 func SettingsKeys() []string {
-	return []string{"ai_api_key", "ai_chat_enabled", "ai_custom_headers", "ai_endpoint", "ai_model", "ai_summary_prompt", "ai_translation_prompt", "ai_usage_limit", "ai_usage_tokens", "auto_cleanup_enabled", "auto_show_all_content", "auto_update", "baidu_app_id", "baidu_secret_key", "close_to_tray", "custom_css_file", "deepl_api_key", "deepl_endpoint", "default_view_mode", "freshrss_api_password", "freshrss_auto_sync_interval", "freshrss_enabled", "freshrss_last_sync_time", "freshrss_server_url", "freshrss_sync_on_startup", "freshrss_username", "full_text_fetch_enabled", "google_translate_endpoint", "hover_mark_as_read", "image_gallery_enabled", "language", "last_global_refresh", "last_network_test", "max_article_age_days", "max_cache_size_mb", "max_concurrent_refreshes", "media_cache_enabled", "media_cache_max_age_days", "media_cache_max_size_mb", "media_proxy_fallback", "network_bandwidth_mbps", "network_latency_ms", "network_speed", "obsidian_enabled", "obsidian_vault", "obsidian_vault_path", "proxy_enabled", "proxy_host", "proxy_password", "proxy_port", "proxy_type", "proxy_username", "refresh_mode", "retry_timeout_seconds", "rules", "shortcuts", "shortcuts_enabled", "show_article_preview_images", "show_hidden_articles", "startup_on_boot", "summary_enabled", "summary_length", "summary_provider", "summary_trigger_mode", "target_language", "theme", "translation_enabled", "translation_provider", "update_interval", "window_height", "window_maximized", "window_width", "window_x", "window_y"}
+	return []string{"ai_api_key", "ai_budget_alert_threshold", "ai_chat_enabled", "ai_cost_budget", "ai_custom_headers", "ai_endpoint", "ai_model", "ai_price_table", "ai_summary_prompt", "ai_translation_prompt", "ai_usage_auto_reset", "ai_usage_limit", "ai_usage_period", "ai_usage_tokens", "auto_cleanup_enabled", "auto_show_all_content", "auto_update", "baidu_app_id", "baidu_secret_key", "close_to_tray", "custom_css_file", "deepl_api_key", "deepl_endpoint", "default_view_mode", "embedding_api_key", "embedding_dedup_enabled", "embedding_dedup_threshold", "embedding_enabled", "embedding_endpoint", "embedding_model", "freshrss_api_password", "freshrss_auto_sync_interval", "freshrss_enabled", "freshrss_last_sync_time", "freshrss_server_url", "freshrss_sync_on_startup", "freshrss_username", "full_text_fetch_enabled", "google_translate_endpoint", "hover_mark_as_read", "image_gallery_enabled", "language", "last_global_refresh", "last_network_test", "max_article_age_days", "max_cache_size_mb", "max_concurrent_refreshes", "media_cache_enabled", "media_cache_max_age_days", "media_cache_max_size_mb", "media_proxy_allowed_hosts", "media_proxy_denied_hosts", "media_proxy_fallback", "media_proxy_max_size_mb", "media_proxy_private_networks", "network_bandwidth_mbps", "network_latency_ms", "network_speed", "obsidian_enabled", "obsidian_vault", "obsidian_vault_path", "offline_prefetch_categories", "offline_prefetch_enabled", "offline_prefetch_favorites", "offline_prefetch_max_size_mb", "offline_prefetch_read_later", "offline_prefetch_unread", "podcast_download_max_size_mb", "proxy_enabled", "proxy_host", "proxy_password", "proxy_port", "proxy_type", "proxy_username", "refresh_mode", "retry_timeout_seconds", "rules", "shortcuts", "shortcuts_enabled", "show_article_preview_images", "show_hidden_articles", "startup_on_boot", "summary_enabled", "summary_length", "summary_provider", "summary_trigger_mode", "target_language", "theme", "translation_enabled", "translation_provider", "update_interval", "video_transcripts_enabled", "websub_callback_url", "window_height", "window_maximized", "window_width", "window_x", "window_y"}
+}
+
+// EncryptedSettingsKeys returns the setting keys stored encrypted
+func EncryptedSettingsKeys() []string {
+	return []string{"ai_api_key", "baidu_secret_key", "deepl_api_key", "embedding_api_key", "freshrss_api_password", "proxy_password", "proxy_username"}
 }
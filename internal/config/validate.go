@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// IsSettingKey reports whether key is a known setting
+func IsSettingKey(key string) bool {
+	for _, k := range SettingsKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEncryptedSetting reports whether a setting is stored encrypted
+func IsEncryptedSetting(key string) bool {
+	for _, k := range EncryptedSettingsKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSetting checks that key is a known setting and that value has the type of its default
+func ValidateSetting(key, value string) error {
+	t := reflect.TypeOf(Defaults{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] != key {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			if value != "true" && value != "false" {
+				return fmt.Errorf("%s must be true or false", key)
+			}
+		case reflect.Int:
+			if _, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("%s must be an integer", key)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown setting %q", key)
+}
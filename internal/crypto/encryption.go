@@ -25,6 +25,8 @@ const (
 	saltSize = 16
 	// Version marker to identify encrypted values (prevents false positives in IsEncrypted)
 	versionMarker = "MrRSS-v1:"
+	// Version marker to identify values encrypted with a passphrase
+	passphraseMarker = "MrRSS-pass-v1:"
 )
 
 var (
@@ -32,6 +34,8 @@ var (
 	ErrInvalidCiphertext = errors.New("invalid ciphertext")
 	// ErrDecryptionFailed is returned when decryption fails
 	ErrDecryptionFailed = errors.New("decryption failed")
+	// ErrEmptyPassphrase is returned when a passphrase is required but empty
+	ErrEmptyPassphrase = errors.New("passphrase is empty")
 )
 
 // GetMachineID generates a machine-specific identifier for key derivation.
@@ -84,6 +88,60 @@ func Encrypt(plaintext string) (string, error) {
 		return "", fmt.Errorf("failed to get machine ID: %w", err)
 	}
 
+	return encryptWithSecret(plaintext, machineID, versionMarker)
+}
+
+// Decrypt decrypts ciphertext that was encrypted with Encrypt.
+// The input must be version-prefixed base64-encoded and contain: [salt][nonce][ciphertext+tag]
+func Decrypt(ciphertextBase64 string) (string, error) {
+	if ciphertextBase64 == "" {
+		return "", nil
+	}
+
+	// Check version marker before deriving any key
+	if !strings.HasPrefix(ciphertextBase64, versionMarker) {
+		return "", fmt.Errorf("missing or invalid version marker")
+	}
+
+	// Get machine ID for key derivation
+	machineID, err := GetMachineID()
+	if err != nil {
+		return "", fmt.Errorf("failed to get machine ID: %w", err)
+	}
+
+	return decryptWithSecret(ciphertextBase64, machineID, versionMarker)
+}
+
+// EncryptWithPassphrase encrypts plaintext like Encrypt, but with a key derived from
+// a user-supplied passphrase instead of the machine ID, so the value can be moved
+// to another machine (e.g. in a backup).
+func EncryptWithPassphrase(plaintext, passphrase string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	if passphrase == "" {
+		return "", ErrEmptyPassphrase
+	}
+	return encryptWithSecret(plaintext, passphrase, passphraseMarker)
+}
+
+// DecryptWithPassphrase decrypts ciphertext that was encrypted with EncryptWithPassphrase.
+func DecryptWithPassphrase(ciphertextBase64, passphrase string) (string, error) {
+	if ciphertextBase64 == "" {
+		return "", nil
+	}
+	if passphrase == "" {
+		return "", ErrEmptyPassphrase
+	}
+	if !strings.HasPrefix(ciphertextBase64, passphraseMarker) {
+		return "", fmt.Errorf("missing or invalid version marker")
+	}
+	return decryptWithSecret(ciphertextBase64, passphrase, passphraseMarker)
+}
+
+// encryptWithSecret encrypts plaintext with AES-256-GCM and a key derived from secret,
+// and prefixes the base64-encoded result with marker.
+func encryptWithSecret(plaintext, secret, marker string) (string, error) {
 	// Generate random salt
 	salt := make([]byte, saltSize)
 	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
@@ -91,7 +149,7 @@ func Encrypt(plaintext string) (string, error) {
 	}
 
 	// Derive encryption key
-	key := DeriveKey(machineID, salt)
+	key := DeriveKey(secret, salt)
 
 	// Create AES cipher
 	block, err := aes.NewCipher(key)
@@ -122,21 +180,12 @@ func Encrypt(plaintext string) (string, error) {
 
 	// Encode to base64 and prepend version marker for safe storage
 	encoded := base64.StdEncoding.EncodeToString(result)
-	return versionMarker + encoded, nil
+	return marker + encoded, nil
 }
 
-// Decrypt decrypts ciphertext that was encrypted with Encrypt.
-// The input must be version-prefixed base64-encoded and contain: [salt][nonce][ciphertext+tag]
-func Decrypt(ciphertextBase64 string) (string, error) {
-	if ciphertextBase64 == "" {
-		return "", nil
-	}
-
-	// Check and strip version marker
-	if !strings.HasPrefix(ciphertextBase64, versionMarker) {
-		return "", fmt.Errorf("missing or invalid version marker")
-	}
-	ciphertextBase64 = strings.TrimPrefix(ciphertextBase64, versionMarker)
+// decryptWithSecret decrypts a value produced by encryptWithSecret with the same secret and marker.
+func decryptWithSecret(ciphertextBase64, secret, marker string) (string, error) {
+	ciphertextBase64 = strings.TrimPrefix(ciphertextBase64, marker)
 
 	// Decode from base64
 	data, err := base64.StdEncoding.DecodeString(ciphertextBase64)
@@ -152,14 +201,8 @@ func Decrypt(ciphertextBase64 string) (string, error) {
 	// Extract salt
 	salt := data[:saltSize]
 
-	// Get machine ID for key derivation
-	machineID, err := GetMachineID()
-	if err != nil {
-		return "", fmt.Errorf("failed to get machine ID: %w", err)
-	}
-
 	// Derive decryption key
-	key := DeriveKey(machineID, salt)
+	key := DeriveKey(secret, salt)
 
 	// Create AES cipher
 	block, err := aes.NewCipher(key)
@@ -201,3 +244,8 @@ func IsEncrypted(value string) bool {
 	// Check for version marker - this is definitive, not a heuristic
 	return strings.HasPrefix(value, versionMarker)
 }
+
+// IsPassphraseEncrypted checks if a value was encrypted with EncryptWithPassphrase.
+func IsPassphraseEncrypted(value string) bool {
+	return strings.HasPrefix(value, passphraseMarker)
+}
@@ -241,3 +241,34 @@ func BenchmarkIsEncrypted(b *testing.B) {
 		_ = IsEncrypted(encrypted)
 	}
 }
+
+func TestEncryptDecryptWithPassphrase(t *testing.T) {
+	encrypted, err := EncryptWithPassphrase("sk-secret", "correct horse")
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase() error = %v", err)
+	}
+	if !IsPassphraseEncrypted(encrypted) || IsEncrypted(encrypted) {
+		t.Fatalf("unexpected marker on %q", encrypted)
+	}
+
+	decrypted, err := DecryptWithPassphrase(encrypted, "correct horse")
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase() error = %v", err)
+	}
+	if decrypted != "sk-secret" {
+		t.Errorf("DecryptWithPassphrase() = %q, want %q", decrypted, "sk-secret")
+	}
+
+	if _, err := DecryptWithPassphrase(encrypted, "wrong"); err != ErrDecryptionFailed {
+		t.Errorf("expected ErrDecryptionFailed with wrong passphrase, got %v", err)
+	}
+	if _, err := EncryptWithPassphrase("sk-secret", ""); err != ErrEmptyPassphrase {
+		t.Errorf("expected ErrEmptyPassphrase, got %v", err)
+	}
+
+	// Machine-encrypted values are not passphrase-encrypted
+	machineEncrypted, _ := Encrypt("sk-secret")
+	if _, err := DecryptWithPassphrase(machineEncrypted, "correct horse"); err == nil {
+		t.Error("expected error decrypting machine-encrypted value with passphrase")
+	}
+}
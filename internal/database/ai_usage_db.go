@@ -0,0 +1,75 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// AIUsageDaily represents aggregated AI usage of one feature on one day
+type AIUsageDaily struct {
+	Date             string  `json:"date"` // Local date in YYYY-MM-DD format
+	Feature          string  `json:"feature"`
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	Cost             float64 `json:"cost"`
+}
+
+// ledgerTimeFormat matches the format SQLite uses for CURRENT_TIMESTAMP (UTC).
+const ledgerTimeFormat = "2006-01-02 15:04:05"
+
+// RecordAIUsage stores a single AI request in the usage ledger.
+func (db *DB) RecordAIUsage(feature, provider, model string, promptTokens, completionTokens int64, cost float64, estimated bool) error {
+	db.WaitForReady()
+	_, err := db.Exec(
+		`INSERT INTO ai_usage_ledger (created_at, feature, provider, model, prompt_tokens, completion_tokens, cost, estimated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		time.Now().UTC().Format(ledgerTimeFormat), feature, provider, model, promptTokens, completionTokens, cost, estimated,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record AI usage: %w", err)
+	}
+	return nil
+}
+
+// GetAIUsageCostSince returns the total cost of AI requests made since the given time.
+func (db *DB) GetAIUsageCostSince(since time.Time) (float64, error) {
+	db.WaitForReady()
+	var cost float64
+	err := db.QueryRow(
+		`SELECT COALESCE(SUM(cost), 0) FROM ai_usage_ledger WHERE created_at >= ?`,
+		since.UTC().Format(ledgerTimeFormat),
+	).Scan(&cost)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get AI usage cost: %w", err)
+	}
+	return cost, nil
+}
+
+// GetAIUsageDaily returns AI usage since the given time, aggregated per local day and feature.
+// Results are ordered by date and feature.
+func (db *DB) GetAIUsageDaily(since time.Time) ([]AIUsageDaily, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT date(created_at, 'localtime') AS day, feature, COUNT(*),
+		       COALESCE(SUM(prompt_tokens), 0), COALESCE(SUM(completion_tokens), 0), COALESCE(SUM(cost), 0)
+		FROM ai_usage_ledger
+		WHERE created_at >= ?
+		GROUP BY day, feature
+		ORDER BY day, feature
+	`, since.UTC().Format(ledgerTimeFormat))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AI usage history: %w", err)
+	}
+	defer rows.Close()
+
+	daily := make([]AIUsageDaily, 0)
+	for rows.Next() {
+		var d AIUsageDaily
+		if err := rows.Scan(&d.Date, &d.Feature, &d.Requests, &d.PromptTokens, &d.CompletionTokens, &d.Cost); err != nil {
+			return nil, fmt.Errorf("failed to scan AI usage history: %w", err)
+		}
+		daily = append(daily, d)
+	}
+	return daily, rows.Err()
+}
@@ -1,6 +1,9 @@
 package database
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+)
 
 // ArticleContent represents a cached article content entry
 type ArticleContent struct {
@@ -72,3 +75,40 @@ func (db *DB) GetArticleContentCount() (int64, error) {
 	}
 	return count, nil
 }
+
+// FeedArticleContent is the cached content of an article with the feed it belongs to
+type FeedArticleContent struct {
+	ArticleID int64
+	FeedID    int64
+	URL       string
+	Content   string
+}
+
+// EachRecentArticleContent calls fn with the cached content of the most recently
+// published articles, newest first, up to limit articles
+func (db *DB) EachRecentArticleContent(limit int, fn func(FeedArticleContent)) error {
+	db.WaitForReady()
+	rows, err := db.Query(
+		`SELECT a.id, a.feed_id, a.url, c.content
+		 FROM article_contents c
+		 JOIN articles a ON a.id = c.article_id
+		 ORDER BY a.published_at DESC
+		 LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to query article contents: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c FeedArticleContent
+		var articleURL sql.NullString
+		if err := rows.Scan(&c.ArticleID, &c.FeedID, &articleURL, &c.Content); err != nil {
+			return fmt.Errorf("failed to scan article content: %w", err)
+		}
+		c.URL = articleURL.String
+		fn(c)
+	}
+	return rows.Err()
+}
@@ -130,3 +130,54 @@ func TestArticleContentCache(t *testing.T) {
 		}
 	})
 }
+
+func TestEachRecentArticleContent(t *testing.T) {
+	db, err := NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer db.DB.Close()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	articles := []struct {
+		id          int64
+		feedID      int64
+		url         string
+		publishedAt string
+	}{
+		{1, 1, "https://a.example/old", "2024-01-01 10:00:00"},
+		{2, 2, "https://b.example/new", "2024-03-01 10:00:00"},
+		{3, 1, "https://a.example/mid", "2024-02-01 10:00:00"},
+		{4, 2, "https://b.example/uncached", "2024-04-01 10:00:00"},
+	}
+	for _, a := range articles {
+		if _, err := db.Exec(`INSERT INTO articles (id, feed_id, title, url, published_at) VALUES (?, ?, ?, ?, ?)`,
+			a.id, a.feedID, "Article", a.url, a.publishedAt); err != nil {
+			t.Fatalf("insert article: %v", err)
+		}
+		if a.id != 4 {
+			if err := db.SetArticleContent(a.id, "<p>content</p>"); err != nil {
+				t.Fatalf("SetArticleContent: %v", err)
+			}
+		}
+	}
+
+	var got []FeedArticleContent
+	if err := db.EachRecentArticleContent(2, func(c FeedArticleContent) {
+		got = append(got, c)
+	}); err != nil {
+		t.Fatalf("EachRecentArticleContent: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 contents, got %d", len(got))
+	}
+	if got[0].ArticleID != 2 || got[0].FeedID != 2 || got[0].URL != "https://b.example/new" {
+		t.Errorf("Unexpected first content: %+v", got[0])
+	}
+	if got[1].ArticleID != 3 || got[1].Content != "<p>content</p>" {
+		t.Errorf("Unexpected second content: %+v", got[1])
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"MrRSS/internal/langdetect"
 	"MrRSS/internal/models"
 	"MrRSS/internal/utils"
 )
@@ -15,16 +16,29 @@ import (
 func (db *DB) SaveArticle(article *models.Article) error {
 	db.WaitForReady()
 
+	// Detect language from the title when the caller did not provide one
+	if article.Lang == "" {
+		article.Lang = langdetect.Detect(article.Title)
+	}
+
 	// Generate unique_id for deduplication
 	uniqueID := utils.GenerateArticleUniqueID(article.Title, article.FeedID, article.PublishedAt, article.HasValidPublishedTime)
-	query := `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(query, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID)
+	query := `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, lang, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := db.Exec(query, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, article.Lang, uniqueID)
 	return err
 }
 
 // SaveArticles saves multiple articles in a transaction.
 // Includes progressive cleanup check to prevent database from exceeding size limit during refresh.
 func (db *DB) SaveArticles(ctx context.Context, articles []*models.Article) error {
+	_, err := db.SaveNewArticles(ctx, articles)
+	return err
+}
+
+// SaveNewArticles saves multiple articles in a transaction like SaveArticles, and
+// returns how many of them were new rather than already stored.
+// The ID of every new article is set to the ID of its inserted row.
+func (db *DB) SaveNewArticles(ctx context.Context, articles []*models.Article) (int, error) {
 	db.WaitForReady()
 
 	// Progressive cleanup: check if we need to clean up before saving
@@ -46,41 +60,74 @@ func (db *DB) SaveArticles(ctx context.Context, articles []*models.Article) erro
 
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	stmt, err := tx.PrepareContext(ctx, `INSERT OR IGNORE INTO articles (feed_id, title, url, image_url, audio_url, video_url, published_at, translated_title, is_read, is_favorite, is_hidden, is_read_later, summary, lang, unique_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer stmt.Close()
 
+	inserted := 0
+
 	for _, article := range articles {
 		// Check context before each insert
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return 0, ctx.Err()
 		default:
 		}
 
+		// Detect language from the title when the caller did not provide one
+		if article.Lang == "" {
+			article.Lang = langdetect.Detect(article.Title)
+		}
+
 		// Generate unique_id for deduplication
 		uniqueID := utils.GenerateArticleUniqueID(article.Title, article.FeedID, article.PublishedAt, article.HasValidPublishedTime)
-		_, err := stmt.ExecContext(ctx, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, uniqueID)
+		res, err := stmt.ExecContext(ctx, article.FeedID, article.Title, article.URL, article.ImageURL, article.AudioURL, article.VideoURL, article.PublishedAt, article.TranslatedTitle, article.IsRead, article.IsFavorite, article.IsHidden, article.IsReadLater, article.Summary, article.Lang, uniqueID)
 		if err != nil {
 			log.Println("Error saving article in batch:", err)
 			// Continue even if one fails
+		} else if n, _ := res.RowsAffected(); n > 0 {
+			inserted++
+			if id, err := res.LastInsertId(); err == nil {
+				article.ID = id
+			}
 		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return inserted, nil
 }
 
 // GetArticles retrieves articles with filtering, pagination, and sorting.
 func (db *DB) GetArticles(filter string, feedID int64, category string, showHidden bool, limit, offset int) ([]models.Article, error) {
+	return db.queryArticles(filter, feedID, category, showHidden, false, "", limit, offset)
+}
+
+// GetArticlesCollapsingStories retrieves articles like GetArticles, but lists each story
+// clustered from near-duplicate articles only once, under its lead article.
+// Alternates whose lead article is missing or hidden are listed on their own.
+func (db *DB) GetArticlesCollapsingStories(filter string, feedID int64, category string, showHidden bool, limit, offset int) ([]models.Article, error) {
+	return db.queryArticles(filter, feedID, category, showHidden, true, "", limit, offset)
+}
+
+// SearchArticles retrieves the articles whose title, translated title or summary contain
+// the search text, case-insensitively. The filter is the same as for GetArticles.
+func (db *DB) SearchArticles(search, filter string, limit, offset int) ([]models.Article, error) {
+	return db.queryArticles(filter, 0, "", false, false, search, limit, offset)
+}
+
+// queryArticles implements GetArticles, GetArticlesCollapsingStories and SearchArticles.
+func (db *DB) queryArticles(filter string, feedID int64, category string, showHidden, collapseStories bool, search string, limit, offset int) ([]models.Article, error) {
 	db.WaitForReady()
 	baseQuery := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.lang, f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 	`
@@ -121,6 +168,21 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 		args = append(args, category, category+"/%")
 	}
 
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		whereClauses = append(whereClauses, `(a.title LIKE ? ESCAPE '\' OR a.translated_title LIKE ? ESCAPE '\' OR a.summary LIKE ? ESCAPE '\')`)
+		args = append(args, pattern, pattern, pattern)
+	}
+
+	if collapseStories {
+		// Skip story alternates while their lead article is still visible
+		whereClauses = append(whereClauses, `NOT EXISTS (
+			SELECT 1 FROM article_embeddings e
+			JOIN articles lead ON lead.id = e.story_id
+			WHERE e.article_id = a.id AND e.story_id != 0 AND e.story_id != a.id AND lead.is_hidden = 0
+		)`)
+	}
+
 	query := baseQuery
 	if len(whereClauses) > 0 {
 		query += " WHERE " + whereClauses[0]
@@ -140,9 +202,9 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 	var articles []models.Article
 	for rows.Next() {
 		var a models.Article
-		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID, lang sql.NullString
 		var publishedAt sql.NullTime
-		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle); err != nil {
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &lang, &a.FeedTitle); err != nil {
 			log.Println("Error scanning article:", err)
 			continue
 		}
@@ -156,6 +218,7 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 		}
 		a.TranslatedTitle = translatedTitle.String
 		a.Summary = summary.String
+		a.Lang = lang.String
 		a.FreshRSSItemID = freshrssItemID.String
 		articles = append(articles, a)
 	}
@@ -167,7 +230,7 @@ func (db *DB) GetArticles(filter string, feedID int64, category string, showHidd
 func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 	db.WaitForReady()
 	query := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.lang, f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 		WHERE a.id = ?
@@ -175,9 +238,9 @@ func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 	row := db.QueryRow(query, id)
 
 	var a models.Article
-	var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+	var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID, lang sql.NullString
 	var publishedAt sql.NullTime
-	if err := row.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle); err != nil {
+	if err := row.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &lang, &a.FeedTitle); err != nil {
 		return nil, err
 	}
 	a.ImageURL = imageURL.String
@@ -190,10 +253,34 @@ func (db *DB) GetArticleByID(id int64) (*models.Article, error) {
 	}
 	a.TranslatedTitle = translatedTitle.String
 	a.Summary = summary.String
+	a.Lang = lang.String
 	a.FreshRSSItemID = freshrssItemID.String
 	return &a, nil
 }
 
+// GetArticlePublishTimes returns the publication times of the latest visible articles
+// of a feed, newest first.
+func (db *DB) GetArticlePublishTimes(feedID int64, limit int) ([]time.Time, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT published_at FROM articles WHERE feed_id = ? AND is_hidden = 0 ORDER BY published_at DESC LIMIT ?`, feedID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var published []time.Time
+	for rows.Next() {
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&publishedAt); err != nil {
+			return nil, err
+		}
+		if publishedAt.Valid {
+			published = append(published, publishedAt.Time)
+		}
+	}
+	return published, rows.Err()
+}
+
 // GetArticlesByIDs retrieves multiple articles by their IDs
 func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 	db.WaitForReady()
@@ -210,7 +297,7 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 	}
 
 	query := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.freshrss_item_id, a.lang, f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 		WHERE a.id IN (` + strings.Join(placeholders, ",") + `)
@@ -225,10 +312,10 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 	articles := []models.Article{}
 	for rows.Next() {
 		var a models.Article
-		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID sql.NullString
+		var imageURL, audioURL, videoURL, translatedTitle, summary, freshrssItemID, lang sql.NullString
 		var publishedAt sql.NullTime
 
-		err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &a.FeedTitle)
+		err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &freshrssItemID, &lang, &a.FeedTitle)
 		if err != nil {
 			return nil, err
 		}
@@ -243,6 +330,7 @@ func (db *DB) GetArticlesByIDs(ids []int64) ([]models.Article, error) {
 		}
 		a.TranslatedTitle = translatedTitle.String
 		a.Summary = summary.String
+		a.Lang = lang.String
 		a.FreshRSSItemID = freshrssItemID.String
 
 		articles = append(articles, a)
@@ -297,6 +385,13 @@ func (db *DB) UpdateArticleTranslation(id int64, translatedTitle string) error {
 	return err
 }
 
+// UpdateArticleLanguage updates the detected language code for an article.
+func (db *DB) UpdateArticleLanguage(id int64, lang string) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE articles SET lang = ? WHERE id = ?", lang, id)
+	return err
+}
+
 // ClearAllTranslations clears all translated titles from articles.
 func (db *DB) ClearAllTranslations() error {
 	db.WaitForReady()
@@ -465,7 +560,7 @@ func (db *DB) ClearReadLater() error {
 func (db *DB) GetImageGalleryArticles(feedID int64, showHidden bool, limit, offset int) ([]models.Article, error) {
 	db.WaitForReady()
 	baseQuery := `
-		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, f.title
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.audio_url, a.video_url, a.published_at, a.is_read, a.is_favorite, a.is_hidden, a.is_read_later, a.translated_title, a.summary, a.lang, f.title
 		FROM articles a
 		JOIN feeds f ON a.feed_id = f.id
 		WHERE COALESCE(f.is_image_mode, 0) = 1
@@ -497,9 +592,9 @@ func (db *DB) GetImageGalleryArticles(feedID int64, showHidden bool, limit, offs
 	articles := make([]models.Article, 0)
 	for rows.Next() {
 		var a models.Article
-		var imageURL, audioURL, videoURL, translatedTitle, summary sql.NullString
+		var imageURL, audioURL, videoURL, translatedTitle, summary, lang sql.NullString
 		var publishedAt sql.NullTime
-		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &a.FeedTitle); err != nil {
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &audioURL, &videoURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsHidden, &a.IsReadLater, &translatedTitle, &summary, &lang, &a.FeedTitle); err != nil {
 			log.Println("Error scanning article:", err)
 			continue
 		}
@@ -513,6 +608,7 @@ func (db *DB) GetImageGalleryArticles(feedID int64, showHidden bool, limit, offs
 		}
 		a.TranslatedTitle = translatedTitle.String
 		a.Summary = summary.String
+		a.Lang = lang.String
 		articles = append(articles, a)
 	}
 	return articles, nil
@@ -551,3 +647,11 @@ func (db *DB) GetArticleIDByURL(url string) (int64, error) {
 	}
 	return id, nil
 }
+
+// likeEscaper escapes the wildcards of LIKE patterns, using \ as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes text match literally in a LIKE pattern with ESCAPE '\'
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
@@ -224,6 +224,61 @@ func TestSaveArticlesBatchContextCancel(t *testing.T) {
 	}
 }
 
+func TestSaveNewArticlesSetsIDs(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	_ = db.QueryRow(`SELECT id FROM feeds WHERE url = ?`, "https://example.com/feed").Scan(&feedID)
+
+	publishedAt := time.Now()
+	existing := &models.Article{FeedID: feedID, Title: "existing", URL: "u1", PublishedAt: publishedAt}
+	if err := db.SaveArticles(context.Background(), []*models.Article{existing}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	again := &models.Article{FeedID: feedID, Title: "existing", URL: "u1", PublishedAt: publishedAt}
+	fresh := &models.Article{FeedID: feedID, Title: "fresh", URL: "u2", PublishedAt: publishedAt}
+	inserted, err := db.SaveNewArticles(context.Background(), []*models.Article{again, fresh})
+	if err != nil {
+		t.Fatalf("SaveNewArticles: %v", err)
+	}
+	if inserted != 1 {
+		t.Fatalf("inserted = %d, want 1", inserted)
+	}
+	if again.ID != 0 {
+		t.Errorf("already stored article got ID %d", again.ID)
+	}
+	if got, err := db.GetArticleByID(fresh.ID); err != nil || got.Title != "fresh" {
+		t.Errorf("GetArticleByID(%d) = %+v, %v", fresh.ID, got, err)
+	}
+}
+
+func TestGetArticlePublishTimes(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	_ = db.QueryRow(`SELECT id FROM feeds WHERE url = ?`, "https://example.com/feed").Scan(&feedID)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	articles := []*models.Article{
+		{FeedID: feedID, Title: "old", URL: "u1", PublishedAt: now.Add(-2 * time.Hour)},
+		{FeedID: feedID, Title: "new", URL: "u2", PublishedAt: now},
+		{FeedID: feedID, Title: "hidden", URL: "u3", PublishedAt: now.Add(-time.Hour), IsHidden: true},
+		{FeedID: feedID, Title: "oldest", URL: "u4", PublishedAt: now.Add(-3 * time.Hour)},
+	}
+	if err := db.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	published, err := db.GetArticlePublishTimes(feedID, 2)
+	if err != nil {
+		t.Fatalf("GetArticlePublishTimes: %v", err)
+	}
+	if len(published) != 2 || !published[0].Equal(now) || !published[1].Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("published = %v", published)
+	}
+}
+
 func TestArticleDeduplicationByUniqueID(t *testing.T) {
 	db := setupDBWithFeed(t)
 
@@ -323,3 +378,45 @@ func TestArticleDifferentTitlesNotDeduplicated(t *testing.T) {
 		t.Fatalf("expected 2 articles with different titles, got %d", len(articles))
 	}
 }
+
+func TestSearchArticles(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	row := db.QueryRow(`SELECT id FROM feeds WHERE url = ?`, "https://example.com/feed")
+	if err := row.Scan(&feedID); err != nil {
+		t.Fatalf("scan feed id: %v", err)
+	}
+
+	now := time.Now()
+	for i, a := range []*models.Article{
+		{Title: "Go 1.24 released", Summary: "New features"},
+		{Title: "Weekly notes", Summary: "Thoughts on golang generics"},
+		{Title: "100% coverage", Summary: "Testing"},
+		{Title: "Cooking", Summary: "Recipes"},
+	} {
+		a.FeedID = feedID
+		a.URL = fmt.Sprintf("https://example.com/search/%d", i)
+		a.PublishedAt = now.Add(-time.Duration(i) * time.Hour)
+		if err := db.SaveArticle(a); err != nil {
+			t.Fatalf("SaveArticle error: %v", err)
+		}
+	}
+
+	articles, err := db.SearchArticles("GO", "all", 10, 0)
+	if err != nil {
+		t.Fatalf("SearchArticles error: %v", err)
+	}
+	if len(articles) != 2 || articles[0].Title != "Go 1.24 released" || articles[1].Title != "Weekly notes" {
+		t.Fatalf("unexpected search results: %+v", articles)
+	}
+
+	// Wildcards in the search text match literally
+	articles, err = db.SearchArticles("0%", "all", 10, 0)
+	if err != nil {
+		t.Fatalf("SearchArticles error: %v", err)
+	}
+	if len(articles) != 1 || articles[0].Title != "100% coverage" {
+		t.Fatalf("unexpected search results for wildcard: %+v", articles)
+	}
+}
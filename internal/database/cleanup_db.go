@@ -1,6 +1,7 @@
 package database
 
 import (
+	"fmt"
 	"log"
 	"strconv"
 	"time"
@@ -50,6 +51,8 @@ func (db *DB) CleanupOldArticles() (int64, error) {
 	// Also cleanup related caches with the same age limit
 	_, _ = db.CleanupTranslationCache(maxAgeDays)
 	_, _ = db.CleanupOldArticleContents(maxAgeDays)
+	_, _ = db.CleanupOrphanedArticleData()
+	_, _ = db.CleanupOldFeedFetches(FeedFetchRetentionDays)
 
 	// Run VACUUM to reclaim space
 	_, _ = db.Exec("VACUUM")
@@ -445,3 +448,35 @@ func (db *DB) CleanupOldUnreadArticles(maxAgeDays int) (int64, error) {
 	count, _ := result.RowsAffected()
 	return count, nil
 }
+
+// CleanupOrphanedArticleData removes the embeddings, tags, podcast and video data
+// left behind by deleted articles.
+func (db *DB) CleanupOrphanedArticleData() (int64, error) {
+	var total int64
+	for _, cleanup := range []func() (int64, error){
+		db.CleanupOrphanedEmbeddings,
+		db.CleanupOrphanedArticleTags,
+		db.CleanupOrphanedPodcastData,
+		db.CleanupOrphanedVideoData,
+	} {
+		count, err := cleanup()
+		total += count
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Vacuum removes data left behind by deleted articles and feeds, then rebuilds
+// the database file to reclaim the free space.
+func (db *DB) Vacuum() error {
+	db.WaitForReady()
+
+	_, _ = db.CleanupOrphanedArticleData()
+
+	if _, err := db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
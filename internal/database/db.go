@@ -258,7 +258,19 @@ func (db *DB) Init() error {
 					email_folder TEXT DEFAULT 'INBOX',
 					email_last_uid INTEGER DEFAULT 0,
 					is_freshrss_source BOOLEAN DEFAULT 0,
-					freshrss_stream_id TEXT DEFAULT ''
+					freshrss_stream_id TEXT DEFAULT '',
+					translation_mode TEXT DEFAULT 'global',
+					translation_target_language TEXT DEFAULT '',
+					summary_provider TEXT DEFAULT 'global',
+					summary_length TEXT DEFAULT 'global',
+					pregenerate_on_fetch BOOLEAN DEFAULT 0,
+					is_paused BOOLEAN DEFAULT 0,
+					is_gone BOOLEAN DEFAULT 0,
+					consecutive_failures INTEGER DEFAULT 0,
+					backoff_until DATETIME,
+					min_refresh_interval INTEGER DEFAULT 0,
+					skip_hours TEXT DEFAULT '',
+					skip_days TEXT DEFAULT ''
 				)
 			`)
 			if err == nil {
@@ -271,7 +283,10 @@ func (db *DB) Init() error {
 						xpath_item_author, xpath_item_timestamp, xpath_item_time_format, xpath_item_thumbnail,
 						xpath_item_categories, xpath_item_uid, article_view_mode, auto_expand_content,
 						email_address, email_imap_server, email_imap_port, email_username, email_password,
-						email_folder, email_last_uid, is_freshrss_source, freshrss_stream_id
+						email_folder, email_last_uid, is_freshrss_source, freshrss_stream_id,
+						translation_mode, translation_target_language, summary_provider, summary_length,
+						pregenerate_on_fetch, is_paused, is_gone, consecutive_failures, backoff_until,
+						min_refresh_interval, skip_hours, skip_days
 					)
 					SELECT
 						id, title, url, link, description, category, image_url,
@@ -305,7 +320,19 @@ func (db *DB) Init() error {
 						COALESCE(email_folder, 'INBOX') as email_folder,
 						COALESCE(email_last_uid, 0) as email_last_uid,
 						COALESCE(is_freshrss_source, 0) as is_freshrss_source,
-						COALESCE(freshrss_stream_id, '') as freshrss_stream_id
+						COALESCE(freshrss_stream_id, '') as freshrss_stream_id,
+						COALESCE(translation_mode, 'global') as translation_mode,
+						COALESCE(translation_target_language, '') as translation_target_language,
+						COALESCE(summary_provider, 'global') as summary_provider,
+						COALESCE(summary_length, 'global') as summary_length,
+						COALESCE(pregenerate_on_fetch, 0) as pregenerate_on_fetch,
+						COALESCE(is_paused, 0) as is_paused,
+						COALESCE(is_gone, 0) as is_gone,
+						COALESCE(consecutive_failures, 0) as consecutive_failures,
+						backoff_until,
+						COALESCE(min_refresh_interval, 0) as min_refresh_interval,
+						COALESCE(skip_hours, '') as skip_hours,
+						COALESCE(skip_days, '') as skip_days
 					FROM feeds
 				`)
 				if err != nil {
@@ -325,6 +352,16 @@ func (db *DB) Init() error {
 	return err
 }
 
+// IsReady reports whether the database is initialized, without blocking.
+func (db *DB) IsReady() bool {
+	select {
+	case <-db.ready:
+		return true
+	default:
+		return false
+	}
+}
+
 // WaitForReady blocks until the database is initialized.
 func (db *DB) WaitForReady() {
 	<-db.ready
@@ -406,6 +443,178 @@ func initSchema(db *sql.DB) error {
 		FOREIGN KEY(session_id) REFERENCES chat_sessions(id) ON DELETE CASCADE
 	);
 
+	-- AI usage ledger recording every AI request for history and cost reporting
+	CREATE TABLE IF NOT EXISTS ai_usage_ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		feature TEXT NOT NULL,
+		provider TEXT DEFAULT '',
+		model TEXT DEFAULT '',
+		prompt_tokens INTEGER DEFAULT 0,
+		completion_tokens INTEGER DEFAULT 0,
+		cost REAL DEFAULT 0,
+		estimated BOOLEAN DEFAULT 0
+	);
+
+	-- Article embeddings table storing vectors for related articles, semantic search and story clustering
+	CREATE TABLE IF NOT EXISTS article_embeddings (
+		article_id INTEGER PRIMARY KEY,
+		model TEXT NOT NULL,
+		vector BLOB NOT NULL,
+		story_id INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Article tags table storing keyphrases extracted from article content
+	CREATE TABLE IF NOT EXISTS article_tags (
+		article_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		score REAL DEFAULT 0,
+		source TEXT NOT NULL DEFAULT 'keyphrase',
+		PRIMARY KEY (article_id, tag)
+	);
+
+	-- Media cache index for size accounting and least recently used eviction
+	CREATE TABLE IF NOT EXISTS media_cache (
+		url_hash TEXT NOT NULL,
+		width INTEGER NOT NULL DEFAULT 0,
+		file_name TEXT NOT NULL,
+		url TEXT,
+		content_type TEXT,
+		size INTEGER NOT NULL DEFAULT 0,
+		last_access DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (url_hash, width)
+	);
+
+	-- Articles downloaded for offline reading
+	CREATE TABLE IF NOT EXISTS offline_articles (
+		article_id INTEGER PRIMARY KEY,
+		content TEXT,
+		content_size INTEGER NOT NULL DEFAULT 0,
+		media_size INTEGER NOT NULL DEFAULT 0,
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Media cache files pinned by offline articles
+	CREATE TABLE IF NOT EXISTS offline_media (
+		article_id INTEGER NOT NULL,
+		url_hash TEXT NOT NULL,
+		PRIMARY KEY (article_id, url_hash)
+	);
+
+	-- Podcast metadata of articles with an audio enclosure
+	CREATE TABLE IF NOT EXISTS podcast_episodes (
+		article_id INTEGER PRIMARY KEY,
+		mime_type TEXT DEFAULT '',
+		file_size INTEGER DEFAULT 0,
+		duration INTEGER DEFAULT 0,
+		episode INTEGER DEFAULT 0,
+		season INTEGER DEFAULT 0,
+		episode_type TEXT DEFAULT '',
+		explicit BOOLEAN DEFAULT 0,
+		image_url TEXT DEFAULT '',
+		chapters_url TEXT DEFAULT '',
+		transcript_url TEXT DEFAULT '',
+		transcript_type TEXT DEFAULT ''
+	);
+
+	-- Playback position of podcast episodes
+	CREATE TABLE IF NOT EXISTS podcast_playback (
+		article_id INTEGER PRIMARY KEY,
+		position REAL NOT NULL DEFAULT 0,
+		completed BOOLEAN DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Podcast episodes downloaded to disk
+	CREATE TABLE IF NOT EXISTS podcast_downloads (
+		article_id INTEGER PRIMARY KEY,
+		url TEXT NOT NULL,
+		file_name TEXT DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'queued',
+		total_size INTEGER DEFAULT 0,
+		downloaded_size INTEGER DEFAULT 0,
+		error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		completed_at DATETIME
+	);
+
+	-- Up Next queue of podcast episodes
+	CREATE TABLE IF NOT EXISTS podcast_queue (
+		article_id INTEGER PRIMARY KEY,
+		position INTEGER NOT NULL
+	);
+
+	-- Video metadata of articles from YouTube and other Media RSS feeds
+	CREATE TABLE IF NOT EXISTS video_info (
+		article_id INTEGER PRIMARY KEY,
+		video_id TEXT DEFAULT '',
+		duration INTEGER DEFAULT 0,
+		views INTEGER DEFAULT 0,
+		rating_count INTEGER DEFAULT 0,
+		rating_average REAL DEFAULT 0,
+		thumbnails TEXT DEFAULT '[]'
+	);
+
+	-- Caption tracks of videos, fetched once per video
+	CREATE TABLE IF NOT EXISTS video_transcripts (
+		article_id INTEGER PRIMARY KEY,
+		lang TEXT DEFAULT '',
+		content TEXT DEFAULT '',
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- WebSub push subscriptions of feeds advertising a hub
+	CREATE TABLE IF NOT EXISTS websub_subscriptions (
+		feed_id INTEGER PRIMARY KEY,
+		hub TEXT NOT NULL,
+		topic TEXT NOT NULL,
+		secret TEXT DEFAULT '',
+		state TEXT NOT NULL DEFAULT 'discovered',
+		lease_expires_at DATETIME,
+		last_error TEXT DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Outgoing feeds published for other readers
+	CREATE TABLE IF NOT EXISTS published_feeds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		source TEXT NOT NULL,
+		category TEXT DEFAULT '',
+		conditions TEXT DEFAULT '',
+		item_limit INTEGER DEFAULT 50,
+		token TEXT NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	-- Fetch attempts of each feed for the feed health dashboard
+	CREATE TABLE IF NOT EXISTS feed_fetch_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		fetched_at DATETIME NOT NULL,
+		reason TEXT DEFAULT '',
+		duration_ms INTEGER DEFAULT 0,
+		status_code INTEGER DEFAULT 0,
+		bytes INTEGER DEFAULT 0,
+		new_items INTEGER DEFAULT 0,
+		error_class TEXT DEFAULT '',
+		error TEXT DEFAULT '',
+		redirect_url TEXT DEFAULT ''
+	);
+
+	-- Automatic changes of feeds, such as following permanent redirects, which can be undone
+	CREATE TABLE IF NOT EXISTS feed_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		old_value TEXT DEFAULT '',
+		new_value TEXT DEFAULT '',
+		created_at DATETIME NOT NULL,
+		undone_at DATETIME
+	);
+
 	-- Create indexes for better query performance
 	CREATE INDEX IF NOT EXISTS idx_articles_feed_id ON articles(feed_id);
 	CREATE INDEX IF NOT EXISTS idx_articles_published_at ON articles(published_at DESC);
@@ -431,6 +640,29 @@ func initSchema(db *sql.DB) error {
 	CREATE INDEX IF NOT EXISTS idx_chat_sessions_article_id ON chat_sessions(article_id);
 	CREATE INDEX IF NOT EXISTS idx_chat_sessions_updated_at ON chat_sessions(updated_at DESC);
 	CREATE INDEX IF NOT EXISTS idx_chat_messages_session_id ON chat_messages(session_id);
+
+	-- AI usage ledger index
+	CREATE INDEX IF NOT EXISTS idx_ai_usage_ledger_created_at ON ai_usage_ledger(created_at);
+
+	-- Article embeddings index
+	CREATE INDEX IF NOT EXISTS idx_article_embeddings_story_id ON article_embeddings(story_id);
+
+	-- Article tags index
+	CREATE INDEX IF NOT EXISTS idx_article_tags_tag ON article_tags(tag);
+
+	-- Media cache indexes
+	CREATE INDEX IF NOT EXISTS idx_media_cache_last_access ON media_cache(last_access);
+	CREATE INDEX IF NOT EXISTS idx_media_cache_file_name ON media_cache(file_name);
+
+	-- Offline media index
+	CREATE INDEX IF NOT EXISTS idx_offline_media_url_hash ON offline_media(url_hash);
+
+	-- Podcast download status index
+	CREATE INDEX IF NOT EXISTS idx_podcast_downloads_status ON podcast_downloads(status);
+
+	-- Feed fetch log index
+	CREATE INDEX IF NOT EXISTS idx_feed_fetch_log_feed ON feed_fetch_log(feed_id, fetched_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_feed_changes_feed ON feed_changes(feed_id);
 	`
 	_, err := db.Exec(query)
 	if err != nil {
@@ -526,6 +758,196 @@ func runMigrations(db *sql.DB) error {
 	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN freshrss_stream_id TEXT DEFAULT ''`)
 	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN freshrss_item_id TEXT DEFAULT ''`)
 
+	// Migration: Add lang column for detected article language (ISO 639-1 code)
+	_, _ = db.Exec(`ALTER TABLE articles ADD COLUMN lang TEXT DEFAULT ''`)
+
+	// Migration: Add per-feed translation and summary policy fields
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN translation_mode TEXT DEFAULT 'global'`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN translation_target_language TEXT DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN summary_provider TEXT DEFAULT 'global'`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN summary_length TEXT DEFAULT 'global'`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN pregenerate_on_fetch BOOLEAN DEFAULT 0`)
+
+	// Migration: Add ai_usage_ledger table for AI usage history and cost tracking
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS ai_usage_ledger (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		feature TEXT NOT NULL,
+		provider TEXT DEFAULT '',
+		model TEXT DEFAULT '',
+		prompt_tokens INTEGER DEFAULT 0,
+		completion_tokens INTEGER DEFAULT 0,
+		cost REAL DEFAULT 0,
+		estimated BOOLEAN DEFAULT 0
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_ai_usage_ledger_created_at ON ai_usage_ledger(created_at)`)
+
+	// Migration: Add article_embeddings table for related articles and semantic deduplication
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS article_embeddings (
+		article_id INTEGER PRIMARY KEY,
+		model TEXT NOT NULL,
+		vector BLOB NOT NULL,
+		story_id INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_article_embeddings_story_id ON article_embeddings(story_id)`)
+
+	// Migration: Add article_tags table for keyphrases extracted by the local summarizer
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS article_tags (
+		article_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		score REAL DEFAULT 0,
+		source TEXT NOT NULL DEFAULT 'keyphrase',
+		PRIMARY KEY (article_id, tag)
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_article_tags_tag ON article_tags(tag)`)
+
+	// Migration: Add media_cache table indexing the files of the media cache
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS media_cache (
+		url_hash TEXT NOT NULL,
+		width INTEGER NOT NULL DEFAULT 0,
+		file_name TEXT NOT NULL,
+		url TEXT,
+		content_type TEXT,
+		size INTEGER NOT NULL DEFAULT 0,
+		last_access DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (url_hash, width)
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_media_cache_last_access ON media_cache(last_access)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_media_cache_file_name ON media_cache(file_name)`)
+
+	// Migration: Add offline_articles and offline_media tables for offline reading
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS offline_articles (
+		article_id INTEGER PRIMARY KEY,
+		content TEXT,
+		content_size INTEGER NOT NULL DEFAULT 0,
+		media_size INTEGER NOT NULL DEFAULT 0,
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS offline_media (
+		article_id INTEGER NOT NULL,
+		url_hash TEXT NOT NULL,
+		PRIMARY KEY (article_id, url_hash)
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_offline_media_url_hash ON offline_media(url_hash)`)
+
+	// Migration: Add podcast tables for episode metadata, playback positions, downloads and the Up Next queue
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS podcast_episodes (
+		article_id INTEGER PRIMARY KEY,
+		mime_type TEXT DEFAULT '',
+		file_size INTEGER DEFAULT 0,
+		duration INTEGER DEFAULT 0,
+		episode INTEGER DEFAULT 0,
+		season INTEGER DEFAULT 0,
+		episode_type TEXT DEFAULT '',
+		explicit BOOLEAN DEFAULT 0,
+		image_url TEXT DEFAULT '',
+		chapters_url TEXT DEFAULT '',
+		transcript_url TEXT DEFAULT '',
+		transcript_type TEXT DEFAULT ''
+	)`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS podcast_playback (
+		article_id INTEGER PRIMARY KEY,
+		position REAL NOT NULL DEFAULT 0,
+		completed BOOLEAN DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS podcast_downloads (
+		article_id INTEGER PRIMARY KEY,
+		url TEXT NOT NULL,
+		file_name TEXT DEFAULT '',
+		status TEXT NOT NULL DEFAULT 'queued',
+		total_size INTEGER DEFAULT 0,
+		downloaded_size INTEGER DEFAULT 0,
+		error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		completed_at DATETIME
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_podcast_downloads_status ON podcast_downloads(status)`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS podcast_queue (
+		article_id INTEGER PRIMARY KEY,
+		position INTEGER NOT NULL
+	)`)
+
+	// Migration: Add video metadata and transcript tables
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS video_info (
+		article_id INTEGER PRIMARY KEY,
+		video_id TEXT DEFAULT '',
+		duration INTEGER DEFAULT 0,
+		views INTEGER DEFAULT 0,
+		rating_count INTEGER DEFAULT 0,
+		rating_average REAL DEFAULT 0,
+		thumbnails TEXT DEFAULT '[]'
+	)`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS video_transcripts (
+		article_id INTEGER PRIMARY KEY,
+		lang TEXT DEFAULT '',
+		content TEXT DEFAULT '',
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+
+	// Migration: Add websub_subscriptions table for push updates from WebSub hubs
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS websub_subscriptions (
+		feed_id INTEGER PRIMARY KEY,
+		hub TEXT NOT NULL,
+		topic TEXT NOT NULL,
+		secret TEXT DEFAULT '',
+		state TEXT NOT NULL DEFAULT 'discovered',
+		lease_expires_at DATETIME,
+		last_error TEXT DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+
+	// Migration: Add published_feeds table for outgoing feeds
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS published_feeds (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		source TEXT NOT NULL,
+		category TEXT DEFAULT '',
+		conditions TEXT DEFAULT '',
+		item_limit INTEGER DEFAULT 50,
+		token TEXT NOT NULL UNIQUE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+
+	// Migration: Add feed_fetch_log table and is_paused column for the feed health dashboard
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS feed_fetch_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		fetched_at DATETIME NOT NULL,
+		reason TEXT DEFAULT '',
+		duration_ms INTEGER DEFAULT 0,
+		status_code INTEGER DEFAULT 0,
+		bytes INTEGER DEFAULT 0,
+		new_items INTEGER DEFAULT 0,
+		error_class TEXT DEFAULT '',
+		error TEXT DEFAULT ''
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_feed_fetch_log_feed ON feed_fetch_log(feed_id, fetched_at DESC)`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN is_paused BOOLEAN DEFAULT 0`)
+
+	// Migration: Add redirect, gone and backoff tracking with the feed_changes table of automatic changes
+	_, _ = db.Exec(`ALTER TABLE feed_fetch_log ADD COLUMN redirect_url TEXT DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN is_gone BOOLEAN DEFAULT 0`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN consecutive_failures INTEGER DEFAULT 0`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN backoff_until DATETIME`)
+	_, _ = db.Exec(`CREATE TABLE IF NOT EXISTS feed_changes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feed_id INTEGER NOT NULL,
+		kind TEXT NOT NULL,
+		old_value TEXT DEFAULT '',
+		new_value TEXT DEFAULT '',
+		created_at DATETIME NOT NULL,
+		undone_at DATETIME
+	)`)
+	_, _ = db.Exec(`CREATE INDEX IF NOT EXISTS idx_feed_changes_feed ON feed_changes(feed_id)`)
+
+	// Migration: Add the refresh limits feeds ask for with <ttl>, sy:updatePeriod, <skipHours> and <skipDays>
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN min_refresh_interval INTEGER DEFAULT 0`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN skip_hours TEXT DEFAULT ''`)
+	_, _ = db.Exec(`ALTER TABLE feeds ADD COLUMN skip_days TEXT DEFAULT ''`)
+
 	return nil
 }
 
@@ -0,0 +1,212 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// ArticleEmbedding represents the stored embedding vector of an article
+type ArticleEmbedding struct {
+	ArticleID   int64
+	Model       string
+	Vector      []byte // Encoded float32 vector
+	StoryID     int64  // ID of the lead article of the story this article belongs to (0 = none)
+	PublishedAt time.Time
+}
+
+// SaveArticleEmbedding stores the embedding vector of an article, replacing any previous one.
+func (db *DB) SaveArticleEmbedding(articleID int64, model string, vector []byte) error {
+	db.WaitForReady()
+	_, err := db.Exec(
+		`INSERT OR REPLACE INTO article_embeddings (article_id, model, vector, story_id, created_at) VALUES (?, ?, ?, 0, CURRENT_TIMESTAMP)`,
+		articleID, model, vector,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save article embedding: %w", err)
+	}
+	return nil
+}
+
+// GetArticleEmbedding retrieves the embedding of an article. Returns nil if none is stored.
+func (db *DB) GetArticleEmbedding(articleID int64) (*ArticleEmbedding, error) {
+	db.WaitForReady()
+	var e ArticleEmbedding
+	var publishedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT e.article_id, e.model, e.vector, COALESCE(e.story_id, 0), a.published_at
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.article_id = ?
+	`, articleID).Scan(&e.ArticleID, &e.Model, &e.Vector, &e.StoryID, &publishedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article embedding: %w", err)
+	}
+	e.PublishedAt = publishedAt.Time
+	return &e, nil
+}
+
+// GetArticlesWithoutEmbedding returns the newest articles published since the given time
+// that have no embedding for the given model.
+func (db *DB) GetArticlesWithoutEmbedding(model string, since time.Time, limit int) ([]models.Article, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT a.id, a.feed_id, a.title, a.published_at
+		FROM articles a
+		LEFT JOIN article_embeddings e ON e.article_id = a.id
+		WHERE (e.article_id IS NULL OR e.model != ?) AND a.published_at >= ?
+		ORDER BY a.published_at DESC
+		LIMIT ?
+	`, model, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles without embedding: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]models.Article, 0)
+	for rows.Next() {
+		var a models.Article
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &publishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		a.PublishedAt = publishedAt.Time
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetEmbeddingsPublishedBetween returns embeddings of the given model for articles
+// published within the given time range.
+func (db *DB) GetEmbeddingsPublishedBetween(model string, from, to time.Time) ([]ArticleEmbedding, error) {
+	db.WaitForReady()
+	return db.queryEmbeddings(`
+		SELECT e.article_id, e.model, e.vector, COALESCE(e.story_id, 0), a.published_at
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.model = ? AND a.published_at BETWEEN ? AND ?
+	`, model, from, to)
+}
+
+// GetRecentEmbeddings returns embeddings of the given model for the most recently
+// published visible articles.
+func (db *DB) GetRecentEmbeddings(model string, limit int) ([]ArticleEmbedding, error) {
+	db.WaitForReady()
+	return db.queryEmbeddings(`
+		SELECT e.article_id, e.model, e.vector, COALESCE(e.story_id, 0), a.published_at
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.model = ? AND a.is_hidden = 0
+		ORDER BY a.published_at DESC
+		LIMIT ?
+	`, model, limit)
+}
+
+// queryEmbeddings runs an embedding query and scans the results
+func (db *DB) queryEmbeddings(query string, args ...interface{}) ([]ArticleEmbedding, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get embeddings: %w", err)
+	}
+	defer rows.Close()
+
+	embeddings := make([]ArticleEmbedding, 0)
+	for rows.Next() {
+		var e ArticleEmbedding
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&e.ArticleID, &e.Model, &e.Vector, &e.StoryID, &publishedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding: %w", err)
+		}
+		e.PublishedAt = publishedAt.Time
+		embeddings = append(embeddings, e)
+	}
+	return embeddings, rows.Err()
+}
+
+// SetArticleStoryID assigns an article to the story led by storyID.
+func (db *DB) SetArticleStoryID(articleID, storyID int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE article_embeddings SET story_id = ? WHERE article_id = ?`, storyID, articleID)
+	return err
+}
+
+// GetStoryArticleIDs returns the IDs of all existing articles in a story, including its lead article.
+func (db *DB) GetStoryArticleIDs(storyID int64) ([]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT e.article_id
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.story_id = ?
+		ORDER BY a.published_at ASC
+	`, storyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story articles: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetStoryAlternateCounts returns, for each given lead article, the number of other
+// existing articles clustered into its story. Articles without alternates are omitted.
+func (db *DB) GetStoryAlternateCounts(leadIDs []int64) (map[int64]int, error) {
+	db.WaitForReady()
+	counts := make(map[int64]int)
+	if len(leadIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders := make([]string, len(leadIDs))
+	args := make([]interface{}, len(leadIDs))
+	for i, id := range leadIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+
+	rows, err := db.Query(`
+		SELECT e.story_id, COUNT(*)
+		FROM article_embeddings e
+		JOIN articles a ON a.id = e.article_id
+		WHERE e.story_id IN (`+strings.Join(placeholders, ",")+`) AND e.article_id != e.story_id
+		GROUP BY e.story_id
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get story alternate counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var storyID int64
+		var count int
+		if err := rows.Scan(&storyID, &count); err != nil {
+			return nil, err
+		}
+		counts[storyID] = count
+	}
+	return counts, rows.Err()
+}
+
+// CleanupOrphanedEmbeddings removes embeddings whose articles no longer exist.
+func (db *DB) CleanupOrphanedEmbeddings() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_embeddings WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,122 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArticleEmbeddingsAndStoryCollapse(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+
+	now := time.Now()
+	articles := []*models.Article{
+		{FeedID: feedID, Title: "lead", URL: "https://example.com/1", PublishedAt: now.Add(-2 * time.Hour)},
+		{FeedID: feedID, Title: "alternate", URL: "https://example.com/2", PublishedAt: now.Add(-time.Hour)},
+		{FeedID: feedID, Title: "other", URL: "https://example.com/3", PublishedAt: now},
+	}
+	if err := db.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	ids := make(map[string]int64)
+	all, err := db.GetArticles("", 0, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticles: %v", err)
+	}
+	for _, a := range all {
+		ids[a.Title] = a.ID
+	}
+
+	pending, err := db.GetArticlesWithoutEmbedding("m", now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetArticlesWithoutEmbedding: %v", err)
+	}
+	if len(pending) != 3 {
+		t.Fatalf("expected 3 articles without embedding, got %d", len(pending))
+	}
+
+	for _, title := range []string{"lead", "alternate", "other"} {
+		if err := db.SaveArticleEmbedding(ids[title], "m", []byte{0, 0, 128, 63}); err != nil {
+			t.Fatalf("SaveArticleEmbedding: %v", err)
+		}
+	}
+	if err := db.SetArticleStoryID(ids["lead"], ids["lead"]); err != nil {
+		t.Fatalf("SetArticleStoryID: %v", err)
+	}
+	if err := db.SetArticleStoryID(ids["alternate"], ids["lead"]); err != nil {
+		t.Fatalf("SetArticleStoryID: %v", err)
+	}
+
+	pending, err = db.GetArticlesWithoutEmbedding("m", now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetArticlesWithoutEmbedding: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no articles without embedding, got %d", len(pending))
+	}
+
+	collapsed, err := db.GetArticlesCollapsingStories("", 0, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticlesCollapsingStories: %v", err)
+	}
+	if len(collapsed) != 2 {
+		t.Fatalf("expected 2 articles after collapsing, got %d", len(collapsed))
+	}
+	for _, a := range collapsed {
+		if a.ID == ids["alternate"] {
+			t.Fatalf("alternate article should be collapsed into its story")
+		}
+	}
+
+	counts, err := db.GetStoryAlternateCounts([]int64{ids["lead"], ids["other"]})
+	if err != nil {
+		t.Fatalf("GetStoryAlternateCounts: %v", err)
+	}
+	if counts[ids["lead"]] != 1 || counts[ids["other"]] != 0 {
+		t.Fatalf("unexpected alternate counts: %v", counts)
+	}
+
+	story, err := db.GetStoryArticleIDs(ids["lead"])
+	if err != nil {
+		t.Fatalf("GetStoryArticleIDs: %v", err)
+	}
+	if fmt.Sprint(story) != fmt.Sprint([]int64{ids["lead"], ids["alternate"]}) {
+		t.Fatalf("unexpected story articles: %v", story)
+	}
+
+	// Hiding the lead article shows its alternates again
+	if _, err := db.Exec(`UPDATE articles SET is_hidden = 1 WHERE id = ?`, ids["lead"]); err != nil {
+		t.Fatalf("hide lead: %v", err)
+	}
+	collapsed, err = db.GetArticlesCollapsingStories("", 0, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticlesCollapsingStories: %v", err)
+	}
+	if len(collapsed) != 2 {
+		t.Fatalf("expected alternate and other article, got %d articles", len(collapsed))
+	}
+
+	// Embeddings of deleted articles are cleaned up
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, ids["other"]); err != nil {
+		t.Fatalf("delete article: %v", err)
+	}
+	removed, err := db.CleanupOrphanedEmbeddings()
+	if err != nil {
+		t.Fatalf("CleanupOrphanedEmbeddings: %v", err)
+	}
+	if removed != 1 {
+		t.Fatalf("expected 1 orphaned embedding removed, got %d", removed)
+	}
+	if e, err := db.GetArticleEmbedding(ids["other"]); err != nil || e != nil {
+		t.Fatalf("expected no embedding for deleted article, got %v, %v", e, err)
+	}
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// Kinds of automatic feed changes
+const (
+	FeedChangeRedirect = "redirect" // The URL was updated after permanent redirects
+	FeedChangeGone     = "gone"     // The feed answered 410 Gone and is no longer scheduled
+	FeedChangeBackoff  = "backoff"  // Scheduled refreshes are spaced out after repeated failures
+)
+
+// RecordFeedChange stores an automatic change of a feed
+func (db *DB) RecordFeedChange(change *models.FeedChange) error {
+	db.WaitForReady()
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	res, err := db.Exec(`
+		INSERT INTO feed_changes (feed_id, kind, old_value, new_value, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, change.FeedID, change.Kind, change.OldValue, change.NewValue, change.CreatedAt.UTC().Truncate(time.Second))
+	if err != nil {
+		return fmt.Errorf("failed to record feed change: %w", err)
+	}
+	change.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// GetFeedChanges returns the automatic feed changes, newest first. Changes that were
+// undone or ended are only included with includeUndone.
+func (db *DB) GetFeedChanges(includeUndone bool) ([]models.FeedChange, error) {
+	db.WaitForReady()
+	query := `SELECT c.id, c.feed_id, COALESCE(f.title, ''), c.kind, COALESCE(c.old_value, ''),
+			COALESCE(c.new_value, ''), c.created_at, c.undone_at
+		FROM feed_changes c LEFT JOIN feeds f ON f.id = c.feed_id`
+	if !includeUndone {
+		query += ` WHERE c.undone_at IS NULL`
+	}
+	rows, err := db.Query(query + ` ORDER BY c.created_at DESC, c.id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.FeedChange
+	for rows.Next() {
+		var c models.FeedChange
+		var undoneAt sql.NullTime
+		if err := rows.Scan(&c.ID, &c.FeedID, &c.FeedTitle, &c.Kind, &c.OldValue, &c.NewValue, &c.CreatedAt, &undoneAt); err != nil {
+			return nil, fmt.Errorf("failed to scan feed change: %w", err)
+		}
+		if undoneAt.Valid {
+			c.UndoneAt = &undoneAt.Time
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get feed changes: %w", err)
+	}
+	return changes, nil
+}
+
+// GetFeedChange returns an automatic feed change by ID, or nil if it does not exist
+func (db *DB) GetFeedChange(id int64) (*models.FeedChange, error) {
+	db.WaitForReady()
+	var c models.FeedChange
+	var undoneAt sql.NullTime
+	err := db.QueryRow(`SELECT id, feed_id, kind, COALESCE(old_value, ''), COALESCE(new_value, ''), created_at, undone_at
+		FROM feed_changes WHERE id = ?`, id).Scan(&c.ID, &c.FeedID, &c.Kind, &c.OldValue, &c.NewValue, &c.CreatedAt, &undoneAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed change: %w", err)
+	}
+	if undoneAt.Valid {
+		c.UndoneAt = &undoneAt.Time
+	}
+	return &c, nil
+}
+
+// MarkFeedChangeUndone marks an automatic feed change as undone or ended
+func (db *DB) MarkFeedChangeUndone(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE feed_changes SET undone_at = ? WHERE id = ? AND undone_at IS NULL`,
+		time.Now().UTC().Truncate(time.Second), id)
+	if err != nil {
+		return fmt.Errorf("failed to mark feed change undone: %w", err)
+	}
+	return nil
+}
+
+// EndFeedChanges marks the open automatic changes of a kind of a feed as ended
+func (db *DB) EndFeedChanges(feedID int64, kind string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE feed_changes SET undone_at = ? WHERE feed_id = ? AND kind = ? AND undone_at IS NULL`,
+		time.Now().UTC().Truncate(time.Second), feedID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to end feed changes: %w", err)
+	}
+	return nil
+}
+
+// HasUndoneFeedChange reports whether the user undid an automatic change of a kind of a
+// feed to a value, so that the same change is not made again
+func (db *DB) HasUndoneFeedChange(feedID int64, kind, newValue string) (bool, error) {
+	db.WaitForReady()
+	var exists bool
+	err := db.QueryRow(`SELECT EXISTS(SELECT 1 FROM feed_changes
+		WHERE feed_id = ? AND kind = ? AND new_value = ? AND undone_at IS NOT NULL)`, feedID, kind, newValue).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check feed changes: %w", err)
+	}
+	return exists, nil
+}
+
+// UndoFeedChange reverts an automatic feed change and marks it as undone: the previous
+// URL of a redirect is restored, a gone feed is scheduled again and a backoff is lifted
+func (db *DB) UndoFeedChange(change *models.FeedChange) error {
+	var err error
+	switch change.Kind {
+	case FeedChangeRedirect:
+		err = db.UpdateFeedURL(change.FeedID, change.OldValue)
+	case FeedChangeGone:
+		err = db.SetFeedGone(change.FeedID, false)
+	case FeedChangeBackoff:
+		err = db.ResetFeedFailures(change.FeedID)
+	default:
+		return fmt.Errorf("unknown feed change kind: %s", change.Kind)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to undo feed change: %w", err)
+	}
+	return db.MarkFeedChangeUndone(change.ID)
+}
@@ -0,0 +1,86 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestFeedChanges(t *testing.T) {
+	db := setupTestDB(t)
+
+	id, _ := db.AddFeed(&models.Feed{Title: "moved", URL: "http://old/feed"})
+
+	if err := db.UpdateFeedURL(id, "http://new/feed"); err != nil {
+		t.Fatalf("UpdateFeedURL: %v", err)
+	}
+	redirect := &models.FeedChange{FeedID: id, Kind: database.FeedChangeRedirect, OldValue: "http://old/feed", NewValue: "http://new/feed"}
+	if err := db.RecordFeedChange(redirect); err != nil {
+		t.Fatalf("RecordFeedChange: %v", err)
+	}
+	if err := db.SetFeedGone(id, true); err != nil {
+		t.Fatalf("SetFeedGone: %v", err)
+	}
+	if err := db.RecordFeedChange(&models.FeedChange{FeedID: id, Kind: database.FeedChangeGone}); err != nil {
+		t.Fatalf("RecordFeedChange: %v", err)
+	}
+
+	changes, err := db.GetFeedChanges(false)
+	if err != nil || len(changes) != 2 || changes[0].FeedTitle != "moved" {
+		t.Fatalf("GetFeedChanges = %+v, %v", changes, err)
+	}
+
+	if err := db.UndoFeedChange(redirect); err != nil {
+		t.Fatalf("UndoFeedChange: %v", err)
+	}
+	feed, _ := db.GetFeedByID(id)
+	if feed.URL != "http://old/feed" || !feed.IsGone {
+		t.Errorf("feed after undo = %s, gone %v", feed.URL, feed.IsGone)
+	}
+	if undone, err := db.HasUndoneFeedChange(id, database.FeedChangeRedirect, "http://new/feed"); err != nil || !undone {
+		t.Errorf("HasUndoneFeedChange = %v, %v", undone, err)
+	}
+	if changes, _ := db.GetFeedChanges(false); len(changes) != 1 || changes[0].Kind != database.FeedChangeGone {
+		t.Errorf("open changes = %+v", changes)
+	}
+	if changes, _ := db.GetFeedChanges(true); len(changes) != 2 {
+		t.Errorf("all changes = %+v", changes)
+	}
+	if change, err := db.GetFeedChange(redirect.ID); err != nil || change.UndoneAt == nil {
+		t.Errorf("GetFeedChange = %+v, %v", change, err)
+	}
+	if change, err := db.GetFeedChange(12345); err != nil || change != nil {
+		t.Errorf("GetFeedChange(missing) = %+v, %v", change, err)
+	}
+}
+
+func TestFeedFailures(t *testing.T) {
+	db := setupTestDB(t)
+
+	id, _ := db.AddFeed(&models.Feed{Title: "failing", URL: "http://x/feed"})
+
+	for want := 1; want <= 3; want++ {
+		if failures, err := db.RecordFeedFailure(id); err != nil || failures != want {
+			t.Fatalf("RecordFeedFailure = %d, %v; want %d", failures, err, want)
+		}
+	}
+	until := time.Now().Add(time.Hour)
+	if err := db.SetFeedBackoff(id, until); err != nil {
+		t.Fatalf("SetFeedBackoff: %v", err)
+	}
+	feeds, err := db.GetFeeds()
+	if err != nil || len(feeds) != 1 || feeds[0].ConsecutiveFailures != 3 || feeds[0].BackoffUntil == nil ||
+		feeds[0].BackoffUntil.Sub(until).Abs() > time.Second {
+		t.Fatalf("GetFeeds = %+v, %v", feeds, err)
+	}
+
+	if err := db.ResetFeedFailures(id); err != nil {
+		t.Fatalf("ResetFeedFailures: %v", err)
+	}
+	feed, _ := db.GetFeedByID(id)
+	if feed.ConsecutiveFailures != 0 || feed.BackoffUntil != nil {
+		t.Errorf("feed after reset = %d failures, backoff %v", feed.ConsecutiveFailures, feed.BackoffUntil)
+	}
+}
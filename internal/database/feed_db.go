@@ -45,7 +45,7 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 			}
 		}
 
-		// 36 columns to insert (added is_freshrss_source and freshrss_stream_id)
+		// 41 columns to insert (added per-feed translation and summary policy)
 		query := `INSERT INTO feeds (
 			title, url, link, description, category, image_url, position,
 			script_path, hide_from_timeline, proxy_url, proxy_enabled, refresh_interval,
@@ -54,11 +54,12 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 			xpath_item_author, xpath_item_timestamp, xpath_item_time_format,
 			xpath_item_thumbnail, xpath_item_categories, xpath_item_uid,
 			article_view_mode, auto_expand_content,
+			translation_mode, translation_target_language, summary_provider, summary_length, pregenerate_on_fetch,
 			email_address, email_imap_server, email_imap_port,
 			email_username, email_password, email_folder, email_last_uid,
 			is_freshrss_source, freshrss_stream_id,
 			last_updated
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 		result, err := db.Exec(query,
 			feed.Title, feed.URL, feed.Link, feed.Description, feed.Category, feed.ImageURL, position,
 			feed.ScriptPath, feed.HideFromTimeline, feed.ProxyURL, feed.ProxyEnabled, feed.RefreshInterval,
@@ -67,6 +68,7 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 			feed.XPathItemAuthor, feed.XPathItemTimestamp, feed.XPathItemTimeFormat,
 			feed.XPathItemThumbnail, feed.XPathItemCategories, feed.XPathItemUid,
 			feed.ArticleViewMode, feed.AutoExpandContent,
+			feed.TranslationMode, feed.TranslationTargetLanguage, feed.SummaryProvider, feed.SummaryLength, feed.PregenerateOnFetch,
 			feed.EmailAddress, feed.EmailIMAPServer, feed.EmailIMAPPort,
 			feed.EmailUsername, feed.EmailPassword, feed.EmailFolder, feed.EmailLastUID,
 			feed.IsFreshRSSSource, feed.FreshRSSStreamID,
@@ -104,11 +106,12 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 			xpath_item_author, xpath_item_timestamp, xpath_item_time_format,
 			xpath_item_thumbnail, xpath_item_categories, xpath_item_uid,
 			article_view_mode, auto_expand_content,
+			translation_mode, translation_target_language, summary_provider, summary_length, pregenerate_on_fetch,
 			email_address, email_imap_server, email_imap_port,
 			email_username, email_password, email_folder, email_last_uid,
 			is_freshrss_source, freshrss_stream_id,
 			last_updated
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 		result, err := db.Exec(query,
 			feed.Title, feed.URL, feed.Link, feed.Description, feed.Category, feed.ImageURL, position,
 			feed.ScriptPath, feed.HideFromTimeline, feed.ProxyURL, feed.ProxyEnabled, feed.RefreshInterval,
@@ -117,6 +120,7 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 			feed.XPathItemAuthor, feed.XPathItemTimestamp, feed.XPathItemTimeFormat,
 			feed.XPathItemThumbnail, feed.XPathItemCategories, feed.XPathItemUid,
 			feed.ArticleViewMode, feed.AutoExpandContent,
+			feed.TranslationMode, feed.TranslationTargetLanguage, feed.SummaryProvider, feed.SummaryLength, feed.PregenerateOnFetch,
 			feed.EmailAddress, feed.EmailIMAPServer, feed.EmailIMAPPort,
 			feed.EmailUsername, feed.EmailPassword, feed.EmailFolder, feed.EmailLastUID,
 			feed.IsFreshRSSSource, feed.FreshRSSStreamID,
@@ -133,8 +137,8 @@ func (db *DB) AddFeed(feed *models.Feed) (int64, error) {
 
 	// Same URL and same source type - update existing feed
 	// (note: we don't update is_freshrss_source or freshrss_stream_id for existing feeds)
-	query := `UPDATE feeds SET title = ?, link = ?, description = ?, category = ?, image_url = ?, position = ?, script_path = ?, hide_from_timeline = ?, proxy_url = ?, proxy_enabled = ?, refresh_interval = ?, is_image_mode = ?, type = ?, xpath_item = ?, xpath_item_title = ?, xpath_item_content = ?, xpath_item_uri = ?, xpath_item_author = ?, xpath_item_timestamp = ?, xpath_item_time_format = ?, xpath_item_thumbnail = ?, xpath_item_categories = ?, xpath_item_uid = ?, article_view_mode = ?, auto_expand_content = ?, email_address = ?, email_imap_server = ?, email_imap_port = ?, email_username = ?, email_password = ?, email_folder = ?, email_last_uid = ?, last_updated = ? WHERE id = ?`
-	_, err = db.Exec(query, feed.Title, feed.Link, feed.Description, feed.Category, feed.ImageURL, feed.Position, feed.ScriptPath, feed.HideFromTimeline, feed.ProxyURL, feed.ProxyEnabled, feed.RefreshInterval, feed.IsImageMode, feed.Type, feed.XPathItem, feed.XPathItemTitle, feed.XPathItemContent, feed.XPathItemUri, feed.XPathItemAuthor, feed.XPathItemTimestamp, feed.XPathItemTimeFormat, feed.XPathItemThumbnail, feed.XPathItemCategories, feed.XPathItemUid, feed.ArticleViewMode, feed.AutoExpandContent, feed.EmailAddress, feed.EmailIMAPServer, feed.EmailIMAPPort, feed.EmailUsername, feed.EmailPassword, feed.EmailFolder, feed.EmailLastUID, time.Now(), existingID)
+	query := `UPDATE feeds SET title = ?, link = ?, description = ?, category = ?, image_url = ?, position = ?, script_path = ?, hide_from_timeline = ?, proxy_url = ?, proxy_enabled = ?, refresh_interval = ?, is_image_mode = ?, type = ?, xpath_item = ?, xpath_item_title = ?, xpath_item_content = ?, xpath_item_uri = ?, xpath_item_author = ?, xpath_item_timestamp = ?, xpath_item_time_format = ?, xpath_item_thumbnail = ?, xpath_item_categories = ?, xpath_item_uid = ?, article_view_mode = ?, auto_expand_content = ?, translation_mode = ?, translation_target_language = ?, summary_provider = ?, summary_length = ?, pregenerate_on_fetch = ?, email_address = ?, email_imap_server = ?, email_imap_port = ?, email_username = ?, email_password = ?, email_folder = ?, email_last_uid = ?, last_updated = ? WHERE id = ?`
+	_, err = db.Exec(query, feed.Title, feed.Link, feed.Description, feed.Category, feed.ImageURL, feed.Position, feed.ScriptPath, feed.HideFromTimeline, feed.ProxyURL, feed.ProxyEnabled, feed.RefreshInterval, feed.IsImageMode, feed.Type, feed.XPathItem, feed.XPathItemTitle, feed.XPathItemContent, feed.XPathItemUri, feed.XPathItemAuthor, feed.XPathItemTimestamp, feed.XPathItemTimeFormat, feed.XPathItemThumbnail, feed.XPathItemCategories, feed.XPathItemUid, feed.ArticleViewMode, feed.AutoExpandContent, feed.TranslationMode, feed.TranslationTargetLanguage, feed.SummaryProvider, feed.SummaryLength, feed.PregenerateOnFetch, feed.EmailAddress, feed.EmailIMAPServer, feed.EmailIMAPPort, feed.EmailUsername, feed.EmailPassword, feed.EmailFolder, feed.EmailLastUID, time.Now(), existingID)
 	return existingID, err
 }
 
@@ -146,6 +150,18 @@ func (db *DB) DeleteFeed(id int64) error {
 	if err != nil {
 		return err
 	}
+	_, err = db.Exec("DELETE FROM websub_subscriptions WHERE feed_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM feed_fetch_log WHERE feed_id = ?", id)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("DELETE FROM feed_changes WHERE feed_id = ?", id)
+	if err != nil {
+		return err
+	}
 	_, err = db.Exec("DELETE FROM feeds WHERE id = ?", id)
 	return err
 }
@@ -168,11 +184,16 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 			COALESCE(f.xpath_item_categories, ''), COALESCE(f.xpath_item_uid, ''),
 			COALESCE(f.article_view_mode, 'global'),
 			COALESCE(f.auto_expand_content, 'global'),
+			COALESCE(f.translation_mode, 'global'), COALESCE(f.translation_target_language, ''),
+			COALESCE(f.summary_provider, 'global'), COALESCE(f.summary_length, 'global'),
+			COALESCE(f.pregenerate_on_fetch, 0),
 			COALESCE(f.email_address, ''), COALESCE(f.email_imap_server, ''),
 			COALESCE(f.email_imap_port, 993), COALESCE(f.email_username, ''),
 			COALESCE(f.email_password, ''), COALESCE(f.email_folder, 'INBOX'),
 			COALESCE(f.email_last_uid, 0), COALESCE(f.is_freshrss_source, 0),
-			COALESCE(f.freshrss_stream_id, ''),
+			COALESCE(f.freshrss_stream_id, ''), COALESCE(f.is_paused, 0),
+			COALESCE(f.is_gone, 0), COALESCE(f.consecutive_failures, 0), f.backoff_until,
+			COALESCE(f.min_refresh_interval, 0), COALESCE(f.skip_hours, ''), COALESCE(f.skip_days, ''),
 			(SELECT MAX(a.published_at) FROM articles a WHERE a.feed_id = f.id) as latest_article_time,
 			CAST(COALESCE((
 				SELECT
@@ -203,8 +224,8 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 	var feeds []models.Feed
 	for rows.Next() {
 		var f models.Feed
-		var link, category, imageURL, lastError, scriptPath, proxyURL, feedType, xpathItem, xpathItemTitle, xpathItemContent, xpathItemUri, xpathItemAuthor, xpathItemTimestamp, xpathItemTimeFormat, xpathItemThumbnail, xpathItemCategories, xpathItemUid, articleViewMode, autoExpandContent, emailAddress, emailIMAPServer, emailUsername, emailPassword, emailFolder, freshRSSStreamID, latestArticleTimeStr sql.NullString
-		var lastUpdated sql.NullTime
+		var link, category, imageURL, lastError, scriptPath, proxyURL, feedType, xpathItem, xpathItemTitle, xpathItemContent, xpathItemUri, xpathItemAuthor, xpathItemTimestamp, xpathItemTimeFormat, xpathItemThumbnail, xpathItemCategories, xpathItemUid, articleViewMode, autoExpandContent, translationMode, translationTargetLanguage, summaryProvider, summaryLength, emailAddress, emailIMAPServer, emailUsername, emailPassword, emailFolder, freshRSSStreamID, latestArticleTimeStr sql.NullString
+		var lastUpdated, backoffUntil sql.NullTime
 		if err := rows.Scan(
 			&f.ID, &f.Title, &f.URL, &link, &f.Description, &category, &imageURL,
 			&f.Position, &lastUpdated, &lastError, &f.DiscoveryCompleted, &scriptPath,
@@ -212,9 +233,13 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 			&f.IsImageMode, &feedType, &xpathItem, &xpathItemTitle, &xpathItemContent,
 			&xpathItemUri, &xpathItemAuthor, &xpathItemTimestamp, &xpathItemTimeFormat,
 			&xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode,
-			&autoExpandContent, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort,
+			&autoExpandContent, &translationMode, &translationTargetLanguage,
+			&summaryProvider, &summaryLength, &f.PregenerateOnFetch,
+			&emailAddress, &emailIMAPServer, &f.EmailIMAPPort,
 			&emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID,
-			&f.IsFreshRSSSource, &freshRSSStreamID, &latestArticleTimeStr, &f.ArticlesPerMonth,
+			&f.IsFreshRSSSource, &freshRSSStreamID, &f.IsPaused,
+			&f.IsGone, &f.ConsecutiveFailures, &backoffUntil,
+			&f.MinRefreshInterval, &f.SkipHours, &f.SkipDays, &latestArticleTimeStr, &f.ArticlesPerMonth,
 		); err != nil {
 			return nil, err
 		}
@@ -230,6 +255,9 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 		} else {
 			f.LastUpdated = time.Time{}
 		}
+		if backoffUntil.Valid {
+			f.BackoffUntil = &backoffUntil.Time
+		}
 		f.LastError = lastError.String
 		f.ScriptPath = scriptPath.String
 		f.ProxyURL = proxyURL.String
@@ -252,6 +280,19 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 		if f.AutoExpandContent == "" {
 			f.AutoExpandContent = "global"
 		}
+		f.TranslationMode = translationMode.String
+		if f.TranslationMode == "" {
+			f.TranslationMode = "global"
+		}
+		f.TranslationTargetLanguage = translationTargetLanguage.String
+		f.SummaryProvider = summaryProvider.String
+		if f.SummaryProvider == "" {
+			f.SummaryProvider = "global"
+		}
+		f.SummaryLength = summaryLength.String
+		if f.SummaryLength == "" {
+			f.SummaryLength = "global"
+		}
 		f.EmailAddress = emailAddress.String
 		f.EmailIMAPServer = emailIMAPServer.String
 		f.EmailUsername = emailUsername.String
@@ -306,12 +347,12 @@ func (db *DB) GetFeeds() ([]models.Feed, error) {
 // GetFeedByID retrieves a specific feed by its ID.
 func (db *DB) GetFeedByID(id int64) (*models.Feed, error) {
 	db.WaitForReady()
-	row := db.QueryRow("SELECT id, title, url, link, description, category, image_url, COALESCE(position, 0), last_updated, last_error, COALESCE(discovery_completed, 0), COALESCE(script_path, ''), COALESCE(hide_from_timeline, 0), COALESCE(proxy_url, ''), COALESCE(proxy_enabled, 0), COALESCE(refresh_interval, 0), COALESCE(is_image_mode, 0), COALESCE(type, ''), COALESCE(xpath_item, ''), COALESCE(xpath_item_title, ''), COALESCE(xpath_item_content, ''), COALESCE(xpath_item_uri, ''), COALESCE(xpath_item_author, ''), COALESCE(xpath_item_timestamp, ''), COALESCE(xpath_item_time_format, ''), COALESCE(xpath_item_thumbnail, ''), COALESCE(xpath_item_categories, ''), COALESCE(xpath_item_uid, ''), COALESCE(article_view_mode, 'global'), COALESCE(auto_expand_content, 'global'), COALESCE(email_address, ''), COALESCE(email_imap_server, ''), COALESCE(email_imap_port, 993), COALESCE(email_username, ''), COALESCE(email_password, ''), COALESCE(email_folder, 'INBOX'), COALESCE(email_last_uid, 0), COALESCE(is_freshrss_source, 0), COALESCE(freshrss_stream_id, '') FROM feeds WHERE id = ?", id)
+	row := db.QueryRow("SELECT id, title, url, link, description, category, image_url, COALESCE(position, 0), last_updated, last_error, COALESCE(discovery_completed, 0), COALESCE(script_path, ''), COALESCE(hide_from_timeline, 0), COALESCE(proxy_url, ''), COALESCE(proxy_enabled, 0), COALESCE(refresh_interval, 0), COALESCE(is_image_mode, 0), COALESCE(type, ''), COALESCE(xpath_item, ''), COALESCE(xpath_item_title, ''), COALESCE(xpath_item_content, ''), COALESCE(xpath_item_uri, ''), COALESCE(xpath_item_author, ''), COALESCE(xpath_item_timestamp, ''), COALESCE(xpath_item_time_format, ''), COALESCE(xpath_item_thumbnail, ''), COALESCE(xpath_item_categories, ''), COALESCE(xpath_item_uid, ''), COALESCE(article_view_mode, 'global'), COALESCE(auto_expand_content, 'global'), COALESCE(translation_mode, 'global'), COALESCE(translation_target_language, ''), COALESCE(summary_provider, 'global'), COALESCE(summary_length, 'global'), COALESCE(pregenerate_on_fetch, 0), COALESCE(email_address, ''), COALESCE(email_imap_server, ''), COALESCE(email_imap_port, 993), COALESCE(email_username, ''), COALESCE(email_password, ''), COALESCE(email_folder, 'INBOX'), COALESCE(email_last_uid, 0), COALESCE(is_freshrss_source, 0), COALESCE(freshrss_stream_id, ''), COALESCE(is_paused, 0), COALESCE(is_gone, 0), COALESCE(consecutive_failures, 0), backoff_until, COALESCE(min_refresh_interval, 0), COALESCE(skip_hours, ''), COALESCE(skip_days, '') FROM feeds WHERE id = ?", id)
 
 	var f models.Feed
-	var link, category, imageURL, lastError, scriptPath, proxyURL, feedType, xpathItem, xpathItemTitle, xpathItemContent, xpathItemUri, xpathItemAuthor, xpathItemTimestamp, xpathItemTimeFormat, xpathItemThumbnail, xpathItemCategories, xpathItemUid, articleViewMode, autoExpandContent, emailAddress, emailIMAPServer, emailUsername, emailPassword, emailFolder, freshRSSStreamID sql.NullString
-	var lastUpdated sql.NullTime
-	if err := row.Scan(&f.ID, &f.Title, &f.URL, &link, &f.Description, &category, &imageURL, &f.Position, &lastUpdated, &lastError, &f.DiscoveryCompleted, &scriptPath, &f.HideFromTimeline, &proxyURL, &f.ProxyEnabled, &f.RefreshInterval, &f.IsImageMode, &feedType, &xpathItem, &xpathItemTitle, &xpathItemContent, &xpathItemUri, &xpathItemAuthor, &xpathItemTimestamp, &xpathItemTimeFormat, &xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode, &autoExpandContent, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort, &emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID, &f.IsFreshRSSSource, &freshRSSStreamID); err != nil {
+	var link, category, imageURL, lastError, scriptPath, proxyURL, feedType, xpathItem, xpathItemTitle, xpathItemContent, xpathItemUri, xpathItemAuthor, xpathItemTimestamp, xpathItemTimeFormat, xpathItemThumbnail, xpathItemCategories, xpathItemUid, articleViewMode, autoExpandContent, translationMode, translationTargetLanguage, summaryProvider, summaryLength, emailAddress, emailIMAPServer, emailUsername, emailPassword, emailFolder, freshRSSStreamID sql.NullString
+	var lastUpdated, backoffUntil sql.NullTime
+	if err := row.Scan(&f.ID, &f.Title, &f.URL, &link, &f.Description, &category, &imageURL, &f.Position, &lastUpdated, &lastError, &f.DiscoveryCompleted, &scriptPath, &f.HideFromTimeline, &proxyURL, &f.ProxyEnabled, &f.RefreshInterval, &f.IsImageMode, &feedType, &xpathItem, &xpathItemTitle, &xpathItemContent, &xpathItemUri, &xpathItemAuthor, &xpathItemTimestamp, &xpathItemTimeFormat, &xpathItemThumbnail, &xpathItemCategories, &xpathItemUid, &articleViewMode, &autoExpandContent, &translationMode, &translationTargetLanguage, &summaryProvider, &summaryLength, &f.PregenerateOnFetch, &emailAddress, &emailIMAPServer, &f.EmailIMAPPort, &emailUsername, &emailPassword, &emailFolder, &f.EmailLastUID, &f.IsFreshRSSSource, &freshRSSStreamID, &f.IsPaused, &f.IsGone, &f.ConsecutiveFailures, &backoffUntil, &f.MinRefreshInterval, &f.SkipHours, &f.SkipDays); err != nil {
 		return nil, err
 	}
 	f.Link = link.String
@@ -322,6 +363,9 @@ func (db *DB) GetFeedByID(id int64) (*models.Feed, error) {
 	} else {
 		f.LastUpdated = time.Time{}
 	}
+	if backoffUntil.Valid {
+		f.BackoffUntil = &backoffUntil.Time
+	}
 	f.LastError = lastError.String
 	f.ScriptPath = scriptPath.String
 	f.ProxyURL = proxyURL.String
@@ -344,6 +388,19 @@ func (db *DB) GetFeedByID(id int64) (*models.Feed, error) {
 	if f.AutoExpandContent == "" {
 		f.AutoExpandContent = "global"
 	}
+	f.TranslationMode = translationMode.String
+	if f.TranslationMode == "" {
+		f.TranslationMode = "global"
+	}
+	f.TranslationTargetLanguage = translationTargetLanguage.String
+	f.SummaryProvider = summaryProvider.String
+	if f.SummaryProvider == "" {
+		f.SummaryProvider = "global"
+	}
+	f.SummaryLength = summaryLength.String
+	if f.SummaryLength == "" {
+		f.SummaryLength = "global"
+	}
 	f.EmailAddress = emailAddress.String
 	f.EmailIMAPServer = emailIMAPServer.String
 	f.EmailUsername = emailUsername.String
@@ -394,6 +451,13 @@ func (db *DB) UpdateFeedWithPosition(id int64, title, url, category, scriptPath
 	return err
 }
 
+// UpdateFeedAIPolicy updates a feed's translation and summary overrides.
+func (db *DB) UpdateFeedAIPolicy(id int64, translationMode, translationTargetLanguage, summaryProvider, summaryLength string, pregenerateOnFetch bool) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET translation_mode = ?, translation_target_language = ?, summary_provider = ?, summary_length = ?, pregenerate_on_fetch = ? WHERE id = ?", translationMode, translationTargetLanguage, summaryProvider, summaryLength, pregenerateOnFetch, id)
+	return err
+}
+
 // UpdateFeedCategory updates a feed's category.
 func (db *DB) UpdateFeedCategory(id int64, category string) error {
 	db.WaitForReady()
@@ -436,6 +500,64 @@ func (db *DB) UpdateFeedLastUpdated(id int64) error {
 	return err
 }
 
+// SetFeedsPaused pauses or resumes the scheduled refreshes of feeds.
+func (db *DB) SetFeedsPaused(ids []int64, paused bool) error {
+	db.WaitForReady()
+	if len(ids) == 0 {
+		return nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := []interface{}{paused}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	_, err := db.Exec("UPDATE feeds SET is_paused = ? WHERE id IN ("+placeholders+")", args...)
+	return err
+}
+
+// UpdateFeedURL changes the URL of a feed, such as after it moved permanently.
+func (db *DB) UpdateFeedURL(id int64, url string) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET url = ? WHERE id = ?", url, id)
+	return err
+}
+
+// SetFeedGone marks a feed as gone, which stops its scheduled refreshes, or clears the mark.
+func (db *DB) SetFeedGone(id int64, gone bool) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET is_gone = ? WHERE id = ?", gone, id)
+	return err
+}
+
+// RecordFeedFailure counts a failed refresh of a feed and returns its consecutive failures.
+func (db *DB) RecordFeedFailure(id int64) (int, error) {
+	db.WaitForReady()
+	var failures int
+	err := db.QueryRow("UPDATE feeds SET consecutive_failures = COALESCE(consecutive_failures, 0) + 1 WHERE id = ? RETURNING consecutive_failures", id).Scan(&failures)
+	return failures, err
+}
+
+// SetFeedBackoff skips the scheduled refreshes of a feed until a time.
+func (db *DB) SetFeedBackoff(id int64, until time.Time) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET backoff_until = ? WHERE id = ?", until.UTC().Truncate(time.Second), id)
+	return err
+}
+
+// ResetFeedFailures clears the consecutive failures and the backoff of a feed.
+func (db *DB) ResetFeedFailures(id int64) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET consecutive_failures = 0, backoff_until = NULL WHERE id = ?", id)
+	return err
+}
+
+// UpdateFeedRefreshHints stores the refresh limits a feed asks for in its content.
+func (db *DB) UpdateFeedRefreshHints(id int64, minInterval int, skipHours, skipDays string) error {
+	db.WaitForReady()
+	_, err := db.Exec("UPDATE feeds SET min_refresh_interval = ?, skip_hours = ?, skip_days = ? WHERE id = ?", minInterval, skipHours, skipDays, id)
+	return err
+}
+
 // UpdateFeedEmailLastUID updates a newsletter feed's last processed email UID.
 func (db *DB) UpdateFeedEmailLastUID(id int64, lastUID int) error {
 	db.WaitForReady()
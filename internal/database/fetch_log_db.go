@@ -0,0 +1,83 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// FeedFetchRetentionDays is how long fetch attempts are kept in the fetch log
+const FeedFetchRetentionDays = 90
+
+const feedFetchColumns = `id, feed_id, fetched_at, COALESCE(reason, ''), COALESCE(duration_ms, 0), COALESCE(status_code, 0), COALESCE(bytes, 0), COALESCE(new_items, 0), COALESCE(error_class, ''), COALESCE(error, ''), COALESCE(redirect_url, '')`
+
+// RecordFeedFetch stores a fetch attempt of a feed in the fetch log
+func (db *DB) RecordFeedFetch(fetch *models.FeedFetch) error {
+	db.WaitForReady()
+	if fetch.FetchedAt.IsZero() {
+		fetch.FetchedAt = time.Now()
+	}
+	res, err := db.Exec(`
+		INSERT INTO feed_fetch_log (feed_id, fetched_at, reason, duration_ms, status_code, bytes, new_items, error_class, error, redirect_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, fetch.FeedID, fetch.FetchedAt.UTC().Truncate(time.Second), fetch.Reason, fetch.DurationMs, fetch.StatusCode,
+		fetch.Bytes, fetch.NewItems, fetch.ErrorClass, fetch.Error, fetch.RedirectURL)
+	if err != nil {
+		return fmt.Errorf("failed to record feed fetch: %w", err)
+	}
+	fetch.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// GetFeedFetches returns the latest fetch attempts of a feed, newest first
+func (db *DB) GetFeedFetches(feedID int64, limit int) ([]models.FeedFetch, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT `+feedFetchColumns+` FROM feed_fetch_log
+		WHERE feed_id = ? ORDER BY fetched_at DESC, id DESC LIMIT ?`, feedID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed fetches: %w", err)
+	}
+	defer rows.Close()
+	return scanFeedFetches(rows)
+}
+
+// GetFeedFetchesSince returns the fetch attempts of all feeds since a time, oldest first
+func (db *DB) GetFeedFetchesSince(since time.Time) ([]models.FeedFetch, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT `+feedFetchColumns+` FROM feed_fetch_log
+		WHERE fetched_at >= ? ORDER BY fetched_at ASC, id ASC`, since.UTC().Truncate(time.Second))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed fetches: %w", err)
+	}
+	defer rows.Close()
+	return scanFeedFetches(rows)
+}
+
+// CleanupOldFeedFetches removes fetch attempts older than maxAgeDays from the fetch log
+func (db *DB) CleanupOldFeedFetches(maxAgeDays int) (int64, error) {
+	db.WaitForReady()
+	cutoff := time.Now().AddDate(0, 0, -maxAgeDays).UTC().Truncate(time.Second)
+	res, err := db.Exec(`DELETE FROM feed_fetch_log WHERE fetched_at < ?`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean up feed fetches: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func scanFeedFetches(rows *sql.Rows) ([]models.FeedFetch, error) {
+	var fetches []models.FeedFetch
+	for rows.Next() {
+		var f models.FeedFetch
+		if err := rows.Scan(&f.ID, &f.FeedID, &f.FetchedAt, &f.Reason, &f.DurationMs, &f.StatusCode,
+			&f.Bytes, &f.NewItems, &f.ErrorClass, &f.Error, &f.RedirectURL); err != nil {
+			return nil, fmt.Errorf("failed to scan feed fetch: %w", err)
+		}
+		fetches = append(fetches, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get feed fetches: %w", err)
+	}
+	return fetches, nil
+}
@@ -0,0 +1,100 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestFeedFetchLog(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+
+	now := time.Now()
+	for _, fetch := range []models.FeedFetch{
+		{FeedID: feedID, FetchedAt: now.AddDate(0, 0, -100), Reason: "scheduled_global", ErrorClass: "timeout", Error: "context deadline exceeded"},
+		{FeedID: feedID, FetchedAt: now.Add(-2 * time.Hour), Reason: "scheduled_global", DurationMs: 120, StatusCode: 200, Bytes: 2048, NewItems: 3},
+		{FeedID: feedID, FetchedAt: now.Add(-time.Hour), Reason: "manual_refresh", DurationMs: 80, StatusCode: 503, ErrorClass: "http_5xx", Error: "HTTP 503"},
+	} {
+		if err := db.RecordFeedFetch(&fetch); err != nil {
+			t.Fatalf("RecordFeedFetch: %v", err)
+		}
+	}
+
+	latest, err := db.GetFeedFetches(feedID, 2)
+	if err != nil || len(latest) != 2 {
+		t.Fatalf("GetFeedFetches = %+v, %v", latest, err)
+	}
+	if latest[0].StatusCode != 503 || latest[0].ErrorClass != "http_5xx" || latest[1].NewItems != 3 || latest[1].Bytes != 2048 {
+		t.Errorf("unexpected fetches %+v", latest)
+	}
+
+	since, err := db.GetFeedFetchesSince(now.AddDate(0, 0, -30))
+	if err != nil || len(since) != 2 || since[0].Reason != "scheduled_global" {
+		t.Fatalf("GetFeedFetchesSince = %+v, %v", since, err)
+	}
+
+	if n, err := db.CleanupOldFeedFetches(90); err != nil || n != 1 {
+		t.Errorf("CleanupOldFeedFetches = %d, %v", n, err)
+	}
+
+	if err := db.DeleteFeed(feedID); err != nil {
+		t.Fatalf("DeleteFeed: %v", err)
+	}
+	if fetches, err := db.GetFeedFetches(feedID, 10); err != nil || len(fetches) != 0 {
+		t.Errorf("fetches of deleted feed = %+v, %v", fetches, err)
+	}
+}
+
+func TestSetFeedsPaused(t *testing.T) {
+	db := setupTestDB(t)
+
+	id1, _ := db.AddFeed(&models.Feed{Title: "a", URL: "http://x/1"})
+	id2, _ := db.AddFeed(&models.Feed{Title: "b", URL: "http://x/2"})
+
+	if err := db.SetFeedsPaused([]int64{id1, id2}, true); err != nil {
+		t.Fatalf("SetFeedsPaused: %v", err)
+	}
+	if err := db.SetFeedsPaused([]int64{id2}, false); err != nil {
+		t.Fatalf("SetFeedsPaused: %v", err)
+	}
+
+	f1, _ := db.GetFeedByID(id1)
+	f2, _ := db.GetFeedByID(id2)
+	if !f1.IsPaused || f2.IsPaused {
+		t.Errorf("paused = %v, %v; want true, false", f1.IsPaused, f2.IsPaused)
+	}
+	feeds, err := db.GetFeeds()
+	if err != nil || len(feeds) != 2 || feeds[0].IsPaused == feeds[1].IsPaused {
+		t.Errorf("GetFeeds = %+v, %v", feeds, err)
+	}
+}
+
+func TestSaveNewArticles(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+
+	published := time.Now().Add(-time.Hour)
+	articles := []*models.Article{
+		{FeedID: feedID, Title: "One", URL: "https://example.com/1", PublishedAt: published, HasValidPublishedTime: true},
+		{FeedID: feedID, Title: "Two", URL: "https://example.com/2", PublishedAt: published, HasValidPublishedTime: true},
+	}
+	if n, err := db.SaveNewArticles(context.Background(), articles); err != nil || n != 2 {
+		t.Fatalf("first SaveNewArticles = %d, %v", n, err)
+	}
+
+	articles = append(articles, &models.Article{FeedID: feedID, Title: "Three", URL: "https://example.com/3", PublishedAt: published, HasValidPublishedTime: true})
+	if n, err := db.SaveNewArticles(context.Background(), articles); err != nil || n != 1 {
+		t.Errorf("second SaveNewArticles = %d, %v; want 1 new", n, err)
+	}
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// SaveImportedArticle saves an article imported from another reader. When an
+// article with the same URL already exists, its favorite, read later and read
+// flags are merged into it instead. Returns the article ID and whether a new
+// article was created.
+func (db *DB) SaveImportedArticle(article *models.Article) (int64, bool, error) {
+	db.WaitForReady()
+
+	var existingID int64
+	err := db.QueryRow("SELECT id FROM articles WHERE url = ? LIMIT 1", article.URL).Scan(&existingID)
+	if err == nil {
+		_, err = db.Exec(`UPDATE articles SET
+			is_favorite = MAX(is_favorite, ?),
+			is_read_later = MAX(is_read_later, ?),
+			is_read = MAX(is_read, ?)
+			WHERE id = ?`, article.IsFavorite, article.IsReadLater, article.IsRead, existingID)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to update imported article: %w", err)
+		}
+		return existingID, false, nil
+	} else if err != sql.ErrNoRows {
+		return 0, false, fmt.Errorf("failed to look up imported article: %w", err)
+	}
+
+	if err := db.SaveArticle(article); err != nil {
+		return 0, false, fmt.Errorf("failed to save imported article: %w", err)
+	}
+
+	// The insert is ignored when the unique ID already exists
+	uniqueID := utils.GenerateArticleUniqueID(article.Title, article.FeedID, article.PublishedAt, article.HasValidPublishedTime)
+	var id int64
+	if err := db.QueryRow("SELECT id FROM articles WHERE unique_id = ?", uniqueID).Scan(&id); err != nil {
+		return 0, false, fmt.Errorf("failed to look up imported article: %w", err)
+	}
+	return id, true, nil
+}
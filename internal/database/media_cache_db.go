@@ -0,0 +1,122 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// MediaCacheEntry represents a file in the media cache
+type MediaCacheEntry struct {
+	URLHash     string
+	Width       int // Width of a thumbnail variant (0 = original)
+	FileName    string
+	URL         string
+	ContentType string
+	Size        int64
+	LastAccess  time.Time
+	CreatedAt   time.Time
+	Pinned      bool // Kept for offline reading, not evicted
+}
+
+// GetMediaCacheEntry retrieves a cached media file by URL hash and variant width.
+// Returns nil if the file is not in the cache.
+func (db *DB) GetMediaCacheEntry(urlHash string, width int) (*MediaCacheEntry, error) {
+	db.WaitForReady()
+	var e MediaCacheEntry
+	var lastAccess, createdAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT url_hash, width, file_name, COALESCE(url, ''), COALESCE(content_type, ''), size, last_access, created_at
+		FROM media_cache
+		WHERE url_hash = ? AND width = ?
+	`, urlHash, width).Scan(&e.URLHash, &e.Width, &e.FileName, &e.URL, &e.ContentType, &e.Size, &lastAccess, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media cache entry: %w", err)
+	}
+	e.LastAccess = lastAccess.Time
+	e.CreatedAt = createdAt.Time
+	return &e, nil
+}
+
+// SaveMediaCacheEntry adds a file to the media cache index, replacing any previous entry.
+func (db *DB) SaveMediaCacheEntry(e MediaCacheEntry) error {
+	db.WaitForReady()
+	now := time.Now()
+	if e.LastAccess.IsZero() {
+		e.LastAccess = now
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = now
+	}
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO media_cache (url_hash, width, file_name, url, content_type, size, last_access, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.URLHash, e.Width, e.FileName, e.URL, e.ContentType, e.Size, e.LastAccess, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save media cache entry: %w", err)
+	}
+	return nil
+}
+
+// TouchMediaCacheEntry records an access to a cached media file
+func (db *DB) TouchMediaCacheEntry(urlHash string, width int) error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE media_cache SET last_access = ? WHERE url_hash = ? AND width = ?`, time.Now(), urlHash, width)
+	if err != nil {
+		return fmt.Errorf("failed to update media cache entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteMediaCacheEntry removes a file from the media cache index
+func (db *DB) DeleteMediaCacheEntry(fileName string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`DELETE FROM media_cache WHERE file_name = ?`, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to delete media cache entry: %w", err)
+	}
+	return nil
+}
+
+// GetMediaCacheEntries returns all cached media files, least recently used first
+func (db *DB) GetMediaCacheEntries() ([]MediaCacheEntry, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT url_hash, width, file_name, COALESCE(url, ''), COALESCE(content_type, ''), size, last_access, created_at,
+			EXISTS (SELECT 1 FROM offline_media o WHERE o.url_hash = media_cache.url_hash)
+		FROM media_cache
+		ORDER BY last_access ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]MediaCacheEntry, 0)
+	for rows.Next() {
+		var e MediaCacheEntry
+		var lastAccess, createdAt sql.NullTime
+		if err := rows.Scan(&e.URLHash, &e.Width, &e.FileName, &e.URL, &e.ContentType, &e.Size, &lastAccess, &createdAt, &e.Pinned); err != nil {
+			return nil, fmt.Errorf("failed to scan media cache entry: %w", err)
+		}
+		e.LastAccess = lastAccess.Time
+		e.CreatedAt = createdAt.Time
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetMediaCacheStats returns the number and total size in bytes of cached media files
+func (db *DB) GetMediaCacheStats() (int, int64, error) {
+	db.WaitForReady()
+	var count int
+	var size int64
+	err := db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(size), 0) FROM media_cache`).Scan(&count, &size)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get media cache stats: %w", err)
+	}
+	return count, size, nil
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// OfflineScope selects the articles that are downloaded for offline reading
+type OfflineScope struct {
+	ReadLater  bool
+	Favorites  bool
+	Unread     bool
+	Categories []string // Limits unread articles to these categories and their subcategories (empty = all)
+}
+
+// GetOfflineCandidates returns the articles in scope for offline reading, in the
+// order they should be downloaded: read later, favorites, then unread articles,
+// newest first within each group.
+func (db *DB) GetOfflineCandidates(scope OfflineScope, limit int) ([]models.Article, error) {
+	db.WaitForReady()
+
+	var conditions []string
+	var args []interface{}
+	if scope.ReadLater {
+		conditions = append(conditions, "a.is_read_later = 1")
+	}
+	if scope.Favorites {
+		conditions = append(conditions, "a.is_favorite = 1")
+	}
+	if scope.Unread {
+		if len(scope.Categories) == 0 {
+			conditions = append(conditions, "a.is_read = 0")
+		} else {
+			categoryConditions := make([]string, 0, len(scope.Categories))
+			for _, category := range scope.Categories {
+				categoryConditions = append(categoryConditions, "f.category = ? OR f.category LIKE ?")
+				args = append(args, category, category+"/%")
+			}
+			conditions = append(conditions, "(a.is_read = 0 AND ("+strings.Join(categoryConditions, " OR ")+"))")
+		}
+	}
+	if len(conditions) == 0 {
+		return []models.Article{}, nil
+	}
+
+	query := `
+		SELECT a.id, a.feed_id, a.title, a.url, a.image_url, a.published_at, a.is_read, a.is_favorite, a.is_read_later
+		FROM articles a
+		JOIN feeds f ON a.feed_id = f.id
+		WHERE a.is_hidden = 0 AND a.url != '' AND (` + strings.Join(conditions, " OR ") + `)
+		ORDER BY a.is_read_later DESC, a.is_favorite DESC, a.published_at DESC
+		LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offline candidates: %w", err)
+	}
+	defer rows.Close()
+
+	articles := make([]models.Article, 0)
+	for rows.Next() {
+		var a models.Article
+		var imageURL sql.NullString
+		var publishedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.FeedID, &a.Title, &a.URL, &imageURL, &publishedAt, &a.IsRead, &a.IsFavorite, &a.IsReadLater); err != nil {
+			return nil, fmt.Errorf("failed to scan article: %w", err)
+		}
+		a.ImageURL = imageURL.String
+		a.PublishedAt = publishedAt.Time
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetOfflineContent retrieves the content downloaded for offline reading of an article
+func (db *DB) GetOfflineContent(articleID int64) (string, bool, error) {
+	db.WaitForReady()
+	var content string
+	err := db.QueryRow(`SELECT content FROM offline_articles WHERE article_id = ?`, articleID).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get offline content: %w", err)
+	}
+	return content, true, nil
+}
+
+// SaveOfflineArticle stores the content of an article for offline reading and pins
+// its images, identified by media cache URL hash, in the media cache.
+func (db *DB) SaveOfflineArticle(articleID int64, content string, mediaHashes []string, mediaSize int64) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT OR REPLACE INTO offline_articles (article_id, content, content_size, media_size, fetched_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, articleID, content, len(content), mediaSize, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save offline article: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM offline_media WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to save offline media: %w", err)
+	}
+	for _, hash := range mediaHashes {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO offline_media (article_id, url_hash) VALUES (?, ?)`, articleID, hash); err != nil {
+			return fmt.Errorf("failed to save offline media: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// DeleteOfflineArticle removes the offline copy of an article and unpins its images
+func (db *DB) DeleteOfflineArticle(articleID int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM offline_media WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to delete offline media: %w", err)
+	}
+	if _, err := db.Exec(`DELETE FROM offline_articles WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to delete offline article: %w", err)
+	}
+	return nil
+}
+
+// GetOfflineArticleIDs returns the IDs of the articles available offline
+func (db *DB) GetOfflineArticleIDs() ([]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT article_id FROM offline_articles`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get offline articles: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan offline article: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// GetOfflineStats returns the number of articles available offline and the size
+// in bytes of their content and images
+func (db *DB) GetOfflineStats() (int, int64, error) {
+	db.WaitForReady()
+	var count int
+	var size int64
+	err := db.QueryRow(`SELECT COUNT(*), COALESCE(SUM(content_size + media_size), 0) FROM offline_articles`).Scan(&count, &size)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get offline stats: %w", err)
+	}
+	return count, size, nil
+}
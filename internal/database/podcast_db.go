@@ -0,0 +1,361 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// Podcast download statuses
+const (
+	PodcastDownloadQueued      = "queued"
+	PodcastDownloadDownloading = "downloading"
+	PodcastDownloadCompleted   = "completed"
+	PodcastDownloadFailed      = "failed"
+)
+
+// SavePodcastEpisode stores the podcast metadata of an article, replacing any previous metadata
+func (db *DB) SavePodcastEpisode(e models.PodcastEpisode) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO podcast_episodes (article_id, mime_type, file_size, duration, episode, season, episode_type, explicit, image_url, chapters_url, transcript_url, transcript_type)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, e.ArticleID, e.MimeType, e.FileSize, e.Duration, e.Episode, e.Season, e.EpisodeType, e.Explicit, e.ImageURL, e.ChaptersURL, e.TranscriptURL, e.TranscriptType)
+	if err != nil {
+		return fmt.Errorf("failed to save podcast episode: %w", err)
+	}
+	return nil
+}
+
+// GetPodcastEpisode retrieves the podcast metadata of an article.
+// Returns nil if the article has no podcast metadata.
+func (db *DB) GetPodcastEpisode(articleID int64) (*models.PodcastEpisode, error) {
+	db.WaitForReady()
+	var e models.PodcastEpisode
+	err := db.QueryRow(`
+		SELECT article_id, COALESCE(mime_type, ''), COALESCE(file_size, 0), COALESCE(duration, 0), COALESCE(episode, 0), COALESCE(season, 0),
+			COALESCE(episode_type, ''), COALESCE(explicit, 0), COALESCE(image_url, ''), COALESCE(chapters_url, ''), COALESCE(transcript_url, ''), COALESCE(transcript_type, '')
+		FROM podcast_episodes
+		WHERE article_id = ?
+	`, articleID).Scan(&e.ArticleID, &e.MimeType, &e.FileSize, &e.Duration, &e.Episode, &e.Season, &e.EpisodeType, &e.Explicit, &e.ImageURL, &e.ChaptersURL, &e.TranscriptURL, &e.TranscriptType)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast episode: %w", err)
+	}
+	return &e, nil
+}
+
+// SavePlaybackPosition stores the playback position of a podcast episode
+func (db *DB) SavePlaybackPosition(articleID int64, position float64, completed bool) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO podcast_playback (article_id, position, completed, updated_at)
+		VALUES (?, ?, ?, ?)
+	`, articleID, position, completed, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save playback position: %w", err)
+	}
+	return nil
+}
+
+// GetPlaybackPosition retrieves the playback position of a podcast episode.
+// Returns nil if the episode was never played.
+func (db *DB) GetPlaybackPosition(articleID int64) (*models.PlaybackPosition, error) {
+	db.WaitForReady()
+	var p models.PlaybackPosition
+	var updatedAt sql.NullTime
+	err := db.QueryRow(`
+		SELECT article_id, position, COALESCE(completed, 0), updated_at
+		FROM podcast_playback
+		WHERE article_id = ?
+	`, articleID).Scan(&p.ArticleID, &p.Position, &p.Completed, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playback position: %w", err)
+	}
+	p.UpdatedAt = updatedAt.Time
+	return &p, nil
+}
+
+// QueuePodcastDownload queues an episode for download. Failed downloads are
+// queued again; queued, running and completed downloads are left unchanged.
+func (db *DB) QueuePodcastDownload(articleID int64, url string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR IGNORE INTO podcast_downloads (article_id, url, status, created_at)
+		VALUES (?, ?, ?, ?)
+	`, articleID, url, PodcastDownloadQueued, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to queue podcast download: %w", err)
+	}
+	_, err = db.Exec(`
+		UPDATE podcast_downloads SET status = ?, error = '' WHERE article_id = ? AND status = ?
+	`, PodcastDownloadQueued, articleID, PodcastDownloadFailed)
+	if err != nil {
+		return fmt.Errorf("failed to queue podcast download: %w", err)
+	}
+	return nil
+}
+
+const podcastDownloadColumns = `article_id, url, COALESCE(file_name, ''), status, COALESCE(total_size, 0), COALESCE(downloaded_size, 0), COALESCE(error, ''), created_at, completed_at`
+
+// scanPodcastDownload scans a row selected with podcastDownloadColumns
+func scanPodcastDownload(scanner interface{ Scan(...interface{}) error }) (models.PodcastDownload, error) {
+	var d models.PodcastDownload
+	var createdAt, completedAt sql.NullTime
+	err := scanner.Scan(&d.ArticleID, &d.URL, &d.FileName, &d.Status, &d.TotalSize, &d.DownloadedSize, &d.Error, &createdAt, &completedAt)
+	if err != nil {
+		return d, err
+	}
+	d.CreatedAt = createdAt.Time
+	if completedAt.Valid {
+		d.CompletedAt = &completedAt.Time
+	}
+	return d, nil
+}
+
+// GetPodcastDownload retrieves the download of an episode.
+// Returns nil if the episode was not downloaded.
+func (db *DB) GetPodcastDownload(articleID int64) (*models.PodcastDownload, error) {
+	db.WaitForReady()
+	row := db.QueryRow(`SELECT `+podcastDownloadColumns+` FROM podcast_downloads WHERE article_id = ?`, articleID)
+	d, err := scanPodcastDownload(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast download: %w", err)
+	}
+	return &d, nil
+}
+
+// GetNextPodcastDownload returns the download that was queued first.
+// Returns nil if no download is queued.
+func (db *DB) GetNextPodcastDownload() (*models.PodcastDownload, error) {
+	db.WaitForReady()
+	row := db.QueryRow(`
+		SELECT `+podcastDownloadColumns+` FROM podcast_downloads
+		WHERE status = ?
+		ORDER BY created_at ASC, article_id ASC
+		LIMIT 1
+	`, PodcastDownloadQueued)
+	d, err := scanPodcastDownload(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast download: %w", err)
+	}
+	return &d, nil
+}
+
+// GetPodcastDownloads returns all downloads, oldest first
+func (db *DB) GetPodcastDownloads() ([]models.PodcastDownload, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT ` + podcastDownloadColumns + ` FROM podcast_downloads ORDER BY created_at ASC, article_id ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast downloads: %w", err)
+	}
+	defer rows.Close()
+
+	downloads := make([]models.PodcastDownload, 0)
+	for rows.Next() {
+		d, err := scanPodcastDownload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan podcast download: %w", err)
+		}
+		downloads = append(downloads, d)
+	}
+	return downloads, rows.Err()
+}
+
+// GetOrphanedPodcastDownloads returns the downloads of deleted articles
+func (db *DB) GetOrphanedPodcastDownloads() ([]models.PodcastDownload, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT ` + podcastDownloadColumns + ` FROM podcast_downloads WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast downloads: %w", err)
+	}
+	defer rows.Close()
+
+	downloads := make([]models.PodcastDownload, 0)
+	for rows.Next() {
+		d, err := scanPodcastDownload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan podcast download: %w", err)
+		}
+		downloads = append(downloads, d)
+	}
+	return downloads, rows.Err()
+}
+
+// GetEvictablePodcastDownloads returns the completed downloads of played
+// episodes and of deleted articles, least recently completed first. These are
+// removed first when the download storage limit is reached.
+func (db *DB) GetEvictablePodcastDownloads() ([]models.PodcastDownload, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT `+podcastDownloadColumns+` FROM podcast_downloads d
+		WHERE d.status = ?
+		AND (
+			d.article_id IN (SELECT article_id FROM podcast_playback WHERE completed = 1)
+			OR d.article_id NOT IN (SELECT id FROM articles)
+		)
+		ORDER BY d.completed_at ASC
+	`, PodcastDownloadCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast downloads: %w", err)
+	}
+	defer rows.Close()
+
+	downloads := make([]models.PodcastDownload, 0)
+	for rows.Next() {
+		d, err := scanPodcastDownload(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan podcast download: %w", err)
+		}
+		downloads = append(downloads, d)
+	}
+	return downloads, rows.Err()
+}
+
+// UpdatePodcastDownload stores the progress of a download
+func (db *DB) UpdatePodcastDownload(d models.PodcastDownload) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE podcast_downloads
+		SET file_name = ?, status = ?, total_size = ?, downloaded_size = ?, error = ?, completed_at = ?
+		WHERE article_id = ?
+	`, d.FileName, d.Status, d.TotalSize, d.DownloadedSize, d.Error, d.CompletedAt, d.ArticleID)
+	if err != nil {
+		return fmt.Errorf("failed to update podcast download: %w", err)
+	}
+	return nil
+}
+
+// DeletePodcastDownload removes the download of an episode
+func (db *DB) DeletePodcastDownload(articleID int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM podcast_downloads WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to delete podcast download: %w", err)
+	}
+	return nil
+}
+
+// ResetInterruptedPodcastDownloads queues downloads again that were running when
+// the application stopped, so they are resumed
+func (db *DB) ResetInterruptedPodcastDownloads() error {
+	db.WaitForReady()
+	_, err := db.Exec(`UPDATE podcast_downloads SET status = ? WHERE status = ?`, PodcastDownloadQueued, PodcastDownloadDownloading)
+	if err != nil {
+		return fmt.Errorf("failed to reset podcast downloads: %w", err)
+	}
+	return nil
+}
+
+// GetPodcastDownloadsSize returns the size in bytes of the downloaded episodes
+func (db *DB) GetPodcastDownloadsSize() (int64, error) {
+	db.WaitForReady()
+	var size int64
+	err := db.QueryRow(`SELECT COALESCE(SUM(downloaded_size), 0) FROM podcast_downloads`).Scan(&size)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get podcast downloads size: %w", err)
+	}
+	return size, nil
+}
+
+// GetPodcastQueue returns the article IDs of the Up Next queue in playback order
+func (db *DB) GetPodcastQueue() ([]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT q.article_id FROM podcast_queue q
+		JOIN articles a ON a.id = q.article_id
+		ORDER BY q.position ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get podcast queue: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan podcast queue: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// AddToPodcastQueue adds an episode to the Up Next queue, at the start if next
+// is set and at the end otherwise. An episode already in the queue is moved.
+func (db *DB) AddToPodcastQueue(articleID int64, next bool) error {
+	db.WaitForReady()
+	query := `INSERT OR REPLACE INTO podcast_queue (article_id, position)
+		SELECT ?, COALESCE(MAX(position), 0) + 1 FROM podcast_queue WHERE article_id != ?`
+	if next {
+		query = `INSERT OR REPLACE INTO podcast_queue (article_id, position)
+		SELECT ?, COALESCE(MIN(position), 0) - 1 FROM podcast_queue WHERE article_id != ?`
+	}
+	if _, err := db.Exec(query, articleID, articleID); err != nil {
+		return fmt.Errorf("failed to add to podcast queue: %w", err)
+	}
+	return nil
+}
+
+// RemoveFromPodcastQueue removes an episode from the Up Next queue
+func (db *DB) RemoveFromPodcastQueue(articleID int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM podcast_queue WHERE article_id = ?`, articleID); err != nil {
+		return fmt.Errorf("failed to remove from podcast queue: %w", err)
+	}
+	return nil
+}
+
+// ReorderPodcastQueue moves the given episodes to the start of the Up Next queue,
+// in the given order. Episodes that are not listed keep their order after them.
+func (db *DB) ReorderPodcastQueue(articleIDs []int64) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Make room for the listed episodes before all others
+	var minPosition int
+	if err := tx.QueryRow(`SELECT COALESCE(MIN(position), 0) FROM podcast_queue`).Scan(&minPosition); err != nil {
+		return fmt.Errorf("failed to reorder podcast queue: %w", err)
+	}
+	for i, id := range articleIDs {
+		position := minPosition - len(articleIDs) + i
+		if _, err := tx.Exec(`UPDATE podcast_queue SET position = ? WHERE article_id = ?`, position, id); err != nil {
+			return fmt.Errorf("failed to reorder podcast queue: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// CleanupOrphanedPodcastData removes podcast metadata, playback positions and
+// queue entries whose articles no longer exist. Downloads are removed by the
+// download manager, which also deletes their files.
+func (db *DB) CleanupOrphanedPodcastData() (int64, error) {
+	db.WaitForReady()
+	var total int64
+	for _, table := range []string{"podcast_episodes", "podcast_playback", "podcast_queue"} {
+		result, err := db.Exec(`DELETE FROM ` + table + ` WHERE article_id NOT IN (SELECT id FROM articles)`)
+		if err != nil {
+			return total, err
+		}
+		count, _ := result.RowsAffected()
+		total += count
+	}
+	return total, nil
+}
@@ -0,0 +1,83 @@
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestPodcastQueueAndPlayback(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+	ids := make([]int64, 3)
+	for i := range ids {
+		url := fmt.Sprintf("https://example.com/episode%d", i)
+		article := &models.Article{FeedID: feedID, Title: fmt.Sprintf("Episode %d", i), URL: url, AudioURL: url + ".mp3", PublishedAt: time.Now()}
+		if err := db.SaveArticles(context.Background(), []*models.Article{article}); err != nil {
+			t.Fatalf("SaveArticles: %v", err)
+		}
+		if err := db.QueryRow(`SELECT id FROM articles WHERE url = ?`, url).Scan(&ids[i]); err != nil {
+			t.Fatalf("select article id: %v", err)
+		}
+	}
+
+	// Episodes are added at the end unless played next
+	_ = db.AddToPodcastQueue(ids[0], false)
+	_ = db.AddToPodcastQueue(ids[1], false)
+	_ = db.AddToPodcastQueue(ids[2], true)
+	assertQueue(t, db.GetPodcastQueue, []int64{ids[2], ids[0], ids[1]})
+
+	// Adding an episode again moves it
+	_ = db.AddToPodcastQueue(ids[2], false)
+	assertQueue(t, db.GetPodcastQueue, []int64{ids[0], ids[1], ids[2]})
+
+	if err := db.ReorderPodcastQueue([]int64{ids[1]}); err != nil {
+		t.Fatalf("ReorderPodcastQueue: %v", err)
+	}
+	assertQueue(t, db.GetPodcastQueue, []int64{ids[1], ids[0], ids[2]})
+
+	_ = db.RemoveFromPodcastQueue(ids[0])
+	assertQueue(t, db.GetPodcastQueue, []int64{ids[1], ids[2]})
+
+	// Playback positions
+	if p, err := db.GetPlaybackPosition(ids[1]); err != nil || p != nil {
+		t.Fatalf("expected no playback position, got %+v, %v", p, err)
+	}
+	if err := db.SavePlaybackPosition(ids[1], 125.5, false); err != nil {
+		t.Fatalf("SavePlaybackPosition: %v", err)
+	}
+	p, err := db.GetPlaybackPosition(ids[1])
+	if err != nil || p == nil || p.Position != 125.5 || p.Completed {
+		t.Fatalf("GetPlaybackPosition = %+v, %v", p, err)
+	}
+
+	// Podcast data of deleted articles is removed
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, ids[1]); err != nil {
+		t.Fatalf("delete article: %v", err)
+	}
+	if _, err := db.CleanupOrphanedPodcastData(); err != nil {
+		t.Fatalf("CleanupOrphanedPodcastData: %v", err)
+	}
+	if p, _ := db.GetPlaybackPosition(ids[1]); p != nil {
+		t.Errorf("expected playback position to be removed")
+	}
+	assertQueue(t, db.GetPodcastQueue, []int64{ids[2]})
+}
+
+func assertQueue(t *testing.T, getQueue func() ([]int64, error), want []int64) {
+	t.Helper()
+	got, err := getQueue()
+	if err != nil {
+		t.Fatalf("GetPodcastQueue: %v", err)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"MrRSS/internal/models"
+)
+
+const publishedFeedColumns = `id, title, source, COALESCE(category, ''), COALESCE(conditions, ''), item_limit, token, created_at`
+
+// CreatePublishedFeed stores a new outgoing feed and returns its ID
+func (db *DB) CreatePublishedFeed(f *models.PublishedFeed) (int64, error) {
+	db.WaitForReady()
+	res, err := db.Exec(`
+		INSERT INTO published_feeds (title, source, category, conditions, item_limit, token)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, f.Title, f.Source, f.Category, string(f.Conditions), f.Limit, f.Token)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create published feed: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create published feed: %w", err)
+	}
+	f.ID = id
+	return id, nil
+}
+
+// UpdatePublishedFeed stores the title, source and limit of an outgoing feed.
+// The token is only changed with SetPublishedFeedToken.
+func (db *DB) UpdatePublishedFeed(f models.PublishedFeed) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE published_feeds SET title = ?, source = ?, category = ?, conditions = ?, item_limit = ?
+		WHERE id = ?
+	`, f.Title, f.Source, f.Category, string(f.Conditions), f.Limit, f.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update published feed: %w", err)
+	}
+	return nil
+}
+
+// SetPublishedFeedToken replaces the token of an outgoing feed, which revokes its previous URLs
+func (db *DB) SetPublishedFeedToken(id int64, token string) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`UPDATE published_feeds SET token = ? WHERE id = ?`, token, id); err != nil {
+		return fmt.Errorf("failed to set published feed token: %w", err)
+	}
+	return nil
+}
+
+// GetPublishedFeed retrieves an outgoing feed by ID. Returns nil if it does not exist.
+func (db *DB) GetPublishedFeed(id int64) (*models.PublishedFeed, error) {
+	return db.getPublishedFeed(`id = ?`, id)
+}
+
+// GetPublishedFeedByToken retrieves the outgoing feed of a token. Returns nil if no feed has it.
+func (db *DB) GetPublishedFeedByToken(token string) (*models.PublishedFeed, error) {
+	if token == "" {
+		return nil, nil
+	}
+	return db.getPublishedFeed(`token = ?`, token)
+}
+
+// getPublishedFeed retrieves the outgoing feed matching a condition
+func (db *DB) getPublishedFeed(where string, arg interface{}) (*models.PublishedFeed, error) {
+	db.WaitForReady()
+	f, err := scanPublishedFeed(db.QueryRow(`SELECT `+publishedFeedColumns+` FROM published_feeds WHERE `+where, arg))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published feed: %w", err)
+	}
+	return f, nil
+}
+
+// GetPublishedFeeds retrieves all outgoing feeds
+func (db *DB) GetPublishedFeeds() ([]models.PublishedFeed, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT ` + publishedFeedColumns + ` FROM published_feeds ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get published feeds: %w", err)
+	}
+	defer rows.Close()
+
+	var feeds []models.PublishedFeed
+	for rows.Next() {
+		f, err := scanPublishedFeed(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan published feed: %w", err)
+		}
+		feeds = append(feeds, *f)
+	}
+	return feeds, rows.Err()
+}
+
+// DeletePublishedFeed removes an outgoing feed
+func (db *DB) DeletePublishedFeed(id int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM published_feeds WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete published feed: %w", err)
+	}
+	return nil
+}
+
+// scanPublishedFeed scans a row of published_feeds
+func scanPublishedFeed(row interface{ Scan(...interface{}) error }) (*models.PublishedFeed, error) {
+	var f models.PublishedFeed
+	var conditions string
+	var createdAt sql.NullTime
+	if err := row.Scan(&f.ID, &f.Title, &f.Source, &f.Category, &conditions, &f.Limit, &f.Token, &createdAt); err != nil {
+		return nil, err
+	}
+	if conditions != "" {
+		f.Conditions = []byte(conditions)
+	}
+	f.CreatedAt = createdAt.Time
+	return &f, nil
+}
@@ -0,0 +1,64 @@
+package database_test
+
+import (
+	"testing"
+
+	"MrRSS/internal/models"
+)
+
+func TestPublishedFeeds(t *testing.T) {
+	db := setupTestDB(t)
+
+	picks := &models.PublishedFeed{
+		Title:      "Team picks",
+		Source:     "filter",
+		Conditions: []byte(`[{"field":"article_title","operator":"contains","value":"go"}]`),
+		Limit:      20,
+		Token:      "secret",
+	}
+	id, err := db.CreatePublishedFeed(picks)
+	if err != nil || id == 0 || picks.ID != id {
+		t.Fatalf("CreatePublishedFeed = %d, %v", id, err)
+	}
+	if _, err := db.CreatePublishedFeed(&models.PublishedFeed{Title: "Other", Source: "favorites", Token: "secret"}); err == nil {
+		t.Error("Expected an error for a duplicate token")
+	}
+
+	f, err := db.GetPublishedFeedByToken("secret")
+	if err != nil || f == nil {
+		t.Fatalf("GetPublishedFeedByToken = %v, %v", f, err)
+	}
+	if f.Title != "Team picks" || f.Limit != 20 || string(f.Conditions) != string(picks.Conditions) || f.CreatedAt.IsZero() {
+		t.Errorf("Unexpected feed: %+v", f)
+	}
+	if f, err := db.GetPublishedFeedByToken(""); err != nil || f != nil {
+		t.Errorf("GetPublishedFeedByToken(\"\") = %v, %v", f, err)
+	}
+
+	f.Source = "category"
+	f.Category = "news"
+	f.Conditions = nil
+	if err := db.UpdatePublishedFeed(*f); err != nil {
+		t.Fatalf("UpdatePublishedFeed: %v", err)
+	}
+	if err := db.SetPublishedFeedToken(id, "rotated"); err != nil {
+		t.Fatalf("SetPublishedFeedToken: %v", err)
+	}
+	if f, _ := db.GetPublishedFeedByToken("secret"); f != nil {
+		t.Error("Previous token still valid")
+	}
+	f, _ = db.GetPublishedFeed(id)
+	if f.Token != "rotated" || f.Source != "category" || f.Category != "news" || f.Conditions != nil {
+		t.Errorf("Unexpected feed after update: %+v", f)
+	}
+
+	if feeds, err := db.GetPublishedFeeds(); err != nil || len(feeds) != 1 {
+		t.Fatalf("GetPublishedFeeds = %v, %v", feeds, err)
+	}
+	if err := db.DeletePublishedFeed(id); err != nil {
+		t.Fatalf("DeletePublishedFeed: %v", err)
+	}
+	if f, err := db.GetPublishedFeed(id); err != nil || f != nil {
+		t.Errorf("GetPublishedFeed after delete = %v, %v", f, err)
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"fmt"
+
+	"MrRSS/internal/models"
+)
+
+// SaveArticleTags replaces the tags of an article that come from the given source.
+func (db *DB) SaveArticleTags(articleID int64, source string, tags []models.ArticleTag) error {
+	db.WaitForReady()
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM article_tags WHERE article_id = ? AND source = ?`, articleID, source); err != nil {
+		return fmt.Errorf("failed to delete article tags: %w", err)
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(
+			`INSERT OR REPLACE INTO article_tags (article_id, tag, score, source) VALUES (?, ?, ?, ?)`,
+			articleID, tag.Tag, tag.Score, source,
+		); err != nil {
+			return fmt.Errorf("failed to save article tag: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// GetArticleTags returns the tags of an article, most relevant first.
+func (db *DB) GetArticleTags(articleID int64) ([]models.ArticleTag, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`SELECT tag, score, source FROM article_tags WHERE article_id = ? ORDER BY score DESC, tag`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]models.ArticleTag, 0)
+	for rows.Next() {
+		var tag models.ArticleTag
+		if err := rows.Scan(&tag.Tag, &tag.Score, &tag.Source); err != nil {
+			return nil, fmt.Errorf("failed to scan article tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetArticleIDsByTag returns the IDs of the articles with the given tag, newest first.
+func (db *DB) GetArticleIDsByTag(tag string, limit int) ([]int64, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT t.article_id
+		FROM article_tags t
+		JOIN articles a ON a.id = t.article_id
+		WHERE t.tag = ?
+		ORDER BY a.published_at DESC
+		LIMIT ?
+	`, tag, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles by tag: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// CleanupOrphanedArticleTags removes tags whose articles no longer exist.
+func (db *DB) CleanupOrphanedArticleTags() (int64, error) {
+	db.WaitForReady()
+	result, err := db.Exec(`DELETE FROM article_tags WHERE article_id NOT IN (SELECT id FROM articles)`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
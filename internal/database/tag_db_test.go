@@ -0,0 +1,61 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestArticleTags(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+	article := &models.Article{FeedID: feedID, Title: "tagged", URL: "https://example.com/tagged", PublishedAt: time.Now()}
+	if err := db.SaveArticles(context.Background(), []*models.Article{article}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	var articleID int64
+	if err := db.QueryRow(`SELECT id FROM articles WHERE url = ?`, article.URL).Scan(&articleID); err != nil {
+		t.Fatalf("select article id: %v", err)
+	}
+
+	tags := []models.ArticleTag{{Tag: "golang", Score: 0.5}, {Tag: "sqlite", Score: 1}}
+	if err := db.SaveArticleTags(articleID, "keyphrase", tags); err != nil {
+		t.Fatalf("SaveArticleTags: %v", err)
+	}
+
+	got, err := db.GetArticleTags(articleID)
+	if err != nil {
+		t.Fatalf("GetArticleTags: %v", err)
+	}
+	if len(got) != 2 || got[0].Tag != "sqlite" || got[0].Source != "keyphrase" {
+		t.Fatalf("unexpected tags: %+v", got)
+	}
+
+	// Saving again replaces the tags of the same source
+	if err := db.SaveArticleTags(articleID, "keyphrase", []models.ArticleTag{{Tag: "rss", Score: 1}}); err != nil {
+		t.Fatalf("SaveArticleTags: %v", err)
+	}
+	got, _ = db.GetArticleTags(articleID)
+	if len(got) != 1 || got[0].Tag != "rss" {
+		t.Fatalf("expected tags to be replaced, got %+v", got)
+	}
+
+	ids, err := db.GetArticleIDsByTag("rss", 10)
+	if err != nil || len(ids) != 1 || ids[0] != articleID {
+		t.Fatalf("GetArticleIDsByTag = %v, %v", ids, err)
+	}
+
+	// Tags of deleted articles are cleaned up
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, articleID); err != nil {
+		t.Fatalf("delete article: %v", err)
+	}
+	if n, err := db.CleanupOrphanedArticleTags(); err != nil || n != 1 {
+		t.Fatalf("CleanupOrphanedArticleTags = %d, %v", n, err)
+	}
+}
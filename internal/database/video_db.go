@@ -0,0 +1,100 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// SaveVideoInfo stores the video metadata of an article, replacing any previous metadata
+func (db *DB) SaveVideoInfo(v models.VideoInfo) error {
+	db.WaitForReady()
+	thumbnails, err := json.Marshal(v.Thumbnails)
+	if err != nil {
+		return fmt.Errorf("failed to encode video thumbnails: %w", err)
+	}
+	_, err = db.Exec(`
+		INSERT OR REPLACE INTO video_info (article_id, video_id, duration, views, rating_count, rating_average, thumbnails)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, v.ArticleID, v.VideoID, v.Duration, v.Views, v.RatingCount, v.RatingAverage, string(thumbnails))
+	if err != nil {
+		return fmt.Errorf("failed to save video info: %w", err)
+	}
+	return nil
+}
+
+// GetVideoInfo retrieves the video metadata of an article.
+// Returns nil if the article has no video metadata.
+func (db *DB) GetVideoInfo(articleID int64) (*models.VideoInfo, error) {
+	db.WaitForReady()
+	var v models.VideoInfo
+	var thumbnails string
+	err := db.QueryRow(`
+		SELECT v.article_id, COALESCE(v.video_id, ''), COALESCE(v.duration, 0), COALESCE(v.views, 0),
+			COALESCE(v.rating_count, 0), COALESCE(v.rating_average, 0), COALESCE(v.thumbnails, '[]'),
+			CASE WHEN COALESCE(t.content, '') != '' THEN COALESCE(t.lang, '') ELSE '' END
+		FROM video_info v
+		LEFT JOIN video_transcripts t ON t.article_id = v.article_id
+		WHERE v.article_id = ?
+	`, articleID).Scan(&v.ArticleID, &v.VideoID, &v.Duration, &v.Views, &v.RatingCount, &v.RatingAverage, &thumbnails, &v.TranscriptLang)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video info: %w", err)
+	}
+	if err := json.Unmarshal([]byte(thumbnails), &v.Thumbnails); err != nil {
+		v.Thumbnails = nil
+	}
+	return &v, nil
+}
+
+// SaveVideoTranscript stores the captions of a video as plain text.
+// An empty content records that the video has no usable captions, so they are
+// not requested again.
+func (db *DB) SaveVideoTranscript(articleID int64, lang, content string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT OR REPLACE INTO video_transcripts (article_id, lang, content, fetched_at)
+		VALUES (?, ?, ?, ?)
+	`, articleID, lang, content, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save video transcript: %w", err)
+	}
+	return nil
+}
+
+// GetVideoTranscript retrieves the captions of a video.
+// Returns found = false if the captions were never fetched.
+func (db *DB) GetVideoTranscript(articleID int64) (lang, content string, found bool, err error) {
+	db.WaitForReady()
+	err = db.QueryRow(
+		`SELECT COALESCE(lang, ''), COALESCE(content, '') FROM video_transcripts WHERE article_id = ?`,
+		articleID,
+	).Scan(&lang, &content)
+	if err == sql.ErrNoRows {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to get video transcript: %w", err)
+	}
+	return lang, content, true, nil
+}
+
+// CleanupOrphanedVideoData removes video metadata and transcripts whose articles no longer exist
+func (db *DB) CleanupOrphanedVideoData() (int64, error) {
+	db.WaitForReady()
+	var total int64
+	for _, table := range []string{"video_info", "video_transcripts"} {
+		result, err := db.Exec(`DELETE FROM ` + table + ` WHERE article_id NOT IN (SELECT id FROM articles)`)
+		if err != nil {
+			return total, err
+		}
+		count, _ := result.RowsAffected()
+		total += count
+	}
+	return total, nil
+}
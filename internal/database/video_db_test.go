@@ -0,0 +1,72 @@
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+func TestVideoInfoAndTranscript(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+	article := &models.Article{FeedID: feedID, Title: "Video", URL: "https://www.youtube.com/watch?v=abc", PublishedAt: time.Now()}
+	if err := db.SaveArticles(context.Background(), []*models.Article{article}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	var articleID int64
+	if err := db.QueryRow(`SELECT id FROM articles WHERE url = ?`, article.URL).Scan(&articleID); err != nil {
+		t.Fatalf("select article id: %v", err)
+	}
+
+	if info, err := db.GetVideoInfo(articleID); err != nil || info != nil {
+		t.Fatalf("expected no video info, got %+v, %v", info, err)
+	}
+
+	err := db.SaveVideoInfo(models.VideoInfo{
+		ArticleID:  articleID,
+		VideoID:    "abc",
+		Views:      42,
+		Thumbnails: []models.VideoThumbnail{{URL: "https://i.ytimg.com/vi/abc/default.jpg", Width: 120, Height: 90}},
+	})
+	if err != nil {
+		t.Fatalf("SaveVideoInfo: %v", err)
+	}
+	info, err := db.GetVideoInfo(articleID)
+	if err != nil || info == nil {
+		t.Fatalf("GetVideoInfo: %+v, %v", info, err)
+	}
+	if info.VideoID != "abc" || info.Views != 42 || len(info.Thumbnails) != 1 || info.TranscriptLang != "" {
+		t.Errorf("unexpected video info: %+v", info)
+	}
+
+	// An empty transcript records a video without captions
+	if _, _, found, _ := db.GetVideoTranscript(articleID); found {
+		t.Error("expected no transcript before fetching")
+	}
+	_ = db.SaveVideoTranscript(articleID, "", "")
+	if _, _, found, _ := db.GetVideoTranscript(articleID); !found {
+		t.Error("expected missing captions to be recorded")
+	}
+
+	_ = db.SaveVideoTranscript(articleID, "en", "Hello")
+	lang, content, found, err := db.GetVideoTranscript(articleID)
+	if err != nil || !found || lang != "en" || content != "Hello" {
+		t.Errorf("GetVideoTranscript = %q, %q, %v, %v", lang, content, found, err)
+	}
+	if info, _ := db.GetVideoInfo(articleID); info.TranscriptLang != "en" {
+		t.Errorf("TranscriptLang = %q, want en", info.TranscriptLang)
+	}
+
+	if _, err := db.Exec(`DELETE FROM articles WHERE id = ?`, articleID); err != nil {
+		t.Fatalf("delete article: %v", err)
+	}
+	if count, err := db.CleanupOrphanedVideoData(); err != nil || count != 2 {
+		t.Errorf("CleanupOrphanedVideoData = %d, %v; want 2", count, err)
+	}
+}
@@ -0,0 +1,106 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// SaveWebSubHub records the hub advertised by a feed. A subscription to another
+// hub or topic is replaced, so that the feed subscribes again; otherwise the
+// subscription is left unchanged.
+func (db *DB) SaveWebSubHub(feedID int64, hub, topic string) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		INSERT INTO websub_subscriptions (feed_id, hub, topic, state, updated_at)
+		VALUES (?, ?, ?, 'discovered', ?)
+		ON CONFLICT(feed_id) DO UPDATE SET
+			hub = excluded.hub, topic = excluded.topic, secret = '', state = 'discovered',
+			lease_expires_at = NULL, last_error = '', updated_at = excluded.updated_at
+		WHERE hub != excluded.hub OR topic != excluded.topic
+	`, feedID, hub, topic, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save websub hub: %w", err)
+	}
+	return nil
+}
+
+// UpdateWebSubSubscription stores the state, secret, lease and error of a subscription
+func (db *DB) UpdateWebSubSubscription(s models.WebSubSubscription) error {
+	db.WaitForReady()
+	_, err := db.Exec(`
+		UPDATE websub_subscriptions
+		SET secret = ?, state = ?, lease_expires_at = ?, last_error = ?, updated_at = ?
+		WHERE feed_id = ?
+	`, s.Secret, s.State, s.LeaseExpiresAt, s.LastError, time.Now(), s.FeedID)
+	if err != nil {
+		return fmt.Errorf("failed to update websub subscription: %w", err)
+	}
+	return nil
+}
+
+// GetWebSubSubscription retrieves the subscription of a feed.
+// Returns nil if the feed advertises no hub.
+func (db *DB) GetWebSubSubscription(feedID int64) (*models.WebSubSubscription, error) {
+	db.WaitForReady()
+	s, err := scanWebSubSubscription(db.QueryRow(`
+		SELECT feed_id, hub, topic, COALESCE(secret, ''), state, lease_expires_at, COALESCE(last_error, ''), updated_at
+		FROM websub_subscriptions WHERE feed_id = ?
+	`, feedID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get websub subscription: %w", err)
+	}
+	return s, nil
+}
+
+// GetWebSubSubscriptions retrieves the subscriptions of all feeds
+func (db *DB) GetWebSubSubscriptions() ([]models.WebSubSubscription, error) {
+	db.WaitForReady()
+	rows, err := db.Query(`
+		SELECT feed_id, hub, topic, COALESCE(secret, ''), state, lease_expires_at, COALESCE(last_error, ''), updated_at
+		FROM websub_subscriptions ORDER BY feed_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get websub subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []models.WebSubSubscription
+	for rows.Next() {
+		s, err := scanWebSubSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan websub subscription: %w", err)
+		}
+		subs = append(subs, *s)
+	}
+	return subs, rows.Err()
+}
+
+// DeleteWebSubSubscription removes the subscription of a feed
+func (db *DB) DeleteWebSubSubscription(feedID int64) error {
+	db.WaitForReady()
+	if _, err := db.Exec(`DELETE FROM websub_subscriptions WHERE feed_id = ?`, feedID); err != nil {
+		return fmt.Errorf("failed to delete websub subscription: %w", err)
+	}
+	return nil
+}
+
+// scanWebSubSubscription scans a row of websub_subscriptions
+func scanWebSubSubscription(row interface{ Scan(...interface{}) error }) (*models.WebSubSubscription, error) {
+	var s models.WebSubSubscription
+	var leaseExpiresAt, updatedAt sql.NullTime
+	if err := row.Scan(&s.FeedID, &s.Hub, &s.Topic, &s.Secret, &s.State, &leaseExpiresAt, &s.LastError, &updatedAt); err != nil {
+		return nil, err
+	}
+	if leaseExpiresAt.Valid {
+		t := leaseExpiresAt.Time
+		s.LeaseExpiresAt = &t
+	}
+	s.UpdatedAt = updatedAt.Time
+	return &s, nil
+}
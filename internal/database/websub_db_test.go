@@ -0,0 +1,61 @@
+package database_test
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebSubSubscriptions(t *testing.T) {
+	db := setupDBWithFeed(t)
+
+	var feedID int64
+	if err := db.QueryRow(`SELECT id FROM feeds LIMIT 1`).Scan(&feedID); err != nil {
+		t.Fatalf("select feed id: %v", err)
+	}
+
+	if s, err := db.GetWebSubSubscription(feedID); err != nil || s != nil {
+		t.Fatalf("GetWebSubSubscription before discovery = %v, %v", s, err)
+	}
+	if err := db.SaveWebSubHub(feedID, "https://hub.example/", "https://example.com/feed"); err != nil {
+		t.Fatalf("SaveWebSubHub: %v", err)
+	}
+
+	s, err := db.GetWebSubSubscription(feedID)
+	if err != nil || s == nil || s.State != "discovered" || s.Hub != "https://hub.example/" {
+		t.Fatalf("GetWebSubSubscription = %+v, %v", s, err)
+	}
+
+	lease := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	s.State = "active"
+	s.Secret = "key"
+	s.LeaseExpiresAt = &lease
+	if err := db.UpdateWebSubSubscription(*s); err != nil {
+		t.Fatalf("UpdateWebSubSubscription: %v", err)
+	}
+
+	// The same hub leaves the subscription active
+	if err := db.SaveWebSubHub(feedID, "https://hub.example/", "https://example.com/feed"); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = db.GetWebSubSubscription(feedID)
+	if s.State != "active" || s.Secret != "key" || s.LeaseExpiresAt == nil || !s.LeaseExpiresAt.Equal(lease) {
+		t.Errorf("subscription changed by the same hub: %+v", s)
+	}
+
+	// Another hub subscribes again
+	if err := db.SaveWebSubHub(feedID, "https://other-hub.example/", "https://example.com/feed"); err != nil {
+		t.Fatal(err)
+	}
+	s, _ = db.GetWebSubSubscription(feedID)
+	if s.State != "discovered" || s.Secret != "" || s.LeaseExpiresAt != nil {
+		t.Errorf("subscription not reset for a new hub: %+v", s)
+	}
+
+	// Deleting the feed deletes its subscription
+	if err := db.DeleteFeed(feedID); err != nil {
+		t.Fatal(err)
+	}
+	if subs, err := db.GetWebSubSubscriptions(); err != nil || len(subs) != 0 {
+		t.Errorf("GetWebSubSubscriptions after deleting the feed = %v, %v", subs, err)
+	}
+}
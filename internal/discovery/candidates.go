@@ -0,0 +1,320 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// Candidate sources, in the order candidates are returned
+const (
+	CandidateSourceLink     = "link"     // <link rel="alternate"> in the page head
+	CandidateSourcePlatform = "platform" // URL pattern of a known platform
+	CandidateSourcePath     = "path"     // Common feed path on the site
+)
+
+const (
+	// maxPageSize limits the size of pages and feeds read during discovery
+	maxPageSize = 10 * 1024 * 1024
+	// MaxFeedCandidates limits the number of candidates returned for a page
+	MaxFeedCandidates = 10
+)
+
+// FeedCandidate is a feed found on a page, with enough details to choose one before subscribing
+type FeedCandidate struct {
+	URL        string     `json:"url"`
+	Title      string     `json:"title"`
+	Type       string     `json:"type"`   // "rss", "atom" or "json"
+	Source     string     `json:"source"` // "link", "platform" or "path"
+	ItemCount  int        `json:"item_count"`
+	LastPostAt *time.Time `json:"last_post_at,omitempty"`
+}
+
+// commonFeedPaths are the paths where sites commonly serve their feed
+var commonFeedPaths = []string{
+	"/rss.xml",
+	"/feed.xml",
+	"/atom.xml",
+	"/feed",
+	"/rss",
+	"/feeds/posts/default", // Blogger
+	"/index.xml",           // Hugo
+	"/feed/",
+	"/rss/",
+	"/atom/",
+	"/blog/feed",
+	"/blog/rss",
+	"/blog/feed.xml",
+	"/blog/rss.xml",
+	"/posts/feed",
+	"/posts/rss.xml",
+	"/?feed=rss2",     // WordPress
+	"/feed/?type=rss", // Some WordPress
+	"/rss2.xml",
+	"/feed.atom",
+	"/feed.rss",
+	"/feed.json", // JSON Feed
+}
+
+// feedLinkTypes are the MIME types of <link rel="alternate"> elements pointing to feeds
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/rdf+xml":   true,
+	"application/feed+json": true,
+	"application/json":      true,
+	"text/xml":              true,
+	"application/xml":       true,
+}
+
+// FindFeedCandidates finds the feeds offered by a page: its <link rel="alternate">
+// feeds, the feeds of known platforms (WordPress, Ghost, Blogger, Substack, Medium,
+// GitHub, Reddit, Mastodon) and, if none is found, feeds at common paths.
+// Each candidate is fetched for its title, item count and last post date.
+// If the URL itself is a feed, it is returned parsed, with no candidates, so that
+// subscribing does not download it again.
+func (s *Service) FindFeedCandidates(ctx context.Context, pageURL string) (candidates []FeedCandidate, feed *gofeed.Feed, err error) {
+	body, finalURL, err := s.fetchPage(ctx, pageURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if feed, err := gofeed.NewParser().Parse(bytes.NewReader(body)); err == nil {
+		return nil, feed, nil
+	}
+
+	base, err := url.Parse(finalURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, _ := goquery.NewDocumentFromReader(bytes.NewReader(body))
+
+	var urls, sources []string
+	seen := make(map[string]bool)
+	add := func(candidateURL, source string) {
+		if candidateURL == "" || seen[candidateURL] {
+			return
+		}
+		seen[candidateURL] = true
+		urls = append(urls, candidateURL)
+		sources = append(sources, source)
+	}
+
+	if doc != nil {
+		for _, link := range s.feedLinks(doc, finalURL) {
+			add(link, CandidateSourceLink)
+		}
+	}
+	for _, link := range platformFeedURLs(base, doc) {
+		add(link, CandidateSourcePlatform)
+	}
+
+	candidates = s.probeCandidates(ctx, urls, sources)
+	if len(candidates) == 0 {
+		urls, sources = nil, nil
+		root := fmt.Sprintf("%s://%s", base.Scheme, base.Host)
+		for _, path := range commonFeedPaths {
+			add(root+path, CandidateSourcePath)
+		}
+		candidates = s.probeCandidates(ctx, urls, sources)
+	}
+
+	if len(candidates) > MaxFeedCandidates {
+		candidates = candidates[:MaxFeedCandidates]
+	}
+	return candidates, nil, nil
+}
+
+// fetchPage fetches a page and returns its body and its URL after redirects
+func (s *Service) fetchPage(ctx context.Context, pageURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", "MrRSS (Feed Discovery)")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return nil, "", err
+	}
+	return body, resp.Request.URL.String(), nil
+}
+
+// feedLinks returns the feeds linked from the head of a page
+func (s *Service) feedLinks(doc *goquery.Document, pageURL string) []string {
+	var links []string
+	doc.Find("link[href]").Each(func(i int, sel *goquery.Selection) {
+		rel := strings.Fields(strings.ToLower(sel.AttrOr("rel", "")))
+		isAlternate := false
+		for _, r := range rel {
+			if r == "alternate" || r == "feed" {
+				isAlternate = true
+			}
+		}
+		linkType := strings.ToLower(strings.TrimSpace(sel.AttrOr("type", "")))
+		if !isAlternate || !feedLinkTypes[linkType] {
+			return
+		}
+		resolved := s.resolveURL(pageURL, strings.TrimSpace(sel.AttrOr("href", "")))
+		if strings.HasPrefix(resolved, "http://") || strings.HasPrefix(resolved, "https://") {
+			links = append(links, resolved)
+		}
+	})
+	return links
+}
+
+// platformFeedURLs returns the feed URLs of known platforms, derived from the page
+// URL and, when available, the page itself
+func platformFeedURLs(u *url.URL, doc *goquery.Document) []string {
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	root := fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+	path := strings.Trim(u.Path, "/")
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, "/")
+	}
+
+	switch {
+	case host == "github.com":
+		if len(segments) == 1 {
+			return []string{"https://github.com/" + segments[0] + ".atom"}
+		}
+		if len(segments) >= 2 {
+			repo := "https://github.com/" + segments[0] + "/" + segments[1]
+			return []string{repo + "/releases.atom", repo + "/tags.atom", repo + "/commits.atom"}
+		}
+		return nil
+
+	case host == "reddit.com" || host == "old.reddit.com":
+		if len(segments) >= 2 && (segments[0] == "r" || segments[0] == "user" || segments[0] == "u") {
+			kind := segments[0]
+			if kind == "u" {
+				kind = "user"
+			}
+			return []string{"https://www.reddit.com/" + kind + "/" + segments[1] + "/.rss"}
+		}
+		return []string{"https://www.reddit.com/.rss"}
+
+	case host == "medium.com":
+		if len(segments) >= 2 && segments[0] == "tag" {
+			return []string{"https://medium.com/feed/tag/" + segments[1]}
+		}
+		if len(segments) >= 1 {
+			return []string{"https://medium.com/feed/" + segments[0]}
+		}
+		return nil
+
+	case strings.HasSuffix(host, ".medium.com"), strings.HasSuffix(host, ".substack.com"):
+		return []string{root + "/feed"}
+	}
+
+	var feeds []string
+	// Mastodon and other fediverse profiles
+	if len(segments) == 1 && strings.HasPrefix(segments[0], "@") && len(segments[0]) > 1 {
+		feeds = append(feeds, root+"/"+segments[0]+".rss")
+	}
+
+	if doc == nil {
+		return feeds
+	}
+	generator := strings.ToLower(doc.Find(`meta[name="generator"]`).AttrOr("content", ""))
+	html, _ := doc.Html()
+	switch {
+	case strings.Contains(generator, "wordpress") || strings.Contains(html, "/wp-content/"):
+		// Category, tag and author archives have their own feed
+		if path != "" {
+			feeds = append(feeds, root+"/"+path+"/feed/")
+		}
+		feeds = append(feeds, root+"/feed/")
+	case strings.Contains(generator, "ghost"):
+		feeds = append(feeds, root+"/rss/")
+	case strings.Contains(generator, "blogger"):
+		feeds = append(feeds, root+"/feeds/posts/default")
+	case strings.Contains(html, "substackcdn.com"):
+		// Substack publication on a custom domain
+		feeds = append(feeds, root+"/feed")
+	}
+	return feeds
+}
+
+// probeCandidates fetches candidate feeds concurrently and returns the valid
+// ones, in the order they were given
+func (s *Service) probeCandidates(ctx context.Context, urls, sources []string) []FeedCandidate {
+	results := make([]*FeedCandidate, len(urls))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrentPathChecks)
+
+	for i, candidateURL := range urls {
+		wg.Add(1)
+		go func(i int, candidateURL string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if candidate, err := s.fetchCandidate(ctx, candidateURL); err == nil {
+				candidate.Source = sources[i]
+				results[i] = candidate
+			}
+		}(i, candidateURL)
+	}
+	wg.Wait()
+
+	var candidates []FeedCandidate
+	seen := make(map[string]bool)
+	for _, candidate := range results {
+		// Several paths often redirect to the same feed
+		if candidate == nil || seen[candidate.URL] {
+			continue
+		}
+		seen[candidate.URL] = true
+		candidates = append(candidates, *candidate)
+	}
+	return candidates
+}
+
+// fetchCandidate fetches and parses a candidate feed
+func (s *Service) fetchCandidate(ctx context.Context, feedURL string) (*FeedCandidate, error) {
+	body, finalURL, err := s.fetchPage(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	candidate := &FeedCandidate{
+		URL:       finalURL,
+		Title:     strings.TrimSpace(feed.Title),
+		Type:      feed.FeedType,
+		ItemCount: len(feed.Items),
+	}
+	for _, item := range feed.Items {
+		date := item.PublishedParsed
+		if date == nil {
+			date = item.UpdatedParsed
+		}
+		if date != nil && (candidate.LastPostAt == nil || date.After(*candidate.LastPostAt)) {
+			candidate.LastPostAt = date
+		}
+	}
+	return candidate, nil
+}
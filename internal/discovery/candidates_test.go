@@ -0,0 +1,120 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const candidateRSS = `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog RSS</title>
+<item><title>New</title><pubDate>Mon, 02 Jan 2006 15:04:05 GMT</pubDate></item>
+<item><title>Old</title><pubDate>Sun, 01 Jan 2006 15:04:05 GMT</pubDate></item>
+</channel></rss>`
+
+const candidateJSONFeed = `{"version": "https://jsonfeed.org/version/1.1", "title": "Blog JSON",
+"items": [{"id": "1", "title": "Post", "date_published": "2006-01-03T00:00:00Z"}]}`
+
+func TestFindFeedCandidates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><head>
+				<link rel="alternate" type="application/rss+xml" href="/feed.xml">
+				<link rel="alternate" type="application/feed+json" href="/feed.json">
+				<link rel="alternate" type="application/rss+xml" href="/missing.xml">
+				<link rel="stylesheet" href="/style.css">
+			</head></html>`))
+		case "/bare":
+			w.Write([]byte(`<html><head><title>No links</title></head></html>`))
+		case "/feed.xml", "/rss.xml":
+			w.Write([]byte(candidateRSS))
+		case "/feed.json":
+			w.Write([]byte(candidateJSONFeed))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	service := NewService()
+	ctx := context.Background()
+
+	candidates, feed, err := service.FindFeedCandidates(ctx, server.URL+"/")
+	if err != nil || feed != nil {
+		t.Fatalf("FindFeedCandidates = %v, %v", feed, err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", candidates)
+	}
+	rss, jsonFeed := candidates[0], candidates[1]
+	if rss.URL != server.URL+"/feed.xml" || rss.Title != "Blog RSS" || rss.Type != "rss" || rss.ItemCount != 2 || rss.Source != CandidateSourceLink {
+		t.Errorf("unexpected RSS candidate: %+v", rss)
+	}
+	if rss.LastPostAt == nil || rss.LastPostAt.Day() != 2 {
+		t.Errorf("LastPostAt = %v, want the newest item", rss.LastPostAt)
+	}
+	if jsonFeed.Title != "Blog JSON" || jsonFeed.Type != "json" || jsonFeed.ItemCount != 1 {
+		t.Errorf("unexpected JSON Feed candidate: %+v", jsonFeed)
+	}
+
+	// Without links, common paths are tried
+	candidates, _, err = service.FindFeedCandidates(ctx, server.URL+"/bare")
+	if err != nil {
+		t.Fatalf("FindFeedCandidates: %v", err)
+	}
+	if len(candidates) != 3 || candidates[0].Source != CandidateSourcePath {
+		t.Errorf("expected the feeds at common paths, got %+v", candidates)
+	}
+
+	// A feed URL has no candidates
+	candidates, feed, err = service.FindFeedCandidates(ctx, server.URL+"/feed.xml")
+	if err != nil || feed == nil || feed.Title != "Blog RSS" || len(candidates) != 0 {
+		t.Errorf("expected feed URL to be detected, got %+v, %v, %v", candidates, feed, err)
+	}
+}
+
+func TestPlatformFeedURLs(t *testing.T) {
+	tests := []struct {
+		page string
+		html string
+		want []string
+	}{
+		{"https://github.com/owner", "", []string{"https://github.com/owner.atom"}},
+		{"https://github.com/owner/repo/issues", "", []string{
+			"https://github.com/owner/repo/releases.atom",
+			"https://github.com/owner/repo/tags.atom",
+			"https://github.com/owner/repo/commits.atom",
+		}},
+		{"https://old.reddit.com/r/golang/", "", []string{"https://www.reddit.com/r/golang/.rss"}},
+		{"https://www.reddit.com/u/someone", "", []string{"https://www.reddit.com/user/someone/.rss"}},
+		{"https://medium.com/@writer", "", []string{"https://medium.com/feed/@writer"}},
+		{"https://medium.com/tag/go/latest", "", []string{"https://medium.com/feed/tag/go"}},
+		{"https://writer.substack.com/p/post", "", []string{"https://writer.substack.com/feed"}},
+		{"https://mastodon.social/@user", "", []string{"https://mastodon.social/@user.rss"}},
+		{"https://blog.example.com/category/news/", `<meta name="generator" content="WordPress 6.4">`, []string{
+			"https://blog.example.com/category/news/feed/",
+			"https://blog.example.com/feed/",
+		}},
+		{"https://ghost.example.com/", `<meta name="generator" content="Ghost 5.0">`, []string{"https://ghost.example.com/rss/"}},
+		{"https://news.example.com/", `<script src="https://substackcdn.com/bundle.js"></script>`, []string{"https://news.example.com/feed"}},
+		{"https://example.com/about", "", nil},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.page)
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + tt.html + "</head></html>"))
+		if err != nil {
+			t.Fatalf("parse HTML: %v", err)
+		}
+		if got := platformFeedURLs(u, doc); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("platformFeedURLs(%q) = %v, want %v", tt.page, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package discovery
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// MaxRecommendationCandidates limits the number of cited domains checked for a feed
+	MaxRecommendationCandidates = 40
+	// MaxRecommendations limits the number of recommendations returned
+	MaxRecommendations = 20
+	// MinRecommendationLinks is the number of links a domain needs to be recommended
+	MinRecommendationLinks = 2
+)
+
+// citationSkipDomains are sites articles often link to as references rather than
+// as blogs worth following
+var citationSkipDomains = []string{
+	"wikipedia.org", "wikimedia.org", "archive.org", "doi.org",
+	"amazon.com", "apple.com", "microsoft.com", "x.com", "t.co",
+	"bsky.app", "news.ycombinator.com", "imgur.com", "gravatar.com",
+}
+
+// citationSkipExtensions are file links, which say little about the site they are hosted on
+var citationSkipExtensions = map[string]bool{
+	".jpg": true, ".jpeg": true, ".png": true, ".gif": true, ".webp": true, ".svg": true,
+	".pdf": true, ".zip": true, ".mp3": true, ".mp4": true,
+}
+
+// DomainCitation is an external domain linked from the articles of subscribed feeds
+type DomainCitation struct {
+	Domain    string
+	Homepage  string
+	LinkCount int     // Number of distinct links to the domain
+	FeedIDs   []int64 // Subscribed feeds whose articles link to the domain
+}
+
+// Recommendation is a blog recommended because subscribed feeds link to it
+type Recommendation struct {
+	DiscoveredBlog
+	Domain    string  `json:"domain"`
+	LinkCount int     `json:"link_count"`
+	FeedCount int     `json:"feed_count"`
+	FeedIDs   []int64 `json:"feed_ids"`
+}
+
+// citedDomain accumulates the links to a domain
+type citedDomain struct {
+	host  string
+	https bool
+	links int
+	feeds map[int64]bool
+}
+
+// CitationCounter counts the outbound links of articles per external domain
+type CitationCounter struct {
+	s        *Service
+	excluded map[string]bool
+	domains  map[string]*citedDomain
+}
+
+// NewCitationCounter creates a counter ignoring links to the given hosts,
+// usually the sites of subscribed feeds
+func (s *Service) NewCitationCounter(excludedHosts []string) *CitationCounter {
+	excluded := make(map[string]bool)
+	for _, host := range excludedHosts {
+		if host = citationDomain(host); host != "" {
+			excluded[host] = true
+		}
+	}
+	return &CitationCounter{s: s, excluded: excluded, domains: make(map[string]*citedDomain)}
+}
+
+// AddArticle counts the external links in the HTML content of an article.
+// Links to the article's own site are ignored.
+func (c *CitationCounter) AddArticle(feedID int64, articleURL, content string) {
+	if !strings.Contains(content, "href") {
+		return
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return
+	}
+
+	ownSite := ""
+	if u, err := url.Parse(articleURL); err == nil {
+		ownSite, _ = publicsuffix.EffectiveTLDPlusOne(strings.ToLower(u.Hostname()))
+	}
+
+	seen := make(map[string]bool)
+	doc.Find("a[href]").Each(func(i int, sel *goquery.Selection) {
+		link := strings.TrimSpace(sel.AttrOr("href", ""))
+		if articleURL != "" {
+			link = c.s.resolveURL(articleURL, link)
+		}
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+		u.Fragment = ""
+		if seen[u.String()] {
+			return
+		}
+		seen[u.String()] = true
+
+		domain := citationDomain(u.Hostname())
+		if !c.isCitable(domain, u.Path, ownSite) {
+			return
+		}
+
+		cited, ok := c.domains[domain]
+		if !ok {
+			cited = &citedDomain{host: strings.ToLower(u.Hostname()), feeds: make(map[int64]bool)}
+			c.domains[domain] = cited
+		}
+		cited.links++
+		cited.feeds[feedID] = true
+		cited.https = cited.https || u.Scheme == "https"
+	})
+}
+
+// isCitable checks whether a link to a domain can lead to a recommendation
+func (c *CitationCounter) isCitable(domain, linkPath, ownSite string) bool {
+	if domain == "" || net.ParseIP(domain) != nil || !strings.Contains(domain, ".") {
+		return false
+	}
+	if c.excluded[domain] || !c.s.isValidBlogDomain(domain) {
+		return false
+	}
+	if site, _ := publicsuffix.EffectiveTLDPlusOne(domain); site != "" && site == ownSite {
+		return false
+	}
+	for _, skip := range citationSkipDomains {
+		if domain == skip || strings.HasSuffix(domain, "."+skip) {
+			return false
+		}
+	}
+	return !citationSkipExtensions[strings.ToLower(path.Ext(linkPath))]
+}
+
+// Citations returns the domains linked at least MinRecommendationLinks times,
+// most cited first: by number of feeds linking to them, then by number of links
+func (c *CitationCounter) Citations() []DomainCitation {
+	var citations []DomainCitation
+	for domain, cited := range c.domains {
+		if cited.links < MinRecommendationLinks {
+			continue
+		}
+		scheme := "http"
+		if cited.https {
+			scheme = "https"
+		}
+		feedIDs := make([]int64, 0, len(cited.feeds))
+		for id := range cited.feeds {
+			feedIDs = append(feedIDs, id)
+		}
+		sort.Slice(feedIDs, func(i, j int) bool { return feedIDs[i] < feedIDs[j] })
+
+		citations = append(citations, DomainCitation{
+			Domain:    domain,
+			Homepage:  scheme + "://" + cited.host,
+			LinkCount: cited.links,
+			FeedIDs:   feedIDs,
+		})
+	}
+
+	sort.Slice(citations, func(i, j int) bool {
+		if len(citations[i].FeedIDs) != len(citations[j].FeedIDs) {
+			return len(citations[i].FeedIDs) > len(citations[j].FeedIDs)
+		}
+		if citations[i].LinkCount != citations[j].LinkCount {
+			return citations[i].LinkCount > citations[j].LinkCount
+		}
+		return citations[i].Domain < citations[j].Domain
+	})
+	return citations
+}
+
+// citationDomain normalizes a host name for counting citations
+func citationDomain(host string) string {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	return strings.TrimPrefix(strings.TrimSuffix(strings.ToLower(host), "."), "www.")
+}
+
+// RecommendFromCitations looks for the feeds of the most cited domains and returns
+// them as recommendations, keeping the order of the citations
+func (s *Service) RecommendFromCitations(ctx context.Context, citations []DomainCitation, progressCb ProgressCallback) []Recommendation {
+	if len(citations) > MaxRecommendationCandidates {
+		citations = citations[:MaxRecommendationCandidates]
+	}
+
+	results := make([]*Recommendation, len(citations))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, MaxConcurrentRSSChecks)
+
+	var progressMu sync.Mutex
+	processed := 0
+	foundCount := 0
+
+	for i, citation := range citations {
+		wg.Add(1)
+		go func(i int, citation DomainCitation) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+			blog, err := s.discoverBlogRSS(ctx, citation.Homepage)
+
+			progressMu.Lock()
+			processed++
+			if err == nil {
+				foundCount++
+			}
+			if progressCb != nil {
+				progressCb(Progress{
+					Stage:      "checking_rss",
+					Message:    "Checking RSS feed",
+					Detail:     citation.Homepage,
+					Current:    processed,
+					Total:      len(citations),
+					FoundCount: foundCount,
+				})
+			}
+			progressMu.Unlock()
+
+			if err != nil {
+				return
+			}
+			results[i] = &Recommendation{
+				DiscoveredBlog: blog,
+				Domain:         citation.Domain,
+				LinkCount:      citation.LinkCount,
+				FeedCount:      len(citation.FeedIDs),
+				FeedIDs:        citation.FeedIDs,
+			}
+		}(i, citation)
+	}
+	wg.Wait()
+
+	var recommendations []Recommendation
+	for _, recommendation := range results {
+		if recommendation != nil {
+			recommendations = append(recommendations, *recommendation)
+		}
+	}
+	return recommendations
+}
@@ -0,0 +1,85 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCitationCounter(t *testing.T) {
+	service := NewService()
+	counter := service.NewCitationCounter([]string{"https://subscribed.example/feed.xml", "www.known.example"})
+
+	counter.AddArticle(1, "https://blog-a.example/posts/1", `
+		<p>See <a href="https://www.cited.example/post">this</a> and <a href="https://cited.example/other">that</a>,
+		again <a href="https://cited.example/post#top">here</a> and <a href="https://cited.example/post">here</a>.</p>
+		<a href="/about">About</a> <a href="https://static.blog-a.example/x">Own CDN</a>
+		<a href="https://subscribed.example/a">Subscribed</a> <a href="https://www.known.example/b">Known</a>
+		<a href="https://en.wikipedia.org/wiki/RSS">Wikipedia</a> <a href="https://twitter.com/someone">Twitter</a>
+		<a href="https://images.example/photo.jpg">Photo</a> <a href="mailto:me@blog-a.example">Mail</a>
+		<a href="http://other.example/1">Other</a>`)
+	counter.AddArticle(2, "https://blog-b.example/2024/post", `
+		<a href="https://cited.example/post">Cited</a>
+		<a href="http://other.example/1">Other</a> <a href="http://other.example/2">Other</a>
+		<a href="https://once.example/">Once</a>`)
+
+	citations := counter.Citations()
+	if len(citations) != 2 {
+		t.Fatalf("Expected 2 citations, got %d: %+v", len(citations), citations)
+	}
+
+	// Both domains are linked from two feeds, cited.example more often
+	first := citations[0]
+	if first.Domain != "cited.example" || first.LinkCount != 4 || len(first.FeedIDs) != 2 {
+		t.Errorf("Unexpected first citation: %+v", first)
+	}
+	if first.Homepage != "https://www.cited.example" {
+		t.Errorf("Homepage = %q, want https://www.cited.example", first.Homepage)
+	}
+
+	second := citations[1]
+	if second.Domain != "other.example" || second.LinkCount != 3 || second.Homepage != "http://other.example" {
+		t.Errorf("Unexpected second citation: %+v", second)
+	}
+}
+
+func TestRecommendFromCitations(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/blog/cited-posts.rss":
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Cited Blog</title><item><title>Post</title></item></channel></rss>`))
+		case "/blog":
+			w.Write([]byte(`<html><head><link rel="alternate" type="application/rss+xml" href="` + srv.URL + `/blog/cited-posts.rss"></head></html>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	citations := []DomainCitation{
+		{Domain: "cited.example", Homepage: srv.URL + "/blog", LinkCount: 14, FeedIDs: []int64{1, 2, 3, 4, 5}},
+		{Domain: "nofeed.example", Homepage: srv.URL + "/missing", LinkCount: 3, FeedIDs: []int64{1}},
+	}
+
+	var progressCalls int
+	recommendations := NewService().RecommendFromCitations(context.Background(), citations, func(p Progress) {
+		progressCalls++
+	})
+
+	if len(recommendations) != 1 {
+		t.Fatalf("Expected 1 recommendation, got %d", len(recommendations))
+	}
+	rec := recommendations[0]
+	if rec.Name != "Cited Blog" || rec.RSSFeed != srv.URL+"/blog/cited-posts.rss" {
+		t.Errorf("Unexpected blog: %+v", rec.DiscoveredBlog)
+	}
+	if rec.Domain != "cited.example" || rec.LinkCount != 14 || rec.FeedCount != 5 {
+		t.Errorf("Unexpected counts: %+v", rec)
+	}
+	if progressCalls != 2 {
+		t.Errorf("Expected 2 progress updates, got %d", progressCalls)
+	}
+}
@@ -153,42 +153,17 @@ func (s *Service) findRSSFeed(ctx context.Context, blogURL string) (string, erro
 		}
 	}
 
-	// Expanded common RSS/Atom feed paths
-	commonPaths := []string{
-		"/rss.xml",
-		"/feed.xml",
-		"/atom.xml",
-		"/feed",
-		"/rss",
-		"/feeds/posts/default", // Blogger
-		"/index.xml",           // Hugo
-		"/feed/",
-		"/rss/",
-		"/atom/",
-		"/blog/feed",
-		"/blog/rss",
-		"/blog/feed.xml",
-		"/blog/rss.xml",
-		"/posts/feed",
-		"/posts/rss.xml",
-		"/?feed=rss2",     // WordPress
-		"/feed/?type=rss", // Some WordPress
-		"/rss2.xml",
-		"/feed.atom",
-		"/feed.rss",
-	}
-
 	// Try common paths concurrently for faster discovery
 	type feedResult struct {
 		url   string
 		valid bool
 	}
-	resultCh := make(chan feedResult, len(commonPaths))
+	resultCh := make(chan feedResult, len(commonFeedPaths))
 
 	var wg sync.WaitGroup
 	semaphore := make(chan struct{}, MaxConcurrentPathChecks)
 
-	for _, path := range commonPaths {
+	for _, path := range commonFeedPaths {
 		feedURL := baseURL + path
 		wg.Add(1)
 		go func(fURL string) {
@@ -0,0 +1,132 @@
+// Package embedding computes text embeddings with OpenAI-compatible or Ollama APIs.
+// Embeddings power related articles, semantic search and the clustering of
+// near-duplicate articles (the same story syndicated across feeds) into stories.
+package embedding
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/translation"
+)
+
+// Client computes embeddings using an OpenAI-compatible /embeddings endpoint
+// or the Ollama /api/embed endpoint. Both accept the same request body and the
+// response format is detected automatically.
+type Client struct {
+	APIKey   string
+	Endpoint string
+	Model    string
+	client   *http.Client
+}
+
+// NewClient creates a new embedding client.
+// endpoint should be the full API URL (e.g., "https://api.openai.com/v1/embeddings" for OpenAI,
+// "http://localhost:11434/api/embed" for Ollama).
+// db is optional - if nil, no proxy will be used.
+func NewClient(apiKey, endpoint, model string, db translation.DBInterface) *Client {
+	client := &http.Client{Timeout: 60 * time.Second}
+	if db != nil {
+		if proxyClient, err := translation.CreateHTTPClientWithProxy(db, 60*time.Second); err == nil {
+			client = proxyClient
+		}
+	}
+	return &Client{
+		APIKey:   apiKey,
+		Endpoint: strings.TrimSuffix(endpoint, "/"),
+		Model:    model,
+		client:   client,
+	}
+}
+
+// Embed returns one normalized embedding vector per input text, together with the
+// token usage reported by the API (zero if not reported).
+func (c *Client) Embed(texts []string) ([][]float32, aiusage.Usage, error) {
+	if len(texts) == 0 {
+		return nil, aiusage.Usage{Cached: true}, nil
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"model": c.Model,
+		"input": texts,
+	})
+	if err != nil {
+		return nil, aiusage.Usage{}, fmt.Errorf("failed to marshal embedding request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", c.Endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, aiusage.Usage{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Only add Authorization header if API key is provided
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, aiusage.Usage{}, fmt.Errorf("embedding request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		errorMsg := fmt.Sprintf("embedding API returned status: %d", resp.StatusCode)
+		if len(bodyBytes) > 0 {
+			errorMsg = fmt.Sprintf("%s - %s", errorMsg, string(bodyBytes))
+		}
+		return nil, aiusage.Usage{}, fmt.Errorf("%s", errorMsg)
+	}
+
+	var result struct {
+		// OpenAI format
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+		Usage struct {
+			PromptTokens int64 `json:"prompt_tokens"`
+		} `json:"usage"`
+		// Ollama format
+		Embeddings      [][]float32 `json:"embeddings"`
+		PromptEvalCount int64       `json:"prompt_eval_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, aiusage.Usage{}, fmt.Errorf("failed to decode embedding response: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	usage := aiusage.Usage{}
+	switch {
+	case len(result.Data) > 0:
+		for i, item := range result.Data {
+			index := item.Index
+			if index < 0 || index >= len(texts) {
+				index = i
+			}
+			vectors[index] = item.Embedding
+		}
+		usage.PromptTokens = result.Usage.PromptTokens
+	case len(result.Embeddings) > 0:
+		copy(vectors, result.Embeddings)
+		usage.PromptTokens = result.PromptEvalCount
+	default:
+		return nil, aiusage.Usage{}, fmt.Errorf("no embeddings found in response")
+	}
+
+	for i, v := range vectors {
+		if len(v) == 0 {
+			return nil, aiusage.Usage{}, fmt.Errorf("missing embedding for input %d", i)
+		}
+		vectors[i] = Normalize(v)
+	}
+
+	return vectors, usage, nil
+}
@@ -0,0 +1,305 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+// topicVector maps texts to fixed vectors by topic so similarity is predictable.
+func topicVector(text string) []float32 {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "earthquake"):
+		return []float32{1, 0.1, 0}
+	case strings.Contains(text, "election"):
+		return []float32{0, 1, 0.1}
+	default:
+		return []float32{0.1, 0, 1}
+	}
+}
+
+// newFakeServer returns an embedding server using the OpenAI or Ollama response format.
+func newFakeServer(t *testing.T, ollama bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ollama {
+			embeddings := make([][]float32, len(req.Input))
+			for i, text := range req.Input {
+				embeddings[i] = topicVector(text)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"model":             req.Model,
+				"embeddings":        embeddings,
+				"prompt_eval_count": 7,
+			})
+			return
+		}
+
+		// Return the items in reverse order to exercise index handling
+		data := make([]map[string]interface{}, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, map[string]interface{}{
+				"object":    "embedding",
+				"index":     i,
+				"embedding": topicVector(req.Input[i]),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":  data,
+			"usage": map[string]int{"prompt_tokens": 11, "total_tokens": 11},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestVectorEncodeAndSimilarity(t *testing.T) {
+	v := Normalize([]float32{3, 4})
+	if math.Abs(float64(v[0])-0.6) > 1e-6 || math.Abs(float64(v[1])-0.8) > 1e-6 {
+		t.Fatalf("unexpected normalized vector: %v", v)
+	}
+
+	decoded := Decode(Encode(v))
+	if len(decoded) != 2 || decoded[0] != v[0] || decoded[1] != v[1] {
+		t.Fatalf("round trip mismatch: %v != %v", decoded, v)
+	}
+
+	if s := Similarity(v, v); math.Abs(s-1) > 1e-6 {
+		t.Fatalf("expected similarity 1, got %f", s)
+	}
+	if s := Similarity(v, []float32{1, 0, 0}); s != 0 {
+		t.Fatalf("expected similarity 0 for different dimensions, got %f", s)
+	}
+}
+
+func TestClientEmbed(t *testing.T) {
+	tests := []struct {
+		name   string
+		ollama bool
+		tokens int64
+	}{
+		{"OpenAI", false, 11},
+		{"Ollama", true, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeServer(t, tt.ollama)
+			client := NewClient("key", server.URL, "test-model", nil)
+
+			vectors, usage, err := client.Embed([]string{"Earthquake hits", "Election results"})
+			if err != nil {
+				t.Fatalf("Embed: %v", err)
+			}
+			if len(vectors) != 2 {
+				t.Fatalf("expected 2 vectors, got %d", len(vectors))
+			}
+			if Similarity(vectors[0], Normalize(topicVector("earthquake"))) < 0.999 {
+				t.Errorf("first vector does not match the first input: %v", vectors[0])
+			}
+			if Similarity(vectors[1], Normalize(topicVector("election"))) < 0.999 {
+				t.Errorf("second vector does not match the second input: %v", vectors[1])
+			}
+			if usage.PromptTokens != tt.tokens {
+				t.Errorf("expected %d prompt tokens, got %d", tt.tokens, usage.PromptTokens)
+			}
+		})
+	}
+}
+
+func TestClientEmbedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid model", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewClient("", server.URL, "missing", nil)
+	if _, _, err := client.Embed([]string{"text"}); err == nil || !strings.Contains(err.Error(), "invalid model") {
+		t.Fatalf("expected error with response body, got %v", err)
+	}
+}
+
+func setupService(t *testing.T, endpoint string) (*Service, map[string]int64) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+
+	feedA, err := db.AddFeed(&models.Feed{Title: "A", URL: "https://a.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	feedB, err := db.AddFeed(&models.Feed{Title: "B", URL: "https://b.example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+
+	now := time.Now()
+	articles := []*models.Article{
+		{FeedID: feedA, Title: "Earthquake strikes the coast", URL: "https://a.example.com/1", PublishedAt: now.Add(-3 * time.Hour)},
+		{FeedID: feedB, Title: "Coast hit by strong earthquake", URL: "https://b.example.com/1", PublishedAt: now.Add(-2 * time.Hour)},
+		{FeedID: feedA, Title: "Election campaign begins", URL: "https://a.example.com/2", PublishedAt: now.Add(-time.Hour)},
+		{FeedID: feedB, Title: "New recipe for bread", URL: "https://b.example.com/2", PublishedAt: now},
+	}
+	if err := db.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	saved, err := db.GetArticles("", 0, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticles: %v", err)
+	}
+	ids := make(map[string]int64)
+	for _, a := range saved {
+		ids[strings.Fields(a.Title)[0]] = a.ID
+	}
+
+	if endpoint != "" {
+		db.SetSetting("embedding_enabled", "true")
+		db.SetSetting("embedding_endpoint", endpoint)
+		db.SetSetting("embedding_model", "test-model")
+		db.SetSetting("embedding_dedup_threshold", "0.9")
+	}
+	return NewService(db, nil), ids
+}
+
+func TestServiceNotConfigured(t *testing.T) {
+	service, ids := setupService(t, "")
+
+	if service.IsAvailable() {
+		t.Fatal("expected service to be unavailable")
+	}
+	if _, err := service.ProcessPending(10); err != ErrNotConfigured {
+		t.Errorf("ProcessPending: expected ErrNotConfigured, got %v", err)
+	}
+	if _, err := service.Related(ids["Earthquake"], 5); err != ErrNotConfigured {
+		t.Errorf("Related: expected ErrNotConfigured, got %v", err)
+	}
+	if _, err := service.Search("earthquake", 5); err != ErrNotConfigured {
+		t.Errorf("Search: expected ErrNotConfigured, got %v", err)
+	}
+	alternates, err := service.StoryAlternates(ids["Earthquake"])
+	if err != nil || len(alternates) != 0 {
+		t.Errorf("StoryAlternates: expected no alternates, got %v, %v", alternates, err)
+	}
+}
+
+func TestServiceClustersStories(t *testing.T) {
+	server := newFakeServer(t, false)
+	service, ids := setupService(t, server.URL)
+
+	processed, err := service.ProcessPending(10)
+	if err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+	if processed != 4 {
+		t.Fatalf("expected 4 articles processed, got %d", processed)
+	}
+
+	// The two earthquake reports are one story, led by the earliest
+	alternates, err := service.StoryAlternates(ids["Earthquake"])
+	if err != nil {
+		t.Fatalf("StoryAlternates: %v", err)
+	}
+	if len(alternates) != 1 || alternates[0] != ids["Coast"] {
+		t.Fatalf("expected earthquake story to contain the other report, got %v", alternates)
+	}
+	lead, err := service.db.GetArticleEmbedding(ids["Coast"])
+	if err != nil || lead == nil {
+		t.Fatalf("GetArticleEmbedding: %v", err)
+	}
+	if lead.StoryID != ids["Earthquake"] {
+		t.Errorf("expected story lead %d, got %d", ids["Earthquake"], lead.StoryID)
+	}
+
+	alternates, err = service.StoryAlternates(ids["Election"])
+	if err != nil || len(alternates) != 0 {
+		t.Fatalf("expected election article to stand alone, got %v, %v", alternates, err)
+	}
+
+	// Already embedded articles are not processed again
+	if processed, err := service.ProcessPending(10); err != nil || processed != 0 {
+		t.Fatalf("expected nothing to process, got %d, %v", processed, err)
+	}
+}
+
+func TestServiceRelatedAndSearch(t *testing.T) {
+	server := newFakeServer(t, true)
+	service, ids := setupService(t, server.URL)
+
+	// Related embeds the article on demand
+	related, err := service.Related(ids["Election"], 2)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(related) != 0 {
+		t.Fatalf("expected no related articles before others are embedded, got %v", related)
+	}
+
+	if _, err := service.ProcessPending(10); err != nil {
+		t.Fatalf("ProcessPending: %v", err)
+	}
+
+	related, err = service.Related(ids["Election"], 3)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	if len(related) != 3 {
+		t.Fatalf("expected 3 related articles, got %v", related)
+	}
+	for _, m := range related {
+		if m.ArticleID == ids["Election"] {
+			t.Fatal("related articles should not include the article itself")
+		}
+	}
+	for i := 1; i < len(related); i++ {
+		if related[i].Similarity > related[i-1].Similarity {
+			t.Fatalf("related articles not sorted by similarity: %v", related)
+		}
+	}
+
+	// Articles of the same story are alternates, not related articles
+	related, err = service.Related(ids["Earthquake"], 3)
+	if err != nil {
+		t.Fatalf("Related: %v", err)
+	}
+	for _, m := range related {
+		if m.ArticleID == ids["Coast"] {
+			t.Fatal("related articles should not include articles of the same story")
+		}
+	}
+
+	results, err := service.Search("earthquake damage", 5)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the 2 earthquake articles, got %v", results)
+	}
+	for _, m := range results {
+		if m.ArticleID != ids["Earthquake"] && m.ArticleID != ids["Coast"] {
+			t.Fatalf("unexpected search result %v", m)
+		}
+	}
+}
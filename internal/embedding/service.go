@@ -0,0 +1,388 @@
+package embedding
+
+import (
+	"errors"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// ErrNotConfigured is returned when embeddings are disabled or no provider is configured.
+var ErrNotConfigured = errors.New("no embedding provider configured")
+
+const (
+	// batchSize is the number of articles embedded per API request
+	batchSize = 32
+	// maxContentChars limits the article content included in the embedded text
+	maxContentChars = 2000
+	// pendingWindow limits background embedding to recently published articles
+	pendingWindow = 7 * 24 * time.Hour
+	// storyWindow is how far apart in publication time two articles of the same story may be
+	storyWindow = 72 * time.Hour
+	// searchCandidates is the number of most recent articles considered for related articles and search
+	searchCandidates = 5000
+	// minSearchSimilarity filters out unrelated search results
+	minSearchSimilarity = 0.3
+	// defaultDedupThreshold is used when embedding_dedup_threshold is not a valid similarity
+	defaultDedupThreshold = 0.9
+)
+
+// Match is an article matched by similarity.
+type Match struct {
+	ArticleID  int64
+	Similarity float64
+}
+
+// config holds the embedding provider settings.
+type config struct {
+	endpoint string
+	apiKey   string
+	model    string
+}
+
+// Service embeds articles and answers similarity queries.
+// All methods return ErrNotConfigured when no embedding provider is configured,
+// so callers can degrade gracefully.
+type Service struct {
+	db      *database.DB
+	tracker *aiusage.Tracker
+	mu      sync.Mutex // Serializes background processing
+}
+
+// NewService creates a new embedding service. tracker may be nil, in which case
+// usage is not tracked and the AI usage limit is not enforced.
+func NewService(db *database.DB, tracker *aiusage.Tracker) *Service {
+	return &Service{db: db, tracker: tracker}
+}
+
+// IsAvailable reports whether embeddings are enabled and a provider is configured.
+func (s *Service) IsAvailable() bool {
+	_, ok := s.loadConfig()
+	return ok
+}
+
+// loadConfig reads the embedding provider settings.
+// The AI API key is used when no embedding-specific key is set.
+func (s *Service) loadConfig() (config, bool) {
+	enabled, _ := s.db.GetSetting("embedding_enabled")
+	if enabled != "true" {
+		return config{}, false
+	}
+
+	var cfg config
+	cfg.endpoint, _ = s.db.GetSetting("embedding_endpoint")
+	cfg.model, _ = s.db.GetSetting("embedding_model")
+	cfg.apiKey, _ = s.db.GetEncryptedSetting("embedding_api_key")
+	if cfg.apiKey == "" {
+		cfg.apiKey, _ = s.db.GetEncryptedSetting("ai_api_key")
+	}
+	if cfg.endpoint == "" || cfg.model == "" {
+		return config{}, false
+	}
+	return cfg, true
+}
+
+// dedupThreshold returns the similarity above which two articles are the same story.
+func (s *Service) dedupThreshold() float64 {
+	thresholdStr, _ := s.db.GetSetting("embedding_dedup_threshold")
+	threshold, err := strconv.ParseFloat(thresholdStr, 64)
+	if err != nil || threshold <= 0 || threshold > 1 {
+		return defaultDedupThreshold
+	}
+	return threshold
+}
+
+// ProcessPending embeds recently published articles that have no embedding yet and
+// clusters them into stories. At most limit articles are processed. If processing
+// is already running, it returns immediately.
+func (s *Service) ProcessPending(limit int) (int, error) {
+	cfg, ok := s.loadConfig()
+	if !ok {
+		return 0, ErrNotConfigured
+	}
+	if !s.mu.TryLock() {
+		return 0, nil
+	}
+	defer s.mu.Unlock()
+
+	articles, err := s.db.GetArticlesWithoutEmbedding(cfg.model, time.Now().Add(-pendingWindow), limit)
+	if err != nil {
+		return 0, err
+	}
+	// Process the oldest articles first so that the first report of a story leads it
+	sort.Slice(articles, func(i, j int) bool {
+		return articles[i].PublishedAt.Before(articles[j].PublishedAt)
+	})
+
+	client := NewClient(cfg.apiKey, cfg.endpoint, cfg.model, s.db)
+	threshold := s.dedupThreshold()
+	processed := 0
+	for start := 0; start < len(articles); start += batchSize {
+		end := start + batchSize
+		if end > len(articles) {
+			end = len(articles)
+		}
+		batch := articles[start:end]
+
+		vectors, err := s.embedArticles(client, cfg, batch)
+		if err != nil {
+			return processed, err
+		}
+		for i, article := range batch {
+			if err := s.db.SaveArticleEmbedding(article.ID, cfg.model, Encode(vectors[i])); err != nil {
+				log.Printf("Failed to save embedding for article %d: %v", article.ID, err)
+				continue
+			}
+			if err := s.assignStory(cfg.model, article.ID, article.PublishedAt, vectors[i], threshold); err != nil {
+				log.Printf("Failed to cluster article %d: %v", article.ID, err)
+			}
+			processed++
+		}
+	}
+
+	if processed > 0 {
+		utils.DebugLog("Embedded %d articles", processed)
+	}
+	return processed, nil
+}
+
+// embedArticles computes the embeddings of a batch of articles and tracks the usage.
+func (s *Service) embedArticles(client *Client, cfg config, articles []models.Article) ([][]float32, error) {
+	if s.tracker != nil {
+		if s.tracker.IsLimitReached() {
+			return nil, errors.New("AI usage limit reached")
+		}
+		s.tracker.WaitForRateLimit()
+	}
+
+	texts := make([]string, len(articles))
+	for i, article := range articles {
+		texts[i] = s.articleText(article)
+	}
+
+	vectors, usage, err := client.Embed(texts)
+	if err != nil {
+		return nil, err
+	}
+	if s.tracker != nil {
+		s.tracker.TrackModelUsage(aiusage.FeatureEmbedding, cfg.endpoint, cfg.model, usage, strings.Join(texts, "\n"), "")
+	}
+	return vectors, nil
+}
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// articleText builds the text embedded for an article: its title followed by the
+// beginning of its cached content, if any.
+func (s *Service) articleText(article models.Article) string {
+	text := article.Title
+	content, found, err := s.db.GetArticleContent(article.ID)
+	if err != nil || !found {
+		return text
+	}
+
+	content = html.UnescapeString(htmlTagRegex.ReplaceAllString(content, " "))
+	content = strings.Join(strings.Fields(content), " ")
+	if runes := []rune(content); len(runes) > maxContentChars {
+		content = string(runes[:maxContentChars])
+	}
+	if content == "" {
+		return text
+	}
+	return text + "\n\n" + content
+}
+
+// assignStory clusters an article into the story of its most similar article published
+// within storyWindow, if their similarity reaches the threshold. The first article
+// of a story is its lead.
+func (s *Service) assignStory(model string, articleID int64, publishedAt time.Time, vector []float32, threshold float64) error {
+	candidates, err := s.db.GetEmbeddingsPublishedBetween(model, publishedAt.Add(-storyWindow), publishedAt.Add(storyWindow))
+	if err != nil {
+		return err
+	}
+
+	var best *database.ArticleEmbedding
+	bestSimilarity := threshold
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ArticleID == articleID {
+			continue
+		}
+		if similarity := Similarity(vector, Decode(candidate.Vector)); similarity >= bestSimilarity {
+			best = candidate
+			bestSimilarity = similarity
+		}
+	}
+	if best == nil {
+		return nil
+	}
+
+	storyID := best.StoryID
+	if storyID == 0 {
+		storyID = best.ArticleID
+		if err := s.db.SetArticleStoryID(best.ArticleID, storyID); err != nil {
+			return err
+		}
+	}
+	return s.db.SetArticleStoryID(articleID, storyID)
+}
+
+// articleEmbedding returns the stored embedding of an article for the configured model,
+// embedding the article on demand if needed.
+func (s *Service) articleEmbedding(cfg config, articleID int64) (*database.ArticleEmbedding, []float32, error) {
+	stored, err := s.db.GetArticleEmbedding(articleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if stored != nil && stored.Model == cfg.model {
+		return stored, Decode(stored.Vector), nil
+	}
+
+	article, err := s.db.GetArticleByID(articleID)
+	if err != nil {
+		return nil, nil, err
+	}
+	client := NewClient(cfg.apiKey, cfg.endpoint, cfg.model, s.db)
+	vectors, err := s.embedArticles(client, cfg, []models.Article{*article})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.db.SaveArticleEmbedding(articleID, cfg.model, Encode(vectors[0])); err != nil {
+		return nil, nil, err
+	}
+	if err := s.assignStory(cfg.model, articleID, article.PublishedAt, vectors[0], s.dedupThreshold()); err != nil {
+		log.Printf("Failed to cluster article %d: %v", articleID, err)
+	}
+
+	stored, err = s.db.GetArticleEmbedding(articleID)
+	if err != nil || stored == nil {
+		return nil, nil, err
+	}
+	return stored, vectors[0], nil
+}
+
+// Related returns up to limit articles most similar to the given article, most similar first.
+// Articles of the same story are excluded, since they are alternates rather than related articles.
+func (s *Service) Related(articleID int64, limit int) ([]Match, error) {
+	cfg, ok := s.loadConfig()
+	if !ok {
+		return nil, ErrNotConfigured
+	}
+
+	stored, vector, err := s.articleEmbedding(cfg, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil {
+		return []Match{}, nil
+	}
+
+	sameStory := func(candidate database.ArticleEmbedding) bool {
+		if stored.StoryID == 0 {
+			return false
+		}
+		return candidate.StoryID == stored.StoryID || candidate.ArticleID == stored.StoryID
+	}
+
+	candidates, err := s.db.GetRecentEmbeddings(cfg.model, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.ArticleID == articleID || sameStory(candidate) {
+			continue
+		}
+		matches = append(matches, Match{ArticleID: candidate.ArticleID, Similarity: Similarity(vector, Decode(candidate.Vector))})
+	}
+	return topMatches(matches, limit, 0), nil
+}
+
+// Search returns up to limit articles semantically matching the query, best match first.
+func (s *Service) Search(query string, limit int) ([]Match, error) {
+	cfg, ok := s.loadConfig()
+	if !ok {
+		return nil, ErrNotConfigured
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []Match{}, nil
+	}
+
+	if s.tracker != nil {
+		if s.tracker.IsLimitReached() {
+			return nil, errors.New("AI usage limit reached")
+		}
+		s.tracker.WaitForRateLimit()
+	}
+	client := NewClient(cfg.apiKey, cfg.endpoint, cfg.model, s.db)
+	vectors, usage, err := client.Embed([]string{query})
+	if err != nil {
+		return nil, err
+	}
+	if s.tracker != nil {
+		s.tracker.TrackModelUsage(aiusage.FeatureEmbedding, cfg.endpoint, cfg.model, usage, query, "")
+	}
+
+	candidates, err := s.db.GetRecentEmbeddings(cfg.model, searchCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(candidates))
+	for _, candidate := range candidates {
+		matches = append(matches, Match{ArticleID: candidate.ArticleID, Similarity: Similarity(vectors[0], Decode(candidate.Vector))})
+	}
+	return topMatches(matches, limit, minSearchSimilarity), nil
+}
+
+// StoryAlternates returns the IDs of the other articles in the same story as the given article.
+// It works without a configured provider, using the stories clustered so far.
+func (s *Service) StoryAlternates(articleID int64) ([]int64, error) {
+	stored, err := s.db.GetArticleEmbedding(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.StoryID == 0 {
+		return []int64{}, nil
+	}
+
+	ids, err := s.db.GetStoryArticleIDs(stored.StoryID)
+	if err != nil {
+		return nil, err
+	}
+	alternates := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id != articleID {
+			alternates = append(alternates, id)
+		}
+	}
+	return alternates, nil
+}
+
+// topMatches sorts matches by similarity and returns at most limit matches
+// with a similarity of at least minSimilarity.
+func topMatches(matches []Match, limit int, minSimilarity float64) []Match {
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Similarity > matches[j].Similarity
+	})
+	result := make([]Match, 0, limit)
+	for _, m := range matches {
+		if len(result) >= limit || m.Similarity < minSimilarity {
+			break
+		}
+		result = append(result, m)
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package embedding
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// Normalize scales a vector to unit length so that the cosine similarity of two
+// normalized vectors is their dot product. Zero vectors are returned unchanged.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := math.Sqrt(sum)
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
+
+// Similarity returns the cosine similarity of two normalized vectors.
+// Vectors of different dimensions (from different models) have similarity 0.
+func Similarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return dot
+}
+
+// Encode serializes a vector as little-endian float32 values for storage.
+func Encode(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(x))
+	}
+	return buf
+}
+
+// Decode deserializes a vector stored by Encode.
+func Decode(buf []byte) []float32 {
+	v := make([]float32, len(buf)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(buf[i*4:]))
+	}
+	return v
+}
@@ -0,0 +1,211 @@
+package feed
+
+import (
+	"log"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
+	"MrRSS/internal/langdetect"
+	"MrRSS/internal/models"
+	"MrRSS/internal/summary"
+	"MrRSS/internal/translation"
+	"MrRSS/internal/utils"
+)
+
+// AIPolicy is the effective translation and summary configuration for a feed.
+// It combines the feed's own overrides with the global settings.
+type AIPolicy struct {
+	TranslationEnabled bool
+	TargetLanguage     string
+	SummaryEnabled     bool
+	SummaryProvider    string // "local" or "ai"
+	SummaryLength      string // "short", "medium" or "long"
+	PregenerateOnFetch bool
+}
+
+// ResolveAIPolicy returns the effective AI policy for a feed.
+// Every value the feed leaves at 'global' (or empty) falls back to the global settings.
+// A nil feed yields the global policy.
+func ResolveAIPolicy(db *database.DB, feed *models.Feed) AIPolicy {
+	translationEnabled, _ := db.GetSetting("translation_enabled")
+	targetLanguage, _ := db.GetSetting("target_language")
+	summaryEnabled, _ := db.GetSetting("summary_enabled")
+	summaryProvider, _ := db.GetSetting("summary_provider")
+	summaryLength, _ := db.GetSetting("summary_length")
+
+	policy := AIPolicy{
+		TranslationEnabled: translationEnabled == "true",
+		TargetLanguage:     targetLanguage,
+		SummaryEnabled:     summaryEnabled == "true",
+		SummaryProvider:    summaryProvider,
+		SummaryLength:      summaryLength,
+	}
+
+	if feed != nil {
+		switch feed.TranslationMode {
+		case "enabled":
+			policy.TranslationEnabled = true
+		case "disabled":
+			policy.TranslationEnabled = false
+		}
+		if feed.TranslationTargetLanguage != "" {
+			policy.TargetLanguage = feed.TranslationTargetLanguage
+		}
+		if feed.SummaryProvider == "local" || feed.SummaryProvider == "ai" {
+			policy.SummaryProvider = feed.SummaryProvider
+		}
+		if feed.SummaryLength == "short" || feed.SummaryLength == "medium" || feed.SummaryLength == "long" {
+			policy.SummaryLength = feed.SummaryLength
+		}
+		policy.PregenerateOnFetch = feed.PregenerateOnFetch
+	}
+
+	if policy.TargetLanguage == "" {
+		policy.TargetLanguage = "en"
+	}
+	if policy.SummaryProvider != "ai" {
+		policy.SummaryProvider = "local"
+	}
+	if policy.SummaryLength == "" {
+		policy.SummaryLength = "medium"
+	}
+
+	return policy
+}
+
+// SummaryLengthValue converts the policy's summary length into a summary.SummaryLength.
+func (p AIPolicy) SummaryLengthValue() summary.SummaryLength {
+	switch p.SummaryLength {
+	case "short":
+		return summary.Short
+	case "long":
+		return summary.Long
+	default:
+		return summary.Medium
+	}
+}
+
+// SetAITracker sets the AI usage tracker used when pre-generating translations
+// and summaries during refresh. Without a tracker, AI providers are not used.
+func (f *Fetcher) SetAITracker(tracker *aiusage.Tracker) {
+	f.aiTracker = tracker
+}
+
+// pregenerateAIContent translates titles and generates summaries for the articles a
+// refresh just inserted, of feeds that opted into pre-generation on fetch.
+func (f *Fetcher) pregenerateAIContent(feed models.Feed, newArticles []*ArticleWithContent) {
+	if len(newArticles) == 0 {
+		return
+	}
+	policy := ResolveAIPolicy(f.db, &feed)
+	if !policy.PregenerateOnFetch || (!policy.TranslationEnabled && !policy.SummaryEnabled) {
+		return
+	}
+
+	translated, summarized := 0, 0
+	for _, awc := range newArticles {
+		article := awc.Article
+
+		if policy.TranslationEnabled && article.TranslatedTitle == "" {
+			if f.pregenerateTranslation(article, policy.TargetLanguage) {
+				translated++
+			}
+		}
+
+		if policy.SummaryEnabled && article.Summary == "" && awc.Content != "" {
+			if f.pregenerateSummary(article.ID, awc.Content, policy) {
+				summarized++
+			}
+		}
+	}
+
+	if translated > 0 || summarized > 0 {
+		utils.DebugLog("Pre-generated %d translations and %d summaries for feed %s", translated, summarized, feed.Title)
+	}
+}
+
+// pregenerateTranslation translates an article title into the target language.
+// Articles already in the target language keep their original title.
+func (f *Fetcher) pregenerateTranslation(article *models.Article, targetLang string) bool {
+	if langdetect.Matches(article.Lang, targetLang) {
+		if err := f.db.UpdateArticleTranslation(article.ID, article.Title); err != nil {
+			log.Printf("Error updating article translation: %v", err)
+			return false
+		}
+		return true
+	}
+
+	if f.translator == nil {
+		return false
+	}
+
+	provider, _ := f.db.GetSetting("translation_provider")
+	isAIProvider := provider == "ai"
+	if isAIProvider {
+		// Never spend AI tokens in the background once the limit is reached
+		if f.aiTracker == nil || f.aiTracker.IsLimitReached() {
+			return false
+		}
+		f.aiTracker.WaitForRateLimit()
+	}
+
+	translatedTitle, usage, err := translation.TranslateWithUsage(f.translator, article.Title, targetLang)
+	if err != nil {
+		log.Printf("Error pre-translating article %d: %v", article.ID, err)
+		return false
+	}
+	if isAIProvider {
+		f.aiTracker.TrackUsage(aiusage.FeatureTranslation, usage, article.Title, translatedTitle)
+	}
+
+	if err := f.db.UpdateArticleTranslation(article.ID, translatedTitle); err != nil {
+		log.Printf("Error updating article translation: %v", err)
+		return false
+	}
+	return true
+}
+
+// pregenerateSummary summarizes article content with the policy's provider and length.
+// Content too short to summarize is never sent to the AI provider. AI summaries fall back
+// to the local algorithm on errors or empty results, or when the usage limit is reached.
+func (f *Fetcher) pregenerateSummary(articleID int64, content string, policy AIPolicy) bool {
+	length := policy.SummaryLengthValue()
+
+	result := summary.NewSummarizer().Summarize(content, length)
+	if result.IsTooShort || result.Summary == "" {
+		return false
+	}
+
+	if policy.SummaryProvider == "ai" && f.aiTracker != nil && !f.aiTracker.IsLimitReached() {
+		apiKey, _ := f.db.GetEncryptedSetting("ai_api_key")
+		endpoint, _ := f.db.GetSetting("ai_endpoint")
+		model, _ := f.db.GetSetting("ai_model")
+		systemPrompt, _ := f.db.GetSetting("ai_summary_prompt")
+		customHeaders, _ := f.db.GetSetting("ai_custom_headers")
+
+		aiSummarizer := summary.NewAISummarizerWithDB(apiKey, endpoint, model, f.db)
+		if systemPrompt != "" {
+			aiSummarizer.SetSystemPrompt(systemPrompt)
+		}
+		if customHeaders != "" {
+			aiSummarizer.SetCustomHeaders(customHeaders)
+		}
+
+		f.aiTracker.WaitForRateLimit()
+		aiResult, err := aiSummarizer.Summarize(content, length)
+		if err != nil {
+			log.Printf("Error pre-generating AI summary for article %d, falling back to local: %v", articleID, err)
+		} else {
+			f.aiTracker.TrackUsage(aiusage.FeatureSummary, aiResult.Usage, content, aiResult.Summary)
+			if !aiResult.IsTooShort && aiResult.Summary != "" {
+				result = aiResult
+			}
+		}
+	}
+
+	if err := f.db.UpdateArticleSummary(articleID, result.Summary); err != nil {
+		log.Printf("Failed to cache summary for article %d: %v", articleID, err)
+		return false
+	}
+	return true
+}
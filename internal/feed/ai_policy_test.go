@@ -0,0 +1,119 @@
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
+)
+
+func setupPolicyDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	return db
+}
+
+func TestResolveAIPolicy(t *testing.T) {
+	db := setupPolicyDB(t)
+	db.SetSetting("translation_enabled", "false")
+	db.SetSetting("target_language", "zh")
+	db.SetSetting("summary_provider", "ai")
+	db.SetSetting("summary_length", "long")
+
+	global := ResolveAIPolicy(db, nil)
+	if global.TranslationEnabled || global.TargetLanguage != "zh" || global.SummaryProvider != "ai" || global.SummaryLength != "long" {
+		t.Fatalf("unexpected global policy: %+v", global)
+	}
+
+	inherit := ResolveAIPolicy(db, &models.Feed{TranslationMode: "global", SummaryProvider: "global", SummaryLength: "global"})
+	if inherit != global {
+		t.Fatalf("feed with global overrides should match global policy: %+v vs %+v", inherit, global)
+	}
+
+	override := ResolveAIPolicy(db, &models.Feed{
+		TranslationMode:           "enabled",
+		TranslationTargetLanguage: "en",
+		SummaryProvider:           "local",
+		SummaryLength:             "short",
+		PregenerateOnFetch:        true,
+	})
+	if !override.TranslationEnabled || override.TargetLanguage != "en" || override.SummaryProvider != "local" || override.SummaryLength != "short" || !override.PregenerateOnFetch {
+		t.Fatalf("unexpected overridden policy: %+v", override)
+	}
+
+	db.SetSetting("translation_enabled", "true")
+	disabled := ResolveAIPolicy(db, &models.Feed{TranslationMode: "disabled"})
+	if disabled.TranslationEnabled {
+		t.Fatal("expected translation to be disabled by feed override")
+	}
+}
+
+func TestPregenerateAIContent(t *testing.T) {
+	db := setupPolicyDB(t)
+	db.SetSetting("translation_enabled", "false")
+	db.SetSetting("translation_provider", "google")
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Mixed", URL: "http://example.com/mixed"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.UpdateFeedAIPolicy(feedID, "enabled", "en", "global", "global", true); err != nil {
+		t.Fatalf("UpdateFeedAIPolicy error: %v", err)
+	}
+	feed, err := db.GetFeedByID(feedID)
+	if err != nil {
+		t.Fatalf("GetFeedByID error: %v", err)
+	}
+	if feed.TranslationMode != "enabled" || feed.TranslationTargetLanguage != "en" || !feed.PregenerateOnFetch {
+		t.Fatalf("feed policy not persisted: %+v", feed)
+	}
+
+	now := time.Now()
+	japanese := &models.Article{FeedID: feedID, Title: "東京で新しいロボットが発表されました", URL: "http://example.com/ja", PublishedAt: now, HasValidPublishedTime: true, Lang: "ja"}
+	english := &models.Article{FeedID: feedID, Title: "New robots were announced in Tokyo today", URL: "http://example.com/en", PublishedAt: now, HasValidPublishedTime: true, Lang: "en"}
+	if err := db.SaveArticles(context.Background(), []*models.Article{japanese, english}); err != nil {
+		t.Fatalf("SaveArticles error: %v", err)
+	}
+
+	// Only the articles inserted by a refresh are pre-generated
+	stored := &models.Article{FeedID: feedID, Title: english.Title, URL: english.URL, PublishedAt: now, HasValidPublishedTime: true}
+	if err := db.SaveArticles(context.Background(), []*models.Article{stored}); err != nil {
+		t.Fatalf("SaveArticles error: %v", err)
+	}
+	saved := newArticles([]*ArticleWithContent{{Article: japanese}, {Article: english}, {Article: stored}})
+	if len(saved) != 2 {
+		t.Fatalf("expected 2 new articles, got %d", len(saved))
+	}
+
+	f := &Fetcher{db: db, translator: translation.NewMockTranslator()}
+	f.pregenerateAIContent(*feed, saved)
+
+	articles, err := db.GetArticles("", feedID, "", true, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticles error: %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(articles))
+	}
+	for _, a := range articles {
+		switch a.Lang {
+		case "ja":
+			if a.TranslatedTitle != "[EN] "+japanese.Title {
+				t.Errorf("expected Japanese title to be translated, got %q", a.TranslatedTitle)
+			}
+		case "en":
+			if a.TranslatedTitle != english.Title {
+				t.Errorf("expected English title to be kept, got %q", a.TranslatedTitle)
+			}
+		}
+	}
+}
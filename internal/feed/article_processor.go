@@ -1,6 +1,7 @@
 package feed
 
 import (
+	"MrRSS/internal/langdetect"
 	"MrRSS/internal/models"
 	"MrRSS/internal/utils"
 	"regexp"
@@ -34,6 +35,19 @@ func ExtractContent(item *gofeed.Item) string {
 type ArticleWithContent struct {
 	Article *models.Article
 	Content string
+	Episode *models.PodcastEpisode // Podcast metadata, nil if the item has no audio
+	Video   *models.VideoInfo      // Video metadata, nil if the item is not a video
+}
+
+// newArticles returns the articles that were inserted when saved, which have their ID set
+func newArticles(articlesWithContent []*ArticleWithContent) []*ArticleWithContent {
+	var inserted []*ArticleWithContent
+	for _, awc := range articlesWithContent {
+		if awc.Article.ID != 0 {
+			inserted = append(inserted, awc)
+		}
+	}
+	return inserted
 }
 
 // processArticles processes RSS feed items and converts them to Article models
@@ -82,6 +96,10 @@ func (f *Fetcher) processArticles(feed models.Feed, items []*gofeed.Item) []*Art
 		// Doing it here for all articles during refresh causes massive performance issues
 		translatedTitle := "" // Always empty - translation happens on-demand in frontend
 
+		// Detect the article language locally from title and content so that
+		// translation can be skipped for articles already in the target language
+		lang := langdetect.Detect(title + "\n" + content)
+
 		article := &models.Article{
 			FeedID:                feed.ID,
 			Title:                 title,
@@ -92,11 +110,19 @@ func (f *Fetcher) processArticles(feed models.Feed, items []*gofeed.Item) []*Art
 			PublishedAt:           published,
 			HasValidPublishedTime: hasValidPublishedTime,
 			TranslatedTitle:       translatedTitle,
+			Lang:                  lang,
+		}
+
+		var episode *models.PodcastEpisode
+		if audioURL != "" {
+			episode = extractPodcastEpisode(item, audioURL)
 		}
 
 		articlesWithContent = append(articlesWithContent, &ArticleWithContent{
 			Article: article,
 			Content: content,
+			Episode: episode,
+			Video:   extractVideoInfo(item, videoURL),
 		})
 	}
 
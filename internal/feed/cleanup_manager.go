@@ -4,6 +4,8 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"MrRSS/internal/database"
 )
 
 // CleanupManager manages automatic cleanup with retry mechanism
@@ -143,6 +145,18 @@ func (cm *CleanupManager) executeCleanup() {
 	// Execute layered cleanup with 80% target
 	totalRemoved := cm.layeredCleanup(maxSizeMB * 0.8)
 
+	// Remove the data of deleted articles and expire the fetch log whatever the database size
+	if count, err := cm.fetcher.db.CleanupOrphanedArticleData(); err != nil {
+		log.Printf("Error cleaning up orphaned article data: %v", err)
+	} else {
+		totalRemoved += count
+	}
+	if count, err := cm.fetcher.db.CleanupOldFeedFetches(database.FeedFetchRetentionDays); err != nil {
+		log.Printf("Error cleaning up feed fetch log: %v", err)
+	} else {
+		totalRemoved += count
+	}
+
 	if totalRemoved > 0 {
 		log.Printf("Automatic cleanup completed: removed %d items", totalRemoved)
 	} else {
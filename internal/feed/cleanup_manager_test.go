@@ -0,0 +1,67 @@
+package feed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestExecuteCleanup(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	feedID, _ := db.AddFeed(&models.Feed{Title: "logged", URL: "http://example.com/logged"})
+	now := time.Now()
+	for _, fetchedAt := range []time.Time{now.AddDate(0, 0, -database.FeedFetchRetentionDays-1), now.Add(-time.Hour)} {
+		if err := db.RecordFeedFetch(&models.FeedFetch{FeedID: feedID, FetchedAt: fetchedAt, Reason: TaskReasonScheduledGlobal.String()}); err != nil {
+			t.Fatalf("RecordFeedFetch: %v", err)
+		}
+	}
+
+	// Data of a stored article and of a deleted one
+	article := &models.Article{FeedID: feedID, Title: "kept", URL: "http://example.com/kept", PublishedAt: now}
+	if _, err := db.SaveNewArticles(context.Background(), []*models.Article{article}); err != nil {
+		t.Fatalf("SaveNewArticles: %v", err)
+	}
+	deletedID := article.ID + 1
+	for _, articleID := range []int64{article.ID, deletedID} {
+		for _, query := range []string{
+			`INSERT INTO article_embeddings (article_id, model, vector) VALUES (?, 'model', x'00')`,
+			`INSERT INTO article_tags (article_id, tag) VALUES (?, 'tag')`,
+			`INSERT INTO podcast_episodes (article_id) VALUES (?)`,
+			`INSERT INTO video_info (article_id) VALUES (?)`,
+		} {
+			if _, err := db.Exec(query, articleID); err != nil {
+				t.Fatalf("insert article data: %v", err)
+			}
+		}
+	}
+
+	NewCleanupManager(NewFetcher(db, nil)).executeCleanup()
+
+	for _, table := range []string{"article_embeddings", "article_tags", "podcast_episodes", "video_info"} {
+		var kept, orphaned int
+		_ = db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE article_id = ?`, article.ID).Scan(&kept)
+		_ = db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE article_id = ?`, deletedID).Scan(&orphaned)
+		if kept != 1 || orphaned != 0 {
+			t.Errorf("%s after cleanup: %d rows of the stored article, %d of the deleted one", table, kept, orphaned)
+		}
+	}
+
+	fetches, err := db.GetFeedFetches(feedID, 10)
+	if err != nil {
+		t.Fatalf("GetFeedFetches: %v", err)
+	}
+	if len(fetches) != 1 || fetches[0].FetchedAt.Before(now.AddDate(0, 0, -1)) {
+		t.Errorf("fetches after cleanup = %+v", fetches)
+	}
+}
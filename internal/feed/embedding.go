@@ -0,0 +1,28 @@
+package feed
+
+import (
+	"errors"
+	"log"
+
+	"MrRSS/internal/embedding"
+)
+
+// embeddingBatchLimit is the maximum number of articles embedded after each feed refresh
+const embeddingBatchLimit = 200
+
+// SetEmbeddingService sets the embedding service used to embed new articles after refresh.
+// Without a service, articles are not embedded in the background.
+func (f *Fetcher) SetEmbeddingService(service *embedding.Service) {
+	f.embeddings = service
+}
+
+// embedNewArticles embeds recently saved articles and clusters near-duplicates into stories.
+// It does nothing when no embedding provider is configured.
+func (f *Fetcher) embedNewArticles() {
+	if f.embeddings == nil {
+		return
+	}
+	if _, err := f.embeddings.ProcessPending(embeddingBatchLimit); err != nil && !errors.Is(err, embedding.ErrNotConfigured) {
+		log.Printf("Error embedding articles: %v", err)
+	}
+}
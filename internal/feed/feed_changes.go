@@ -0,0 +1,169 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+// Thresholds of the automatic handling of moved, gone and failing feeds
+const (
+	RedirectObservations = 3                // Consecutive fetches ending at the same permanent redirect target before the feed URL is updated
+	BackoffAfterFailures = 3                // Consecutive failures after which scheduled refreshes back off
+	BackoffBase          = 30 * time.Minute // First backoff, doubled with every further failure
+	BackoffMax           = 24 * time.Hour   // Longest backoff
+)
+
+// maxRedirects is the number of redirects followed when fetching a feed, like the default of net/http
+const maxRedirects = 10
+
+var errTooManyRedirects = errors.New("stopped after 10 redirects")
+
+// isPermanentRedirect reports whether an HTTP status moves a resource for good
+func isPermanentRedirect(statusCode int) bool {
+	return statusCode == http.StatusMovedPermanently || statusCode == http.StatusPermanentRedirect
+}
+
+// BackoffDelay returns how long scheduled refreshes of a feed are skipped after a number
+// of consecutive failures, or 0 below BackoffAfterFailures
+func BackoffDelay(failures int) time.Duration {
+	if failures < BackoffAfterFailures {
+		return 0
+	}
+	delay := BackoffBase
+	for i := BackoffAfterFailures; i < failures && delay < BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > BackoffMax {
+		delay = BackoffMax
+	}
+	return delay
+}
+
+// InBackoff reports whether the scheduled refreshes of a consistently failing feed are still backed off
+func InBackoff(feed models.Feed, now time.Time) bool {
+	return feed.BackoffUntil != nil && now.Before(*feed.BackoffUntil)
+}
+
+// handleRefreshResult follows up on the result of a refresh: it updates the URL of feeds
+// that moved permanently, marks feeds answering 410 Gone and backs off consistently
+// failing feeds. Each of these changes is recorded so that it can be undone.
+func (f *Fetcher) handleRefreshResult(feedID int64, err error) {
+	// Rate limited feeds are held back by the host limits instead
+	var rateErr *RateLimitedError
+	if errors.Is(err, context.Canceled) || errors.As(err, &rateErr) {
+		return
+	}
+	// The feed of a queued task may be outdated, so start from the stored one
+	feed, getErr := f.db.GetFeedByID(feedID)
+	if getErr != nil || feed.IsFreshRSSSource {
+		return
+	}
+	if err != nil {
+		f.handleRefreshFailure(*feed)
+	} else {
+		f.handleRefreshSuccess(*feed)
+	}
+}
+
+func (f *Fetcher) handleRefreshSuccess(feed models.Feed) {
+	if feed.ConsecutiveFailures > 0 || feed.BackoffUntil != nil {
+		if err := f.db.ResetFeedFailures(feed.ID); err != nil {
+			log.Printf("Error resetting failures of feed %s: %v", feed.Title, err)
+		}
+		if err := f.db.EndFeedChanges(feed.ID, database.FeedChangeBackoff); err != nil {
+			log.Printf("Error ending backoff of feed %s: %v", feed.Title, err)
+		}
+	}
+	if feed.IsGone {
+		log.Printf("Feed %s is back, no longer marking it as gone", feed.Title)
+		if err := f.db.SetFeedGone(feed.ID, false); err != nil {
+			log.Printf("Error clearing gone mark of feed %s: %v", feed.Title, err)
+		}
+		if err := f.db.EndFeedChanges(feed.ID, database.FeedChangeGone); err != nil {
+			log.Printf("Error ending gone mark of feed %s: %v", feed.Title, err)
+		}
+	}
+	f.followPermanentRedirect(feed)
+}
+
+// followPermanentRedirect updates the URL of a feed once its latest fetches were all
+// permanently redirected to the same URL
+func (f *Fetcher) followPermanentRedirect(feed models.Feed) {
+	fetches, err := f.db.GetFeedFetches(feed.ID, RedirectObservations)
+	if err != nil || len(fetches) < RedirectObservations {
+		return
+	}
+	target := fetches[0].RedirectURL
+	if target == "" || target == feed.URL {
+		return
+	}
+	for _, fetch := range fetches {
+		if fetch.RedirectURL != target || fetch.ErrorClass != "" {
+			return
+		}
+	}
+
+	// Respect an earlier undo, and never turn the feed into a duplicate subscription
+	if undone, err := f.db.HasUndoneFeedChange(feed.ID, database.FeedChangeRedirect, target); err != nil || undone {
+		return
+	}
+	urls, err := f.db.GetAllFeedURLs()
+	if err != nil || urls[target] {
+		return
+	}
+
+	log.Printf("Feed %s moved permanently, updating its URL from %s to %s", feed.Title, feed.URL, target)
+	if err := f.db.UpdateFeedURL(feed.ID, target); err != nil {
+		log.Printf("Error updating URL of feed %s: %v", feed.Title, err)
+		return
+	}
+	f.recordFeedChange(feed, database.FeedChangeRedirect, feed.URL, target)
+}
+
+func (f *Fetcher) handleRefreshFailure(feed models.Feed) {
+	if !feed.IsGone {
+		fetches, err := f.db.GetFeedFetches(feed.ID, 1)
+		if err == nil && len(fetches) == 1 && fetches[0].StatusCode == http.StatusGone {
+			// Respect an earlier undo, which asks to keep refreshing the feed
+			if undone, err := f.db.HasUndoneFeedChange(feed.ID, database.FeedChangeGone, ""); err == nil && !undone {
+				log.Printf("Feed %s is gone, no longer refreshing it on schedule", feed.Title)
+				if err := f.db.SetFeedGone(feed.ID, true); err != nil {
+					log.Printf("Error marking feed %s as gone: %v", feed.Title, err)
+				} else {
+					f.recordFeedChange(feed, database.FeedChangeGone, "", "")
+				}
+			}
+		}
+	}
+
+	failures, err := f.db.RecordFeedFailure(feed.ID)
+	if err != nil {
+		log.Printf("Error recording failure of feed %s: %v", feed.Title, err)
+		return
+	}
+	delay := BackoffDelay(failures)
+	if delay == 0 {
+		return
+	}
+	if err := f.db.SetFeedBackoff(feed.ID, time.Now().Add(delay)); err != nil {
+		log.Printf("Error backing off feed %s: %v", feed.Title, err)
+		return
+	}
+	if feed.BackoffUntil == nil {
+		log.Printf("Feed %s failed %d times in a row, backing off its scheduled refreshes", feed.Title, failures)
+		f.recordFeedChange(feed, database.FeedChangeBackoff, "", "")
+	}
+}
+
+func (f *Fetcher) recordFeedChange(feed models.Feed, kind, oldValue, newValue string) {
+	change := &models.FeedChange{FeedID: feed.ID, Kind: kind, OldValue: oldValue, NewValue: newValue}
+	if err := f.db.RecordFeedChange(change); err != nil {
+		log.Printf("Error recording change of feed %s: %v", feed.Title, err)
+	}
+}
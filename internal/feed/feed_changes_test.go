@@ -0,0 +1,163 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{2, 0},
+		{3, 30 * time.Minute},
+		{4, time.Hour},
+		{6, 4 * time.Hour},
+		{9, BackoffMax},
+		{100, BackoffMax},
+	}
+	for _, tt := range tests {
+		if got := BackoffDelay(tt.failures); got != tt.want {
+			t.Errorf("BackoffDelay(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestHandleRefreshResult(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	rss := `<?xml version="1.0"?><rss><channel><title>Moved</title>` +
+		`<item><title>first</title><link>/1</link><guid>1</guid></item></channel></rss>`
+	var down atomic.Bool
+	down.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/temp":
+			http.Redirect(w, r, "/new", http.StatusFound)
+		case "/gone":
+			http.Error(w, "gone", http.StatusGone)
+		case "/flaky":
+			if down.Load() {
+				http.Error(w, "down", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(rss))
+		default:
+			w.Write([]byte(rss))
+		}
+	}))
+	defer srv.Close()
+
+	fetcher := NewFetcher(db, nil)
+	refresh := func(id int64) {
+		t.Helper()
+		feed, err := db.GetFeedByID(id)
+		if err != nil {
+			t.Fatalf("GetFeedByID: %v", err)
+		}
+		err = fetcher.fetchAndRecord(context.Background(), *feed, TaskReasonScheduledGlobal)
+		fetcher.handleRefreshResult(id, err)
+	}
+
+	t.Run("permanent redirect", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "moved", URL: srv.URL + "/old"})
+		for i := 0; i < RedirectObservations-1; i++ {
+			refresh(id)
+		}
+		if feed, _ := db.GetFeedByID(id); feed.URL != srv.URL+"/old" {
+			t.Fatalf("URL updated after %d observations: %s", RedirectObservations-1, feed.URL)
+		}
+		refresh(id)
+		if feed, _ := db.GetFeedByID(id); feed.URL != srv.URL+"/new" {
+			t.Fatalf("URL = %s, want %s", feed.URL, srv.URL+"/new")
+		}
+
+		changes, _ := db.GetFeedChanges(false)
+		if len(changes) != 1 || changes[0].Kind != database.FeedChangeRedirect || changes[0].OldValue != srv.URL+"/old" {
+			t.Fatalf("changes = %+v", changes)
+		}
+
+		// An undone redirect is not followed again
+		if err := db.UndoFeedChange(&changes[0]); err != nil {
+			t.Fatalf("UndoFeedChange: %v", err)
+		}
+		for i := 0; i < RedirectObservations; i++ {
+			refresh(id)
+		}
+		if feed, _ := db.GetFeedByID(id); feed.URL != srv.URL+"/old" {
+			t.Errorf("URL after undo = %s", feed.URL)
+		}
+	})
+
+	t.Run("temporary redirect", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "temp", URL: srv.URL + "/temp"})
+		for i := 0; i < RedirectObservations; i++ {
+			refresh(id)
+		}
+		if feed, _ := db.GetFeedByID(id); feed.URL != srv.URL+"/temp" {
+			t.Errorf("URL = %s, want it unchanged", feed.URL)
+		}
+	})
+
+	t.Run("gone", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "gone", URL: srv.URL + "/gone"})
+		refresh(id)
+		feed, _ := db.GetFeedByID(id)
+		if !feed.IsGone || feed.ConsecutiveFailures != 1 {
+			t.Errorf("feed = gone %v, %d failures", feed.IsGone, feed.ConsecutiveFailures)
+		}
+	})
+
+	t.Run("backoff", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "flaky", URL: srv.URL + "/flaky"})
+		for i := 0; i < BackoffAfterFailures+1; i++ {
+			refresh(id)
+		}
+		feed, _ := db.GetFeedByID(id)
+		if feed.IsGone || feed.ConsecutiveFailures != BackoffAfterFailures+1 || !InBackoff(*feed, time.Now()) {
+			t.Fatalf("feed = gone %v, %d failures, backoff %v", feed.IsGone, feed.ConsecutiveFailures, feed.BackoffUntil)
+		}
+		if feed.BackoffUntil.Before(time.Now().Add(BackoffBase)) {
+			t.Errorf("backoff until %v, want at least %v", feed.BackoffUntil, BackoffDelay(BackoffAfterFailures+1))
+		}
+
+		down.Store(false)
+		refresh(id)
+		feed, _ = db.GetFeedByID(id)
+		if feed.ConsecutiveFailures != 0 || feed.BackoffUntil != nil {
+			t.Errorf("feed after recovery = %d failures, backoff %v", feed.ConsecutiveFailures, feed.BackoffUntil)
+		}
+
+		all, _ := db.GetFeedChanges(true)
+		backoffs := 0
+		for _, c := range all {
+			if c.FeedID == id && c.Kind == database.FeedChangeBackoff {
+				backoffs++
+				if c.UndoneAt == nil {
+					t.Errorf("backoff change still open after recovery")
+				}
+			}
+		}
+		if backoffs != 1 {
+			t.Errorf("recorded %d backoff changes, want 1", backoffs)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package feed
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Classes of the errors recorded in the fetch log
+const (
+	ErrorClassTimeout    = "timeout"
+	ErrorClassCanceled   = "canceled"
+	ErrorClassDNS        = "dns"
+	ErrorClassConnection = "connection"
+	ErrorClassTLS        = "tls"
+	ErrorClassHTTP4xx    = "http_4xx"
+	ErrorClassHTTP5xx    = "http_5xx"
+	ErrorClassRateLimit  = "rate_limited" // 429, or 503 with Retry-After
+	ErrorClassParse      = "parse"
+	ErrorClassOther      = "other"
+)
+
+// fetchAttempt collects what a fetch attempt learned on its way, for the fetch log
+type fetchAttempt struct {
+	statusCode int
+	bytes      int64
+	newItems   int
+	// Target of the followed redirects, if they were all permanent
+	redirectURL string
+}
+
+type fetchAttemptKey struct{}
+
+// withFetchAttempt returns a context collecting the details of a fetch attempt
+func withFetchAttempt(ctx context.Context) (context.Context, *fetchAttempt) {
+	attempt := &fetchAttempt{}
+	return context.WithValue(ctx, fetchAttemptKey{}, attempt), attempt
+}
+
+// attemptFrom returns the fetch attempt of a context, or nil outside the task manager
+func attemptFrom(ctx context.Context) *fetchAttempt {
+	attempt, _ := ctx.Value(fetchAttemptKey{}).(*fetchAttempt)
+	return attempt
+}
+
+func (a *fetchAttempt) setResponse(statusCode int, bytes int64) {
+	if a != nil {
+		a.statusCode = statusCode
+		a.bytes = bytes
+	}
+}
+
+func (a *fetchAttempt) setRedirect(url string) {
+	if a != nil {
+		a.redirectURL = url
+	}
+}
+
+func (a *fetchAttempt) setNewItems(n int) {
+	if a != nil {
+		a.newItems = n
+	}
+}
+
+// fetchAndRecord fetches a feed like fetchFeedWithContext and records the attempt in the fetch log
+func (f *Fetcher) fetchAndRecord(ctx context.Context, feed models.Feed, reason TaskReason) error {
+	ctx, attempt := withFetchAttempt(ctx)
+	start := time.Now()
+	err := f.fetchFeedWithContext(ctx, feed)
+
+	fetch := &models.FeedFetch{
+		FeedID:      feed.ID,
+		FetchedAt:   start,
+		Reason:      reason.String(),
+		DurationMs:  time.Since(start).Milliseconds(),
+		StatusCode:  attempt.statusCode,
+		Bytes:       attempt.bytes,
+		NewItems:    attempt.newItems,
+		RedirectURL: attempt.redirectURL,
+	}
+	if err != nil {
+		fetch.ErrorClass = ClassifyFetchError(err, attempt.statusCode)
+		fetch.Error = err.Error()
+		var httpErr gofeed.HTTPError
+		if fetch.StatusCode == 0 && errors.As(err, &httpErr) {
+			fetch.StatusCode = httpErr.StatusCode
+		}
+	}
+	if recordErr := f.db.RecordFeedFetch(fetch); recordErr != nil {
+		log.Printf("Error recording fetch of feed %s: %v", feed.Title, recordErr)
+	}
+	return err
+}
+
+// ClassifyFetchError returns the class of a fetch error, given the HTTP status of the
+// response if one was received
+func ClassifyFetchError(err error, statusCode int) string {
+	var rateErr *RateLimitedError
+	if errors.As(err, &rateErr) {
+		return ErrorClassRateLimit
+	}
+	var httpErr gofeed.HTTPError
+	if errors.As(err, &httpErr) {
+		statusCode = httpErr.StatusCode
+	}
+	switch {
+	case statusCode >= 500:
+		return ErrorClassHTTP5xx
+	case statusCode >= 400:
+		return ErrorClassHTTP4xx
+	}
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var recordErr tls.RecordHeaderError
+	var opErr *net.OpError
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.As(err, &certErr), errors.As(err, &unknownAuthErr), errors.As(err, &hostnameErr), errors.As(err, &recordErr):
+		return ErrorClassTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &opErr):
+		return ErrorClassConnection
+	}
+
+	// Errors of scripts, XPath and the feed parser lose their type, so fall back to the message
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return ErrorClassTimeout
+	case strings.Contains(msg, "no such host"):
+		return ErrorClassDNS
+	case strings.Contains(msg, "tls:") || strings.Contains(msg, "x509:"):
+		return ErrorClassTLS
+	case strings.Contains(msg, "connection refused") || strings.Contains(msg, "connection reset") || strings.Contains(msg, "eof"):
+		return ErrorClassConnection
+	case statusCode == 200 || strings.Contains(msg, "xml syntax error") || strings.Contains(msg, "failed to detect feed type") ||
+		strings.Contains(msg, "parsing failed") || strings.Contains(msg, "not a valid"):
+		return ErrorClassParse
+	}
+	return ErrorClassOther
+}
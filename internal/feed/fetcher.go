@@ -1,7 +1,10 @@
 package feed
 
 import (
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/database"
+	"MrRSS/internal/embedding"
+	"MrRSS/internal/metrics"
 	"MrRSS/internal/models"
 	"MrRSS/internal/rules"
 	"MrRSS/internal/translation"
@@ -27,6 +30,8 @@ type Fetcher struct {
 	fp                FeedParser
 	highPriorityFp    FeedParser // High priority parser for content fetching
 	translator        translation.Translator
+	aiTracker         *aiusage.Tracker
+	embeddings        *embedding.Service
 	scriptExecutor    *ScriptExecutor
 	emailFetcher      *EmailFetcher
 	progress          Progress
@@ -34,6 +39,7 @@ type Fetcher struct {
 	refreshCalculator *IntelligentRefreshCalculator
 	taskManager       *TaskManager
 	cleanupManager    *CleanupManager
+	onRefreshComplete func()
 }
 
 func NewFetcher(db *database.DB, translator translation.Translator) *Fetcher {
@@ -106,6 +112,12 @@ func (f *Fetcher) GetCleanupManager() *CleanupManager {
 	return f.cleanupManager
 }
 
+// SetRefreshCompleteHandler sets a function that is run in the background
+// whenever all queued refresh tasks have completed.
+func (f *Fetcher) SetRefreshCompleteHandler(handler func()) {
+	f.onRefreshComplete = handler
+}
+
 // getDataDir returns the data directory path
 func (f *Fetcher) getDataDir() (string, error) {
 	return utils.GetDataDir()
@@ -221,33 +233,46 @@ func (f *Fetcher) FetchAll(ctx context.Context) {
 		return
 	}
 
-	// Filter out FreshRSS feeds - they are refreshed via sync, not standard refresh
+	// Filter out FreshRSS feeds - they are refreshed via sync, not standard refresh -
+	// and paused and gone feeds, which are only refreshed on demand
 	filteredFeeds := make([]models.Feed, 0, len(feeds))
 	freshRSSCount := 0
+	pausedCount := 0
 	for _, feed := range feeds {
 		if feed.IsFreshRSSSource {
 			freshRSSCount++
+		} else if feed.IsPaused || feed.IsGone {
+			pausedCount++
 		} else {
 			filteredFeeds = append(filteredFeeds, feed)
 		}
 	}
 
-	// If all feeds are FreshRSS feeds, no standard refresh needed
+	// If all feeds are FreshRSS or paused feeds, no standard refresh needed
 	if len(filteredFeeds) == 0 {
-		log.Printf("All %d feeds are FreshRSS sources (refreshed via sync only), skipping standard refresh", freshRSSCount)
+		log.Printf("All %d feeds are FreshRSS sources, paused or gone, skipping standard refresh", len(feeds))
 		// Mark progress as completed since there's nothing to do
 		f.taskManager.MarkCompleted()
 		return
 	}
 
-	log.Printf("Standard refresh: %d feeds (skipped %d FreshRSS feeds)", len(filteredFeeds), freshRSSCount)
+	log.Printf("Standard refresh: %d feeds (skipped %d FreshRSS feeds, %d paused or gone feeds)", len(filteredFeeds), freshRSSCount, pausedCount)
+	f.RefreshFeeds(ctx, filteredFeeds)
+}
+
+// RefreshFeeds refreshes the given feeds as a global refresh, at the tail of the queue
+func (f *Fetcher) RefreshFeeds(ctx context.Context, feeds []models.Feed) {
+	if len(feeds) == 0 {
+		f.taskManager.MarkCompleted()
+		return
+	}
 
 	// Update task manager capacity based on network
 	concurrency := f.getConcurrencyLimit()
 	f.taskManager.SetPoolCapacity(concurrency)
 
 	// Use task manager for global refresh (all feeds go to queue tail)
-	f.taskManager.AddGlobalRefresh(ctx, filteredFeeds)
+	f.taskManager.AddGlobalRefresh(ctx, feeds)
 }
 
 func (f *Fetcher) FetchFeed(ctx context.Context, feed models.Feed) {
@@ -301,6 +326,8 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed models.Feed) {
 		} else {
 			// Cache article content from RSS feed
 			f.cacheArticleContents(articlesWithContent)
+			f.savePodcastEpisodes(articlesWithContent)
+			pendingTranscripts := f.saveVideoInfo(articlesWithContent)
 
 			// Apply rules to newly saved articles
 			// We fetch the recent articles for this feed since SaveArticles doesn't return IDs
@@ -315,6 +342,17 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed models.Feed) {
 					utils.DebugLog("Applied rules to %d articles in feed %s", affected, feed.Title)
 				}
 			}
+
+			go func() {
+				// Fetch video transcripts first so that they are summarized and embedded
+				f.fetchVideoTranscripts(feed, pendingTranscripts)
+
+				// Translate and summarize new articles if the feed opted in
+				f.pregenerateAIContent(feed, newArticles(articlesWithContent))
+
+				// Embed new articles for related articles and story deduplication
+				f.embedNewArticles()
+			}()
 		}
 	}
 	utils.DebugLog("Updated feed: %s", feed.Title)
@@ -324,7 +362,9 @@ func (f *Fetcher) FetchFeed(ctx context.Context, feed models.Feed) {
 // Returns error instead of storing in progress.Errors
 func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) error {
 	// Use ParseFeedWithFeed with normal priority for feed refresh
+	start := time.Now()
 	parsedFeed, err := f.ParseFeedWithFeed(ctx, &feed, false)
+	metrics.ObserveFetch(fetchType(feed), time.Since(start), err)
 	if err != nil {
 		return err
 	}
@@ -349,6 +389,29 @@ func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) er
 		f.db.UpdateFeedLink(feed.ID, parsedFeed.Link)
 	}
 
+	return f.saveParsedFeed(ctx, feed, parsedFeed)
+}
+
+// fetchType returns how a feed is fetched, the feed type of the fetch metrics
+func fetchType(feed models.Feed) string {
+	switch {
+	case feed.Type == "email":
+		return "email"
+	case feed.Type == "imported":
+		return "imported"
+	case feed.ScriptPath != "":
+		return "script"
+	case feed.Type == "HTML+XPath" || feed.Type == "XML+XPath":
+		return "xpath"
+	case feed.IsFreshRSSSource:
+		return "freshrss"
+	}
+	return "rss"
+}
+
+// saveParsedFeed saves the new articles of a parsed feed, then applies the rules and
+// runs the post-processing of new articles in the background
+func (f *Fetcher) saveParsedFeed(ctx context.Context, feed models.Feed, parsedFeed *gofeed.Feed) error {
 	// Check context before processing articles
 	select {
 	case <-ctx.Done():
@@ -373,16 +436,26 @@ func (f *Fetcher) fetchFeedWithContext(ctx context.Context, feed models.Feed) er
 			articlesToSave[i] = awc.Article
 		}
 
-		if err := f.db.SaveArticles(ctx, articlesToSave); err != nil {
+		newItems, err := f.db.SaveNewArticles(ctx, articlesToSave)
+		if err != nil {
 			return err
 		}
+		attemptFrom(ctx).setNewItems(newItems)
 
 		// Post-processing operations (content caching and rule application)
 		// These are non-critical and run asynchronously to avoid blocking the feed refresh
 		// Even if they fail or are slow, the feed has already been successfully saved
 		go func() {
-			// Cache article content from RSS feed
+			// Cache article content, podcast and video metadata from RSS feed
 			f.cacheArticleContents(articlesWithContent)
+			f.savePodcastEpisodes(articlesWithContent)
+			pendingTranscripts := f.saveVideoInfo(articlesWithContent)
+
+			// Fetch video transcripts, then translate and summarize new articles if
+			// the feed opted in, then embed them for related articles and story deduplication
+			defer f.embedNewArticles()
+			defer f.pregenerateAIContent(feed, newArticles(articlesWithContent))
+			defer f.fetchVideoTranscripts(feed, pendingTranscripts)
 
 			// Apply rules to newly saved articles
 			savedArticles, err := f.db.GetArticles("", feed.ID, "", false, len(articlesToSave), 0)
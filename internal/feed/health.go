@@ -0,0 +1,150 @@
+package feed
+
+import (
+	"sort"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// Health statuses of feeds, from the fetch log and their newest items
+const (
+	HealthHealthy = "healthy"
+	HealthUnknown = "unknown" // Not fetched within the health window
+	HealthFailing = "failing" // The latest attempts failed
+	HealthDead    = "dead"    // Failing for a long time
+	HealthStale   = "stale"   // Fetched fine, but without new items for a long time
+	HealthPaused  = "paused"
+	HealthGone    = "gone" // Answered 410 Gone, so no longer refreshed on schedule
+)
+
+// Thresholds of the health statuses
+const (
+	HealthWindowDays  = 30 // Fetch attempts considered for the success rate and latency
+	DeadAfterFailures = 5  // Consecutive failures, together with DeadAfterDays, that make a feed dead
+	DeadAfterDays     = 7  // Days of consecutive failures, together with DeadAfterFailures, that make a feed dead
+	StaleAfterDays    = 90 // Days without a new item that make a feed stale
+)
+
+// Health is the health of a feed computed from its fetch attempts
+type Health struct {
+	FeedID              int64      `json:"feed_id"`
+	Title               string     `json:"title"`
+	URL                 string     `json:"url"`
+	Category            string     `json:"category"`
+	Status              string     `json:"status"`
+	IsPaused            bool       `json:"is_paused"`
+	IsGone              bool       `json:"is_gone"`
+	BackoffUntil        *time.Time `json:"backoff_until,omitempty"` // Scheduled refreshes are skipped until then
+	Attempts            int        `json:"attempts"`
+	Failures            int        `json:"failures"`
+	SuccessRate         float64    `json:"success_rate"`      // Fraction of successful attempts in the window, 0 without attempts
+	MedianLatencyMs     int64      `json:"median_latency_ms"` // Median duration of the successful attempts in the window
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	FailingSince        *time.Time `json:"failing_since,omitempty"` // First of the consecutive failures
+	LastFetch           *time.Time `json:"last_fetch,omitempty"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	LastNewItem         *time.Time `json:"last_new_item,omitempty"`
+	DaysSinceNewItem    *int       `json:"days_since_new_item,omitempty"`
+	LastErrorClass      string     `json:"last_error_class,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// ComputeHealth computes the health of feeds from their fetch attempts in the health
+// window, oldest first. Feeds synchronized from FreshRSS are not fetched, so they are left out.
+func ComputeHealth(feeds []models.Feed, fetches []models.FeedFetch, now time.Time) []Health {
+	byFeed := make(map[int64][]models.FeedFetch)
+	for _, fetch := range fetches {
+		byFeed[fetch.FeedID] = append(byFeed[fetch.FeedID], fetch)
+	}
+
+	health := make([]Health, 0, len(feeds))
+	for _, feed := range feeds {
+		if feed.IsFreshRSSSource {
+			continue
+		}
+		health = append(health, feedHealth(feed, byFeed[feed.ID], now))
+	}
+	return health
+}
+
+func feedHealth(feed models.Feed, fetches []models.FeedFetch, now time.Time) Health {
+	h := Health{
+		FeedID:       feed.ID,
+		Title:        feed.Title,
+		URL:          feed.URL,
+		Category:     feed.Category,
+		IsPaused:     feed.IsPaused,
+		IsGone:       feed.IsGone,
+		BackoffUntil: feed.BackoffUntil,
+		Attempts:     len(fetches),
+	}
+
+	var latencies []int64
+	var lastNewItem time.Time
+	if feed.LatestArticleTime != nil {
+		lastNewItem = *feed.LatestArticleTime
+	}
+	for _, fetch := range fetches {
+		if fetch.ErrorClass != "" {
+			if h.ConsecutiveFailures == 0 {
+				failingSince := fetch.FetchedAt
+				h.FailingSince = &failingSince
+			}
+			h.Failures++
+			h.ConsecutiveFailures++
+			h.LastErrorClass = fetch.ErrorClass
+			h.LastError = fetch.Error
+			continue
+		}
+		h.ConsecutiveFailures = 0
+		h.FailingSince = nil
+		h.LastErrorClass = ""
+		h.LastError = ""
+		latencies = append(latencies, fetch.DurationMs)
+		fetchedAt := fetch.FetchedAt
+		h.LastSuccess = &fetchedAt
+		if fetch.NewItems > 0 && fetchedAt.After(lastNewItem) {
+			lastNewItem = fetchedAt
+		}
+	}
+	if len(fetches) > 0 {
+		lastFetch := fetches[len(fetches)-1].FetchedAt
+		h.LastFetch = &lastFetch
+		h.SuccessRate = float64(len(fetches)-h.Failures) / float64(len(fetches))
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		mid := len(latencies) / 2
+		h.MedianLatencyMs = latencies[mid]
+		if len(latencies)%2 == 0 {
+			h.MedianLatencyMs = (latencies[mid-1] + latencies[mid]) / 2
+		}
+	}
+	if !lastNewItem.IsZero() {
+		days := int(now.Sub(lastNewItem).Hours() / 24)
+		h.LastNewItem = &lastNewItem
+		h.DaysSinceNewItem = &days
+	}
+
+	h.Status = healthStatus(h, now)
+	return h
+}
+
+func healthStatus(h Health, now time.Time) string {
+	switch {
+	case h.IsPaused:
+		return HealthPaused
+	case h.IsGone:
+		return HealthGone
+	case h.ConsecutiveFailures >= DeadAfterFailures && now.Sub(*h.FailingSince) >= DeadAfterDays*24*time.Hour:
+		return HealthDead
+	case h.ConsecutiveFailures > 0:
+		return HealthFailing
+	case h.DaysSinceNewItem != nil && *h.DaysSinceNewItem >= StaleAfterDays:
+		return HealthStale
+	case h.Attempts == 0:
+		return HealthUnknown
+	}
+	return HealthHealthy
+}
@@ -0,0 +1,202 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestFetchAndRecord(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	rss := `<?xml version="1.0"?><rss><channel><title>Log</title>` +
+		`<item><title>first</title><link>/1</link><guid>1</guid><pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate></item>` +
+		`<item><title>second</title><link>/2</link><guid>2</guid><pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate></item>` +
+		`</channel></rss>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			http.Error(w, "gone", http.StatusGone)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(rss))
+	}))
+	defer srv.Close()
+
+	fetcher := NewFetcher(db, nil)
+	id, _ := db.AddFeed(&models.Feed{Title: "log", URL: srv.URL})
+	goneID, _ := db.AddFeed(&models.Feed{Title: "gone", URL: srv.URL + "/gone"})
+
+	for i := 0; i < 2; i++ {
+		if err := fetcher.fetchAndRecord(context.Background(), models.Feed{ID: id, Title: "log", URL: srv.URL}, TaskReasonManualRefresh); err != nil {
+			t.Fatalf("fetchAndRecord: %v", err)
+		}
+	}
+	if err := fetcher.fetchAndRecord(context.Background(), models.Feed{ID: goneID, Title: "gone", URL: srv.URL + "/gone"}, TaskReasonScheduledGlobal); err == nil {
+		t.Fatal("expected an error for the gone feed")
+	}
+
+	fetches, err := db.GetFeedFetches(id, 10)
+	if err != nil || len(fetches) != 2 {
+		t.Fatalf("GetFeedFetches = %+v, %v", fetches, err)
+	}
+	if fetches[1].NewItems != 2 || fetches[0].NewItems != 0 {
+		t.Errorf("new items = %d then %d, want 2 then 0", fetches[1].NewItems, fetches[0].NewItems)
+	}
+	if f := fetches[0]; f.Reason != "manual_refresh" || f.StatusCode != 200 || f.Bytes != int64(len(rss)) || f.ErrorClass != "" {
+		t.Errorf("unexpected fetch %+v", f)
+	}
+
+	gone, err := db.GetFeedFetches(goneID, 10)
+	if err != nil || len(gone) != 1 {
+		t.Fatalf("GetFeedFetches(gone) = %+v, %v", gone, err)
+	}
+	if gone[0].StatusCode != http.StatusGone || gone[0].ErrorClass != ErrorClassHTTP4xx || gone[0].Reason != "scheduled_global" {
+		t.Errorf("unexpected fetch %+v", gone[0])
+	}
+}
+
+func TestClassifyFetchError(t *testing.T) {
+	tests := []struct {
+		err        error
+		statusCode int
+		want       string
+	}{
+		{errors.New("HTTP 404: 404 Not Found"), 404, ErrorClassHTTP4xx},
+		{errors.New("HTTP 502: 502 Bad Gateway"), 502, ErrorClassHTTP5xx},
+		{fmt.Errorf("failed to fetch feed: %w", context.DeadlineExceeded), 0, ErrorClassTimeout},
+		{context.Canceled, 0, ErrorClassCanceled},
+		{fmt.Errorf("failed to fetch feed: %w", &net.DNSError{Err: "no such host", Name: "nowhere.invalid"}), 0, ErrorClassDNS},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, 0, ErrorClassConnection},
+		{errors.New("tls: failed to verify certificate: x509: certificate has expired"), 0, ErrorClassTLS},
+		{errors.New("Failed to detect feed type"), 200, ErrorClassParse},
+		{errors.New("script exited with status 1"), 0, ErrorClassOther},
+	}
+	for _, tt := range tests {
+		if got := ClassifyFetchError(tt.err, tt.statusCode); got != tt.want {
+			t.Errorf("ClassifyFetchError(%v, %d) = %q, want %q", tt.err, tt.statusCode, got, tt.want)
+		}
+	}
+}
+
+func TestComputeHealth(t *testing.T) {
+	now := time.Now()
+	old := now.AddDate(0, 0, -200)
+	recent := now.Add(-time.Hour)
+	feeds := []models.Feed{
+		{ID: 1, Title: "healthy", LatestArticleTime: &recent},
+		{ID: 2, Title: "failing"},
+		{ID: 3, Title: "dead"},
+		{ID: 4, Title: "stale", LatestArticleTime: &old},
+		{ID: 5, Title: "paused", IsPaused: true},
+		{ID: 6, Title: "new"},
+		{ID: 7, Title: "freshrss", IsFreshRSSSource: true},
+		{ID: 8, Title: "gone", IsGone: true},
+	}
+
+	ok := func(feedID int64, daysAgo int, durationMs int64, newItems int) models.FeedFetch {
+		return models.FeedFetch{FeedID: feedID, FetchedAt: now.AddDate(0, 0, -daysAgo), DurationMs: durationMs, NewItems: newItems}
+	}
+	failed := func(feedID int64, daysAgo int) models.FeedFetch {
+		return models.FeedFetch{FeedID: feedID, FetchedAt: now.AddDate(0, 0, -daysAgo), ErrorClass: ErrorClassTimeout, Error: "timeout"}
+	}
+	fetches := []models.FeedFetch{
+		ok(1, 3, 100, 1), ok(1, 2, 300, 0), ok(1, 1, 200, 0), failed(1, 1), ok(1, 0, 400, 2),
+		ok(2, 3, 100, 0), failed(2, 2), failed(2, 1),
+		failed(3, 20), failed(3, 15), failed(3, 10), failed(3, 5), failed(3, 1),
+		ok(4, 1, 100, 0),
+		failed(5, 20), failed(5, 15), failed(5, 10), failed(5, 5), failed(5, 1),
+	}
+
+	health := ComputeHealth(feeds, fetches, now)
+	if len(health) != 7 {
+		t.Fatalf("expected 7 feeds without the FreshRSS feed, got %d", len(health))
+	}
+	byTitle := make(map[string]Health)
+	for _, h := range health {
+		byTitle[h.Title] = h
+	}
+
+	for title, want := range map[string]string{
+		"healthy": HealthHealthy,
+		"failing": HealthFailing,
+		"dead":    HealthDead,
+		"stale":   HealthStale,
+		"paused":  HealthPaused,
+		"new":     HealthUnknown,
+		"gone":    HealthGone,
+	} {
+		if got := byTitle[title].Status; got != want {
+			t.Errorf("%s: status %q, want %q", title, got, want)
+		}
+	}
+
+	h := byTitle["healthy"]
+	if h.Attempts != 5 || h.Failures != 1 || h.SuccessRate != 0.8 || h.MedianLatencyMs != 250 || h.ConsecutiveFailures != 0 {
+		t.Errorf("unexpected healthy feed %+v", h)
+	}
+	if h.DaysSinceNewItem == nil || *h.DaysSinceNewItem != 0 {
+		t.Errorf("healthy feed: days since new item %v, want 0", h.DaysSinceNewItem)
+	}
+	if f := byTitle["failing"]; f.ConsecutiveFailures != 2 || f.LastErrorClass != ErrorClassTimeout || f.FailingSince == nil {
+		t.Errorf("unexpected failing feed %+v", f)
+	}
+	if s := byTitle["stale"]; s.DaysSinceNewItem == nil || *s.DaysSinceNewItem != 200 {
+		t.Errorf("stale feed: days since new item %v, want 200", s.DaysSinceNewItem)
+	}
+}
+
+func TestQueuedFetchReasons(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0"?><rss><channel><title>Queued</title>` +
+			`<item><title>first</title><link>/1</link><guid>1</guid></item></channel></rss>`))
+	}))
+	defer srv.Close()
+
+	fetcher := NewFetcher(db, nil)
+	tests := []struct {
+		manual bool
+		want   string
+	}{
+		{true, "manual_refresh"},
+		{false, "scheduled_custom"},
+	}
+	for i, tt := range tests {
+		id, _ := db.AddFeed(&models.Feed{Title: fmt.Sprintf("queued %d", i), URL: fmt.Sprintf("%s/%d", srv.URL, i)})
+		feed, _ := db.GetFeedByID(id)
+		fetcher.FetchSingleFeed(context.Background(), *feed, tt.manual)
+
+		var fetches []models.FeedFetch
+		for deadline := time.Now().Add(5 * time.Second); len(fetches) == 0 && time.Now().Before(deadline); {
+			time.Sleep(20 * time.Millisecond)
+			fetches, _ = db.GetFeedFetches(id, 10)
+		}
+		if len(fetches) != 1 || fetches[0].Reason != tt.want {
+			t.Errorf("manual %v: fetches = %+v, want reason %s", tt.manual, fetches, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"MrRSS/internal/database"
 	"MrRSS/internal/models"
 	"math"
+	"sync"
 	"time"
 )
 
@@ -19,59 +20,73 @@ const (
 // IntelligentRefreshCalculator calculates optimal refresh intervals based on feed activity
 type IntelligentRefreshCalculator struct {
 	db *database.DB
+
+	mu          sync.Mutex
+	predictions map[int64]cachedPrediction
+}
+
+// cachedPrediction is a refresh prediction with the state of the feed it was made for
+type cachedPrediction struct {
+	prediction        RefreshPrediction
+	lastUpdated       time.Time
+	latestArticleTime time.Time
 }
 
 // NewIntelligentRefreshCalculator creates a new calculator
 func NewIntelligentRefreshCalculator(db *database.DB) *IntelligentRefreshCalculator {
-	return &IntelligentRefreshCalculator{db: db}
+	return &IntelligentRefreshCalculator{db: db, predictions: make(map[int64]cachedPrediction)}
 }
 
 // CalculateInterval calculates the optimal refresh interval for a feed
-// based on its recent article publication frequency
+// based on its recent article publication pattern
 // Interval range: 5 minutes to 24 hours
 func (irc *IntelligentRefreshCalculator) CalculateInterval(feed models.Feed) time.Duration {
-	// Get recent articles (last 30 days) to analyze frequency
-	articles, err := irc.db.GetArticles("", feed.ID, "", false, 100, 0)
-	if err != nil || len(articles) == 0 {
-		return DefaultRefreshInterval
+	return irc.Predict(feed, time.Now()).Interval()
+}
+
+// Predict predicts when a feed is refreshed next, and why, from the publication
+// times of its latest articles. Predictions are reused until their next refresh,
+// unless the feed was refreshed or got new articles in the meantime.
+func (irc *IntelligentRefreshCalculator) Predict(feed models.Feed, now time.Time) RefreshPrediction {
+	var latestArticleTime time.Time
+	if feed.LatestArticleTime != nil {
+		latestArticleTime = *feed.LatestArticleTime
 	}
 
-	// Calculate average time between articles
-	avgInterval := irc.calculateAverageInterval(articles)
+	irc.mu.Lock()
+	cached, ok := irc.predictions[feed.ID]
+	irc.mu.Unlock()
+	if ok && now.Before(cached.prediction.NextRefresh) && cached.lastUpdated.Equal(feed.LastUpdated) &&
+		cached.latestArticleTime.Equal(latestArticleTime) {
+		return cached.prediction
+	}
 
-	// Apply smart scaling: refresh more frequently than publication rate
-	// but with reasonable bounds
-	optimalInterval := avgInterval / 2
+	published, _ := irc.db.GetArticlePublishTimes(feed.ID, PatternArticles)
+	prediction := predictRefresh(published, feed.LastUpdated, now)
+	prediction.FeedID = feed.ID
 
-	// Clamp to min/max bounds (5 minutes to 24 hours)
-	if optimalInterval < MinRefreshInterval {
-		return MinRefreshInterval
-	}
-	if optimalInterval > MaxRefreshInterval {
-		return MaxRefreshInterval
+	irc.mu.Lock()
+	irc.predictions[feed.ID] = cachedPrediction{
+		prediction:        prediction,
+		lastUpdated:       feed.LastUpdated,
+		latestArticleTime: latestArticleTime,
 	}
-
-	return optimalInterval
+	irc.mu.Unlock()
+	return prediction
 }
 
-// calculateAverageInterval computes the average time between article publications
-func (irc *IntelligentRefreshCalculator) calculateAverageInterval(articles []models.Article) time.Duration {
-	if len(articles) < 2 {
+// averageInterval computes the average time between publications, newest first
+func averageInterval(published []time.Time) time.Duration {
+	if len(published) < 2 {
 		return DefaultRefreshInterval
 	}
 
-	// Note: Articles should be ordered by published_at DESC from the database query.
-	// If ordering is not guaranteed, explicit sorting should be added here.
-	// Calculate intervals between consecutive articles
+	// Calculate intervals between consecutive publications
 	var totalInterval time.Duration
 	validIntervals := 0
 
-	for i := 0; i < len(articles)-1; i++ {
-		// Articles already have time.Time for PublishedAt
-		published1 := articles[i].PublishedAt
-		published2 := articles[i+1].PublishedAt
-
-		interval := published1.Sub(published2)
+	for i := 0; i < len(published)-1; i++ {
+		interval := published[i].Sub(published[i+1])
 		// Only count positive intervals (skip negative or zero)
 		if interval > 0 {
 			totalInterval += interval
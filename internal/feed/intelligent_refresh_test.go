@@ -2,6 +2,7 @@ package feed
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -186,3 +187,51 @@ func TestIntelligentRefreshCalculator_WithArticles(t *testing.T) {
 		t.Errorf("Interval %v is too high (above 2 hours)", interval)
 	}
 }
+
+func TestIntelligentRefreshCalculator_CachesPredictions(t *testing.T) {
+	db, err := database.NewDB(t.TempDir() + "/test.db")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	calculator := NewIntelligentRefreshCalculator(db)
+	now := time.Now()
+	feed := models.Feed{ID: 1, Title: "Test Feed", LastUpdated: now}
+	if p := calculator.Predict(feed, now); p.Strategy != RefreshStrategyDefault {
+		t.Fatalf("Strategy = %s, want %s", p.Strategy, RefreshStrategyDefault)
+	}
+
+	articles := make([]*models.Article, 0, 20)
+	for i := 0; i < 20; i++ {
+		articles = append(articles, &models.Article{
+			FeedID:      1,
+			Title:       "Article " + strconv.Itoa(i),
+			URL:         "http://example.com/article",
+			PublishedAt: now.Add(time.Duration(-i*2) * time.Hour),
+		})
+	}
+	if err := db.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("Failed to save articles: %v", err)
+	}
+
+	// Reused until the next refresh while the feed is unchanged
+	if p := calculator.Predict(feed, now.Add(time.Minute)); p.Strategy != RefreshStrategyDefault {
+		t.Errorf("unchanged feed: Strategy = %s, want cached %s", p.Strategy, RefreshStrategyDefault)
+	}
+	if p := calculator.Predict(feed, now.Add(DefaultRefreshInterval)); p.Strategy != RefreshStrategyAverage {
+		t.Errorf("after the next refresh: Strategy = %s, want %s", p.Strategy, RefreshStrategyAverage)
+	}
+
+	// New articles invalidate the prediction
+	calculator = NewIntelligentRefreshCalculator(db)
+	calculator.Predict(models.Feed{ID: 1, LastUpdated: now}, now)
+	latest := now
+	feed.LatestArticleTime = &latest
+	if p := calculator.Predict(feed, now.Add(time.Minute)); p.Strategy != RefreshStrategyAverage || p.Samples != 20 {
+		t.Errorf("new articles: Strategy = %s with %d samples", p.Strategy, p.Samples)
+	}
+}
@@ -0,0 +1,117 @@
+package feed
+
+import (
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// transcriptTypePriority lists the transcript formats that are preferred when
+// an episode has several, because they carry timestamps
+var transcriptTypePriority = []string{
+	"text/vtt",
+	"application/x-subrip",
+	"application/srt",
+	"application/json",
+	"text/html",
+	"text/plain",
+}
+
+// extractPodcastEpisode extracts the podcast metadata of a feed item with an
+// audio enclosure: iTunes duration, episode and season numbers and artwork, and
+// the chapters and transcript of the Podcasting 2.0 namespace.
+// ArticleID is set once the article is saved.
+func extractPodcastEpisode(item *gofeed.Item, audioURL string) *models.PodcastEpisode {
+	episode := &models.PodcastEpisode{}
+
+	for _, enc := range item.Enclosures {
+		if enc.URL == audioURL {
+			episode.MimeType = enc.Type
+			episode.FileSize, _ = strconv.ParseInt(strings.TrimSpace(enc.Length), 10, 64)
+			break
+		}
+	}
+
+	if itunes := item.ITunesExt; itunes != nil {
+		episode.Duration = parseITunesDuration(itunes.Duration)
+		episode.Episode, _ = strconv.Atoi(strings.TrimSpace(itunes.Episode))
+		episode.Season, _ = strconv.Atoi(strings.TrimSpace(itunes.Season))
+		episode.EpisodeType = strings.ToLower(strings.TrimSpace(itunes.EpisodeType))
+		explicit := strings.ToLower(strings.TrimSpace(itunes.Explicit))
+		episode.Explicit = explicit == "yes" || explicit == "true" || explicit == "explicit"
+		episode.ImageURL = strings.TrimSpace(itunes.Image)
+	}
+
+	if podcastExt, ok := item.Extensions["podcast"]; ok {
+		if chapters, ok := podcastExt["chapters"]; ok && len(chapters) > 0 {
+			episode.ChaptersURL = chapters[0].Attrs["url"]
+		}
+		bestPriority := len(transcriptTypePriority)
+		for _, transcript := range podcastExt["transcript"] {
+			transcriptURL := transcript.Attrs["url"]
+			if transcriptURL == "" {
+				continue
+			}
+			transcriptType := strings.ToLower(transcript.Attrs["type"])
+			priority := len(transcriptTypePriority)
+			for i, t := range transcriptTypePriority {
+				if transcriptType == t {
+					priority = i
+					break
+				}
+			}
+			if episode.TranscriptURL == "" || priority < bestPriority {
+				episode.TranscriptURL = transcriptURL
+				episode.TranscriptType = transcriptType
+				bestPriority = priority
+			}
+		}
+	}
+
+	return episode
+}
+
+// parseITunesDuration parses an itunes:duration value, given either in seconds
+// or as HH:MM:SS or MM:SS, and returns the duration in seconds (0 if invalid)
+func parseITunesDuration(s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	seconds := 0
+	for _, part := range strings.Split(s, ":") {
+		// Some feeds use fractional seconds
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || value < 0 {
+			return 0
+		}
+		seconds = seconds*60 + int(value)
+	}
+	return seconds
+}
+
+// savePodcastEpisodes stores the podcast metadata of saved articles
+func (f *Fetcher) savePodcastEpisodes(articlesWithContent []*ArticleWithContent) {
+	for _, awc := range articlesWithContent {
+		if awc.Episode == nil {
+			continue
+		}
+
+		articleID, err := f.db.GetArticleIDByUniqueID(awc.Article.Title, awc.Article.FeedID, awc.Article.PublishedAt, awc.Article.HasValidPublishedTime)
+		if err != nil {
+			utils.DebugLog("Could not find article ID for %s: %v", awc.Article.Title, err)
+			continue
+		}
+
+		episode := *awc.Episode
+		episode.ArticleID = articleID
+		if err := f.db.SavePodcastEpisode(episode); err != nil {
+			utils.DebugLog("Error saving podcast metadata for article %d: %v", articleID, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestParseITunesDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"3600", 3600},
+		{"45:30", 2730},
+		{"1:02:03", 3723},
+		{"01:02:03.500", 3723},
+		{"abc", 0},
+		{"-5", 0},
+	}
+	for _, tt := range tests {
+		if got := parseITunesDuration(tt.in); got != tt.want {
+			t.Errorf("parseITunesDuration(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExtractPodcastEpisode(t *testing.T) {
+	rss := `<?xml version="1.0"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:podcast="https://podcastindex.org/namespace/1.0">
+<channel>
+<title>Show</title>
+<item>
+	<title>Episode 12</title>
+	<link>https://example.com/12</link>
+	<enclosure url="https://example.com/12.jpg" type="image/jpeg" length="100"/>
+	<enclosure url="https://example.com/12.mp3" type="audio/mpeg" length="34567890"/>
+	<itunes:duration>1:02:03</itunes:duration>
+	<itunes:episode>12</itunes:episode>
+	<itunes:season>2</itunes:season>
+	<itunes:episodeType>Full</itunes:episodeType>
+	<itunes:explicit>yes</itunes:explicit>
+	<itunes:image href="https://example.com/12-art.jpg"/>
+	<podcast:chapters url="https://example.com/12-chapters.json" type="application/json+chapters"/>
+	<podcast:transcript url="https://example.com/12.txt" type="text/plain"/>
+	<podcast:transcript url="https://example.com/12.vtt" type="text/vtt"/>
+	<podcast:transcript url="https://example.com/12.html" type="text/html"/>
+</item>
+</channel>
+</rss>`
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(rss))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	item := feed.Items[0]
+
+	audioURL := extractAudioURL(item)
+	if audioURL != "https://example.com/12.mp3" {
+		t.Fatalf("extractAudioURL() = %q", audioURL)
+	}
+	episode := extractPodcastEpisode(item, audioURL)
+
+	if episode.MimeType != "audio/mpeg" || episode.FileSize != 34567890 {
+		t.Errorf("enclosure metadata = %q, %d", episode.MimeType, episode.FileSize)
+	}
+	if episode.Duration != 3723 || episode.Episode != 12 || episode.Season != 2 {
+		t.Errorf("duration, episode, season = %d, %d, %d", episode.Duration, episode.Episode, episode.Season)
+	}
+	if episode.EpisodeType != "full" || !episode.Explicit {
+		t.Errorf("episode type, explicit = %q, %v", episode.EpisodeType, episode.Explicit)
+	}
+	if episode.ImageURL != "https://example.com/12-art.jpg" {
+		t.Errorf("ImageURL = %q", episode.ImageURL)
+	}
+	if episode.ChaptersURL != "https://example.com/12-chapters.json" {
+		t.Errorf("ChaptersURL = %q", episode.ChaptersURL)
+	}
+	// Timed transcripts are preferred
+	if episode.TranscriptURL != "https://example.com/12.vtt" || episode.TranscriptType != "text/vtt" {
+		t.Errorf("transcript = %q, %q", episode.TranscriptURL, episode.TranscriptType)
+	}
+}
@@ -0,0 +1,322 @@
+package feed
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+	"github.com/mmcdole/gofeed/rss"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Limits of the requests sent to a single host
+const (
+	MaxRequestsPerHost = 2               // Feeds of the same host refreshed at the same time
+	HostRequestSpacing = time.Second     // Minimum time between starting two refreshes of the same host
+	DefaultRetryAfter  = time.Minute     // Throttle of a 429 response without Retry-After
+	MaxRetryAfter      = 6 * time.Hour   // Longest Retry-After honoured
+	MaxThrottleWait    = 5 * time.Minute // Queued feeds of hosts throttled for longer are left to a later refresh
+	MaxHintInterval    = 24 * time.Hour  // Longest refresh interval asked by <ttl> or sy:updatePeriod
+)
+
+// RateLimitedError is returned when a server asks to slow down with 429 Too Many Requests,
+// or 503 Service Unavailable with a Retry-After header
+type RateLimitedError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s, retry after %v", e.StatusCode, http.StatusText(e.StatusCode), e.RetryAfter)
+}
+
+// rateLimitError returns the error of a response asking to slow down, or nil
+func rateLimitError(resp *http.Response, now time.Time) error {
+	header := resp.Header.Get("Retry-After")
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter := parseRetryAfter(header, now)
+		if retryAfter == 0 {
+			retryAfter = DefaultRetryAfter
+		}
+		return &RateLimitedError{StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+	case resp.StatusCode == http.StatusServiceUnavailable && header != "":
+		if retryAfter := parseRetryAfter(header, now); retryAfter > 0 {
+			return &RateLimitedError{StatusCode: resp.StatusCode, RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date, capped at
+// MaxRetryAfter. It returns 0 for a missing or invalid header.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		retryAfter = date.Sub(now)
+	}
+	if retryAfter < 0 {
+		return 0
+	}
+	if retryAfter > MaxRetryAfter {
+		return MaxRetryAfter
+	}
+	return retryAfter
+}
+
+// feedHost returns the host whose requests are limited together for a feed: the
+// registrable domain of its URL, so that the blogs of a platform such as substack.com
+// share their limits. Feeds that are not fetched over HTTP have no host.
+func feedHost(feed models.Feed) string {
+	if feed.Type == "email" || feed.Type == "imported" || feed.ScriptPath != "" {
+		return ""
+	}
+	u, err := url.Parse(feed.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
+
+// hostLimiter caps the concurrent refreshes of each host, spaces them out and holds
+// them back while the host asked to slow down
+type hostLimiter struct {
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	active         int
+	lastStart      time.Time
+	throttledUntil time.Time
+}
+
+func newHostLimiter() *hostLimiter {
+	return &hostLimiter{hosts: make(map[string]*hostState)}
+}
+
+// acquire starts a refresh of a host if its limits allow it. Otherwise it returns when
+// the host is ready again, or the zero time when it waits for a running refresh.
+func (l *hostLimiter) acquire(host string, now time.Time) (bool, time.Time) {
+	if host == "" {
+		return true, time.Time{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.hosts[host]
+	if state == nil {
+		state = &hostState{}
+		l.hosts[host] = state
+	}
+	switch {
+	case now.Before(state.throttledUntil):
+		return false, state.throttledUntil
+	case state.active >= MaxRequestsPerHost:
+		return false, time.Time{}
+	case now.Sub(state.lastStart) < HostRequestSpacing:
+		return false, state.lastStart.Add(HostRequestSpacing)
+	}
+	state.active++
+	state.lastStart = now
+	return true, time.Time{}
+}
+
+// release ends a refresh started by acquire
+func (l *hostLimiter) release(host string) {
+	if host == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state := l.hosts[host]; state != nil && state.active > 0 {
+		state.active--
+	}
+}
+
+// throttle holds back the refreshes of a host until a time
+func (l *hostLimiter) throttle(host string, until time.Time) {
+	if host == "" {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state := l.hosts[host]
+	if state == nil {
+		state = &hostState{}
+		l.hosts[host] = state
+	}
+	if until.After(state.throttledUntil) {
+		state.throttledUntil = until
+	}
+}
+
+// throttledUntil returns until when a host is throttled, or nil if it is not
+func (l *hostLimiter) throttledUntil(host string, now time.Time) *time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if state := l.hosts[host]; state != nil && now.Before(state.throttledUntil) {
+		until := state.throttledUntil
+		return &until
+	}
+	return nil
+}
+
+// refreshHints are the refresh limits a feed asks for in its content
+type refreshHints struct {
+	minInterval int    // Minutes, from <ttl> or sy:updatePeriod and sy:updateFrequency
+	skipHours   string // Comma-separated hours in GMT, from <skipHours>
+	skipDays    string // Comma-separated English day names, from <skipDays>
+}
+
+var syUpdatePeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// parseRefreshHints reads the <ttl>, <skipHours> and <skipDays> of an RSS feed and the
+// sy:updatePeriod and sy:updateFrequency of the syndication module
+func parseRefreshHints(parsedFeed *gofeed.Feed, data string) refreshHints {
+	var interval time.Duration
+	if sy, ok := parsedFeed.Extensions["sy"]; ok {
+		if period := syUpdatePeriods[strings.ToLower(extensionValue(sy, "updatePeriod"))]; period > 0 {
+			frequency, err := strconv.Atoi(extensionValue(sy, "updateFrequency"))
+			if err != nil || frequency < 1 {
+				frequency = 1
+			}
+			interval = period / time.Duration(frequency)
+		}
+	}
+
+	var hints refreshHints
+	if parsedFeed.FeedType == "rss" {
+		if rssFeed, err := (&rss.Parser{}).Parse(strings.NewReader(data)); err == nil {
+			if ttl, err := strconv.Atoi(strings.TrimSpace(rssFeed.TTL)); err == nil && ttl > 0 {
+				interval = max(interval, time.Duration(ttl)*time.Minute)
+			}
+			hints.skipHours = normalizeSkipHours(rssFeed.SkipHours)
+			hints.skipDays = normalizeSkipDays(rssFeed.SkipDays)
+		}
+	}
+	hints.minInterval = int(min(interval, MaxHintInterval) / time.Minute)
+	return hints
+}
+
+func extensionValue(extensions map[string][]ext.Extension, name string) string {
+	if values := extensions[name]; len(values) > 0 {
+		return strings.TrimSpace(values[0].Value)
+	}
+	return ""
+}
+
+// normalizeSkipHours keeps the valid hours, sorted, and drops them all when they cover
+// the whole day, which would never refresh the feed
+func normalizeSkipHours(hours []string) string {
+	seen := make(map[int]bool)
+	for _, h := range hours {
+		if hour, err := strconv.Atoi(strings.TrimSpace(h)); err == nil && hour >= 0 && hour < 24 {
+			seen[hour] = true
+		}
+	}
+	if len(seen) == 0 || len(seen) == 24 {
+		return ""
+	}
+	sorted := make([]int, 0, len(seen))
+	for hour := range seen {
+		sorted = append(sorted, hour)
+	}
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, hour := range sorted {
+		parts[i] = strconv.Itoa(hour)
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeSkipDays keeps the valid days in week order, and drops them all when they
+// cover the whole week
+func normalizeSkipDays(days []string) string {
+	seen := make(map[time.Weekday]bool)
+	for _, d := range days {
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(strings.TrimSpace(d), day.String()) {
+				seen[day] = true
+			}
+		}
+	}
+	if len(seen) == 0 || len(seen) == 7 {
+		return ""
+	}
+	parts := make([]string, 0, len(seen))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if seen[day] {
+			parts = append(parts, day.String())
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// recordRefreshHints stores the refresh limits a feed asks for, when they changed
+func (f *Fetcher) recordRefreshHints(feed *models.Feed, parsedFeed *gofeed.Feed, data string) {
+	if feed.ID == 0 {
+		return
+	}
+	hints := parseRefreshHints(parsedFeed, data)
+	if hints.minInterval == feed.MinRefreshInterval && hints.skipHours == feed.SkipHours && hints.skipDays == feed.SkipDays {
+		return
+	}
+	if err := f.db.UpdateFeedRefreshHints(feed.ID, hints.minInterval, hints.skipHours, hints.skipDays); err != nil {
+		log.Printf("Error saving refresh hints of feed %s: %v", feed.URL, err)
+	}
+}
+
+// HintsAllowRefresh reports whether the refresh limits a feed asks for allow a scheduled
+// refresh: not before its minimum interval, and not in its skipped hours and days in GMT.
+// Refreshes the user asks for ignore these limits.
+func HintsAllowRefresh(feed models.Feed, now time.Time) bool {
+	if feed.MinRefreshInterval > 0 && now.Sub(feed.LastUpdated) < time.Duration(feed.MinRefreshInterval)*time.Minute {
+		return false
+	}
+	utc := now.UTC()
+	if feed.SkipHours != "" {
+		for _, h := range strings.Split(feed.SkipHours, ",") {
+			if h == strconv.Itoa(utc.Hour()) {
+				return false
+			}
+		}
+	}
+	if feed.SkipDays != "" {
+		for _, d := range strings.Split(feed.SkipDays, ",") {
+			if d == utc.Weekday().String() {
+				return false
+			}
+		}
+	}
+	return true
+}
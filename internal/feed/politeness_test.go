@@ -0,0 +1,269 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"soon", 0},
+		{now.Add(10 * time.Minute).Format(http.TimeFormat), 10 * time.Minute},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"86400", MaxRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	now := time.Now()
+	response := func(status int, retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set("Retry-After", retryAfter)
+		}
+		return resp
+	}
+
+	var rateErr *RateLimitedError
+	if err := rateLimitError(response(http.StatusTooManyRequests, ""), now); !errors.As(err, &rateErr) || rateErr.RetryAfter != DefaultRetryAfter {
+		t.Errorf("429 without Retry-After = %v", err)
+	}
+	if err := rateLimitError(response(http.StatusServiceUnavailable, "30"), now); !errors.As(err, &rateErr) || rateErr.RetryAfter != 30*time.Second {
+		t.Errorf("503 with Retry-After = %v", err)
+	}
+	if err := rateLimitError(response(http.StatusServiceUnavailable, ""), now); err != nil {
+		t.Errorf("503 without Retry-After = %v, want nil", err)
+	}
+	if err := rateLimitError(response(http.StatusOK, "30"), now); err != nil {
+		t.Errorf("200 = %v, want nil", err)
+	}
+}
+
+func TestFeedHost(t *testing.T) {
+	tests := []struct {
+		feed models.Feed
+		want string
+	}{
+		{models.Feed{URL: "https://alice.substack.com/feed"}, "substack.com"},
+		{models.Feed{URL: "https://bob.substack.com/feed"}, "substack.com"},
+		{models.Feed{URL: "https://RSSHub.app/github/issue/x"}, "rsshub.app"},
+		{models.Feed{URL: "http://localhost:1200/feed"}, "localhost"},
+		{models.Feed{URL: "file:///tmp/feed.xml"}, ""},
+		{models.Feed{URL: "https://example.com/feed", ScriptPath: "feed.py"}, ""},
+		{models.Feed{URL: "email://inbox", Type: "email"}, ""},
+	}
+	for _, tt := range tests {
+		if got := feedHost(tt.feed); got != tt.want {
+			t.Errorf("feedHost(%q) = %q, want %q", tt.feed.URL, got, tt.want)
+		}
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	l := newHostLimiter()
+	now := time.Now()
+
+	if ok, _ := l.acquire("example.com", now); !ok {
+		t.Fatal("first acquire refused")
+	}
+	// Refreshes of the same host are spaced out
+	if ok, readyAt := l.acquire("example.com", now); ok || !readyAt.Equal(now.Add(HostRequestSpacing)) {
+		t.Fatalf("acquire within spacing = %v, %v", ok, readyAt)
+	}
+	// Other hosts are independent
+	if ok, _ := l.acquire("example.org", now); !ok {
+		t.Fatal("acquire of another host refused")
+	}
+	// Feeds without a host are never limited
+	for i := 0; i < MaxRequestsPerHost+1; i++ {
+		if ok, _ := l.acquire("", now); !ok {
+			t.Fatal("acquire without host refused")
+		}
+	}
+
+	later := now
+	for i := 1; i < MaxRequestsPerHost; i++ {
+		later = later.Add(HostRequestSpacing)
+		if ok, _ := l.acquire("example.com", later); !ok {
+			t.Fatalf("acquire %d refused", i+1)
+		}
+	}
+	later = later.Add(HostRequestSpacing)
+	if ok, readyAt := l.acquire("example.com", later); ok || !readyAt.IsZero() {
+		t.Fatalf("acquire over the cap = %v, %v", ok, readyAt)
+	}
+	l.release("example.com")
+	if ok, _ := l.acquire("example.com", later); !ok {
+		t.Fatal("acquire after release refused")
+	}
+
+	until := later.Add(time.Minute)
+	l.throttle("example.com", until)
+	l.release("example.com")
+	if ok, readyAt := l.acquire("example.com", later.Add(30*time.Second)); ok || !readyAt.Equal(until) {
+		t.Fatalf("acquire while throttled = %v, %v", ok, readyAt)
+	}
+	if got := l.throttledUntil("example.com", later); got == nil || !got.Equal(until) {
+		t.Errorf("throttledUntil = %v, want %v", got, until)
+	}
+	if got := l.throttledUntil("example.com", until); got != nil {
+		t.Errorf("throttledUntil after the throttle = %v, want nil", got)
+	}
+	if got := l.throttledUntil("example.org", later); got != nil {
+		t.Errorf("throttledUntil of another host = %v, want nil", got)
+	}
+}
+
+func TestParseRefreshHints(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want refreshHints
+	}{
+		{
+			name: "ttl and skips",
+			data: `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title><ttl>90</ttl>` +
+				`<skipHours><hour>3</hour><hour>1</hour><hour>25</hour></skipHours>` +
+				`<skipDays><day>sunday</day><day>Saturday</day></skipDays></channel></rss>`,
+			want: refreshHints{minInterval: 90, skipHours: "1,3", skipDays: "Sunday,Saturday"},
+		},
+		{
+			name: "syndication module",
+			data: `<?xml version="1.0"?><rss version="2.0" xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">` +
+				`<channel><title>t</title><sy:updatePeriod>daily</sy:updatePeriod>` +
+				`<sy:updateFrequency>4</sy:updateFrequency></channel></rss>`,
+			want: refreshHints{minInterval: 360},
+		},
+		{
+			name: "capped interval",
+			data: `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title><ttl>100000</ttl></channel></rss>`,
+			want: refreshHints{minInterval: int(MaxHintInterval / time.Minute)},
+		},
+		{
+			name: "whole week skipped",
+			data: `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title><skipDays>` +
+				`<day>Monday</day><day>Tuesday</day><day>Wednesday</day><day>Thursday</day>` +
+				`<day>Friday</day><day>Saturday</day><day>Sunday</day></skipDays></channel></rss>`,
+			want: refreshHints{},
+		},
+		{
+			name: "atom",
+			data: `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>t</title></feed>`,
+			want: refreshHints{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsedFeed, err := gofeed.NewParser().Parse(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := parseRefreshHints(parsedFeed, tt.data); got != tt.want {
+				t.Errorf("parseRefreshHints = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHintsAllowRefresh(t *testing.T) {
+	// A Sunday, 03:30 GMT
+	now := time.Date(2025, 6, 1, 3, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		feed models.Feed
+		want bool
+	}{
+		{"no hints", models.Feed{LastUpdated: now}, true},
+		{"within ttl", models.Feed{MinRefreshInterval: 60, LastUpdated: now.Add(-30 * time.Minute)}, false},
+		{"after ttl", models.Feed{MinRefreshInterval: 60, LastUpdated: now.Add(-61 * time.Minute)}, true},
+		{"skipped hour", models.Feed{SkipHours: "1,3"}, false},
+		{"other hours", models.Feed{SkipHours: "1,13"}, true},
+		{"skipped day", models.Feed{SkipDays: "Sunday,Saturday"}, false},
+		{"other days", models.Feed{SkipDays: "Monday"}, true},
+	}
+	for _, tt := range tests {
+		if got := HintsAllowRefresh(tt.feed, now); got != tt.want {
+			t.Errorf("%s: HintsAllowRefresh = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFetchRateLimited(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	defer db.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limited":
+			w.Header().Set("Retry-After", "120")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`<?xml version="1.0"?><rss version="2.0"><channel><title>Hinted</title>` +
+				`<ttl>45</ttl><skipDays><day>Sunday</day></skipDays>` +
+				`<item><title>first</title><link>/1</link><guid>1</guid></item></channel></rss>`))
+		}
+	}))
+	defer srv.Close()
+
+	fetcher := NewFetcher(db, nil)
+
+	t.Run("rate limited", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "limited", URL: srv.URL + "/limited"})
+		feed, _ := db.GetFeedByID(id)
+		err := fetcher.fetchAndRecord(context.Background(), *feed, TaskReasonScheduledGlobal)
+		var rateErr *RateLimitedError
+		if !errors.As(err, &rateErr) || rateErr.StatusCode != http.StatusTooManyRequests || rateErr.RetryAfter != 2*time.Minute {
+			t.Fatalf("fetchAndRecord error = %v", err)
+		}
+		fetches, _ := db.GetFeedFetches(id, 1)
+		if len(fetches) != 1 || fetches[0].ErrorClass != ErrorClassRateLimit {
+			t.Errorf("fetches = %+v", fetches)
+		}
+
+		// Rate limiting is not a failure of the feed
+		fetcher.handleRefreshResult(id, err)
+		if feed, _ := db.GetFeedByID(id); feed.ConsecutiveFailures != 0 {
+			t.Errorf("ConsecutiveFailures = %d, want 0", feed.ConsecutiveFailures)
+		}
+	})
+
+	t.Run("refresh hints", func(t *testing.T) {
+		id, _ := db.AddFeed(&models.Feed{Title: "hinted", URL: srv.URL + "/hinted"})
+		feed, _ := db.GetFeedByID(id)
+		if err := fetcher.fetchAndRecord(context.Background(), *feed, TaskReasonScheduledGlobal); err != nil {
+			t.Fatalf("fetchAndRecord: %v", err)
+		}
+		feed, _ = db.GetFeedByID(id)
+		if feed.MinRefreshInterval != 45 || feed.SkipHours != "" || feed.SkipDays != "Sunday" {
+			t.Errorf("hints = %d, %q, %q", feed.MinRefreshInterval, feed.SkipHours, feed.SkipDays)
+		}
+	})
+}
@@ -0,0 +1,185 @@
+package feed
+
+import (
+	"sort"
+	"time"
+)
+
+// Thresholds of the publication patterns learned from the articles of a feed
+const (
+	PatternArticles     = 100              // Latest articles the pattern is learned from
+	PatternMinSamples   = 10               // Articles needed before a pattern is trusted
+	PatternMinHourCount = 2                // Articles published in an hour of the week that make it a publish window
+	PatternMinCoverage  = 0.6              // Share of the articles published in publish windows for the pattern to be trusted
+	WindowLead          = 15 * time.Minute // Extra checks start this long before a publish window
+	WindowTrail         = time.Hour        // and go on this long after it
+	WindowCheckInterval = 15 * time.Minute // Interval of the checks around a publish window
+)
+
+const hoursPerWeek = 7 * 24
+
+// Strategies of refresh predictions
+const (
+	RefreshStrategyDefault = "default"        // Not enough publication history
+	RefreshStrategyAverage = "average"        // No clear pattern: half the mean gap between articles
+	RefreshStrategyWindow  = "publish_window" // Around a publish window: frequent checks
+	RefreshStrategyQuiet   = "quiet"          // Outside the publish windows: backed off until the next one
+)
+
+// RefreshPrediction explains when a feed is refreshed in intelligent mode
+type RefreshPrediction struct {
+	FeedID          int64      `json:"feed_id"`
+	Strategy        string     `json:"strategy"`
+	IntervalSeconds int64      `json:"interval_seconds"`
+	LastRefresh     *time.Time `json:"last_refresh,omitempty"`
+	NextRefresh     time.Time  `json:"next_refresh"`
+	NextWindowStart *time.Time `json:"next_window_start,omitempty"` // Publish window the checks are scheduled around, lead and trail included
+	NextWindowEnd   *time.Time `json:"next_window_end,omitempty"`
+	Samples         int        `json:"samples"`          // Articles the prediction is based on
+	MeanGapSeconds  int64      `json:"mean_gap_seconds"` // Mean time between these articles
+	PatternCoverage float64    `json:"pattern_coverage"` // Share of these articles published in publish windows
+	Histogram       []int      `json:"histogram"`        // Articles by hour of the week in UTC, from Sunday 00:00
+}
+
+// Interval returns the refresh interval of the prediction
+func (p RefreshPrediction) Interval() time.Duration {
+	return time.Duration(p.IntervalSeconds) * time.Second
+}
+
+// hourOfWeek returns the hour of the week of a time in UTC, from Sunday 00:00
+func hourOfWeek(t time.Time) int {
+	utc := t.UTC()
+	return int(utc.Weekday())*24 + utc.Hour()
+}
+
+// publicationPattern is the hour-of-week histogram of the articles of a feed
+type publicationPattern struct {
+	histogram [hoursPerWeek]int
+	active    [hoursPerWeek]bool // Publish windows
+	coverage  float64
+}
+
+func newPublicationPattern(published []time.Time) publicationPattern {
+	var p publicationPattern
+	for _, t := range published {
+		p.histogram[hourOfWeek(t)]++
+	}
+	inWindows := 0
+	for h, count := range p.histogram {
+		if count >= PatternMinHourCount {
+			p.active[h] = true
+			inWindows += count
+		}
+	}
+	if len(published) > 0 {
+		p.coverage = float64(inWindows) / float64(len(published))
+	}
+	return p
+}
+
+// trusted reports whether the pattern is clear enough to schedule refreshes around
+func (p publicationPattern) trusted(samples int) bool {
+	return samples >= PatternMinSamples && p.coverage >= PatternMinCoverage
+}
+
+func (p publicationPattern) activeAt(t time.Time) bool {
+	return p.active[hourOfWeek(t)]
+}
+
+// windowAround returns the publish window, lead and trail included, that a time is in
+func (p publicationPattern) windowAround(t time.Time) (time.Time, time.Time, bool) {
+	var hour time.Time
+	switch {
+	case p.activeAt(t):
+		hour = t
+	case p.activeAt(t.Add(WindowLead)):
+		hour = t.Add(WindowLead)
+	case p.activeAt(t.Add(-WindowTrail)):
+		hour = t.Add(-WindowTrail)
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+	start, end := p.windowHours(hour.UTC().Truncate(time.Hour))
+	return start.Add(-WindowLead), end.Add(WindowTrail), true
+}
+
+// windowHours returns the start and end of the consecutive publish window hours around an active hour
+func (p publicationPattern) windowHours(hour time.Time) (time.Time, time.Time) {
+	start, end := hour, hour.Add(time.Hour)
+	for i := 1; i < hoursPerWeek && p.activeAt(start.Add(-time.Hour)); i++ {
+		start = start.Add(-time.Hour)
+	}
+	for i := 1; i < hoursPerWeek && p.activeAt(end); i++ {
+		end = end.Add(time.Hour)
+	}
+	return start, end
+}
+
+// nextWindow returns the next publish window after a time, lead and trail included
+func (p publicationPattern) nextWindow(t time.Time) (time.Time, time.Time, bool) {
+	hour := t.UTC().Truncate(time.Hour)
+	for i := 1; i <= hoursPerWeek; i++ {
+		hour = hour.Add(time.Hour)
+		if p.activeAt(hour) {
+			start, end := p.windowHours(hour)
+			return start.Add(-WindowLead), end.Add(WindowTrail), true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// predictRefresh predicts when to refresh a feed from the publication times of its
+// latest articles. Feeds publishing at regular hours of the week are checked often
+// around those hours and backed off elsewhere; other feeds are refreshed at half the
+// mean gap between their articles.
+func predictRefresh(published []time.Time, lastRefresh, now time.Time) RefreshPrediction {
+	samples := make([]time.Time, 0, len(published))
+	for _, t := range published {
+		if !t.IsZero() && !t.After(now) {
+			samples = append(samples, t)
+		}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].After(samples[j]) })
+
+	pattern := newPublicationPattern(samples)
+	prediction := RefreshPrediction{
+		Strategy:        RefreshStrategyDefault,
+		Samples:         len(samples),
+		PatternCoverage: pattern.coverage,
+		Histogram:       pattern.histogram[:],
+	}
+	base := now
+	if !lastRefresh.IsZero() {
+		last := lastRefresh
+		prediction.LastRefresh = &last
+		base = lastRefresh
+	}
+
+	interval := DefaultRefreshInterval
+	// A mean gap needs at least two articles
+	if len(samples) > 1 {
+		meanGap := averageInterval(samples)
+		prediction.MeanGapSeconds = int64(meanGap / time.Second)
+		prediction.Strategy = RefreshStrategyAverage
+		interval = meanGap / 2
+
+		if pattern.trusted(len(samples)) {
+			if start, end, ok := pattern.windowAround(now); ok {
+				prediction.Strategy = RefreshStrategyWindow
+				prediction.NextWindowStart, prediction.NextWindowEnd = &start, &end
+				interval = WindowCheckInterval
+			} else if start, end, ok := pattern.nextWindow(now); ok {
+				prediction.Strategy = RefreshStrategyQuiet
+				prediction.NextWindowStart, prediction.NextWindowEnd = &start, &end
+				// Check at the mean gap in case of an article out of the windows, and at the
+				// start of the next window at the latest
+				interval = min(meanGap, start.Sub(base))
+			}
+		}
+	}
+
+	interval = max(MinRefreshInterval, min(interval, MaxRefreshInterval))
+	prediction.IntervalSeconds = int64(interval / time.Second)
+	prediction.NextRefresh = base.Add(interval)
+	return prediction
+}
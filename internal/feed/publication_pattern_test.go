@@ -0,0 +1,110 @@
+package feed
+
+import (
+	"testing"
+	"time"
+)
+
+// weekdayMornings returns publication times every weekday between 09:05 and 09:40 UTC
+// over the weeks before a Monday
+func weekdayMornings(monday time.Time, weeks int) []time.Time {
+	var published []time.Time
+	for day := monday.AddDate(0, 0, -7*weeks); day.Before(monday); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		published = append(published, day.Add(9*time.Hour+time.Duration(5+day.Day()%35)*time.Minute))
+	}
+	return published
+}
+
+func TestPredictRefresh_PublishWindows(t *testing.T) {
+	monday := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	published := weekdayMornings(monday, 4)
+
+	t.Run("night before a window", func(t *testing.T) {
+		now := monday.Add(24*time.Hour + 2*time.Hour) // Tuesday 02:00
+		p := predictRefresh(published, now.Add(-time.Hour), now)
+		if p.Strategy != RefreshStrategyQuiet {
+			t.Fatalf("Strategy = %s, want %s", p.Strategy, RefreshStrategyQuiet)
+		}
+		wantStart := monday.Add(24*time.Hour + 9*time.Hour - WindowLead)
+		if p.NextWindowStart == nil || !p.NextWindowStart.Equal(wantStart) {
+			t.Errorf("NextWindowStart = %v, want %v", p.NextWindowStart, wantStart)
+		}
+		if wantEnd := monday.Add(24*time.Hour + 10*time.Hour + WindowTrail); p.NextWindowEnd == nil || !p.NextWindowEnd.Equal(wantEnd) {
+			t.Errorf("NextWindowEnd = %v, want %v", p.NextWindowEnd, wantEnd)
+		}
+		// Backed off until the window starts
+		if !p.NextRefresh.Equal(wantStart) {
+			t.Errorf("NextRefresh = %v, want %v", p.NextRefresh, wantStart)
+		}
+	})
+
+	t.Run("in a window", func(t *testing.T) {
+		now := monday.Add(24*time.Hour + 9*time.Hour + 20*time.Minute) // Tuesday 09:20
+		p := predictRefresh(published, now.Add(-10*time.Minute), now)
+		if p.Strategy != RefreshStrategyWindow || p.Interval() != WindowCheckInterval {
+			t.Fatalf("prediction = %s, %v", p.Strategy, p.Interval())
+		}
+	})
+
+	t.Run("shortly before a window", func(t *testing.T) {
+		now := monday.Add(24*time.Hour + 8*time.Hour + 50*time.Minute) // Tuesday 08:50
+		if p := predictRefresh(published, now.Add(-time.Hour), now); p.Strategy != RefreshStrategyWindow {
+			t.Errorf("Strategy = %s, want %s", p.Strategy, RefreshStrategyWindow)
+		}
+	})
+
+	t.Run("weekend", func(t *testing.T) {
+		now := monday.Add(-2*24*time.Hour + 12*time.Hour) // Saturday 12:00
+		p := predictRefresh(published, now.Add(-time.Hour), now)
+		if p.Strategy != RefreshStrategyQuiet || p.Interval() != MaxRefreshInterval {
+			t.Errorf("prediction = %s, %v, want %s, %v", p.Strategy, p.Interval(), RefreshStrategyQuiet, MaxRefreshInterval)
+		}
+		if p.NextWindowStart == nil || p.NextWindowStart.Weekday() != time.Monday {
+			t.Errorf("NextWindowStart = %v, want Monday", p.NextWindowStart)
+		}
+	})
+
+	p := predictRefresh(published, time.Time{}, monday)
+	if p.Samples != len(published) || len(p.Histogram) != 7*24 || p.Histogram[int(time.Wednesday)*24+9] != 4 {
+		t.Errorf("Samples = %d, histogram = %v", p.Samples, p.Histogram)
+	}
+	if p.PatternCoverage != 1 {
+		t.Errorf("PatternCoverage = %v, want 1", p.PatternCoverage)
+	}
+}
+
+func TestPredictRefresh_WithoutPattern(t *testing.T) {
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+
+	p := predictRefresh(nil, time.Time{}, now)
+	if p.Strategy != RefreshStrategyDefault || p.Interval() != DefaultRefreshInterval || !p.NextRefresh.Equal(now.Add(DefaultRefreshInterval)) {
+		t.Errorf("no articles: %+v", p)
+	}
+	if p := predictRefresh([]time.Time{now.Add(-time.Hour)}, now, now); p.Strategy != RefreshStrategyDefault || p.Interval() != DefaultRefreshInterval {
+		t.Errorf("single article: %s, %v", p.Strategy, p.Interval())
+	}
+
+	// Every 2 hours around the clock: half the mean gap
+	var published []time.Time
+	for i := 0; i < 40; i++ {
+		published = append(published, now.Add(-time.Duration(i*2)*time.Hour))
+	}
+	// Articles dated in the future are ignored
+	published = append(published, now.Add(time.Hour))
+	p = predictRefresh(published, now, now)
+	if p.Strategy != RefreshStrategyAverage || p.Interval() != time.Hour || p.Samples != 40 {
+		t.Errorf("regular articles: %s, %v, %d samples", p.Strategy, p.Interval(), p.Samples)
+	}
+	if p.NextWindowStart != nil {
+		t.Errorf("NextWindowStart = %v, want nil", p.NextWindowStart)
+	}
+
+	// Too few articles to trust a pattern
+	few := weekdayMornings(now.Truncate(24*time.Hour), 1)
+	if p := predictRefresh(few, now, now); p.Strategy != RefreshStrategyAverage {
+		t.Errorf("few articles: Strategy = %s, want %s", p.Strategy, RefreshStrategyAverage)
+	}
+}
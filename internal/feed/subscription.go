@@ -4,6 +4,7 @@ import (
 	"MrRSS/internal/models"
 	"MrRSS/internal/utils"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -55,6 +56,19 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 	}
 	debugTimer.Stage("HTTP client created")
 
+	// Follow redirects as usual, noting whether all of them were permanent so that
+	// the feed URL can be updated once the move is confirmed
+	permanentRedirect := true
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errTooManyRedirects
+		}
+		if req.Response == nil || !isPermanentRedirect(req.Response.StatusCode) {
+			permanentRedirect = false
+		}
+		return nil
+	}
+
 	debugTimer.LogWithTime("Creating HTTP request")
 	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
 	if err != nil {
@@ -75,6 +89,13 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 	}
 	defer resp.Body.Close()
 	debugTimer.Stage("HTTP request completed")
+	attemptFrom(ctx).setResponse(resp.StatusCode, 0)
+
+	// Servers asking to slow down are not requested again until they allow it
+	if err := rateLimitError(resp, time.Now()); err != nil {
+		debugTimer.LogWithTime("Rate limited: %v", err)
+		return "", err
+	}
 
 	if resp.StatusCode != http.StatusOK {
 		debugTimer.LogWithTime("HTTP status not OK: %d", resp.StatusCode)
@@ -90,6 +111,10 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 		return "", fmt.Errorf("failed to read response body: %w", err)
 	}
 	debugTimer.LogWithTime("Read %d bytes from response", len(body))
+	attemptFrom(ctx).setResponse(resp.StatusCode, int64(len(body)))
+	if finalURL := resp.Request.URL.String(); permanentRedirect && finalURL != feedURL {
+		attemptFrom(ctx).setRedirect(finalURL)
+	}
 	debugTimer.Stage("Body read complete")
 
 	xmlContent := string(body)
@@ -107,8 +132,16 @@ func (f *Fetcher) fetchAndSanitizeFeed(ctx context.Context, feedURL string) (str
 func (f *Fetcher) AddSubscription(url string, category string, customTitle string) (int64, error) {
 	utils.DebugLog("AddSubscription: Starting to add feed from URL: %s", url)
 
-	// Try fetching and sanitizing the feed first
+	// Subscribe to the feed of YouTube channel and playlist pages
 	ctx := context.Background()
+	if feedURL, err := f.resolveVideoFeedURL(ctx, url); err != nil {
+		utils.DebugLog("AddSubscription: Failed to resolve video feed URL for %s: %v", url, err)
+	} else if feedURL != "" {
+		utils.DebugLog("AddSubscription: Resolved %s to video feed %s", url, feedURL)
+		url = feedURL
+	}
+
+	// Try fetching and sanitizing the feed first
 	cleanedXML, err := f.fetchAndSanitizeFeed(ctx, url)
 	if err != nil {
 		utils.DebugLog("AddSubscription: Failed to fetch feed for %s: %v", url, err)
@@ -123,24 +156,7 @@ func (f *Fetcher) AddSubscription(url string, category string, customTitle strin
 		parsedFeed, parseErr := parser.ParseString(cleanedXML)
 		if parseErr == nil {
 			utils.DebugLog("AddSubscription: Successfully parsed sanitized feed for URL: %s", url)
-			title := parsedFeed.Title
-			if customTitle != "" {
-				title = customTitle
-			}
-
-			feed := &models.Feed{
-				Title:       title,
-				URL:         url,
-				Link:        parsedFeed.Link,
-				Description: parsedFeed.Description,
-				Category:    category,
-			}
-
-			if parsedFeed.Image != nil {
-				feed.ImageURL = parsedFeed.Image.URL
-			}
-
-			return f.db.AddFeed(feed)
+			return f.AddParsedSubscription(url, category, customTitle, parsedFeed)
 		}
 		utils.DebugLog("AddSubscription: Parsing sanitized feed failed: %v", parseErr)
 	}
@@ -185,6 +201,12 @@ func (f *Fetcher) AddSubscription(url string, category string, customTitle strin
 		utils.DebugLog("AddSubscription: Standard RSS parsing succeeded for URL: %s", url)
 	}
 
+	return f.AddParsedSubscription(url, category, customTitle, parsedFeed)
+}
+
+// AddParsedSubscription adds a new feed subscription from a feed already downloaded
+// and parsed, and returns the feed ID.
+func (f *Fetcher) AddParsedSubscription(url string, category string, customTitle string, parsedFeed *gofeed.Feed) (int64, error) {
 	title := parsedFeed.Title
 	if customTitle != "" {
 		title = customTitle
@@ -382,15 +404,23 @@ func (f *Fetcher) AddXPathSubscription(url string, category string, customTitle
 	return f.db.AddFeed(feed)
 }
 
-// ImportSubscription imports a feed subscription and returns the feed ID.
-func (f *Fetcher) ImportSubscription(title, url, category string) (int64, error) {
-	feed := &models.Feed{
-		Title:    title,
-		URL:      url,
-		Link:     "", // Link will be fetched later when feed is refreshed
-		Category: category,
-	}
-	return f.db.AddFeed(feed)
+// ImportSubscription imports a feed subscription with its configuration, as parsed
+// from an OPML or JSON file, and returns the feed ID.
+func (f *Fetcher) ImportSubscription(imported models.Feed) (int64, error) {
+	// Only keep the configuration; state is rebuilt when the feed is refreshed
+	feed := imported
+	feed.ID = 0
+	feed.Position = 0
+	feed.LastUpdated = time.Time{}
+	feed.LastError = ""
+	feed.DiscoveryCompleted = false
+	feed.EmailLastUID = 0
+	feed.IsFreshRSSSource = false
+	feed.FreshRSSStreamID = ""
+	feed.LatestArticleTime = nil
+	feed.ArticlesPerMonth = 0
+	feed.LastUpdateStatus = ""
+	return f.db.AddFeed(&feed)
 }
 
 // ParseFeed parses an RSS feed from a URL and returns the parsed feed
@@ -446,6 +476,14 @@ func (f *Fetcher) parseFeedWithFeedInternal(ctx context.Context, feed *models.Fe
 		return parsedFeed, nil
 	}
 
+	// Items imported from other readers have nothing to fetch
+	if feed.Type == "imported" {
+		return &gofeed.Feed{
+			Title:       feed.Title,
+			Description: feed.Description,
+		}, nil
+	}
+
 	if feed.ScriptPath != "" {
 		utils.DebugLog("parseFeedWithFeedInternal: Using script execution for %s", feed.ScriptPath)
 		// Execute the custom script to fetch feed
@@ -496,6 +534,12 @@ func (f *Fetcher) parseFeedWithFeedInternal(ctx context.Context, feed *models.Fe
 	cleanedXML, sanitizeErr := f.fetchAndSanitizeFeed(fetchCtx, feed.URL)
 	debugTimer.LogWithTime("fetchAndSanitizeFeed completed, err=%v", sanitizeErr)
 
+	// Requesting the feed again for the fallbacks would ignore the server's request to slow down
+	var rateErr *RateLimitedError
+	if errors.As(sanitizeErr, &rateErr) {
+		return nil, sanitizeErr
+	}
+
 	if sanitizeErr == nil {
 		debugTimer.Stage("Parsing sanitized XML")
 		// Successfully fetched and sanitized, try parsing
@@ -511,6 +555,8 @@ func (f *Fetcher) parseFeedWithFeedInternal(ctx context.Context, feed *models.Fe
 		if err == nil {
 			debugTimer.Stage("Successfully parsed sanitized feed")
 			utils.DebugLog("parseFeedWithFeedInternal: Successfully parsed sanitized feed for %s", feed.URL)
+			f.recordWebSubHub(feed, cleanedXML)
+			f.recordRefreshHints(feed, parsedFeed, cleanedXML)
 			return parsedFeed, nil
 		}
 		utils.DebugLog("parseFeedWithFeedInternal: Parsing sanitized feed failed: %v", err)
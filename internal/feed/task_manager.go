@@ -3,6 +3,7 @@ package feed
 import (
 	"MrRSS/internal/models"
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
@@ -23,11 +24,29 @@ const (
 	TaskReasonArticleClick                      // Article content missing
 )
 
+// String returns the name of the reason recorded in the fetch log
+func (r TaskReason) String() string {
+	switch r {
+	case TaskReasonManualAdd:
+		return "manual_add"
+	case TaskReasonManualRefresh:
+		return "manual_refresh"
+	case TaskReasonScheduledCustom:
+		return "scheduled_custom"
+	case TaskReasonScheduledGlobal:
+		return "scheduled_global"
+	case TaskReasonArticleClick:
+		return "article_click"
+	}
+	return "unknown"
+}
+
 // RefreshTask represents a single feed refresh task
 type RefreshTask struct {
 	Feed      models.Feed
 	Reason    TaskReason
 	CreatedAt time.Time
+	Host      string // Host whose limits the task counts against
 }
 
 // TaskManager manages the task queue and pool for feed refreshing
@@ -35,8 +54,15 @@ type TaskManager struct {
 	fetcher *Fetcher
 
 	// Double-ended queue for pending tasks
-	queue      []int64 // Feed IDs only for efficient storage
-	queueMutex sync.RWMutex
+	queue        []int64              // Feed IDs only for efficient storage
+	queueHosts   map[int64]string     // Hosts of the queued feeds
+	queueReasons map[int64]TaskReason // Reasons the feeds were queued for
+	queueMutex   sync.RWMutex
+
+	// Per-host limits, and when processing resumes for tasks held back by them
+	hosts     *hostLimiter
+	wakeAt    time.Time
+	wakeMutex sync.Mutex
 
 	// Task pool for active tasks (limited capacity)
 	pool      map[int64]*RefreshTask
@@ -68,6 +94,7 @@ type TaskManager struct {
 // TaskStats represents runtime statistics
 type TaskStats struct {
 	PoolTaskCount     int // Tasks currently in pool
+	PoolCapacity      int // Tasks the pool runs at the same time
 	ArticleClickCount int // Article click triggered tasks
 	QueueTaskCount    int // Tasks in queue
 }
@@ -81,6 +108,9 @@ func NewTaskManager(fetcher *Fetcher, poolCapacity int) *TaskManager {
 	tm := &TaskManager{
 		fetcher:      fetcher,
 		queue:        make([]int64, 0),
+		queueHosts:   make(map[int64]string),
+		queueReasons: make(map[int64]TaskReason),
+		hosts:        newHostLimiter(),
 		pool:         make(map[int64]*RefreshTask),
 		poolCapacity: poolCapacity,
 		poolSem:      make(chan struct{}, poolCapacity),
@@ -147,6 +177,8 @@ func (tm *TaskManager) Stop() {
 	// Clear state
 	tm.queueMutex.Lock()
 	tm.queue = make([]int64, 0)
+	tm.queueHosts = make(map[int64]string)
+	tm.queueReasons = make(map[int64]TaskReason)
 	tm.queueMutex.Unlock()
 
 	log.Println("Task manager stopped")
@@ -212,6 +244,8 @@ func (tm *TaskManager) AddToQueueHead(ctx context.Context, feed models.Feed, rea
 	if !inPool {
 		// Add to queue head
 		tm.queue = append([]int64{feed.ID}, tm.queue...)
+		tm.queueHosts[feed.ID] = feedHost(feed)
+		tm.queueReasons[feed.ID] = reason
 		added = true
 	}
 
@@ -246,6 +280,12 @@ func (tm *TaskManager) AddToQueueTail(ctx context.Context, feed models.Feed, rea
 		return
 	}
 
+	// Skip feeds asking not to be refreshed yet, with <ttl>, sy:updatePeriod, <skipHours> or <skipDays>
+	if !HintsAllowRefresh(feed, time.Now()) {
+		log.Printf("Skipping feed %s (refresh limits of the feed)", feed.Title)
+		return
+	}
+
 	tm.stateMutex.RLock()
 	isStopped := tm.isStopped
 	tm.stateMutex.RUnlock()
@@ -276,6 +316,8 @@ func (tm *TaskManager) AddToQueueTail(ctx context.Context, feed models.Feed, rea
 	var added bool
 	if !inQueue && !inPool {
 		tm.queue = append(tm.queue, feed.ID)
+		tm.queueHosts[feed.ID] = feedHost(feed)
+		tm.queueReasons[feed.ID] = reason
 		added = true
 	}
 
@@ -316,12 +358,17 @@ func (tm *TaskManager) AddGlobalRefresh(ctx context.Context, feeds []models.Feed
 		return
 	}
 
-	// Filter out FreshRSS feeds - they are refreshed via sync, not standard refresh
+	// Filter out FreshRSS feeds - they are refreshed via sync, not standard refresh -
+	// and consistently failing feeds until their backoff ends
 	filteredFeeds := make([]models.Feed, 0, len(feeds))
 	skippedCount := 0
+	backoffCount := 0
+	now := time.Now()
 	for _, feed := range feeds {
 		if feed.IsFreshRSSSource {
 			skippedCount++
+		} else if InBackoff(feed, now) {
+			backoffCount++
 		} else {
 			filteredFeeds = append(filteredFeeds, feed)
 		}
@@ -329,6 +376,9 @@ func (tm *TaskManager) AddGlobalRefresh(ctx context.Context, feeds []models.Feed
 	if skippedCount > 0 {
 		log.Printf("Filtered out %d FreshRSS feeds from global refresh (refreshed via sync only)", skippedCount)
 	}
+	if backoffCount > 0 {
+		log.Printf("Filtered out %d failing feeds from global refresh (backing off)", backoffCount)
+	}
 	feeds = filteredFeeds
 
 	if len(feeds) == 0 {
@@ -381,6 +431,8 @@ func (tm *TaskManager) AddGlobalRefresh(ctx context.Context, feeds []models.Feed
 	for _, feed := range feeds {
 		if !existingFeedIDs[feed.ID] {
 			tm.queue = append(tm.queue, feed.ID)
+			tm.queueHosts[feed.ID] = feedHost(feed)
+			tm.queueReasons[feed.ID] = TaskReasonScheduledGlobal
 			existingFeedIDs[feed.ID] = true
 			addedCount++
 			addedFeeds = append(addedFeeds, feed)
@@ -420,6 +472,8 @@ func (tm *TaskManager) ExecuteImmediately(ctx context.Context, feed models.Feed)
 	// Remove from queue if present
 	tm.queueMutex.Lock()
 	removedFromQueue := removeFromQueue(&tm.queue, feed.ID)
+	delete(tm.queueHosts, feed.ID)
+	delete(tm.queueReasons, feed.ID)
 	tm.queueMutex.Unlock()
 
 	// Remove from pool if present
@@ -443,6 +497,7 @@ func (tm *TaskManager) ExecuteImmediately(ctx context.Context, feed models.Feed)
 		Feed:      feed,
 		Reason:    TaskReasonArticleClick,
 		CreatedAt: time.Now(),
+		Host:      feedHost(feed),
 	}
 
 	// Update stats (increment article click count)
@@ -481,26 +536,32 @@ func (tm *TaskManager) ExecuteImmediately(ctx context.Context, feed models.Feed)
 		ctx1, cancel1 := context.WithTimeout(ctx, 10*time.Second)
 		defer cancel1()
 
-		err = tm.fetcher.fetchFeedWithContext(ctx1, task.Feed)
+		err = tm.fetcher.fetchAndRecord(ctx1, task.Feed, task.Reason)
 		if err == nil {
 			success = true
 			log.Printf("Successfully fetched feed: %s (immediate, first attempt)", task.Feed.Title)
 		}
 
-		// Second attempt: use configured retry timeout if first attempt failed
-		if !success && err != nil {
+		// Second attempt: use configured retry timeout if first attempt failed,
+		// unless the server asked to slow down
+		if !success && err != nil && !tm.throttleHost(task, err) {
 			log.Printf("First attempt failed for %s: %v, retrying with %v timeout", task.Feed.Title, err, retryTimeoutSeconds)
 
 			ctx2, cancel2 := context.WithTimeout(ctx, retryTimeoutSeconds)
 			defer cancel2()
 
-			err = tm.fetcher.fetchFeedWithContext(ctx2, task.Feed)
+			err = tm.fetcher.fetchAndRecord(ctx2, task.Feed, task.Reason)
 			if err == nil {
 				success = true
 				log.Printf("Successfully fetched feed: %s (immediate, second attempt)", task.Feed.Title)
+			} else {
+				tm.throttleHost(task, err)
 			}
 		}
 
+		// Follow moved feeds, mark gone ones and back off failing ones
+		tm.fetcher.handleRefreshResult(task.Feed.ID, err)
+
 		// Handle result
 		if err != nil {
 			log.Printf("Failed to fetch feed %s (immediate): %v", task.Feed.Title, err)
@@ -541,18 +602,54 @@ func (tm *TaskManager) processQueue(ctx context.Context) {
 		tm.queueMutex.Lock()
 		tm.poolMutex.Lock()
 
-		// Get next task from queue
+		// Get the first task from the queue whose host limits allow it
 		var feedID int64
+		var host string
+		var reason TaskReason
+		var wakeAt time.Time
+		var dropped []int64
 		if len(tm.queue) > 0 && len(tm.pool) < tm.poolCapacity {
-			feedID = tm.queue[0]
-			tm.queue = tm.queue[1:]
+			now := time.Now()
+			for i := 0; i < len(tm.queue); i++ {
+				id := tm.queue[i]
+				ok, readyAt := tm.hosts.acquire(tm.queueHosts[id], now)
+				if ok {
+					feedID = id
+					host = tm.queueHosts[id]
+					reason = tm.queueReasons[id]
+					tm.queue = append(tm.queue[:i], tm.queue[i+1:]...)
+					delete(tm.queueHosts, id)
+					delete(tm.queueReasons, id)
+					break
+				}
+				if readyAt.Sub(now) > MaxThrottleWait {
+					// Leave feeds of hosts throttled for long to a later refresh
+					dropped = append(dropped, id)
+					tm.queue = append(tm.queue[:i], tm.queue[i+1:]...)
+					delete(tm.queueHosts, id)
+					delete(tm.queueReasons, id)
+					i--
+					continue
+				}
+				if !readyAt.IsZero() && (wakeAt.IsZero() || readyAt.Before(wakeAt)) {
+					wakeAt = readyAt
+				}
+			}
 		}
 
 		tm.poolMutex.Unlock()
 		tm.queueMutex.Unlock()
 
+		if len(dropped) > 0 {
+			log.Printf("Skipped %d queued feeds of throttled hosts", len(dropped))
+			tm.updateStats()
+		}
+
 		if feedID == 0 {
-			// No task available or pool is full
+			// No task available, pool is full or the hosts of the queued tasks are busy
+			if !wakeAt.IsZero() {
+				tm.wakeAfter(ctx, wakeAt)
+			}
 			tm.checkCompletion()
 			return
 		}
@@ -561,14 +658,16 @@ func (tm *TaskManager) processQueue(ctx context.Context) {
 		feed, err := tm.fetcher.db.GetFeedByID(feedID)
 		if err != nil {
 			log.Printf("Error getting feed %d: %v", feedID, err)
+			tm.hosts.release(host)
 			continue
 		}
 
 		// Create task
 		task := &RefreshTask{
 			Feed:      *feed,
-			Reason:    TaskReasonScheduledGlobal, // Default reason
+			Reason:    reason,
 			CreatedAt: time.Now(),
+			Host:      host,
 		}
 
 		// Acquire semaphore FIRST (this will block if pool is at capacity)
@@ -595,8 +694,9 @@ func (tm *TaskManager) processQueue(ctx context.Context) {
 // processTask processes a single task with timeout and retry logic
 func (tm *TaskManager) processTask(ctx context.Context, task *RefreshTask) {
 	defer func() {
-		// Release semaphore
+		// Release semaphore and host
 		<-tm.poolSem
+		tm.hosts.release(task.Host)
 		tm.wg.Done()
 
 		// Remove from pool
@@ -632,27 +732,33 @@ func (tm *TaskManager) processTask(ctx context.Context, task *RefreshTask) {
 	defer cancel1()
 
 	log.Printf("Starting first attempt to fetch feed: %s (timeout: 60s)", task.Feed.Title)
-	err = tm.fetcher.fetchFeedWithContext(ctx1, task.Feed)
+	err = tm.fetcher.fetchAndRecord(ctx1, task.Feed, task.Reason)
 	if err == nil {
 		success = true
 		log.Printf("Successfully fetched feed: %s (first attempt)", task.Feed.Title)
 	}
 
-	// Second attempt: use configured retry timeout if first attempt failed
-	if !success && err != nil {
+	// Second attempt: use configured retry timeout if first attempt failed,
+	// unless the server asked to slow down
+	if !success && err != nil && !tm.throttleHost(task, err) {
 		log.Printf("First attempt failed for %s: %v, retrying with %v timeout", task.Feed.Title, err, retryTimeoutSeconds)
 		tm.logOperation("RT", task.Feed.Title)
 
 		ctx2, cancel2 := context.WithTimeout(ctx, retryTimeoutSeconds)
 		defer cancel2()
 
-		err = tm.fetcher.fetchFeedWithContext(ctx2, task.Feed)
+		err = tm.fetcher.fetchAndRecord(ctx2, task.Feed, task.Reason)
 		if err == nil {
 			success = true
 			log.Printf("Successfully fetched feed: %s (second attempt)", task.Feed.Title)
+		} else {
+			tm.throttleHost(task, err)
 		}
 	}
 
+	// Follow moved feeds, mark gone ones and back off failing ones
+	tm.fetcher.handleRefreshResult(task.Feed.ID, err)
+
 	// Handle result
 	if err != nil {
 		log.Printf("Failed to fetch feed %s after retry: %v", task.Feed.Title, err)
@@ -677,6 +783,34 @@ func (tm *TaskManager) processTask(ctx context.Context, task *RefreshTask) {
 	}
 }
 
+// wakeAfter processes the queue again at a time, when tasks held back by their host limits may start
+func (tm *TaskManager) wakeAfter(ctx context.Context, at time.Time) {
+	tm.wakeMutex.Lock()
+	defer tm.wakeMutex.Unlock()
+
+	// An earlier wake up processes the queue anyway
+	if now := time.Now(); tm.wakeAt.After(now) && !tm.wakeAt.After(at) {
+		return
+	}
+	tm.wakeAt = at
+	time.AfterFunc(time.Until(at), func() {
+		tm.processQueue(ctx)
+	})
+}
+
+// throttleHost holds back the tasks of a host that asked to slow down, and reports whether it did
+func (tm *TaskManager) throttleHost(task *RefreshTask, err error) bool {
+	var rateErr *RateLimitedError
+	if !errors.As(err, &rateErr) {
+		return false
+	}
+	until := time.Now().Add(rateErr.RetryAfter)
+	log.Printf("Host of feed %s asked to slow down (HTTP %d), throttling it until %s", task.Feed.Title, rateErr.StatusCode, until.Format(time.RFC3339))
+	tm.hosts.throttle(task.Host, until)
+	tm.logOperation("TH", task.Feed.Title)
+	return true
+}
+
 // checkCompletion checks if all tasks are completed and triggers cleanup if needed
 func (tm *TaskManager) checkCompletion() {
 	tm.queueMutex.RLock()
@@ -702,6 +836,10 @@ func (tm *TaskManager) checkCompletion() {
 
 		// Trigger cleanup through cleanup manager
 		tm.fetcher.cleanupManager.RequestCleanup()
+
+		if tm.fetcher.onRefreshComplete != nil {
+			go tm.fetcher.onRefreshComplete()
+		}
 	}
 }
 
@@ -724,6 +862,7 @@ func (tm *TaskManager) GetStats() TaskStats {
 	// Also get current pool and queue counts
 	tm.poolMutex.RLock()
 	poolLen := len(tm.pool)
+	poolCapacity := tm.poolCapacity
 	tm.poolMutex.RUnlock()
 
 	tm.queueMutex.RLock()
@@ -732,6 +871,7 @@ func (tm *TaskManager) GetStats() TaskStats {
 
 	stats := TaskStats{
 		PoolTaskCount:     poolLen,
+		PoolCapacity:      poolCapacity,
 		ArticleClickCount: tm.stats.ArticleClickCount,
 		QueueTaskCount:    queueLen,
 	}
@@ -783,13 +923,16 @@ func (tm *TaskManager) GetPoolTasks() []PoolTaskInfo {
 	tm.poolMutex.RLock()
 	defer tm.poolMutex.RUnlock()
 
+	now := time.Now()
 	tasks := make([]PoolTaskInfo, 0, len(tm.pool))
 	for _, task := range tm.pool {
 		tasks = append(tasks, PoolTaskInfo{
-			FeedID:    task.Feed.ID,
-			FeedTitle: task.Feed.Title,
-			Reason:    task.Reason,
-			CreatedAt: task.CreatedAt,
+			FeedID:         task.Feed.ID,
+			FeedTitle:      task.Feed.Title,
+			Reason:         task.Reason,
+			CreatedAt:      task.CreatedAt,
+			Host:           task.Host,
+			ThrottledUntil: tm.hosts.throttledUntil(task.Host, now),
 		})
 	}
 
@@ -817,15 +960,19 @@ func (tm *TaskManager) GetQueueTasks(limit int) []QueueTaskInfo {
 		count = limit
 	}
 
+	now := time.Now()
 	tasks := make([]QueueTaskInfo, 0, count)
 	for i := 0; i < count; i++ {
 		feedID := tm.queue[i]
 		feed, err := tm.fetcher.db.GetFeedByID(feedID)
 		if err == nil {
+			host := tm.queueHosts[feedID]
 			tasks = append(tasks, QueueTaskInfo{
-				FeedID:    feed.ID,
-				FeedTitle: feed.Title,
-				Position:  i,
+				FeedID:         feed.ID,
+				FeedTitle:      feed.Title,
+				Position:       i,
+				Host:           host,
+				ThrottledUntil: tm.hosts.throttledUntil(host, now),
 			})
 		}
 	}
@@ -835,17 +982,21 @@ func (tm *TaskManager) GetQueueTasks(limit int) []QueueTaskInfo {
 
 // PoolTaskInfo contains information about a task in the pool
 type PoolTaskInfo struct {
-	FeedID    int64      `json:"feed_id"`
-	FeedTitle string     `json:"feed_title"`
-	Reason    TaskReason `json:"reason"`
-	CreatedAt time.Time  `json:"created_at"`
+	FeedID         int64      `json:"feed_id"`
+	FeedTitle      string     `json:"feed_title"`
+	Reason         TaskReason `json:"reason"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Host           string     `json:"host,omitempty"`
+	ThrottledUntil *time.Time `json:"throttled_until,omitempty"` // Set while the host asked to slow down
 }
 
 // QueueTaskInfo contains information about a task in the queue
 type QueueTaskInfo struct {
-	FeedID    int64  `json:"feed_id"`
-	FeedTitle string `json:"feed_title"`
-	Position  int    `json:"position"`
+	FeedID         int64      `json:"feed_id"`
+	FeedTitle      string     `json:"feed_title"`
+	Position       int        `json:"position"`
+	Host           string     `json:"host,omitempty"`
+	ThrottledUntil *time.Time `json:"throttled_until,omitempty"` // Set while the host asked to slow down
 }
 
 // IsRunning returns true if the task manager is running
@@ -881,6 +1032,8 @@ func (tm *TaskManager) ClearQueue() {
 	defer tm.queueMutex.Unlock()
 
 	tm.queue = make([]int64, 0)
+	tm.queueHosts = make(map[int64]string)
+	tm.queueReasons = make(map[int64]TaskReason)
 
 	log.Println("Queue cleared")
 }
@@ -991,7 +1144,7 @@ func (tm *TaskManager) initTaskLog() {
 // logOperation logs a task operation with the specified format
 // Format: AF/AR/MV/RT/SC/FL n/m name
 // AF = Add to Front (queue head), AR = Add to Rear (queue tail)
-// MV = Move to Pool, RT = Retry, SC = Success, FL = Failure
+// MV = Move to Pool, RT = Retry, SC = Success, FL = Failure, TH = Host throttled
 // n = pool task count, m = queue task count
 func (tm *TaskManager) logOperation(operation string, feedName string) {
 	if !tm.logEnabled || tm.logFile == nil {
@@ -0,0 +1,237 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+)
+
+// youtubeThumbnailSizes are the thumbnail variants YouTube serves for every
+// video. Larger variants (sddefault, maxresdefault) are missing for some videos.
+var youtubeThumbnailSizes = []struct {
+	name          string
+	width, height int
+}{
+	{"default", 120, 90},
+	{"mqdefault", 320, 180},
+	{"hqdefault", 480, 360},
+}
+
+// extractVideoInfo extracts the metadata of a video feed item from its Media RSS
+// extensions: duration, view count, star rating and thumbnails.
+// Returns nil if the item is not a video. ArticleID is set once the article is saved.
+func extractVideoInfo(item *gofeed.Item, videoURL string) *models.VideoInfo {
+	info := &models.VideoInfo{}
+	isVideo := videoURL != ""
+
+	if videoURL != "" {
+		info.VideoID = extractYouTubeVideoID(item.Link)
+		if info.VideoID == "" {
+			info.VideoID = strings.TrimPrefix(videoURL, "https://www.youtube.com/embed/")
+		}
+	}
+
+	for _, content := range mediaElements(item, "content") {
+		medium := content.Attrs["medium"]
+		if medium != "video" && !strings.HasPrefix(content.Attrs["type"], "video/") && videoURL == "" {
+			continue
+		}
+		isVideo = true
+		if duration, err := strconv.ParseFloat(content.Attrs["duration"], 64); err == nil && int(duration) > info.Duration {
+			info.Duration = int(duration)
+		}
+	}
+	if !isVideo {
+		return nil
+	}
+
+	if community := mediaElements(item, "community"); len(community) > 0 {
+		if statistics := community[0].Children["statistics"]; len(statistics) > 0 {
+			info.Views, _ = strconv.ParseInt(statistics[0].Attrs["views"], 10, 64)
+		}
+		if rating := community[0].Children["starRating"]; len(rating) > 0 {
+			info.RatingCount, _ = strconv.ParseInt(rating[0].Attrs["count"], 10, 64)
+			info.RatingAverage, _ = strconv.ParseFloat(rating[0].Attrs["average"], 64)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, thumbnail := range mediaElements(item, "thumbnail") {
+		thumbnailURL := thumbnail.Attrs["url"]
+		if thumbnailURL == "" || seen[thumbnailURL] {
+			continue
+		}
+		seen[thumbnailURL] = true
+		width, _ := strconv.Atoi(thumbnail.Attrs["width"])
+		height, _ := strconv.Atoi(thumbnail.Attrs["height"])
+		info.Thumbnails = append(info.Thumbnails, models.VideoThumbnail{URL: thumbnailURL, Width: width, Height: height})
+	}
+	if info.VideoID != "" {
+	youtubeSizes:
+		for _, size := range youtubeThumbnailSizes {
+			// The feed lists some variants on other ytimg.com hosts
+			path := "/vi/" + info.VideoID + "/" + size.name + ".jpg"
+			for thumbnailURL := range seen {
+				if strings.HasSuffix(thumbnailURL, path) {
+					continue youtubeSizes
+				}
+			}
+			info.Thumbnails = append(info.Thumbnails, models.VideoThumbnail{URL: "https://i.ytimg.com" + path, Width: size.width, Height: size.height})
+		}
+	}
+	sort.SliceStable(info.Thumbnails, func(i, j int) bool {
+		return info.Thumbnails[i].Width < info.Thumbnails[j].Width
+	})
+
+	return info
+}
+
+// mediaElements returns the Media RSS elements with the given name, both inside
+// media:group (used by YouTube) and directly under the item
+func mediaElements(item *gofeed.Item, name string) []ext.Extension {
+	mediaExt, ok := item.Extensions["media"]
+	if !ok {
+		return nil
+	}
+
+	var elements []ext.Extension
+	for _, group := range mediaExt["group"] {
+		elements = append(elements, group.Children[name]...)
+	}
+	return append(elements, mediaExt[name]...)
+}
+
+// pendingTranscript is a saved video article whose captions were not fetched yet
+type pendingTranscript struct {
+	articleID int64
+	videoID   string
+	lang      string
+	awc       *ArticleWithContent
+}
+
+// saveVideoInfo stores the video metadata of saved articles and adds the stored
+// transcripts to their cached content, which was just replaced by the feed content.
+// It returns the YouTube videos whose captions have not been fetched yet.
+func (f *Fetcher) saveVideoInfo(articlesWithContent []*ArticleWithContent) []pendingTranscript {
+	var pending []pendingTranscript
+	for _, awc := range articlesWithContent {
+		if awc.Video == nil {
+			continue
+		}
+
+		articleID, err := f.db.GetArticleIDByUniqueID(awc.Article.Title, awc.Article.FeedID, awc.Article.PublishedAt, awc.Article.HasValidPublishedTime)
+		if err != nil {
+			utils.DebugLog("Could not find article ID for %s: %v", awc.Article.Title, err)
+			continue
+		}
+
+		info := *awc.Video
+		info.ArticleID = articleID
+		if err := f.db.SaveVideoInfo(info); err != nil {
+			utils.DebugLog("Error saving video metadata for article %d: %v", articleID, err)
+			continue
+		}
+
+		_, transcript, found, err := f.db.GetVideoTranscript(articleID)
+		if err != nil {
+			utils.DebugLog("Error getting transcript for article %d: %v", articleID, err)
+			continue
+		}
+		if !found {
+			if info.VideoID != "" {
+				pending = append(pending, pendingTranscript{articleID: articleID, videoID: info.VideoID, lang: awc.Article.Lang, awc: awc})
+			}
+			continue
+		}
+		f.addTranscriptToContent(articleID, awc, transcript)
+	}
+	return pending
+}
+
+// youtubeChannelIDPatterns find the channel ID in the HTML of a YouTube channel page
+var youtubeChannelIDPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`youtube\.com/feeds/videos\.xml\?channel_id=(UC[\w-]{22})`),
+	regexp.MustCompile(`"externalId"\s*:\s*"(UC[\w-]{22})"`),
+	regexp.MustCompile(`<meta itemprop="(?:identifier|channelId)" content="(UC[\w-]{22})"`),
+}
+
+// youtubeFeedURL returns the feed URL of a YouTube channel, user or playlist URL.
+// needsPage is true for handles (@name) and custom URLs (/c/name), whose channel
+// ID must be read from the channel page. Returns an empty URL for other URLs.
+func youtubeFeedURL(rawURL string) (feedURL string, needsPage bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, prefix := range []string{"www.", "m.", "music."} {
+		host = strings.TrimPrefix(host, prefix)
+	}
+	if host != "youtube.com" {
+		return "", false
+	}
+
+	const feedBase = "https://www.youtube.com/feeds/videos.xml?"
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case segments[0] == "playlist" && u.Query().Get("list") != "":
+		return feedBase + url.Values{"playlist_id": {u.Query().Get("list")}}.Encode(), false
+	case segments[0] == "channel" && len(segments) > 1 && strings.HasPrefix(segments[1], "UC"):
+		return feedBase + url.Values{"channel_id": {segments[1]}}.Encode(), false
+	case segments[0] == "user" && len(segments) > 1 && segments[1] != "":
+		return feedBase + url.Values{"user": {segments[1]}}.Encode(), false
+	case strings.HasPrefix(segments[0], "@") && len(segments[0]) > 1,
+		segments[0] == "c" && len(segments) > 1 && segments[1] != "":
+		return "", true
+	}
+	return "", false
+}
+
+// resolveVideoFeedURL returns the feed URL of a YouTube channel or playlist URL,
+// or an empty string if the URL is not one
+func (f *Fetcher) resolveVideoFeedURL(ctx context.Context, pageURL string) (string, error) {
+	feedURL, needsPage := youtubeFeedURL(pageURL)
+	if !needsPage {
+		return feedURL, nil
+	}
+
+	client, err := f.getHTTPClient(models.Feed{URL: pageURL})
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch channel page: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch channel page: %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5*1024*1024))
+	if err != nil {
+		return "", fmt.Errorf("failed to read channel page: %w", err)
+	}
+
+	for _, pattern := range youtubeChannelIDPatterns {
+		if matches := pattern.FindSubmatch(body); len(matches) > 1 {
+			return "https://www.youtube.com/feeds/videos.xml?channel_id=" + string(matches[1]), nil
+		}
+	}
+	return "", fmt.Errorf("channel ID not found on %s", pageURL)
+}
@@ -0,0 +1,97 @@
+package feed
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestExtractVideoInfo(t *testing.T) {
+	atom := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+<title>Channel</title>
+<entry>
+	<yt:videoId>dQw4w9WgXcQ</yt:videoId>
+	<title>Video</title>
+	<link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+	<media:group>
+		<media:title>Video</media:title>
+		<media:content url="https://www.youtube.com/v/dQw4w9WgXcQ?version=3" type="application/x-shockwave-flash" width="640" height="390"/>
+		<media:thumbnail url="https://i4.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"/>
+		<media:description>Description</media:description>
+		<media:community>
+			<media:starRating count="1234" average="5.00" min="1" max="5"/>
+			<media:statistics views="98765"/>
+		</media:community>
+	</media:group>
+</entry>
+<entry>
+	<title>Clip</title>
+	<link rel="alternate" href="https://example.com/clip"/>
+	<media:content url="https://example.com/clip.mp4" type="video/mp4" duration="125.4"/>
+	<media:thumbnail url="https://example.com/clip.jpg"/>
+</entry>
+<entry>
+	<title>Post</title>
+	<link rel="alternate" href="https://example.com/post"/>
+	<media:content url="https://example.com/photo.jpg" medium="image"/>
+</entry>
+</feed>`
+
+	parsed, err := gofeed.NewParser().Parse(strings.NewReader(atom))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	youtube := extractVideoInfo(parsed.Items[0], extractVideoURL(parsed.Items[0]))
+	if youtube == nil {
+		t.Fatal("expected video info for YouTube entry")
+	}
+	if youtube.VideoID != "dQw4w9WgXcQ" || youtube.Views != 98765 || youtube.RatingCount != 1234 || youtube.RatingAverage != 5 {
+		t.Errorf("unexpected video info: %+v", youtube)
+	}
+	// The feed thumbnail and the derived default and mqdefault variants, smallest first
+	if len(youtube.Thumbnails) != 3 {
+		t.Fatalf("expected 3 thumbnails, got %+v", youtube.Thumbnails)
+	}
+	if youtube.Thumbnails[0].Width != 120 || youtube.Thumbnails[2].URL != "https://i4.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+		t.Errorf("unexpected thumbnails: %+v", youtube.Thumbnails)
+	}
+
+	clip := extractVideoInfo(parsed.Items[1], extractVideoURL(parsed.Items[1]))
+	if clip == nil {
+		t.Fatal("expected video info for video/mp4 entry")
+	}
+	if clip.VideoID != "" || clip.Duration != 125 || len(clip.Thumbnails) != 1 {
+		t.Errorf("unexpected video info: %+v", clip)
+	}
+
+	if info := extractVideoInfo(parsed.Items[2], extractVideoURL(parsed.Items[2])); info != nil {
+		t.Errorf("expected no video info for image entry, got %+v", info)
+	}
+}
+
+func TestYouTubeFeedURL(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      string
+		needsPage bool
+	}{
+		{"https://www.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw", "https://www.youtube.com/feeds/videos.xml?channel_id=UCuAXFkgsw1L7xaCfnd5JJOw", false},
+		{"https://m.youtube.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw/videos", "https://www.youtube.com/feeds/videos.xml?channel_id=UCuAXFkgsw1L7xaCfnd5JJOw", false},
+		{"https://www.youtube.com/playlist?list=PL590L5WQmH8fJ54F369BLDSqIwcs-TCfs", "https://www.youtube.com/feeds/videos.xml?playlist_id=PL590L5WQmH8fJ54F369BLDSqIwcs-TCfs", false},
+		{"https://youtube.com/user/someone", "https://www.youtube.com/feeds/videos.xml?user=someone", false},
+		{"https://www.youtube.com/@handle", "", true},
+		{"https://www.youtube.com/c/CustomName", "", true},
+		{"https://www.youtube.com/feeds/videos.xml?channel_id=UCuAXFkgsw1L7xaCfnd5JJOw", "", false},
+		{"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "", false},
+		{"https://example.com/channel/UCuAXFkgsw1L7xaCfnd5JJOw", "", false},
+	}
+	for _, tt := range tests {
+		got, needsPage := youtubeFeedURL(tt.in)
+		if got != tt.want || needsPage != tt.needsPage {
+			t.Errorf("youtubeFeedURL(%q) = %q, %v; want %q, %v", tt.in, got, needsPage, tt.want, tt.needsPage)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package feed
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// youtubeTimedTextURL is the endpoint listing and serving the caption tracks of YouTube videos
+var youtubeTimedTextURL = "https://www.youtube.com/api/timedtext"
+
+const (
+	// transcriptParagraphSeconds groups captions into paragraphs of about this length
+	transcriptParagraphSeconds = 60
+	// maxTranscriptSize limits the size of a caption track
+	maxTranscriptSize = 5 * 1024 * 1024
+	// transcriptFetchTimeout bounds fetching the captions of one video
+	transcriptFetchTimeout = 30 * time.Second
+)
+
+// timedTextTrack is a caption track of a YouTube video
+type timedTextTrack struct {
+	LangCode    string `xml:"lang_code,attr"`
+	Name        string `xml:"name,attr"`
+	Kind        string `xml:"kind,attr"`
+	LangDefault bool   `xml:"lang_default,attr"`
+}
+
+// timedTextTranscript is a caption track in the timedtext XML format
+type timedTextTranscript struct {
+	Texts []struct {
+		Start float64 `xml:"start,attr"`
+		Text  string  `xml:",chardata"`
+	} `xml:"text"`
+}
+
+// fetchVideoTranscripts fetches the captions of new YouTube videos and adds them
+// to their cached content, so they can be searched and summarized.
+// Videos without captions are recorded and not requested again.
+func (f *Fetcher) fetchVideoTranscripts(feed models.Feed, pending []pendingTranscript) {
+	if len(pending) == 0 {
+		return
+	}
+	if enabled, _ := f.db.GetSetting("video_transcripts_enabled"); enabled == "false" {
+		return
+	}
+
+	client, err := f.getHTTPClient(feed)
+	if err != nil {
+		log.Printf("Error creating HTTP client for video transcripts: %v", err)
+		return
+	}
+	targetLanguage, _ := f.db.GetSetting("target_language")
+
+	for _, p := range pending {
+		ctx, cancel := context.WithTimeout(context.Background(), transcriptFetchTimeout)
+		lang, transcript, err := fetchYouTubeTranscript(ctx, client, p.videoID, []string{p.lang, targetLanguage})
+		cancel()
+		if err != nil {
+			// Retried on the next refresh
+			utils.DebugLog("Error fetching transcript of video %s: %v", p.videoID, err)
+			continue
+		}
+
+		if err := f.db.SaveVideoTranscript(p.articleID, lang, transcript); err != nil {
+			log.Printf("Error saving transcript for article %d: %v", p.articleID, err)
+			continue
+		}
+		f.addTranscriptToContent(p.articleID, p.awc, transcript)
+	}
+}
+
+// addTranscriptToContent appends a video transcript to the content of an article
+// and updates its cached content
+func (f *Fetcher) addTranscriptToContent(articleID int64, awc *ArticleWithContent, transcript string) {
+	if transcript == "" {
+		return
+	}
+
+	awc.Content += transcriptHTML(transcript)
+	if err := f.db.SetArticleContent(articleID, awc.Content); err != nil {
+		log.Printf("Error caching content for article %d: %v", articleID, err)
+	}
+}
+
+// transcriptHTML renders a transcript, with paragraphs separated by blank lines, as HTML
+func transcriptHTML(transcript string) string {
+	var b strings.Builder
+	b.WriteString(`<section class="video-transcript"><h3>Transcript</h3>`)
+	for _, paragraph := range strings.Split(transcript, "\n\n") {
+		b.WriteString("<p>")
+		b.WriteString(html.EscapeString(paragraph))
+		b.WriteString("</p>")
+	}
+	b.WriteString("</section>")
+	return b.String()
+}
+
+// fetchYouTubeTranscript fetches the captions of a YouTube video as plain text.
+// The first track matching one of the preferred languages is used, falling back
+// to the default track. Returns an empty transcript if the video has no captions.
+func fetchYouTubeTranscript(ctx context.Context, client *http.Client, videoID string, preferredLangs []string) (string, string, error) {
+	var list struct {
+		Tracks []timedTextTrack `xml:"track"`
+	}
+	if err := getTimedText(ctx, client, url.Values{"v": {videoID}, "type": {"list"}}, &list); err != nil {
+		return "", "", fmt.Errorf("failed to list caption tracks: %w", err)
+	}
+
+	track, ok := selectCaptionTrack(list.Tracks, preferredLangs)
+	if !ok {
+		return "", "", nil
+	}
+
+	params := url.Values{"v": {videoID}, "lang": {track.LangCode}}
+	if track.Name != "" {
+		params.Set("name", track.Name)
+	}
+	if track.Kind != "" {
+		params.Set("kind", track.Kind)
+	}
+	var transcript timedTextTranscript
+	if err := getTimedText(ctx, client, params, &transcript); err != nil {
+		return "", "", fmt.Errorf("failed to fetch captions: %w", err)
+	}
+
+	return track.LangCode, transcriptText(transcript), nil
+}
+
+// getTimedText requests the timedtext endpoint and decodes its XML response.
+// An empty response, returned for videos without captions, leaves v unchanged.
+func getTimedText(ctx context.Context, client *http.Client, params url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, youtubeTimedTextURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTranscriptSize))
+	if err != nil {
+		return err
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return nil
+	}
+	return xml.Unmarshal(body, v)
+}
+
+// selectCaptionTrack picks the caption track to fetch: the first one matching a
+// preferred language (by primary language subtag), then the default track, then
+// the first track. Manual captions are preferred over automatic ones.
+func selectCaptionTrack(tracks []timedTextTrack, preferredLangs []string) (timedTextTrack, bool) {
+	if len(tracks) == 0 {
+		return timedTextTrack{}, false
+	}
+
+	primary := func(lang string) string {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if i := strings.IndexAny(lang, "-_"); i >= 0 {
+			lang = lang[:i]
+		}
+		return lang
+	}
+
+	for _, automatic := range []bool{false, true} {
+		for _, lang := range preferredLangs {
+			if primary(lang) == "" {
+				continue
+			}
+			for _, track := range tracks {
+				if (track.Kind == "asr") == automatic && primary(track.LangCode) == primary(lang) {
+					return track, true
+				}
+			}
+		}
+	}
+	for _, track := range tracks {
+		if track.LangDefault {
+			return track, true
+		}
+	}
+	return tracks[0], true
+}
+
+// transcriptText joins the captions of a track into paragraphs of plain text
+func transcriptText(transcript timedTextTranscript) string {
+	var paragraphs []string
+	var current []string
+	paragraphStart := 0.0
+	for _, text := range transcript.Texts {
+		if len(current) > 0 && text.Start-paragraphStart >= transcriptParagraphSeconds {
+			paragraphs = append(paragraphs, strings.Join(current, " "))
+			current = nil
+		}
+		if len(current) == 0 {
+			paragraphStart = text.Start
+		}
+		// Caption text is HTML-escaped inside the XML
+		line := strings.Join(strings.Fields(html.UnescapeString(text.Text)), " ")
+		if line != "" {
+			current = append(current, line)
+		}
+	}
+	if len(current) > 0 {
+		paragraphs = append(paragraphs, strings.Join(current, " "))
+	}
+	return strings.Join(paragraphs, "\n\n")
+}
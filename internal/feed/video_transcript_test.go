@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSelectCaptionTrack(t *testing.T) {
+	tracks := []timedTextTrack{
+		{LangCode: "de"},
+		{LangCode: "en", Kind: "asr"},
+		{LangCode: "fr", LangDefault: true},
+		{LangCode: "en-GB"},
+	}
+
+	tests := []struct {
+		preferred []string
+		want      string
+	}{
+		{[]string{"en"}, "en-GB"},       // manual captions before automatic ones
+		{[]string{"de", "en"}, "de"},    // first preferred language wins
+		{[]string{"ja", ""}, "fr"},      // default track
+		{[]string{"zh-CN", "ja"}, "fr"}, // default track
+	}
+	for _, tt := range tests {
+		track, ok := selectCaptionTrack(tracks, tt.preferred)
+		if !ok || track.LangCode != tt.want {
+			t.Errorf("selectCaptionTrack(%v) = %q, want %q", tt.preferred, track.LangCode, tt.want)
+		}
+	}
+
+	if _, ok := selectCaptionTrack(nil, []string{"en"}); ok {
+		t.Error("expected no track for a video without captions")
+	}
+}
+
+func TestFetchYouTubeTranscript(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("v") == "nocaptions":
+			// YouTube answers with an empty body for videos without captions
+		case q.Get("type") == "list":
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8" ?><transcript_list docid="1">` +
+				`<track id="0" name="" lang_code="en" lang_original="English" lang_translated="English" lang_default="true"/>` +
+				`</transcript_list>`))
+		case q.Get("lang") == "en":
+			w.Write([]byte(`<?xml version="1.0" encoding="utf-8" ?><transcript>` +
+				`<text start="0.5" dur="2">Hello &amp;amp; welcome</text>` +
+				`<text start="3" dur="2">it&amp;#39;s   a test</text>` +
+				`<text start="75" dur="2">Second paragraph</text>` +
+				`</transcript>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	original := youtubeTimedTextURL
+	youtubeTimedTextURL = server.URL
+	defer func() { youtubeTimedTextURL = original }()
+
+	lang, transcript, err := fetchYouTubeTranscript(context.Background(), server.Client(), "video", []string{"de"})
+	if err != nil {
+		t.Fatalf("fetchYouTubeTranscript failed: %v", err)
+	}
+	if lang != "en" {
+		t.Errorf("lang = %q, want en", lang)
+	}
+	if want := "Hello & welcome it's a test\n\nSecond paragraph"; transcript != want {
+		t.Errorf("transcript = %q, want %q", transcript, want)
+	}
+	if html := transcriptHTML(transcript); !strings.Contains(html, "<p>Hello &amp; welcome it&#39;s a test</p><p>Second paragraph</p>") {
+		t.Errorf("unexpected transcript HTML: %s", html)
+	}
+
+	lang, transcript, err = fetchYouTubeTranscript(context.Background(), server.Client(), "nocaptions", nil)
+	if err != nil || lang != "" || transcript != "" {
+		t.Errorf("expected empty transcript without captions, got %q, %q, %v", lang, transcript, err)
+	}
+}
@@ -0,0 +1,42 @@
+package feed
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+	"MrRSS/internal/websub"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// recordWebSubHub records the WebSub hub advertised by a fetched feed document,
+// so that the server subscribes to its push updates
+func (f *Fetcher) recordWebSubHub(feed *models.Feed, data string) {
+	if !utils.IsServerMode() || feed.ID == 0 || feed.IsFreshRSSSource {
+		return
+	}
+	hub, self := websub.FindHub([]byte(data))
+	if hub == "" {
+		return
+	}
+	if self == "" {
+		self = feed.URL
+	}
+	if err := f.db.SaveWebSubHub(feed.ID, hub, self); err != nil {
+		log.Printf("Error saving websub hub of feed %s: %v", feed.URL, err)
+	}
+}
+
+// IngestPushedContent saves the articles of feed content pushed by a WebSub hub,
+// like the articles of a refresh
+func (f *Fetcher) IngestPushedContent(ctx context.Context, feed models.Feed, body []byte) error {
+	parsedFeed, err := gofeed.NewParser().ParseString(sanitizeFeedXML(string(body)))
+	if err != nil {
+		return fmt.Errorf("failed to parse pushed content: %w", err)
+	}
+	utils.DebugLog("WebSub push for feed %s: %d items", feed.Title, len(parsedFeed.Items))
+	return f.saveParsedFeed(ctx, feed, parsedFeed)
+}
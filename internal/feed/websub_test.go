@@ -0,0 +1,81 @@
+package feed
+
+import (
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/translation"
+	"MrRSS/internal/utils"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const websubAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Pushed Blog</title>
+  <link rel="hub" href="https://hub.example/"/>
+  <link rel="self" href="https://blog.example/feed.atom"/>
+  <entry>
+    <title>Pushed post</title>
+    <link href="https://blog.example/pushed"/>
+    <id>tag:blog.example,2026:1</id>
+    <updated>2026-01-02T03:04:05Z</updated>
+  </entry>
+</feed>`
+
+func TestWebSubHubAndPush(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to init db: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write([]byte(websubAtom))
+	}))
+	defer srv.Close()
+
+	fetcher := NewFetcher(db, translation.NewMockTranslator())
+	// Hubs are only recorded in server mode, enabled after the fetcher
+	// so that its task log is not written to the working directory
+	utils.SetServerMode(true)
+	defer utils.SetServerMode(false)
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Pushed Blog", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	feed, _ := db.GetFeedByID(feedID)
+
+	// Fetching the feed records its hub
+	if _, err := fetcher.ParseFeedWithFeed(context.Background(), feed, false); err != nil {
+		t.Fatalf("ParseFeedWithFeed failed: %v", err)
+	}
+	sub, err := db.GetWebSubSubscription(feedID)
+	if err != nil || sub == nil {
+		t.Fatalf("Hub not recorded: %v, %v", sub, err)
+	}
+	if sub.Hub != "https://hub.example/" || sub.Topic != "https://blog.example/feed.atom" || sub.State != "discovered" {
+		t.Errorf("Unexpected subscription: %+v", sub)
+	}
+
+	// Pushed content is saved like fetched content
+	if err := fetcher.IngestPushedContent(context.Background(), *feed, []byte(websubAtom)); err != nil {
+		t.Fatalf("IngestPushedContent failed: %v", err)
+	}
+	articles, err := db.GetArticles("", feedID, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticles failed: %v", err)
+	}
+	if len(articles) != 1 || articles[0].Title != "Pushed post" {
+		t.Errorf("Unexpected articles after push: %+v", articles)
+	}
+
+	if err := fetcher.IngestPushedContent(context.Background(), *feed, []byte("not a feed")); err == nil {
+		t.Error("Expected an error for content that is not a feed")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"MrRSS/internal/database"
+	"MrRSS/internal/metrics"
 	"MrRSS/internal/models"
 )
 
@@ -44,7 +45,10 @@ func (s *BidirectionalSyncService) Sync(ctx context.Context) (*SyncResult, error
 		LastSyncTime: time.Now(),
 	}
 	startTime := time.Now()
-	defer func() { result.Duration = time.Since(startTime) }()
+	defer func() {
+		result.Duration = time.Since(startTime)
+		metrics.ObserveFreshRSSSync(result.PullSuccess, result.PushSuccess, result.Duration)
+	}()
 
 	// Stage 1: Login to FreshRSS
 	if err := s.client.Login(ctx); err != nil {
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
 )
 
 // HandleArticles returns articles with filtering and pagination.
@@ -38,11 +39,37 @@ func HandleArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	showHiddenStr, _ := h.DB.GetSetting("show_hidden_articles")
 	showHidden := showHiddenStr == "true"
 
-	articles, err := h.DB.GetArticles(filter, feedID, category, showHidden, limit, offset)
+	// Near-duplicate articles are collapsed into their story in the combined views,
+	// where the same story syndicated across feeds would otherwise be listed repeatedly
+	dedupStr, _ := h.DB.GetSetting("embedding_dedup_enabled")
+	collapseStories := dedupStr == "true" && feedID <= 0 && category == ""
+
+	var articles []models.Article
+	var err error
+	if collapseStories {
+		articles, err = h.DB.GetArticlesCollapsingStories(filter, feedID, category, showHidden, limit, offset)
+	} else {
+		articles, err = h.DB.GetArticles(filter, feedID, category, showHidden, limit, offset)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+
+	if collapseStories && len(articles) > 0 {
+		ids := make([]int64, len(articles))
+		for i, article := range articles {
+			ids[i] = article.ID
+		}
+		counts, err := h.DB.GetStoryAlternateCounts(ids)
+		if err != nil {
+			log.Printf("Error getting story alternate counts: %v", err)
+		}
+		for i := range articles {
+			articles[i].AlternateCount = counts[articles[i].ID]
+		}
+	}
+
 	json.NewEncoder(w).Encode(articles)
 }
 
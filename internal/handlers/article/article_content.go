@@ -87,8 +87,11 @@ func HandleFetchFullArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Fetch full content
-	fullContent, err := h.FetchFullArticleContent(article.URL)
+	// Fetch full content, unless it was downloaded for offline reading
+	fullContent, found, err := h.DB.GetOfflineContent(articleID)
+	if err != nil || !found {
+		fullContent, err = h.FetchFullArticleContent(article.URL)
+	}
 	if err != nil {
 		log.Printf("Error fetching full article content: %v", err)
 		http.Error(w, "Failed to fetch full article content", http.StatusInternalServerError)
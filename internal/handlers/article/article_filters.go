@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
 )
 
 // FilterCondition represents a single filter condition from the frontend
@@ -14,7 +15,7 @@ type FilterCondition struct {
 	ID       int64    `json:"id"`
 	Logic    string   `json:"logic"`    // "and", "or" (null for first condition)
 	Negate   bool     `json:"negate"`   // NOT modifier for this condition
-	Field    string   `json:"field"`    // "feed_name", "feed_category", "article_title", "published_after", "published_before"
+	Field    string   `json:"field"`    // "feed_name", "feed_category", "article_title", "article_language", "published_after", "published_before"
 	Operator string   `json:"operator"` // "contains", "exact" (null for date fields and multi-select)
 	Value    string   `json:"value"`    // Single value for text/date fields
 	Values   []string `json:"values"`   // Multiple values for feed_name and feed_category
@@ -128,6 +129,13 @@ func evaluateSingleCondition(article models.Article, condition FilterCondition,
 			result = feedIsImageMode[article.FeedID] == wantImageMode
 		}
 
+	case "article_language":
+		matched, decided := rules.MatchLanguage(article.Lang, condition.Values, condition.Value)
+		if !decided {
+			return false
+		}
+		result = matched
+
 	case "published_after":
 		if condition.Value == "" {
 			result = true
@@ -24,17 +24,30 @@ type TaskDetailsResponse struct {
 
 // PoolTaskInfo contains information about a task in the pool
 type PoolTaskInfo struct {
-	FeedID    int64  `json:"feed_id"`
-	FeedTitle string `json:"feed_title"`
-	Reason    int    `json:"reason"`
-	CreatedAt string `json:"created_at"`
+	FeedID         int64   `json:"feed_id"`
+	FeedTitle      string  `json:"feed_title"`
+	Reason         int     `json:"reason"`
+	CreatedAt      string  `json:"created_at"`
+	Host           string  `json:"host,omitempty"`
+	ThrottledUntil *string `json:"throttled_until,omitempty"`
 }
 
 // QueueTaskInfo contains information about a task in the queue
 type QueueTaskInfo struct {
-	FeedID    int64  `json:"feed_id"`
-	FeedTitle string `json:"feed_title"`
-	Position  int    `json:"position"`
+	FeedID         int64   `json:"feed_id"`
+	FeedTitle      string  `json:"feed_title"`
+	Position       int     `json:"position"`
+	Host           string  `json:"host,omitempty"`
+	ThrottledUntil *string `json:"throttled_until,omitempty"`
+}
+
+// formatThrottledUntil formats until when the host of a task is throttled, if it is
+func formatThrottledUntil(until *time.Time) *string {
+	if until == nil {
+		return nil
+	}
+	formatted := until.Format(time.RFC3339)
+	return &formatted
 }
 
 // HandleTaskDetails returns detailed information about tasks in pool and queue
@@ -46,10 +59,12 @@ func HandleTaskDetails(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	poolTasks := make([]PoolTaskInfo, len(poolTasksRaw))
 	for i, task := range poolTasksRaw {
 		poolTasks[i] = PoolTaskInfo{
-			FeedID:    task.FeedID,
-			FeedTitle: task.FeedTitle,
-			Reason:    int(task.Reason),
-			CreatedAt: task.CreatedAt.Format(time.RFC3339),
+			FeedID:         task.FeedID,
+			FeedTitle:      task.FeedTitle,
+			Reason:         int(task.Reason),
+			CreatedAt:      task.CreatedAt.Format(time.RFC3339),
+			Host:           task.Host,
+			ThrottledUntil: formatThrottledUntil(task.ThrottledUntil),
 		}
 	}
 
@@ -63,9 +78,11 @@ func HandleTaskDetails(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	queueTasks := make([]QueueTaskInfo, len(queueTasksRaw))
 	for i, task := range queueTasksRaw {
 		queueTasks[i] = QueueTaskInfo{
-			FeedID:    task.FeedID,
-			FeedTitle: task.FeedTitle,
-			Position:  task.Position,
+			FeedID:         task.FeedID,
+			FeedTitle:      task.FeedTitle,
+			Position:       task.Position,
+			Host:           task.Host,
+			ThrottledUntil: formatThrottledUntil(task.ThrottledUntil),
 		}
 	}
 
@@ -198,3 +198,54 @@ func TestHandleExportToObsidian(t *testing.T) {
 		t.Fatalf("Export not successful: %v", response)
 	}
 }
+
+func TestHandleRelatedArticles_NoProvider(t *testing.T) {
+	h := setupHandler(t)
+
+	feedID, err := h.DB.AddFeed(&models.Feed{Title: "F", URL: "http://x"})
+	if err != nil {
+		t.Fatalf("AddFeed: %v", err)
+	}
+	if err := h.DB.SaveArticles(context.Background(), []*models.Article{
+		{FeedID: feedID, Title: "a1", URL: "u1", PublishedAt: time.Now()},
+	}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	all, err := h.DB.GetArticles("", 0, "", false, 10, 0)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("GetArticles: %v", err)
+	}
+
+	for _, tc := range []struct {
+		url     string
+		handler func(*core.Handler, http.ResponseWriter, *http.Request)
+	}{
+		{fmt.Sprintf("/api/articles/related?id=%d", all[0].ID), article.HandleRelatedArticles},
+		{"/api/articles/semantic-search?q=news", article.HandleSemanticSearch},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+		w := httptest.NewRecorder()
+		tc.handler(h, w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tc.url, w.Code)
+		}
+
+		var resp struct {
+			Available bool             `json:"available"`
+			Articles  []models.Article `json:"articles"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if resp.Available || resp.Articles == nil || len(resp.Articles) != 0 {
+			t.Fatalf("%s: expected unavailable with empty list, got %+v", tc.url, resp)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/alternates?id=%d", all[0].ID), nil)
+	w := httptest.NewRecorder()
+	article.HandleStoryAlternates(h, w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("alternates: expected 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,169 @@
+package article
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"MrRSS/internal/embedding"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+)
+
+// maxRelatedLimit caps the number of articles returned by the similarity endpoints
+const maxRelatedLimit = 50
+
+// scoredArticle is an article with its similarity to the related article or search query.
+type scoredArticle struct {
+	models.Article
+	Similarity float64 `json:"similarity"`
+}
+
+// HandleRelatedArticles returns the articles most similar to an article.
+// When no embedding provider is configured, it returns an empty list with available set to false.
+func HandleRelatedArticles(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+	limit := parseSimilarityLimit(r, 5)
+
+	matches, err := embeddingService(h).Related(articleID, limit)
+	writeScoredArticles(h, w, matches, err)
+}
+
+// HandleSemanticSearch returns the articles whose meaning best matches a free-text query.
+// When no embedding provider is configured, it returns an empty list with available set to false.
+func HandleSemanticSearch(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		http.Error(w, "Missing query", http.StatusBadRequest)
+		return
+	}
+	limit := parseSimilarityLimit(r, 20)
+
+	matches, err := embeddingService(h).Search(query, limit)
+	writeScoredArticles(h, w, matches, err)
+}
+
+// HandleStoryAlternates returns the near-duplicate articles clustered into the same story as an article.
+// Stories clustered so far remain available even if the embedding provider is no longer configured.
+func HandleStoryAlternates(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	ids, err := embeddingService(h).StoryAlternates(articleID)
+	if err != nil {
+		log.Printf("Error getting story alternates: %v", err)
+		http.Error(w, "Failed to get story alternates", http.StatusInternalServerError)
+		return
+	}
+
+	articles, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		log.Printf("Error getting story alternates: %v", err)
+		http.Error(w, "Failed to get story alternates", http.StatusInternalServerError)
+		return
+	}
+	byID := make(map[int64]models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	// Keep the story order (oldest first)
+	alternates := make([]models.Article, 0, len(ids))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			alternates = append(alternates, article)
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"articles": alternates,
+	})
+}
+
+// embeddingService returns the handler's embedding service, creating one if the
+// handler was constructed without it.
+func embeddingService(h *core.Handler) *embedding.Service {
+	if h.Embeddings != nil {
+		return h.Embeddings
+	}
+	return embedding.NewService(h.DB, h.AITracker)
+}
+
+// parseSimilarityLimit parses the limit query parameter, capped at maxRelatedLimit.
+func parseSimilarityLimit(r *http.Request, defaultLimit int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return defaultLimit
+	}
+	if limit > maxRelatedLimit {
+		return maxRelatedLimit
+	}
+	return limit
+}
+
+// writeScoredArticles writes the articles of similarity matches in match order.
+func writeScoredArticles(h *core.Handler, w http.ResponseWriter, matches []embedding.Match, err error) {
+	if errors.Is(err, embedding.ErrNotConfigured) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"available": false,
+			"articles":  []scoredArticle{},
+		})
+		return
+	}
+	if err != nil {
+		log.Printf("Error computing article similarity: %v", err)
+		http.Error(w, "Failed to compute article similarity", http.StatusInternalServerError)
+		return
+	}
+
+	ids := make([]int64, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ArticleID
+	}
+	articles, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		log.Printf("Error getting articles: %v", err)
+		http.Error(w, "Failed to get articles", http.StatusInternalServerError)
+		return
+	}
+	byID := make(map[int64]models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+
+	scored := make([]scoredArticle, 0, len(matches))
+	for _, m := range matches {
+		if article, ok := byID[m.ArticleID]; ok {
+			scored = append(scored, scoredArticle{Article: article, Similarity: m.Similarity})
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"available": true,
+		"articles":  scored,
+	})
+}
@@ -0,0 +1,36 @@
+package article
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"MrRSS/internal/handlers/core"
+)
+
+// HandleGetVideoInfo returns the video metadata of an article: duration, views,
+// rating, thumbnail variants and the language of its transcript.
+// Returns null if the article is not a video.
+func HandleGetVideoInfo(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	info, err := h.DB.GetVideoInfo(articleID)
+	if err != nil {
+		log.Printf("Error getting video info: %v", err)
+		http.Error(w, "Failed to get video info", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"MrRSS/internal/backup"
+	"MrRSS/internal/crypto"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/utils"
+)
+
+// maxBackupUploadSize limits the size of an uploaded backup archive
+const maxBackupUploadSize = 4 << 30
+
+// HandleBackupExport creates a backup archive of the database, settings, custom CSS
+// and scripts. Secrets are encrypted with the passphrase of the request.
+func HandleBackupExport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Passphrase string `json:"passphrase"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Passphrase == "" {
+		http.Error(w, "Passphrase is required", http.StatusBadRequest)
+		return
+	}
+
+	dataDir, err := utils.GetDataDir()
+	if err != nil {
+		log.Printf("Error getting data directory: %v", err)
+		http.Error(w, "Failed to get data directory", http.StatusInternalServerError)
+		return
+	}
+
+	// Build the archive in a temp file so that errors can still be reported
+	archive, err := os.CreateTemp("", "mrrss-backup-*.zip")
+	if err != nil {
+		log.Printf("Error creating backup file: %v", err)
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	if err := backup.Export(h.DB, dataDir, req.Passphrase, archive); err != nil {
+		log.Printf("Error exporting backup: %v", err)
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	size, err := archive.Seek(0, io.SeekCurrent)
+	if err != nil {
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		http.Error(w, "Failed to create backup", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("mrrss-backup-%s.zip", time.Now().Format("20060102-150405"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", size))
+	io.Copy(w, archive)
+}
+
+// HandleBackupImport restores a backup archive uploaded as the "file" field of a
+// multipart form, with the passphrase in the "passphrase" field.
+// The restored data replaces the current data, and secrets are re-encrypted for this machine.
+func HandleBackupImport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBackupUploadSize)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Missing backup file", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	passphrase := r.FormValue("passphrase")
+	if passphrase == "" {
+		http.Error(w, "Passphrase is required", http.StatusBadRequest)
+		return
+	}
+
+	// The archive reader needs random access
+	archive, err := os.CreateTemp("", "mrrss-restore-*.zip")
+	if err != nil {
+		log.Printf("Error creating restore file: %v", err)
+		http.Error(w, "Failed to restore backup", http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+
+	size, err := io.Copy(archive, file)
+	if err != nil {
+		http.Error(w, "Failed to read backup file", http.StatusBadRequest)
+		return
+	}
+
+	dataDir, err := utils.GetDataDir()
+	if err != nil {
+		log.Printf("Error getting data directory: %v", err)
+		http.Error(w, "Failed to get data directory", http.StatusInternalServerError)
+		return
+	}
+
+	manifest, err := backup.Import(h.DB, dataDir, passphrase, archive, size)
+	if err != nil {
+		switch {
+		case errors.Is(err, backup.ErrWrongPassphrase), errors.Is(err, crypto.ErrDecryptionFailed):
+			http.Error(w, backup.ErrWrongPassphrase.Error(), http.StatusBadRequest)
+		case errors.Is(err, backup.ErrInvalidArchive), errors.Is(err, backup.ErrUnsupportedVersion):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Printf("Error importing backup: %v", err)
+			http.Error(w, "Failed to restore backup", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	// Cached content may belong to articles that were replaced
+	h.ContentCache.Clear()
+
+	log.Printf("Restored backup created at %s by MrRSS %s", manifest.CreatedAt.Format(time.RFC3339), manifest.AppVersion)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":      "success",
+		"created_at":  manifest.CreatedAt,
+		"app_version": manifest.AppVersion,
+	})
+}
@@ -0,0 +1,42 @@
+package backup
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleBackupExport_MethodNotAllowed(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/backup/export", nil)
+	rr := httptest.NewRecorder()
+
+	HandleBackupExport(nil, rr, req)
+
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected %d got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+}
+
+func TestHandleBackupExport_MissingPassphrase(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/backup/export", bytes.NewReader([]byte(`{"passphrase": ""}`)))
+	rr := httptest.NewRecorder()
+
+	// The passphrase is validated before any DB access
+	HandleBackupExport(nil, rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestHandleBackupImport_MissingFile(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/backup/import", bytes.NewReader(nil))
+	rr := httptest.NewRecorder()
+
+	HandleBackupImport(nil, rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d", http.StatusBadRequest, rr.Code)
+	}
+}
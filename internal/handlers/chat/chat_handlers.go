@@ -11,7 +11,9 @@ import (
 	"strings"
 	"time"
 
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/metrics"
 	"MrRSS/internal/utils"
 )
 
@@ -53,12 +55,18 @@ type OpenAIResponse struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error,omitempty"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
 }
 
 // OllamaResponse represents the response from Ollama API
 type OllamaResponse struct {
-	Response string `json:"response"`
-	Done     bool   `json:"done"`
+	Response        string `json:"response"`
+	Done            bool   `json:"done"`
+	PromptEvalCount int64  `json:"prompt_eval_count"`
+	EvalCount       int64  `json:"eval_count"`
 }
 
 // HandleAIChat handles chat requests for article discussions
@@ -89,6 +97,8 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	// Check if AI usage limit is reached
 	if h.AITracker.IsLimitReached() {
 		log.Printf("AI usage limit reached for chat")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTooManyRequests)
 		json.NewEncoder(w).Encode(map[string]string{
 			"error": "AI usage limit reached",
 		})
@@ -114,16 +124,13 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	optimizedMessages := optimizeChatContext(req.Messages, req.ArticleTitle, req.ArticleURL, req.ArticleContent, req.IsFirstMessage)
 
 	// Try OpenAI format first
-	response, err := tryOpenAIFormat(endpoint, apiKey, model, optimizedMessages, h)
-	if err == nil {
+	response, usage, openAIErr := tryOpenAIFormat(endpoint, apiKey, model, optimizedMessages, h)
+	if openAIErr == nil {
 		// Convert markdown response to HTML
 		htmlResponse := utils.ConvertMarkdownToHTML(response)
 
-		// Track AI usage (estimate tokens from input and output)
-		estimatedTokens := estimateChatTokens(optimizedMessages, response)
-		if err := h.AITracker.AddUsage(estimatedTokens); err != nil {
-			log.Printf("Warning: failed to track AI usage: %v", err)
-		}
+		// Track AI usage (reported by the API, or estimated from input and output)
+		h.AITracker.TrackUsage(aiusage.FeatureChat, usage, chatPromptText(optimizedMessages), response)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(ChatResponse{Response: response, HTML: htmlResponse})
@@ -131,17 +138,14 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// If OpenAI format fails, try Ollama format
-	log.Printf("OpenAI format failed, trying Ollama format: %v", err)
-	response, err = tryOllamaFormat(endpoint, apiKey, model, optimizedMessages, h)
-	if err == nil {
+	log.Printf("OpenAI format failed, trying Ollama format: %v", openAIErr)
+	response, usage, ollamaErr := tryOllamaFormat(endpoint, apiKey, model, optimizedMessages, h)
+	if ollamaErr == nil {
 		// Convert markdown response to HTML
 		htmlResponse := utils.ConvertMarkdownToHTML(response)
 
-		// Track AI usage (estimate tokens from input and output)
-		estimatedTokens := estimateChatTokens(optimizedMessages, response)
-		if err := h.AITracker.AddUsage(estimatedTokens); err != nil {
-			log.Printf("Warning: failed to track AI usage: %v", err)
-		}
+		// Track AI usage (reported by the API, or estimated from input and output)
+		h.AITracker.TrackUsage(aiusage.FeatureChat, usage, chatPromptText(optimizedMessages), response)
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(ChatResponse{Response: response, HTML: htmlResponse})
@@ -149,9 +153,9 @@ func HandleAIChat(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Both formats failed
-	log.Printf("All chat formats failed: OpenAI error: %v, Ollama error: %v", err, err)
+	log.Printf("All chat formats failed: OpenAI error: %v, Ollama error: %v", openAIErr, ollamaErr)
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
+	w.WriteHeader(http.StatusBadGateway)
 	json.NewEncoder(w).Encode(map[string]string{"error": "No response from AI"})
 }
 
@@ -241,7 +245,7 @@ func isLocalEndpoint(host string) bool {
 }
 
 // tryOpenAIFormat attempts to use OpenAI-compatible API format for chat
-func tryOpenAIFormat(endpoint, apiKey, model string, messages []ChatMessage, h *core.Handler) (string, error) {
+func tryOpenAIFormat(endpoint, apiKey, model string, messages []ChatMessage, h *core.Handler) (string, aiusage.Usage, error) {
 	openAIReq := OpenAIRequest{
 		Model:       model,
 		Messages:    messages,
@@ -251,12 +255,12 @@ func tryOpenAIFormat(endpoint, apiKey, model string, messages []ChatMessage, h *
 
 	jsonBody, err := json.Marshal(openAIReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal OpenAI request: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to marshal OpenAI request: %w", err)
 	}
 
 	resp, err := sendChatRequest(endpoint, apiKey, jsonBody, h)
 	if err != nil {
-		return "", fmt.Errorf("OpenAI request failed: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("OpenAI request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -266,28 +270,29 @@ func tryOpenAIFormat(endpoint, apiKey, model string, messages []ChatMessage, h *
 		if len(bodyBytes) > 0 {
 			errorMsg = fmt.Sprintf("%s - %s", errorMsg, string(bodyBytes))
 		}
-		return "", fmt.Errorf("%s", errorMsg)
+		return "", aiusage.Usage{}, fmt.Errorf("%s", errorMsg)
 	}
 
 	var openAIResp OpenAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&openAIResp); err != nil {
-		return "", fmt.Errorf("failed to decode OpenAI response: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to decode OpenAI response: %w", err)
 	}
 
 	// Check for API error
 	if openAIResp.Error != nil {
-		return "", fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
+		return "", aiusage.Usage{}, fmt.Errorf("OpenAI API error: %s", openAIResp.Error.Message)
 	}
 
 	if len(openAIResp.Choices) == 0 || openAIResp.Choices[0].Message.Content == "" {
-		return "", fmt.Errorf("no response found in OpenAI response")
+		return "", aiusage.Usage{}, fmt.Errorf("no response found in OpenAI response")
 	}
 
-	return strings.TrimSpace(openAIResp.Choices[0].Message.Content), nil
+	usage := aiusage.Usage{PromptTokens: openAIResp.Usage.PromptTokens, CompletionTokens: openAIResp.Usage.CompletionTokens}
+	return strings.TrimSpace(openAIResp.Choices[0].Message.Content), usage, nil
 }
 
 // tryOllamaFormat attempts to use Ollama API format for chat
-func tryOllamaFormat(endpoint, apiKey, model string, messages []ChatMessage, h *core.Handler) (string, error) {
+func tryOllamaFormat(endpoint, apiKey, model string, messages []ChatMessage, h *core.Handler) (string, aiusage.Usage, error) {
 	// Convert messages to Ollama prompt format
 	var promptBuilder strings.Builder
 	for _, msg := range messages {
@@ -316,12 +321,12 @@ func tryOllamaFormat(endpoint, apiKey, model string, messages []ChatMessage, h *
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal Ollama request: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to marshal Ollama request: %w", err)
 	}
 
 	resp, err := sendChatRequest(endpoint, apiKey, jsonBody, h)
 	if err != nil {
-		return "", fmt.Errorf("Ollama request failed: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("Ollama request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -331,19 +336,20 @@ func tryOllamaFormat(endpoint, apiKey, model string, messages []ChatMessage, h *
 		if len(bodyBytes) > 0 {
 			errorMsg = fmt.Sprintf("%s - %s", errorMsg, string(bodyBytes))
 		}
-		return "", fmt.Errorf("%s", errorMsg)
+		return "", aiusage.Usage{}, fmt.Errorf("%s", errorMsg)
 	}
 
 	var ollamaResp OllamaResponse
 	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return "", fmt.Errorf("failed to decode Ollama response: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to decode Ollama response: %w", err)
 	}
 
 	if !ollamaResp.Done || ollamaResp.Response == "" {
-		return "", fmt.Errorf("no response found in Ollama response")
+		return "", aiusage.Usage{}, fmt.Errorf("no response found in Ollama response")
 	}
 
-	return strings.TrimSpace(ollamaResp.Response), nil
+	usage := aiusage.Usage{PromptTokens: ollamaResp.PromptEvalCount, CompletionTokens: ollamaResp.EvalCount}
+	return strings.TrimSpace(ollamaResp.Response), usage, nil
 }
 
 // sendChatRequest sends the HTTP request for chat with proper headers and validation
@@ -376,18 +382,20 @@ func sendChatRequest(endpoint, apiKey string, jsonBody []byte, h *core.Handler)
 		req.Header.Set("Authorization", "Bearer "+apiKey)
 	}
 
-	return client.Do(req)
+	start := time.Now()
+	resp, err := client.Do(req)
+	metrics.ObserveAIRequest(aiusage.FeatureChat, start, resp, err)
+	return resp, err
 }
 
-// estimateChatTokens estimates token usage for chat requests
-func estimateChatTokens(messages []ChatMessage, response string) int64 {
-	var total int64
+// chatPromptText joins the message contents for token estimation
+func chatPromptText(messages []ChatMessage) string {
+	var sb strings.Builder
 	for _, msg := range messages {
-		// Rough estimation: 1 token ≈ 4 characters
-		total += int64(len(msg.Content) / 4)
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
 	}
-	total += int64(len(response) / 4)
-	return total
+	return sb.String()
 }
 
 // optimizeChatContext optimizes the chat context to reduce token usage and manage context length
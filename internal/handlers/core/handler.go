@@ -9,16 +9,20 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"MrRSS/internal/aiusage"
 	"MrRSS/internal/cache"
 	"MrRSS/internal/database"
 	"MrRSS/internal/discovery"
+	"MrRSS/internal/embedding"
 	"MrRSS/internal/feed"
 	"MrRSS/internal/models"
+	"MrRSS/internal/podcast"
 	"MrRSS/internal/translation"
 	"MrRSS/internal/utils"
+	"MrRSS/internal/websub"
 
 	"codeberg.org/readeck/go-readability/v2"
 
@@ -42,13 +46,25 @@ type DiscoveryState struct {
 	IsComplete bool                       `json:"is_complete"`
 }
 
+// RecommendationState represents the current state of building feed recommendations
+type RecommendationState struct {
+	IsRunning       bool                       `json:"is_running"`
+	Progress        discovery.Progress         `json:"progress"`
+	Recommendations []discovery.Recommendation `json:"recommendations,omitempty"`
+	Error           string                     `json:"error,omitempty"`
+	IsComplete      bool                       `json:"is_complete"`
+}
+
 // Handler holds all dependencies for HTTP handlers.
 type Handler struct {
 	DB               *database.DB
 	Fetcher          *feed.Fetcher
 	Translator       translation.Translator
 	AITracker        *aiusage.Tracker
+	Embeddings       *embedding.Service
 	DiscoveryService *discovery.Service
+	Podcasts         *podcast.Downloader // Background downloads of podcast episodes
+	WebSub           *websub.Manager     // Push subscriptions to the WebSub hubs of feeds
 	App              interface{}         // Wails app instance for browser integration (interface{} to avoid import in server mode)
 	ContentCache     *cache.ContentCache // Cache for article content
 
@@ -56,6 +72,13 @@ type Handler struct {
 	DiscoveryMu          sync.RWMutex
 	SingleDiscoveryState *DiscoveryState
 	BatchDiscoveryState  *DiscoveryState
+	RecommendationState  *RecommendationState
+
+	// Set while articles are downloaded for offline reading
+	offlineRunning atomic.Bool
+
+	// IDs of the feeds waiting for their staggered scheduled refresh
+	scheduledFeeds sync.Map
 }
 
 // NewHandler creates a new Handler with the given dependencies.
@@ -69,6 +92,18 @@ func NewHandler(db *database.DB, fetcher *feed.Fetcher, translator translation.T
 		ContentCache:     cache.NewContentCache(100, 30*time.Minute), // Cache up to 100 articles for 30 minutes
 	}
 
+	h.Embeddings = embedding.NewService(db, h.AITracker)
+	h.Podcasts = podcast.NewDownloader(db, h.podcastClient)
+
+	// Share the AI usage tracker so pre-generation during refresh respects the usage limit
+	if fetcher != nil {
+		fetcher.SetAITracker(h.AITracker)
+		fetcher.SetEmbeddingService(h.Embeddings)
+		// Download articles for offline reading after each refresh
+		fetcher.SetRefreshCompleteHandler(h.PrefetchOffline)
+		h.WebSub = websub.NewManager(db, fetcher.IngestPushedContent)
+	}
+
 	return h
 }
 
@@ -106,6 +141,11 @@ func (h *Handler) GetArticleContent(articleID int64) (string, error) {
 		return content, nil
 	}
 
+	// Use the copy downloaded for offline reading before going to the network
+	if content, found, err := h.DB.GetOfflineContent(articleID); err == nil && found {
+		return content, nil
+	}
+
 	// Get the article from database
 	article, err := h.DB.GetArticleByID(articleID)
 	if err != nil {
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/netguard"
+	"MrRSS/internal/utils"
+)
+
+// defaultMaxMediaSizeMB is used when media_proxy_max_size_mb is not set
+const defaultMaxMediaSizeMB = 50
+
+// MediaRequestPolicy builds the guard for URLs fetched on behalf of clients, such
+// as media, webpages and prefetched images, from the settings. Private networks
+// are blocked in server mode unless configured otherwise.
+func (h *Handler) MediaRequestPolicy() *netguard.Policy {
+	privateNetworks, _ := h.DB.GetSetting("media_proxy_private_networks")
+	allowedHosts, _ := h.DB.GetSetting("media_proxy_allowed_hosts")
+	deniedHosts, _ := h.DB.GetSetting("media_proxy_denied_hosts")
+
+	blockPrivate := utils.IsServerMode()
+	switch privateNetworks {
+	case "block":
+		blockPrivate = true
+	case "allow":
+		blockPrivate = false
+	}
+
+	return &netguard.Policy{
+		BlockPrivate: blockPrivate,
+		AllowedHosts: netguard.ParseHostList(allowedHosts),
+		DeniedHosts:  netguard.ParseHostList(deniedHosts),
+	}
+}
+
+// MaxMediaSize returns the largest response accepted for a proxied URL, in bytes
+func (h *Handler) MaxMediaSize() int64 {
+	maxSizeMBStr, _ := h.DB.GetSetting("media_proxy_max_size_mb")
+	maxSizeMB, err := strconv.Atoi(maxSizeMBStr)
+	if err != nil || maxSizeMB <= 0 {
+		maxSizeMB = defaultMaxMediaSizeMB
+	}
+	return int64(maxSizeMB) << 20
+}
+
+// MediaClient returns an HTTP client that enforces the policy.
+// If useProxy is set, requests go through the configured network proxy.
+func (h *Handler) MediaClient(policy *netguard.Policy, useProxy bool) *http.Client {
+	var proxyURL *url.URL
+	if useProxy {
+		proxyURL = h.configuredProxyURL()
+	}
+	return policy.NewClient(30*time.Second, proxyURL)
+}
+
+// podcastClient returns the client for podcast episode downloads. It enforces
+// the media policy and uses the configured network proxy, without the overall
+// timeout of the media client as episodes can take long to download.
+func (h *Handler) podcastClient() *http.Client {
+	return h.MediaRequestPolicy().NewClient(0, h.configuredProxyURL())
+}
+
+// configuredProxyURL returns the network proxy of the settings, or nil if disabled
+func (h *Handler) configuredProxyURL() *url.URL {
+	if proxyEnabled, _ := h.DB.GetSetting("proxy_enabled"); proxyEnabled != "true" {
+		return nil
+	}
+
+	proxyType, _ := h.DB.GetSetting("proxy_type")
+	proxyHost, _ := h.DB.GetSetting("proxy_host")
+	proxyPort, _ := h.DB.GetSetting("proxy_port")
+	proxyUsername, _ := h.DB.GetEncryptedSetting("proxy_username")
+	proxyPassword, _ := h.DB.GetEncryptedSetting("proxy_password")
+
+	proxyURLStr := utils.BuildProxyURL(proxyType, proxyHost, proxyPort, proxyUsername, proxyPassword)
+	if proxyURLStr == "" {
+		return nil
+	}
+	proxyURL, err := url.Parse(proxyURLStr)
+	if err != nil {
+		log.Printf("Failed to parse proxy URL: %v", err)
+		return nil
+	}
+	return proxyURL
+}
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"MrRSS/internal/cache"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+const (
+	// offlineMaxArticles is the largest number of articles kept for offline reading
+	offlineMaxArticles = 1000
+	// offlinePrefetchBatch is the largest number of articles downloaded per run
+	offlinePrefetchBatch = 50
+	// defaultOfflineMaxSizeMB is used when offline_prefetch_max_size_mb is not set
+	defaultOfflineMaxSizeMB = 500
+	// offlineThumbnailWidth matches the width of the thumbnails of the article list
+	offlineThumbnailWidth = 160
+)
+
+// OfflineStatus describes the articles available for offline reading
+type OfflineStatus struct {
+	IsRunning    bool    `json:"is_running"`
+	ArticleCount int     `json:"article_count"`
+	SizeMB       float64 `json:"size_mb"`
+	MaxSizeMB    int     `json:"max_size_mb"`
+}
+
+// offlineScope reads the articles to download for offline reading from the settings
+func (h *Handler) offlineScope() database.OfflineScope {
+	readLater, _ := h.DB.GetSetting("offline_prefetch_read_later")
+	favorites, _ := h.DB.GetSetting("offline_prefetch_favorites")
+	unread, _ := h.DB.GetSetting("offline_prefetch_unread")
+	categories, _ := h.DB.GetSetting("offline_prefetch_categories")
+
+	scope := database.OfflineScope{
+		ReadLater: readLater == "true",
+		Favorites: favorites == "true",
+		Unread:    unread == "true",
+	}
+	for _, category := range strings.Split(categories, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			scope.Categories = append(scope.Categories, category)
+		}
+	}
+	return scope
+}
+
+// offlineMaxSizeMB returns the disk budget for offline reading
+func (h *Handler) offlineMaxSizeMB() int {
+	maxSizeMBStr, _ := h.DB.GetSetting("offline_prefetch_max_size_mb")
+	maxSizeMB, err := strconv.Atoi(maxSizeMBStr)
+	if err != nil || maxSizeMB <= 0 {
+		maxSizeMB = defaultOfflineMaxSizeMB
+	}
+	return maxSizeMB
+}
+
+// GetOfflineStatus returns the number and size of the articles available offline
+func (h *Handler) GetOfflineStatus() (OfflineStatus, error) {
+	count, size, err := h.DB.GetOfflineStats()
+	if err != nil {
+		return OfflineStatus{}, err
+	}
+
+	return OfflineStatus{
+		IsRunning:    h.offlineRunning.Load(),
+		ArticleCount: count,
+		SizeMB:       float64(size) / (1024 * 1024),
+		MaxSizeMB:    h.offlineMaxSizeMB(),
+	}, nil
+}
+
+// PrefetchOffline downloads the full content and images of the articles in the
+// offline scope, so they can be read without a network connection. Articles that
+// left the scope are released first, then new articles are downloaded in order
+// of priority until the disk budget is used. It does nothing if offline
+// prefetching is disabled or already running.
+func (h *Handler) PrefetchOffline() {
+	if enabled, _ := h.DB.GetSetting("offline_prefetch_enabled"); enabled != "true" {
+		return
+	}
+	if !h.offlineRunning.CompareAndSwap(false, true) {
+		return
+	}
+	defer h.offlineRunning.Store(false)
+
+	candidates, err := h.DB.GetOfflineCandidates(h.offlineScope(), offlineMaxArticles)
+	if err != nil {
+		log.Printf("Offline prefetch: %v", err)
+		return
+	}
+	inScope := make(map[int64]bool, len(candidates))
+	for _, article := range candidates {
+		inScope[article.ID] = true
+	}
+
+	// Release articles that were read, removed from read later, or deleted
+	offlineIDs, err := h.DB.GetOfflineArticleIDs()
+	if err != nil {
+		log.Printf("Offline prefetch: %v", err)
+		return
+	}
+	available := make(map[int64]bool, len(offlineIDs))
+	for _, id := range offlineIDs {
+		if !inScope[id] {
+			if err := h.DB.DeleteOfflineArticle(id); err != nil {
+				log.Printf("Offline prefetch: %v", err)
+			}
+			continue
+		}
+		available[id] = true
+	}
+
+	_, usedSize, err := h.DB.GetOfflineStats()
+	if err != nil {
+		log.Printf("Offline prefetch: %v", err)
+		return
+	}
+	maxSize := int64(h.offlineMaxSizeMB()) * 1024 * 1024
+
+	mediaCache := h.offlineMediaCache()
+
+	downloaded := 0
+	for _, article := range candidates {
+		if downloaded >= offlinePrefetchBatch || usedSize >= maxSize {
+			break
+		}
+		if available[article.ID] {
+			continue
+		}
+
+		content := h.offlineContent(article)
+		if content == "" {
+			continue
+		}
+
+		var mediaHashes []string
+		var mediaSize int64
+		if mediaCache != nil {
+			mediaHashes, mediaSize = h.prefetchArticleMedia(mediaCache, article, content)
+		}
+
+		size := int64(len(content)) + mediaSize
+		if usedSize+size > maxSize {
+			log.Printf("Offline prefetch: disk budget of %d MB reached", maxSize/(1024*1024))
+			break
+		}
+		if err := h.DB.SaveOfflineArticle(article.ID, content, mediaHashes, mediaSize); err != nil {
+			log.Printf("Offline prefetch: %v", err)
+			continue
+		}
+		usedSize += size
+		downloaded++
+	}
+
+	if downloaded > 0 {
+		log.Printf("Offline prefetch: downloaded %d articles", downloaded)
+	}
+}
+
+// offlineContent returns the full content of an article, falling back to the
+// content of the feed if the page cannot be parsed
+func (h *Handler) offlineContent(article models.Article) string {
+	content, err := h.FetchFullArticleContent(article.URL)
+	if err == nil && strings.TrimSpace(content) != "" {
+		return content
+	}
+	feedContent, found, dbErr := h.DB.GetArticleContent(article.ID)
+	if dbErr == nil && found && strings.TrimSpace(feedContent) != "" {
+		return feedContent
+	}
+	if err != nil {
+		log.Printf("Offline prefetch: failed to fetch %s: %v", article.URL, err)
+	}
+	return ""
+}
+
+// offlineMediaCache returns the media cache used to store images for offline
+// reading, or nil if the media cache is disabled, as images would not be served
+// from it.
+func (h *Handler) offlineMediaCache() *cache.MediaCache {
+	if enabled, _ := h.DB.GetSetting("media_cache_enabled"); enabled != "true" {
+		return nil
+	}
+	cacheDir, err := utils.GetMediaCacheDir()
+	if err != nil {
+		log.Printf("Failed to get media cache directory: %v", err)
+		return nil
+	}
+	mediaCache, err := cache.NewMediaCache(cacheDir, h.DB)
+	if err != nil {
+		log.Printf("Failed to initialize media cache: %v", err)
+		return nil
+	}
+	mediaCache.SetClient(h.MediaClient(h.MediaRequestPolicy(), false), h.MaxMediaSize())
+	return mediaCache
+}
+
+// prefetchArticleMedia caches the images of an article and its list thumbnail.
+// It returns the URL hashes of the cached files and their total size.
+func (h *Handler) prefetchArticleMedia(mediaCache *cache.MediaCache, article models.Article, content string) ([]string, int64) {
+	var hashes []string
+	var size int64
+	seen := make(map[string]bool)
+	add := func(file *cache.CachedFile) {
+		if !seen[file.Path] {
+			seen[file.Path] = true
+			hashes = append(hashes, file.URLHash)
+			size += file.Size
+		}
+	}
+
+	if article.ImageURL != "" {
+		if file, err := mediaCache.FetchVariant(article.ImageURL, article.URL, offlineThumbnailWidth); err == nil {
+			add(file)
+		}
+	}
+	for _, imageURL := range extractImageURLs(content, article.URL) {
+		file, err := mediaCache.Fetch(imageURL, article.URL)
+		if err != nil {
+			continue
+		}
+		add(file)
+	}
+	return hashes, size
+}
+
+// extractImageURLs returns the absolute URLs of the images in HTML content
+func extractImageURLs(content, baseURL string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+	base, _ := url.Parse(baseURL)
+
+	var urls []string
+	seen := make(map[string]bool)
+	doc.Find("img[src]").Each(func(_ int, img *goquery.Selection) {
+		src := strings.TrimSpace(img.AttrOr("src", ""))
+		if src == "" || strings.HasPrefix(src, "data:") || strings.HasPrefix(src, "blob:") {
+			return
+		}
+		u, err := url.Parse(src)
+		if err != nil {
+			return
+		}
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return
+		}
+		if abs := u.String(); !seen[abs] {
+			seen[abs] = true
+			urls = append(urls, abs)
+		}
+	})
+	return urls
+}
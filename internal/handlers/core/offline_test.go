@@ -0,0 +1,107 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+func TestPrefetchOffline(t *testing.T) {
+	tmp := t.TempDir()
+	_ = os.Setenv("APPDATA", tmp)
+	_ = os.Setenv("HOME", tmp)
+	_ = os.Setenv("XDG_DATA_HOME", tmp)
+
+	var imageData bytes.Buffer
+	if err := png.Encode(&imageData, image.NewRGBA(image.Rect(0, 0, 10, 10))); err != nil {
+		t.Fatalf("encode image: %v", err)
+	}
+	paragraph := strings.Repeat("This paragraph is long enough to be recognized as the main content of the page. ", 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/photo.png" {
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(imageData.Bytes())
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><head><title>Story</title></head><body><article><h1>Story</h1><p>%s</p><img src="/photo.png"><p>%s</p></article></body></html>`, paragraph, paragraph)
+	}))
+	defer server.Close()
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init failed: %v", err)
+	}
+	h := NewHandler(db, nil, nil)
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Feed", URL: server.URL + "/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	for _, title := range []string{"Saved", "Other"} {
+		err := db.SaveArticle(&models.Article{FeedID: feedID, Title: title, URL: server.URL + "/" + strings.ToLower(title), PublishedAt: time.Now()})
+		if err != nil {
+			t.Fatalf("SaveArticle failed: %v", err)
+		}
+	}
+	savedID, _ := db.GetArticleIDByURL(server.URL + "/saved")
+	otherID, _ := db.GetArticleIDByURL(server.URL + "/other")
+	if err := db.SetArticleReadLater(savedID, true); err != nil {
+		t.Fatalf("SetArticleReadLater failed: %v", err)
+	}
+
+	_ = db.SetSetting("offline_prefetch_enabled", "true")
+	_ = db.SetSetting("media_cache_enabled", "true")
+	_ = db.SetSetting("media_proxy_private_networks", "allow")
+
+	h.PrefetchOffline()
+
+	content, found, err := db.GetOfflineContent(savedID)
+	if err != nil || !found {
+		t.Fatalf("expected read later article to be available offline, got %v, %v", found, err)
+	}
+	if !strings.Contains(content, "main content") {
+		t.Errorf("expected full content, got %q", content)
+	}
+	if _, found, _ := db.GetOfflineContent(otherID); found {
+		t.Error("expected article outside of the scope not to be downloaded")
+	}
+
+	// The image is pinned in the media cache
+	entries, err := db.GetMediaCacheEntries()
+	if err != nil || len(entries) != 1 || !entries[0].Pinned {
+		t.Fatalf("expected one pinned media file, got %+v, %v", entries, err)
+	}
+
+	// Content is served without the network
+	server.Close()
+	if got, err := h.GetArticleContent(savedID); err != nil || got != content {
+		t.Errorf("GetArticleContent() = %q, %v", got, err)
+	}
+
+	// Articles that leave the scope are released
+	if err := db.SetArticleReadLater(savedID, false); err != nil {
+		t.Fatalf("SetArticleReadLater failed: %v", err)
+	}
+	h.PrefetchOffline()
+	if _, found, _ := db.GetOfflineContent(savedID); found {
+		t.Error("expected article to be released")
+	}
+	entries, _ = db.GetMediaCacheEntries()
+	if len(entries) != 1 || entries[0].Pinned {
+		t.Errorf("expected media file to be unpinned, got %+v", entries)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"MrRSS/internal/cache"
+	"MrRSS/internal/feed"
 	"MrRSS/internal/utils"
+	"MrRSS/internal/websub"
 )
 
 // StartBackgroundScheduler starts the background scheduler for auto-updates and cleanup.
@@ -35,6 +37,14 @@ func (h *Handler) StartBackgroundScheduler(ctx context.Context) {
 		}
 	}()
 
+	// Resume interrupted podcast downloads
+	h.Podcasts.Start(ctx)
+
+	// Subscribe to the WebSub hubs of feeds and renew their leases
+	if h.WebSub != nil {
+		h.WebSub.Start(ctx)
+	}
+
 	// Start the scheduler based on refresh mode
 	refreshMode, _ := h.DB.GetSetting("refresh_mode")
 
@@ -51,6 +61,7 @@ func (h *Handler) StartBackgroundScheduler(ctx context.Context) {
 // Logic:
 // 1. Individual feeds with custom intervals (RefreshInterval != 0) are scheduled individually
 // 2. Global refresh is triggered at the global interval for feeds using global setting (RefreshInterval == 0)
+// 3. In intelligent mode, feeds using global setting are scheduled individually too, from their refresh predictions
 func (h *Handler) startScheduler(ctx context.Context, intelligentMode bool) {
 	modeName := "fixed"
 	if intelligentMode {
@@ -121,7 +132,7 @@ func (h *Handler) startScheduler(ctx context.Context, intelligentMode bool) {
 }
 
 // triggerGlobalRefresh triggers a global refresh for all feeds with RefreshInterval == 0
-// In intelligent mode, this calculates intervals per feed
+// In intelligent mode, these feeds are refreshed individually by scheduleIndividualFeeds
 // In fixed mode, all feeds refresh together at the global interval
 func (h *Handler) triggerGlobalRefresh(ctx context.Context, intelligentMode bool, lastGlobalRefresh *time.Time) {
 	feeds, err := h.DB.GetFeeds()
@@ -138,10 +149,10 @@ func (h *Handler) triggerGlobalRefresh(ctx context.Context, intelligentMode bool
 		}
 	}
 
-	// Check if there are any refreshable feeds (excluding FreshRSS feeds)
+	// Check if there are any refreshable feeds (excluding FreshRSS, paused and gone feeds)
 	refreshableFeeds := make([]models.Feed, 0)
 	for _, feed := range globalFeeds {
-		if !feed.IsFreshRSSSource {
+		if !feed.IsFreshRSSSource && !feed.IsPaused && !feed.IsGone {
 			refreshableFeeds = append(refreshableFeeds, feed)
 		}
 	}
@@ -149,7 +160,7 @@ func (h *Handler) triggerGlobalRefresh(ctx context.Context, intelligentMode bool
 	// If no refreshable feeds, skip updating last_global_refresh
 	// This allows the next refresh to be triggered when feeds are added
 	if len(refreshableFeeds) == 0 {
-		log.Printf("No refreshable feeds found (all %d feeds are FreshRSS sources), skipping global refresh", len(globalFeeds))
+		log.Printf("No refreshable feeds found (all %d feeds are FreshRSS sources, paused or gone), skipping global refresh", len(globalFeeds))
 		return
 	}
 
@@ -163,30 +174,39 @@ func (h *Handler) triggerGlobalRefresh(ctx context.Context, intelligentMode bool
 		log.Printf("Failed to save last_global_refresh to settings: %v", err)
 	}
 
-	log.Printf("Triggering global refresh for %d refreshable feeds (skipped %d FreshRSS feeds, intelligent mode: %v)",
+	log.Printf("Triggering global refresh for %d refreshable feeds (skipped %d FreshRSS, paused or gone feeds, intelligent mode: %v)",
 		len(refreshableFeeds), len(globalFeeds)-len(refreshableFeeds), intelligentMode)
 
-	if intelligentMode {
-		// In intelligent mode, schedule each feed individually with calculated intervals
-		calculator := h.Fetcher.GetIntelligentRefreshCalculator()
-		for _, feed := range refreshableFeeds {
-			interval := calculator.CalculateInterval(feed)
-			staggerDelay := h.Fetcher.GetStaggeredDelay(feed.ID, len(refreshableFeeds))
+	pushed := h.pushedFeeds()
 
-			go func(f models.Feed, delay time.Duration, calculatedInterval time.Duration) {
-				time.Sleep(delay)
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					log.Printf("Auto-refreshing feed %s (intelligent mode, interval: %v)", f.Title, calculatedInterval)
-					h.Fetcher.FetchSingleFeed(ctx, f, false)
-				}
-			}(feed, staggerDelay, interval)
-		}
+	if intelligentMode {
+		// In intelligent mode, each feed is refreshed when its refresh prediction is due,
+		// see scheduleIndividualFeeds
+		log.Printf("Intelligent mode: %d feeds are refreshed from their publication patterns", len(refreshableFeeds))
 	} else {
-		// In fixed mode, refresh all feeds together
-		h.Fetcher.FetchAll(ctx)
+		// In fixed mode, refresh all feeds together, leaving out the feeds updated by pushes
+		// until their fallback poll and the feeds asking not to be refreshed yet with <ttl>,
+		// sy:updatePeriod, <skipHours> or <skipDays>
+		now := time.Now()
+		pollFeeds := make([]models.Feed, 0, len(feeds))
+		pushSkipped, hintSkipped := 0, 0
+		for _, f := range feeds {
+			if f.IsFreshRSSSource || f.IsPaused || f.IsGone {
+				continue
+			}
+			if skipPolling(f, pushed) {
+				pushSkipped++
+				continue
+			}
+			if !feed.HintsAllowRefresh(f, now) {
+				hintSkipped++
+				continue
+			}
+			pollFeeds = append(pollFeeds, f)
+		}
+		log.Printf("Standard refresh: %d feeds (skipped %d feeds updated by WebSub, %d feeds by their refresh limits)",
+			len(pollFeeds), pushSkipped, hintSkipped)
+		h.Fetcher.RefreshFeeds(ctx, pollFeeds)
 	}
 
 	// Run media cache cleanup if enabled
@@ -196,7 +216,8 @@ func (h *Handler) triggerGlobalRefresh(ctx context.Context, intelligentMode bool
 	}
 }
 
-// scheduleIndividualFeeds schedules feeds with custom intervals (RefreshInterval != 0)
+// scheduleIndividualFeeds schedules feeds with custom intervals (RefreshInterval != 0),
+// and in intelligent mode the feeds using global setting too
 // These feeds are refreshed independently of the global refresh cycle
 func (h *Handler) scheduleIndividualFeeds(ctx context.Context, intelligentMode bool) {
 	feeds, err := h.DB.GetFeeds()
@@ -206,15 +227,27 @@ func (h *Handler) scheduleIndividualFeeds(ctx context.Context, intelligentMode b
 	}
 
 	calculator := h.Fetcher.GetIntelligentRefreshCalculator()
+	pushed := h.pushedFeeds()
+	now := time.Now()
 
-	for _, feed := range feeds {
-		// Skip feeds using global setting (RefreshInterval == 0)
-		if feed.RefreshInterval == 0 {
+	for _, f := range feeds {
+		// Skip feeds using global setting (RefreshInterval == 0), unless in intelligent mode
+		if f.RefreshInterval == 0 && !intelligentMode {
 			continue
 		}
 
 		// Skip FreshRSS feeds - they are refreshed via sync, not standard refresh
-		if feed.IsFreshRSSSource {
+		if f.IsFreshRSSSource {
+			continue
+		}
+
+		// Skip paused and gone feeds - they are only refreshed on demand
+		if f.IsPaused || f.IsGone {
+			continue
+		}
+
+		// Skip consistently failing feeds until their backoff ends
+		if feed.InBackoff(f, now) {
 			continue
 		}
 
@@ -227,36 +260,76 @@ func (h *Handler) scheduleIndividualFeeds(ctx context.Context, intelligentMode b
 
 		// Determine refresh interval
 		var refreshInterval time.Duration
-		if feed.RefreshInterval > 0 {
+		reason := "custom interval"
+		if f.RefreshInterval > 0 {
 			// Use custom fixed interval
-			refreshInterval = time.Duration(feed.RefreshInterval) * time.Minute
-		} else if feed.RefreshInterval == -1 {
-			// Use intelligent interval
-			refreshInterval = calculator.CalculateInterval(feed)
+			refreshInterval = time.Duration(f.RefreshInterval) * time.Minute
+		} else {
+			// Use intelligent interval, predicted from the publication pattern of the feed
+			prediction := calculator.Predict(f, now)
+			refreshInterval = prediction.Interval()
+			reason = "intelligent mode, " + prediction.Strategy
+		}
+
+		// Feeds updated by WebSub pushes are polled less often
+		if pushed[f.ID] && refreshInterval < websub.FallbackPollInterval {
+			refreshInterval = websub.FallbackPollInterval
 		}
 
 		// Check if feed needs refresh based on last_updated time
-		timeSinceUpdate := time.Since(feed.LastUpdated)
+		timeSinceUpdate := time.Since(f.LastUpdated)
 		if timeSinceUpdate >= refreshInterval {
+			// Skip feeds still waiting for the refresh scheduled on an earlier tick
+			if _, scheduled := h.scheduledFeeds.LoadOrStore(f.ID, true); scheduled {
+				continue
+			}
+
 			// Apply staggered delay
-			staggerDelay := h.Fetcher.GetStaggeredDelay(feed.ID, len(feeds))
+			staggerDelay := h.Fetcher.GetStaggeredDelay(f.ID, len(feeds))
 
 			// Schedule feed refresh
-			feedCopy := feed
-			go func(f models.Feed, delay time.Duration, interval time.Duration) {
+			feedCopy := f
+			go func(f models.Feed, delay time.Duration, interval time.Duration, reason string) {
+				defer h.scheduledFeeds.Delete(f.ID)
 				time.Sleep(delay)
 				select {
 				case <-ctx.Done():
 					return
 				default:
-					log.Printf("Auto-refreshing feed %s (custom interval: %v)", f.Title, interval)
-					h.Fetcher.FetchSingleFeed(ctx, f, false)
+					h.refreshScheduledFeed(ctx, f, interval, reason)
 				}
-			}(feedCopy, staggerDelay, refreshInterval)
+			}(feedCopy, staggerDelay, refreshInterval, reason)
 		}
 	}
 }
 
+// refreshScheduledFeed queues the scheduled refresh of a feed, unless the feed was
+// refreshed or deleted while waiting for its staggered delay
+func (h *Handler) refreshScheduledFeed(ctx context.Context, f models.Feed, interval time.Duration, reason string) bool {
+	current, err := h.DB.GetFeedByID(f.ID)
+	if err != nil || current.LastUpdated.After(f.LastUpdated) {
+		return false
+	}
+
+	log.Printf("Auto-refreshing feed %s (%s: %v)", current.Title, reason, interval)
+	h.Fetcher.FetchSingleFeed(ctx, *current, false)
+	return true
+}
+
+// pushedFeeds returns the IDs of the feeds with an active WebSub subscription
+func (h *Handler) pushedFeeds() map[int64]bool {
+	if h.WebSub == nil {
+		return nil
+	}
+	return h.WebSub.PushedFeeds()
+}
+
+// skipPolling reports whether a feed updated by WebSub pushes was polled
+// recently enough to skip a scheduled refresh
+func skipPolling(feed models.Feed, pushed map[int64]bool) bool {
+	return pushed[feed.ID] && time.Since(feed.LastUpdated) < websub.FallbackPollInterval
+}
+
 // cleanupMediaCache performs media cache cleanup based on settings
 func (h *Handler) cleanupMediaCache() {
 	cacheDir, err := utils.GetMediaCacheDir()
@@ -265,7 +338,7 @@ func (h *Handler) cleanupMediaCache() {
 		return
 	}
 
-	mediaCache, err := cache.NewMediaCache(cacheDir)
+	mediaCache, err := cache.NewMediaCache(cacheDir, h.DB)
 	if err != nil {
 		log.Printf("Failed to initialize media cache: %v", err)
 		return
@@ -0,0 +1,51 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/models"
+)
+
+func TestRefreshScheduledFeed(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init failed: %v", err)
+	}
+	h := NewHandler(db, feed.NewFetcher(db, nil), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	id, err := db.AddFeed(&models.Feed{Title: "Feed", URL: "http://127.0.0.1:1/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	stale, err := db.GetFeedByID(id)
+	if err != nil {
+		t.Fatalf("GetFeedByID failed: %v", err)
+	}
+
+	if !h.refreshScheduledFeed(ctx, *stale, time.Hour, "custom interval") {
+		t.Error("expected a feed not refreshed since scheduling to be queued")
+	}
+
+	// Refreshed while the scheduled refresh was waiting for its delay
+	stale.LastUpdated = time.Now().Add(-time.Hour)
+	if err := db.UpdateFeedLastUpdated(id); err != nil {
+		t.Fatalf("UpdateFeedLastUpdated failed: %v", err)
+	}
+	if h.refreshScheduledFeed(ctx, *stale, time.Hour, "custom interval") {
+		t.Error("expected a feed refreshed since scheduling to be skipped")
+	}
+
+	if h.refreshScheduledFeed(ctx, models.Feed{ID: id + 1}, time.Hour, "custom interval") {
+		t.Error("expected a deleted feed to be skipped")
+	}
+}
@@ -133,3 +133,41 @@ func TestHandleDiscoverBlogs_Success(t *testing.T) {
 		t.Fatalf("expected 200 from clear, got %d", cw.Result().StatusCode)
 	}
 }
+
+func TestHandleRecommendations_NoCachedArticles(t *testing.T) {
+	h := setupHandler(t)
+
+	sw := httptest.NewRecorder()
+	HandleStartRecommendations(h, sw, httptest.NewRequest(http.MethodPost, "/api/feeds/recommendations/start", nil))
+	if sw.Result().StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202 Accepted from start, got %d", sw.Result().StatusCode)
+	}
+
+	var state core.RecommendationState
+	for i := 0; i < 50; i++ {
+		pw := httptest.NewRecorder()
+		HandleGetRecommendationsProgress(h, pw, httptest.NewRequest(http.MethodGet, "/api/feeds/recommendations/progress", nil))
+		if err := json.NewDecoder(pw.Result().Body).Decode(&state); err != nil {
+			t.Fatalf("failed to decode progress: %v", err)
+		}
+		if state.IsComplete {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if !state.IsComplete || state.IsRunning || state.Error != "" {
+		t.Fatalf("expected completed recommendations, got %+v", state)
+	}
+	if len(state.Recommendations) != 0 {
+		t.Errorf("expected no recommendations, got %d", len(state.Recommendations))
+	}
+
+	cw := httptest.NewRecorder()
+	HandleClearRecommendations(h, cw, httptest.NewRequest(http.MethodPost, "/api/feeds/recommendations/clear", nil))
+	if cw.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 from clear, got %d", cw.Result().StatusCode)
+	}
+	if h.RecommendationState != nil {
+		t.Error("expected recommendation state to be cleared")
+	}
+}
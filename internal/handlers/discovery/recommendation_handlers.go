@@ -0,0 +1,175 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/discovery"
+	"MrRSS/internal/handlers/core"
+)
+
+// maxRecommendationArticles limits the number of cached articles mined for links
+const maxRecommendationArticles = 5000
+
+// HandleStartRecommendations starts building feed recommendations in the background.
+// Recommendations are the blogs most often linked from the articles of subscribed feeds.
+func HandleStartRecommendations(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Check if recommendations are already being built
+	h.DiscoveryMu.Lock()
+	if h.RecommendationState != nil && h.RecommendationState.IsRunning {
+		h.DiscoveryMu.Unlock()
+		http.Error(w, "Recommendations already in progress", http.StatusConflict)
+		return
+	}
+
+	// Initialize state
+	h.RecommendationState = &core.RecommendationState{
+		IsRunning: true,
+		Progress: discovery.Progress{
+			Stage:   "starting",
+			Message: "Starting recommendations",
+		},
+	}
+	h.DiscoveryMu.Unlock()
+
+	feeds, err := h.DB.GetFeeds()
+	if err != nil {
+		h.DiscoveryMu.Lock()
+		h.RecommendationState.IsRunning = false
+		h.RecommendationState.IsComplete = true
+		h.RecommendationState.Error = err.Error()
+		h.DiscoveryMu.Unlock()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Get all existing feed URLs for deduplication
+	subscribedURLs, err := h.DB.GetAllFeedURLs()
+	if err != nil {
+		log.Printf("Error getting subscribed URLs: %v", err)
+		subscribedURLs = make(map[string]bool)
+	}
+
+	// Links to the sites of subscribed feeds are not counted
+	var subscribedHosts []string
+	for _, feed := range feeds {
+		subscribedHosts = append(subscribedHosts, feed.URL, feed.Link)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), core.BatchDiscoveryTimeout)
+		defer cancel()
+
+		h.DiscoveryMu.Lock()
+		if h.RecommendationState != nil {
+			h.RecommendationState.Progress = discovery.Progress{
+				Stage:   "collecting_links",
+				Message: "Collecting links from cached articles",
+			}
+		}
+		h.DiscoveryMu.Unlock()
+
+		counter := h.DiscoveryService.NewCitationCounter(subscribedHosts)
+		articleCount := 0
+		err := h.DB.EachRecentArticleContent(maxRecommendationArticles, func(c database.FeedArticleContent) {
+			counter.AddArticle(c.FeedID, c.URL, c.Content)
+			articleCount++
+		})
+		if err != nil {
+			log.Printf("Error collecting links for recommendations: %v", err)
+			h.DiscoveryMu.Lock()
+			if h.RecommendationState != nil {
+				h.RecommendationState.IsRunning = false
+				h.RecommendationState.IsComplete = true
+				h.RecommendationState.Error = err.Error()
+			}
+			h.DiscoveryMu.Unlock()
+			return
+		}
+
+		citations := counter.Citations()
+		log.Printf("Building recommendations: %d cited domains in %d articles", len(citations), articleCount)
+
+		progressCb := func(progress discovery.Progress) {
+			h.DiscoveryMu.Lock()
+			if h.RecommendationState != nil {
+				h.RecommendationState.Progress = progress
+			}
+			h.DiscoveryMu.Unlock()
+		}
+		recommended := h.DiscoveryService.RecommendFromCitations(ctx, citations, progressCb)
+
+		// Filter out already-subscribed feeds
+		recommendations := make([]discovery.Recommendation, 0)
+		for _, rec := range recommended {
+			if subscribedURLs[rec.RSSFeed] {
+				continue
+			}
+			subscribedURLs[rec.RSSFeed] = true
+			recommendations = append(recommendations, rec)
+			if len(recommendations) == discovery.MaxRecommendations {
+				break
+			}
+		}
+
+		log.Printf("Recommendations complete: %d feeds from %d cited domains", len(recommendations), len(citations))
+
+		h.DiscoveryMu.Lock()
+		if h.RecommendationState != nil {
+			h.RecommendationState.IsRunning = false
+			h.RecommendationState.IsComplete = true
+			h.RecommendationState.Progress.Stage = "complete"
+			h.RecommendationState.Progress.Message = fmt.Sprintf("Found %d feeds from %d articles", len(recommendations), articleCount)
+			h.RecommendationState.Progress.FoundCount = len(recommendations)
+			h.RecommendationState.Recommendations = recommendations
+		}
+		h.DiscoveryMu.Unlock()
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "started",
+	})
+}
+
+// HandleGetRecommendationsProgress returns the current progress of building recommendations.
+func HandleGetRecommendationsProgress(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.DiscoveryMu.RLock()
+	defer h.DiscoveryMu.RUnlock()
+
+	if h.RecommendationState == nil {
+		json.NewEncoder(w).Encode(&core.RecommendationState{})
+		return
+	}
+
+	json.NewEncoder(w).Encode(h.RecommendationState)
+}
+
+// HandleClearRecommendations clears the recommendation state.
+func HandleClearRecommendations(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.DiscoveryMu.Lock()
+	h.RecommendationState = nil
+	h.DiscoveryMu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"status": "cleared"})
+}
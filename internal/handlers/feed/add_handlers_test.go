@@ -0,0 +1,79 @@
+package feed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"MrRSS/internal/discovery"
+	fh "MrRSS/internal/handlers/feed"
+)
+
+func TestHandleAddFeed_PageURLReturnsCandidates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><head><link rel="alternate" type="application/atom+xml" href="/atom.xml"></head></html>`))
+		case "/atom.xml":
+			w.Write([]byte(`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Site</title>` +
+				`<entry><title>Post</title><updated>2024-01-15T10:30:00Z</updated></entry></feed>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	h := setupHandler(t)
+	body, _ := json.Marshal(map[string]string{"url": server.URL + "/"})
+	w := httptest.NewRecorder()
+	fh.HandleAddFeed(h, w, httptest.NewRequest("POST", "/api/feeds/add", bytes.NewReader(body)))
+
+	if w.Code != http.StatusMultipleChoices {
+		t.Fatalf("expected 300 for a page URL, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Candidates []discovery.FeedCandidate `json:"candidates"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Candidates) != 1 || resp.Candidates[0].URL != server.URL+"/atom.xml" || resp.Candidates[0].Type != "atom" {
+		t.Errorf("unexpected candidates: %+v", resp.Candidates)
+	}
+
+	feeds, _ := h.DB.GetFeeds()
+	if len(feeds) != 0 {
+		t.Errorf("expected no subscription for a page URL, got %d feeds", len(feeds))
+	}
+}
+
+func TestHandleAddFeed_FeedURLFetchedOnce(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Write([]byte(`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Site</title>` +
+			`<entry><title>Post</title><updated>2024-01-15T10:30:00Z</updated></entry></feed>`))
+	}))
+	defer server.Close()
+
+	h := setupHandler(t)
+	// Leave out the refresh that follows the subscription
+	h.Fetcher.GetTaskManager().Stop()
+	body, _ := json.Marshal(map[string]string{"url": server.URL + "/atom.xml", "category": "Tech"})
+	w := httptest.NewRecorder()
+	fh.HandleAddFeed(h, w, httptest.NewRequest("POST", "/api/feeds/add", bytes.NewReader(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a feed URL, got %d: %s", w.Code, w.Body.String())
+	}
+	feeds, _ := h.DB.GetFeeds()
+	if len(feeds) != 1 || feeds[0].Title != "Site" || feeds[0].Category != "Tech" {
+		t.Errorf("unexpected feeds: %+v", feeds)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("expected the feed to be downloaded once when subscribing, got %d requests", n)
+	}
+}
@@ -0,0 +1,56 @@
+package feed_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	ff "MrRSS/internal/feed"
+	fh "MrRSS/internal/handlers/feed"
+	"MrRSS/internal/models"
+)
+
+func TestHandleFeedDetail(t *testing.T) {
+	h := setupHandler(t)
+
+	id, _ := h.DB.AddFeed(&models.Feed{Title: "daily", URL: "http://x/daily", RefreshInterval: -1})
+	articles := make([]*models.Article, 0, 12)
+	for i := 0; i < 12; i++ {
+		articles = append(articles, &models.Article{
+			FeedID:      id,
+			Title:       "Article " + strconv.Itoa(i),
+			URL:         "http://x/daily/" + strconv.Itoa(i),
+			PublishedAt: time.Now().Add(-time.Duration(i) * 5 * time.Hour),
+		})
+	}
+	if err := h.DB.SaveArticles(context.Background(), articles); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	fh.HandleFeedDetail(h, w, httptest.NewRequest(http.MethodGet, "/api/feeds/detail?id="+strconv.FormatInt(id, 10), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp fh.FeedDetailResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Feed == nil || resp.Feed.ID != id || !resp.Intelligent {
+		t.Fatalf("unexpected feed %+v, intelligent %v", resp.Feed, resp.Intelligent)
+	}
+	p := resp.RefreshPrediction
+	if p.FeedID != id || p.Strategy != ff.RefreshStrategyAverage || p.Samples != 12 || p.Interval() != 150*time.Minute {
+		t.Errorf("unexpected prediction %+v", p)
+	}
+
+	w = httptest.NewRecorder()
+	fh.HandleFeedDetail(h, w, httptest.NewRequest(http.MethodGet, "/api/feeds/detail?id=999", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown feed: expected 404, got %d", w.Code)
+	}
+}
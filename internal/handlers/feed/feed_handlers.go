@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"time"
 
+	"MrRSS/internal/feed"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
 )
 
 // HandleFeeds returns all feeds.
@@ -25,7 +28,39 @@ func HandleFeeds(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(feeds)
 }
 
+// FeedDetailResponse is a feed with the prediction of its next refresh
+type FeedDetailResponse struct {
+	Feed              *models.Feed           `json:"feed"`
+	Intelligent       bool                   `json:"intelligent"` // Whether the prediction schedules the refreshes of the feed
+	RefreshPrediction feed.RefreshPrediction `json:"refresh_prediction"`
+}
+
+// HandleFeedDetail returns a feed and when it is refreshed next in intelligent mode, with
+// the publication pattern the prediction is based on.
+func HandleFeedDetail(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	f, err := h.DB.GetFeedByID(id)
+	if err != nil {
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+	f.EmailPassword = ""
+
+	refreshMode, _ := h.DB.GetSetting("refresh_mode")
+	json.NewEncoder(w).Encode(FeedDetailResponse{
+		Feed:              f,
+		Intelligent:       f.RefreshInterval == -1 || (f.RefreshInterval == 0 && refreshMode == "intelligent"),
+		RefreshPrediction: h.Fetcher.GetIntelligentRefreshCalculator().Predict(*f, time.Now()),
+	})
+}
+
 // HandleAddFeed adds a new feed subscription and immediately fetches its articles.
+// When the URL is a web page offering feeds, it responds with 300 Multiple Choices
+// and the candidate feeds instead of subscribing.
 func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		URL              string `json:"url"`
@@ -51,6 +86,12 @@ func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		XPathItemUid        string `json:"xpath_item_uid"`
 		ArticleViewMode     string `json:"article_view_mode"`
 		AutoExpandContent   string `json:"auto_expand_content"`
+		// Translation and summary policy fields
+		TranslationMode           string `json:"translation_mode"`
+		TranslationTargetLanguage string `json:"translation_target_language"`
+		SummaryProvider           string `json:"summary_provider"`
+		SummaryLength             string `json:"summary_length"`
+		PregenerateOnFetch        bool   `json:"pregenerate_on_fetch"`
 		// Email/Newsletter fields
 		EmailAddress    string `json:"email_address"`
 		EmailIMAPServer string `json:"email_imap_server"`
@@ -76,8 +117,25 @@ func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		// Add feed as email newsletter subscription
 		feedID, err = h.Fetcher.AddEmailSubscription(req.EmailAddress, req.EmailIMAPServer, req.EmailUsername, req.EmailPassword, req.Category, req.Title, req.EmailFolder, req.EmailIMAPPort)
 	} else {
-		// Add feed using URL
-		feedID, err = h.Fetcher.AddSubscription(req.URL, req.Category, req.Title)
+		// For a page URL, return the feeds it offers so that one can be chosen.
+		// If none is found, subscribing still tries to render the page.
+		candidates, parsedFeed, findErr := h.DiscoveryService.FindFeedCandidates(r.Context(), req.URL)
+		if findErr == nil && parsedFeed == nil && len(candidates) > 0 {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusMultipleChoices)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"candidates": candidates,
+			})
+			return
+		}
+
+		if parsedFeed != nil {
+			// Add the feed already downloaded while looking for candidates
+			feedID, err = h.Fetcher.AddParsedSubscription(req.URL, req.Category, req.Title, parsedFeed)
+		} else {
+			// Add feed using URL
+			feedID, err = h.Fetcher.AddSubscription(req.URL, req.Category, req.Title)
+		}
 	}
 
 	if err != nil {
@@ -97,6 +155,10 @@ func HandleAddFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := updateFeedAIPolicy(h, feed.ID, req.TranslationMode, req.TranslationTargetLanguage, req.SummaryProvider, req.SummaryLength, req.PregenerateOnFetch); err != nil {
+		http.Error(w, "feed created but failed to update settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	// Immediately fetch articles for the newly added feed in background
 	go func() {
@@ -149,6 +211,12 @@ func HandleUpdateFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		XPathItemUid        string `json:"xpath_item_uid"`
 		ArticleViewMode     string `json:"article_view_mode"`
 		AutoExpandContent   string `json:"auto_expand_content"`
+		// Translation and summary policy fields
+		TranslationMode           string `json:"translation_mode"`
+		TranslationTargetLanguage string `json:"translation_target_language"`
+		SummaryProvider           string `json:"summary_provider"`
+		SummaryLength             string `json:"summary_length"`
+		PregenerateOnFetch        bool   `json:"pregenerate_on_fetch"`
 		// Email/Newsletter fields
 		EmailAddress    string `json:"email_address"`
 		EmailIMAPServer string `json:"email_imap_server"`
@@ -166,9 +234,28 @@ func HandleUpdateFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := updateFeedAIPolicy(h, req.ID, req.TranslationMode, req.TranslationTargetLanguage, req.SummaryProvider, req.SummaryLength, req.PregenerateOnFetch); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	w.WriteHeader(http.StatusOK)
 }
 
+// updateFeedAIPolicy stores a feed's translation and summary overrides.
+// Empty modes are stored as 'global' so the feed follows the global settings.
+func updateFeedAIPolicy(h *core.Handler, feedID int64, translationMode, targetLanguage, summaryProvider, summaryLength string, pregenerateOnFetch bool) error {
+	if translationMode == "" {
+		translationMode = "global"
+	}
+	if summaryProvider == "" {
+		summaryProvider = "global"
+	}
+	if summaryLength == "" {
+		summaryLength = "global"
+	}
+	return h.DB.UpdateFeedAIPolicy(feedID, translationMode, targetLanguage, summaryProvider, summaryLength, pregenerateOnFetch)
+}
+
 // HandleRefreshFeed refreshes a single feed by ID with progress tracking.
 func HandleRefreshFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -0,0 +1,158 @@
+package feed
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+)
+
+// defaultFetchLogLimit is the number of fetch attempts listed by HandleFeedFetchLog by default
+const defaultFetchLogLimit = 50
+
+// FeedHealthResponse is the feed health dashboard
+type FeedHealthResponse struct {
+	WindowDays int           `json:"window_days"`
+	Feeds      []feed.Health `json:"feeds"`
+}
+
+// HandleFeedHealth returns the health of the feeds, computed from their fetch attempts
+// in the health window. The status query parameter keeps the feeds of the given
+// comma-separated statuses, such as "dead,stale".
+func HandleFeedHealth(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	feeds, err := h.DB.GetFeeds()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	fetches, err := h.DB.GetFeedFetchesSince(now.AddDate(0, 0, -feed.HealthWindowDays))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	health := feed.ComputeHealth(feeds, fetches, now)
+	if status := r.URL.Query().Get("status"); status != "" {
+		statuses := make(map[string]bool)
+		for _, s := range strings.Split(status, ",") {
+			statuses[strings.TrimSpace(s)] = true
+		}
+		filtered := make([]feed.Health, 0, len(health))
+		for _, fh := range health {
+			if statuses[fh.Status] {
+				filtered = append(filtered, fh)
+			}
+		}
+		health = filtered
+	}
+
+	json.NewEncoder(w).Encode(FeedHealthResponse{WindowDays: feed.HealthWindowDays, Feeds: health})
+}
+
+// HandleFeedFetchLog returns the latest fetch attempts of a feed, newest first.
+func HandleFeedFetchLog(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+	limit := defaultFetchLogLimit
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+
+	fetches, err := h.DB.GetFeedFetches(id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if fetches == nil {
+		fetches = []models.FeedFetch{}
+	}
+	json.NewEncoder(w).Encode(fetches)
+}
+
+// HandlePauseFeeds pauses or resumes the scheduled refreshes of feeds. Paused feeds
+// are still refreshed on demand.
+func HandlePauseFeeds(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IDs    []int64 `json:"ids"`
+		Paused bool    `json:"paused"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.DB.SetFeedsPaused(req.IDs, req.Paused); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "updated": len(req.IDs)})
+}
+
+// HandleUnsubscribeFeeds deletes feed subscriptions and their articles.
+func HandleUnsubscribeFeeds(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, id := range req.IDs {
+		if err := h.DB.DeleteFeed(id); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "deleted": len(req.IDs)})
+}
+
+// HandleFeedChanges returns the automatic feed changes, such as followed redirects,
+// newest first. Undone and ended changes are only listed with all=true.
+func HandleFeedChanges(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	changes, err := h.DB.GetFeedChanges(r.URL.Query().Get("all") == "true")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if changes == nil {
+		changes = []models.FeedChange{}
+	}
+	json.NewEncoder(w).Encode(changes)
+}
+
+// HandleUndoFeedChange reverts an automatic feed change. An undone change is not made again.
+func HandleUndoFeedChange(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	change, err := h.DB.GetFeedChange(req.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if change == nil {
+		http.Error(w, "Feed change not found", http.StatusNotFound)
+		return
+	}
+	if change.UndoneAt != nil {
+		http.Error(w, "Feed change already undone", http.StatusConflict)
+		return
+	}
+	if err := h.DB.UndoFeedChange(change); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
@@ -0,0 +1,125 @@
+package feed_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	ff "MrRSS/internal/feed"
+	fh "MrRSS/internal/handlers/feed"
+	"MrRSS/internal/models"
+)
+
+func TestFeedHealthAndBulkActions(t *testing.T) {
+	h := setupHandler(t)
+
+	okID, _ := h.DB.AddFeed(&models.Feed{Title: "ok", URL: "http://x/ok"})
+	deadID, _ := h.DB.AddFeed(&models.Feed{Title: "dead", URL: "http://x/dead"})
+	for days := 10; days >= 0; days -= 2 {
+		h.DB.RecordFeedFetch(&models.FeedFetch{FeedID: okID, FetchedAt: time.Now().AddDate(0, 0, -days), DurationMs: 100, StatusCode: 200, NewItems: 1})
+		h.DB.RecordFeedFetch(&models.FeedFetch{FeedID: deadID, FetchedAt: time.Now().AddDate(0, 0, -days), StatusCode: 404, ErrorClass: ff.ErrorClassHTTP4xx})
+	}
+
+	getHealth := func(query string) fh.FeedHealthResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		fh.HandleFeedHealth(h, w, httptest.NewRequest(http.MethodGet, "/api/feeds/health"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("health: expected 200, got %d", w.Code)
+		}
+		var resp fh.FeedHealthResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp
+	}
+
+	if resp := getHealth(""); len(resp.Feeds) != 2 || resp.WindowDays != ff.HealthWindowDays {
+		t.Fatalf("unexpected health %+v", resp)
+	}
+	resp := getHealth("?status=dead,stale")
+	if len(resp.Feeds) != 1 || resp.Feeds[0].FeedID != deadID || resp.Feeds[0].Status != ff.HealthDead {
+		t.Fatalf("unexpected dead feeds %+v", resp.Feeds)
+	}
+
+	w := httptest.NewRecorder()
+	fh.HandleFeedFetchLog(h, w, httptest.NewRequest(http.MethodGet, "/api/feeds/fetch-log?limit=2&id="+strconv.FormatInt(deadID, 10), nil))
+	var fetches []models.FeedFetch
+	if err := json.NewDecoder(w.Body).Decode(&fetches); err != nil || len(fetches) != 2 || fetches[0].StatusCode != 404 {
+		t.Fatalf("unexpected fetch log %+v, %v", fetches, err)
+	}
+
+	w = httptest.NewRecorder()
+	fh.HandlePauseFeeds(h, w, httptest.NewRequest(http.MethodPost, "/api/feeds/pause", strings.NewReader(`{"ids":[`+strconv.FormatInt(deadID, 10)+`],"paused":true}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("pause: expected 200, got %d", w.Code)
+	}
+	if resp := getHealth("?status=paused"); len(resp.Feeds) != 1 || !resp.Feeds[0].IsPaused {
+		t.Fatalf("unexpected paused feeds %+v", resp.Feeds)
+	}
+
+	w = httptest.NewRecorder()
+	fh.HandleUnsubscribeFeeds(h, w, httptest.NewRequest(http.MethodPost, "/api/feeds/unsubscribe", strings.NewReader(`{"ids":[`+strconv.FormatInt(deadID, 10)+`]}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unsubscribe: expected 200, got %d", w.Code)
+	}
+	if f, _ := h.DB.GetFeedByID(deadID); f != nil {
+		t.Errorf("feed %d still subscribed", deadID)
+	}
+	if resp := getHealth(""); len(resp.Feeds) != 1 || resp.Feeds[0].FeedID != okID {
+		t.Errorf("unexpected health after unsubscribe %+v", resp.Feeds)
+	}
+}
+
+func TestFeedChangesAndUndo(t *testing.T) {
+	h := setupHandler(t)
+
+	id, _ := h.DB.AddFeed(&models.Feed{Title: "moved", URL: "http://new/feed"})
+	change := &models.FeedChange{FeedID: id, Kind: database.FeedChangeRedirect, OldValue: "http://old/feed", NewValue: "http://new/feed"}
+	if err := h.DB.RecordFeedChange(change); err != nil {
+		t.Fatalf("RecordFeedChange: %v", err)
+	}
+
+	getChanges := func(query string) []models.FeedChange {
+		t.Helper()
+		w := httptest.NewRecorder()
+		fh.HandleFeedChanges(h, w, httptest.NewRequest(http.MethodGet, "/api/feeds/changes"+query, nil))
+		var changes []models.FeedChange
+		if err := json.NewDecoder(w.Body).Decode(&changes); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return changes
+	}
+	undo := func(id int64) int {
+		w := httptest.NewRecorder()
+		fh.HandleUndoFeedChange(h, w, httptest.NewRequest(http.MethodPost, "/api/feeds/changes/undo", strings.NewReader(`{"id":`+strconv.FormatInt(id, 10)+`}`)))
+		return w.Code
+	}
+
+	if changes := getChanges(""); len(changes) != 1 || changes[0].FeedTitle != "moved" {
+		t.Fatalf("unexpected changes %+v", changes)
+	}
+	if code := undo(change.ID); code != http.StatusOK {
+		t.Fatalf("undo: expected 200, got %d", code)
+	}
+	if f, _ := h.DB.GetFeedByID(id); f.URL != "http://old/feed" {
+		t.Errorf("URL after undo = %s", f.URL)
+	}
+	if code := undo(change.ID); code != http.StatusConflict {
+		t.Errorf("second undo: expected 409, got %d", code)
+	}
+	if code := undo(change.ID + 100); code != http.StatusNotFound {
+		t.Errorf("undo of missing change: expected 404, got %d", code)
+	}
+	if changes := getChanges(""); len(changes) != 0 {
+		t.Errorf("open changes after undo %+v", changes)
+	}
+	if changes := getChanges("?all=true"); len(changes) != 1 || changes[0].UndoneAt == nil {
+		t.Errorf("unexpected changes %+v", changes)
+	}
+}
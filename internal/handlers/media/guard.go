@@ -0,0 +1,19 @@
+package media
+
+import (
+	"errors"
+	"net/http"
+
+	"MrRSS/internal/netguard"
+)
+
+// isBlocked reports whether a request failed because the policy rejected it
+func isBlocked(err error) bool {
+	return errors.Is(err, netguard.ErrBlockedAddress) || errors.Is(err, netguard.ErrBlockedHost)
+}
+
+// setMediaSecurityHeaders prevents proxied media, such as SVG images, from running scripts
+func setMediaSecurityHeaders(w http.ResponseWriter) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+}
@@ -79,6 +79,20 @@ func TestHandleMediaProxy_InvalidURL(t *testing.T) {
 	}
 }
 
+func TestHandleMediaProxy_InvalidWidth(t *testing.T) {
+	h := setupHandler(t)
+	_ = h.DB.SetSetting("media_cache_enabled", "true")
+
+	req := httptest.NewRequest(http.MethodGet, "/media/proxy?url=https://example.com/image.jpg&w=wide", nil)
+	rr := httptest.NewRecorder()
+
+	HandleMediaProxy(h, rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
 func TestProxyImagesInHTML_RelativeURLs(t *testing.T) {
 	referer := "https://example.com/blog/post-123"
 
@@ -190,3 +204,54 @@ func findInString(s, substr string) bool {
 	}
 	return false
 }
+
+func TestHandleMediaProxy_BlockedPrivateAddress(t *testing.T) {
+	h := setupHandler(t)
+	_ = h.DB.SetSetting("media_proxy_fallback", "true")
+	_ = h.DB.SetSetting("media_proxy_private_networks", "block")
+
+	req := httptest.NewRequest(http.MethodGet, "/media/proxy?url=http://169.254.169.254/latest/meta-data", nil)
+	rr := httptest.NewRecorder()
+
+	HandleMediaProxy(h, rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d got %d", http.StatusForbidden, rr.Code)
+	}
+}
+
+func TestHandleMediaProxy_RejectsNonMedia(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	h := setupHandler(t)
+	_ = h.DB.SetSetting("media_cache_enabled", "false")
+	_ = h.DB.SetSetting("media_proxy_fallback", "true")
+	_ = h.DB.SetSetting("media_proxy_private_networks", "allow")
+
+	req := httptest.NewRequest(http.MethodGet, "/media/proxy?url="+server.URL+"/page", nil)
+	rr := httptest.NewRecorder()
+
+	HandleMediaProxy(h, rr, req)
+
+	if rr.Code != http.StatusBadGateway {
+		t.Fatalf("expected %d got %d", http.StatusBadGateway, rr.Code)
+	}
+}
+
+func TestHandleWebpageProxy_DeniedHost(t *testing.T) {
+	h := setupHandler(t)
+	_ = h.DB.SetSetting("media_proxy_denied_hosts", "example.com")
+
+	req := httptest.NewRequest(http.MethodGet, "/webpage/proxy?url=https://www.example.com/", nil)
+	rr := httptest.NewRecorder()
+
+	HandleWebpageProxy(h, rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf("expected %d got %d", http.StatusForbidden, rr.Code)
+	}
+}
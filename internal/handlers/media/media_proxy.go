@@ -9,14 +9,15 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"MrRSS/internal/cache"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/netguard"
 	"MrRSS/internal/utils"
 )
 
@@ -34,6 +35,15 @@ func validateMediaURL(urlStr string) error {
 	return nil
 }
 
+// checkRequestURL checks a URL of a request against the policy of the proxies
+func checkRequestURL(policy *netguard.Policy, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return errors.New("invalid URL format")
+	}
+	return policy.CheckURL(u)
+}
+
 // proxyImagesInHTML replaces image URLs in HTML with proxied versions
 func proxyImagesInHTML(htmlContent, referer string) string {
 	if htmlContent == "" || referer == "" {
@@ -115,6 +125,15 @@ func HandleMediaProxy(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Reject hosts and addresses the proxy must not reach
+	policy := h.MediaRequestPolicy()
+	if err := checkRequestURL(policy, mediaURL); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	client := h.MediaClient(policy, false)
+	maxSize := h.MaxMediaSize()
+
 	// Check if media cache is enabled
 	mediaCacheEnabled, _ := h.DB.GetSetting("media_cache_enabled")
 	mediaProxyFallback, _ := h.DB.GetSetting("media_proxy_fallback")
@@ -128,6 +147,19 @@ func HandleMediaProxy(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	// Get optional referer from query parameter
 	referer := r.URL.Query().Get("referer")
 
+	// Optional thumbnail width; the original is served by the direct proxy
+	width := 0
+	if widthStr := r.URL.Query().Get("w"); widthStr != "" {
+		var err error
+		width, err = strconv.Atoi(widthStr)
+		if err != nil || width <= 0 {
+			http.Error(w, "Invalid w parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var fetchErr error
+
 	// Try cache first if enabled
 	if mediaCacheEnabled == "true" {
 		// Get media cache directory
@@ -137,38 +169,52 @@ func HandleMediaProxy(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			// Continue to fallback if enabled
 		} else {
 			// Initialize media cache
-			mediaCache, err := cache.NewMediaCache(cacheDir)
+			mediaCache, err := cache.NewMediaCache(cacheDir, h.DB)
 			if err != nil {
 				log.Printf("Failed to initialize media cache: %v", err)
 				// Continue to fallback if enabled
 			} else {
+				mediaCache.SetClient(client, maxSize)
+
 				// Get media (from cache or download)
-				data, contentType, err := mediaCache.Get(mediaURL, referer)
+				var file *cache.CachedFile
+				if width > 0 {
+					file, err = mediaCache.FetchVariant(mediaURL, referer, width)
+				} else {
+					file, err = mediaCache.Fetch(mediaURL, referer)
+				}
 				if err == nil {
 					// Success! Serve from cache
-					w.Header().Set("Content-Type", contentType)
-					w.Header().Set("Content-Length", strconv.Itoa(len(data)))
-					w.Header().Set("Cache-Control", "public, max-age=31536000") // Cache for 1 year
-					w.Header().Set("X-Media-Source", "cache")
-					w.Write(data)
-					return
+					err = serveCachedFile(w, r, file)
+					if err == nil {
+						return
+					}
 				}
 				log.Printf("Cache failed for %s: %v, trying fallback", mediaURL, err)
+				fetchErr = err
 			}
 		}
 	}
 
 	// Fallback: Direct proxy if enabled
 	if mediaProxyFallback == "true" {
-		err := proxyMediaDirectly(mediaURL, referer, w)
+		err := proxyMediaDirectly(client, maxSize, mediaURL, referer, w)
 		if err == nil {
 			return // Success
 		}
 		log.Printf("Direct proxy failed for %s: %v", mediaURL, err)
+		fetchErr = err
 	}
 
 	// All methods failed
-	http.Error(w, "Failed to fetch media", http.StatusInternalServerError)
+	switch {
+	case isBlocked(fetchErr):
+		http.Error(w, "Media URL is not allowed", http.StatusForbidden)
+	case errors.Is(fetchErr, netguard.ErrResponseTooLarge), errors.Is(fetchErr, netguard.ErrContentType):
+		http.Error(w, fetchErr.Error(), http.StatusBadGateway)
+	default:
+		http.Error(w, "Failed to fetch media", http.StatusInternalServerError)
+	}
 }
 
 // HandleMediaCacheCleanup performs manual cleanup of media cache
@@ -187,7 +233,7 @@ func HandleMediaCacheCleanup(h *core.Handler, w http.ResponseWriter, r *http.Req
 	}
 
 	// Initialize media cache
-	mediaCache, err := cache.NewMediaCache(cacheDir)
+	mediaCache, err := cache.NewMediaCache(cacheDir, h.DB)
 	if err != nil {
 		log.Printf("Failed to initialize media cache: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -266,34 +312,16 @@ func HandleWebpageProxy(h *core.Handler, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Create HTTP client with proxy settings if enabled
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
-	// Check if proxy is enabled and configure client
-	proxyEnabled, _ := h.DB.GetSetting("proxy_enabled")
-	if proxyEnabled == "true" {
-		proxyType, _ := h.DB.GetSetting("proxy_type")
-		proxyHost, _ := h.DB.GetSetting("proxy_host")
-		proxyPort, _ := h.DB.GetSetting("proxy_port")
-		proxyUsername, _ := h.DB.GetSetting("proxy_username")
-		proxyPassword, _ := h.DB.GetSetting("proxy_password")
-
-		proxyURLStr := utils.BuildProxyURL(proxyType, proxyHost, proxyPort, proxyUsername, proxyPassword)
-		if proxyURLStr != "" {
-			proxyURL, err := url.Parse(proxyURLStr)
-			if err != nil {
-				log.Printf("Failed to parse proxy URL: %v", err)
-			} else {
-				transport := &http.Transport{
-					Proxy: http.ProxyURL(proxyURL),
-				}
-				client.Transport = transport
-			}
-		}
+	// Reject hosts and addresses the proxy must not reach
+	policy := h.MediaRequestPolicy()
+	if err := checkRequestURL(policy, webpageURL); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
 	}
 
+	// Create HTTP client with proxy settings if enabled
+	client := h.MediaClient(policy, true)
+
 	// Create request to the target URL
 	req, err := http.NewRequest("GET", webpageURL, nil)
 	if err != nil {
@@ -314,6 +342,10 @@ func HandleWebpageProxy(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	resp, err := client.Do(req)
 	if err != nil {
 		log.Printf("Failed to fetch webpage %s: %v", webpageURL, err)
+		if isBlocked(err) {
+			http.Error(w, "Webpage URL is not allowed", http.StatusForbidden)
+			return
+		}
 		http.Error(w, "Failed to fetch webpage", http.StatusInternalServerError)
 		return
 	}
@@ -333,9 +365,13 @@ func HandleWebpageProxy(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	}
 
 	// Read the entire response body
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := netguard.ReadAll(resp.Body, h.MaxMediaSize())
 	if err != nil {
 		log.Printf("Failed to read response body: %v", err)
+		if errors.Is(err, netguard.ErrResponseTooLarge) {
+			http.Error(w, "Webpage is too large", http.StatusBadGateway)
+			return
+		}
 		http.Error(w, "Failed to read webpage content", http.StatusInternalServerError)
 		return
 	}
@@ -392,12 +428,9 @@ func HandleWebpageProxy(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// proxyMediaDirectly proxies media directly without caching
-func proxyMediaDirectly(mediaURL, referer string, w http.ResponseWriter) error {
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
+// proxyMediaDirectly proxies media directly without caching.
+// Responses that are not media or larger than maxSize bytes are rejected.
+func proxyMediaDirectly(client *http.Client, maxSize int64, mediaURL, referer string, w http.ResponseWriter) error {
 	req, err := http.NewRequest("GET", mediaURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -428,14 +461,26 @@ func proxyMediaDirectly(mediaURL, referer string, w http.ResponseWriter) error {
 	if contentType == "" {
 		contentType = getContentTypeFromPath(mediaURL)
 	}
+	if !netguard.IsMediaType(contentType) {
+		return fmt.Errorf("%w: %s", netguard.ErrContentType, contentType)
+	}
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return netguard.ErrResponseTooLarge
+	}
 
 	// Set response headers
+	setMediaSecurityHeaders(w)
 	w.Header().Set("Content-Type", contentType)
 	w.Header().Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
 	w.Header().Set("X-Media-Source", "direct-proxy")
 
-	// Stream the response directly to avoid loading large files into memory
-	_, err = io.Copy(w, resp.Body)
+	// Stream the response directly to avoid loading large files into memory.
+	// Without a Content-Length the limit can only cut the response short.
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, maxSize)
+	}
+	_, err = io.Copy(w, body)
 	if err != nil {
 		return fmt.Errorf("failed to stream response: %w", err)
 	}
@@ -443,6 +488,28 @@ func proxyMediaDirectly(mediaURL, referer string, w http.ResponseWriter) error {
 	return nil
 }
 
+// serveCachedFile streams a cached file, supporting range requests for audio and video
+func serveCachedFile(w http.ResponseWriter, r *http.Request, file *cache.CachedFile) error {
+	f, err := os.Open(file.Path)
+	if err != nil {
+		// The file may have been evicted since it was looked up
+		return fmt.Errorf("failed to open cached file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to read cached file: %w", err)
+	}
+
+	setMediaSecurityHeaders(w)
+	w.Header().Set("Content-Type", file.ContentType)
+	w.Header().Set("Cache-Control", "public, max-age=31536000") // Cache for 1 year
+	w.Header().Set("X-Media-Source", "cache")
+	http.ServeContent(w, r, "", info.ModTime(), f)
+	return nil
+}
+
 // HandleMediaCacheInfo returns information about the media cache
 func HandleMediaCacheInfo(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 
@@ -455,7 +522,7 @@ func HandleMediaCacheInfo(h *core.Handler, w http.ResponseWriter, r *http.Reques
 	}
 
 	// Initialize media cache
-	mediaCache, err := cache.NewMediaCache(cacheDir)
+	mediaCache, err := cache.NewMediaCache(cacheDir, h.DB)
 	if err != nil {
 		log.Printf("Failed to initialize media cache: %v", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"log"
+	"net/http"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/metrics"
+)
+
+// HandleMetrics serves the metrics in the Prometheus text exposition format. The
+// refresh queue, database and media cache are measured on each scrape.
+func HandleMetrics(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if h.Fetcher != nil {
+		stats := h.Fetcher.GetTaskManager().GetStats()
+		metrics.WriteGauge(w, "mrrss_refresh_queue_depth", "Feeds waiting in the refresh queue.", float64(stats.QueueTaskCount))
+		metrics.WriteGauge(w, "mrrss_refresh_pool_tasks", "Feeds being refreshed.", float64(stats.PoolTaskCount))
+		metrics.WriteGauge(w, "mrrss_refresh_pool_capacity", "Feeds refreshed at the same time at most.", float64(stats.PoolCapacity))
+		if stats.PoolCapacity > 0 {
+			metrics.WriteGauge(w, "mrrss_refresh_pool_utilization", "Fraction of the refresh pool in use.", float64(stats.PoolTaskCount)/float64(stats.PoolCapacity))
+		}
+	}
+
+	if sizeMB, err := h.DB.GetDatabaseSizeMB(); err != nil {
+		log.Printf("Error getting database size for metrics: %v", err)
+	} else {
+		metrics.WriteGauge(w, "mrrss_database_size_bytes", "Size of the database file.", sizeMB*1024*1024)
+	}
+
+	if files, size, err := h.DB.GetMediaCacheStats(); err != nil {
+		log.Printf("Error getting media cache size for metrics: %v", err)
+	} else {
+		metrics.WriteGauge(w, "mrrss_media_cache_files", "Files in the media cache.", float64(files))
+		metrics.WriteGauge(w, "mrrss_media_cache_size_bytes", "Size of the files in the media cache.", float64(size))
+	}
+
+	metrics.Write(w)
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+)
+
+func TestHandleMetrics(t *testing.T) {
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db init failed: %v", err)
+	}
+	defer db.Close()
+	h := core.NewHandler(db, feed.NewFetcher(db, nil), nil)
+
+	rr := httptest.NewRecorder()
+	HandleMetrics(h, rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d", http.StatusOK, rr.Code)
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("unexpected content type %q", rr.Header().Get("Content-Type"))
+	}
+	for _, line := range []string{
+		"mrrss_refresh_queue_depth 0",
+		"mrrss_refresh_pool_capacity 10",
+		"mrrss_media_cache_size_bytes 0",
+		"# TYPE mrrss_database_size_bytes gauge",
+		"# TYPE mrrss_feed_fetches_total counter",
+	} {
+		if !strings.Contains(rr.Body.String(), line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, rr.Body.String())
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	HandleMetrics(h, rr, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected %d got %d", http.StatusMethodNotAllowed, rr.Code)
+	}
+}
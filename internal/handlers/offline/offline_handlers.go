@@ -0,0 +1,48 @@
+package offline
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"MrRSS/internal/handlers/core"
+)
+
+// HandleOfflineStatus returns the number and size of the articles available offline
+func HandleOfflineStatus(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := h.GetOfflineStatus()
+	if err != nil {
+		log.Printf("Error getting offline status: %v", err)
+		http.Error(w, "Failed to get offline status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// HandleOfflinePrefetch starts downloading articles for offline reading in the background.
+// Downloads also start automatically after each refresh.
+func HandleOfflinePrefetch(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if enabled, _ := h.DB.GetSetting("offline_prefetch_enabled"); enabled != "true" {
+		http.Error(w, "Offline reading is disabled", http.StatusForbidden)
+		return
+	}
+
+	go h.PrefetchOffline()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
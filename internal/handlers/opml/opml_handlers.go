@@ -75,21 +75,8 @@ func HandleOPMLImport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	// Import feeds synchronously so they appear in the sidebar immediately
 	var feedIDs []int64
 	for _, f := range feeds {
-		var feedID int64
-		var err error
-
-		// Check if feed has XPath configuration
-		if f.Type == "HTML+XPath" || f.Type == "XML+XPath" {
-			feedID, err = h.Fetcher.AddXPathSubscription(
-				f.URL, f.Category, f.Title, f.Type,
-				f.XPathItem, f.XPathItemTitle, f.XPathItemContent, f.XPathItemUri,
-				f.XPathItemAuthor, f.XPathItemTimestamp, f.XPathItemTimeFormat,
-				f.XPathItemThumbnail, f.XPathItemCategories, f.XPathItemUid,
-			)
-		} else {
-			feedID, err = h.Fetcher.ImportSubscription(f.Title, f.URL, f.Category)
-		}
-
+		// The feed configuration (XPath, script, proxy, ...) is imported as is
+		feedID, err := h.Fetcher.ImportSubscription(f)
 		if err != nil {
 			log.Printf("Error importing feed %s: %v", f.Title, err)
 			continue
@@ -126,7 +113,7 @@ func HandleOPMLExport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	log.Printf("[OPML Export] Exporting %d local feeds (excluded %d FreshRSS feeds)",
 		len(localFeeds), len(feeds)-len(localFeeds))
 
-	data, err := opml.Generate(localFeeds)
+	data, err := opml.GenerateWithOptions(localFeeds, exportOptions(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -241,21 +228,8 @@ func HandleOPMLImportDialog(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	// Import feeds synchronously so they appear in the sidebar immediately
 	var feedIDs []int64
 	for _, f := range feeds {
-		var feedID int64
-		var err error
-
-		// Check if feed has XPath configuration
-		if f.Type == "HTML+XPath" || f.Type == "XML+XPath" {
-			feedID, err = h.Fetcher.AddXPathSubscription(
-				f.URL, f.Category, f.Title, f.Type,
-				f.XPathItem, f.XPathItemTitle, f.XPathItemContent, f.XPathItemUri,
-				f.XPathItemAuthor, f.XPathItemTimestamp, f.XPathItemTimeFormat,
-				f.XPathItemThumbnail, f.XPathItemCategories, f.XPathItemUid,
-			)
-		} else {
-			feedID, err = h.Fetcher.ImportSubscription(f.Title, f.URL, f.Category)
-		}
-
+		// The feed configuration (XPath, script, proxy, ...) is imported as is
+		feedID, err := h.Fetcher.ImportSubscription(f)
 		if err != nil {
 			log.Printf("Error importing feed %s: %v", f.Title, err)
 			continue
@@ -374,7 +348,7 @@ func HandleOPMLExportDialog(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		data, err = jsonimport.Generate(localFeeds)
 	} else {
 		log.Printf("HandleOPMLExportDialog: Generating OPML format (extension: %s)", ext)
-		data, err = opml.Generate(localFeeds)
+		data, err = opml.GenerateWithOptions(localFeeds, exportOptions(r))
 	}
 
 	if err != nil {
@@ -54,10 +54,10 @@ func HandleOPMLImport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("Parsed %d feeds from OPML", len(feeds))
 
-	// Import feeds
+	// Import feeds with their configuration
 	imported := 0
 	for _, feed := range feeds {
-		_, err := h.DB.AddFeed(&feed)
+		_, err := h.Fetcher.ImportSubscription(feed)
 		if err != nil {
 			log.Printf("Error importing feed %s: %v", feed.URL, err)
 			continue
@@ -96,7 +96,7 @@ func HandleOPMLExport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate OPML content
-	data, err := opml.Generate(feeds)
+	data, err := opml.GenerateWithOptions(feeds, exportOptions(r))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
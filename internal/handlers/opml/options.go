@@ -0,0 +1,15 @@
+package opml
+
+import (
+	"net/http"
+
+	"MrRSS/internal/opml"
+)
+
+// exportOptions returns the OPML export options of a request.
+// Secrets are only exported with include_secrets=true.
+func exportOptions(r *http.Request) opml.GenerateOptions {
+	return opml.GenerateOptions{
+		IncludeSecrets: r.URL.Query().Get("include_secrets") == "true",
+	}
+}
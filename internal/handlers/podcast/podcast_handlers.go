@@ -0,0 +1,432 @@
+package podcast
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/netguard"
+)
+
+// maxChaptersSize is the largest chapters file that is fetched
+const maxChaptersSize = 1 << 20
+
+// EpisodeResponse is the podcast state of an article
+type EpisodeResponse struct {
+	Episode  *models.PodcastEpisode   `json:"episode"`
+	Playback *models.PlaybackPosition `json:"playback"`
+	Download *models.PodcastDownload  `json:"download"`
+	Queued   bool                     `json:"queued"`
+}
+
+// parseArticleID reads the article ID from the id query parameter
+func parseArticleID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+}
+
+// HandleEpisode returns the podcast metadata, playback position and download of an article
+func HandleEpisode(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := parseArticleID(r)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	var resp EpisodeResponse
+	if resp.Episode, err = h.DB.GetPodcastEpisode(articleID); err == nil {
+		if resp.Playback, err = h.DB.GetPlaybackPosition(articleID); err == nil {
+			resp.Download, err = h.DB.GetPodcastDownload(articleID)
+		}
+	}
+	if err != nil {
+		log.Printf("Error getting podcast episode: %v", err)
+		http.Error(w, "Failed to get podcast episode", http.StatusInternalServerError)
+		return
+	}
+	queue, err := h.DB.GetPodcastQueue()
+	if err != nil {
+		log.Printf("Error getting podcast queue: %v", err)
+		http.Error(w, "Failed to get podcast episode", http.StatusInternalServerError)
+		return
+	}
+	for _, id := range queue {
+		if id == articleID {
+			resp.Queued = true
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleSavePosition stores the playback position of an episode.
+// Completed episodes are removed from the Up Next queue.
+func HandleSavePosition(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID int64   `json:"article_id"`
+		Position  float64 `json:"position"`
+		Completed bool    `json:"completed"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.ArticleID <= 0 || req.Position < 0 {
+		http.Error(w, "Invalid article ID or position", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.SavePlaybackPosition(req.ArticleID, req.Position, req.Completed); err != nil {
+		log.Printf("Error saving playback position: %v", err)
+		http.Error(w, "Failed to save playback position", http.StatusInternalServerError)
+		return
+	}
+	if req.Completed {
+		if err := h.DB.RemoveFromPodcastQueue(req.ArticleID); err != nil {
+			log.Printf("Error removing episode from queue: %v", err)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleQueue returns the Up Next queue of episodes in playback order
+func HandleQueue(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ids, err := h.DB.GetPodcastQueue()
+	if err != nil {
+		log.Printf("Error getting podcast queue: %v", err)
+		http.Error(w, "Failed to get podcast queue", http.StatusInternalServerError)
+		return
+	}
+	articles, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		log.Printf("Error getting queued articles: %v", err)
+		http.Error(w, "Failed to get podcast queue", http.StatusInternalServerError)
+		return
+	}
+	articlesByID := make(map[int64]models.Article, len(articles))
+	for _, article := range articles {
+		articlesByID[article.ID] = article
+	}
+
+	queue := make([]models.QueuedEpisode, 0, len(ids))
+	for _, id := range ids {
+		article, ok := articlesByID[id]
+		if !ok {
+			continue
+		}
+		entry := models.QueuedEpisode{Article: article}
+		entry.Episode, _ = h.DB.GetPodcastEpisode(id)
+		entry.Playback, _ = h.DB.GetPlaybackPosition(id)
+		entry.Download, _ = h.DB.GetPodcastDownload(id)
+		queue = append(queue, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(queue)
+}
+
+// HandleAddToQueue adds an episode to the Up Next queue, at the start if next
+// is set and at the end otherwise
+func HandleAddToQueue(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID int64 `json:"article_id"`
+		Next      bool  `json:"next"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	article, err := h.DB.GetArticleByID(req.ArticleID)
+	if err != nil {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
+	}
+	if article.AudioURL == "" {
+		http.Error(w, "Article has no audio", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.AddToPodcastQueue(req.ArticleID, req.Next); err != nil {
+		log.Printf("Error adding episode to queue: %v", err)
+		http.Error(w, "Failed to add episode to queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleRemoveFromQueue removes an episode from the Up Next queue
+func HandleRemoveFromQueue(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID int64 `json:"article_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.RemoveFromPodcastQueue(req.ArticleID); err != nil {
+		log.Printf("Error removing episode from queue: %v", err)
+		http.Error(w, "Failed to remove episode from queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleReorderQueue moves the given episodes to the start of the Up Next queue, in order
+func HandleReorderQueue(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleIDs []int64 `json:"article_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.ReorderPodcastQueue(req.ArticleIDs); err != nil {
+		log.Printf("Error reordering podcast queue: %v", err)
+		http.Error(w, "Failed to reorder podcast queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleDownloads returns the downloaded and queued episodes with the storage they use
+func HandleDownloads(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	downloads, err := h.DB.GetPodcastDownloads()
+	if err != nil {
+		log.Printf("Error getting podcast downloads: %v", err)
+		http.Error(w, "Failed to get podcast downloads", http.StatusInternalServerError)
+		return
+	}
+	size, err := h.DB.GetPodcastDownloadsSize()
+	if err != nil {
+		log.Printf("Error getting podcast downloads size: %v", err)
+		http.Error(w, "Failed to get podcast downloads", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"downloads":   downloads,
+		"size_mb":     float64(size) / (1024 * 1024),
+		"max_size_mb": h.Podcasts.MaxStorage() >> 20,
+	})
+}
+
+// HandleDownload queues an episode for download. Failed downloads are retried
+// and resume where they stopped.
+func HandleDownload(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID int64 `json:"article_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	article, err := h.DB.GetArticleByID(req.ArticleID)
+	if err != nil {
+		http.Error(w, "Article not found", http.StatusNotFound)
+		return
+	}
+	if article.AudioURL == "" {
+		http.Error(w, "Article has no audio", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Podcasts.Enqueue(article.ID, article.AudioURL); err != nil {
+		log.Printf("Error queueing podcast download: %v", err)
+		http.Error(w, "Failed to queue download", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleDeleteDownload stops the download of an episode and removes its file
+func HandleDeleteDownload(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		ArticleID int64 `json:"article_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Podcasts.Delete(req.ArticleID); err != nil {
+		log.Printf("Error deleting podcast download: %v", err)
+		http.Error(w, "Failed to delete download", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
+// HandleDownloadedFile serves a downloaded episode, with support for range requests
+func HandleDownloadedFile(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := parseArticleID(r)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	path, err := h.Podcasts.FilePath(articleID)
+	if err != nil {
+		log.Printf("Error getting podcast file: %v", err)
+		http.Error(w, "Failed to get download", http.StatusInternalServerError)
+		return
+	}
+	if path == "" {
+		http.Error(w, "Episode not downloaded", http.StatusNotFound)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, "Episode not downloaded", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, "Failed to read download", http.StatusInternalServerError)
+		return
+	}
+
+	if episode, _ := h.DB.GetPodcastEpisode(articleID); episode != nil && netguard.IsMediaType(episode.MimeType) {
+		w.Header().Set("Content-Type", episode.MimeType)
+	}
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, filepath.Base(path), info.ModTime(), f)
+}
+
+// HandleChapters returns the podcast:chapters JSON file of an episode, fetched
+// through the server so that the client is not blocked by CORS
+func HandleChapters(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := parseArticleID(r)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	episode, err := h.DB.GetPodcastEpisode(articleID)
+	if err != nil {
+		log.Printf("Error getting podcast episode: %v", err)
+		http.Error(w, "Failed to get chapters", http.StatusInternalServerError)
+		return
+	}
+	if episode == nil || episode.ChaptersURL == "" {
+		http.Error(w, "Episode has no chapters", http.StatusNotFound)
+		return
+	}
+
+	client := h.MediaClient(h.MediaRequestPolicy(), false)
+	resp, err := client.Get(episode.ChaptersURL)
+	if err != nil {
+		if errors.Is(err, netguard.ErrBlockedAddress) || errors.Is(err, netguard.ErrBlockedHost) {
+			http.Error(w, "Chapters URL is not allowed", http.StatusForbidden)
+			return
+		}
+		http.Error(w, "Failed to fetch chapters", http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		http.Error(w, "Failed to fetch chapters", http.StatusBadGateway)
+		return
+	}
+
+	data, err := netguard.ReadAll(resp.Body, maxChaptersSize)
+	if err != nil || !json.Valid(data) {
+		http.Error(w, "Invalid chapters file", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
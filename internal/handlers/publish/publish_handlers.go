@@ -0,0 +1,249 @@
+package publish
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/publish"
+	"MrRSS/internal/utils"
+)
+
+// FeedPath is the API path of the published feeds, which readers fetch with a token
+const FeedPath = "/api/v1/published/feed"
+
+// PublishedFeed is a published feed with the URLs of its formats
+type PublishedFeed struct {
+	models.PublishedFeed
+	URLs map[string]string `json:"urls"` // Feed URL of each format, "rss", "atom" and "json"
+}
+
+// PublishedFeedsResponse lists the published feeds
+type PublishedFeedsResponse struct {
+	Enabled bool            `json:"enabled"` // Feeds are only served in server mode
+	Feeds   []PublishedFeed `json:"feeds"`
+}
+
+// feedURL returns the URL of a feed in a format, on the host the request was made to
+func feedURL(r *http.Request, token, format string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	query := url.Values{"token": {token}, "format": {format}}
+	return scheme + "://" + r.Host + FeedPath + "?" + query.Encode()
+}
+
+func withURLs(r *http.Request, f models.PublishedFeed) PublishedFeed {
+	urls := make(map[string]string)
+	for _, format := range []string{publish.FormatRSS, publish.FormatAtom, publish.FormatJSON} {
+		urls[format] = feedURL(r, f.Token, format)
+	}
+	return PublishedFeed{PublishedFeed: f, URLs: urls}
+}
+
+func writeFeed(w http.ResponseWriter, r *http.Request, f models.PublishedFeed) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(withURLs(r, f))
+}
+
+// HandleList lists the published feeds with their URLs
+func HandleList(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	feeds, err := h.DB.GetPublishedFeeds()
+	if err != nil {
+		log.Printf("Error getting published feeds: %v", err)
+		http.Error(w, "Failed to get published feeds", http.StatusInternalServerError)
+		return
+	}
+	resp := PublishedFeedsResponse{Enabled: utils.IsServerMode(), Feeds: []PublishedFeed{}}
+	for _, f := range feeds {
+		resp.Feeds = append(resp.Feeds, withURLs(r, f))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// HandleCreate publishes a new feed of favorites, read later articles, a category or a filter
+func HandleCreate(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var f models.PublishedFeed
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := publish.Validate(&f); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	token, err := publish.NewToken()
+	if err != nil {
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+	f.Token = token
+
+	if _, err := h.DB.CreatePublishedFeed(&f); err != nil {
+		log.Printf("Error creating published feed: %v", err)
+		http.Error(w, "Failed to create published feed", http.StatusInternalServerError)
+		return
+	}
+	created, err := h.DB.GetPublishedFeed(f.ID)
+	if err != nil || created == nil {
+		http.Error(w, "Failed to get published feed", http.StatusInternalServerError)
+		return
+	}
+	writeFeed(w, r, *created)
+}
+
+// HandleUpdate changes the title, source or limit of a published feed. Its URLs stay the same.
+func HandleUpdate(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var f models.PublishedFeed
+	if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	existing, err := h.DB.GetPublishedFeed(f.ID)
+	if err != nil {
+		http.Error(w, "Failed to get published feed", http.StatusInternalServerError)
+		return
+	}
+	if existing == nil {
+		http.Error(w, "Published feed not found", http.StatusNotFound)
+		return
+	}
+	if err := publish.Validate(&f); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.DB.UpdatePublishedFeed(f); err != nil {
+		log.Printf("Error updating published feed %d: %v", f.ID, err)
+		http.Error(w, "Failed to update published feed", http.StatusInternalServerError)
+		return
+	}
+	f.Token = existing.Token
+	f.CreatedAt = existing.CreatedAt
+	writeFeed(w, r, f)
+}
+
+// HandleDelete unpublishes a feed
+func HandleDelete(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid published feed ID", http.StatusBadRequest)
+		return
+	}
+	if err := h.DB.DeletePublishedFeed(id); err != nil {
+		log.Printf("Error deleting published feed %d: %v", id, err)
+		http.Error(w, "Failed to delete published feed", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleRegenerateToken gives a published feed a new token, so that its previous URLs stop working
+func HandleRegenerateToken(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid published feed ID", http.StatusBadRequest)
+		return
+	}
+	f, err := h.DB.GetPublishedFeed(id)
+	if err != nil {
+		http.Error(w, "Failed to get published feed", http.StatusInternalServerError)
+		return
+	}
+	if f == nil {
+		http.Error(w, "Published feed not found", http.StatusNotFound)
+		return
+	}
+	token, err := publish.NewToken()
+	if err != nil {
+		http.Error(w, "Failed to create token", http.StatusInternalServerError)
+		return
+	}
+	if err := h.DB.SetPublishedFeedToken(id, token); err != nil {
+		log.Printf("Error setting token of published feed %d: %v", id, err)
+		http.Error(w, "Failed to regenerate token", http.StatusInternalServerError)
+		return
+	}
+	f.Token = token
+	writeFeed(w, r, *f)
+}
+
+// HandleFeed serves a published feed to the holders of its token, as RSS 2.0,
+// Atom or JSON Feed. Feeds are only served in server mode.
+func HandleFeed(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !utils.IsServerMode() {
+		http.Error(w, "Published feeds are only served in server mode", http.StatusNotFound)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = publish.FormatRSS
+	}
+	contentType := publish.ContentType(format)
+	if contentType == "" {
+		http.Error(w, "Unknown format", http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.DB.GetPublishedFeedByToken(r.URL.Query().Get("token"))
+	if err != nil {
+		log.Printf("Error getting published feed: %v", err)
+		http.Error(w, "Failed to get feed", http.StatusInternalServerError)
+		return
+	}
+	if f == nil {
+		// The same response for a missing and a wrong token
+		http.Error(w, "Feed not found", http.StatusNotFound)
+		return
+	}
+
+	items, err := publish.Items(h.DB, *f)
+	if err != nil {
+		log.Printf("Error getting articles of published feed %d: %v", f.ID, err)
+		http.Error(w, "Failed to get articles", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if err := publish.Render(w, *f, items, format, feedURL(r, f.Token, format)); err != nil {
+		log.Printf("Error rendering published feed %d: %v", f.ID, err)
+	}
+}
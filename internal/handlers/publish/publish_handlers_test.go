@@ -0,0 +1,117 @@
+package publish
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+func setupHandler(t *testing.T) *core.Handler {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db init failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Blog", URL: "http://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	art := &models.Article{FeedID: feedID, Title: "Pick of the week", URL: "http://example.com/1", IsFavorite: true, PublishedAt: time.Now()}
+	if err := db.SaveArticle(art); err != nil {
+		t.Fatalf("SaveArticle failed: %v", err)
+	}
+	return core.NewHandler(db, nil, nil)
+}
+
+func TestPublishedFeedLifecycle(t *testing.T) {
+	h := setupHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/published/create", strings.NewReader(`{"title":"Team picks","source":"favorites"}`))
+	rr := httptest.NewRecorder()
+	HandleCreate(h, rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("create: expected %d got %d; body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var created PublishedFeed
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ID == 0 || created.Token == "" || created.Limit != 50 {
+		t.Fatalf("unexpected feed: %+v", created)
+	}
+	feedURL, err := url.Parse(created.URLs["atom"])
+	if err != nil || feedURL.Path != FeedPath || feedURL.Query().Get("token") != created.Token {
+		t.Fatalf("unexpected atom URL %q", created.URLs["atom"])
+	}
+
+	// Feeds are only served in server mode
+	rr = httptest.NewRecorder()
+	HandleFeed(h, rr, httptest.NewRequest(http.MethodGet, feedURL.RequestURI(), nil))
+	if rr.Code != http.StatusNotFound {
+		t.Fatalf("feed outside server mode: expected %d got %d", http.StatusNotFound, rr.Code)
+	}
+
+	utils.SetServerMode(true)
+	defer utils.SetServerMode(false)
+
+	rr = httptest.NewRecorder()
+	HandleFeed(h, rr, httptest.NewRequest(http.MethodGet, feedURL.RequestURI(), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("feed: expected %d got %d; body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Type"), "application/atom+xml") || !strings.Contains(rr.Body.String(), "Pick of the week") {
+		t.Errorf("unexpected feed %s: %s", rr.Header().Get("Content-Type"), rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	HandleFeed(h, rr, httptest.NewRequest(http.MethodGet, FeedPath+"?token=wrong", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("wrong token: expected %d got %d", http.StatusNotFound, rr.Code)
+	}
+
+	// A new token revokes the previous URLs
+	rr = httptest.NewRecorder()
+	HandleRegenerateToken(h, rr, httptest.NewRequest(http.MethodPost, "/api/published/regenerate-token?id=1", nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("regenerate: expected %d got %d", http.StatusOK, rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	HandleFeed(h, rr, httptest.NewRequest(http.MethodGet, feedURL.RequestURI(), nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("revoked token: expected %d got %d", http.StatusNotFound, rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	HandleList(h, rr, httptest.NewRequest(http.MethodGet, "/api/published", nil))
+	var list PublishedFeedsResponse
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil || !list.Enabled || len(list.Feeds) != 1 || list.Feeds[0].Token == created.Token {
+		t.Errorf("unexpected list: %+v, %v", list, err)
+	}
+}
+
+func TestHandleCreate_Invalid(t *testing.T) {
+	h := setupHandler(t)
+
+	for _, body := range []string{`not json`, `{"title":"x","source":"everything"}`, `{"title":"x","source":"filter","conditions":[]}`} {
+		rr := httptest.NewRecorder()
+		HandleCreate(h, rr, httptest.NewRequest(http.MethodPost, "/api/published/create", bytes.NewReader([]byte(body))))
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected %d got %d", body, http.StatusBadRequest, rr.Code)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package readerimport
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/readerimport"
+)
+
+// maxImportUploadSize limits the size of an uploaded export file
+const maxImportUploadSize = 512 << 20
+
+// HandleReaderImport imports the starred and read later items of another reader.
+// The export is uploaded as the "file" field of a multipart form. The optional
+// "format" field selects the format; it is detected from the content otherwise.
+func HandleReaderImport(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportUploadSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "No file provided", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	format := readerimport.Format(r.FormValue("format"))
+	if format == "" {
+		if format, err = readerimport.Detect(content); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	items, err := readerimport.Parse(format, content)
+	if err != nil {
+		log.Printf("Error parsing %s export %s: %v", format, header.Filename, err)
+		if errors.Is(err, readerimport.ErrUnknownFormat) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to parse export file", http.StatusBadRequest)
+		return
+	}
+
+	result, err := readerimport.Import(h.DB, items)
+	if err != nil {
+		log.Printf("Error importing %s export: %v", format, err)
+		http.Error(w, "Failed to import items", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("Imported %s export: %d created, %d updated, %d failed", format, result.Created, result.Updated, result.Failed)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"format":  format,
+		"total":   result.Total,
+		"created": result.Created,
+		"updated": result.Updated,
+		"failed":  result.Failed,
+	})
+}
@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ErrorResponse is the body of every error returned by the versioned API
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an API error
+type ErrorDetail struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code"` // Status text in snake case, e.g. "not_found"
+	Message string `json:"message"`
+}
+
+// WriteError writes an error envelope with the given status
+func WriteError(w http.ResponseWriter, status int, message string) {
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: ErrorDetail{
+		Status:  status,
+		Code:    errorCode(status),
+		Message: message,
+	}})
+}
+
+// errorCode returns the error code of a status, e.g. "method_not_allowed"
+func errorCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	text = strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text)
+	return strings.ToLower(text)
+}
+
+// envelopeWriter passes successful responses through and buffers error responses,
+// which handlers write as plain text or ad-hoc JSON, to rewrite them as envelopes
+type envelopeWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (ew *envelopeWriter) WriteHeader(status int) {
+	if ew.status != 0 {
+		return
+	}
+	ew.status = status
+	if status < http.StatusBadRequest {
+		ew.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (ew *envelopeWriter) Write(b []byte) (int, error) {
+	if ew.status == 0 {
+		ew.WriteHeader(http.StatusOK)
+	}
+	if ew.status >= http.StatusBadRequest {
+		return ew.body.Write(b)
+	}
+	return ew.ResponseWriter.Write(b)
+}
+
+// Flush lets streaming handlers flush successful responses
+func (ew *envelopeWriter) Flush() {
+	if ew.status >= http.StatusBadRequest {
+		return
+	}
+	if f, ok := ew.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer
+func (ew *envelopeWriter) Unwrap() http.ResponseWriter {
+	return ew.ResponseWriter
+}
+
+// finish writes the buffered error, if any, as an envelope
+func (ew *envelopeWriter) finish() {
+	if ew.status < http.StatusBadRequest {
+		return
+	}
+	WriteError(ew.ResponseWriter, ew.status, errorMessage(ew.body.Bytes()))
+}
+
+// errorMessage extracts the message of an error body: the "error" or "message"
+// field of a JSON object, or the text itself
+func errorMessage(body []byte) string {
+	body = bytes.TrimSpace(body)
+	var fields map[string]interface{}
+	if json.Unmarshal(body, &fields) == nil {
+		for _, key := range []string{"error", "message"} {
+			if message, ok := fields[key].(string); ok {
+				return message
+			}
+		}
+	}
+	return string(body)
+}
@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"MrRSS/internal/version"
+)
+
+// OpenAPISpec generates the OpenAPI 3 document of the versioned API from the routes
+func OpenAPISpec(routes []Route) []byte {
+	errorResponse := map[string]interface{}{"$ref": "#/components/responses/Error"}
+
+	paths := make(map[string]interface{})
+	tags := make(map[string]bool)
+	for _, rt := range routes {
+		operations := make(map[string]interface{})
+		for _, method := range rt.Methods {
+			operations[strings.ToLower(method)] = map[string]interface{}{
+				"operationId": operationID(method, rt.Path),
+				"summary":     rt.Summary,
+				"tags":        []string{rt.Tag},
+				"responses": map[string]interface{}{
+					"200":     map[string]interface{}{"description": "Success"},
+					"default": errorResponse,
+				},
+			}
+		}
+		paths[strings.TrimPrefix(rt.V1Path(), V1Prefix)] = operations
+		tags[rt.Tag] = true
+	}
+
+	tagList := make([]map[string]string, 0, len(tags))
+	for tag := range tags {
+		tagList = append(tagList, map[string]string{"name": tag})
+	}
+	sort.Slice(tagList, func(i, j int) bool { return tagList[i]["name"] < tagList[j]["name"] })
+
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "MrRSS API",
+			"version":     version.Version,
+			"description": "Errors are returned as {\"error\": {\"status\", \"code\", \"message\"}} with a matching HTTP status.",
+		},
+		"servers": []map[string]string{{"url": V1Prefix}},
+		"tags":    tagList,
+		"paths":   paths,
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"type":     "object",
+					"required": []string{"error"},
+					"properties": map[string]interface{}{
+						"error": map[string]interface{}{
+							"type":     "object",
+							"required": []string{"status", "code", "message"},
+							"properties": map[string]interface{}{
+								"status":  map[string]string{"type": "integer"},
+								"code":    map[string]string{"type": "string"},
+								"message": map[string]string{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"Error": map[string]interface{}{
+					"description": "Error",
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]string{"$ref": "#/components/schemas/Error"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	data, _ := json.MarshalIndent(spec, "", "  ")
+	return data
+}
+
+// operationID derives an operation ID from a method and path,
+// e.g. "postFeedsDiscoverAllStart" for POST /api/feeds/discover-all/start
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	words := strings.FieldsFunc(strings.TrimPrefix(path, "/api"), func(r rune) bool {
+		return r == '/' || r == '-' || r == '_'
+	})
+	for _, word := range words {
+		id += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return id
+}
@@ -0,0 +1,84 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"MrRSS/internal/handlers/core"
+)
+
+// V1Prefix is the prefix of the versioned API
+const V1Prefix = "/api/v1"
+
+// HandlerFunc is the signature of the API handlers
+type HandlerFunc func(h *core.Handler, w http.ResponseWriter, r *http.Request)
+
+// Route is an API endpoint
+type Route struct {
+	Path    string   // Unversioned path, e.g. "/api/feeds/add"
+	Methods []string // Methods accepted, enforced under /api/v1
+	Tag     string   // Group of the route in the OpenAPI document
+	Summary string
+	Handler HandlerFunc
+}
+
+// V1Path returns the path of the route in the versioned API
+func (rt Route) V1Path() string {
+	return V1Prefix + strings.TrimPrefix(rt.Path, "/api")
+}
+
+// allows reports whether the route accepts a method
+func (rt Route) allows(method string) bool {
+	for _, m := range rt.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Register adds the API routes to mux, both at their unversioned path, used by the
+// bundled frontend, and under /api/v1. Versioned routes reject methods they do not
+// accept and report errors as JSON envelopes. The OpenAPI document of the versioned
+// API is served at /api/v1/openapi.json.
+func Register(mux *http.ServeMux, h *core.Handler) {
+	routes := Routes()
+	for _, rt := range routes {
+		handler := rt.Handler
+		mux.HandleFunc(rt.Path, func(w http.ResponseWriter, r *http.Request) { handler(h, w, r) })
+		mux.Handle(rt.V1Path(), v1Handler(h, rt))
+	}
+
+	spec := OpenAPISpec(routes)
+	mux.HandleFunc(V1Prefix+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	})
+	mux.HandleFunc(V1Prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		WriteError(w, http.StatusNotFound, "Unknown API endpoint: "+r.URL.Path)
+	})
+}
+
+// v1Handler serves a route under /api/v1
+func v1Handler(h *core.Handler, rt Route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !rt.allows(r.Method) {
+			methodNotAllowed(w, rt.Methods...)
+			return
+		}
+
+		ew := &envelopeWriter{ResponseWriter: w}
+		rt.Handler(h, ew, r)
+		ew.finish()
+	})
+}
+
+// methodNotAllowed reports a 405 error listing the allowed methods
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	WriteError(w, http.StatusMethodNotAllowed, "Method not allowed")
+}
@@ -0,0 +1,211 @@
+// Package routes holds the table of API routes shared by the desktop and server
+// builds, and serves it under /api and the versioned /api/v1 namespace.
+package routes
+
+import (
+	"net/http"
+
+	aihandlers "MrRSS/internal/handlers/ai"
+	article "MrRSS/internal/handlers/article"
+	backup "MrRSS/internal/handlers/backup"
+	browser "MrRSS/internal/handlers/browser"
+	chat "MrRSS/internal/handlers/chat"
+	"MrRSS/internal/handlers/core"
+	customcss "MrRSS/internal/handlers/custom_css"
+	discovery "MrRSS/internal/handlers/discovery"
+	feedhandlers "MrRSS/internal/handlers/feed"
+	freshrssHandler "MrRSS/internal/handlers/freshrss"
+	media "MrRSS/internal/handlers/media"
+	networkhandlers "MrRSS/internal/handlers/network"
+	offline "MrRSS/internal/handlers/offline"
+	opml "MrRSS/internal/handlers/opml"
+	podcast "MrRSS/internal/handlers/podcast"
+	publish "MrRSS/internal/handlers/publish"
+	readerimport "MrRSS/internal/handlers/readerimport"
+	rules "MrRSS/internal/handlers/rules"
+	script "MrRSS/internal/handlers/script"
+	settings "MrRSS/internal/handlers/settings"
+	summary "MrRSS/internal/handlers/summary"
+	translationhandlers "MrRSS/internal/handlers/translation"
+	update "MrRSS/internal/handlers/update"
+	websub "MrRSS/internal/handlers/websub"
+	window "MrRSS/internal/handlers/window"
+)
+
+const (
+	get   = http.MethodGet
+	post  = http.MethodPost
+	put   = http.MethodPut
+	patch = http.MethodPatch
+	del   = http.MethodDelete
+	head  = http.MethodHead
+)
+
+// Routes returns the API routes, in the order they are documented
+func Routes() []Route {
+	return []Route{
+		// Feeds
+		{"/api/feeds", []string{get}, "feeds", "List all feeds", feedhandlers.HandleFeeds},
+		{"/api/feeds/add", []string{post}, "feeds", "Subscribe to a feed and fetch its articles", feedhandlers.HandleAddFeed},
+		{"/api/feeds/delete", []string{post}, "feeds", "Delete a feed subscription", feedhandlers.HandleDeleteFeed},
+		{"/api/feeds/update", []string{post}, "feeds", "Update the properties of a feed", feedhandlers.HandleUpdateFeed},
+		{"/api/feeds/detail", []string{get}, "feeds", "Get a feed and the prediction of its next refresh", feedhandlers.HandleFeedDetail},
+		{"/api/feeds/refresh", []string{post}, "feeds", "Refresh a single feed", feedhandlers.HandleRefreshFeed},
+		{"/api/feeds/reorder", []string{post}, "feeds", "Move a feed within or across categories", feedhandlers.HandleReorderFeed},
+		{"/api/feeds/test-imap", []string{post}, "feeds", "Test IMAP connection settings", feedhandlers.HandleTestIMAPConnection},
+		{"/api/feeds/health", []string{get}, "feeds", "Get the health of the feeds, optionally only dead or stale ones", feedhandlers.HandleFeedHealth},
+		{"/api/feeds/fetch-log", []string{get}, "feeds", "List the latest fetch attempts of a feed", feedhandlers.HandleFeedFetchLog},
+		{"/api/feeds/pause", []string{post}, "feeds", "Pause or resume the scheduled refreshes of feeds", feedhandlers.HandlePauseFeeds},
+		{"/api/feeds/unsubscribe", []string{post}, "feeds", "Delete several feed subscriptions", feedhandlers.HandleUnsubscribeFeeds},
+		{"/api/feeds/changes", []string{get}, "feeds", "List automatic feed changes", feedhandlers.HandleFeedChanges},
+		{"/api/feeds/changes/undo", []string{post}, "feeds", "Undo an automatic feed change", feedhandlers.HandleUndoFeedChange},
+
+		// Discovery
+		{"/api/feeds/discover", []string{post}, "discovery", "Discover blogs from the friend links of a feed", discovery.HandleDiscoverBlogs},
+		{"/api/feeds/discover-all", []string{post}, "discovery", "Discover blogs from all feeds not scanned yet", discovery.HandleDiscoverAllFeeds},
+		{"/api/feeds/discover/start", []string{post}, "discovery", "Start discovering blogs from a feed in the background", discovery.HandleStartSingleDiscovery},
+		{"/api/feeds/discover/progress", []string{get}, "discovery", "Get the progress of single feed discovery", discovery.HandleGetSingleDiscoveryProgress},
+		{"/api/feeds/discover/clear", []string{post}, "discovery", "Clear the single feed discovery state", discovery.HandleClearSingleDiscovery},
+		{"/api/feeds/discover-all/start", []string{post}, "discovery", "Start batch discovery in the background", discovery.HandleStartBatchDiscovery},
+		{"/api/feeds/discover-all/progress", []string{get}, "discovery", "Get the progress of batch discovery", discovery.HandleGetBatchDiscoveryProgress},
+		{"/api/feeds/discover-all/clear", []string{post}, "discovery", "Clear the batch discovery state", discovery.HandleClearBatchDiscovery},
+		{"/api/feeds/recommendations/start", []string{post}, "discovery", "Start building feed recommendations in the background", discovery.HandleStartRecommendations},
+		{"/api/feeds/recommendations/progress", []string{get}, "discovery", "Get the progress of feed recommendations", discovery.HandleGetRecommendationsProgress},
+		{"/api/feeds/recommendations/clear", []string{post}, "discovery", "Clear the feed recommendations", discovery.HandleClearRecommendations},
+
+		// Articles
+		{"/api/articles", []string{get}, "articles", "List articles with filtering and pagination", article.HandleArticles},
+		{"/api/articles/images", []string{get}, "articles", "List the articles of image mode feeds", article.HandleImageGalleryArticles},
+		{"/api/articles/filter", []string{post}, "articles", "List articles matching advanced conditions", article.HandleFilteredArticles},
+		{"/api/articles/read", []string{post}, "articles", "Mark an article as read or unread", article.HandleMarkReadWithImmediateSync},
+		{"/api/articles/favorite", []string{post}, "articles", "Toggle the favorite status of an article", article.HandleToggleFavoriteWithImmediateSync},
+		{"/api/articles/toggle-hide", []string{post}, "articles", "Toggle the hidden status of an article", article.HandleToggleHideArticle},
+		{"/api/articles/toggle-read-later", []string{post}, "articles", "Toggle the read later status of an article", article.HandleToggleReadLater},
+		{"/api/articles/content", []string{get}, "articles", "Get the content of an article", article.HandleGetArticleContent},
+		{"/api/articles/video", []string{get}, "articles", "Get the video metadata of an article", article.HandleGetVideoInfo},
+		{"/api/articles/fetch-full", []string{post}, "articles", "Fetch the full content of an article from its page", article.HandleFetchFullArticle},
+		{"/api/articles/related", []string{get}, "articles", "List the articles most similar to an article", article.HandleRelatedArticles},
+		{"/api/articles/alternates", []string{get}, "articles", "List the other articles covering the same story", article.HandleStoryAlternates},
+		{"/api/articles/semantic-search", []string{get}, "articles", "Search articles by meaning", article.HandleSemanticSearch},
+		{"/api/articles/unread-counts", []string{get}, "articles", "Get the unread counts of all feeds", article.HandleGetUnreadCounts},
+		{"/api/articles/mark-all-read", []string{post}, "articles", "Mark all articles as read", article.HandleMarkAllAsRead},
+		{"/api/articles/clear-read-later", []string{post}, "articles", "Empty the read later list", article.HandleClearReadLater},
+		{"/api/articles/cleanup", []string{post}, "articles", "Clean up old articles", article.HandleCleanupArticles},
+		{"/api/articles/cleanup-content", []string{post}, "articles", "Clear the article content cache", article.HandleCleanupArticleContent},
+		{"/api/articles/content-cache-info", []string{get}, "articles", "Get the size of the article content cache", article.HandleGetArticleContentCacheInfo},
+		{"/api/articles/export/obsidian", []string{post}, "articles", "Export an article to Obsidian", article.HandleExportToObsidian},
+		{"/api/refresh", []string{post}, "articles", "Refresh all feeds", article.HandleRefresh},
+		{"/api/progress", []string{get}, "articles", "Get the progress of feed refreshes", article.HandleProgress},
+		{"/api/progress/task-details", []string{get}, "articles", "Get the feeds being refreshed and queued", article.HandleTaskDetails},
+
+		// Translation and summaries
+		{"/api/articles/translate", []string{post}, "translation", "Translate the title of an article", translationhandlers.HandleTranslateArticle},
+		{"/api/articles/translate-text", []string{post}, "translation", "Translate text to the target language", translationhandlers.HandleTranslateText},
+		{"/api/articles/clear-translations", []string{post}, "translation", "Clear all translated titles", translationhandlers.HandleClearTranslations},
+		{"/api/articles/summarize", []string{post}, "summary", "Summarize an article", summary.HandleSummarizeArticle},
+		{"/api/articles/clear-summaries", []string{del}, "summary", "Clear all cached summaries", summary.HandleClearSummaries},
+		{"/api/articles/highlights", []string{get}, "summary", "Get the key sentences of an article", summary.HandleArticleHighlights},
+		{"/api/articles/tags", []string{get}, "summary", "Get the tags of an article", summary.HandleArticleTags},
+		{"/api/articles/by-tag", []string{get}, "summary", "List the most recent articles with a tag", summary.HandleArticlesByTag},
+
+		// AI
+		{"/api/ai-usage", []string{get}, "ai", "Get AI usage statistics", translationhandlers.HandleGetAIUsage},
+		{"/api/ai-usage/reset", []string{post}, "ai", "Reset the AI usage counter", translationhandlers.HandleResetAIUsage},
+		{"/api/ai-usage/history", []string{get}, "ai", "Get daily AI usage", translationhandlers.HandleGetAIUsageHistory},
+		{"/api/ai/test", []string{post}, "ai", "Test the AI configuration", aihandlers.HandleTestAIConfig},
+		{"/api/ai/test/info", []string{get}, "ai", "Get the result of the last AI configuration test", aihandlers.HandleGetAITestInfo},
+
+		// Chat
+		{"/api/ai-chat", []string{post}, "chat", "Chat with the AI about an article", chat.HandleAIChat},
+		{"/api/ai/chat/sessions", []string{get}, "chat", "List the chat sessions of an article", chat.HandleListSessions},
+		{"/api/ai/chat/sessions/delete-all", []string{del}, "chat", "Delete all chat sessions", chat.HandleDeleteAllSessions},
+		{"/api/ai/chat/session/create", []string{post}, "chat", "Create a chat session", chat.HandleCreateSession},
+		{"/api/ai/chat/session", []string{get, put, patch, del}, "chat", "Get, rename or delete a chat session", handleChatSession},
+		{"/api/ai/chat/messages", []string{get}, "chat", "List the messages of a chat session", chat.HandleListMessages},
+		{"/api/ai/chat/message/delete", []string{del}, "chat", "Delete a chat message", chat.HandleDeleteMessage},
+
+		// Podcasts
+		{"/api/podcasts/episode", []string{get}, "podcasts", "Get the episode details, playback position and download of an article", podcast.HandleEpisode},
+		{"/api/podcasts/position", []string{post}, "podcasts", "Save the playback position of an episode", podcast.HandleSavePosition},
+		{"/api/podcasts/chapters", []string{get}, "podcasts", "Get the chapters of an episode", podcast.HandleChapters},
+		{"/api/podcasts/queue", []string{get}, "podcasts", "List the Up Next queue", podcast.HandleQueue},
+		{"/api/podcasts/queue/add", []string{post}, "podcasts", "Add an episode to the Up Next queue", podcast.HandleAddToQueue},
+		{"/api/podcasts/queue/remove", []string{post}, "podcasts", "Remove an episode from the Up Next queue", podcast.HandleRemoveFromQueue},
+		{"/api/podcasts/queue/reorder", []string{post}, "podcasts", "Reorder the Up Next queue", podcast.HandleReorderQueue},
+		{"/api/podcasts/downloads", []string{get}, "podcasts", "List downloaded and queued episodes", podcast.HandleDownloads},
+		{"/api/podcasts/download", []string{post}, "podcasts", "Download an episode", podcast.HandleDownload},
+		{"/api/podcasts/download/delete", []string{post}, "podcasts", "Delete a downloaded episode", podcast.HandleDeleteDownload},
+		{"/api/podcasts/file", []string{get, head}, "podcasts", "Stream a downloaded episode", podcast.HandleDownloadedFile},
+
+		// Media and offline reading
+		{"/api/media/proxy", []string{get}, "media", "Serve cached media, downloading it if needed", media.HandleMediaProxy},
+		{"/api/media/cleanup", []string{post}, "media", "Clean up the media cache", media.HandleMediaCacheCleanup},
+		{"/api/media/info", []string{get}, "media", "Get the size of the media cache", media.HandleMediaCacheInfo},
+		{"/api/webpage/proxy", []string{get}, "media", "Proxy a webpage for display in the reader", media.HandleWebpageProxy},
+		{"/api/offline/status", []string{get}, "offline", "Get the articles available offline", offline.HandleOfflineStatus},
+		{"/api/offline/prefetch", []string{post}, "offline", "Download articles for offline reading", offline.HandleOfflinePrefetch},
+
+		// Settings
+		{"/api/settings", []string{get, post}, "settings", "Get or update the settings", settings.HandleSettings},
+		{"/api/rules/apply", []string{post}, "settings", "Apply a rule to matching articles", rules.HandleApplyRule},
+		{"/api/rules/languages", []string{get}, "settings", "List the languages article language conditions can match", rules.HandleArticleLanguages},
+		{"/api/scripts/dir", []string{get}, "settings", "Get the scripts directory", script.HandleGetScriptsDir},
+		{"/api/scripts/open", []string{post}, "settings", "Open the scripts directory", script.HandleOpenScriptsDir},
+		{"/api/scripts/list", []string{get}, "settings", "List the available scripts", script.HandleListScripts},
+		{"/api/custom-css", []string{get}, "settings", "Get the custom CSS", customcss.HandleGetCSS},
+		{"/api/custom-css/upload", []string{post}, "settings", "Upload custom CSS", customcss.HandleUploadCSS},
+		{"/api/custom-css/upload-dialog", []string{post}, "settings", "Choose a custom CSS file with a dialog", customcss.HandleUploadCSSDialog},
+		{"/api/custom-css/delete", []string{post, del}, "settings", "Delete the custom CSS", customcss.HandleDeleteCSS},
+		{"/api/network/detect", []string{post}, "settings", "Detect the network speed", networkhandlers.HandleDetectNetwork},
+		{"/api/network/info", []string{get}, "settings", "Get the detected network speed", networkhandlers.HandleGetNetworkInfo},
+
+		// Import and export
+		{"/api/opml/import", []string{post}, "import", "Import subscriptions from OPML", opml.HandleOPMLImport},
+		{"/api/opml/export", []string{get, post}, "import", "Export subscriptions as OPML", opml.HandleOPMLExport},
+		{"/api/opml/import-dialog", []string{post}, "import", "Import OPML chosen with a dialog", opml.HandleOPMLImportDialog},
+		{"/api/opml/export-dialog", []string{post}, "import", "Export OPML to a file chosen with a dialog", opml.HandleOPMLExportDialog},
+		{"/api/backup/export", []string{post}, "import", "Create a backup archive", backup.HandleBackupExport},
+		{"/api/backup/import", []string{post}, "import", "Restore a backup archive", backup.HandleBackupImport},
+		{"/api/import/reader", []string{post}, "import", "Import starred and read later items of another reader", readerimport.HandleReaderImport},
+
+		// FreshRSS
+		{"/api/freshrss/sync", []string{post}, "freshrss", "Synchronize with FreshRSS", freshrssHandler.HandleSync},
+		{"/api/freshrss/sync-feed", []string{post}, "freshrss", "Synchronize a single FreshRSS feed", freshrssHandler.HandleSyncFeed},
+		{"/api/freshrss/status", []string{get}, "freshrss", "Get the FreshRSS synchronization status", freshrssHandler.HandleSyncStatus},
+
+		// WebSub
+		{"/api/websub/callback", []string{get, post}, "websub", "Verify a subscription or receive content pushed by a WebSub hub", websub.HandleCallback},
+		{"/api/websub/subscriptions", []string{get}, "websub", "List the WebSub subscriptions of feeds", websub.HandleSubscriptions},
+
+		// Published feeds
+		{"/api/published", []string{get}, "published", "List the published feeds and their URLs", publish.HandleList},
+		{"/api/published/create", []string{post}, "published", "Publish a feed of favorites, read later articles, a category or a filter", publish.HandleCreate},
+		{"/api/published/update", []string{post}, "published", "Update a published feed", publish.HandleUpdate},
+		{"/api/published/delete", []string{post}, "published", "Unpublish a feed", publish.HandleDelete},
+		{"/api/published/regenerate-token", []string{post}, "published", "Replace the token of a published feed, revoking its URLs", publish.HandleRegenerateToken},
+		{"/api/published/feed", []string{get, head}, "published", "Get a published feed as RSS, Atom or JSON Feed with its token", publish.HandleFeed},
+
+		// Application
+		{"/api/version", []string{get}, "app", "Get the application version", update.HandleVersion},
+		{"/api/check-updates", []string{get}, "app", "Check for a new version", update.HandleCheckUpdates},
+		{"/api/download-update", []string{post}, "app", "Download the latest version", update.HandleDownloadUpdate},
+		{"/api/install-update", []string{post}, "app", "Install the downloaded version", update.HandleInstallUpdate},
+		{"/api/window/state", []string{get}, "app", "Get the saved window state", window.HandleGetWindowState},
+		{"/api/window/save", []string{post}, "app", "Save the window state", window.HandleSaveWindowState},
+		{"/api/browser/open", []string{post}, "app", "Open a URL in the default browser", browser.HandleOpenURL},
+	}
+}
+
+// handleChatSession dispatches requests on a chat session by method
+func handleChatSession(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		chat.HandleGetSession(h, w, r)
+	case http.MethodPut, http.MethodPatch:
+		chat.HandleUpdateSession(h, w, r)
+	case http.MethodDelete:
+		chat.HandleDeleteSession(h, w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
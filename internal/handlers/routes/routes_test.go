@@ -0,0 +1,213 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+)
+
+func setupMux(t *testing.T) *http.ServeMux {
+	t.Helper()
+	mux, _ := setupMuxWithDB(t)
+	return mux
+}
+
+func setupMuxWithDB(t *testing.T) (*http.ServeMux, *database.DB) {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	mux := http.NewServeMux()
+	Register(mux, core.NewHandler(db, nil, nil))
+	return mux, db
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) ErrorDetail {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q, want application/json", ct)
+	}
+	var resp ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode error envelope: %v", err)
+	}
+	return resp.Error
+}
+
+func TestRoutesAreComplete(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rt := range Routes() {
+		if seen[rt.Path] {
+			t.Errorf("duplicate route %s", rt.Path)
+		}
+		seen[rt.Path] = true
+
+		if !strings.HasPrefix(rt.Path, "/api/") || strings.HasPrefix(rt.Path, V1Prefix) {
+			t.Errorf("route %s should be an unversioned /api path", rt.Path)
+		}
+		if len(rt.Methods) == 0 || rt.Tag == "" || rt.Summary == "" || rt.Handler == nil {
+			t.Errorf("route %s is missing methods, tag, summary or handler", rt.Path)
+		}
+	}
+}
+
+func TestV1MethodNotAllowed(t *testing.T) {
+	mux := setupMux(t)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/feeds", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET" {
+		t.Errorf("Allow = %q, want GET", allow)
+	}
+	if e := decodeError(t, w); e.Status != 405 || e.Code != "method_not_allowed" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
+
+func TestV1TextErrorIsWrapped(t *testing.T) {
+	mux := setupMux(t)
+
+	// The handler reports a missing article with http.Error
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/video?id=abc", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	e := decodeError(t, w)
+	if e.Status != 400 || e.Code != "bad_request" || e.Message == "" || strings.HasSuffix(e.Message, "\n") {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
+
+func TestV1JSONErrorIsWrapped(t *testing.T) {
+	mux, db := setupMuxWithDB(t)
+
+	// The handler reports an article without content with a JSON error body
+	feedID, _ := db.AddFeed(&models.Feed{Title: "feed", URL: "http://x/feed"})
+	if err := db.SaveArticles(context.Background(), []*models.Article{{FeedID: feedID, Title: "empty", URL: "http://x/1"}}); err != nil {
+		t.Fatalf("SaveArticles: %v", err)
+	}
+	articles, _ := db.GetArticles("", feedID, "", false, 1, 0)
+	if len(articles) != 1 {
+		t.Fatalf("got %d articles, want 1", len(articles))
+	}
+	if err := db.SetArticleContent(articles[0].ID, ""); err != nil {
+		t.Fatalf("SetArticleContent: %v", err)
+	}
+
+	body := `{"article_id":` + strconv.FormatInt(articles[0].ID, 10) + `}`
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/articles/summarize", strings.NewReader(body)))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", w.Code)
+	}
+	if e := decodeError(t, w); e.Code != "unprocessable_entity" || e.Message != "No content available for this article" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
+
+func TestV1SuccessAndLegacyPaths(t *testing.T) {
+	mux := setupMux(t)
+
+	for _, path := range []string{"/api/feeds", "/api/v1/feeds"} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", path, w.Code)
+		}
+	}
+
+	// Unversioned paths keep the handler's own behavior
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles/video?id=abc", nil))
+	if w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), `"code"`) {
+		t.Errorf("unexpected legacy response: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestV1UnknownEndpoint(t *testing.T) {
+	mux := setupMux(t)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", w.Code)
+	}
+	if e := decodeError(t, w); e.Code != "not_found" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"Feed not found\n", "Feed not found"},
+		{`{"error":"AI usage limit reached"}`, "AI usage limit reached"},
+		{`{"success":false,"message":"Sync failed"}`, "Sync failed"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := errorMessage([]byte(tt.body)); got != tt.want {
+			t.Errorf("errorMessage(%q) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	mux := setupMux(t)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+
+	var spec struct {
+		OpenAPI string `json:"openapi"`
+		Paths   map[string]map[string]struct {
+			OperationID string   `json:"operationId"`
+			Tags        []string `json:"tags"`
+		} `json:"paths"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&spec); err != nil {
+		t.Fatalf("failed to decode spec: %v", err)
+	}
+	if spec.OpenAPI != "3.0.3" {
+		t.Errorf("openapi = %q", spec.OpenAPI)
+	}
+	if len(spec.Paths) != len(Routes()) {
+		t.Errorf("spec has %d paths, want %d", len(spec.Paths), len(Routes()))
+	}
+
+	op, ok := spec.Paths["/feeds/discover-all/start"]["post"]
+	if !ok {
+		t.Fatal("POST /feeds/discover-all/start missing from spec")
+	}
+	if op.OperationID != "postFeedsDiscoverAllStart" || len(op.Tags) != 1 || op.Tags[0] != "discovery" {
+		t.Errorf("unexpected operation: %+v", op)
+	}
+	if _, ok := spec.Paths["/ai/chat/session"]["patch"]; !ok {
+		t.Error("PATCH /ai/chat/session missing from spec")
+	}
+}
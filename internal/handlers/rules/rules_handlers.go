@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/langdetect"
 	"MrRSS/internal/rules"
 )
 
@@ -42,3 +43,10 @@ func HandleApplyRule(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	}
 	json.NewEncoder(w).Encode(response)
 }
+
+// HandleArticleLanguages lists the languages that article language conditions can match:
+// the codes of the language detector and the code of articles of undetected language
+func HandleArticleLanguages(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(append(langdetect.Languages(), rules.UnknownLanguage))
+}
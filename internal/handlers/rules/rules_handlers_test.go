@@ -2,8 +2,10 @@ package rules
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -39,3 +41,20 @@ func TestHandleApplyRule_NoActions(t *testing.T) {
 		t.Fatalf("expected %d got %d", http.StatusBadRequest, rr.Code)
 	}
 }
+
+func TestHandleArticleLanguages(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/rules/languages", nil)
+	rr := httptest.NewRecorder()
+
+	HandleArticleLanguages(nil, rr, req)
+
+	var langs []string
+	if err := json.NewDecoder(rr.Body).Decode(&langs); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	for _, lang := range []string{"en", "sv", "id", "uk", "fa", "hi", "unknown"} {
+		if !slices.Contains(langs, lang) {
+			t.Errorf("expected %q in %v", lang, langs)
+		}
+	}
+}
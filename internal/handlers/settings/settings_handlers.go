@@ -15,13 +15,18 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
 		aiApiKey, _ := h.DB.GetEncryptedSetting("ai_api_key")
+		aiBudgetAlertThreshold, _ := h.DB.GetSetting("ai_budget_alert_threshold")
 		aiChatEnabled, _ := h.DB.GetSetting("ai_chat_enabled")
+		aiCostBudget, _ := h.DB.GetSetting("ai_cost_budget")
 		aiCustomHeaders, _ := h.DB.GetSetting("ai_custom_headers")
 		aiEndpoint, _ := h.DB.GetSetting("ai_endpoint")
 		aiModel, _ := h.DB.GetSetting("ai_model")
+		aiPriceTable, _ := h.DB.GetSetting("ai_price_table")
 		aiSummaryPrompt, _ := h.DB.GetSetting("ai_summary_prompt")
 		aiTranslationPrompt, _ := h.DB.GetSetting("ai_translation_prompt")
+		aiUsageAutoReset, _ := h.DB.GetSetting("ai_usage_auto_reset")
 		aiUsageLimit, _ := h.DB.GetSetting("ai_usage_limit")
+		aiUsagePeriod, _ := h.DB.GetSetting("ai_usage_period")
 		aiUsageTokens, _ := h.DB.GetSetting("ai_usage_tokens")
 		autoCleanupEnabled, _ := h.DB.GetSetting("auto_cleanup_enabled")
 		autoShowAllContent, _ := h.DB.GetSetting("auto_show_all_content")
@@ -33,6 +38,12 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		deeplApiKey, _ := h.DB.GetEncryptedSetting("deepl_api_key")
 		deeplEndpoint, _ := h.DB.GetSetting("deepl_endpoint")
 		defaultViewMode, _ := h.DB.GetSetting("default_view_mode")
+		embeddingApiKey, _ := h.DB.GetEncryptedSetting("embedding_api_key")
+		embeddingDedupEnabled, _ := h.DB.GetSetting("embedding_dedup_enabled")
+		embeddingDedupThreshold, _ := h.DB.GetSetting("embedding_dedup_threshold")
+		embeddingEnabled, _ := h.DB.GetSetting("embedding_enabled")
+		embeddingEndpoint, _ := h.DB.GetSetting("embedding_endpoint")
+		embeddingModel, _ := h.DB.GetSetting("embedding_model")
 		freshrssApiPassword, _ := h.DB.GetEncryptedSetting("freshrss_api_password")
 		freshrssAutoSyncInterval, _ := h.DB.GetSetting("freshrss_auto_sync_interval")
 		freshrssEnabled, _ := h.DB.GetSetting("freshrss_enabled")
@@ -53,13 +64,24 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		mediaCacheEnabled, _ := h.DB.GetSetting("media_cache_enabled")
 		mediaCacheMaxAgeDays, _ := h.DB.GetSetting("media_cache_max_age_days")
 		mediaCacheMaxSizeMb, _ := h.DB.GetSetting("media_cache_max_size_mb")
+		mediaProxyAllowedHosts, _ := h.DB.GetSetting("media_proxy_allowed_hosts")
+		mediaProxyDeniedHosts, _ := h.DB.GetSetting("media_proxy_denied_hosts")
 		mediaProxyFallback, _ := h.DB.GetSetting("media_proxy_fallback")
+		mediaProxyMaxSizeMb, _ := h.DB.GetSetting("media_proxy_max_size_mb")
+		mediaProxyPrivateNetworks, _ := h.DB.GetSetting("media_proxy_private_networks")
 		networkBandwidthMbps, _ := h.DB.GetSetting("network_bandwidth_mbps")
 		networkLatencyMs, _ := h.DB.GetSetting("network_latency_ms")
 		networkSpeed, _ := h.DB.GetSetting("network_speed")
 		obsidianEnabled, _ := h.DB.GetSetting("obsidian_enabled")
 		obsidianVault, _ := h.DB.GetSetting("obsidian_vault")
 		obsidianVaultPath, _ := h.DB.GetSetting("obsidian_vault_path")
+		offlinePrefetchCategories, _ := h.DB.GetSetting("offline_prefetch_categories")
+		offlinePrefetchEnabled, _ := h.DB.GetSetting("offline_prefetch_enabled")
+		offlinePrefetchFavorites, _ := h.DB.GetSetting("offline_prefetch_favorites")
+		offlinePrefetchMaxSizeMb, _ := h.DB.GetSetting("offline_prefetch_max_size_mb")
+		offlinePrefetchReadLater, _ := h.DB.GetSetting("offline_prefetch_read_later")
+		offlinePrefetchUnread, _ := h.DB.GetSetting("offline_prefetch_unread")
+		podcastDownloadMaxSizeMb, _ := h.DB.GetSetting("podcast_download_max_size_mb")
 		proxyEnabled, _ := h.DB.GetSetting("proxy_enabled")
 		proxyHost, _ := h.DB.GetSetting("proxy_host")
 		proxyPassword, _ := h.DB.GetEncryptedSetting("proxy_password")
@@ -83,163 +105,213 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		translationEnabled, _ := h.DB.GetSetting("translation_enabled")
 		translationProvider, _ := h.DB.GetSetting("translation_provider")
 		updateInterval, _ := h.DB.GetSetting("update_interval")
+		videoTranscriptsEnabled, _ := h.DB.GetSetting("video_transcripts_enabled")
+		websubCallbackUrl, _ := h.DB.GetSetting("websub_callback_url")
 		windowHeight, _ := h.DB.GetSetting("window_height")
 		windowMaximized, _ := h.DB.GetSetting("window_maximized")
 		windowWidth, _ := h.DB.GetSetting("window_width")
 		windowX, _ := h.DB.GetSetting("window_x")
 		windowY, _ := h.DB.GetSetting("window_y")
 		json.NewEncoder(w).Encode(map[string]string{
-			"ai_api_key":                  aiApiKey,
-			"ai_chat_enabled":             aiChatEnabled,
-			"ai_custom_headers":           aiCustomHeaders,
-			"ai_endpoint":                 aiEndpoint,
-			"ai_model":                    aiModel,
-			"ai_summary_prompt":           aiSummaryPrompt,
-			"ai_translation_prompt":       aiTranslationPrompt,
-			"ai_usage_limit":              aiUsageLimit,
-			"ai_usage_tokens":             aiUsageTokens,
-			"auto_cleanup_enabled":        autoCleanupEnabled,
-			"auto_show_all_content":       autoShowAllContent,
-			"auto_update":                 autoUpdate,
-			"baidu_app_id":                baiduAppId,
-			"baidu_secret_key":            baiduSecretKey,
-			"close_to_tray":               closeToTray,
-			"custom_css_file":             customCssFile,
-			"deepl_api_key":               deeplApiKey,
-			"deepl_endpoint":              deeplEndpoint,
-			"default_view_mode":           defaultViewMode,
-			"freshrss_api_password":       freshrssApiPassword,
-			"freshrss_auto_sync_interval": freshrssAutoSyncInterval,
-			"freshrss_enabled":            freshrssEnabled,
-			"freshrss_last_sync_time":     freshrssLastSyncTime,
-			"freshrss_server_url":         freshrssServerUrl,
-			"freshrss_sync_on_startup":    freshrssSyncOnStartup,
-			"freshrss_username":           freshrssUsername,
-			"full_text_fetch_enabled":     fullTextFetchEnabled,
-			"google_translate_endpoint":   googleTranslateEndpoint,
-			"hover_mark_as_read":          hoverMarkAsRead,
-			"image_gallery_enabled":       imageGalleryEnabled,
-			"language":                    language,
-			"last_global_refresh":         lastGlobalRefresh,
-			"last_network_test":           lastNetworkTest,
-			"max_article_age_days":        maxArticleAgeDays,
-			"max_cache_size_mb":           maxCacheSizeMb,
-			"max_concurrent_refreshes":    maxConcurrentRefreshes,
-			"media_cache_enabled":         mediaCacheEnabled,
-			"media_cache_max_age_days":    mediaCacheMaxAgeDays,
-			"media_cache_max_size_mb":     mediaCacheMaxSizeMb,
-			"media_proxy_fallback":        mediaProxyFallback,
-			"network_bandwidth_mbps":      networkBandwidthMbps,
-			"network_latency_ms":          networkLatencyMs,
-			"network_speed":               networkSpeed,
-			"obsidian_enabled":            obsidianEnabled,
-			"obsidian_vault":              obsidianVault,
-			"obsidian_vault_path":         obsidianVaultPath,
-			"proxy_enabled":               proxyEnabled,
-			"proxy_host":                  proxyHost,
-			"proxy_password":              proxyPassword,
-			"proxy_port":                  proxyPort,
-			"proxy_type":                  proxyType,
-			"proxy_username":              proxyUsername,
-			"refresh_mode":                refreshMode,
-			"retry_timeout_seconds":       retryTimeoutSeconds,
-			"rules":                       rules,
-			"shortcuts":                   shortcuts,
-			"shortcuts_enabled":           shortcutsEnabled,
-			"show_article_preview_images": showArticlePreviewImages,
-			"show_hidden_articles":        showHiddenArticles,
-			"startup_on_boot":             startupOnBoot,
-			"summary_enabled":             summaryEnabled,
-			"summary_length":              summaryLength,
-			"summary_provider":            summaryProvider,
-			"summary_trigger_mode":        summaryTriggerMode,
-			"target_language":             targetLanguage,
-			"theme":                       theme,
-			"translation_enabled":         translationEnabled,
-			"translation_provider":        translationProvider,
-			"update_interval":             updateInterval,
-			"window_height":               windowHeight,
-			"window_maximized":            windowMaximized,
-			"window_width":                windowWidth,
-			"window_x":                    windowX,
-			"window_y":                    windowY,
+			"ai_api_key":                   aiApiKey,
+			"ai_budget_alert_threshold":    aiBudgetAlertThreshold,
+			"ai_chat_enabled":              aiChatEnabled,
+			"ai_cost_budget":               aiCostBudget,
+			"ai_custom_headers":            aiCustomHeaders,
+			"ai_endpoint":                  aiEndpoint,
+			"ai_model":                     aiModel,
+			"ai_price_table":               aiPriceTable,
+			"ai_summary_prompt":            aiSummaryPrompt,
+			"ai_translation_prompt":        aiTranslationPrompt,
+			"ai_usage_auto_reset":          aiUsageAutoReset,
+			"ai_usage_limit":               aiUsageLimit,
+			"ai_usage_period":              aiUsagePeriod,
+			"ai_usage_tokens":              aiUsageTokens,
+			"auto_cleanup_enabled":         autoCleanupEnabled,
+			"auto_show_all_content":        autoShowAllContent,
+			"auto_update":                  autoUpdate,
+			"baidu_app_id":                 baiduAppId,
+			"baidu_secret_key":             baiduSecretKey,
+			"close_to_tray":                closeToTray,
+			"custom_css_file":              customCssFile,
+			"deepl_api_key":                deeplApiKey,
+			"deepl_endpoint":               deeplEndpoint,
+			"default_view_mode":            defaultViewMode,
+			"embedding_api_key":            embeddingApiKey,
+			"embedding_dedup_enabled":      embeddingDedupEnabled,
+			"embedding_dedup_threshold":    embeddingDedupThreshold,
+			"embedding_enabled":            embeddingEnabled,
+			"embedding_endpoint":           embeddingEndpoint,
+			"embedding_model":              embeddingModel,
+			"freshrss_api_password":        freshrssApiPassword,
+			"freshrss_auto_sync_interval":  freshrssAutoSyncInterval,
+			"freshrss_enabled":             freshrssEnabled,
+			"freshrss_last_sync_time":      freshrssLastSyncTime,
+			"freshrss_server_url":          freshrssServerUrl,
+			"freshrss_sync_on_startup":     freshrssSyncOnStartup,
+			"freshrss_username":            freshrssUsername,
+			"full_text_fetch_enabled":      fullTextFetchEnabled,
+			"google_translate_endpoint":    googleTranslateEndpoint,
+			"hover_mark_as_read":           hoverMarkAsRead,
+			"image_gallery_enabled":        imageGalleryEnabled,
+			"language":                     language,
+			"last_global_refresh":          lastGlobalRefresh,
+			"last_network_test":            lastNetworkTest,
+			"max_article_age_days":         maxArticleAgeDays,
+			"max_cache_size_mb":            maxCacheSizeMb,
+			"max_concurrent_refreshes":     maxConcurrentRefreshes,
+			"media_cache_enabled":          mediaCacheEnabled,
+			"media_cache_max_age_days":     mediaCacheMaxAgeDays,
+			"media_cache_max_size_mb":      mediaCacheMaxSizeMb,
+			"media_proxy_allowed_hosts":    mediaProxyAllowedHosts,
+			"media_proxy_denied_hosts":     mediaProxyDeniedHosts,
+			"media_proxy_fallback":         mediaProxyFallback,
+			"media_proxy_max_size_mb":      mediaProxyMaxSizeMb,
+			"media_proxy_private_networks": mediaProxyPrivateNetworks,
+			"network_bandwidth_mbps":       networkBandwidthMbps,
+			"network_latency_ms":           networkLatencyMs,
+			"network_speed":                networkSpeed,
+			"obsidian_enabled":             obsidianEnabled,
+			"obsidian_vault":               obsidianVault,
+			"obsidian_vault_path":          obsidianVaultPath,
+			"offline_prefetch_categories":  offlinePrefetchCategories,
+			"offline_prefetch_enabled":     offlinePrefetchEnabled,
+			"offline_prefetch_favorites":   offlinePrefetchFavorites,
+			"offline_prefetch_max_size_mb": offlinePrefetchMaxSizeMb,
+			"offline_prefetch_read_later":  offlinePrefetchReadLater,
+			"offline_prefetch_unread":      offlinePrefetchUnread,
+			"podcast_download_max_size_mb": podcastDownloadMaxSizeMb,
+			"proxy_enabled":                proxyEnabled,
+			"proxy_host":                   proxyHost,
+			"proxy_password":               proxyPassword,
+			"proxy_port":                   proxyPort,
+			"proxy_type":                   proxyType,
+			"proxy_username":               proxyUsername,
+			"refresh_mode":                 refreshMode,
+			"retry_timeout_seconds":        retryTimeoutSeconds,
+			"rules":                        rules,
+			"shortcuts":                    shortcuts,
+			"shortcuts_enabled":            shortcutsEnabled,
+			"show_article_preview_images":  showArticlePreviewImages,
+			"show_hidden_articles":         showHiddenArticles,
+			"startup_on_boot":              startupOnBoot,
+			"summary_enabled":              summaryEnabled,
+			"summary_length":               summaryLength,
+			"summary_provider":             summaryProvider,
+			"summary_trigger_mode":         summaryTriggerMode,
+			"target_language":              targetLanguage,
+			"theme":                        theme,
+			"translation_enabled":          translationEnabled,
+			"translation_provider":         translationProvider,
+			"update_interval":              updateInterval,
+			"video_transcripts_enabled":    videoTranscriptsEnabled,
+			"websub_callback_url":          websubCallbackUrl,
+			"window_height":                windowHeight,
+			"window_maximized":             windowMaximized,
+			"window_width":                 windowWidth,
+			"window_x":                     windowX,
+			"window_y":                     windowY,
 		})
 	case http.MethodPost:
 		var req struct {
-			AIAPIKey                 string `json:"ai_api_key"`
-			AIChatEnabled            string `json:"ai_chat_enabled"`
-			AICustomHeaders          string `json:"ai_custom_headers"`
-			AIEndpoint               string `json:"ai_endpoint"`
-			AIModel                  string `json:"ai_model"`
-			AISummaryPrompt          string `json:"ai_summary_prompt"`
-			AITranslationPrompt      string `json:"ai_translation_prompt"`
-			AIUsageLimit             string `json:"ai_usage_limit"`
-			AIUsageTokens            string `json:"ai_usage_tokens"`
-			AutoCleanupEnabled       string `json:"auto_cleanup_enabled"`
-			AutoShowAllContent       string `json:"auto_show_all_content"`
-			AutoUpdate               string `json:"auto_update"`
-			BaiduAppId               string `json:"baidu_app_id"`
-			BaiduSecretKey           string `json:"baidu_secret_key"`
-			CloseToTray              string `json:"close_to_tray"`
-			CustomCssFile            string `json:"custom_css_file"`
-			DeeplAPIKey              string `json:"deepl_api_key"`
-			DeeplEndpoint            string `json:"deepl_endpoint"`
-			DefaultViewMode          string `json:"default_view_mode"`
-			FreshRSSAPIPassword      string `json:"freshrss_api_password"`
-			FreshRSSAutoSyncInterval string `json:"freshrss_auto_sync_interval"`
-			FreshRSSEnabled          string `json:"freshrss_enabled"`
-			FreshRSSLastSyncTime     string `json:"freshrss_last_sync_time"`
-			FreshRSSServerUrl        string `json:"freshrss_server_url"`
-			FreshRSSSyncOnStartup    string `json:"freshrss_sync_on_startup"`
-			FreshRSSUsername         string `json:"freshrss_username"`
-			FullTextFetchEnabled     string `json:"full_text_fetch_enabled"`
-			GoogleTranslateEndpoint  string `json:"google_translate_endpoint"`
-			HoverMarkAsRead          string `json:"hover_mark_as_read"`
-			ImageGalleryEnabled      string `json:"image_gallery_enabled"`
-			Language                 string `json:"language"`
-			LastGlobalRefresh        string `json:"last_global_refresh"`
-			LastNetworkTest          string `json:"last_network_test"`
-			MaxArticleAgeDays        string `json:"max_article_age_days"`
-			MaxCacheSizeMb           string `json:"max_cache_size_mb"`
-			MaxConcurrentRefreshes   string `json:"max_concurrent_refreshes"`
-			MediaCacheEnabled        string `json:"media_cache_enabled"`
-			MediaCacheMaxAgeDays     string `json:"media_cache_max_age_days"`
-			MediaCacheMaxSizeMb      string `json:"media_cache_max_size_mb"`
-			MediaProxyFallback       string `json:"media_proxy_fallback"`
-			NetworkBandwidthMbps     string `json:"network_bandwidth_mbps"`
-			NetworkLatencyMs         string `json:"network_latency_ms"`
-			NetworkSpeed             string `json:"network_speed"`
-			ObsidianEnabled          string `json:"obsidian_enabled"`
-			ObsidianVault            string `json:"obsidian_vault"`
-			ObsidianVaultPath        string `json:"obsidian_vault_path"`
-			ProxyEnabled             string `json:"proxy_enabled"`
-			ProxyHost                string `json:"proxy_host"`
-			ProxyPassword            string `json:"proxy_password"`
-			ProxyPort                string `json:"proxy_port"`
-			ProxyType                string `json:"proxy_type"`
-			ProxyUsername            string `json:"proxy_username"`
-			RefreshMode              string `json:"refresh_mode"`
-			RetryTimeoutSeconds      string `json:"retry_timeout_seconds"`
-			Rules                    string `json:"rules"`
-			Shortcuts                string `json:"shortcuts"`
-			ShortcutsEnabled         string `json:"shortcuts_enabled"`
-			ShowArticlePreviewImages string `json:"show_article_preview_images"`
-			ShowHiddenArticles       string `json:"show_hidden_articles"`
-			StartupOnBoot            string `json:"startup_on_boot"`
-			SummaryEnabled           string `json:"summary_enabled"`
-			SummaryLength            string `json:"summary_length"`
-			SummaryProvider          string `json:"summary_provider"`
-			SummaryTriggerMode       string `json:"summary_trigger_mode"`
-			TargetLanguage           string `json:"target_language"`
-			Theme                    string `json:"theme"`
-			TranslationEnabled       string `json:"translation_enabled"`
-			TranslationProvider      string `json:"translation_provider"`
-			UpdateInterval           string `json:"update_interval"`
-			WindowHeight             string `json:"window_height"`
-			WindowMaximized          string `json:"window_maximized"`
-			WindowWidth              string `json:"window_width"`
-			WindowX                  string `json:"window_x"`
-			WindowY                  string `json:"window_y"`
+			AIAPIKey                  string `json:"ai_api_key"`
+			AIBudgetAlertThreshold    string `json:"ai_budget_alert_threshold"`
+			AIChatEnabled             string `json:"ai_chat_enabled"`
+			AICostBudget              string `json:"ai_cost_budget"`
+			AICustomHeaders           string `json:"ai_custom_headers"`
+			AIEndpoint                string `json:"ai_endpoint"`
+			AIModel                   string `json:"ai_model"`
+			AIPriceTable              string `json:"ai_price_table"`
+			AISummaryPrompt           string `json:"ai_summary_prompt"`
+			AITranslationPrompt       string `json:"ai_translation_prompt"`
+			AIUsageAutoReset          string `json:"ai_usage_auto_reset"`
+			AIUsageLimit              string `json:"ai_usage_limit"`
+			AIUsagePeriod             string `json:"ai_usage_period"`
+			AIUsageTokens             string `json:"ai_usage_tokens"`
+			AutoCleanupEnabled        string `json:"auto_cleanup_enabled"`
+			AutoShowAllContent        string `json:"auto_show_all_content"`
+			AutoUpdate                string `json:"auto_update"`
+			BaiduAppId                string `json:"baidu_app_id"`
+			BaiduSecretKey            string `json:"baidu_secret_key"`
+			CloseToTray               string `json:"close_to_tray"`
+			CustomCssFile             string `json:"custom_css_file"`
+			DeeplAPIKey               string `json:"deepl_api_key"`
+			DeeplEndpoint             string `json:"deepl_endpoint"`
+			DefaultViewMode           string `json:"default_view_mode"`
+			EmbeddingAPIKey           string `json:"embedding_api_key"`
+			EmbeddingDedupEnabled     string `json:"embedding_dedup_enabled"`
+			EmbeddingDedupThreshold   string `json:"embedding_dedup_threshold"`
+			EmbeddingEnabled          string `json:"embedding_enabled"`
+			EmbeddingEndpoint         string `json:"embedding_endpoint"`
+			EmbeddingModel            string `json:"embedding_model"`
+			FreshRSSAPIPassword       string `json:"freshrss_api_password"`
+			FreshRSSAutoSyncInterval  string `json:"freshrss_auto_sync_interval"`
+			FreshRSSEnabled           string `json:"freshrss_enabled"`
+			FreshRSSLastSyncTime      string `json:"freshrss_last_sync_time"`
+			FreshRSSServerUrl         string `json:"freshrss_server_url"`
+			FreshRSSSyncOnStartup     string `json:"freshrss_sync_on_startup"`
+			FreshRSSUsername          string `json:"freshrss_username"`
+			FullTextFetchEnabled      string `json:"full_text_fetch_enabled"`
+			GoogleTranslateEndpoint   string `json:"google_translate_endpoint"`
+			HoverMarkAsRead           string `json:"hover_mark_as_read"`
+			ImageGalleryEnabled       string `json:"image_gallery_enabled"`
+			Language                  string `json:"language"`
+			LastGlobalRefresh         string `json:"last_global_refresh"`
+			LastNetworkTest           string `json:"last_network_test"`
+			MaxArticleAgeDays         string `json:"max_article_age_days"`
+			MaxCacheSizeMb            string `json:"max_cache_size_mb"`
+			MaxConcurrentRefreshes    string `json:"max_concurrent_refreshes"`
+			MediaCacheEnabled         string `json:"media_cache_enabled"`
+			MediaCacheMaxAgeDays      string `json:"media_cache_max_age_days"`
+			MediaCacheMaxSizeMb       string `json:"media_cache_max_size_mb"`
+			MediaProxyAllowedHosts    string `json:"media_proxy_allowed_hosts"`
+			MediaProxyDeniedHosts     string `json:"media_proxy_denied_hosts"`
+			MediaProxyFallback        string `json:"media_proxy_fallback"`
+			MediaProxyMaxSizeMb       string `json:"media_proxy_max_size_mb"`
+			MediaProxyPrivateNetworks string `json:"media_proxy_private_networks"`
+			NetworkBandwidthMbps      string `json:"network_bandwidth_mbps"`
+			NetworkLatencyMs          string `json:"network_latency_ms"`
+			NetworkSpeed              string `json:"network_speed"`
+			ObsidianEnabled           string `json:"obsidian_enabled"`
+			ObsidianVault             string `json:"obsidian_vault"`
+			ObsidianVaultPath         string `json:"obsidian_vault_path"`
+			OfflinePrefetchCategories string `json:"offline_prefetch_categories"`
+			OfflinePrefetchEnabled    string `json:"offline_prefetch_enabled"`
+			OfflinePrefetchFavorites  string `json:"offline_prefetch_favorites"`
+			OfflinePrefetchMaxSizeMb  string `json:"offline_prefetch_max_size_mb"`
+			OfflinePrefetchReadLater  string `json:"offline_prefetch_read_later"`
+			OfflinePrefetchUnread     string `json:"offline_prefetch_unread"`
+			PodcastDownloadMaxSizeMb  string `json:"podcast_download_max_size_mb"`
+			ProxyEnabled              string `json:"proxy_enabled"`
+			ProxyHost                 string `json:"proxy_host"`
+			ProxyPassword             string `json:"proxy_password"`
+			ProxyPort                 string `json:"proxy_port"`
+			ProxyType                 string `json:"proxy_type"`
+			ProxyUsername             string `json:"proxy_username"`
+			RefreshMode               string `json:"refresh_mode"`
+			RetryTimeoutSeconds       string `json:"retry_timeout_seconds"`
+			Rules                     string `json:"rules"`
+			Shortcuts                 string `json:"shortcuts"`
+			ShortcutsEnabled          string `json:"shortcuts_enabled"`
+			ShowArticlePreviewImages  string `json:"show_article_preview_images"`
+			ShowHiddenArticles        string `json:"show_hidden_articles"`
+			StartupOnBoot             string `json:"startup_on_boot"`
+			SummaryEnabled            string `json:"summary_enabled"`
+			SummaryLength             string `json:"summary_length"`
+			SummaryProvider           string `json:"summary_provider"`
+			SummaryTriggerMode        string `json:"summary_trigger_mode"`
+			TargetLanguage            string `json:"target_language"`
+			Theme                     string `json:"theme"`
+			TranslationEnabled        string `json:"translation_enabled"`
+			TranslationProvider       string `json:"translation_provider"`
+			UpdateInterval            string `json:"update_interval"`
+			VideoTranscriptsEnabled   string `json:"video_transcripts_enabled"`
+			WebsubCallbackUrl         string `json:"websub_callback_url"`
+			WindowHeight              string `json:"window_height"`
+			WindowMaximized           string `json:"window_maximized"`
+			WindowWidth               string `json:"window_width"`
+			WindowX                   string `json:"window_x"`
+			WindowY                   string `json:"window_y"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -251,10 +323,18 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if req.AIBudgetAlertThreshold != "" {
+			h.DB.SetSetting("ai_budget_alert_threshold", req.AIBudgetAlertThreshold)
+		}
+
 		if req.AIChatEnabled != "" {
 			h.DB.SetSetting("ai_chat_enabled", req.AIChatEnabled)
 		}
 
+		if req.AICostBudget != "" {
+			h.DB.SetSetting("ai_cost_budget", req.AICostBudget)
+		}
+
 		if req.AICustomHeaders != "" {
 			h.DB.SetSetting("ai_custom_headers", req.AICustomHeaders)
 		}
@@ -267,6 +347,10 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("ai_model", req.AIModel)
 		}
 
+		if req.AIPriceTable != "" {
+			h.DB.SetSetting("ai_price_table", req.AIPriceTable)
+		}
+
 		if req.AISummaryPrompt != "" {
 			h.DB.SetSetting("ai_summary_prompt", req.AISummaryPrompt)
 		}
@@ -275,10 +359,18 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("ai_translation_prompt", req.AITranslationPrompt)
 		}
 
+		if req.AIUsageAutoReset != "" {
+			h.DB.SetSetting("ai_usage_auto_reset", req.AIUsageAutoReset)
+		}
+
 		if req.AIUsageLimit != "" {
 			h.DB.SetSetting("ai_usage_limit", req.AIUsageLimit)
 		}
 
+		if req.AIUsagePeriod != "" {
+			h.DB.SetSetting("ai_usage_period", req.AIUsagePeriod)
+		}
+
 		if req.AIUsageTokens != "" {
 			h.DB.SetSetting("ai_usage_tokens", req.AIUsageTokens)
 		}
@@ -327,6 +419,32 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("default_view_mode", req.DefaultViewMode)
 		}
 
+		if err := h.DB.SetEncryptedSetting("embedding_api_key", req.EmbeddingAPIKey); err != nil {
+			log.Printf("Failed to save embedding_api_key: %v", err)
+			http.Error(w, "Failed to save embedding_api_key", http.StatusInternalServerError)
+			return
+		}
+
+		if req.EmbeddingDedupEnabled != "" {
+			h.DB.SetSetting("embedding_dedup_enabled", req.EmbeddingDedupEnabled)
+		}
+
+		if req.EmbeddingDedupThreshold != "" {
+			h.DB.SetSetting("embedding_dedup_threshold", req.EmbeddingDedupThreshold)
+		}
+
+		if req.EmbeddingEnabled != "" {
+			h.DB.SetSetting("embedding_enabled", req.EmbeddingEnabled)
+		}
+
+		if req.EmbeddingEndpoint != "" {
+			h.DB.SetSetting("embedding_endpoint", req.EmbeddingEndpoint)
+		}
+
+		if req.EmbeddingModel != "" {
+			h.DB.SetSetting("embedding_model", req.EmbeddingModel)
+		}
+
 		if err := h.DB.SetEncryptedSetting("freshrss_api_password", req.FreshRSSAPIPassword); err != nil {
 			log.Printf("Failed to save freshrss_api_password: %v", err)
 			http.Error(w, "Failed to save freshrss_api_password", http.StatusInternalServerError)
@@ -409,10 +527,26 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("media_cache_max_size_mb", req.MediaCacheMaxSizeMb)
 		}
 
+		if req.MediaProxyAllowedHosts != "" {
+			h.DB.SetSetting("media_proxy_allowed_hosts", req.MediaProxyAllowedHosts)
+		}
+
+		if req.MediaProxyDeniedHosts != "" {
+			h.DB.SetSetting("media_proxy_denied_hosts", req.MediaProxyDeniedHosts)
+		}
+
 		if req.MediaProxyFallback != "" {
 			h.DB.SetSetting("media_proxy_fallback", req.MediaProxyFallback)
 		}
 
+		if req.MediaProxyMaxSizeMb != "" {
+			h.DB.SetSetting("media_proxy_max_size_mb", req.MediaProxyMaxSizeMb)
+		}
+
+		if req.MediaProxyPrivateNetworks != "" {
+			h.DB.SetSetting("media_proxy_private_networks", req.MediaProxyPrivateNetworks)
+		}
+
 		if req.NetworkBandwidthMbps != "" {
 			h.DB.SetSetting("network_bandwidth_mbps", req.NetworkBandwidthMbps)
 		}
@@ -437,6 +571,34 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("obsidian_vault_path", req.ObsidianVaultPath)
 		}
 
+		if req.OfflinePrefetchCategories != "" {
+			h.DB.SetSetting("offline_prefetch_categories", req.OfflinePrefetchCategories)
+		}
+
+		if req.OfflinePrefetchEnabled != "" {
+			h.DB.SetSetting("offline_prefetch_enabled", req.OfflinePrefetchEnabled)
+		}
+
+		if req.OfflinePrefetchFavorites != "" {
+			h.DB.SetSetting("offline_prefetch_favorites", req.OfflinePrefetchFavorites)
+		}
+
+		if req.OfflinePrefetchMaxSizeMb != "" {
+			h.DB.SetSetting("offline_prefetch_max_size_mb", req.OfflinePrefetchMaxSizeMb)
+		}
+
+		if req.OfflinePrefetchReadLater != "" {
+			h.DB.SetSetting("offline_prefetch_read_later", req.OfflinePrefetchReadLater)
+		}
+
+		if req.OfflinePrefetchUnread != "" {
+			h.DB.SetSetting("offline_prefetch_unread", req.OfflinePrefetchUnread)
+		}
+
+		if req.PodcastDownloadMaxSizeMb != "" {
+			h.DB.SetSetting("podcast_download_max_size_mb", req.PodcastDownloadMaxSizeMb)
+		}
+
 		if req.ProxyEnabled != "" {
 			h.DB.SetSetting("proxy_enabled", req.ProxyEnabled)
 		}
@@ -533,6 +695,14 @@ func HandleSettings(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 			h.DB.SetSetting("update_interval", req.UpdateInterval)
 		}
 
+		if req.VideoTranscriptsEnabled != "" {
+			h.DB.SetSetting("video_transcripts_enabled", req.VideoTranscriptsEnabled)
+		}
+
+		if req.WebsubCallbackUrl != "" {
+			h.DB.SetSetting("websub_callback_url", req.WebsubCallbackUrl)
+		}
+
 		if req.WindowHeight != "" {
 			h.DB.SetSetting("window_height", req.WindowHeight)
 		}
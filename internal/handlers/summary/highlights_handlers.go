@@ -0,0 +1,162 @@
+package summary
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/summary"
+)
+
+// keyphraseTagSource marks tags extracted as keyphrases by the local summarizer
+const keyphraseTagSource = "keyphrase"
+
+// HandleArticleHighlights returns the key sentences of an article with their positions
+// in the article content, so the reader can highlight them in place.
+// Highlights are computed locally and never use an AI provider.
+func HandleArticleHighlights(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	lengthStr := r.URL.Query().Get("length")
+	if lengthStr == "" {
+		lengthStr, _ = h.DB.GetSetting("summary_length")
+	}
+	var length summary.SummaryLength
+	switch lengthStr {
+	case "short":
+		length = summary.Short
+	case "long":
+		length = summary.Long
+	case "medium", "":
+		length = summary.Medium
+	default:
+		http.Error(w, "Invalid length parameter. Use 'short', 'medium', or 'long'", http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.GetArticleContent(articleID)
+	if err != nil {
+		log.Printf("Error getting article content for highlights: %v", err)
+		http.Error(w, "Failed to fetch article content", http.StatusInternalServerError)
+		return
+	}
+
+	// Offsets refer to the content returned by /api/articles/content
+	json.NewEncoder(w).Encode(summary.NewSummarizer().Highlights(content, length))
+}
+
+// HandleArticleTags returns the tags of an article. Keyphrases are extracted from
+// the article content and stored as tags on first request, or when refresh is set.
+func HandleArticleTags(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	articleID, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid article ID", http.StatusBadRequest)
+		return
+	}
+
+	tags, err := h.DB.GetArticleTags(articleID)
+	if err != nil {
+		log.Printf("Error getting article tags: %v", err)
+		http.Error(w, "Failed to get article tags", http.StatusInternalServerError)
+		return
+	}
+
+	if len(tags) == 0 || r.URL.Query().Get("refresh") == "true" {
+		tags, err = extractArticleTags(h, articleID)
+		if err != nil {
+			log.Printf("Error extracting article keyphrases: %v", err)
+			http.Error(w, "Failed to extract article keyphrases", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tags": tags,
+	})
+}
+
+// extractArticleTags extracts keyphrases from an article's title and content
+// and stores them as tags.
+func extractArticleTags(h *core.Handler, articleID int64) ([]models.ArticleTag, error) {
+	article, err := h.DB.GetArticleByID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := h.GetArticleContent(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyphrases := summary.NewSummarizer().Keyphrases("<h1>"+article.Title+"</h1>"+content, summary.DefaultKeyphraseCount)
+	tags := make([]models.ArticleTag, len(keyphrases))
+	for i, k := range keyphrases {
+		tags[i] = models.ArticleTag{Tag: k.Phrase, Score: k.Score, Source: keyphraseTagSource}
+	}
+
+	if err := h.DB.SaveArticleTags(articleID, keyphraseTagSource, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// HandleArticlesByTag returns the most recent articles with a tag.
+func HandleArticlesByTag(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		http.Error(w, "Missing tag", http.StatusBadRequest)
+		return
+	}
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	ids, err := h.DB.GetArticleIDsByTag(tag, limit)
+	if err != nil {
+		log.Printf("Error getting articles by tag: %v", err)
+		http.Error(w, "Failed to get articles", http.StatusInternalServerError)
+		return
+	}
+	articles, err := h.DB.GetArticlesByIDs(ids)
+	if err != nil {
+		log.Printf("Error getting articles by tag: %v", err)
+		http.Error(w, "Failed to get articles", http.StatusInternalServerError)
+		return
+	}
+
+	// Keep the newest-first order of the tag query
+	byID := make(map[int64]models.Article, len(articles))
+	for _, article := range articles {
+		byID[article.ID] = article
+	}
+	ordered := make([]models.Article, 0, len(ids))
+	for _, id := range ids {
+		if article, ok := byID[id]; ok {
+			ordered = append(ordered, article)
+		}
+	}
+
+	json.NewEncoder(w).Encode(ordered)
+}
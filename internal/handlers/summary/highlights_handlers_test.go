@@ -0,0 +1,138 @@
+package summary
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/feed"
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/summary"
+)
+
+const testArticleContent = `<p>Machine learning is changing how companies build software.</p>
+<p>Machine learning models need large amounts of training data.</p>
+<p>Companies collect training data from many different sources.</p>
+<p>The quality of training data decides how well machine learning models perform.</p>
+<p>Some companies buy training data from specialized vendors.</p>`
+
+// setupArticleWithContent creates a handler with one article whose content is cached
+func setupArticleWithContent(t *testing.T) (*core.Handler, int64) {
+	t.Helper()
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db init failed: %v", err)
+	}
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "T", URL: "http://example.com/feed"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	art := &models.Article{
+		FeedID:      feedID,
+		Title:       "Machine learning",
+		URL:         "http://example.com/article/1",
+		PublishedAt: time.Now(),
+	}
+	if err := db.SaveArticle(art); err != nil {
+		t.Fatalf("SaveArticle failed: %v", err)
+	}
+
+	var articleID int64
+	if err := db.QueryRow("SELECT id FROM articles WHERE url = ?", art.URL).Scan(&articleID); err != nil {
+		t.Fatalf("failed to query article id: %v", err)
+	}
+	if err := db.SetArticleContent(articleID, testArticleContent); err != nil {
+		t.Fatalf("SetArticleContent failed: %v", err)
+	}
+
+	return core.NewHandler(db, feed.NewFetcher(db, nil), nil), articleID
+}
+
+func TestHandleArticleHighlights(t *testing.T) {
+	h, articleID := setupArticleWithContent(t)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/highlights?id=%d&length=short", articleID), nil)
+	rr := httptest.NewRecorder()
+	HandleArticleHighlights(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d; body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var result summary.HighlightResult
+	if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Highlights) == 0 {
+		t.Fatalf("expected highlights, got %+v", result)
+	}
+	runes := []rune(testArticleContent)
+	for _, hl := range result.Highlights {
+		if hl.Start < 0 || hl.End > len(runes) || hl.Start >= hl.End {
+			t.Errorf("invalid highlight offsets: %+v", hl)
+		}
+	}
+}
+
+func TestHandleArticleHighlights_InvalidLength(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/highlights?id=1&length=bad", nil)
+	rr := httptest.NewRecorder()
+
+	// Length validation happens before content access
+	HandleArticleHighlights(nil, rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestHandleArticleTags(t *testing.T) {
+	h, articleID := setupArticleWithContent(t)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/tags?id=%d", articleID), nil)
+	rr := httptest.NewRecorder()
+	HandleArticleTags(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d got %d; body: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var resp struct {
+		Tags []models.ArticleTag `json:"tags"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	found := false
+	for _, tag := range resp.Tags {
+		if tag.Tag == "training data" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected training data tag, got %+v", resp.Tags)
+	}
+
+	// Tags are stored and articles can be found by tag
+	req = httptest.NewRequest(http.MethodGet, "/api/articles/by-tag?tag=training+data", nil)
+	rr = httptest.NewRecorder()
+	HandleArticlesByTag(h, rr, req)
+
+	var articles []models.Article
+	if err := json.NewDecoder(rr.Body).Decode(&articles); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(articles) != 1 || articles[0].ID != articleID {
+		t.Fatalf("expected the tagged article, got %+v", articles)
+	}
+}
@@ -5,7 +5,10 @@ import (
 	"log"
 	"net/http"
 
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/feed"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
 	"MrRSS/internal/summary"
 	"MrRSS/internal/utils"
 )
@@ -42,6 +45,18 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Resolve the feed's summary policy. A per-feed length override wins over
+	// the requested length, which the frontend fills from the global setting.
+	var articleFeed *models.Feed
+	if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil {
+		articleFeed, _ = h.DB.GetFeedByID(article.FeedID)
+	}
+	policy := feed.ResolveAIPolicy(h.DB, articleFeed)
+	feedOverridesLength := articleFeed != nil && articleFeed.SummaryLength != "" && articleFeed.SummaryLength != "global"
+	if req.Length == "" || feedOverridesLength {
+		summaryLength = policy.SummaryLengthValue()
+	}
+
 	// Check if article already has a cached summary in database
 	// If content is provided (for on-the-fly summarization), skip this check
 	if req.Content == "" {
@@ -69,6 +84,8 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 	}
 
 	if content == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"summary":      "",
 			"is_too_short": true,
@@ -77,11 +94,8 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	// Get summary provider from settings (with default)
-	provider, err := h.DB.GetSetting("summary_provider")
-	if err != nil || provider == "" {
-		provider = "local" // Default to local algorithm
-	}
+	// Get summary provider from the feed policy (falls back to global settings, default local)
+	provider := policy.SummaryProvider
 
 	var result summary.SummaryResult
 	usedFallback := false
@@ -132,7 +146,7 @@ func HandleSummarizeArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 			} else {
 				result = aiResult
 				// Track AI usage only on success
-				h.AITracker.TrackSummary(content, result.Summary)
+				h.AITracker.TrackUsage(aiusage.FeatureSummary, result.Usage, content, result.Summary)
 			}
 		}
 	} else {
@@ -4,9 +4,13 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	"MrRSS/internal/aiusage"
+	"MrRSS/internal/database"
 	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/langdetect"
 	"MrRSS/internal/translation"
 	"MrRSS/internal/utils"
 )
@@ -29,11 +33,57 @@ func HandleTranslateArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Apply the feed's translation policy: feeds can disable translation
+	// entirely or translate into a different target language
+	lang := ""
+	if article, err := h.DB.GetArticleByID(req.ArticleID); err == nil {
+		lang = article.Lang
+		if feed, err := h.DB.GetFeedByID(article.FeedID); err == nil {
+			if feed.TranslationMode == "disabled" {
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"translated_title": req.Title,
+					"limit_reached":    false,
+					"skipped":          true,
+					"disabled":         true,
+				})
+				return
+			}
+			if feed.TranslationTargetLanguage != "" {
+				req.TargetLang = feed.TranslationTargetLanguage
+			}
+		}
+	}
+
 	if req.Title == "" || req.TargetLang == "" {
 		http.Error(w, "Missing required fields", http.StatusBadRequest)
 		return
 	}
 
+	// Skip translation when the article is already in the target language.
+	// The stored language was detected from title and content at fetch time;
+	// older articles fall back to detecting the title on the fly.
+	if lang == "" {
+		lang = langdetect.Detect(req.Title)
+		if lang != "" {
+			if err := h.DB.UpdateArticleLanguage(req.ArticleID, lang); err != nil {
+				log.Printf("Error updating article language: %v", err)
+			}
+		}
+	}
+	if langdetect.Matches(lang, req.TargetLang) {
+		// Store the original title so the frontend does not request it again
+		if err := h.DB.UpdateArticleTranslation(req.ArticleID, req.Title); err != nil {
+			log.Printf("Error updating article translation: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translated_title":  req.Title,
+			"limit_reached":     false,
+			"skipped":           true,
+			"detected_language": lang,
+		})
+		return
+	}
+
 	// Check if we should use AI translation or fallback to Google
 	provider, _ := h.DB.GetSetting("translation_provider")
 	isAIProvider := provider == "ai"
@@ -55,19 +105,18 @@ func HandleTranslateArticle(h *core.Handler, w http.ResponseWriter, r *http.Requ
 			h.AITracker.WaitForRateLimit()
 
 			// Try AI translation first
-			translatedTitle, err = h.Translator.Translate(req.Title, req.TargetLang)
+			var usage aiusage.Usage
+			translatedTitle, usage, err = translation.TranslateWithUsage(h.Translator, req.Title, req.TargetLang)
 
-			// If AI fails, fallback to Google Translate
-			if err != nil {
+			if err == nil {
+				// Track AI usage only when the AI request succeeded
+				h.AITracker.TrackUsage(aiusage.FeatureTranslation, usage, req.Title, translatedTitle)
+			} else {
+				// If AI fails, fallback to Google Translate
 				log.Printf("AI translation failed, falling back to Google Translate: %v", err)
 				googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
 				translatedTitle, err = googleTranslator.Translate(req.Title, req.TargetLang)
 			}
-
-			// Track AI usage only on success (whether AI or fallback)
-			if err == nil {
-				h.AITracker.TrackTranslation(req.Title, translatedTitle)
-			}
 		}
 	} else {
 		// Non-AI provider, no special handling needed
@@ -133,6 +182,17 @@ func HandleTranslateText(h *core.Handler, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// Skip translation when the text (e.g. content or summary) is already in the target language
+	if lang := langdetect.Detect(req.Text); langdetect.Matches(lang, req.TargetLang) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translated_text":   req.Text,
+			"html":              utils.ConvertMarkdownToHTML(req.Text),
+			"skipped":           true,
+			"detected_language": lang,
+		})
+		return
+	}
+
 	// Check if we should use AI translation or fallback to Google
 	provider, _ := h.DB.GetSetting("translation_provider")
 	isAIProvider := provider == "ai"
@@ -152,19 +212,18 @@ func HandleTranslateText(h *core.Handler, w http.ResponseWriter, r *http.Request
 			h.AITracker.WaitForRateLimit()
 
 			// Try AI translation first
-			translatedText, err = h.Translator.Translate(req.Text, req.TargetLang)
+			var usage aiusage.Usage
+			translatedText, usage, err = translation.TranslateWithUsage(h.Translator, req.Text, req.TargetLang)
 
-			// If AI fails, fallback to Google Translate
-			if err != nil {
+			if err == nil {
+				// Track AI usage only when the AI request succeeded
+				h.AITracker.TrackUsage(aiusage.FeatureTranslation, usage, req.Text, translatedText)
+			} else {
+				// If AI fails, fallback to Google Translate
 				log.Printf("AI translation failed, falling back to Google Translate: %v", err)
 				googleTranslator := translation.NewGoogleFreeTranslatorWithDB(h.DB)
 				translatedText, err = googleTranslator.Translate(req.Text, req.TargetLang)
 			}
-
-			// Track AI usage only on success (whether AI or fallback)
-			if err == nil {
-				h.AITracker.TrackTranslation(req.Text, translatedText)
-			}
 		}
 	} else {
 		// Non-AI provider, no special handling needed
@@ -217,6 +276,85 @@ func HandleGetAIUsage(h *core.Handler, w http.ResponseWriter, r *http.Request) {
 		"usage":         usage,
 		"limit":         limit,
 		"limit_reached": h.AITracker.IsLimitReached(),
+		"budget":        h.AITracker.GetBudgetStatus(),
+	})
+}
+
+// aiUsageDay is the usage of all AI features on one day.
+type aiUsageDay struct {
+	Date             string                           `json:"date"`
+	Requests         int64                            `json:"requests"`
+	PromptTokens     int64                            `json:"prompt_tokens"`
+	CompletionTokens int64                            `json:"completion_tokens"`
+	Cost             float64                          `json:"cost"`
+	Features         map[string]database.AIUsageDaily `json:"features"`
+}
+
+// HandleGetAIUsageHistory returns daily AI usage aggregates from the usage ledger.
+// The optional "days" query parameter selects the period (default 30, max 365).
+// Days without usage are included with zero values.
+func HandleGetAIUsageHistory(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	days := 30
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 {
+			http.Error(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+	if days > 365 {
+		days = 365
+	}
+
+	now := time.Now()
+	since := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -(days - 1))
+
+	rows, err := h.DB.GetAIUsageDaily(since)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	history := make([]*aiUsageDay, 0, days)
+	byDate := make(map[string]*aiUsageDay, days)
+	for i := 0; i < days; i++ {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		day := &aiUsageDay{Date: date, Features: make(map[string]database.AIUsageDaily)}
+		history = append(history, day)
+		byDate[date] = day
+	}
+
+	var total aiUsageDay
+	for _, row := range rows {
+		day, ok := byDate[row.Date]
+		if !ok {
+			continue
+		}
+		day.Requests += row.Requests
+		day.PromptTokens += row.PromptTokens
+		day.CompletionTokens += row.CompletionTokens
+		day.Cost += row.Cost
+		day.Features[row.Feature] = row
+
+		total.Requests += row.Requests
+		total.PromptTokens += row.PromptTokens
+		total.CompletionTokens += row.CompletionTokens
+		total.Cost += row.Cost
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"days":                    history,
+		"total_requests":          total.Requests,
+		"total_prompt_tokens":     total.PromptTokens,
+		"total_completion_tokens": total.CompletionTokens,
+		"total_cost":              total.Cost,
+		"budget":                  h.AITracker.GetBudgetStatus(),
 	})
 }
 
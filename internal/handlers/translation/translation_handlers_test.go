@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/database"
 	corepkg "MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
 	transpkg "MrRSS/internal/translation"
 )
 
@@ -123,3 +125,161 @@ func TestHandleClearTranslations(t *testing.T) {
 		t.Fatalf("expected 0 translations remaining, got %d", count)
 	}
 }
+
+func TestHandleTranslateArticle_SkipsTargetLanguage(t *testing.T) {
+	db := setupDB(t)
+
+	res, err := db.Exec("INSERT INTO articles (feed_id, title, url, published_at) VALUES (1, 't', 'u', datetime('now'))")
+	if err != nil {
+		t.Fatalf("insert article failed: %v", err)
+	}
+	id, _ := res.LastInsertId()
+
+	h := &corepkg.Handler{DB: db, Translator: transpkg.NewMockTranslator()}
+
+	title := "苹果公司今天发布了新款手机"
+	body := map[string]interface{}{"article_id": id, "title": title, "target_language": "zh"}
+	b, _ := json.Marshal(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/translate/article", bytes.NewReader(b))
+	rr := httptest.NewRecorder()
+
+	HandleTranslateArticle(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if resp["translated_title"] != title {
+		t.Fatalf("expected original title, got %v", resp["translated_title"])
+	}
+	if resp["skipped"] != true {
+		t.Fatalf("expected skipped=true, got %v", resp["skipped"])
+	}
+
+	var stored, lang string
+	if err := db.QueryRow("SELECT translated_title, lang FROM articles WHERE id = ?", id).Scan(&stored, &lang); err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if stored != title {
+		t.Fatalf("db value mismatch: %v", stored)
+	}
+	if lang != "zh" {
+		t.Fatalf("expected detected language zh, got %q", lang)
+	}
+}
+
+func TestHandleTranslateArticle_FeedPolicy(t *testing.T) {
+	db := setupDB(t)
+
+	disabledFeed, err := db.AddFeed(&models.Feed{Title: "EN", URL: "http://example.com/en"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.UpdateFeedAIPolicy(disabledFeed, "disabled", "", "global", "global", false); err != nil {
+		t.Fatalf("UpdateFeedAIPolicy error: %v", err)
+	}
+	overrideFeed, err := db.AddFeed(&models.Feed{Title: "JA", URL: "http://example.com/ja"})
+	if err != nil {
+		t.Fatalf("AddFeed error: %v", err)
+	}
+	if err := db.UpdateFeedAIPolicy(overrideFeed, "enabled", "de", "global", "global", false); err != nil {
+		t.Fatalf("UpdateFeedAIPolicy error: %v", err)
+	}
+
+	h := &corepkg.Handler{DB: db, Translator: transpkg.NewMockTranslator()}
+
+	translate := func(feedID int64, title string) map[string]interface{} {
+		res, err := db.Exec("INSERT INTO articles (feed_id, title, url, published_at) VALUES (?, ?, 'u', datetime('now'))", feedID, title)
+		if err != nil {
+			t.Fatalf("insert article failed: %v", err)
+		}
+		id, _ := res.LastInsertId()
+
+		b, _ := json.Marshal(map[string]interface{}{"article_id": id, "title": title, "target_language": "fr"})
+		req := httptest.NewRequest(http.MethodPost, "/translate/article", bytes.NewReader(b))
+		rr := httptest.NewRecorder()
+		HandleTranslateArticle(h, rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 got %d", rr.Code)
+		}
+		var resp map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := translate(disabledFeed, "Hello")
+	if resp["translated_title"] != "Hello" || resp["disabled"] != true {
+		t.Fatalf("expected translation to be disabled for feed, got %v", resp)
+	}
+
+	resp = translate(overrideFeed, "こんにちは")
+	if resp["translated_title"] != "[DE] こんにちは" {
+		t.Fatalf("expected feed target language to be used, got %v", resp["translated_title"])
+	}
+}
+
+func TestHandleGetAIUsageHistory(t *testing.T) {
+	db := setupDB(t)
+	db.SetSetting("ai_cost_budget", "1")
+
+	if err := db.RecordAIUsage("translation", "api.openai.com", "gpt-4o-mini", 100, 20, 0.25, false); err != nil {
+		t.Fatalf("RecordAIUsage error: %v", err)
+	}
+	if err := db.RecordAIUsage("summary", "api.openai.com", "gpt-4o-mini", 300, 80, 0.5, true); err != nil {
+		t.Fatalf("RecordAIUsage error: %v", err)
+	}
+
+	h := &corepkg.Handler{DB: db, Translator: transpkg.NewMockTranslator(), AITracker: aiusage.NewTracker(db)}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/ai-usage/history?days=7", nil)
+	rr := httptest.NewRecorder()
+	HandleGetAIUsageHistory(h, rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 got %d", rr.Code)
+	}
+
+	var resp struct {
+		Days []struct {
+			Date         string                     `json:"date"`
+			Requests     int64                      `json:"requests"`
+			PromptTokens int64                      `json:"prompt_tokens"`
+			Features     map[string]json.RawMessage `json:"features"`
+		} `json:"days"`
+		TotalRequests int64                `json:"total_requests"`
+		TotalCost     float64              `json:"total_cost"`
+		Budget        aiusage.BudgetStatus `json:"budget"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+
+	if len(resp.Days) != 7 {
+		t.Fatalf("expected 7 days, got %d", len(resp.Days))
+	}
+	today := resp.Days[len(resp.Days)-1]
+	if today.Requests != 2 || today.PromptTokens != 400 || len(today.Features) != 2 {
+		t.Fatalf("unexpected aggregate for today: %+v", today)
+	}
+	if resp.TotalRequests != 2 || resp.TotalCost != 0.75 {
+		t.Fatalf("unexpected totals: %d requests, cost %v", resp.TotalRequests, resp.TotalCost)
+	}
+	if resp.Budget.Level != aiusage.BudgetLevelOK || resp.Budget.Spent != 0.75 {
+		t.Fatalf("unexpected budget status: %+v", resp.Budget)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/ai-usage/history?days=abc", nil)
+	rr = httptest.NewRecorder()
+	HandleGetAIUsageHistory(h, rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid days, got %d", rr.Code)
+	}
+}
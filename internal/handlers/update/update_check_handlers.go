@@ -13,6 +13,16 @@ import (
 	"MrRSS/internal/version"
 )
 
+// writeUpdateError writes a failed update check with its status
+func writeUpdateError(w http.ResponseWriter, status int, currentVersion, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"current_version": currentVersion,
+		"error":           message,
+	})
+}
+
 // HandleCheckUpdates checks for the latest stable version on GitHub.
 // Pre-release versions (alpha, beta) are filtered out.
 func HandleCheckUpdates(h *core.Handler, w http.ResponseWriter, r *http.Request) {
@@ -41,30 +51,21 @@ func HandleCheckUpdates(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	client, err := utils.CreateHTTPClient(proxyURL, 30*time.Second)
 	if err != nil {
 		log.Printf("Error creating HTTP client: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"current_version": currentVersion,
-			"error":           "Failed to create HTTP client",
-		})
+		writeUpdateError(w, http.StatusInternalServerError, currentVersion, "Failed to create HTTP client")
 		return
 	}
 
 	resp, err := client.Get(githubAPI)
 	if err != nil {
 		log.Printf("Error checking for updates: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"current_version": currentVersion,
-			"error":           "Failed to check for updates",
-		})
+		writeUpdateError(w, http.StatusBadGateway, currentVersion, "Failed to check for updates")
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		log.Printf("GitHub API returned status: %d", resp.StatusCode)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"current_version": currentVersion,
-			"error":           "Failed to fetch releases",
-		})
+		writeUpdateError(w, http.StatusBadGateway, currentVersion, "Failed to fetch releases")
 		return
 	}
 
@@ -86,10 +87,7 @@ func HandleCheckUpdates(h *core.Handler, w http.ResponseWriter, r *http.Request)
 	var releases []Release
 	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
 		log.Printf("Error decoding releases info: %v", err)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"current_version": currentVersion,
-			"error":           "Failed to parse release information",
-		})
+		writeUpdateError(w, http.StatusBadGateway, currentVersion, "Failed to parse release information")
 		return
 	}
 
@@ -111,10 +109,7 @@ func HandleCheckUpdates(h *core.Handler, w http.ResponseWriter, r *http.Request)
 
 	if !found {
 		log.Printf("No stable releases found")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"current_version": currentVersion,
-			"error":           "No stable release available",
-		})
+		writeUpdateError(w, http.StatusNotFound, currentVersion, "No stable release available")
 		return
 	}
 
@@ -0,0 +1,104 @@
+package websub
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"MrRSS/internal/handlers/core"
+	"MrRSS/internal/models"
+	"MrRSS/internal/websub"
+)
+
+// SubscriptionsResponse lists the WebSub subscriptions of feeds
+type SubscriptionsResponse struct {
+	Enabled       bool                        `json:"enabled"`
+	CallbackURL   string                      `json:"callback_url,omitempty"` // Base of the callback URLs given to hubs
+	Subscriptions []models.WebSubSubscription `json:"subscriptions"`
+}
+
+// HandleCallback serves the callback URL of WebSub subscriptions. Hubs verify
+// subscriptions with GET requests and push new content with POST requests.
+func HandleCallback(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if h.WebSub == nil {
+		http.Error(w, "WebSub is not available", http.StatusNotFound)
+		return
+	}
+	feedID, err := strconv.ParseInt(r.URL.Query().Get("feed"), 10, 64)
+	if err != nil {
+		http.Error(w, "Invalid feed ID", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		challenge, ok := h.WebSub.Verify(feedID, r.URL.Query())
+		if !ok {
+			http.Error(w, "Subscription not requested", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(challenge))
+
+	case http.MethodPost:
+		body, err := io.ReadAll(io.LimitReader(r.Body, websub.MaxPushSize+1))
+		if err != nil {
+			http.Error(w, "Failed to read content", http.StatusBadRequest)
+			return
+		}
+		if len(body) > websub.MaxPushSize {
+			http.Error(w, "Content too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		err = h.WebSub.Push(r.Context(), feedID, body, r.Header.Get("X-Hub-Signature"))
+		switch {
+		case errors.Is(err, websub.ErrUnknownSubscription):
+			// Gone tells the hub to stop pushing to this callback
+			http.Error(w, "No subscription for this feed", http.StatusGone)
+			return
+		case errors.Is(err, websub.ErrInvalidSignature):
+			// The content is ignored, but acknowledged as the protocol requires
+			log.Printf("Ignoring WebSub push for feed %d: %v", feedID, err)
+		case err != nil:
+			log.Printf("Error saving WebSub push for feed %d: %v", feedID, err)
+			http.Error(w, "Failed to save content", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleSubscriptions lists the WebSub subscriptions of feeds and their state
+func HandleSubscriptions(h *core.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	subs, err := h.DB.GetWebSubSubscriptions()
+	if err != nil {
+		log.Printf("Error getting websub subscriptions: %v", err)
+		http.Error(w, "Failed to get subscriptions", http.StatusInternalServerError)
+		return
+	}
+	resp := SubscriptionsResponse{Subscriptions: subs}
+	if resp.Subscriptions == nil {
+		resp.Subscriptions = []models.WebSubSubscription{}
+	}
+	if h.WebSub != nil {
+		if base := h.WebSub.CallbackBase(); base != "" {
+			resp.Enabled = true
+			resp.CallbackURL = base + websub.CallbackPath
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
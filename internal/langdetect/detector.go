@@ -0,0 +1,267 @@
+// Package langdetect provides a small, offline language identifier.
+// It combines Unicode script analysis (for CJK, Cyrillic, Arabic, etc.) with
+// word and character n-gram scoring for Latin-script languages. It is designed
+// for short texts such as article titles and never performs network requests.
+package langdetect
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// MinLatinLetters is the minimum number of Latin letters required before a
+// Latin-script language is reported. Shorter texts are too ambiguous.
+const MinLatinLetters = 12
+
+// MinScriptLetters is the minimum number of letters required before a
+// non-Latin script language is reported.
+const MinScriptLetters = 2
+
+// maxSampleRunes limits how much text is analysed to keep detection cheap
+const maxSampleRunes = 2000
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// scriptLanguages are the languages identified by their script rather than by a Latin profile
+var scriptLanguages = []string{"zh", "ja", "ko", "ru", "uk", "ar", "fa", "el", "he", "th", "hi"}
+
+// Languages returns the codes of all the languages Detect can report
+func Languages() []string {
+	langs := make([]string, 0, len(latinOrder)+len(scriptLanguages))
+	langs = append(langs, latinOrder...)
+	return append(langs, scriptLanguages...)
+}
+
+// scriptCounts holds the number of letters per Unicode script in a text
+type scriptCounts struct {
+	latin      int
+	han        int
+	kana       int
+	hangul     int
+	cyrillic   int
+	arabic     int
+	greek      int
+	hebrew     int
+	thai       int
+	devanagari int
+	total      int
+}
+
+// Detect returns the ISO 639-1 code of the dominant language of text,
+// or an empty string when the language cannot be determined reliably.
+func Detect(text string) string {
+	lang, _ := DetectWithConfidence(text)
+	return lang
+}
+
+// DetectWithConfidence returns the detected language code together with a
+// confidence between 0 and 1. An empty code is returned with zero confidence
+// when the text is too short or ambiguous.
+func DetectWithConfidence(text string) (string, float64) {
+	text = prepareText(text)
+	if text == "" {
+		return "", 0
+	}
+
+	counts := countScripts(text)
+	if counts.total == 0 {
+		return "", 0
+	}
+
+	// Non-Latin scripts are identified by their dominant script
+	if lang, confidence := detectByScript(text, counts); lang != "" {
+		return lang, confidence
+	}
+
+	if counts.latin < MinLatinLetters {
+		return "", 0
+	}
+
+	return detectLatin(text)
+}
+
+// prepareText strips HTML markup and limits the sample size
+func prepareText(text string) string {
+	if strings.Contains(text, "<") {
+		text = htmlTagRegex.ReplaceAllString(text, " ")
+	}
+	text = strings.TrimSpace(text)
+
+	runes := []rune(text)
+	if len(runes) > maxSampleRunes {
+		text = string(runes[:maxSampleRunes])
+	}
+	return text
+}
+
+// countScripts counts letters by Unicode script
+func countScripts(text string) scriptCounts {
+	var c scriptCounts
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		c.total++
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			c.kana++
+		case unicode.Is(unicode.Han, r):
+			c.han++
+		case unicode.Is(unicode.Hangul, r):
+			c.hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			c.cyrillic++
+		case unicode.Is(unicode.Arabic, r):
+			c.arabic++
+		case unicode.Is(unicode.Greek, r):
+			c.greek++
+		case unicode.Is(unicode.Hebrew, r):
+			c.hebrew++
+		case unicode.Is(unicode.Thai, r):
+			c.thai++
+		case unicode.Is(unicode.Devanagari, r):
+			c.devanagari++
+		case unicode.Is(unicode.Latin, r):
+			c.latin++
+		}
+	}
+	return c
+}
+
+// detectByScript identifies languages written in a distinctive script.
+// Latin text mixed with a few foreign words is left to the Latin detector.
+func detectByScript(text string, c scriptCounts) (string, float64) {
+	nonLatin := c.total - c.latin
+	if nonLatin < MinScriptLetters {
+		return "", 0
+	}
+
+	// Titles like "iPhone 16 发布" are still Chinese even though Latin letters
+	// dominate by count, so CJK scripts only need a modest share of the text.
+	cjk := c.han + c.kana + c.hangul
+	share := func(n int) float64 { return float64(n) / float64(c.total) }
+
+	if cjk > 0 && share(cjk) >= 0.2 {
+		switch {
+		case c.kana > 0 && float64(c.kana) >= float64(cjk)*0.1:
+			return "ja", share(cjk)
+		case c.hangul >= c.han:
+			return "ko", share(cjk)
+		default:
+			return "zh", share(cjk)
+		}
+	}
+
+	if share(nonLatin) < 0.5 {
+		return "", 0
+	}
+
+	switch {
+	case c.cyrillic >= nonLatin/2 && c.cyrillic > 0:
+		if strings.ContainsAny(text, "іїєґІЇЄҐ") {
+			return "uk", share(c.cyrillic)
+		}
+		return "ru", share(c.cyrillic)
+	case c.arabic >= nonLatin/2 && c.arabic > 0:
+		if strings.ContainsAny(text, "پچژگ") {
+			return "fa", share(c.arabic)
+		}
+		return "ar", share(c.arabic)
+	case c.greek >= nonLatin/2 && c.greek > 0:
+		return "el", share(c.greek)
+	case c.hebrew >= nonLatin/2 && c.hebrew > 0:
+		return "he", share(c.hebrew)
+	case c.thai >= nonLatin/2 && c.thai > 0:
+		return "th", share(c.thai)
+	case c.devanagari >= nonLatin/2 && c.devanagari > 0:
+		return "hi", share(c.devanagari)
+	}
+
+	return "", 0
+}
+
+// detectLatin scores Latin-script text against per-language word and
+// character n-gram profiles and returns the best match.
+func detectLatin(text string) (string, float64) {
+	lower := strings.ToLower(text)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	if len(words) == 0 {
+		return "", 0
+	}
+
+	scores := make(map[string]float64, len(latinProfiles))
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		for lang, profile := range latinProfiles {
+			if profile.words[word] {
+				scores[lang] += 2
+			}
+		}
+	}
+
+	padded := " " + strings.Join(words, " ") + " "
+	for lang, profile := range latinProfiles {
+		for _, gram := range profile.ngrams {
+			scores[lang] += 0.5 * float64(strings.Count(padded, gram))
+		}
+		for _, ch := range profile.letters {
+			if strings.ContainsRune(lower, ch) {
+				scores[lang] += 1.5
+			}
+		}
+	}
+
+	best, second := "", ""
+	for _, lang := range latinOrder {
+		if best == "" || scores[lang] > scores[best] {
+			second = best
+			best = lang
+		} else if second == "" || scores[lang] > scores[second] {
+			second = lang
+		}
+	}
+
+	if scores[best] < 2 {
+		return "", 0
+	}
+
+	confidence := 1.0
+	if scores[second] > 0 {
+		confidence = 1 - scores[second]/scores[best]
+	}
+	// Require a clear margin so that ambiguous titles are left undetected
+	if confidence < 0.15 {
+		return "", 0
+	}
+	return best, confidence
+}
+
+// Normalize reduces a language tag such as "zh-CN", "pt_BR" or "EN" to its
+// lowercase ISO 639-1 base code.
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if idx := strings.IndexAny(lang, "-_"); idx != -1 {
+		lang = lang[:idx]
+	}
+	return lang
+}
+
+// Matches reports whether a detected language code refers to the same
+// language as target. Unknown (empty) languages never match.
+func Matches(detected, target string) bool {
+	detected = Normalize(detected)
+	if detected == "" {
+		return false
+	}
+	return detected == Normalize(target)
+}
+
+// IsInLanguage reports whether text is confidently written in the target
+// language. It is used to skip translation requests that would return the
+// source text unchanged.
+func IsInLanguage(text, target string) bool {
+	return Matches(Detect(text), target)
+}
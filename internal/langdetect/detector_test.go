@@ -0,0 +1,125 @@
+package langdetect
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetect_Scripts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"chinese", "苹果发布新款 iPhone，售价上涨", "zh"},
+		{"japanese", "東京で新しいカフェがオープンしました", "ja"},
+		{"korean", "서울에서 새로운 전시회가 열렸습니다", "ko"},
+		{"russian", "Новая версия операционной системы вышла сегодня", "ru"},
+		{"ukrainian", "Київ готується до зими і відновлює енергетику", "uk"},
+		{"arabic", "أعلنت الشركة عن منتج جديد اليوم", "ar"},
+		{"greek", "Η κυβέρνηση ανακοίνωσε νέα μέτρα", "el"},
+		{"persian", "پژوهشگران ایرانی گزارش جدیدی منتشر کردند", "fa"},
+		{"hebrew", "הממשלה הודיעה על צעדים חדשים", "he"},
+		{"thai", "รัฐบาลประกาศมาตรการใหม่", "th"},
+		{"hindi", "सरकार ने नई नीति की घोषणा की", "hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if !slices.Contains(Languages(), tt.want) {
+				t.Errorf("Languages() misses %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_Latin(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"english", "How the new release improves the performance of your apps", "en"},
+		{"german", "Die Bundesregierung will die Steuern für Unternehmen senken", "de"},
+		{"french", "Le gouvernement annonce une nouvelle réforme des retraites", "fr"},
+		{"spanish", "El presidente anunció una nueva ley para la educación", "es"},
+		{"italian", "Il governo ha approvato la nuova legge sulla sanità", "it"},
+		{"portuguese", "O governo não aprovou a proposta de reforma da previdência", "pt"},
+		{"dutch", "Het kabinet wil de belasting voor een groot aantal bedrijven verlagen", "nl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if !slices.Contains(Languages(), tt.want) {
+				t.Errorf("Languages() misses %q", tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	langs := Languages()
+	seen := make(map[string]bool, len(langs))
+	for _, lang := range langs {
+		if seen[lang] {
+			t.Errorf("Languages() lists %q twice", lang)
+		}
+		seen[lang] = true
+	}
+	for lang := range latinProfiles {
+		if !seen[lang] {
+			t.Errorf("Languages() misses the Latin profile %q", lang)
+		}
+	}
+}
+
+func TestDetect_Undetermined(t *testing.T) {
+	for _, text := range []string{"", "   ", "iOS 18", "12345", "<p></p>"} {
+		if got := Detect(text); got != "" {
+			t.Errorf("Detect(%q) = %q, want empty", text, got)
+		}
+	}
+}
+
+func TestDetect_StripsHTML(t *testing.T) {
+	text := `<div class="content"><p>The quick update for the app is now available to everyone</p></div>`
+	if got := Detect(text); got != "en" {
+		t.Errorf("Detect() = %q, want en", got)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		detected string
+		target   string
+		want     bool
+	}{
+		{"zh", "zh", true},
+		{"zh", "zh-CN", true},
+		{"pt", "pt_BR", true},
+		{"en", "EN", true},
+		{"en", "zh", false},
+		{"", "en", false},
+	}
+
+	for _, tt := range tests {
+		if got := Matches(tt.detected, tt.target); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.detected, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestIsInLanguage(t *testing.T) {
+	if !IsInLanguage("苹果公司发布了新的产品", "zh") {
+		t.Error("Expected Chinese text to be detected as zh")
+	}
+	if IsInLanguage("Apple releases a new product for the holiday season", "zh") {
+		t.Error("Expected English text not to be detected as zh")
+	}
+}
@@ -0,0 +1,83 @@
+package langdetect
+
+import "strings"
+
+// latinProfile describes the distinctive features of a Latin-script language
+type latinProfile struct {
+	words   map[string]bool // Frequent function words
+	ngrams  []string        // Characteristic character n-grams (space marks word boundary)
+	letters []rune          // Letters that rarely appear in other supported languages
+}
+
+// latinOrder fixes iteration order so that ties are resolved deterministically
+var latinOrder = []string{"en", "de", "fr", "es", "it", "pt", "nl", "sv", "pl", "tr", "vi", "id"}
+
+var latinProfiles = map[string]latinProfile{
+	"en": {
+		words:  wordSet("the of and to in is was for that with on as are by this be from at or an it not have has how what why new you your will can we our their after about into more than over"),
+		ngrams: []string{" th", "the ", "ing ", " and ", "tion", " wh", "ed ", " of "},
+	},
+	"de": {
+		words:   wordSet("der die das und ist nicht ein eine einen mit von zu den dem des auf für im sich auch wie wird sind bei aus nach oder wir ich sie noch über zum zur werden hat"),
+		ngrams:  []string{"sch", "ich ", "cht", "ein", "ung ", " der ", "en ", "ie "},
+		letters: []rune{'ß', 'ä', 'ö', 'ü'},
+	},
+	"fr": {
+		words:   wordSet("le la les des du de et est un une en pour que qui dans pas sur au aux par avec ce cette il elle sont plus ne nous vous leur mais comme"),
+		ngrams:  []string{" le ", " la ", " les ", "ent ", "tion", " qu", "eau", "ais "},
+		letters: []rune{'ç', 'è', 'ê', 'à', 'ù', 'œ', 'î', 'ë'},
+	},
+	"es": {
+		words:   wordSet("el la los las de del y en que es un una por para con no se su al lo como más pero sus le ya o este esta son entre cuando muy sin sobre también"),
+		ngrams:  []string{" el ", " los ", "ción", "ado ", " que ", "ía ", "dad "},
+		letters: []rune{'ñ', '¿', '¡'},
+	},
+	"it": {
+		words:   wordSet("il lo la gli le di del della che è e un una per non con sono nel nella alla al sul sulla si come più ma anche questo questa dei delle degli ha hanno"),
+		ngrams:  []string{"zione", " il ", " di ", "ell", "gli ", " che ", "tto ", "uo"},
+		letters: []rune{'ì', 'ò'},
+	},
+	"pt": {
+		words:   wordSet("o a os as de do da dos das e em um uma que para com não no na por se mais como mas ao foi são seu sua ou já também pelo pela"),
+		ngrams:  []string{"ção", "ões", " não ", "nh", "lh", " do ", " da "},
+		letters: []rune{'ã', 'õ'},
+	},
+	"nl": {
+		words:  wordSet("de het een en van in is dat op te met voor niet zijn er aan ook als bij door maar om dan nog wordt naar uit over dit deze"),
+		ngrams: []string{"ij", " het ", "aa", "oe", " een ", "sch", "en "},
+	},
+	"sv": {
+		words:   wordSet("och att det som en är på för med av den till inte har de ett om var men jag så från kan"),
+		ngrams:  []string{" och ", "för", "ade ", "är "},
+		letters: []rune{'å'},
+	},
+	"pl": {
+		words:   wordSet("i w na z do że się nie jest to jak o od po co tak ale dla przez są czy jego jej"),
+		ngrams:  []string{"prz", "ie ", "cz", "sz", "ow"},
+		letters: []rune{'ł', 'ą', 'ę', 'ś', 'ż', 'ź', 'ć', 'ń'},
+	},
+	"tr": {
+		words:   wordSet("ve bir bu da de için ile çok daha gibi olarak olan ama en mi ne var yok"),
+		ngrams:  []string{"lar", "ler", "ın ", "yor"},
+		letters: []rune{'ğ', 'ı', 'ş'},
+	},
+	"vi": {
+		words:   wordSet("và của có là được cho không một những các trong với này người đã khi để"),
+		ngrams:  []string{"ng ", "nh", "ượ"},
+		letters: []rune{'ư', 'ơ', 'đ', 'ạ', 'ả', 'ế', 'ề', 'ộ'},
+	},
+	"id": {
+		words:  wordSet("yang dan di ini itu dengan untuk dari ke tidak ada akan pada juga dalam adalah oleh atau bisa"),
+		ngrams: []string{"ng ", "kan ", "nya ", " yang "},
+	},
+}
+
+// wordSet builds a lookup set from a space separated word list
+func wordSet(words string) map[string]bool {
+	fields := strings.Fields(words)
+	set := make(map[string]bool, len(fields))
+	for _, w := range fields {
+		set[w] = true
+	}
+	return set
+}
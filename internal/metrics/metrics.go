@@ -0,0 +1,230 @@
+// Package metrics collects the counters and histograms of feed fetches, AI requests
+// and FreshRSS syncs, and writes them in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Results of the counted operations
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+	ResultPartial = "partial" // FreshRSS sync that pulled but failed to push
+)
+
+// durationBuckets are the upper bounds in seconds of the duration histograms,
+// from quick cache hits to slow feeds and AI models
+var durationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+var (
+	feedFetches = newCounter("mrrss_feed_fetches_total",
+		"Feed fetch attempts by feed type and result.", "type", "result")
+	feedFetchDuration = newHistogram("mrrss_feed_fetch_duration_seconds",
+		"Duration of feed fetch attempts by feed type.", "type")
+
+	aiRequests = newCounter("mrrss_ai_requests_total",
+		"Requests to the AI endpoint by feature and result.", "feature", "result")
+	aiRequestDuration = newHistogram("mrrss_ai_request_duration_seconds",
+		"Latency of requests to the AI endpoint by feature.", "feature")
+	aiTokens = newCounter("mrrss_ai_tokens_total",
+		"AI tokens used by feature and kind, estimated when the API does not report them.", "feature", "kind")
+
+	freshRSSSyncs = newCounter("mrrss_freshrss_syncs_total",
+		"FreshRSS synchronizations by result.", "result")
+	freshRSSSyncDuration = newHistogram("mrrss_freshrss_sync_duration_seconds",
+		"Duration of FreshRSS synchronizations.")
+)
+
+// ObserveFetch records a feed fetch attempt of a feed type
+func ObserveFetch(feedType string, duration time.Duration, err error) {
+	feedFetches.inc(feedType, result(err))
+	feedFetchDuration.observe(duration.Seconds(), feedType)
+}
+
+// ObserveAIRequest records a request of an AI feature sent at start. Requests that
+// failed or got an error status are counted as errors.
+func ObserveAIRequest(feature string, start time.Time, resp *http.Response, err error) {
+	if err == nil && resp != nil && resp.StatusCode >= 400 {
+		err = fmt.Errorf("status %d", resp.StatusCode)
+	}
+	aiRequests.inc(feature, result(err))
+	aiRequestDuration.observe(time.Since(start).Seconds(), feature)
+}
+
+// AddAITokens records the tokens used by a request of an AI feature
+func AddAITokens(feature string, promptTokens, completionTokens int64) {
+	aiTokens.add(float64(promptTokens), feature, "prompt")
+	aiTokens.add(float64(completionTokens), feature, "completion")
+}
+
+// ObserveFreshRSSSync records a FreshRSS synchronization and whether its pull and
+// push stages succeeded
+func ObserveFreshRSSSync(pulled, pushed bool, duration time.Duration) {
+	r := ResultSuccess
+	if !pulled {
+		r = ResultError
+	} else if !pushed {
+		r = ResultPartial
+	}
+	freshRSSSyncs.inc(r)
+	freshRSSSyncDuration.observe(duration.Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// Write writes the collected metrics
+func Write(w io.Writer) {
+	for _, c := range []*counter{feedFetches, aiRequests, aiTokens, freshRSSSyncs} {
+		c.write(w)
+	}
+	for _, h := range []*histogram{feedFetchDuration, aiRequestDuration, freshRSSSyncDuration} {
+		h.write(w)
+	}
+}
+
+// WriteGauge writes a gauge measured when the metrics are scraped
+func WriteGauge(w io.Writer, name, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatValue(value))
+}
+
+// series is the value of a metric for a set of label values
+type series struct {
+	labels []string
+	value  float64
+}
+
+// counter is a counter metric with labels
+type counter struct {
+	name, help string
+	labelNames []string
+	mu         sync.Mutex
+	series     map[string]*series
+}
+
+func newCounter(name, help string, labelNames ...string) *counter {
+	return &counter{name: name, help: help, labelNames: labelNames, series: make(map[string]*series)}
+}
+
+func (c *counter) inc(labels ...string) {
+	c.add(1, labels...)
+}
+
+func (c *counter) add(v float64, labels ...string) {
+	key := strings.Join(labels, "\x00")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &series{labels: labels}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labelNames, s.labels, "", ""), formatValue(s.value))
+	}
+}
+
+// histogramSeries holds the observations of a histogram for a set of label values
+type histogramSeries struct {
+	labels []string
+	counts []uint64 // Observations in each bucket, not cumulated
+	sum    float64
+	count  uint64
+}
+
+// histogram is a histogram metric with labels and the durationBuckets
+type histogram struct {
+	name, help string
+	labelNames []string
+	mu         sync.Mutex
+	series     map[string]*histogramSeries
+}
+
+func newHistogram(name, help string, labelNames ...string) *histogram {
+	return &histogram{name: name, help: help, labelNames: labelNames, series: make(map[string]*histogramSeries)}
+}
+
+func (h *histogram) observe(v float64, labels ...string) {
+	key := strings.Join(labels, "\x00")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: labels, counts: make([]uint64, len(durationBuckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(durationBuckets, v); i < len(durationBuckets) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+}
+
+func (h *histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range durationBuckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, s.labels, "le", formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labelNames, s.labels, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labelNames, s.labels, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labelNames, s.labels, "", ""), s.count)
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels formats label pairs, with an extra pair such as the bucket bound if extraName is set
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, name+"="+strconv.Quote(values[i]))
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+"="+strconv.Quote(extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	ObserveFetch("xpath", 300*time.Millisecond, nil)
+	ObserveFetch("xpath", 3*time.Second, errors.New("timeout"))
+	ObserveAIRequest("summary", time.Now(), &http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	AddAITokens("summary", 120, 30)
+	ObserveFreshRSSSync(true, false, time.Second)
+
+	var buf bytes.Buffer
+	Write(&buf)
+	WriteGauge(&buf, "mrrss_test_gauge", "A gauge.", 0.5)
+	out := buf.String()
+
+	for _, line := range []string{
+		"# TYPE mrrss_feed_fetches_total counter",
+		`mrrss_feed_fetches_total{type="xpath",result="success"} 1`,
+		`mrrss_feed_fetches_total{type="xpath",result="error"} 1`,
+		"# TYPE mrrss_feed_fetch_duration_seconds histogram",
+		`mrrss_feed_fetch_duration_seconds_bucket{type="xpath",le="0.25"} 0`,
+		`mrrss_feed_fetch_duration_seconds_bucket{type="xpath",le="0.5"} 1`,
+		`mrrss_feed_fetch_duration_seconds_bucket{type="xpath",le="5"} 2`,
+		`mrrss_feed_fetch_duration_seconds_bucket{type="xpath",le="+Inf"} 2`,
+		`mrrss_feed_fetch_duration_seconds_sum{type="xpath"} 3.3`,
+		`mrrss_feed_fetch_duration_seconds_count{type="xpath"} 2`,
+		`mrrss_ai_requests_total{feature="summary",result="error"} 1`,
+		`mrrss_ai_tokens_total{feature="summary",kind="prompt"} 120`,
+		`mrrss_ai_tokens_total{feature="summary",kind="completion"} 30`,
+		`mrrss_freshrss_syncs_total{result="partial"} 1`,
+		`mrrss_freshrss_sync_duration_seconds_count 1`,
+		"# TYPE mrrss_test_gauge gauge\nmrrss_test_gauge 0.5",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Feed struct {
 	ID                 int64     `json:"id"`
@@ -34,6 +37,12 @@ type Feed struct {
 	XPathItemUid        string `json:"xpath_item_uid"`         // XPath to extract item unique ID
 	ArticleViewMode     string `json:"article_view_mode"`      // Article view mode override ('global', 'webpage', 'rendered')
 	AutoExpandContent   string `json:"auto_expand_content"`    // Auto expand content mode ('global', 'enabled', 'disabled')
+	// Per-feed translation and summary policy
+	TranslationMode           string `json:"translation_mode"`            // Translation override ('global', 'enabled', 'disabled')
+	TranslationTargetLanguage string `json:"translation_target_language"` // Target language override (empty = use global)
+	SummaryProvider           string `json:"summary_provider"`            // Summary provider override ('global', 'local', 'ai')
+	SummaryLength             string `json:"summary_length"`              // Summary length override ('global', 'short', 'medium', 'long')
+	PregenerateOnFetch        bool   `json:"pregenerate_on_fetch"`        // Translate and summarize new articles during refresh
 	// Email/Newsletter support
 	EmailAddress    string `json:"email_address,omitempty"`     // Email address for newsletter subscriptions
 	EmailIMAPServer string `json:"email_imap_server,omitempty"` // IMAP server address
@@ -45,6 +54,15 @@ type Feed struct {
 	// FreshRSS integration
 	IsFreshRSSSource bool   `json:"is_freshrss_source"` // Whether this feed is from FreshRSS sync
 	FreshRSSStreamID string `json:"freshrss_stream_id"` // FreshRSS stream ID (e.g., "feed/http://...")
+	// Feed health
+	IsPaused            bool       `json:"is_paused"`               // Whether scheduled refreshes skip this feed
+	IsGone              bool       `json:"is_gone"`                 // Whether the feed answered 410 Gone, which stops scheduled refreshes
+	ConsecutiveFailures int        `json:"consecutive_failures"`    // Refreshes failed in a row
+	BackoffUntil        *time.Time `json:"backoff_until,omitempty"` // Scheduled refreshes are skipped until then after repeated failures
+	// Refresh limits the feed asks for in its content
+	MinRefreshInterval int    `json:"min_refresh_interval,omitempty"` // Minutes, from <ttl> or sy:updatePeriod
+	SkipHours          string `json:"skip_hours,omitempty"`           // Comma-separated hours in GMT, from <skipHours>
+	SkipDays           string `json:"skip_days,omitempty"`            // Comma-separated day names, from <skipDays>
 	// Statistics
 	LatestArticleTime *time.Time `json:"latest_article_time,omitempty"` // Latest article publish time
 	ArticlesPerMonth  float64    `json:"articles_per_month,omitempty"`  // Average articles per month (last 90 days / 3)
@@ -70,4 +88,128 @@ type Article struct {
 	Summary               string    `json:"summary"`          // Cached AI-generated summary
 	UniqueID              string    `json:"unique_id"`        // Unique identifier for deduplication (title+feed_id+published_date)
 	FreshRSSItemID        string    `json:"freshrss_item_id"` // FreshRSS/Google Reader item ID for API operations
+	Lang                  string    `json:"lang"`             // Detected language (ISO 639-1 code, empty if unknown)
+	AlternateCount        int       `json:"alternate_count"`  // Number of near-duplicate articles clustered into this article's story
+}
+
+// ArticleTag is a tag attached to an article, such as a keyphrase extracted from its content
+type ArticleTag struct {
+	Tag    string  `json:"tag"`
+	Score  float64 `json:"score"`  // Relative relevance between 0 and 1
+	Source string  `json:"source"` // How the tag was created (e.g. "keyphrase")
+}
+
+// PodcastEpisode holds the podcast metadata of an article with an audio enclosure
+type PodcastEpisode struct {
+	ArticleID      int64  `json:"article_id"`
+	MimeType       string `json:"mime_type"`
+	FileSize       int64  `json:"file_size"`       // Enclosure length in bytes (0 if unknown)
+	Duration       int    `json:"duration"`        // Duration in seconds (0 if unknown)
+	Episode        int    `json:"episode"`         // itunes:episode (0 if not set)
+	Season         int    `json:"season"`          // itunes:season (0 if not set)
+	EpisodeType    string `json:"episode_type"`    // "full", "trailer" or "bonus" (empty if not set)
+	Explicit       bool   `json:"explicit"`        // itunes:explicit
+	ImageURL       string `json:"image_url"`       // Episode artwork
+	ChaptersURL    string `json:"chapters_url"`    // podcast:chapters JSON file
+	TranscriptURL  string `json:"transcript_url"`  // podcast:transcript file
+	TranscriptType string `json:"transcript_type"` // MIME type of the transcript
+}
+
+// PlaybackPosition is the stored playback position of a podcast episode
+type PlaybackPosition struct {
+	ArticleID int64     `json:"article_id"`
+	Position  float64   `json:"position"` // Seconds from the start
+	Completed bool      `json:"completed"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PodcastDownload is a podcast episode queued for download or stored on disk
+type PodcastDownload struct {
+	ArticleID      int64      `json:"article_id"`
+	URL            string     `json:"url"`
+	FileName       string     `json:"file_name,omitempty"`
+	Status         string     `json:"status"`          // "queued", "downloading", "completed" or "failed"
+	TotalSize      int64      `json:"total_size"`      // Size of the file in bytes (0 if unknown)
+	DownloadedSize int64      `json:"downloaded_size"` // Bytes stored on disk
+	Error          string     `json:"error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// QueuedEpisode is an entry of the Up Next queue of podcast episodes
+type QueuedEpisode struct {
+	Article  Article           `json:"article"`
+	Episode  *PodcastEpisode   `json:"episode,omitempty"`
+	Playback *PlaybackPosition `json:"playback,omitempty"`
+	Download *PodcastDownload  `json:"download,omitempty"`
+}
+
+// VideoThumbnail is one size of a video thumbnail
+type VideoThumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// VideoInfo holds the metadata of a video article
+type VideoInfo struct {
+	ArticleID      int64            `json:"article_id"`
+	VideoID        string           `json:"video_id"` // YouTube video ID, empty for other platforms
+	Duration       int              `json:"duration"` // Seconds (0 if unknown)
+	Views          int64            `json:"views"`
+	RatingCount    int64            `json:"rating_count"`
+	RatingAverage  float64          `json:"rating_average"`
+	Thumbnails     []VideoThumbnail `json:"thumbnails"`                // Sorted from smallest to largest
+	TranscriptLang string           `json:"transcript_lang,omitempty"` // Language of the captions, empty if none
+}
+
+// WebSubSubscription is the push subscription of a feed to the WebSub hub it advertises
+type WebSubSubscription struct {
+	FeedID         int64      `json:"feed_id"`
+	Hub            string     `json:"hub"`
+	Topic          string     `json:"topic"` // URL of the feed as advertised with rel="self"
+	Secret         string     `json:"-"`     // Key of the HMAC signatures of pushed content
+	State          string     `json:"state"` // "discovered", "pending", "active", "denied" or "failed"
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// PublishedFeed is an outgoing feed of articles, read by other readers with its token
+type PublishedFeed struct {
+	ID         int64           `json:"id"`
+	Title      string          `json:"title"`
+	Source     string          `json:"source"`               // "favorites", "read_later", "category" or "filter"
+	Category   string          `json:"category,omitempty"`   // Category of the "category" source, with its subcategories
+	Conditions json.RawMessage `json:"conditions,omitempty"` // Filter conditions of the "filter" source, as for rules
+	Limit      int             `json:"limit"`                // Number of newest articles published
+	Token      string          `json:"token"`                // Secret of the feed URLs
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// FeedFetch is a fetch attempt of a feed recorded in the fetch log
+type FeedFetch struct {
+	ID          int64     `json:"id"`
+	FeedID      int64     `json:"feed_id"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	Reason      string    `json:"reason"` // Why the feed was fetched, such as "scheduled_global"
+	DurationMs  int64     `json:"duration_ms"`
+	StatusCode  int       `json:"status_code,omitempty"` // HTTP status, 0 when the feed was not fetched over HTTP
+	Bytes       int64     `json:"bytes"`
+	NewItems    int       `json:"new_items"`
+	ErrorClass  string    `json:"error_class,omitempty"` // Class of the error, such as "timeout" or "http_5xx"
+	Error       string    `json:"error,omitempty"`
+	RedirectURL string    `json:"redirect_url,omitempty"` // Target of the permanent redirects followed, if any
+}
+
+// FeedChange is a change made to a feed automatically, which can be undone
+type FeedChange struct {
+	ID        int64      `json:"id"`
+	FeedID    int64      `json:"feed_id"`
+	FeedTitle string     `json:"feed_title"`
+	Kind      string     `json:"kind"`      // "redirect", "gone" or "backoff"
+	OldValue  string     `json:"old_value"` // Previous URL of a redirect
+	NewValue  string     `json:"new_value"` // New URL of a redirect
+	CreatedAt time.Time  `json:"created_at"`
+	UndoneAt  *time.Time `json:"undone_at,omitempty"` // When the change was undone, or the backoff ended
 }
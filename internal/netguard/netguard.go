@@ -0,0 +1,294 @@
+// Package netguard protects requests made on behalf of clients, such as the
+// media and webpage proxies, against server-side request forgery. Addresses
+// are checked when connecting, after DNS resolution, so redirects and DNS
+// rebinding cannot reach private networks either.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrBlockedAddress is returned when a request would connect to a private, loopback or link-local address
+	ErrBlockedAddress = errors.New("address is not allowed")
+	// ErrBlockedHost is returned when a host is denied or not in the allow list
+	ErrBlockedHost = errors.New("host is not allowed")
+	// ErrResponseTooLarge is returned when a response exceeds the size limit
+	ErrResponseTooLarge = errors.New("response is too large")
+	// ErrContentType is returned when a response has a content type that is not allowed
+	ErrContentType = errors.New("content type is not allowed")
+)
+
+// Ranges that are not covered by the net.IP helpers
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // "This" network
+	"100.64.0.0/10",  // Carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // Benchmarking
+	"240.0.0.0/4",    // Reserved
+	"64:ff9b:1::/48", // Local-use NAT64
+)
+
+// Policy decides which hosts and addresses may be requested
+type Policy struct {
+	// BlockPrivate rejects private, loopback, link-local and reserved addresses
+	BlockPrivate bool
+	// AllowedHosts restricts requests to these hosts if not empty. Entries are
+	// host names, which also match their subdomains, IP addresses or CIDR ranges.
+	// Addresses matching an entry are allowed even if they are private.
+	AllowedHosts []string
+	// DeniedHosts rejects these hosts, in the same format as AllowedHosts
+	DeniedHosts []string
+}
+
+// ParseHostList splits a comma, space or newline separated list of hosts
+func ParseHostList(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+	hosts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		hosts = append(hosts, strings.TrimPrefix(strings.ToLower(field), "*."))
+	}
+	return hosts
+}
+
+// IsPrivateIP reports whether ip is a private, loopback, link-local, multicast or reserved address
+func IsPrivateIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL checks the scheme and host of a URL. Addresses of host names are
+// checked when connecting.
+func (p *Policy) CheckURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("URL must use HTTP or HTTPS")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return errors.New("URL has no host")
+	}
+
+	if matchHost(p.DeniedHosts, host) {
+		return fmt.Errorf("%w: %s", ErrBlockedHost, host)
+	}
+	if len(p.AllowedHosts) > 0 && !matchHost(p.AllowedHosts, host) {
+		return fmt.Errorf("%w: %s", ErrBlockedHost, host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckIP(ip)
+	}
+	return nil
+}
+
+// CheckIP checks an address that is about to be connected to
+func (p *Policy) CheckIP(ip net.IP) error {
+	if matchIP(p.DeniedHosts, ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	if p.BlockPrivate && IsPrivateIP(ip) && !matchIP(p.AllowedHosts, ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// NewClient returns an HTTP client that enforces the policy on every request,
+// redirect and connection. proxyURL may be nil; connections to the proxy itself
+// are allowed, and target hosts are resolved and checked before the request
+// is handed to the proxy.
+func (p *Policy) NewClient(timeout time.Duration, proxyURL *url.URL) *http.Client {
+	var proxyAddrs map[string]bool
+	if proxyURL != nil {
+		proxyAddrs = resolveProxyAddrs(proxyURL)
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			if proxyAddrs[address] {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+			return p.CheckIP(ip)
+		},
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &guardedTransport{
+			policy:    p,
+			transport: transport,
+			viaProxy:  proxyURL != nil,
+		},
+	}
+}
+
+// guardedTransport checks every request, including each redirect, before sending it
+type guardedTransport struct {
+	policy    *Policy
+	transport http.RoundTripper
+	viaProxy  bool
+}
+
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.policy.CheckURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	// The proxy connects to the target, so its addresses cannot be checked when dialing
+	if t.viaProxy && net.ParseIP(req.URL.Hostname()) == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(req.Context(), req.URL.Hostname())
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			if err := t.policy.CheckIP(addr.IP); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+// ReadAll reads r up to maxBytes and returns ErrResponseTooLarge beyond it.
+// maxBytes <= 0 disables the limit.
+func ReadAll(r io.Reader, maxBytes int64) ([]byte, error) {
+	if maxBytes <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrResponseTooLarge
+	}
+	return data, nil
+}
+
+// IsMediaType reports whether a content type may be served by the media proxy.
+// Images, audio and video are allowed, as well as generic binary content that
+// some CDNs use for images.
+func IsMediaType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch {
+	case strings.HasPrefix(mediaType, "image/"),
+		strings.HasPrefix(mediaType, "audio/"),
+		strings.HasPrefix(mediaType, "video/"):
+		return true
+	case mediaType == "application/octet-stream", mediaType == "binary/octet-stream":
+		return true
+	}
+	return false
+}
+
+// matchHost reports whether host matches an entry of list by name, address or range
+func matchHost(list []string, host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return matchIP(list, ip)
+	}
+	for _, entry := range list {
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchIP reports whether ip matches an address or range of list
+func matchIP(list []string, ip net.IP) bool {
+	for _, entry := range list {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(ip) {
+				return true
+			}
+		} else if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProxyAddrs returns the addresses, as host:port, the dialer uses to reach the proxy
+func resolveProxyAddrs(proxyURL *url.URL) map[string]bool {
+	port := proxyURL.Port()
+	if port == "" {
+		switch proxyURL.Scheme {
+		case "https":
+			port = "443"
+		case "socks5", "socks5h":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+
+	addrs := make(map[string]bool)
+	host := proxyURL.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		addrs[net.JoinHostPort(ip.String(), port)] = true
+		return addrs
+	}
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return addrs
+	}
+	for _, ip := range ips {
+		addrs[net.JoinHostPort(ip.String(), port)] = true
+	}
+	return addrs
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
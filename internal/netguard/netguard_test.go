@@ -0,0 +1,128 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsPrivateIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		private bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+	}
+	for _, tt := range tests {
+		if got := IsPrivateIP(net.ParseIP(tt.ip)); got != tt.private {
+			t.Errorf("IsPrivateIP(%s) = %v, want %v", tt.ip, got, tt.private)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	policy := &Policy{
+		BlockPrivate: true,
+		AllowedHosts: ParseHostList("example.com, 10.0.0.0/8"),
+		DeniedHosts:  ParseHostList("*.bad.example.com"),
+	}
+	tests := []struct {
+		url string
+		err error
+	}{
+		{"https://example.com/a.png", nil},
+		{"https://cdn.example.com/a.png", nil},
+		{"http://10.1.2.3/a.png", nil},
+		{"https://other.org/a.png", ErrBlockedHost},
+		{"https://img.bad.example.com/a.png", ErrBlockedHost},
+		{"http://192.168.1.1/a.png", ErrBlockedHost},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if err := policy.CheckURL(u); !errors.Is(err, tt.err) {
+			t.Errorf("CheckURL(%s) = %v, want %v", tt.url, err, tt.err)
+		}
+	}
+
+	u, _ := url.Parse("http://169.254.169.254/latest/meta-data")
+	if err := (&Policy{BlockPrivate: true}).CheckURL(u); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("expected ErrBlockedAddress for metadata address, got %v", err)
+	}
+	u, _ = url.Parse("file:///etc/passwd")
+	if err := (&Policy{}).CheckURL(u); err == nil {
+		t.Error("expected file URL to be rejected")
+	}
+}
+
+func TestClientBlocksResolvedPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	// The host name passes the URL check; its address is rejected when connecting
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	client := (&Policy{BlockPrivate: true}).NewClient(5*time.Second, nil)
+	if _, err := client.Get(target); !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got %v", err)
+	}
+
+	// Allowed ranges may be private
+	client = (&Policy{BlockPrivate: true, AllowedHosts: []string{"localhost", "127.0.0.0/8", "::1"}}).NewClient(5*time.Second, nil)
+	resp, err := client.Get(target)
+	if err != nil {
+		t.Fatalf("expected allowed request to succeed, got %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestClientChecksRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.2"+r.Host[strings.LastIndex(r.Host, ":"):]+"/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := (&Policy{AllowedHosts: []string{"127.0.0.1"}}).NewClient(5*time.Second, nil)
+	if _, err := client.Get(server.URL); !errors.Is(err, ErrBlockedHost) {
+		t.Fatalf("expected redirect to be blocked, got %v", err)
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	if data, err := ReadAll(strings.NewReader("12345"), 5); err != nil || string(data) != "12345" {
+		t.Errorf("ReadAll() = %q, %v", data, err)
+	}
+	if _, err := ReadAll(strings.NewReader("123456"), 5); err != ErrResponseTooLarge {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestIsMediaType(t *testing.T) {
+	for _, contentType := range []string{"image/png", "video/mp4", "audio/mpeg; charset=binary", "application/octet-stream"} {
+		if !IsMediaType(contentType) {
+			t.Errorf("IsMediaType(%q) = false", contentType)
+		}
+	}
+	for _, contentType := range []string{"text/html; charset=utf-8", "application/json", ""} {
+		if IsMediaType(contentType) {
+			t.Errorf("IsMediaType(%q) = true", contentType)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package opml
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/models"
+)
+
+// Namespace is the XML namespace of the MrRSS feed configuration attributes.
+// Outlines carry the configuration of a feed as mrrss:* attributes, so that an
+// export/import cycle keeps it. Other readers ignore these attributes.
+const Namespace = "https://github.com/WCY-dt/MrRSS/opml"
+
+// namespacePrefix is the prefix bound to Namespace in generated documents
+const namespacePrefix = "mrrss"
+
+// GenerateOptions controls what is written by GenerateWithOptions
+type GenerateOptions struct {
+	// IncludeSecrets writes IMAP passwords and proxy credentials.
+	// They are left out by default because OPML files are often shared.
+	IncludeSecrets bool
+}
+
+// feedAttribute maps a feed field to an mrrss:* attribute
+type feedAttribute struct {
+	name   string
+	get    func(f *models.Feed) string
+	set    func(f *models.Feed, value string)
+	secret bool
+}
+
+var feedAttributes = []feedAttribute{
+	{name: "scriptPath", get: func(f *models.Feed) string { return f.ScriptPath }, set: func(f *models.Feed, v string) { f.ScriptPath = v }},
+	{name: "imageUrl", get: func(f *models.Feed) string { return f.ImageURL }, set: func(f *models.Feed, v string) { f.ImageURL = v }},
+	{name: "hideFromTimeline", get: func(f *models.Feed) string { return formatBool(f.HideFromTimeline) }, set: func(f *models.Feed, v string) { f.HideFromTimeline = parseBool(v) }},
+	{name: "proxyEnabled", get: func(f *models.Feed) string { return formatBool(f.ProxyEnabled) }, set: func(f *models.Feed, v string) { f.ProxyEnabled = parseBool(v) }},
+	{name: "proxyUrl", get: func(f *models.Feed) string { return f.ProxyURL }, set: func(f *models.Feed, v string) { f.ProxyURL = v }},
+	{name: "refreshInterval", get: func(f *models.Feed) string { return formatInt(f.RefreshInterval) }, set: func(f *models.Feed, v string) { f.RefreshInterval = parseInt(v) }},
+	{name: "imageMode", get: func(f *models.Feed) string { return formatBool(f.IsImageMode) }, set: func(f *models.Feed, v string) { f.IsImageMode = parseBool(v) }},
+	{name: "articleViewMode", get: func(f *models.Feed) string { return f.ArticleViewMode }, set: func(f *models.Feed, v string) { f.ArticleViewMode = v }},
+	{name: "autoExpandContent", get: func(f *models.Feed) string { return f.AutoExpandContent }, set: func(f *models.Feed, v string) { f.AutoExpandContent = v }},
+	{name: "translationMode", get: func(f *models.Feed) string { return f.TranslationMode }, set: func(f *models.Feed, v string) { f.TranslationMode = v }},
+	{name: "translationTargetLanguage", get: func(f *models.Feed) string { return f.TranslationTargetLanguage }, set: func(f *models.Feed, v string) { f.TranslationTargetLanguage = v }},
+	{name: "summaryProvider", get: func(f *models.Feed) string { return f.SummaryProvider }, set: func(f *models.Feed, v string) { f.SummaryProvider = v }},
+	{name: "summaryLength", get: func(f *models.Feed) string { return f.SummaryLength }, set: func(f *models.Feed, v string) { f.SummaryLength = v }},
+	{name: "pregenerateOnFetch", get: func(f *models.Feed) string { return formatBool(f.PregenerateOnFetch) }, set: func(f *models.Feed, v string) { f.PregenerateOnFetch = parseBool(v) }},
+	{name: "emailAddress", get: func(f *models.Feed) string { return f.EmailAddress }, set: func(f *models.Feed, v string) { f.EmailAddress = v }},
+	{name: "emailImapServer", get: func(f *models.Feed) string { return f.EmailIMAPServer }, set: func(f *models.Feed, v string) { f.EmailIMAPServer = v }},
+	{name: "emailImapPort", get: func(f *models.Feed) string { return formatInt(f.EmailIMAPPort) }, set: func(f *models.Feed, v string) { f.EmailIMAPPort = parseInt(v) }},
+	{name: "emailUsername", get: func(f *models.Feed) string { return f.EmailUsername }, set: func(f *models.Feed, v string) { f.EmailUsername = v }},
+	{name: "emailPassword", get: func(f *models.Feed) string { return f.EmailPassword }, set: func(f *models.Feed, v string) { f.EmailPassword = v }, secret: true},
+	{name: "emailFolder", get: func(f *models.Feed) string { return f.EmailFolder }, set: func(f *models.Feed, v string) { f.EmailFolder = v }},
+}
+
+// extensionAttrs returns the mrrss:* attributes of a feed. Default values are left out.
+func extensionAttrs(f *models.Feed, opts GenerateOptions) []xml.Attr {
+	var attrs []xml.Attr
+	for _, a := range feedAttributes {
+		if a.secret && !opts.IncludeSecrets {
+			continue
+		}
+		value := a.get(f)
+		if value == "" || value == "global" {
+			continue
+		}
+		if a.name == "proxyUrl" && !opts.IncludeSecrets {
+			value = stripPassword(value)
+		}
+		// The name is written as is, with the prefix declared on the root element
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: namespacePrefix + ":" + a.name}, Value: value})
+	}
+	return attrs
+}
+
+// applyExtensionAttrs sets the feed fields of the mrrss:* attributes of an outline.
+func applyExtensionAttrs(f *models.Feed, attrs []xml.Attr) {
+	values := make(map[string]string)
+	for _, attr := range attrs {
+		// The decoder resolves bound prefixes to the namespace and keeps unbound ones
+		if attr.Name.Space == Namespace || attr.Name.Space == namespacePrefix {
+			values[attr.Name.Local] = attr.Value
+		}
+	}
+	if len(values) == 0 {
+		return
+	}
+	for _, a := range feedAttributes {
+		if value, ok := values[a.name]; ok {
+			a.set(f, strings.TrimSpace(value))
+		}
+	}
+}
+
+// stripPassword removes the password from the user info of a URL
+func stripPassword(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	if _, hasPassword := u.User.Password(); !hasPassword {
+		return rawURL
+	}
+	u.User = url.User(u.User.Username())
+	return u.String()
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "true"
+	}
+	return ""
+}
+
+func parseBool(s string) bool {
+	b, _ := strconv.ParseBool(s)
+	return b
+}
+
+func formatInt(i int) string {
+	if i == 0 {
+		return ""
+	}
+	return strconv.Itoa(i)
+}
+
+func parseInt(s string) int {
+	i, _ := strconv.Atoi(s)
+	return i
+}
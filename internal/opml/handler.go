@@ -10,17 +10,21 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 )
 
 type OPML struct {
 	XMLName xml.Name `xml:"opml"`
 	Version string   `xml:"version,attr"`
+	XMLNS   string   `xml:"xmlns:mrrss,attr,omitempty"` // Declares the MrRSS extension namespace
 	Head    Head     `xml:"head"`
 	Body    Body     `xml:"body"`
 }
 
 type Head struct {
-	Title string `xml:"title"`
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"` // RFC 822 date
+	OwnerName   string `xml:"ownerName,omitempty"`
 }
 
 type Body struct {
@@ -32,24 +36,27 @@ type Body struct {
 type Outline struct {
 	Text     string     `xml:"text,attr"`
 	Title    string     `xml:"title,attr"`
-	Type     string     `xml:"type,attr"`
-	XMLURL   string     `xml:"xmlUrl,attr"`
-	HTMLURL  string     `xml:"htmlUrl,attr"`
+	Type     string     `xml:"type,attr,omitempty"`
+	XMLURL   string     `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string     `xml:"htmlUrl,attr,omitempty"`
 	Outlines []*Outline `xml:"outline"` // Nested outlines
 	// Additional attributes for compatibility with various OPML formats
-	Description string `xml:"description,attr"`
-	Category    string `xml:"category,attr"`
+	Description string `xml:"description,attr,omitempty"`
+	Category    string `xml:"category,attr,omitempty"`
 	// FreshRSS XPath extension attributes
-	XPathItem           string `xml:"xPathItem,attr"`
-	XPathItemTitle      string `xml:"xPathItemTitle,attr"`
-	XPathItemContent    string `xml:"xPathItemContent,attr"`
-	XPathItemUri        string `xml:"xPathItemUri,attr"`
-	XPathItemAuthor     string `xml:"xPathItemAuthor,attr"`
-	XPathItemTimestamp  string `xml:"xPathItemTimestamp,attr"`
-	XPathItemTimeFormat string `xml:"xPathItemTimeFormat,attr"`
-	XPathItemThumbnail  string `xml:"xPathItemThumbnail,attr"`
-	XPathItemCategories string `xml:"xPathItemCategories,attr"`
-	XPathItemUid        string `xml:"xPathItemUid,attr"`
+	XPathItem           string `xml:"xPathItem,attr,omitempty"`
+	XPathItemTitle      string `xml:"xPathItemTitle,attr,omitempty"`
+	XPathItemContent    string `xml:"xPathItemContent,attr,omitempty"`
+	XPathItemUri        string `xml:"xPathItemUri,attr,omitempty"`
+	XPathItemAuthor     string `xml:"xPathItemAuthor,attr,omitempty"`
+	XPathItemTimestamp  string `xml:"xPathItemTimestamp,attr,omitempty"`
+	XPathItemTimeFormat string `xml:"xPathItemTimeFormat,attr,omitempty"`
+	XPathItemThumbnail  string `xml:"xPathItemThumbnail,attr,omitempty"`
+	XPathItemCategories string `xml:"xPathItemCategories,attr,omitempty"`
+	XPathItemUid        string `xml:"xPathItemUid,attr,omitempty"`
+
+	// MrRSS feed configuration (mrrss:* attributes) and unknown attributes
+	Extensions []xml.Attr `xml:",any,attr"`
 }
 
 // normalizeOPMLAttributes normalizes attribute names in OPML content to handle
@@ -136,12 +143,14 @@ func Parse(r io.Reader) ([]models.Feed, error) {
 				if o.Category != "" {
 					feedCategory = strings.TrimSpace(o.Category)
 				}
-				feeds = append(feeds, models.Feed{
-					Title:    title,
-					URL:      xmlURL,
-					Category: feedCategory,
+				feed := models.Feed{
+					Title:       title,
+					URL:         xmlURL,
+					Link:        strings.TrimSpace(o.HTMLURL),
+					Description: o.Description,
+					Category:    feedCategory,
 					// XPath support
-					Type:                o.Type,
+					Type:                feedType(o.Type),
 					XPathItem:           o.XPathItem,
 					XPathItemTitle:      o.XPathItemTitle,
 					XPathItemContent:    o.XPathItemContent,
@@ -152,7 +161,9 @@ func Parse(r io.Reader) ([]models.Feed, error) {
 					XPathItemThumbnail:  o.XPathItemThumbnail,
 					XPathItemCategories: o.XPathItemCategories,
 					XPathItemUid:        o.XPathItemUid,
-				})
+				}
+				applyExtensionAttrs(&feed, o.Extensions)
+				feeds = append(feeds, feed)
 			}
 
 			newCategory := category
@@ -228,15 +239,36 @@ func fallbackParse(content []byte) []models.Feed {
 	return feeds
 }
 
+// feedType converts an outline type to a feed type.
+// Plain feeds have no type in MrRSS.
+func feedType(outlineType string) string {
+	switch strings.ToLower(strings.TrimSpace(outlineType)) {
+	case "", "rss", "atom":
+		return ""
+	}
+	return outlineType
+}
+
+// Generate creates an OPML 2.0 document of feeds, without secrets.
 func Generate(feeds []models.Feed) ([]byte, error) {
+	return GenerateWithOptions(feeds, GenerateOptions{})
+}
+
+// GenerateWithOptions creates an OPML 2.0 document of feeds. The configuration of
+// each feed is written as mrrss:* attributes so that Parse can restore it.
+func GenerateWithOptions(feeds []models.Feed, opts GenerateOptions) ([]byte, error) {
 	doc := OPML{
-		Version: "1.0",
+		Version: "2.0",
+		XMLNS:   Namespace,
 		Head: Head{
-			Title: "MrRSS Subscriptions",
+			Title:       "MrRSS Subscriptions",
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+			OwnerName:   "MrRSS",
 		},
 	}
 
-	for _, f := range feeds {
+	for i := range feeds {
+		f := &feeds[i]
 		currentOutlines := &doc.Body.Outlines
 
 		if f.Category != "" {
@@ -260,11 +292,17 @@ func Generate(feeds []models.Feed) ([]byte, error) {
 			}
 		}
 
+		outlineType := f.Type
+		if outlineType == "" {
+			outlineType = "rss"
+		}
 		*currentOutlines = append(*currentOutlines, &Outline{
-			Text:   f.Title,
-			Title:  f.Title,
-			Type:   f.Type,
-			XMLURL: f.URL,
+			Text:        f.Title,
+			Title:       f.Title,
+			Type:        outlineType,
+			XMLURL:      f.URL,
+			HTMLURL:     f.Link,
+			Description: f.Description,
 			// XPath support
 			XPathItem:           f.XPathItem,
 			XPathItemTitle:      f.XPathItemTitle,
@@ -276,8 +314,13 @@ func Generate(feeds []models.Feed) ([]byte, error) {
 			XPathItemThumbnail:  f.XPathItemThumbnail,
 			XPathItemCategories: f.XPathItemCategories,
 			XPathItemUid:        f.XPathItemUid,
+			Extensions:          extensionAttrs(f, opts),
 		})
 	}
 
-	return xml.MarshalIndent(doc, "", "  ")
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
 }
@@ -0,0 +1,451 @@
+// Package podcast downloads podcast episodes in the background.
+package podcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+const (
+	// DefaultMaxStorageMB is used when podcast_download_max_size_mb is not set
+	DefaultMaxStorageMB = 2000
+	// partialSuffix is appended to the file name while an episode is downloaded
+	partialSuffix = ".part"
+	// progressInterval is how often the progress of a download is stored
+	progressInterval = 2 * time.Second
+)
+
+// ErrStorageLimit is returned when an episode does not fit in the storage limit
+var ErrStorageLimit = errors.New("podcast storage limit reached")
+
+// audioExtensions maps the content types of common episode formats to file extensions
+var audioExtensions = map[string]string{
+	"audio/mpeg":  ".mp3",
+	"audio/mp3":   ".mp3",
+	"audio/mp4":   ".m4a",
+	"audio/x-m4a": ".m4a",
+	"audio/aac":   ".aac",
+	"audio/ogg":   ".ogg",
+	"audio/opus":  ".opus",
+	"audio/flac":  ".flac",
+	"audio/wav":   ".wav",
+	"audio/x-wav": ".wav",
+	"video/mp4":   ".mp4",
+}
+
+// validExtension matches the file extensions kept from episode URLs
+var validExtension = regexp.MustCompile(`^\.[a-z0-9]{1,5}$`)
+
+// activeDownload is the download that is running
+type activeDownload struct {
+	articleID int64
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// Downloader downloads queued episodes one at a time, in the order they were
+// queued. Interrupted downloads are resumed with HTTP range requests. To stay
+// within the storage limit, the downloads of played episodes are removed,
+// least recently downloaded first.
+type Downloader struct {
+	db        *database.DB
+	newClient func() *http.Client
+	wake      chan struct{}
+
+	mu     sync.Mutex
+	active *activeDownload
+}
+
+// NewDownloader creates a new downloader. newClient returns the HTTP client used
+// for each download, so that network settings changes are picked up.
+func NewDownloader(db *database.DB, newClient func() *http.Client) *Downloader {
+	return &Downloader{
+		db:        db,
+		newClient: newClient,
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Start resumes interrupted downloads and processes the queue until ctx is done
+func (d *Downloader) Start(ctx context.Context) {
+	if err := d.db.ResetInterruptedPodcastDownloads(); err != nil {
+		log.Printf("Podcast downloads: %v", err)
+	}
+	d.removeOrphans()
+	go d.run(ctx)
+}
+
+// Enqueue queues an episode for download. Failed downloads are retried and
+// resume where they stopped.
+func (d *Downloader) Enqueue(articleID int64, url string) error {
+	if err := d.db.QueuePodcastDownload(articleID, url); err != nil {
+		return err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Delete stops the download of an episode if it is running and removes the
+// downloaded file
+func (d *Downloader) Delete(articleID int64) error {
+	d.mu.Lock()
+	active := d.active
+	d.mu.Unlock()
+	if active != nil && active.articleID == articleID {
+		active.cancel()
+		<-active.done
+	}
+
+	download, err := d.db.GetPodcastDownload(articleID)
+	if err != nil || download == nil {
+		return err
+	}
+	d.removeFiles(*download)
+	return d.db.DeletePodcastDownload(articleID)
+}
+
+// FilePath returns the path of a downloaded episode.
+// Returns an empty path if the download is not completed.
+func (d *Downloader) FilePath(articleID int64) (string, error) {
+	download, err := d.db.GetPodcastDownload(articleID)
+	if err != nil || download == nil || download.Status != database.PodcastDownloadCompleted {
+		return "", err
+	}
+	dir, err := utils.GetPodcastDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, download.FileName), nil
+}
+
+// MaxStorage returns the storage limit of downloaded episodes in bytes
+func (d *Downloader) MaxStorage() int64 {
+	maxSizeMBStr, _ := d.db.GetSetting("podcast_download_max_size_mb")
+	maxSizeMB, err := strconv.Atoi(maxSizeMBStr)
+	if err != nil || maxSizeMB <= 0 {
+		maxSizeMB = DefaultMaxStorageMB
+	}
+	return int64(maxSizeMB) << 20
+}
+
+// run downloads queued episodes until the queue is empty, then waits for new ones
+func (d *Downloader) run(ctx context.Context) {
+	for {
+		for ctx.Err() == nil {
+			next, err := d.db.GetNextPodcastDownload()
+			if err != nil {
+				log.Printf("Podcast downloads: %v", err)
+				break
+			}
+			if next == nil {
+				break
+			}
+			d.process(ctx, *next)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		}
+	}
+}
+
+// process runs a download and stores its result
+func (d *Downloader) process(ctx context.Context, download models.PodcastDownload) {
+	downloadCtx, cancel := context.WithCancel(ctx)
+	active := &activeDownload{articleID: download.ArticleID, cancel: cancel, done: make(chan struct{})}
+	d.mu.Lock()
+	d.active = active
+	d.mu.Unlock()
+	defer func() {
+		cancel()
+		d.mu.Lock()
+		d.active = nil
+		d.mu.Unlock()
+		close(active.done)
+	}()
+
+	download.Status = database.PodcastDownloadDownloading
+	download.Error = ""
+	if download.FileName == "" {
+		download.FileName = episodeFileName(download.ArticleID, download.URL, "")
+	}
+	if err := d.db.UpdatePodcastDownload(download); err != nil {
+		log.Printf("Podcast downloads: %v", err)
+		return
+	}
+
+	err := d.download(downloadCtx, &download)
+	if errors.Is(err, context.Canceled) {
+		// Deleted, or the application is stopping and the download resumes on the next start
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to download podcast episode %d: %v", download.ArticleID, err)
+		download.Status = database.PodcastDownloadFailed
+		download.Error = err.Error()
+	} else {
+		now := time.Now()
+		download.Status = database.PodcastDownloadCompleted
+		download.CompletedAt = &now
+	}
+	if err := d.db.UpdatePodcastDownload(download); err != nil {
+		log.Printf("Podcast downloads: %v", err)
+	}
+}
+
+// download fetches an episode to disk, resuming a partial download if there is one
+func (d *Downloader) download(ctx context.Context, download *models.PodcastDownload) error {
+	dir, err := utils.GetPodcastDir()
+	if err != nil {
+		return err
+	}
+	partialPath := filepath.Join(dir, download.FileName+partialSuffix)
+
+	var offset int64
+	if info, err := os.Stat(partialPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, download.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "MrRSS/1.0 (Podcast Downloader)")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := d.newClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		start, total := parseContentRange(resp.Header.Get("Content-Range"))
+		if start != offset {
+			return fmt.Errorf("server resumed at byte %d instead of %d", start, offset)
+		}
+		download.TotalSize = total
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0 && offset == download.TotalSize:
+		// The previous run was interrupted after the last byte
+		return d.finish(download, partialPath, dir)
+	case resp.StatusCode == http.StatusOK:
+		// The server does not support ranges, start over
+		offset = 0
+		download.TotalSize = max(resp.ContentLength, 0)
+		flags |= os.O_TRUNC
+	default:
+		if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// The file changed on the server, start over on the next attempt
+			os.Remove(partialPath)
+		}
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	download.DownloadedSize = offset
+
+	// Keep the file extension of the content type if the URL has none
+	if filepath.Ext(download.FileName) == "" {
+		if name := episodeFileName(download.ArticleID, download.URL, resp.Header.Get("Content-Type")); name != download.FileName {
+			if offset > 0 {
+				if err := os.Rename(partialPath, filepath.Join(dir, name+partialSuffix)); err != nil {
+					return err
+				}
+			}
+			download.FileName = name
+			partialPath = filepath.Join(dir, name+partialSuffix)
+		}
+	}
+
+	remaining := int64(-1)
+	if download.TotalSize > 0 {
+		remaining = download.TotalSize - offset
+	}
+	budget, err := d.reserveStorage(download.ArticleID, offset, remaining)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(partialPath, flags, 0644)
+	if err != nil {
+		return err
+	}
+	written, copyErr := d.copyWithProgress(f, io.LimitReader(resp.Body, budget+1), download)
+	closeErr := f.Close()
+	if copyErr != nil {
+		return copyErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if written > budget {
+		os.Remove(partialPath)
+		download.DownloadedSize = 0
+		return ErrStorageLimit
+	}
+	if download.TotalSize > 0 && download.DownloadedSize != download.TotalSize {
+		return fmt.Errorf("incomplete download: %d of %d bytes", download.DownloadedSize, download.TotalSize)
+	}
+	download.TotalSize = download.DownloadedSize
+	return d.finish(download, partialPath, dir)
+}
+
+// finish moves a complete partial download to its final name
+func (d *Downloader) finish(download *models.PodcastDownload, partialPath, dir string) error {
+	if err := os.Rename(partialPath, filepath.Join(dir, download.FileName)); err != nil {
+		return err
+	}
+	download.DownloadedSize = download.TotalSize
+	return nil
+}
+
+// copyWithProgress copies the body of a download to the file, storing the
+// progress regularly so that it can be displayed
+func (d *Downloader) copyWithProgress(w io.Writer, r io.Reader, download *models.PodcastDownload) (int64, error) {
+	buf := make([]byte, 64*1024)
+	var written int64
+	lastUpdate := time.Now()
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return written, werr
+			}
+			written += int64(n)
+			download.DownloadedSize += int64(n)
+			if time.Since(lastUpdate) >= progressInterval {
+				lastUpdate = time.Now()
+				if err := d.db.UpdatePodcastDownload(*download); err != nil {
+					log.Printf("Podcast downloads: %v", err)
+				}
+			}
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, err
+		}
+	}
+}
+
+// reserveStorage makes room for the rest of a download by removing downloads of
+// played episodes, and returns the number of bytes that may be written.
+// remaining is -1 if the size of the episode is unknown.
+func (d *Downloader) reserveStorage(articleID, offset, remaining int64) (int64, error) {
+	limit := d.MaxStorage()
+	used, err := d.db.GetPodcastDownloadsSize()
+	if err != nil {
+		return 0, err
+	}
+	// The partial file is already counted, unless the download started over
+	current, err := d.db.GetPodcastDownload(articleID)
+	if err == nil && current != nil {
+		used += offset - current.DownloadedSize
+	}
+
+	needed := remaining
+	if needed < 0 {
+		needed = 0
+	}
+	if used+needed > limit {
+		evictable, err := d.db.GetEvictablePodcastDownloads()
+		if err != nil {
+			return 0, err
+		}
+		for _, old := range evictable {
+			if used+needed <= limit {
+				break
+			}
+			d.removeFiles(old)
+			if err := d.db.DeletePodcastDownload(old.ArticleID); err != nil {
+				return 0, err
+			}
+			used -= old.DownloadedSize
+		}
+	}
+	if used+needed > limit {
+		return 0, ErrStorageLimit
+	}
+	return limit - used, nil
+}
+
+// removeOrphans removes the downloads of deleted articles
+func (d *Downloader) removeOrphans() {
+	orphans, err := d.db.GetOrphanedPodcastDownloads()
+	if err != nil {
+		log.Printf("Podcast downloads: %v", err)
+		return
+	}
+	for _, download := range orphans {
+		d.removeFiles(download)
+		if err := d.db.DeletePodcastDownload(download.ArticleID); err != nil {
+			log.Printf("Podcast downloads: %v", err)
+		}
+	}
+}
+
+// removeFiles removes the complete and partial files of a download
+func (d *Downloader) removeFiles(download models.PodcastDownload) {
+	if download.FileName == "" {
+		return
+	}
+	dir, err := utils.GetPodcastDir()
+	if err != nil {
+		return
+	}
+	os.Remove(filepath.Join(dir, download.FileName))
+	os.Remove(filepath.Join(dir, download.FileName+partialSuffix))
+}
+
+// episodeFileName returns the file name of a downloaded episode, keeping the
+// extension of the URL or, failing that, of the content type
+func episodeFileName(articleID int64, url, contentType string) string {
+	ext := strings.ToLower(path.Ext(strings.SplitN(strings.SplitN(url, "?", 2)[0], "#", 2)[0]))
+	if !validExtension.MatchString(ext) {
+		ext = ""
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+			ext = audioExtensions[mediaType]
+		}
+	}
+	return strconv.FormatInt(articleID, 10) + ext
+}
+
+// parseContentRange parses a "bytes start-end/total" header and returns the
+// start and total (0 if unknown)
+func parseContentRange(header string) (int64, int64) {
+	header = strings.TrimSpace(strings.TrimPrefix(header, "bytes"))
+	rangePart, totalPart, _ := strings.Cut(header, "/")
+	startPart, _, _ := strings.Cut(rangePart, "-")
+	start, err := strconv.ParseInt(strings.TrimSpace(startPart), 10, 64)
+	if err != nil {
+		return -1, 0
+	}
+	total, _ := strconv.ParseInt(strings.TrimSpace(totalPart), 10, 64)
+	return start, total
+}
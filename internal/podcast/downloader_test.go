@@ -0,0 +1,192 @@
+package podcast
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// setupDownloader creates a downloader with a temporary data directory and a
+// feed with the given number of episodes
+func setupDownloader(t *testing.T, episodes int) (*Downloader, *database.DB, []int64) {
+	t.Helper()
+	tmp := t.TempDir()
+	t.Setenv("APPDATA", tmp)
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_DATA_HOME", tmp)
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB failed: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("db Init failed: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Podcast", URL: "https://example.com/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed failed: %v", err)
+	}
+	ids := make([]int64, episodes)
+	for i := range ids {
+		url := fmt.Sprintf("https://example.com/episode%d", i)
+		if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: url, URL: url, PublishedAt: time.Now()}); err != nil {
+			t.Fatalf("SaveArticle failed: %v", err)
+		}
+		ids[i], _ = db.GetArticleIDByURL(url)
+	}
+
+	return NewDownloader(db, func() *http.Client { return http.DefaultClient }), db, ids
+}
+
+// waitForDownload waits until a download is completed or failed
+func waitForDownload(t *testing.T, db *database.DB, articleID int64) *models.PodcastDownload {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		download, err := db.GetPodcastDownload(articleID)
+		if err != nil {
+			t.Fatalf("GetPodcastDownload failed: %v", err)
+		}
+		if download != nil && (download.Status == database.PodcastDownloadCompleted || download.Status == database.PodcastDownloadFailed) {
+			return download
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("download of %d did not finish", articleID)
+	return nil
+}
+
+func TestDownloaderResumesPartialDownload(t *testing.T) {
+	d, db, ids := setupDownloader(t, 1)
+
+	audio := bytes.Repeat([]byte("0123456789"), 10000)
+	var mu sync.Mutex
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "audio/mpeg")
+		http.ServeContent(w, r, "episode.mp3", time.Now(), bytes.NewReader(audio))
+	}))
+	defer server.Close()
+
+	// A previous run stopped after 30000 bytes
+	dir, err := utils.GetPodcastDir()
+	if err != nil {
+		t.Fatalf("GetPodcastDir failed: %v", err)
+	}
+	fileName := fmt.Sprintf("%d.mp3", ids[0])
+	if err := os.WriteFile(filepath.Join(dir, fileName+partialSuffix), audio[:30000], 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := d.Enqueue(ids[0], server.URL+"/episode.mp3"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	_, _ = db.Exec(`UPDATE podcast_downloads SET status = ?, file_name = ?`, database.PodcastDownloadDownloading, fileName)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx)
+
+	download := waitForDownload(t, db, ids[0])
+	if download.Status != database.PodcastDownloadCompleted {
+		t.Fatalf("download failed: %s", download.Error)
+	}
+	if download.DownloadedSize != int64(len(audio)) || download.TotalSize != int64(len(audio)) {
+		t.Errorf("sizes = %d/%d, want %d", download.DownloadedSize, download.TotalSize, len(audio))
+	}
+
+	mu.Lock()
+	if len(ranges) != 1 || ranges[0] != "bytes=30000-" {
+		t.Errorf("expected a single range request, got %q", ranges)
+	}
+	mu.Unlock()
+
+	path, err := d.FilePath(ids[0])
+	if err != nil {
+		t.Fatalf("FilePath failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.Equal(data, audio) {
+		t.Errorf("downloaded file does not match (err: %v)", err)
+	}
+
+	if err := d.Delete(ids[0]); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected file to be removed")
+	}
+}
+
+func TestDownloaderStorageLimit(t *testing.T) {
+	d, db, ids := setupDownloader(t, 3)
+	_ = db.SetSetting("podcast_download_max_size_mb", "1")
+
+	episode := bytes.Repeat([]byte("a"), 600*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "episode.mp3", time.Now(), bytes.NewReader(episode))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	d.Start(ctx)
+
+	if err := d.Enqueue(ids[0], server.URL+"/0.mp3"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if download := waitForDownload(t, db, ids[0]); download.Status != database.PodcastDownloadCompleted {
+		t.Fatalf("first download failed: %s", download.Error)
+	}
+
+	// The first episode was not played, so the second one does not fit
+	if err := d.Enqueue(ids[1], server.URL+"/1.mp3"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if download := waitForDownload(t, db, ids[1]); download.Status != database.PodcastDownloadFailed {
+		t.Fatalf("expected second download to fail, got %s", download.Status)
+	}
+
+	// Once the first episode is played, it makes room for the next one
+	_ = db.SavePlaybackPosition(ids[0], 0, true)
+	if err := d.Enqueue(ids[2], server.URL+"/2.mp3"); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if download := waitForDownload(t, db, ids[2]); download.Status != database.PodcastDownloadCompleted {
+		t.Fatalf("third download failed: %s", download.Error)
+	}
+	if download, _ := db.GetPodcastDownload(ids[0]); download != nil {
+		t.Errorf("expected played episode to be removed")
+	}
+}
+
+func TestEpisodeFileName(t *testing.T) {
+	tests := []struct {
+		url, contentType, want string
+	}{
+		{"https://example.com/show/ep1.MP3?token=abc", "", "7.mp3"},
+		{"https://example.com/listen?id=1", "audio/x-m4a", "7.m4a"},
+		{"https://example.com/listen", "application/octet-stream", "7"},
+		{"https://example.com/file.verylongext", "audio/mpeg", "7.mp3"},
+	}
+	for _, tt := range tests {
+		if got := episodeFileName(7, tt.url, tt.contentType); got != tt.want {
+			t.Errorf("episodeFileName(%q, %q) = %q, want %q", tt.url, tt.contentType, got, tt.want)
+		}
+	}
+}
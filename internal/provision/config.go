@@ -0,0 +1,293 @@
+// Package provision makes the database match a declarative configuration file,
+// so that deployments can manage their settings, feeds and rules from version control.
+//
+// A configuration file is YAML; other formats such as TOML are not supported:
+//
+//	settings:
+//	  update_interval: 30
+//	  translation_enabled: false
+//	feeds:
+//	  - url: https://example.com/feed.xml
+//	    category: Tech
+//	  - url: https://example.com/news
+//	    title: Example News
+//	    type: HTML+XPath
+//	    xpath:
+//	      item: //article
+//	      title: .//h2
+//	prune_feeds: true
+//	rules:
+//	  - name: Hide ads
+//	    conditions:
+//	      - field: article_title
+//	        operator: contains
+//	        value: Sponsored
+//	    actions: [hide]
+//
+// Settings can be overridden with MRRSS_SETTING_<KEY> environment variables,
+// e.g. MRRSS_SETTING_AI_API_KEY, to keep secrets out of the file. The server
+// applies them at startup even without a configuration file.
+package provision
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"MrRSS/internal/config"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding settings
+const EnvPrefix = "MRRSS_SETTING_"
+
+// Config is a declarative configuration
+type Config struct {
+	// Settings are set to the given values; other settings are left unchanged
+	Settings map[string]string
+	// Feeds are added or updated to match, identified by URL
+	Feeds []Feed
+	// PruneFeeds deletes the feeds that are not declared, except FreshRSS feeds
+	PruneFeeds bool
+	// Rules replace the saved rules when declared, even as an empty list
+	Rules *[]Rule
+}
+
+// Feed is a declared feed subscription
+type Feed struct {
+	URL      string `yaml:"url"`
+	Title    string `yaml:"title"` // Defaults to the existing title, or the URL host for new feeds
+	Category string `yaml:"category"`
+	// Type is empty for RSS/Atom feeds, or "HTML+XPath" or "XML+XPath"
+	Type             string `yaml:"type"`
+	Script           string `yaml:"script"`
+	XPath            XPath  `yaml:"xpath"`
+	Proxy            string `yaml:"proxy"` // Proxy URL for this feed, which enables the feed proxy
+	RefreshInterval  int    `yaml:"refresh_interval"`
+	HideFromTimeline bool   `yaml:"hide_from_timeline"`
+	ImageMode        bool   `yaml:"image_mode"`
+}
+
+// XPath holds the expressions of XPath feeds
+type XPath struct {
+	Item       string `yaml:"item"`
+	Title      string `yaml:"title"`
+	Content    string `yaml:"content"`
+	URI        string `yaml:"uri"`
+	Author     string `yaml:"author"`
+	Timestamp  string `yaml:"timestamp"`
+	TimeFormat string `yaml:"time_format"`
+	Thumbnail  string `yaml:"thumbnail"`
+	Categories string `yaml:"categories"`
+	UID        string `yaml:"uid"`
+}
+
+// Rule is a declared automation rule
+type Rule struct {
+	Name       string      `yaml:"name"`
+	Enabled    *bool       `yaml:"enabled"` // Defaults to true
+	Conditions []Condition `yaml:"conditions"`
+	Actions    []string    `yaml:"actions"`
+}
+
+// Condition is a condition of a declared rule
+type Condition struct {
+	Logic    string   `yaml:"logic"`
+	Negate   bool     `yaml:"negate"`
+	Field    string   `yaml:"field"`
+	Operator string   `yaml:"operator"`
+	Value    string   `yaml:"value"`
+	Values   []string `yaml:"values"`
+}
+
+// rawConfig is the file format, where setting values can be any scalar
+type rawConfig struct {
+	Settings   map[string]interface{} `yaml:"settings"`
+	Feeds      []Feed                 `yaml:"feeds"`
+	PruneFeeds bool                   `yaml:"prune_feeds"`
+	Rules      *[]Rule                `yaml:"rules"`
+}
+
+// Load reads and validates a configuration file. An empty path gives an empty configuration.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		return &Config{Settings: map[string]string{}}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse parses and validates a configuration
+func Parse(data []byte) (*Config, error) {
+	var raw rawConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	cfg := &Config{
+		Settings:   make(map[string]string, len(raw.Settings)),
+		Feeds:      raw.Feeds,
+		PruneFeeds: raw.PruneFeeds,
+		Rules:      raw.Rules,
+	}
+	for key, value := range raw.Settings {
+		s, err := settingString(value)
+		if err != nil {
+			return nil, fmt.Errorf("setting %s: %w", key, err)
+		}
+		cfg.Settings[key] = s
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ApplyEnv overrides settings with the MRRSS_SETTING_<KEY> variables of environ
+func (c *Config) ApplyEnv(environ []string) error {
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, EnvPrefix) {
+			continue
+		}
+		key := strings.ToLower(strings.TrimPrefix(name, EnvPrefix))
+		if err := config.ValidateSetting(key, value); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		c.Settings[key] = value
+	}
+	return nil
+}
+
+func (c *Config) validate() error {
+	for key, value := range c.Settings {
+		if err := config.ValidateSetting(key, value); err != nil {
+			return err
+		}
+	}
+	if _, ok := c.Settings["rules"]; ok && c.Rules != nil {
+		return fmt.Errorf("rules are declared both as a setting and as a list")
+	}
+
+	seen := make(map[string]bool)
+	for i, f := range c.Feeds {
+		if f.URL == "" {
+			return fmt.Errorf("feed %d: url is required", i+1)
+		}
+		if seen[f.URL] {
+			return fmt.Errorf("feed %s is declared twice", f.URL)
+		}
+		seen[f.URL] = true
+		if f.Type != "" && f.Type != "HTML+XPath" && f.Type != "XML+XPath" {
+			return fmt.Errorf("feed %s: unknown type %q", f.URL, f.Type)
+		}
+		if f.Type != "" && f.XPath.Item == "" {
+			return fmt.Errorf("feed %s: xpath.item is required for %s feeds", f.URL, f.Type)
+		}
+	}
+
+	if c.Rules != nil {
+		for i, r := range *c.Rules {
+			if r.Name == "" {
+				return fmt.Errorf("rule %d: name is required", i+1)
+			}
+			if len(r.Actions) == 0 {
+				return fmt.Errorf("rule %s: no actions", r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// settingString formats a YAML scalar as a setting value
+func settingString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	default:
+		return "", fmt.Errorf("must be a string, number or boolean")
+	}
+}
+
+// apply sets the declared fields of a feed on f
+func (d Feed) apply(f *models.Feed) {
+	f.URL = d.URL
+	if d.Title != "" {
+		f.Title = d.Title
+	} else if f.Title == "" {
+		f.Title = d.URL
+		if u, err := url.Parse(d.URL); err == nil && u.Host != "" {
+			f.Title = u.Host
+		}
+	}
+	f.Category = d.Category
+	f.Type = d.Type
+	f.ScriptPath = d.Script
+	f.XPathItem = d.XPath.Item
+	f.XPathItemTitle = d.XPath.Title
+	f.XPathItemContent = d.XPath.Content
+	f.XPathItemUri = d.XPath.URI
+	f.XPathItemAuthor = d.XPath.Author
+	f.XPathItemTimestamp = d.XPath.Timestamp
+	f.XPathItemTimeFormat = d.XPath.TimeFormat
+	f.XPathItemThumbnail = d.XPath.Thumbnail
+	f.XPathItemCategories = d.XPath.Categories
+	f.XPathItemUid = d.XPath.UID
+	f.ProxyURL = d.Proxy
+	f.ProxyEnabled = d.Proxy != ""
+	f.RefreshInterval = d.RefreshInterval
+	f.HideFromTimeline = d.HideFromTimeline
+	f.IsImageMode = d.ImageMode
+}
+
+// savedRules converts the declared rules to saved rules, numbered in order
+func (c *Config) savedRules() []rules.Rule {
+	saved := make([]rules.Rule, 0, len(*c.Rules))
+	for i, r := range *c.Rules {
+		rule := rules.Rule{
+			ID:         int64(i + 1),
+			Name:       r.Name,
+			Enabled:    r.Enabled == nil || *r.Enabled,
+			Conditions: make([]rules.Condition, 0, len(r.Conditions)),
+			Actions:    r.Actions,
+		}
+		for j, cond := range r.Conditions {
+			rule.Conditions = append(rule.Conditions, rules.Condition{
+				ID:       int64(j + 1),
+				Logic:    cond.Logic,
+				Negate:   cond.Negate,
+				Field:    cond.Field,
+				Operator: cond.Operator,
+				Value:    cond.Value,
+				Values:   cond.Values,
+			})
+		}
+		saved = append(saved, rule)
+	}
+	return saved
+}
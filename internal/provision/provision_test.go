@@ -0,0 +1,198 @@
+package provision
+
+import (
+	"strings"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+const testConfig = `
+settings:
+  update_interval: 45
+  translation_enabled: true
+  theme: dark
+feeds:
+  - url: https://example.com/feed.xml
+    category: Tech
+  - url: https://news.example/
+    title: Example News
+    type: HTML+XPath
+    xpath:
+      item: //article
+      title: .//h2
+    proxy: socks5://127.0.0.1:1080
+prune_feeds: true
+rules:
+  - name: Hide sponsored
+    conditions:
+      - field: article_title
+        operator: contains
+        value: Sponsored
+    actions: [hide]
+`
+
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB error: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init error: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestParseValidation(t *testing.T) {
+	cfg, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	if cfg.Settings["update_interval"] != "45" || cfg.Settings["translation_enabled"] != "true" {
+		t.Errorf("Unexpected settings: %v", cfg.Settings)
+	}
+	if len(cfg.Feeds) != 2 || cfg.Rules == nil || len(*cfg.Rules) != 1 {
+		t.Fatalf("Unexpected config: %+v", cfg)
+	}
+
+	invalid := map[string]string{
+		"unknown setting":  "settings:\n  no_such_setting: 1\n",
+		"wrong type":       "settings:\n  update_interval: often\n",
+		"unknown field":    "feeds:\n  - url: https://a.example/\n    colour: red\n",
+		"missing url":      "feeds:\n  - title: No URL\n",
+		"duplicate feed":   "feeds:\n  - url: https://a.example/\n  - url: https://a.example/\n",
+		"xpath item":       "feeds:\n  - url: https://a.example/\n    type: HTML+XPath\n",
+		"rule action":      "rules:\n  - name: Nothing\n",
+		"rules twice":      "settings:\n  rules: '[]'\nrules: []\n",
+		"non-scalar value": "settings:\n  theme: [dark]\n",
+	}
+	for name, data := range invalid {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if cfg, err := Parse(nil); err != nil || len(cfg.Settings) != 0 {
+		t.Errorf("Empty config: %+v, %v", cfg, err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	cfg, _ := Parse([]byte("settings:\n  theme: dark\n"))
+	err := cfg.ApplyEnv([]string{"PATH=/bin", "MRRSS_SETTING_AI_API_KEY=sk-test", "MRRSS_SETTING_THEME=light"})
+	if err != nil {
+		t.Fatalf("ApplyEnv error: %v", err)
+	}
+	if cfg.Settings["ai_api_key"] != "sk-test" || cfg.Settings["theme"] != "light" {
+		t.Errorf("Unexpected settings: %v", cfg.Settings)
+	}
+	if err := cfg.ApplyEnv([]string{"MRRSS_SETTING_NOPE=1"}); err == nil {
+		t.Error("Expected an error for an unknown setting")
+	}
+
+	// Without a configuration file
+	cfg, err = Load("")
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if err := cfg.ApplyEnv([]string{"MRRSS_SETTING_AI_API_KEY=sk-test"}); err != nil {
+		t.Fatalf("ApplyEnv error: %v", err)
+	}
+	if len(cfg.Settings) != 1 || cfg.Settings["ai_api_key"] != "sk-test" {
+		t.Errorf("Unexpected settings without a file: %v", cfg.Settings)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	db := newTestDB(t)
+
+	// A feed that is not declared, and one that is declared with other options
+	if _, err := db.AddFeed(&models.Feed{Title: "Old", URL: "https://old.example/rss"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AddFeed(&models.Feed{Title: "Example", URL: "https://example.com/feed.xml", Category: "Misc"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AddFeed(&models.Feed{Title: "Synced", URL: "https://synced.example/rss", IsFreshRSSSource: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Parse([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.ApplyEnv([]string{"MRRSS_SETTING_DEEPL_API_KEY=secret"})
+
+	// A dry run reports the changes without making them
+	planned, err := Reconcile(db, cfg, true)
+	if err != nil {
+		t.Fatalf("Dry run error: %v", err)
+	}
+	if theme, _ := db.GetSetting("theme"); theme == "dark" {
+		t.Error("Dry run changed a setting")
+	}
+	feeds, _ := db.GetFeeds()
+	if len(feeds) != 3 {
+		t.Errorf("Dry run changed feeds: %d feeds", len(feeds))
+	}
+
+	changes, err := Reconcile(db, cfg, false)
+	if err != nil {
+		t.Fatalf("Reconcile error: %v", err)
+	}
+	if len(changes) != len(planned) {
+		t.Errorf("Applied %d changes, planned %d", len(changes), len(planned))
+	}
+
+	var diff []string
+	for _, c := range changes {
+		diff = append(diff, c.String())
+	}
+	text := strings.Join(diff, "\n")
+	for _, want := range []string{
+		`~ setting deepl_api_key: secret changed`,
+		`~ setting theme: "auto" -> "dark"`,
+		`~ feed https://example.com/feed.xml: category "Misc" -> "Tech"`,
+		`+ feed https://news.example/`,
+		`- feed https://old.example/rss: Old`,
+		`~ rules rules: 0 rules -> 1 rules`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Diff is missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "secret\"") || strings.Contains(text, "synced.example") {
+		t.Errorf("Diff leaks a secret or touches a FreshRSS feed:\n%s", text)
+	}
+
+	if key, _ := db.GetEncryptedSetting("deepl_api_key"); key != "secret" {
+		t.Errorf("deepl_api_key = %q", key)
+	}
+	feeds, _ = db.GetFeeds()
+	byURL := make(map[string]models.Feed)
+	for _, f := range feeds {
+		byURL[f.URL] = f
+	}
+	if _, ok := byURL["https://old.example/rss"]; ok || len(feeds) != 3 {
+		t.Errorf("Unexpected feeds after reconcile: %+v", feeds)
+	}
+	news := byURL["https://news.example/"]
+	if news.Title != "Example News" || news.XPathItem != "//article" || !news.ProxyEnabled {
+		t.Errorf("Unexpected XPath feed: %+v", news)
+	}
+	if byURL["https://example.com/feed.xml"].Title != "Example" {
+		t.Error("Title of an existing feed declared without title was changed")
+	}
+
+	// Reconciling again changes nothing
+	again, err := Reconcile(db, cfg, false)
+	if err != nil {
+		t.Fatalf("Second reconcile error: %v", err)
+	}
+	if len(again) != 0 {
+		t.Errorf("Second reconcile made changes: %v", again)
+	}
+}
@@ -0,0 +1,256 @@
+package provision
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"MrRSS/internal/config"
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
+)
+
+// Change actions
+const (
+	ActionAdd    = "add"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Change is a difference between the configuration and the database
+type Change struct {
+	Kind   string `json:"kind"`   // "setting", "feed" or "rules"
+	Action string `json:"action"` // "add", "update" or "delete"
+	Name   string `json:"name"`   // Setting key or feed URL
+	Detail string `json:"detail,omitempty"`
+	FeedID int64  `json:"feed_id,omitempty"` // ID of an added, updated or deleted feed
+}
+
+// String formats a change as a line of a diff
+func (c Change) String() string {
+	sign := "~"
+	switch c.Action {
+	case ActionAdd:
+		sign = "+"
+	case ActionDelete:
+		sign = "-"
+	}
+	line := fmt.Sprintf("%s %s %s", sign, c.Kind, c.Name)
+	if c.Detail != "" {
+		line += ": " + c.Detail
+	}
+	return line
+}
+
+// feedField is a feed property managed by the configuration
+type feedField struct {
+	name string
+	get  func(f *models.Feed) string
+}
+
+var feedFields = []feedField{
+	{"title", func(f *models.Feed) string { return f.Title }},
+	{"category", func(f *models.Feed) string { return f.Category }},
+	{"type", func(f *models.Feed) string { return f.Type }},
+	{"script", func(f *models.Feed) string { return f.ScriptPath }},
+	{"xpath.item", func(f *models.Feed) string { return f.XPathItem }},
+	{"xpath.title", func(f *models.Feed) string { return f.XPathItemTitle }},
+	{"xpath.content", func(f *models.Feed) string { return f.XPathItemContent }},
+	{"xpath.uri", func(f *models.Feed) string { return f.XPathItemUri }},
+	{"xpath.author", func(f *models.Feed) string { return f.XPathItemAuthor }},
+	{"xpath.timestamp", func(f *models.Feed) string { return f.XPathItemTimestamp }},
+	{"xpath.time_format", func(f *models.Feed) string { return f.XPathItemTimeFormat }},
+	{"xpath.thumbnail", func(f *models.Feed) string { return f.XPathItemThumbnail }},
+	{"xpath.categories", func(f *models.Feed) string { return f.XPathItemCategories }},
+	{"xpath.uid", func(f *models.Feed) string { return f.XPathItemUid }},
+	{"proxy", func(f *models.Feed) string { return f.ProxyURL }},
+	{"refresh_interval", func(f *models.Feed) string { return strconv.Itoa(f.RefreshInterval) }},
+	{"hide_from_timeline", func(f *models.Feed) string { return strconv.FormatBool(f.HideFromTimeline) }},
+	{"image_mode", func(f *models.Feed) string { return strconv.FormatBool(f.IsImageMode) }},
+}
+
+// Reconcile makes the database match the configuration and returns the changes made.
+// With dryRun the changes are only computed.
+func Reconcile(db *database.DB, cfg *Config, dryRun bool) ([]Change, error) {
+	var changes []Change
+
+	settingChanges, err := reconcileSettings(db, cfg, dryRun)
+	changes = append(changes, settingChanges...)
+	if err != nil {
+		return changes, err
+	}
+
+	feedChanges, err := reconcileFeeds(db, cfg, dryRun)
+	changes = append(changes, feedChanges...)
+	if err != nil {
+		return changes, err
+	}
+
+	ruleChanges, err := reconcileRules(db, cfg, dryRun)
+	changes = append(changes, ruleChanges...)
+	return changes, err
+}
+
+func reconcileSettings(db *database.DB, cfg *Config, dryRun bool) ([]Change, error) {
+	keys := make([]string, 0, len(cfg.Settings))
+	for key := range cfg.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var changes []Change
+	for _, key := range keys {
+		value := cfg.Settings[key]
+		encrypted := config.IsEncryptedSetting(key)
+
+		var current string
+		var err error
+		if encrypted {
+			current, err = db.GetEncryptedSetting(key)
+		} else {
+			current, err = db.GetSetting(key)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			current = config.GetString(key)
+		} else if err != nil {
+			return changes, fmt.Errorf("failed to read setting %s: %w", key, err)
+		}
+		if current == value {
+			continue
+		}
+
+		detail := fmt.Sprintf("%q -> %q", current, value)
+		if encrypted {
+			detail = "secret changed"
+		}
+		changes = append(changes, Change{Kind: "setting", Action: ActionUpdate, Name: key, Detail: detail})
+		if dryRun {
+			continue
+		}
+
+		if encrypted {
+			err = db.SetEncryptedSetting(key, value)
+		} else {
+			err = db.SetSetting(key, value)
+		}
+		if err != nil {
+			return changes, fmt.Errorf("failed to save setting %s: %w", key, err)
+		}
+	}
+	return changes, nil
+}
+
+func reconcileFeeds(db *database.DB, cfg *Config, dryRun bool) ([]Change, error) {
+	feeds, err := db.GetFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	existing := make(map[string]models.Feed)
+	for _, f := range feeds {
+		// FreshRSS feeds are managed by the FreshRSS server
+		if !f.IsFreshRSSSource {
+			existing[f.URL] = f
+		}
+	}
+
+	var changes []Change
+	declared := make(map[string]bool)
+	for _, d := range cfg.Feeds {
+		declared[d.URL] = true
+
+		current, ok := existing[d.URL]
+		desired := current
+		d.apply(&desired)
+
+		change := Change{Kind: "feed", Action: ActionAdd, Name: d.URL, FeedID: current.ID}
+		if ok {
+			var diffs []string
+			for _, field := range feedFields {
+				if before, after := field.get(&current), field.get(&desired); before != after {
+					diffs = append(diffs, fmt.Sprintf("%s %q -> %q", field.name, before, after))
+				}
+			}
+			if len(diffs) == 0 {
+				continue
+			}
+			change.Action = ActionUpdate
+			change.Detail = strings.Join(diffs, ", ")
+		} else if desired.Category != "" {
+			change.Detail = "category " + desired.Category
+		}
+
+		if !dryRun {
+			// Adding a feed with the URL of an existing one updates it
+			id, err := db.AddFeed(&desired)
+			if err != nil {
+				return changes, fmt.Errorf("failed to save feed %s: %w", d.URL, err)
+			}
+			change.FeedID = id
+		}
+		changes = append(changes, change)
+	}
+
+	if !cfg.PruneFeeds {
+		return changes, nil
+	}
+	for _, f := range feeds {
+		if f.IsFreshRSSSource || declared[f.URL] {
+			continue
+		}
+		changes = append(changes, Change{Kind: "feed", Action: ActionDelete, Name: f.URL, Detail: f.Title, FeedID: f.ID})
+		if dryRun {
+			continue
+		}
+		if err := db.DeleteFeed(f.ID); err != nil {
+			return changes, fmt.Errorf("failed to delete feed %s: %w", f.URL, err)
+		}
+	}
+	return changes, nil
+}
+
+func reconcileRules(db *database.DB, cfg *Config, dryRun bool) ([]Change, error) {
+	if cfg.Rules == nil {
+		return nil, nil
+	}
+	desired := cfg.savedRules()
+
+	currentJSON, err := db.GetSetting("rules")
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read rules: %w", err)
+	}
+	var current []rules.Rule
+	if currentJSON != "" {
+		// Rules that cannot be parsed are replaced
+		_ = json.Unmarshal([]byte(currentJSON), &current)
+	}
+
+	desiredJSON, err := json.Marshal(desired)
+	if err != nil {
+		return nil, err
+	}
+	// Compare the rules as saved by the rules engine, ignoring formatting
+	if len(current) == 0 && len(desired) == 0 {
+		return nil, nil
+	}
+	if normalized, _ := json.Marshal(current); string(normalized) == string(desiredJSON) {
+		return nil, nil
+	}
+
+	change := Change{
+		Kind:   "rules",
+		Action: ActionUpdate,
+		Name:   "rules",
+		Detail: fmt.Sprintf("%d rules -> %d rules", len(current), len(desired)),
+	}
+	if !dryRun {
+		if err := db.SetSetting("rules", string(desiredJSON)); err != nil {
+			return nil, fmt.Errorf("failed to save rules: %w", err)
+		}
+	}
+	return []Change{change}, nil
+}
@@ -0,0 +1,125 @@
+// Package publish renders outgoing feeds of articles, so that favorites, read later
+// articles, categories and filters can be read with other readers. Each feed is
+// only served to the holders of its token.
+package publish
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/rules"
+)
+
+// Sources of the articles of a feed
+const (
+	SourceFavorites = "favorites"
+	SourceReadLater = "read_later"
+	SourceCategory  = "category"
+	SourceFilter    = "filter"
+)
+
+const (
+	// DefaultLimit is the number of articles of a feed without a limit
+	DefaultLimit = 50
+	// MaxLimit is the largest number of articles of a feed
+	MaxLimit = 500
+)
+
+// ErrInvalidFeed is returned by Validate for feeds that cannot be published
+var ErrInvalidFeed = errors.New("invalid published feed")
+
+// Item is an article of a feed, with its cached content
+type Item struct {
+	Article models.Article
+	Content string // Cached HTML content of the article, empty if not cached
+}
+
+// NewToken returns a random token for the URLs of a feed
+func NewToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// Validate checks the source of a feed and clamps its limit. The returned errors
+// wrap ErrInvalidFeed.
+func Validate(f *models.PublishedFeed) error {
+	f.Title = strings.TrimSpace(f.Title)
+	if f.Title == "" {
+		return fmt.Errorf("%w: the title is required", ErrInvalidFeed)
+	}
+
+	switch f.Source {
+	case SourceFavorites, SourceReadLater:
+		f.Category = ""
+		f.Conditions = nil
+	case SourceCategory:
+		f.Category = strings.Trim(strings.TrimSpace(f.Category), "/")
+		if f.Category == "" {
+			return fmt.Errorf("%w: the category is required", ErrInvalidFeed)
+		}
+		f.Conditions = nil
+	case SourceFilter:
+		var conditions []rules.Condition
+		if err := json.Unmarshal(f.Conditions, &conditions); err != nil {
+			return fmt.Errorf("%w: invalid conditions: %v", ErrInvalidFeed, err)
+		}
+		if len(conditions) == 0 {
+			return fmt.Errorf("%w: at least one condition is required", ErrInvalidFeed)
+		}
+		f.Category = ""
+	default:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidFeed, f.Source)
+	}
+
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	} else if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+	return nil
+}
+
+// Items returns the newest articles of a feed with their cached content. Hidden
+// articles are never published.
+func Items(db *database.DB, f models.PublishedFeed) ([]Item, error) {
+	var articles []models.Article
+	var err error
+	switch f.Source {
+	case SourceFavorites:
+		articles, err = db.GetArticles("favorites", 0, "", false, f.Limit, 0)
+	case SourceReadLater:
+		articles, err = db.GetArticles("readLater", 0, "", false, f.Limit, 0)
+	case SourceCategory:
+		articles, err = db.GetArticles("", 0, f.Category, false, f.Limit, 0)
+	case SourceFilter:
+		var conditions []rules.Condition
+		if err := json.Unmarshal(f.Conditions, &conditions); err != nil {
+			return nil, fmt.Errorf("invalid conditions: %w", err)
+		}
+		articles, err = rules.NewEngine(db).MatchingArticles(conditions, f.Limit)
+	default:
+		return nil, fmt.Errorf("unknown source %q", f.Source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(articles))
+	for _, article := range articles {
+		item := Item{Article: article}
+		if content, found, err := db.GetArticleContent(article.ID); err == nil && found {
+			item.Content = content
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
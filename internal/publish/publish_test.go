@@ -0,0 +1,141 @@
+package publish
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func setupTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to create db: %v", err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatalf("Failed to init db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	news, _ := db.AddFeed(&models.Feed{Title: "News Site", URL: "https://news.example/feed", Category: "News/World"})
+	blog, _ := db.AddFeed(&models.Feed{Title: "Go Blog", URL: "https://go.example/feed", Category: "Tech"})
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, a := range []models.Article{
+		{FeedID: news, Title: "Election results", URL: "https://news.example/1", IsFavorite: true, Summary: "Who won & why"},
+		{FeedID: blog, Title: "Go 1.24 released", URL: "https://go.example/1", IsReadLater: true},
+		{FeedID: blog, Title: "Hidden go post", URL: "https://go.example/2", IsFavorite: true, IsHidden: true},
+	} {
+		a.PublishedAt = now.Add(-time.Duration(i) * time.Hour)
+		if err := db.SaveArticle(&a); err != nil {
+			t.Fatalf("SaveArticle failed: %v", err)
+		}
+	}
+	return db
+}
+
+func titles(items []Item) []string {
+	var t []string
+	for _, item := range items {
+		t = append(t, item.Article.Title)
+	}
+	return t
+}
+
+func TestValidate(t *testing.T) {
+	f := models.PublishedFeed{Title: " Picks ", Source: SourceCategory, Category: "/News/", Limit: 10000}
+	if err := Validate(&f); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if f.Title != "Picks" || f.Category != "News" || f.Limit != MaxLimit {
+		t.Errorf("Unexpected feed after Validate: %+v", f)
+	}
+
+	for _, f := range []models.PublishedFeed{
+		{Title: "", Source: SourceFavorites},
+		{Title: "x", Source: "everything"},
+		{Title: "x", Source: SourceCategory},
+		{Title: "x", Source: SourceFilter, Conditions: []byte(`[]`)},
+		{Title: "x", Source: SourceFilter, Conditions: []byte(`{`)},
+	} {
+		if err := Validate(&f); !errors.Is(err, ErrInvalidFeed) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidFeed", f, err)
+		}
+	}
+}
+
+func TestItems(t *testing.T) {
+	db := setupTestDB(t)
+
+	tests := []struct {
+		feed models.PublishedFeed
+		want string
+	}{
+		{models.PublishedFeed{Source: SourceFavorites, Limit: 10}, "Election results"},
+		{models.PublishedFeed{Source: SourceReadLater, Limit: 10}, "Go 1.24 released"},
+		{models.PublishedFeed{Source: SourceCategory, Category: "News", Limit: 10}, "Election results"},
+		{models.PublishedFeed{Source: SourceFilter, Conditions: []byte(`[{"field":"article_title","operator":"contains","value":"go"}]`), Limit: 10}, "Go 1.24 released"},
+	}
+	for _, tt := range tests {
+		items, err := Items(db, tt.feed)
+		if err != nil {
+			t.Fatalf("Items(%s) failed: %v", tt.feed.Source, err)
+		}
+		if got := strings.Join(titles(items), ", "); got != tt.want {
+			t.Errorf("Items(%s) = %q, want %q", tt.feed.Source, got, tt.want)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	db := setupTestDB(t)
+	f := models.PublishedFeed{ID: 1, Title: "Team picks", Source: SourceFavorites, Limit: 10}
+	items, err := Items(db, f)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Items = %v, %v", items, err)
+	}
+	if err := db.SetArticleContent(items[0].Article.ID, "<p>Full <b>story</b></p>"); err != nil {
+		t.Fatal(err)
+	}
+	items, _ = Items(db, f)
+
+	for _, format := range []string{FormatRSS, FormatAtom, FormatJSON} {
+		var buf bytes.Buffer
+		if err := Render(&buf, f, items, format, "https://rss.example/feed"); err != nil {
+			t.Fatalf("Render(%s) failed: %v", format, err)
+		}
+		parsed, err := gofeed.NewParser().ParseString(buf.String())
+		if err != nil {
+			t.Fatalf("%s output does not parse: %v\n%s", format, err, buf.String())
+		}
+		if parsed.Title != "Team picks" || len(parsed.Items) != 1 {
+			t.Fatalf("%s: unexpected feed %q with %d items", format, parsed.Title, len(parsed.Items))
+		}
+		item := parsed.Items[0]
+		if item.Title != "Election results" || item.Link != "https://news.example/1" {
+			t.Errorf("%s: unexpected item %q %q", format, item.Title, item.Link)
+		}
+		if item.Content != "<p>Full <b>story</b></p>" {
+			t.Errorf("%s: content = %q", format, item.Content)
+		}
+		if item.Description != "Who won & why" {
+			t.Errorf("%s: summary = %q", format, item.Description)
+		}
+		if item.PublishedParsed == nil || !item.PublishedParsed.Equal(items[0].Article.PublishedAt) {
+			t.Errorf("%s: published = %v, want %v", format, item.PublishedParsed, items[0].Article.PublishedAt)
+		}
+	}
+
+	if err := Render(&bytes.Buffer{}, f, items, "html", ""); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+	if ContentType("html") != "" || ContentType(FormatJSON) == "" {
+		t.Error("Unexpected content types")
+	}
+}
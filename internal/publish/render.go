@@ -0,0 +1,277 @@
+package publish
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"MrRSS/internal/models"
+)
+
+// Formats of the rendered feeds
+const (
+	FormatRSS  = "rss"
+	FormatAtom = "atom"
+	FormatJSON = "json"
+)
+
+const generator = "MrRSS"
+
+// ContentType returns the media type of a format, or an empty string for unknown formats
+func ContentType(format string) string {
+	switch format {
+	case FormatRSS:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJSON:
+		return "application/feed+json; charset=utf-8"
+	}
+	return ""
+}
+
+// Render writes a feed in a format. selfURL is the URL the feed is served at.
+func Render(w io.Writer, f models.PublishedFeed, items []Item, format, selfURL string) error {
+	switch format {
+	case FormatRSS:
+		return renderRSS(w, f, items, selfURL)
+	case FormatAtom:
+		return renderAtom(w, f, items, selfURL)
+	case FormatJSON:
+		return renderJSON(w, f, items, selfURL)
+	}
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// itemID returns a stable identifier of an article, for readers to tell new articles
+func itemID(a models.Article) string {
+	if a.URL != "" {
+		return a.URL
+	}
+	return "mrrss:article:" + strconv.FormatInt(a.ID, 10)
+}
+
+// updated returns the date of the newest article, or the creation date of an empty feed
+func updated(f models.PublishedFeed, items []Item) time.Time {
+	if len(items) > 0 && !items[0].Article.PublishedAt.IsZero() {
+		return items[0].Article.PublishedAt
+	}
+	if !f.CreatedAt.IsZero() {
+		return f.CreatedAt
+	}
+	return time.Now()
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	Content     string  `xml:"content:encoded,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func renderRSS(w io.Writer, f models.PublishedFeed, items []Item, selfURL string) error {
+	doc := rssDoc{
+		Version: "2.0",
+		Content: "http://purl.org/rss/1.0/modules/content/",
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          selfURL,
+			Description:   f.Title,
+			Self:          atomLink{Rel: "self", Href: selfURL, Type: "application/rss+xml"},
+			LastBuildDate: updated(f, items).Format(time.RFC1123Z),
+			Generator:     generator,
+		},
+	}
+	for _, item := range items {
+		a := item.Article
+		ri := rssItem{
+			Title:       a.Title,
+			Link:        a.URL,
+			GUID:        rssGUID{IsPermaLink: a.URL != "", Value: itemID(a)},
+			Creator:     a.FeedTitle,
+			Description: a.Summary,
+			Content:     item.Content,
+		}
+		if !a.PublishedAt.IsZero() {
+			ri.PubDate = a.PublishedAt.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, ri)
+	}
+	return writeXML(w, doc)
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Self      atomLink    `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title     string    `xml:"title"`
+	ID        string    `xml:"id"`
+	Link      *atomLink `xml:"link,omitempty"`
+	Updated   string    `xml:"updated"`
+	Published string    `xml:"published,omitempty"`
+	Author    *atomName `xml:"author,omitempty"`
+	Summary   *atomText `xml:"summary,omitempty"`
+	Content   *atomText `xml:"content,omitempty"`
+}
+
+type atomName struct {
+	Name string `xml:"name"`
+}
+
+func renderAtom(w io.Writer, f models.PublishedFeed, items []Item, selfURL string) error {
+	feedUpdated := updated(f, items)
+	doc := atomFeed{
+		Title:     f.Title,
+		ID:        "mrrss:published:" + strconv.FormatInt(f.ID, 10),
+		Updated:   feedUpdated.Format(time.RFC3339),
+		Self:      atomLink{Rel: "self", Href: selfURL, Type: "application/atom+xml"},
+		Generator: generator,
+	}
+	for _, item := range items {
+		a := item.Article
+		entry := atomEntry{
+			Title:   a.Title,
+			ID:      itemID(a),
+			Updated: feedUpdated.Format(time.RFC3339),
+		}
+		if !a.PublishedAt.IsZero() {
+			entry.Updated = a.PublishedAt.Format(time.RFC3339)
+			entry.Published = entry.Updated
+		}
+		if a.URL != "" {
+			entry.Link = &atomLink{Rel: "alternate", Href: a.URL}
+		}
+		if a.FeedTitle != "" {
+			entry.Author = &atomName{Name: a.FeedTitle}
+		}
+		if a.Summary != "" {
+			entry.Summary = &atomText{Type: "text", Value: a.Summary}
+		}
+		if item.Content != "" {
+			entry.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// jsonFeed is a JSON Feed 1.1 document, see https://www.jsonfeed.org/version/1.1/
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	FeedURL string         `json:"feed_url"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	Summary       string           `json:"summary,omitempty"`
+	DatePublished string           `json:"date_published,omitempty"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func renderJSON(w io.Writer, f models.PublishedFeed, items []Item, selfURL string) error {
+	doc := jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1",
+		Title:   f.Title,
+		FeedURL: selfURL,
+		Items:   []jsonFeedItem{},
+	}
+	for _, item := range items {
+		a := item.Article
+		ji := jsonFeedItem{
+			ID:          itemID(a),
+			URL:         a.URL,
+			Title:       a.Title,
+			ContentHTML: item.Content,
+			Summary:     a.Summary,
+		}
+		// Items must have content, the summary stands in for articles without cached content
+		if ji.ContentHTML == "" {
+			ji.ContentText = a.Summary
+			if ji.ContentText == "" {
+				ji.ContentText = a.Title
+			}
+		}
+		if !a.PublishedAt.IsZero() {
+			ji.DatePublished = a.PublishedAt.Format(time.RFC3339)
+		}
+		if a.FeedTitle != "" {
+			ji.Authors = []jsonFeedAuthor{{Name: a.FeedTitle}}
+		}
+		doc.Items = append(doc.Items, ji)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
@@ -0,0 +1,92 @@
+package readerimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// readCSV reads a CSV export into records keyed by the lowercase column names of its header.
+func readCSV(content []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	header := rows[0]
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+	records := make([]map[string]string, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		record := make(map[string]string, len(header))
+		for i, value := range row {
+			if i < len(header) {
+				record[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parsePocket reads a Pocket CSV export. Unread items land in read later, archived
+// items are imported as read.
+func parsePocket(content []byte) ([]Item, error) {
+	records, err := readCSV(content)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(records))
+	for _, record := range records {
+		if record["url"] == "" {
+			continue
+		}
+		added, _ := strconv.ParseInt(record["time_added"], 10, 64)
+		archived := record["status"] == "archive"
+		items = append(items, Item{
+			Title:       record["title"],
+			URL:         record["url"],
+			PublishedAt: unixTime(added),
+			IsReadLater: !archived,
+			IsRead:      archived,
+		})
+	}
+	return items, nil
+}
+
+// parseInstapaper reads an Instapaper CSV export. Items land in read later, except
+// archived items which are imported as read, and starred items also become favorites.
+func parseInstapaper(content []byte) ([]Item, error) {
+	records, err := readCSV(content)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]Item, 0, len(records))
+	for _, record := range records {
+		if record["url"] == "" {
+			continue
+		}
+		timestamp, _ := strconv.ParseInt(record["timestamp"], 10, 64)
+		folder := strings.ToLower(record["folder"])
+		items = append(items, Item{
+			Title:       record["title"],
+			URL:         record["url"],
+			Content:     record["selection"],
+			PublishedAt: unixTime(timestamp),
+			IsReadLater: folder != "archive",
+			IsRead:      folder == "archive",
+			IsFavorite:  folder == "starred",
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,63 @@
+package readerimport
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteHeader starts every SQLite database file
+const sqliteHeader = "SQLite format 3\x00"
+
+// parseFreshRSSDB reads the favorites of a FreshRSS SQLite database export.
+func parseFreshRSSDB(content []byte) ([]Item, error) {
+	// The driver needs a file
+	tempDir, err := os.MkdirTemp("", "mrrss-import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "freshrss.sqlite")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write database export: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database export: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`
+		SELECT e.title, e.link, COALESCE(e.content, ''), e.date, e.is_read, COALESCE(f.url, ''), COALESCE(f.name, '')
+		FROM entry e
+		LEFT JOIN feed f ON e.id_feed = f.id
+		WHERE e.is_favorite = 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FreshRSS favorites: %w", err)
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var item Item
+		var date int64
+		var isRead sql.NullBool
+		if err := rows.Scan(&item.Title, &item.URL, &item.Content, &date, &isRead, &item.FeedURL, &item.FeedTitle); err != nil {
+			return nil, fmt.Errorf("failed to read FreshRSS favorites: %w", err)
+		}
+		if item.URL == "" {
+			continue
+		}
+		item.PublishedAt = unixTime(date)
+		item.IsFavorite = true
+		item.IsRead = isRead.Bool
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,99 @@
+package readerimport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+// ImportedFeedURL identifies the feed that holds imported items whose feed is not subscribed.
+// The feed has type ImportedFeedType and is never refreshed.
+const ImportedFeedURL = "imported://items"
+
+// ImportedFeedType is the feed type of the feed that holds imported items
+const ImportedFeedType = "imported"
+
+// Result summarizes an import
+type Result struct {
+	Total   int `json:"total"`   // Items in the export
+	Created int `json:"created"` // New articles
+	Updated int `json:"updated"` // Existing articles whose flags were merged
+	Failed  int `json:"failed"`  // Items that could not be saved
+}
+
+// Import saves items as articles. Items of subscribed feeds are attached to
+// those feeds, the other items to the "Imported" feed, which is created on demand.
+func Import(db *database.DB, items []Item) (*Result, error) {
+	feeds, err := db.GetFeeds()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feeds: %w", err)
+	}
+	feedIDs := make(map[string]int64, len(feeds))
+	var importedFeedID int64
+	for _, feed := range feeds {
+		if feed.URL == ImportedFeedURL {
+			importedFeedID = feed.ID
+			continue
+		}
+		if _, exists := feedIDs[feed.URL]; !exists {
+			feedIDs[feed.URL] = feed.ID
+		}
+	}
+
+	result := &Result{Total: len(items)}
+	for _, item := range items {
+		feedID, ok := feedIDs[item.FeedURL]
+		if !ok {
+			if importedFeedID == 0 {
+				importedFeedID, err = db.AddFeed(&models.Feed{
+					Title:       "Imported",
+					URL:         ImportedFeedURL,
+					Description: "Starred and read later items imported from other readers",
+					Type:        ImportedFeedType,
+				})
+				if err != nil {
+					return result, fmt.Errorf("failed to create imported feed: %w", err)
+				}
+				// There is no website to discover feeds from
+				db.MarkFeedDiscovered(importedFeedID)
+			}
+			feedID = importedFeedID
+		}
+
+		article := &models.Article{
+			FeedID:                feedID,
+			Title:                 item.Title,
+			URL:                   item.URL,
+			PublishedAt:           item.PublishedAt,
+			HasValidPublishedTime: !item.PublishedAt.IsZero(),
+			IsFavorite:            item.IsFavorite,
+			IsReadLater:           item.IsReadLater,
+			// Read later articles leave the list when they are read
+			IsRead: item.IsRead && !item.IsReadLater,
+		}
+		if strings.TrimSpace(article.Title) == "" {
+			article.Title = item.URL
+		}
+		if article.PublishedAt.IsZero() {
+			article.PublishedAt = time.Now()
+		}
+
+		id, created, err := db.SaveImportedArticle(article)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if !created {
+			result.Updated++
+			continue
+		}
+		result.Created++
+		if item.Content != "" {
+			db.SetArticleContent(id, item.Content)
+		}
+	}
+	return result, nil
+}
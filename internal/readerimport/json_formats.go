@@ -0,0 +1,159 @@
+package readerimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// greaderItem is an item of the Google Reader JSON format. Feedly entries use
+// the same fields, with timestamps in milliseconds.
+type greaderItem struct {
+	Title        string        `json:"title"`
+	Published    int64         `json:"published"`
+	Crawled      int64         `json:"crawled"`
+	Categories   []string      `json:"categories"`
+	Canonical    []greaderLink `json:"canonical"`
+	Alternate    []greaderLink `json:"alternate"`
+	CanonicalURL string        `json:"canonicalUrl"`
+	OriginID     string        `json:"originId"`
+	Content      *greaderText  `json:"content"`
+	Summary      *greaderText  `json:"summary"`
+	Origin       struct {
+		StreamID string `json:"streamId"`
+		Title    string `json:"title"`
+	} `json:"origin"`
+	Unread *bool `json:"unread"`
+}
+
+type greaderLink struct {
+	Href string `json:"href"`
+}
+
+type greaderText struct {
+	Content string `json:"content"`
+}
+
+// toItem converts the fields shared by Google Reader and Feedly
+func (gi greaderItem) toItem() Item {
+	item := Item{
+		Title:       strings.TrimSpace(gi.Title),
+		PublishedAt: unixTime(gi.Published),
+		FeedTitle:   gi.Origin.Title,
+	}
+	if item.PublishedAt.IsZero() {
+		item.PublishedAt = unixTime(gi.Crawled)
+	}
+
+	switch {
+	case len(gi.Canonical) > 0 && gi.Canonical[0].Href != "":
+		item.URL = gi.Canonical[0].Href
+	case gi.CanonicalURL != "":
+		item.URL = gi.CanonicalURL
+	case len(gi.Alternate) > 0 && gi.Alternate[0].Href != "":
+		item.URL = gi.Alternate[0].Href
+	case strings.HasPrefix(gi.OriginID, "http"):
+		item.URL = gi.OriginID
+	}
+
+	if gi.Content != nil && gi.Content.Content != "" {
+		item.Content = gi.Content.Content
+	} else if gi.Summary != nil {
+		item.Content = gi.Summary.Content
+	}
+
+	// Stream IDs of feeds are "feed/<url>"
+	if strings.HasPrefix(gi.Origin.StreamID, "feed/") {
+		item.FeedURL = strings.TrimPrefix(gi.Origin.StreamID, "feed/")
+	}
+	return item
+}
+
+// parseGoogleReader reads starred items exported by Inoreader, FreshRSS or Google Takeout.
+func parseGoogleReader(content []byte) ([]Item, error) {
+	var export struct {
+		Items []greaderItem `json:"items"`
+	}
+	if err := json.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Google Reader JSON: %w", err)
+	}
+
+	items := make([]Item, 0, len(export.Items))
+	for _, gi := range export.Items {
+		item := gi.toItem()
+		if item.URL == "" {
+			continue
+		}
+		// The export contains starred items only; the read state is a category
+		item.IsFavorite = true
+		for _, category := range gi.Categories {
+			if strings.HasSuffix(category, "/state/com.google/read") {
+				item.IsRead = true
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseFeedly reads the saved items exported by Feedly, which land in read later.
+func parseFeedly(content []byte) ([]Item, error) {
+	var entries []greaderItem
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse Feedly JSON: %w", err)
+	}
+
+	items := make([]Item, 0, len(entries))
+	for _, entry := range entries {
+		item := entry.toItem()
+		if item.URL == "" {
+			continue
+		}
+		item.IsReadLater = true
+		item.IsRead = entry.Unread != nil && !*entry.Unread
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseMiniflux reads a response of the Miniflux entries API, e.g. /v1/entries?starred=true.
+// Starred entries become favorites; other entries land in read later.
+func parseMiniflux(content []byte) ([]Item, error) {
+	var export struct {
+		Entries []struct {
+			Title       string    `json:"title"`
+			URL         string    `json:"url"`
+			Content     string    `json:"content"`
+			PublishedAt time.Time `json:"published_at"`
+			Status      string    `json:"status"`
+			Starred     bool      `json:"starred"`
+			Feed        struct {
+				Title   string `json:"title"`
+				FeedURL string `json:"feed_url"`
+			} `json:"feed"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Miniflux JSON: %w", err)
+	}
+
+	items := make([]Item, 0, len(export.Entries))
+	for _, entry := range export.Entries {
+		if entry.URL == "" {
+			continue
+		}
+		items = append(items, Item{
+			Title:       strings.TrimSpace(entry.Title),
+			URL:         entry.URL,
+			Content:     entry.Content,
+			PublishedAt: entry.PublishedAt.UTC(),
+			FeedURL:     entry.Feed.FeedURL,
+			FeedTitle:   entry.Feed.Title,
+			IsFavorite:  entry.Starred,
+			IsReadLater: !entry.Starred,
+			IsRead:      entry.Status == "read",
+		})
+	}
+	return items, nil
+}
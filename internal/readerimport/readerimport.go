@@ -0,0 +1,128 @@
+// Package readerimport reads the saved items of other feed readers and
+// read-later services, so that starred and read-later articles survive a
+// switch to MrRSS.
+//
+// Supported formats are the Google Reader JSON used by Inoreader, FreshRSS
+// and Google Takeout, Feedly saved-items JSON, Miniflux entries JSON, FreshRSS
+// SQLite database exports, and Pocket and Instapaper CSV exports.
+package readerimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Format identifies the format of an export file
+type Format string
+
+const (
+	FormatGoogleReader Format = "google-reader" // Inoreader, FreshRSS and Google Takeout starred items
+	FormatFeedly       Format = "feedly"        // Feedly saved items
+	FormatMiniflux     Format = "miniflux"      // Miniflux entries API response
+	FormatFreshRSSDB   Format = "freshrss-db"   // FreshRSS SQLite database export
+	FormatPocket       Format = "pocket"        // Pocket CSV export
+	FormatInstapaper   Format = "instapaper"    // Instapaper CSV export
+)
+
+// ErrUnknownFormat is returned when the format of an export file is not recognized
+var ErrUnknownFormat = errors.New("unrecognized export format")
+
+// Item is a saved article of another reader
+type Item struct {
+	Title       string
+	URL         string
+	Content     string
+	PublishedAt time.Time
+	// FeedURL and FeedTitle describe the feed the item came from, if known
+	FeedURL     string
+	FeedTitle   string
+	IsFavorite  bool
+	IsReadLater bool
+	IsRead      bool
+}
+
+// Detect guesses the format of an export file from its content
+func Detect(content []byte) (Format, error) {
+	if bytes.HasPrefix(content, []byte(sqliteHeader)) {
+		return FormatFreshRSSDB, nil
+	}
+
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf")))
+	if len(trimmed) == 0 {
+		return "", ErrUnknownFormat
+	}
+
+	switch trimmed[0] {
+	case '[':
+		return FormatFeedly, nil
+	case '{':
+		var probe struct {
+			Items   json.RawMessage `json:"items"`
+			Entries json.RawMessage `json:"entries"`
+		}
+		if err := json.Unmarshal(trimmed, &probe); err != nil {
+			return "", ErrUnknownFormat
+		}
+		switch {
+		case probe.Items != nil:
+			return FormatGoogleReader, nil
+		case probe.Entries != nil:
+			return FormatMiniflux, nil
+		}
+		return "", ErrUnknownFormat
+	}
+
+	// CSV exports are recognized by their header
+	header := strings.ToLower(string(trimmed))
+	if i := strings.IndexAny(header, "\r\n"); i >= 0 {
+		header = header[:i]
+	}
+	switch {
+	case strings.Contains(header, "time_added"):
+		return FormatPocket, nil
+	case strings.Contains(header, "folder") && strings.Contains(header, "url"):
+		return FormatInstapaper, nil
+	}
+	return "", ErrUnknownFormat
+}
+
+// Parse reads the items of an export file. An empty format is detected from the content.
+func Parse(format Format, content []byte) ([]Item, error) {
+	if format == "" {
+		var err error
+		if format, err = Detect(content); err != nil {
+			return nil, err
+		}
+	}
+
+	switch format {
+	case FormatGoogleReader:
+		return parseGoogleReader(content)
+	case FormatFeedly:
+		return parseFeedly(content)
+	case FormatMiniflux:
+		return parseMiniflux(content)
+	case FormatFreshRSSDB:
+		return parseFreshRSSDB(content)
+	case FormatPocket:
+		return parsePocket(content)
+	case FormatInstapaper:
+		return parseInstapaper(content)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// unixTime converts a timestamp in seconds or milliseconds to a time
+func unixTime(ts int64) time.Time {
+	if ts <= 0 {
+		return time.Time{}
+	}
+	// Feedly uses milliseconds, the other readers seconds
+	if ts > 1e11 {
+		return time.UnixMilli(ts).UTC()
+	}
+	return time.Unix(ts, 0).UTC()
+}
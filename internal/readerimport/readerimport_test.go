@@ -0,0 +1,207 @@
+package readerimport
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+)
+
+const googleReaderExport = `{
+  "id": "user/-/state/com.google/starred",
+  "items": [
+    {
+      "title": "Starred post",
+      "published": 1700000000,
+      "categories": ["user/-/state/com.google/starred", "user/-/state/com.google/read"],
+      "canonical": [{"href": "https://example.com/starred"}],
+      "summary": {"content": "<p>Saved content</p>"},
+      "origin": {"streamId": "feed/https://example.com/feed.xml", "title": "Example"}
+    }
+  ]
+}`
+
+const feedlyExport = `[
+  {
+    "title": "Saved post",
+    "published": 1700000000000,
+    "alternate": [{"href": "https://blog.example.org/saved", "type": "text/html"}],
+    "origin": {"streamId": "feed/https://blog.example.org/rss", "title": "Blog"},
+    "unread": false
+  }
+]`
+
+const minifluxExport = `{"total": 1, "entries": [{"title": "Miniflux post", "url": "https://example.net/post", "published_at": "2023-11-14T22:13:20Z", "status": "unread", "starred": true, "feed": {"title": "Net", "feed_url": "https://example.net/feed"}}]}`
+
+const pocketExport = "title,url,time_added,tags,status\nRead me,https://example.com/pocket,1700000000,,unread\nDone,https://example.com/done,1600000000,tech,archive\n"
+
+const instapaperExport = "URL,Title,Selection,Folder,Timestamp\nhttps://example.com/insta,Instapaper post,,Starred,1700000000\n" +
+	"https://example.com/insta-done,Archived post,,Archive,1600000000\n"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Format
+	}{
+		{googleReaderExport, FormatGoogleReader},
+		{feedlyExport, FormatFeedly},
+		{minifluxExport, FormatMiniflux},
+		{pocketExport, FormatPocket},
+		{instapaperExport, FormatInstapaper},
+		{sqliteHeader + "rest", FormatFreshRSSDB},
+	}
+	for _, tt := range tests {
+		if got, err := Detect([]byte(tt.content)); err != nil || got != tt.want {
+			t.Errorf("Detect() = %q, %v, want %q", got, err, tt.want)
+		}
+	}
+
+	if _, err := Detect([]byte("<opml></opml>")); err != ErrUnknownFormat {
+		t.Errorf("expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	items, err := Parse("", []byte(googleReaderExport))
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Parse(google reader) = %+v, %v", items, err)
+	}
+	if item := items[0]; item.URL != "https://example.com/starred" || item.FeedURL != "https://example.com/feed.xml" ||
+		!item.IsFavorite || !item.IsRead || item.Content != "<p>Saved content</p>" || item.PublishedAt.Unix() != 1700000000 {
+		t.Errorf("unexpected google reader item: %+v", item)
+	}
+
+	items, err = Parse("", []byte(feedlyExport))
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Parse(feedly) = %+v, %v", items, err)
+	}
+	if item := items[0]; item.URL != "https://blog.example.org/saved" || !item.IsReadLater || item.PublishedAt.Unix() != 1700000000 {
+		t.Errorf("unexpected feedly item: %+v", item)
+	}
+
+	items, err = Parse("", []byte(minifluxExport))
+	if err != nil || len(items) != 1 || !items[0].IsFavorite || items[0].FeedURL != "https://example.net/feed" {
+		t.Fatalf("Parse(miniflux) = %+v, %v", items, err)
+	}
+
+	items, err = Parse("", []byte(pocketExport))
+	if err != nil || len(items) != 2 || !items[0].IsReadLater || items[0].IsRead || !items[1].IsRead || items[1].IsReadLater {
+		t.Fatalf("Parse(pocket) = %+v, %v", items, err)
+	}
+
+	items, err = Parse("", []byte(instapaperExport))
+	if err != nil || len(items) != 2 || !items[0].IsFavorite || !items[0].IsReadLater || items[0].Title != "Instapaper post" {
+		t.Fatalf("Parse(instapaper) = %+v, %v", items, err)
+	}
+	if item := items[1]; !item.IsRead || item.IsReadLater || item.IsFavorite {
+		t.Errorf("unexpected archived instapaper item: %+v", item)
+	}
+}
+
+func TestImport(t *testing.T) {
+	db, err := database.NewDB(filepath.Join(t.TempDir(), "rss.db"))
+	if err != nil {
+		t.Fatalf("NewDB() error = %v", err)
+	}
+	defer db.Close()
+	if err := db.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Example", URL: "https://example.com/feed.xml"})
+	if err != nil {
+		t.Fatalf("AddFeed() error = %v", err)
+	}
+	if err := db.SaveArticle(&models.Article{FeedID: feedID, Title: "Already here", URL: "https://example.com/pocket"}); err != nil {
+		t.Fatalf("SaveArticle() error = %v", err)
+	}
+
+	items, _ := Parse(FormatGoogleReader, []byte(googleReaderExport))
+	pocketItems, _ := Parse(FormatPocket, []byte(pocketExport))
+	items = append(items, pocketItems...)
+
+	result, err := Import(db, items)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Total != 3 || result.Created != 2 || result.Updated != 1 || result.Failed != 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// The starred item belongs to a subscribed feed
+	favorites, err := db.GetArticles("favorites", 0, "", false, 10, 0)
+	if err != nil || len(favorites) != 1 || favorites[0].FeedID != feedID {
+		t.Fatalf("favorites = %+v, %v", favorites, err)
+	}
+	if content, found, _ := db.GetArticleContent(favorites[0].ID); !found || content != "<p>Saved content</p>" {
+		t.Errorf("favorite content = %q, %v", content, found)
+	}
+
+	// Existing articles are flagged read later
+	readLater, err := db.GetArticles("readLater", 0, "", false, 10, 0)
+	if err != nil || len(readLater) != 1 || readLater[0].URL != "https://example.com/pocket" || readLater[0].FeedID != feedID {
+		t.Fatalf("read later = %+v, %v", readLater, err)
+	}
+
+	// Archived Pocket items without a feed land read in the imported feed
+	articles, err := db.GetArticles("", 0, "", false, 10, 0)
+	if err != nil {
+		t.Fatalf("GetArticles() error = %v", err)
+	}
+	archived := 0
+	for _, article := range articles {
+		if article.URL != "https://example.com/done" {
+			continue
+		}
+		archived++
+		if article.FeedID == feedID || !article.IsRead || article.IsReadLater {
+			t.Errorf("unexpected archived article: %+v", article)
+		}
+	}
+	if archived != 1 {
+		t.Errorf("expected the archived article to be imported once, got %d", archived)
+	}
+
+	// Importing again does not duplicate the imported feed
+	if _, err := Import(db, pocketItems); err != nil {
+		t.Fatalf("second Import() error = %v", err)
+	}
+	feeds, _ := db.GetFeeds()
+	if len(feeds) != 2 {
+		t.Errorf("expected 2 feeds, got %d", len(feeds))
+	}
+}
+
+func TestParseFreshRSSDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "freshrss.sqlite")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+		CREATE TABLE feed (id INTEGER PRIMARY KEY, url TEXT, name TEXT);
+		CREATE TABLE entry (id INTEGER PRIMARY KEY, title TEXT, link TEXT, content TEXT, date INTEGER, is_read BOOLEAN, is_favorite BOOLEAN, id_feed INTEGER);
+		INSERT INTO feed VALUES (1, 'https://example.com/feed.xml', 'Example');
+		INSERT INTO entry VALUES (1, 'Favorite', 'https://example.com/fav', '<p>Hi</p>', 1700000000, 1, 1, 1);
+		INSERT INTO entry VALUES (2, 'Other', 'https://example.com/other', '', 1700000000, 0, 0, 1);
+	`)
+	db.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, err := Parse("", content)
+	if err != nil || len(items) != 1 {
+		t.Fatalf("Parse(freshrss db) = %+v, %v", items, err)
+	}
+	if item := items[0]; item.URL != "https://example.com/fav" || item.FeedURL != "https://example.com/feed.xml" || !item.IsFavorite || !item.IsRead {
+		t.Errorf("unexpected freshrss item: %+v", item)
+	}
+}
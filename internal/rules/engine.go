@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"MrRSS/internal/database"
+	"MrRSS/internal/langdetect"
 	"MrRSS/internal/models"
 )
 
@@ -150,6 +151,46 @@ func (e *Engine) ApplyRule(rule Rule) (int, error) {
 	return affected, nil
 }
 
+// MatchingArticles returns the newest visible articles matching the conditions,
+// at most limit of them. Like ApplyRule, only the newest batch of articles is searched.
+func (e *Engine) MatchingArticles(conditions []Condition, limit int) ([]models.Article, error) {
+	const batchSize = 10000
+	articles, err := e.db.GetArticles("", 0, "", false, batchSize, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	feeds, err := e.db.GetFeeds()
+	if err != nil {
+		return nil, err
+	}
+
+	feedCategories := make(map[int64]string)
+	feedTitles := make(map[int64]string)
+	feedTypes := make(map[int64]string)
+	feedIsImageMode := make(map[int64]bool)
+	feedIsFreshRSS := make(map[int64]bool)
+
+	for _, feed := range feeds {
+		feedCategories[feed.ID] = feed.Category
+		feedTitles[feed.ID] = feed.Title
+		feedTypes[feed.ID] = feed.Type
+		feedIsImageMode[feed.ID] = feed.IsImageMode
+		feedIsFreshRSS[feed.ID] = feed.IsFreshRSSSource
+	}
+
+	var matching []models.Article
+	for _, article := range articles {
+		if len(matching) >= limit {
+			break
+		}
+		if matchesConditions(article, conditions, feedCategories, feedTitles, feedTypes, feedIsImageMode, feedIsFreshRSS) {
+			matching = append(matching, article)
+		}
+	}
+	return matching, nil
+}
+
 // matchesConditions checks if an article matches the rule conditions
 func matchesConditions(article models.Article, conditions []Condition, feedCategories map[int64]string, feedTitles map[int64]string, feedTypes map[int64]string, feedIsImageMode map[int64]bool, feedIsFreshRSS map[int64]bool) bool {
 	// If no conditions, apply to all articles
@@ -231,6 +272,13 @@ func evaluateCondition(article models.Article, condition Condition, feedCategori
 			result = feedIsImageMode[article.FeedID] == wantImageMode
 		}
 
+	case "article_language":
+		matched, decided := MatchLanguage(article.Lang, condition.Values, condition.Value)
+		if !decided {
+			return false
+		}
+		result = matched
+
 	case "published_after":
 		if condition.Value == "" {
 			result = true
@@ -316,6 +364,30 @@ func matchMultiSelect(fieldValue string, values []string, singleValue string) bo
 	return true
 }
 
+// UnknownLanguage is the language condition value selecting articles whose language
+// could not be detected
+const UnknownLanguage = "unknown"
+
+// MatchLanguage checks if an article's detected language matches any of the selected
+// language codes. decided is false when the language of the article could not be
+// detected and UnknownLanguage is not selected: the condition then matches the article
+// neither as is nor negated.
+func MatchLanguage(lang string, values []string, singleValue string) (matched, decided bool) {
+	if len(values) == 0 {
+		if singleValue == "" {
+			return true, true
+		}
+		values = []string{singleValue}
+	}
+	known := langdetect.Normalize(lang) != ""
+	for _, val := range values {
+		if langdetect.Matches(lang, val) || (!known && langdetect.Normalize(val) == UnknownLanguage) {
+			return true, true
+		}
+	}
+	return false, known
+}
+
 // applyAction applies an action to an article
 func (e *Engine) applyAction(articleID int64, action string) error {
 	switch action {
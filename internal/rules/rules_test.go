@@ -100,3 +100,44 @@ func TestEngine_ApplyRule(t *testing.T) {
 		t.Errorf("Expected 0 articles to be processed, got %d", count)
 	}
 }
+
+func TestEvaluateCondition_ArticleLanguage(t *testing.T) {
+	condition := Condition{Field: "article_language", Values: []string{"en", "zh"}}
+
+	tests := []struct {
+		lang string
+		want bool
+	}{
+		{"en", true},
+		{"zh", true},
+		{"ja", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		article := models.Article{Lang: tt.lang}
+		got := evaluateCondition(article, condition, nil, nil, nil, nil, nil)
+		if got != tt.want {
+			t.Errorf("language %q: expected %v, got %v", tt.lang, tt.want, got)
+		}
+	}
+
+	// "Hide articles not in English or Chinese" uses the NOT modifier
+	condition.Negate = true
+	if !evaluateCondition(models.Article{Lang: "de"}, condition, nil, nil, nil, nil, nil) {
+		t.Error("Expected negated condition to match German article")
+	}
+	// and leaves the articles whose language could not be detected alone
+	if evaluateCondition(models.Article{Lang: ""}, condition, nil, nil, nil, nil, nil) {
+		t.Error("Expected negated condition not to match article of unknown language")
+	}
+
+	// Articles of unknown language are selected explicitly
+	condition = Condition{Field: "article_language", Values: []string{"ja", UnknownLanguage}}
+	if !evaluateCondition(models.Article{Lang: ""}, condition, nil, nil, nil, nil, nil) {
+		t.Error("Expected unknown language condition to match article of unknown language")
+	}
+	if evaluateCondition(models.Article{Lang: "en"}, condition, nil, nil, nil, nil, nil) {
+		t.Error("Expected unknown language condition not to match English article")
+	}
+}
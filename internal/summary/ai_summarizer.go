@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/config"
+	"MrRSS/internal/metrics"
 	"MrRSS/internal/utils"
 )
 
@@ -155,37 +157,39 @@ func (s *AISummarizer) Summarize(text string, length SummaryLength) (SummaryResu
 	userPrompt := fmt.Sprintf("Summarize the following text in approximately %d words:\n\n%s", targetWords, cleanedText)
 
 	// Try OpenAI format first
-	result, thinking, err := s.tryOpenAIFormat(systemPrompt, userPrompt)
-	if err == nil {
+	result, thinking, usage, openAIErr := s.tryOpenAIFormat(systemPrompt, userPrompt)
+	if openAIErr == nil {
 		// Count sentences in the summary
-		sentences := splitSentences(result)
+		sentences := splitSentences(result, detectLanguage(result))
 		return SummaryResult{
 			Summary:       result,
 			Thinking:      thinking,
 			SentenceCount: len(sentences),
 			IsTooShort:    false,
+			Usage:         usage,
 		}, nil
 	}
 
 	// If OpenAI format fails, try Ollama format
-	result, thinking, err = s.tryOllamaFormat(systemPrompt, userPrompt)
-	if err == nil {
+	result, thinking, usage, ollamaErr := s.tryOllamaFormat(systemPrompt, userPrompt)
+	if ollamaErr == nil {
 		// Count sentences in the summary
-		sentences := splitSentences(result)
+		sentences := splitSentences(result, detectLanguage(result))
 		return SummaryResult{
 			Summary:       result,
 			Thinking:      thinking,
 			SentenceCount: len(sentences),
 			IsTooShort:    false,
+			Usage:         usage,
 		}, nil
 	}
 
 	// Both formats failed
-	return SummaryResult{}, fmt.Errorf("all API formats failed: OpenAI error: %v, Ollama error: %v", err, err)
+	return SummaryResult{}, fmt.Errorf("all API formats failed: OpenAI error: %v, Ollama error: %v", openAIErr, ollamaErr)
 }
 
 // tryOpenAIFormat attempts to use OpenAI-compatible API format
-func (s *AISummarizer) tryOpenAIFormat(systemPrompt, userPrompt string) (string, string, error) {
+func (s *AISummarizer) tryOpenAIFormat(systemPrompt, userPrompt string) (string, string, aiusage.Usage, error) {
 	requestBody := map[string]interface{}{
 		"model": s.Model,
 		"messages": []map[string]string{
@@ -197,17 +201,17 @@ func (s *AISummarizer) tryOpenAIFormat(systemPrompt, userPrompt string) (string,
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to marshal OpenAI request: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("failed to marshal OpenAI request: %w", err)
 	}
 
 	resp, err := s.sendRequest(jsonBody)
 	if err != nil {
-		return "", "", fmt.Errorf("OpenAI request failed: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("OpenAI request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("OpenAI API returned status: %d", resp.StatusCode)
+		return "", "", aiusage.Usage{}, fmt.Errorf("OpenAI API returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -216,10 +220,14 @@ func (s *AISummarizer) tryOpenAIFormat(systemPrompt, userPrompt string) (string,
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int64 `json:"prompt_tokens"`
+			CompletionTokens int64 `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", "", fmt.Errorf("failed to decode OpenAI response: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("failed to decode OpenAI response: %w", err)
 	}
 
 	if len(result.Choices) > 0 && result.Choices[0].Message.Content != "" {
@@ -227,14 +235,14 @@ func (s *AISummarizer) tryOpenAIFormat(systemPrompt, userPrompt string) (string,
 		content := strings.TrimSpace(result.Choices[0].Message.Content)
 		thinking := extractThinking(content)
 		summary := removeThinkingTags(content)
-		return summary, thinking, nil
+		return summary, thinking, aiusage.Usage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}, nil
 	}
 
-	return "", "", fmt.Errorf("no summary found in OpenAI response")
+	return "", "", aiusage.Usage{}, fmt.Errorf("no summary found in OpenAI response")
 }
 
 // tryOllamaFormat attempts to use Ollama API format
-func (s *AISummarizer) tryOllamaFormat(systemPrompt, userPrompt string) (string, string, error) {
+func (s *AISummarizer) tryOllamaFormat(systemPrompt, userPrompt string) (string, string, aiusage.Usage, error) {
 	// Combine system and user prompts for Ollama
 	fullPrompt := systemPrompt + "\n\n" + userPrompt
 
@@ -246,26 +254,28 @@ func (s *AISummarizer) tryOllamaFormat(systemPrompt, userPrompt string) (string,
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to marshal Ollama request: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("failed to marshal Ollama request: %w", err)
 	}
 
 	resp, err := s.sendRequest(jsonBody)
 	if err != nil {
-		return "", "", fmt.Errorf("Ollama request failed: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("Ollama request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("Ollama API returned status: %d", resp.StatusCode)
+		return "", "", aiusage.Usage{}, fmt.Errorf("Ollama API returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Response        string `json:"response"`
+		Done            bool   `json:"done"`
+		PromptEvalCount int64  `json:"prompt_eval_count"`
+		EvalCount       int64  `json:"eval_count"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", "", fmt.Errorf("failed to decode Ollama response: %w", err)
+		return "", "", aiusage.Usage{}, fmt.Errorf("failed to decode Ollama response: %w", err)
 	}
 
 	if result.Done && result.Response != "" {
@@ -273,10 +283,10 @@ func (s *AISummarizer) tryOllamaFormat(systemPrompt, userPrompt string) (string,
 		content := strings.TrimSpace(result.Response)
 		thinking := extractThinking(content)
 		summary := removeThinkingTags(content)
-		return summary, thinking, nil
+		return summary, thinking, aiusage.Usage{PromptTokens: result.PromptEvalCount, CompletionTokens: result.EvalCount}, nil
 	}
 
-	return "", "", fmt.Errorf("no summary found in Ollama response")
+	return "", "", aiusage.Usage{}, fmt.Errorf("no summary found in Ollama response")
 }
 
 // sendRequest sends the HTTP request with proper headers
@@ -317,7 +327,10 @@ func (s *AISummarizer) sendRequest(jsonBody []byte) (*http.Response, error) {
 		}
 	}
 
-	return s.client.Do(req)
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	metrics.ObserveAIRequest(aiusage.FeatureSummary, start, resp, err)
+	return resp, err
 }
 
 // isLocalEndpoint checks if a host is a local endpoint (localhost, 127.0.0.1, etc.)
@@ -0,0 +1,60 @@
+package summary
+
+// Highlight is a key sentence located in the original content.
+// Offsets count Unicode code points in the content the highlight was computed from,
+// so the sentence can be marked in place in the article HTML.
+type Highlight struct {
+	Text  string  `json:"text"`
+	Start int     `json:"start"` // Offset of the first character of the sentence
+	End   int     `json:"end"`   // Offset just after the last character of the sentence
+	Score float64 `json:"score"` // Relative importance between 0 and 1
+}
+
+// HighlightResult contains the key sentences of a text and metadata
+type HighlightResult struct {
+	Language   string      `json:"language"`
+	Highlights []Highlight `json:"highlights"`
+	IsTooShort bool        `json:"is_too_short"`
+}
+
+// Highlights selects the key sentences of HTML content, like Summarize, and returns
+// their positions in the content instead of a summary text. The selected sentences
+// are returned in document order.
+func (s *Summarizer) Highlights(content string, length SummaryLength) HighlightResult {
+	doc := analyze(content)
+	result := HighlightResult{
+		Language:   doc.lang.code,
+		Highlights: []Highlight{},
+	}
+
+	if len(doc.text.String()) < MinContentLength || len(doc.sentences) < MinSentenceCount {
+		result.IsTooShort = true
+		return result
+	}
+
+	selected := s.selectSentences(doc, length)
+
+	maxScore := 0.0
+	for _, sent := range selected {
+		if sent.score > maxScore {
+			maxScore = sent.score
+		}
+	}
+
+	for _, sent := range selected {
+		span := doc.sentences[sent.position]
+		start, end := doc.text.sourceRange(span.start, span.end)
+		score := 0.0
+		if maxScore > 0 {
+			score = sent.score / maxScore
+		}
+		result.Highlights = append(result.Highlights, Highlight{
+			Text:  span.text,
+			Start: start,
+			End:   end,
+			Score: score,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,169 @@
+package summary
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultKeyphraseCount is the number of keyphrases extracted for an article
+const DefaultKeyphraseCount = 8
+
+// maxKeyphraseWords is the maximum number of words in a keyphrase
+const maxKeyphraseWords = 3
+
+// Keyphrase is a phrase that characterizes a text
+type Keyphrase struct {
+	Phrase string  `json:"phrase"`
+	Score  float64 `json:"score"` // Relative importance between 0 and 1
+}
+
+// keyphraseCandidate accumulates the statistics of a candidate phrase
+type keyphraseCandidate struct {
+	words []string
+	count int
+	first int // Position of the first occurrence
+}
+
+// Keyphrases extracts up to limit keyphrases from HTML content, best first.
+// Candidates are runs of up to three consecutive content words (single words in
+// Chinese and Japanese) that occur at least twice. They are ranked by how often
+// they and their words occur, favoring multi-word phrases and early mentions.
+func (s *Summarizer) Keyphrases(content string, limit int) []Keyphrase {
+	doc := analyze(content)
+	keyphrases := []Keyphrase{}
+	if limit <= 0 {
+		return keyphrases
+	}
+
+	candidates := make(map[string]*keyphraseCandidate)
+	wordFreq := make(map[string]int)
+	position := 0
+
+	sentences := doc.sentences
+	if len(sentences) == 0 {
+		// Text without sentence punctuation, such as a short description
+		text := string(doc.text.runes)
+		sentences = []sentence{{text: text}}
+	}
+
+	for _, sent := range sentences {
+		for _, phrase := range doc.lang.phrases(sent.text) {
+			for _, word := range phrase {
+				wordFreq[word]++
+			}
+			for n := 1; n <= maxKeyphraseWords && n <= len(phrase); n++ {
+				for i := 0; i+n <= len(phrase); i++ {
+					words := phrase[i : i+n]
+					if isNumeric(words[0]) || isNumeric(words[n-1]) {
+						continue
+					}
+					key := strings.Join(words, " ")
+					c, ok := candidates[key]
+					if !ok {
+						c = &keyphraseCandidate{words: words, first: position}
+						candidates[key] = c
+					}
+					c.count++
+				}
+			}
+			position++
+		}
+	}
+
+	type scored struct {
+		phrase string
+		words  []string
+		score  float64
+	}
+	var ranked []scored
+	for phrase, c := range candidates {
+		if c.count < 2 {
+			continue
+		}
+		sumFreq := 0
+		for _, w := range c.words {
+			sumFreq += wordFreq[w]
+		}
+		meanFreq := float64(sumFreq) / float64(len(c.words))
+		lengthBoost := 1 + 0.5*float64(len(c.words)-1)
+		positionBoost := 1 + 0.5/math.Sqrt(float64(c.first+1))
+		ranked = append(ranked, scored{
+			phrase: phrase,
+			words:  c.words,
+			score:  float64(c.count) * meanFreq * lengthBoost * positionBoost,
+		})
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		return ranked[i].phrase < ranked[j].phrase
+	})
+
+	var selected []scored
+	for _, candidate := range ranked {
+		if len(selected) >= limit {
+			break
+		}
+		overlaps := false
+		for _, kept := range selected {
+			if phrasesOverlap(candidate.words, kept.words) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			selected = append(selected, candidate)
+		}
+	}
+
+	for _, k := range selected {
+		keyphrases = append(keyphrases, Keyphrase{
+			Phrase: k.phrase,
+			Score:  k.score / selected[0].score,
+		})
+	}
+	return keyphrases
+}
+
+// phrasesOverlap reports whether one phrase is contained in the other, so that
+// "learning" is not suggested next to "machine learning".
+func phrasesOverlap(a, b []string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) == 1 && len(b) == 1 && isCJKWord(a[0]) && isCJKWord(b[0]) {
+		// Chinese and Japanese words are not separated by spaces: compare as substrings
+		return strings.Contains(b[0], a[0]) || strings.Contains(a[0], b[0])
+	}
+	inB := make(map[string]bool, len(b))
+	for _, w := range b {
+		inB[w] = true
+	}
+	for _, w := range a {
+		if !inB[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// isCJKWord reports whether a word is written in Chinese characters or kana.
+func isCJKWord(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return isCJKChar(r)
+}
+
+// isNumeric reports whether a word consists only of digits.
+func isNumeric(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,249 @@
+package summary
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"MrRSS/internal/langdetect"
+)
+
+// language holds the rules used to split and tokenize text in one language
+type language struct {
+	code          string
+	stopwords     map[string]bool
+	abbreviations map[string]bool // Lowercase words that are followed by a period without ending a sentence
+}
+
+// languages supported by the local summarizer, keyed by ISO 639-1 code.
+// Other languages are processed with the English rules.
+var languages = map[string]*language{
+	"en": {code: "en", stopwords: englishStopwords, abbreviations: wordSet("mr mrs ms dr prof sr jr st vs etc e.g i.e inc ltd co corp no fig approx dept est govt gen sen rep u.s u.k a.m p.m")},
+	"de": {code: "de", stopwords: germanStopwords, abbreviations: wordSet("z.b bzw usw ca dr prof nr str vgl evtl ggf inkl u.a d.h s abs bspw gem jh mio mrd")},
+	"fr": {code: "fr", stopwords: frenchStopwords, abbreviations: wordSet("m mm mme mlle dr pr etc p.ex cf av bd st ste env min max")},
+	"es": {code: "es", stopwords: spanishStopwords, abbreviations: wordSet("sr sra srta dr dra ud uds etc p.ej núm pág aprox av ee.uu")},
+	"it": {code: "it", stopwords: italianStopwords, abbreviations: wordSet("sig sig.ra dott prof ing avv ecc pag n es ca")},
+	"pt": {code: "pt", stopwords: portugueseStopwords, abbreviations: wordSet("sr sra dr dra etc pág núm av aprox prof")},
+	"nl": {code: "nl", stopwords: dutchStopwords, abbreviations: wordSet("dhr mevr dr bijv enz o.a d.w.z nr blz ca prof")},
+	"ru": {code: "ru", stopwords: russianStopwords, abbreviations: wordSet("т.е т.д т.п г гг им др стр см ул")},
+	"zh": {code: "zh", stopwords: chineseStopwords},
+	"ja": {code: "ja", stopwords: japaneseStopwords},
+	"ko": {code: "ko", stopwords: koreanStopwords},
+}
+
+// languageFor returns the rules for a language code, falling back to English.
+func languageFor(code string) *language {
+	if l, ok := languages[langdetect.Normalize(code)]; ok {
+		return l
+	}
+	return languages["en"]
+}
+
+// detectLanguage detects the language of text and returns its rules.
+func detectLanguage(text string) *language {
+	if code := langdetect.Detect(text); code != "" {
+		return languageFor(code)
+	}
+	// Short or mixed texts: keep the previous heuristic for Chinese
+	if isChineseText(text) {
+		return languages["zh"]
+	}
+	return languages["en"]
+}
+
+// charBased reports whether length is measured in characters rather than words
+// (languages written without spaces between words).
+func (l *language) charBased() bool {
+	return l.code == "zh" || l.code == "ja"
+}
+
+// isStopWord checks if a word is a stopword. English stopwords are always
+// recognized since English terms are common in other languages.
+func (l *language) isStopWord(word string) bool {
+	if l.stopwords[word] || englishStopwords[word] {
+		return true
+	}
+	// Chinese words may appear in Japanese text and vice versa
+	if l.code == "ja" || containsHan(word) {
+		return chineseStopwords[word] || japaneseStopwords[word]
+	}
+	return false
+}
+
+// tokenize splits text into lowercase tokens, removing stopwords.
+// Chinese is segmented with gse, Japanese by runs of kanji and katakana,
+// Korean by words with trailing particles removed, and other languages by words.
+func (l *language) tokenize(text string) []string {
+	var tokens []string
+	for _, phrase := range l.phrases(text) {
+		tokens = append(tokens, phrase...)
+	}
+	return tokens
+}
+
+// phrases splits text into runs of consecutive content words. Stopwords and
+// punctuation separate runs; in languages without spaces each word is its own run.
+func (l *language) phrases(text string) [][]string {
+	text = strings.ToLower(text)
+
+	if l.code == "zh" || (l.code != "ja" && containsHan(text)) {
+		return l.chinesePhrases(text)
+	}
+
+	var phrases [][]string
+	var current []string
+	endPhrase := func() {
+		if len(current) > 0 {
+			phrases = append(phrases, current)
+			current = nil
+		}
+	}
+
+	var word strings.Builder
+	var wordScript rune // 'k' for kanji/katakana, 'h' for hiragana, 'w' for other letters
+	endWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		w := word.String()
+		word.Reset()
+		switch wordScript {
+		case 'h':
+			// Hiragana is mostly particles and inflections
+			endPhrase()
+			return
+		case 'k':
+			if l.isStopWord(w) {
+				endPhrase()
+				return
+			}
+			// Japanese words are not combined into longer phrases
+			endPhrase()
+			current = append(current, w)
+			endPhrase()
+			return
+		}
+		if l.code == "ko" {
+			w = trimKoreanParticle(w)
+		}
+		// Require at least 3 letters (2 Hangul syllables) to skip fragments
+		minLen := 3
+		if isHangulWord(w) {
+			minLen = 2
+		}
+		if utf8.RuneCountInString(w) < minLen || l.isStopWord(w) {
+			endPhrase()
+			return
+		}
+		current = append(current, w)
+	}
+
+	for _, r := range text {
+		script := rune(0)
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Katakana, r) || r == 'ー':
+			script = 'k'
+		case unicode.Is(unicode.Hiragana, r):
+			script = 'h'
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			script = 'w'
+		}
+		if script != wordScript && word.Len() > 0 {
+			endWord()
+		}
+		if script == 0 {
+			if !unicode.IsSpace(r) {
+				// Punctuation separates phrases
+				endPhrase()
+			}
+			wordScript = 0
+			continue
+		}
+		wordScript = script
+		word.WriteRune(r)
+	}
+	endWord()
+	endPhrase()
+
+	return phrases
+}
+
+// chinesePhrases segments Chinese text with gse. Each word is its own phrase.
+func (l *language) chinesePhrases(text string) [][]string {
+	seg := getSegmenter()
+	segments := seg.Cut(text, true) // true = search mode for better recall
+
+	var phrases [][]string
+	for _, word := range segments {
+		word = strings.TrimSpace(word)
+		// Skip empty strings, stopwords, and very short words
+		if len(word) == 0 || l.isStopWord(word) {
+			continue
+		}
+		// For Chinese, single characters can be meaningful
+		// For other scripts, require at least 3 characters
+		if containsHan(word) || utf8.RuneCountInString(word) > 2 {
+			if isPunctuation(word) {
+				continue
+			}
+			phrases = append(phrases, []string{word})
+		}
+	}
+	return phrases
+}
+
+// koreanParticles are common postpositions attached to Korean nouns, longest first
+var koreanParticles = []string{"에서는", "으로는", "에게서", "에서", "으로", "에게", "까지", "부터", "처럼", "보다", "은", "는", "이", "가", "을", "를", "에", "의", "와", "과", "도", "로", "만"}
+
+// trimKoreanParticle removes a trailing particle from a Korean word, keeping at least two syllables.
+func trimKoreanParticle(word string) string {
+	if !isHangulWord(word) {
+		return word
+	}
+	for _, p := range koreanParticles {
+		if strings.HasSuffix(word, p) && utf8.RuneCountInString(word)-utf8.RuneCountInString(p) >= 2 {
+			return strings.TrimSuffix(word, p)
+		}
+	}
+	return word
+}
+
+// containsHan reports whether text contains Chinese characters.
+func containsHan(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHangulWord reports whether a word is written in Hangul.
+func isHangulWord(word string) bool {
+	for _, r := range word {
+		if unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPunctuation reports whether a word consists only of punctuation and symbols.
+func isPunctuation(word string) bool {
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// wordSet builds a lookup set from a space separated word list
+func wordSet(words string) map[string]bool {
+	fields := strings.Fields(words)
+	set := make(map[string]bool, len(fields))
+	for _, w := range fields {
+		set[w] = true
+	}
+	return set
+}
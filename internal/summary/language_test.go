@@ -0,0 +1,181 @@
+package summary
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The government announced a new plan for the economy and the schools.", "en"},
+		{"Die Regierung hat einen neuen Plan für die Wirtschaft und die Schulen angekündigt.", "de"},
+		{"Le gouvernement a annoncé un nouveau plan pour les écoles et la santé.", "fr"},
+		{"政府は経済と学校のための新しい計画を発表しました。", "ja"},
+		{"정부는 경제와 학교를 위한 새로운 계획을 발표했습니다.", "ko"},
+		{"政府宣布了一项新的经济和学校计划。", "zh"},
+	}
+
+	for _, tt := range tests {
+		if got := detectLanguage(tt.text).code; got != tt.want {
+			t.Errorf("detectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitSentences_Languages(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+		want int
+	}{
+		// Abbreviations and initials do not end sentences
+		{"en", "Dr. Smith met J. R. Jones at 3.5 km from the U.S. border today. They talked for an hour.", 2},
+		{"de", "Es gibt viele Beispiele, z.B. Äpfel und Birnen im Garten. Das ist ein zweiter Satz hier.", 2},
+		{"fr", "M. Dupont est arrivé hier soir à Paris. Il repart demain matin pour Lyon.", 2},
+		// A lowercase continuation does not start a new sentence
+		{"en", "The release is called v2. it ships next week with many fixes.", 1},
+		// CJK punctuation ends sentences without spaces
+		{"ja", "今日は東京で大きな会議が開かれました。多くの企業が新しい製品を発表しました！来年も開催される予定です。", 3},
+		{"zh", "今天在北京召开了一次重要会议。许多公司发布了新的产品和服务！", 2},
+		{"ko", "오늘 서울에서 큰 회의가 열렸습니다. 많은 기업이 새로운 제품을 발표했습니다.", 2},
+		// Line breaks from block elements end sentences
+		{"en", "A headline without punctuation\nThe first paragraph starts here.", 2},
+	}
+
+	for _, tt := range tests {
+		sentences := splitSentences(tt.text, languageFor(tt.lang))
+		if len(sentences) != tt.want {
+			t.Errorf("splitSentences(%q) = %d sentences %q, want %d", tt.text, len(sentences), sentences, tt.want)
+		}
+	}
+}
+
+func TestTokenize_Languages(t *testing.T) {
+	tests := []struct {
+		lang    string
+		text    string
+		want    []string
+		notWant []string
+	}{
+		{"de", "Die Regierung und der Kanzler haben den Haushalt beschlossen", []string{"regierung", "kanzler", "haushalt"}, []string{"die", "und", "haben"}},
+		{"es", "El gobierno y los ciudadanos votaron para la reforma", []string{"gobierno", "ciudadanos", "reforma"}, []string{"los", "para"}},
+		{"ja", "東京で新しいスマートフォンが発表されました", []string{"東京", "新", "スマートフォン", "発表"}, []string{"で", "が"}},
+		{"ko", "그리고 정부는 새로운 정책을 발표했다", []string{"정부", "정책"}, []string{"그리고", "정부는", "정책을"}},
+	}
+
+	for _, tt := range tests {
+		tokens := languageFor(tt.lang).tokenize(tt.text)
+		found := make(map[string]bool)
+		for _, token := range tokens {
+			found[token] = true
+		}
+		for _, w := range tt.want {
+			if !found[w] {
+				t.Errorf("%s: tokenize(%q) = %q, missing %q", tt.lang, tt.text, tokens, w)
+			}
+		}
+		for _, w := range tt.notWant {
+			if found[w] {
+				t.Errorf("%s: tokenize(%q) should remove %q", tt.lang, tt.text, w)
+			}
+		}
+	}
+}
+
+func TestExtractPlainText(t *testing.T) {
+	source := `<h1>Title</h1><p>Caf&eacute; &amp; bar.</p><script>var x = "<p>";</script><p>Next  line</p>`
+	pt := extractPlainText(source)
+
+	if got, want := string(pt.runes), "Title\nCafé & bar.\nNext line"; got != want {
+		t.Fatalf("extractPlainText = %q, want %q", got, want)
+	}
+
+	// Offsets map text back to the source
+	runes := []rune(source)
+	text := string(pt.runes)
+	idx := len([]rune(text[:strings.Index(text, "Café")]))
+	start, end := pt.sourceRange(idx, idx+len([]rune("Café & bar.")))
+	if got := string(runes[start:end]); got != "Caf&eacute; &amp; bar." {
+		t.Errorf("sourceRange = %q", got)
+	}
+}
+
+func TestHighlights(t *testing.T) {
+	s := NewSummarizer()
+	content := `<h2>Natural language processing</h2>
+		<p>Natural language processing is a field of <strong>artificial intelligence</strong>.</p>
+		<p>It focuses on the interaction between computers and humans using natural language.</p>
+		<p>The ultimate goal is to enable computers to understand, interpret, and generate human language.</p>
+		<p>NLP combines computational linguistics with machine learning and deep learning.</p>
+		<p>Applications include machine translation, sentiment analysis, and text summarization.</p>
+		<p>Modern NLP uses transformer models that have revolutionized the field.</p>`
+
+	result := s.Highlights(content, Short)
+	if result.IsTooShort {
+		t.Fatal("expected content to be long enough")
+	}
+	if result.Language != "en" {
+		t.Errorf("expected language en, got %q", result.Language)
+	}
+	if len(result.Highlights) == 0 {
+		t.Fatal("expected highlights")
+	}
+
+	runes := []rune(content)
+	previousEnd := 0
+	for _, h := range result.Highlights {
+		if h.Start < previousEnd || h.End <= h.Start || h.End > len(runes) {
+			t.Fatalf("invalid or unordered highlight offsets: %+v", h)
+		}
+		previousEnd = h.End
+		// The highlighted source, without markup, is the sentence
+		if got := extractPlainText(string(runes[h.Start:h.End])).String(); got != h.Text {
+			t.Errorf("highlight source %q does not match sentence %q", got, h.Text)
+		}
+		if h.Score <= 0 || h.Score > 1 {
+			t.Errorf("score out of range: %f", h.Score)
+		}
+	}
+
+	if short := s.Highlights("<p>Too short.</p>", Short); !short.IsTooShort || len(short.Highlights) != 0 {
+		t.Errorf("expected no highlights for short content, got %+v", short)
+	}
+}
+
+func TestKeyphrases(t *testing.T) {
+	s := NewSummarizer()
+	content := `Machine learning is changing how companies build software. Machine learning models
+		need large amounts of training data. Companies collect training data from many sources.
+		The quality of training data decides how well machine learning models perform.
+		Some companies buy training data from specialized vendors.`
+
+	keyphrases := s.Keyphrases(content, 3)
+	if len(keyphrases) == 0 {
+		t.Fatal("expected keyphrases")
+	}
+
+	phrases := make([]string, len(keyphrases))
+	for i, k := range keyphrases {
+		phrases[i] = k.Phrase
+	}
+	joined := strings.Join(phrases, "|")
+	if !strings.Contains(joined, "training data") || !strings.Contains(joined, "machine learning") {
+		t.Errorf("expected training data and machine learning in %q", phrases)
+	}
+	for _, p := range phrases {
+		if p == "learning" || p == "data" {
+			t.Errorf("sub-phrase %q should be merged into its longer phrase", p)
+		}
+	}
+	if keyphrases[0].Score != 1 {
+		t.Errorf("expected best keyphrase to score 1, got %f", keyphrases[0].Score)
+	}
+
+	japanese := "新しいスマートフォンが発表されました。スマートフォンの価格は高いです。多くの人がスマートフォンを買います。"
+	if keyphrases := s.Keyphrases(japanese, 3); len(keyphrases) == 0 || keyphrases[0].Phrase != "スマートフォン" {
+		t.Errorf("expected スマートフォン as Japanese keyphrase, got %+v", keyphrases)
+	}
+}
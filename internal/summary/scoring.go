@@ -4,14 +4,13 @@ import (
 	"math"
 )
 
-// calculateTFIDF computes TF-IDF scores for each sentence
-func calculateTFIDF(sentences []string) []float64 {
+// calculateTFIDF computes TF-IDF scores for each sentence from its tokens
+func calculateTFIDF(sentenceTokens [][]string) []float64 {
 	// Build document frequency map
 	docFreq := make(map[string]int)
-	allTerms := make([]map[string]int, len(sentences))
+	allTerms := make([]map[string]int, len(sentenceTokens))
 
-	for i, sentence := range sentences {
-		terms := tokenize(sentence)
+	for i, terms := range sentenceTokens {
 		termFreq := make(map[string]int)
 		seenTerms := make(map[string]bool)
 
@@ -25,8 +24,8 @@ func calculateTFIDF(sentences []string) []float64 {
 		allTerms[i] = termFreq
 	}
 
-	numDocs := float64(len(sentences))
-	scores := make([]float64, len(sentences))
+	numDocs := float64(len(sentenceTokens))
+	scores := make([]float64, len(sentenceTokens))
 
 	for i, termFreq := range allTerms {
 		var score float64
@@ -64,8 +63,8 @@ func calculateTFIDF(sentences []string) []float64 {
 }
 
 // calculateTextRank computes TextRank scores using sentence similarity
-func calculateTextRank(sentences []string) []float64 {
-	n := len(sentences)
+func calculateTextRank(sentenceTokens [][]string) []float64 {
+	n := len(sentenceTokens)
 	if n == 0 {
 		return []float64{}
 	}
@@ -78,7 +77,7 @@ func calculateTextRank(sentences []string) []float64 {
 
 	for i := 0; i < n; i++ {
 		for j := i + 1; j < n; j++ {
-			sim := sentenceSimilarity(sentences[i], sentences[j])
+			sim := sentenceSimilarity(sentenceTokens[i], sentenceTokens[j])
 			similarity[i][j] = sim
 			similarity[j][i] = sim
 		}
@@ -134,11 +133,8 @@ func calculateTextRank(sentences []string) []float64 {
 	return scores
 }
 
-// sentenceSimilarity calculates similarity between two sentences using word overlap
-func sentenceSimilarity(s1, s2 string) float64 {
-	words1 := tokenize(s1)
-	words2 := tokenize(s2)
-
+// sentenceSimilarity calculates similarity between the tokens of two sentences using word overlap
+func sentenceSimilarity(words1, words2 []string) float64 {
 	if len(words1) == 0 || len(words2) == 0 {
 		return 0
 	}
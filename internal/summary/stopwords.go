@@ -0,0 +1,104 @@
+package summary
+
+// Stopword lists of the languages supported by the local summarizer.
+// They cover function words and very common verbs that carry little meaning.
+
+var englishStopwords = wordSet(`
+	the a an and or but in on at to for of with by from as is was are were been be
+	have has had do does did will would could should may might must shall can this
+	that these those it its they them their what which who whom whose where when why
+	how all each every both few more most other some such than too very just only own
+	same so not also into about your you our his her my we he she over out up down
+	then now said says one two new like get got after before while there here any
+	many much because if been being under again further once against between through
+	during above below off until
+`)
+
+var germanStopwords = wordSet(`
+	der die das den dem des ein eine einer eines einem einen und oder aber ist sind war
+	waren wird werden wurde wurden hat haben hatte hatten sein ich du er sie es wir ihr
+	mit von zu zum zur auf aus bei nach vor über unter für durch gegen ohne um an am im
+	in ins nicht auch noch nur schon sehr wie als wenn dass ob so dann doch denn man
+	sich mehr kann können muss müssen soll sollen will wollen diese dieser dieses diesem
+	diesen jede jeder jedes alle allem allen sein seine seiner ihre ihren ihrem unser
+	hier dort heute immer wieder bereits etwa sowie zwischen seit bis beim vom einem
+`)
+
+var frenchStopwords = wordSet(`
+	le la les un une des du de et ou mais est sont était étaient être avoir a ont avait
+	il elle ils elles on nous vous je tu ce cet cette ces ceux celle qui que quoi dont où
+	en dans sur sous par pour avec sans chez vers entre pas ne plus moins très aussi
+	comme si leur leurs son sa ses mon ma mes ton ta tes notre nos votre vos au aux
+	tout tous toute toutes fait faire peut peuvent sont été déjà encore alors ainsi
+	donc car selon depuis lors avant après
+`)
+
+var spanishStopwords = wordSet(`
+	el la los las un una unos unas de del y o pero es son era eran ser estar está
+	están fue fueron ha han había haber en por para con sin sobre entre hasta desde
+	que qué quien cual cuando donde como más menos muy también ya no se su sus le les
+	lo al este esta estos estas ese esa esos esas aquel todo todos toda todas otro
+	otra otros otras mismo puede pueden según tras durante ante bajo nos yo tú él ella
+	ellos ellas nosotros
+`)
+
+var italianStopwords = wordSet(`
+	il lo la i gli le un uno una di del dello della dei degli delle a al allo alla ai
+	agli alle da dal dallo dalla dai dagli dalle in nel nello nella nei negli nelle su
+	sul sullo sulla sui sugli sulle con per tra fra e ed o ma che chi cui non più
+	anche come dove quando se è sono era erano essere stato stata ha hanno aveva avere
+	questo questa questi queste quello quella quelli quelle suo sua suoi sue loro
+	molto tutto tutti tutta tutte già ancora solo dopo prima così può possono
+`)
+
+var portugueseStopwords = wordSet(`
+	o a os as um uma uns umas de do da dos das e ou mas é são era eram ser estar está
+	estão foi foram tem têm tinha ter em no na nos nas por pelo pela pelos pelas para
+	com sem sobre entre até desde que quem qual quando onde como mais menos muito
+	também já não se seu sua seus suas lhe ao aos este esta estes estas esse essa
+	esses essas aquele aquela isso isto todo todos toda todas outro outra outros
+	outras mesmo pode podem segundo após durante ele ela eles elas nós
+`)
+
+var dutchStopwords = wordSet(`
+	de het een en of maar is zijn was waren wordt worden werd werden heeft hebben had
+	hadden ik jij je hij zij ze wij we jullie u men van in op te met voor aan bij door
+	naar uit over onder tegen zonder om tot tussen niet ook nog al wel geen meer dan
+	als dat die dit deze wat wie waar wanneer hoe zo er hier daar kan kunnen moet
+	moeten zal zullen wil willen zich haar hun ons onze zijn
+`)
+
+var russianStopwords = wordSet(`
+	и в во не что он на я с со как а то все она так его но да ты к у же вы за бы по
+	только ее мне было вот от меня еще нет о из ему теперь когда даже ну вдруг ли
+	если уже или ни быть был него до вас нибудь опять уж вам ведь там потом себя
+	ничего ей может они тут где есть надо ней для мы тебя их чем была сам чтоб без
+	будто чего раз тоже себе под будет ж тогда кто этот того потому этого какой
+	совсем ним здесь этом один почти мой тем чтобы нее сейчас были куда зачем всех
+	никогда можно при наконец два об другой хоть после над больше тот через эти нас
+	про всего них какая много разве три эту моя впрочем хорошо свою этой перед иногда
+	лучше чуть том нельзя такой им более всегда конечно всю между это также которые
+	который которая которых
+`)
+
+var chineseStopwords = wordSet(`
+	的 了 和 是 在 有 这 个 我 不 人 都 一 他 就 们 上 也 你 说 着 对 为 与 而 等 被 把
+	让 给 向 从 到 之 于 或 因 但 却 即 若 虽 所 以 如 则 其 它 她 这个 那个 什么 怎么
+	为什么 哪个 哪些 这些 那些 可以 能够 已经 正在 将要 可能 应该 必须 需要 没有 因为
+	所以 但是 而且 或者 如果 虽然 然 此 彼 自己 我们 你们 他们 它们 这里 那里 哪里
+	任何 某些 每个 很 非常 十分 比较 更 最 太 又 再 还
+`)
+
+var japaneseStopwords = wordSet(`
+	これ それ あれ この その あの ここ そこ あそこ こと もの ため よう など として
+	について により による において 場合 一方 以上 以下 今回 今後 現在 同様 必要
+	可能 予定 関係 部分 時間 方法 問題 結果 目的 自分 私 彼 彼女 何 人 者 等 的
+	年 月 日 時 分
+`)
+
+var koreanStopwords = wordSet(`
+	그리고 그러나 하지만 그래서 그런데 또한 또는 및 등 더 또 그 이 저 것 수 때 중
+	위해 대한 대해 통해 따라 같은 이번 지난 오늘 있다 없다 한다 했다 하는 있는 없는
+	된다 됐다 되는 이다 아니다 밝혔다 말했다 전했다 우리 그들 이것 그것 저것 여기
+	거기 모든 어떤 가장 매우 아주 다른 각 좀
+`)
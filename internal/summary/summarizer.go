@@ -15,10 +15,32 @@ func NewSummarizer() *Summarizer {
 	return &Summarizer{}
 }
 
+// document is text prepared for sentence scoring
+type document struct {
+	text      plainText
+	lang      *language
+	sentences []sentence
+	tokens    [][]string // Tokens of each sentence
+}
+
+// analyze extracts the text of HTML content, detects its language and splits it
+// into tokenized sentences.
+func analyze(content string) document {
+	doc := document{text: extractPlainText(content)}
+	doc.lang = detectLanguage(doc.text.String())
+	doc.sentences = splitSentenceSpans(doc.text.runes, doc.lang)
+
+	doc.tokens = make([][]string, len(doc.sentences))
+	for i, sent := range doc.sentences {
+		doc.tokens[i] = doc.lang.tokenize(sent.text)
+	}
+	return doc
+}
+
 // Summarize generates a summary of the given text using combined TF-IDF and TextRank scoring
 func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult {
-	// Clean the text
-	cleanedText := cleanText(text)
+	doc := analyze(text)
+	cleanedText := doc.text.String()
 
 	// Check if text is too short
 	if len(cleanedText) < MinContentLength {
@@ -28,26 +50,38 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 		}
 	}
 
-	// Split into sentences
-	sentences := splitSentences(cleanedText)
-
 	// Check if we have enough sentences
-	if len(sentences) < MinSentenceCount {
+	if len(doc.sentences) < MinSentenceCount {
 		return SummaryResult{
 			Summary:       cleanedText,
-			SentenceCount: len(sentences),
+			SentenceCount: len(doc.sentences),
 			IsTooShort:    true,
 		}
 	}
 
-	// Check if text is primarily Chinese
-	isChinese := isChineseText(cleanedText)
+	selectedSentences := s.selectSentences(doc, length)
+
+	// Build summary
+	var summaryParts []string
+	for _, sent := range selectedSentences {
+		summaryParts = append(summaryParts, sent.text)
+	}
 
+	return SummaryResult{
+		Summary:       strings.Join(summaryParts, " "),
+		SentenceCount: len(selectedSentences),
+		IsTooShort:    false,
+	}
+}
+
+// selectSentences picks the best scoring sentences up to the target length of the
+// summary and returns them in their original order.
+func (s *Summarizer) selectSentences(doc document, length SummaryLength) []scoredSentence {
 	// Get target word/character count based on length setting
 	targetCount := getTargetWordCount(length)
 
 	// Score sentences using combined TF-IDF and TextRank
-	scoredSentences := s.scoreSentences(sentences)
+	scoredSentences := s.scoreSentences(doc)
 
 	// Sort by score (descending)
 	sort.Slice(scoredSentences, func(i, j int) bool {
@@ -59,7 +93,7 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 	currentCount := 0
 
 	for _, sent := range scoredSentences {
-		sentCount := countWordsOrChars(sent.text, isChinese)
+		sentCount := countWordsOrChars(sent.text, doc.lang.charBased())
 		if currentCount+sentCount <= targetCount || len(selectedSentences) == 0 {
 			selectedSentences = append(selectedSentences, sent)
 			currentCount += sentCount
@@ -75,37 +109,27 @@ func (s *Summarizer) Summarize(text string, length SummaryLength) SummaryResult
 		return selectedSentences[i].position < selectedSentences[j].position
 	})
 
-	// Build summary
-	var summaryParts []string
-	for _, sent := range selectedSentences {
-		summaryParts = append(summaryParts, sent.text)
-	}
-
-	return SummaryResult{
-		Summary:       strings.Join(summaryParts, " "),
-		SentenceCount: len(selectedSentences),
-		IsTooShort:    false,
-	}
+	return selectedSentences
 }
 
 // scoreSentences calculates scores for each sentence using combined TF-IDF and TextRank
-func (s *Summarizer) scoreSentences(sentences []string) []scoredSentence {
+func (s *Summarizer) scoreSentences(doc document) []scoredSentence {
 	// Calculate TF-IDF scores
-	tfidfScores := calculateTFIDF(sentences)
+	tfidfScores := calculateTFIDF(doc.tokens)
 
 	// Calculate TextRank scores
-	textRankScores := calculateTextRank(sentences)
+	textRankScores := calculateTextRank(doc.tokens)
 
 	// Calculate average sentence length for penalty calculation
 	totalLen := 0
-	for _, sent := range sentences {
-		totalLen += len(sent)
+	for _, sent := range doc.sentences {
+		totalLen += len(sent.text)
 	}
-	avgLen := float64(totalLen) / float64(len(sentences))
+	avgLen := float64(totalLen) / float64(len(doc.sentences))
 
 	// Combine scores
-	result := make([]scoredSentence, len(sentences))
-	for i, sentence := range sentences {
+	result := make([]scoredSentence, len(doc.sentences))
+	for i, sent := range doc.sentences {
 		// Weight TF-IDF at 0.5 and TextRank at 0.5
 		combinedScore := 0.5*tfidfScores[i] + 0.5*textRankScores[i]
 
@@ -116,7 +140,7 @@ func (s *Summarizer) scoreSentences(sentences []string) []scoredSentence {
 
 		// Penalize very long sentences (more than 2x average length)
 		// This prevents selecting overly verbose sentences
-		sentLen := float64(len(sentence))
+		sentLen := float64(len(sent.text))
 		if sentLen > avgLen*2 {
 			penalty := avgLen * 2 / sentLen
 			combinedScore *= penalty
@@ -129,7 +153,7 @@ func (s *Summarizer) scoreSentences(sentences []string) []scoredSentence {
 		}
 
 		result[i] = scoredSentence{
-			text:     sentence,
+			text:     sent.text,
 			score:    combinedScore,
 			position: i,
 		}
@@ -136,7 +136,7 @@ func TestCleanText(t *testing.T) {
 
 func TestSplitSentences(t *testing.T) {
 	text := "First sentence here. Second sentence here! Third sentence here?"
-	sentences := splitSentences(text)
+	sentences := splitSentences(text, languageFor("en"))
 
 	if len(sentences) < 1 {
 		t.Errorf("Expected at least 1 sentence, got %d", len(sentences))
@@ -145,7 +145,7 @@ func TestSplitSentences(t *testing.T) {
 
 func TestTokenize(t *testing.T) {
 	text := "The quick brown fox jumps over the lazy dog"
-	tokens := tokenize(text)
+	tokens := languageFor("en").tokenize(text)
 
 	// Should not contain common stopwords like "the"
 	for _, token := range tokens {
@@ -169,14 +169,15 @@ func TestIsStopWord(t *testing.T) {
 	stopWords := []string{"the", "a", "an", "and", "or", "in", "on", "at", "的", "了", "和"}
 	nonStopWords := []string{"computer", "algorithm", "processing", "模型", "技术"}
 
+	lang := languageFor("en")
 	for _, word := range stopWords {
-		if !isStopWord(word) {
+		if !lang.isStopWord(word) {
 			t.Errorf("%q should be a stopword", word)
 		}
 	}
 
 	for _, word := range nonStopWords {
-		if isStopWord(word) {
+		if lang.isStopWord(word) {
 			t.Errorf("%q should not be a stopword", word)
 		}
 	}
@@ -188,8 +189,9 @@ func TestSentenceSimilarity(t *testing.T) {
 	s2 := "Natural language processing is essential"
 	s3 := "Cooking recipes are delicious"
 
-	sim12 := sentenceSimilarity(s1, s2)
-	sim13 := sentenceSimilarity(s1, s3)
+	lang := languageFor("en")
+	sim12 := sentenceSimilarity(lang.tokenize(s1), lang.tokenize(s2))
+	sim13 := sentenceSimilarity(lang.tokenize(s1), lang.tokenize(s3))
 
 	if sim12 <= sim13 {
 		t.Errorf("Similar sentences should have higher similarity: sim(%q, %q)=%f <= sim(%q, %q)=%f",
@@ -204,7 +206,7 @@ func TestCalculateTFIDF(t *testing.T) {
 		"Natural language processing uses machine learning.",
 	}
 
-	scores := calculateTFIDF(sentences)
+	scores := calculateTFIDF(tokenizeAll(sentences))
 
 	if len(scores) != len(sentences) {
 		t.Errorf("Expected %d scores, got %d", len(sentences), len(scores))
@@ -225,7 +227,7 @@ func TestCalculateTextRank(t *testing.T) {
 		"Natural language processing uses machine learning.",
 	}
 
-	scores := calculateTextRank(sentences)
+	scores := calculateTextRank(tokenizeAll(sentences))
 
 	if len(scores) != len(sentences) {
 		t.Errorf("Expected %d scores, got %d", len(sentences), len(scores))
@@ -256,3 +258,12 @@ func TestSummarize_SingleSentence(t *testing.T) {
 		t.Error("Expected IsTooShort to be true for single sentence")
 	}
 }
+
+// tokenizeAll tokenizes English test sentences
+func tokenizeAll(sentences []string) [][]string {
+	tokens := make([][]string, len(sentences))
+	for i, sentence := range sentences {
+		tokens[i] = languageFor("en").tokenize(sentence)
+	}
+	return tokens
+}
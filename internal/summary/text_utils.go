@@ -1,6 +1,7 @@
 package summary
 
 import (
+	"html"
 	"regexp"
 	"strings"
 	"sync"
@@ -45,151 +46,267 @@ func cleanText(text string) string {
 	return strings.TrimSpace(text)
 }
 
-// splitSentences splits text into sentences
-func splitSentences(text string) []string {
-	// Simple sentence splitting with common abbreviations handling
-	// Split on sentence-ending punctuation followed by space (or end of text)
-	sentenceRegex := regexp.MustCompile(`([.!?。！？]+)(\s+|$)`)
+// blockTags end a sentence even without punctuation (e.g. headings and list items)
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "ul": true, "ol": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "table": true, "tr": true, "td": true, "th": true,
+	"section": true, "article": true, "header": true, "footer": true,
+	"figure": true, "figcaption": true, "hr": true, "dt": true, "dd": true,
+}
 
-	// Split the text
-	parts := sentenceRegex.Split(text, -1)
+// skippedTags have content that is never part of the readable text
+var skippedTags = map[string]bool{"script": true, "style": true, "noscript": true, "template": true}
 
-	// Get the delimiters
-	matches := sentenceRegex.FindAllStringSubmatch(text, -1)
+// plainText is the readable text of an HTML document. Each rune remembers the
+// rune offsets of the source it was extracted from, so that positions in the
+// text can be mapped back to the original HTML.
+type plainText struct {
+	runes  []rune
+	starts []int // Source offset where each rune starts
+	ends   []int // Source offset just after each rune
+}
 
-	var sentences []string
-	for i, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
-		}
+// extractPlainText strips markup from HTML, decodes entities and collapses whitespace.
+// Block-level elements are separated by a newline, which always ends a sentence.
+func extractPlainText(source string) plainText {
+	src := []rune(source)
+	var pt plainText
 
-		// Add back the punctuation if available
-		if i < len(matches) && len(matches[i]) > 1 {
-			part += matches[i][1]
+	emit := func(r rune, start, end int) {
+		n := len(pt.runes)
+		switch r {
+		case ' ':
+			if n == 0 || pt.runes[n-1] == ' ' || pt.runes[n-1] == '\n' {
+				return
+			}
+		case '\n':
+			if n == 0 || pt.runes[n-1] == '\n' {
+				return
+			}
+			if pt.runes[n-1] == ' ' {
+				pt.runes[n-1] = '\n'
+				return
+			}
 		}
+		pt.runes = append(pt.runes, r)
+		pt.starts = append(pt.starts, start)
+		pt.ends = append(pt.ends, end)
+	}
 
-		// Filter out very short sentences (likely fragments)
-		// Use a lower threshold to support various languages
-		if len(part) > 10 {
-			sentences = append(sentences, part)
+	for i := 0; i < len(src); {
+		r := src[i]
+		switch {
+		case r == '<':
+			end := indexRune(src, '>', i)
+			if end < 0 {
+				// Not a tag, keep the character
+				emit(r, i, i+1)
+				i++
+				continue
+			}
+			name, closing := tagName(src[i+1 : end])
+			if !closing && skippedTags[name] {
+				// Skip to the end of the matching closing tag
+				if closeStart := indexFold(src, "</"+name, end); closeStart >= 0 {
+					if closeEnd := indexRune(src, '>', closeStart); closeEnd >= 0 {
+						end = closeEnd
+					}
+				}
+			}
+			if blockTags[name] {
+				emit('\n', i, end+1)
+			} else {
+				emit(' ', i, end+1)
+			}
+			i = end + 1
+		case r == '&':
+			end := indexRune(src, ';', i)
+			if end > i+1 && end-i <= 10 {
+				entity := string(src[i : end+1])
+				if decoded := html.UnescapeString(entity); decoded != entity {
+					for _, d := range decoded {
+						if unicode.IsSpace(d) {
+							d = ' '
+						}
+						emit(d, i, end+1)
+					}
+					i = end + 1
+					continue
+				}
+			}
+			emit(r, i, i+1)
+			i++
+		case unicode.IsSpace(r):
+			emit(' ', i, i+1)
+			i++
+		default:
+			emit(r, i, i+1)
+			i++
 		}
 	}
 
-	return sentences
+	// Trim trailing whitespace
+	for n := len(pt.runes); n > 0 && (pt.runes[n-1] == ' ' || pt.runes[n-1] == '\n'); n-- {
+		pt.runes, pt.starts, pt.ends = pt.runes[:n-1], pt.starts[:n-1], pt.ends[:n-1]
+	}
+	return pt
+}
+
+// String returns the extracted text with block breaks replaced by spaces.
+func (pt plainText) String() string {
+	return strings.ReplaceAll(string(pt.runes), "\n", " ")
+}
+
+// sourceRange returns the source offsets covering the text runes [start, end).
+func (pt plainText) sourceRange(start, end int) (int, int) {
+	return pt.starts[start], pt.ends[end-1]
 }
 
-// tokenize splits text into lowercase tokens, removing stopwords
-// Uses gse for Chinese word segmentation for better accuracy
-func tokenize(text string) []string {
-	// Convert to lowercase for English
-	text = strings.ToLower(text)
-
-	// Check if text contains Chinese characters
-	hasChinese := false
-	for _, r := range text {
-		if unicode.Is(unicode.Han, r) {
-			hasChinese = true
-			break
+// tagName returns the lowercase element name of a tag body (the text between < and >)
+// and whether it is a closing tag.
+func tagName(body []rune) (string, bool) {
+	closing := len(body) > 0 && body[0] == '/'
+	if closing {
+		body = body[1:]
+	}
+	end := 0
+	for end < len(body) && (unicode.IsLetter(body[end]) || unicode.IsDigit(body[end])) {
+		end++
+	}
+	return strings.ToLower(string(body[:end])), closing
+}
+
+// indexRune returns the index of the first r in s at or after from, or -1.
+func indexRune(s []rune, r rune, from int) int {
+	for i := from; i < len(s); i++ {
+		if s[i] == r {
+			return i
 		}
 	}
+	return -1
+}
 
-	var tokens []string
-
-	if hasChinese {
-		// Use gse for Chinese text segmentation
-		seg := getSegmenter()
-		segments := seg.Cut(text, true) // true = search mode for better recall
-
-		for _, word := range segments {
-			word = strings.TrimSpace(word)
-			// Skip empty strings, stopwords, and very short words
-			if len(word) > 0 && !isStopWord(word) {
-				// For Chinese, single characters can be meaningful
-				// For English, require at least 2 characters
-				isChinese := false
-				for _, r := range word {
-					if unicode.Is(unicode.Han, r) {
-						isChinese = true
-						break
-					}
-				}
-				if isChinese || len(word) > 2 {
-					tokens = append(tokens, word)
-				}
-			}
+// indexFold returns the index of the first case-insensitive match of substr in s
+// at or after from, or -1.
+func indexFold(s []rune, substr string, from int) int {
+	sub := []rune(substr)
+	for i := from; i+len(sub) <= len(s); i++ {
+		if strings.EqualFold(string(s[i:i+len(sub)]), substr) {
+			return i
 		}
-	} else {
-		// Use simple tokenization for non-Chinese text
-		var currentWord strings.Builder
+	}
+	return -1
+}
 
-		for _, r := range text {
-			if unicode.IsLetter(r) || unicode.IsDigit(r) {
-				currentWord.WriteRune(r)
-			} else {
-				if currentWord.Len() > 0 {
-					word := currentWord.String()
-					// Skip stopwords and very short words
-					if len(word) > 2 && !isStopWord(word) {
-						tokens = append(tokens, word)
-					}
-					currentWord.Reset()
-				}
-			}
+// sentence is a sentence of a plainText, delimited by rune offsets [start, end)
+type sentence struct {
+	text  string
+	start int
+	end   int
+}
+
+// sentence-ending punctuation
+const (
+	latinTerminators = ".!?…"
+	cjkTerminators   = "。！？"
+	closingMarks     = "\"'”’»)]」』）】"
+)
+
+// splitSentences splits text into sentences using the punctuation and
+// abbreviation rules of the given language. Newlines always end a sentence.
+func splitSentences(text string, lang *language) []string {
+	spans := splitSentenceSpans([]rune(text), lang)
+	sentences := make([]string, len(spans))
+	for i, span := range spans {
+		sentences[i] = span.text
+	}
+	return sentences
+}
+
+// splitSentenceSpans splits text into sentences and returns their positions.
+func splitSentenceSpans(text []rune, lang *language) []sentence {
+	var sentences []sentence
+	start := 0
+
+	flush := func(end int) {
+		// Trim surrounding whitespace
+		s, e := start, end
+		for s < e && unicode.IsSpace(text[s]) {
+			s++
+		}
+		for e > s && unicode.IsSpace(text[e-1]) {
+			e--
 		}
+		// Filter out very short sentences (likely fragments)
+		// Use a lower threshold to support various languages
+		if part := string(text[s:e]); len(part) > 10 {
+			sentences = append(sentences, sentence{text: part, start: s, end: e})
+		}
+		start = end
+	}
 
-		// Don't forget the last word
-		if currentWord.Len() > 0 {
-			word := currentWord.String()
-			if len(word) > 2 && !isStopWord(word) {
-				tokens = append(tokens, word)
+	for i := 0; i < len(text); i++ {
+		r := text[i]
+		switch {
+		case r == '\n':
+			flush(i)
+		case strings.ContainsRune(cjkTerminators, r):
+			// CJK punctuation ends a sentence without a following space
+			end := skipRunes(text, i+1, cjkTerminators+closingMarks)
+			flush(end)
+			i = end - 1
+		case strings.ContainsRune(latinTerminators, r):
+			end := skipRunes(text, i+1, latinTerminators+closingMarks)
+			if end < len(text) && !unicode.IsSpace(text[end]) {
+				// No space after the punctuation: decimal numbers, URLs, "e.g"
+				continue
 			}
+			if r == '.' && end == i+1 && !lang.endsSentence(text, start, i, end) {
+				continue
+			}
+			flush(end)
+			i = end - 1
 		}
 	}
+	flush(len(text))
+
+	return sentences
+}
 
-	return tokens
+// skipRunes returns the index of the first rune at or after from that is not in set.
+func skipRunes(text []rune, from int, set string) int {
+	for from < len(text) && strings.ContainsRune(set, text[from]) {
+		from++
+	}
+	return from
 }
 
-// isStopWord checks if a word is a common stopword (English and Chinese)
-func isStopWord(word string) bool {
-	stopWords := map[string]bool{
-		// English stopwords
-		"the": true, "a": true, "an": true, "and": true, "or": true, "but": true,
-		"in": true, "on": true, "at": true, "to": true, "for": true, "of": true,
-		"with": true, "by": true, "from": true, "as": true, "is": true, "was": true,
-		"are": true, "were": true, "been": true, "be": true, "have": true, "has": true,
-		"had": true, "do": true, "does": true, "did": true, "will": true, "would": true,
-		"could": true, "should": true, "may": true, "might": true, "must": true,
-		"shall": true, "can": true, "this": true, "that": true, "these": true,
-		"those": true, "it": true, "its": true, "they": true, "them": true,
-		"their": true, "what": true, "which": true, "who": true, "whom": true,
-		"whose": true, "where": true, "when": true, "why": true, "how": true,
-		"all": true, "each": true, "every": true, "both": true, "few": true,
-		"more": true, "most": true, "other": true, "some": true, "such": true,
-		"than": true, "too": true, "very": true, "just": true, "only": true,
-		"own": true, "same": true, "so": true, "not": true, "also": true,
-		"into": true, "about": true, "your": true, "you": true, "our": true,
-		"his": true, "her": true, "my": true, "we": true, "he": true, "she": true,
-		"over": true, "out": true, "up": true, "down": true, "then": true, "now": true,
-		// Extended Chinese stopwords for better accuracy
-		"的": true, "了": true, "和": true, "是": true, "在": true, "有": true,
-		"这": true, "个": true, "我": true, "不": true, "人": true, "都": true,
-		"一": true, "他": true, "就": true, "们": true, "上": true, "也": true,
-		"你": true, "说": true, "着": true, "对": true, "为": true, "与": true,
-		"而": true, "等": true, "被": true, "把": true, "让": true, "给": true,
-		"向": true, "从": true, "到": true, "之": true, "于": true, "或": true,
-		"因": true, "但": true, "却": true, "即": true, "若": true, "虽": true,
-		"所": true, "以": true, "如": true, "则": true, "其": true, "它": true,
-		"她": true, "这个": true, "那个": true, "什么": true, "怎么": true,
-		"为什么": true, "哪个": true, "哪些": true, "这些": true, "那些": true,
-		"可以": true, "能够": true, "已经": true, "正在": true, "将要": true,
-		"可能": true, "应该": true, "必须": true, "需要": true, "没有": true,
-		"因为": true, "所以": true, "但是": true, "而且": true, "或者": true,
-		"如果": true, "虽然": true, "然": true, "此": true, "彼": true,
-		"自己": true, "我们": true, "你们": true, "他们": true, "它们": true,
-		"这里": true, "那里": true, "哪里": true, "任何": true, "某些": true,
-		"每个": true, "很": true, "非常": true, "十分": true, "比较": true,
-		"更": true, "最": true, "太": true, "又": true, "再": true, "还": true,
+// endsSentence reports whether a single period at text[dot] ends the sentence
+// that starts at sentenceStart. Abbreviations, initials and a lowercase
+// continuation indicate that it does not.
+func (l *language) endsSentence(text []rune, sentenceStart, dot, next int) bool {
+	// The word before the period
+	wordStart := dot
+	for wordStart > sentenceStart && !unicode.IsSpace(text[wordStart-1]) {
+		wordStart--
+	}
+	word := strings.ToLower(strings.TrimLeft(string(text[wordStart:dot]), "\"'(“‘«"))
+
+	if l.abbreviations[word] {
+		return false
+	}
+	// Single-letter initials such as "J. R. R. Tolkien"
+	if runes := []rune(word); len(runes) == 1 && unicode.IsLetter(runes[0]) && !l.charBased() {
+		return false
+	}
+
+	// A lowercase letter after the period continues the sentence
+	for j := next; j < len(text); j++ {
+		if unicode.IsSpace(text[j]) {
+			continue
+		}
+		return !unicode.IsLower(text[j])
 	}
-	return stopWords[word]
+	return true
 }
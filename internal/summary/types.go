@@ -2,6 +2,8 @@
 // It implements TF-IDF and TextRank-based sentence scoring for extractive summarization.
 package summary
 
+import "MrRSS/internal/aiusage"
+
 // SummaryLength represents the desired length of the summary
 type SummaryLength string
 
@@ -40,6 +42,8 @@ type SummaryResult struct {
 	Thinking      string `json:"thinking,omitempty"` // AI thinking process (optional)
 	SentenceCount int    `json:"sentence_count"`
 	IsTooShort    bool   `json:"is_too_short"`
+	// Usage is the token usage reported by the AI API (zero for local summaries)
+	Usage aiusage.Usage `json:"-"`
 }
 
 // scoredSentence holds a sentence with its calculated score and position
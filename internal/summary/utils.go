@@ -35,13 +35,14 @@ func getTargetWordCount(length SummaryLength) int {
 	}
 }
 
-// countWordsOrChars counts words for English or characters for Chinese
-func countWordsOrChars(text string, isChinese bool) int {
-	if isChinese {
-		// Count Chinese characters
+// countWordsOrChars counts words, or characters for languages written without spaces
+// (Chinese and Japanese)
+func countWordsOrChars(text string, charBased bool) int {
+	if charBased {
+		// Count CJK characters
 		count := 0
 		for _, r := range text {
-			if unicode.Is(unicode.Han, r) {
+			if isCJKChar(r) {
 				count++
 			}
 		}
@@ -49,7 +50,7 @@ func countWordsOrChars(text string, isChinese bool) int {
 		englishWords := 0
 		inWord := false
 		for _, r := range text {
-			if unicode.IsLetter(r) && !unicode.Is(unicode.Han, r) {
+			if unicode.IsLetter(r) && !isCJKChar(r) {
 				if !inWord {
 					englishWords++
 					inWord = true
@@ -60,7 +61,12 @@ func countWordsOrChars(text string, isChinese bool) int {
 		}
 		return count + englishWords
 	}
-	// Count English words
+	// Count words
 	words := strings.Fields(text)
 	return len(words)
 }
+
+// isCJKChar reports whether r is a Chinese character or Japanese kana
+func isCJKChar(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r)
+}
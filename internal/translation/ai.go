@@ -8,7 +8,9 @@ import (
 	"strings"
 	"time"
 
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/config"
+	"MrRSS/internal/metrics"
 )
 
 // AITranslator implements translation using OpenAI-compatible APIs (GPT, Claude, etc.).
@@ -99,8 +101,15 @@ func parseCustomHeaders(headersJSON string) (map[string]string, error) {
 // Translate translates text to the target language using an OpenAI-compatible API.
 // Automatically detects and adapts to different API formats (OpenAI vs Ollama).
 func (t *AITranslator) Translate(text, targetLang string) (string, error) {
+	result, _, err := t.TranslateWithUsage(text, targetLang)
+	return result, err
+}
+
+// TranslateWithUsage translates text like Translate and also returns the token usage
+// reported by the API. The usage is zero if the API does not report it.
+func (t *AITranslator) TranslateWithUsage(text, targetLang string) (string, aiusage.Usage, error) {
 	if text == "" {
-		return "", nil
+		return "", aiusage.Usage{Cached: true}, nil
 	}
 
 	langName := getLanguageName(targetLang)
@@ -113,23 +122,23 @@ func (t *AITranslator) Translate(text, targetLang string) (string, error) {
 	userPrompt := fmt.Sprintf("Translate to %s:\n%s", langName, text)
 
 	// Try OpenAI format first
-	result, err := t.tryOpenAIFormat(systemPrompt, userPrompt)
-	if err == nil {
-		return result, nil
+	result, usage, openAIErr := t.tryOpenAIFormat(systemPrompt, userPrompt)
+	if openAIErr == nil {
+		return result, usage, nil
 	}
 
 	// If OpenAI format fails, try Ollama format
-	result, err = t.tryOllamaFormat(systemPrompt, userPrompt)
-	if err == nil {
-		return result, nil
+	result, usage, ollamaErr := t.tryOllamaFormat(systemPrompt, userPrompt)
+	if ollamaErr == nil {
+		return result, usage, nil
 	}
 
 	// Both formats failed
-	return "", fmt.Errorf("all API formats failed: OpenAI error: %v, Ollama error: %v", err, err)
+	return "", aiusage.Usage{}, fmt.Errorf("all API formats failed: OpenAI error: %v, Ollama error: %v", openAIErr, ollamaErr)
 }
 
 // tryOpenAIFormat attempts to use OpenAI-compatible API format
-func (t *AITranslator) tryOpenAIFormat(systemPrompt, userPrompt string) (string, error) {
+func (t *AITranslator) tryOpenAIFormat(systemPrompt, userPrompt string) (string, aiusage.Usage, error) {
 	requestBody := map[string]interface{}{
 		"model": t.Model,
 		"messages": []map[string]string{
@@ -142,17 +151,17 @@ func (t *AITranslator) tryOpenAIFormat(systemPrompt, userPrompt string) (string,
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal OpenAI request: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to marshal OpenAI request: %w", err)
 	}
 
 	resp, err := t.sendRequest(jsonBody)
 	if err != nil {
-		return "", fmt.Errorf("OpenAI request failed: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("OpenAI request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("OpenAI API returned status: %d", resp.StatusCode)
+		return "", aiusage.Usage{}, fmt.Errorf("OpenAI API returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
@@ -161,24 +170,28 @@ func (t *AITranslator) tryOpenAIFormat(systemPrompt, userPrompt string) (string,
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int64 `json:"prompt_tokens"`
+			CompletionTokens int64 `json:"completion_tokens"`
+		} `json:"usage"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode OpenAI response: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to decode OpenAI response: %w", err)
 	}
 
 	if len(result.Choices) > 0 && result.Choices[0].Message.Content != "" {
 		// Clean up the response - remove any quotes or extra whitespace
 		translated := strings.TrimSpace(result.Choices[0].Message.Content)
 		translated = strings.Trim(translated, "\"'")
-		return translated, nil
+		return translated, aiusage.Usage{PromptTokens: result.Usage.PromptTokens, CompletionTokens: result.Usage.CompletionTokens}, nil
 	}
 
-	return "", fmt.Errorf("no translation found in OpenAI response")
+	return "", aiusage.Usage{}, fmt.Errorf("no translation found in OpenAI response")
 }
 
 // tryOllamaFormat attempts to use Ollama API format
-func (t *AITranslator) tryOllamaFormat(systemPrompt, userPrompt string) (string, error) {
+func (t *AITranslator) tryOllamaFormat(systemPrompt, userPrompt string) (string, aiusage.Usage, error) {
 	// Combine system and user prompts for Ollama
 	fullPrompt := systemPrompt + "\n\n" + userPrompt
 
@@ -190,36 +203,38 @@ func (t *AITranslator) tryOllamaFormat(systemPrompt, userPrompt string) (string,
 
 	jsonBody, err := json.Marshal(requestBody)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal Ollama request: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to marshal Ollama request: %w", err)
 	}
 
 	resp, err := t.sendRequest(jsonBody)
 	if err != nil {
-		return "", fmt.Errorf("Ollama request failed: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("Ollama request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("Ollama API returned status: %d", resp.StatusCode)
+		return "", aiusage.Usage{}, fmt.Errorf("Ollama API returned status: %d", resp.StatusCode)
 	}
 
 	var result struct {
-		Response string `json:"response"`
-		Done     bool   `json:"done"`
+		Response        string `json:"response"`
+		Done            bool   `json:"done"`
+		PromptEvalCount int64  `json:"prompt_eval_count"`
+		EvalCount       int64  `json:"eval_count"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode Ollama response: %w", err)
+		return "", aiusage.Usage{}, fmt.Errorf("failed to decode Ollama response: %w", err)
 	}
 
 	if result.Done && result.Response != "" {
 		// Clean up the response - remove any quotes or extra whitespace
 		translated := strings.TrimSpace(result.Response)
 		translated = strings.Trim(translated, "\"'")
-		return translated, nil
+		return translated, aiusage.Usage{PromptTokens: result.PromptEvalCount, CompletionTokens: result.EvalCount}, nil
 	}
 
-	return "", fmt.Errorf("no translation found in Ollama response")
+	return "", aiusage.Usage{}, fmt.Errorf("no translation found in Ollama response")
 }
 
 // sendRequest sends the HTTP request with proper headers
@@ -248,7 +263,10 @@ func (t *AITranslator) sendRequest(jsonBody []byte) (*http.Response, error) {
 		}
 	}
 
-	return t.client.Do(req)
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	metrics.ObserveAIRequest(aiusage.FeatureTranslation, start, resp, err)
+	return resp, err
 }
 
 // getLanguageName converts a language code to a human-readable name.
@@ -4,6 +4,9 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"log"
+
+	"MrRSS/internal/aiusage"
+	"MrRSS/internal/langdetect"
 )
 
 // TranslationCache is an interface for caching translations
@@ -30,8 +33,21 @@ func NewCachedTranslator(translator Translator, cache TranslationCache, provider
 
 // Translate translates text, using cache when available
 func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
+	result, _, err := ct.TranslateWithUsage(text, targetLang)
+	return result, err
+}
+
+// TranslateWithUsage translates text like Translate and also returns the token usage
+// of the underlying translator. Cache hits report Cached usage.
+func (ct *CachedTranslator) TranslateWithUsage(text, targetLang string) (string, aiusage.Usage, error) {
 	if text == "" {
-		return "", nil
+		return "", aiusage.Usage{Cached: true}, nil
+	}
+
+	// Text already in the target language needs neither a translation request
+	// nor a cache entry (which would only store an identical string)
+	if langdetect.IsInLanguage(text, targetLang) {
+		return text, aiusage.Usage{Cached: true}, nil
 	}
 
 	// Generate hash for cache lookup
@@ -40,14 +56,14 @@ func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
 	// Try to get from cache first
 	if ct.cache != nil {
 		if cached, found, err := ct.cache.GetCachedTranslation(textHash, targetLang, ct.provider); err == nil && found {
-			return cached, nil
+			return cached, aiusage.Usage{Cached: true}, nil
 		}
 	}
 
 	// Not in cache, perform translation
-	translated, err := ct.translator.Translate(text, targetLang)
+	translated, usage, err := TranslateWithUsage(ct.translator, text, targetLang)
 	if err != nil {
-		return "", err
+		return "", aiusage.Usage{}, err
 	}
 
 	// Cache the result (including when source == translation, meaning no translation needed)
@@ -58,7 +74,7 @@ func (ct *CachedTranslator) Translate(text, targetLang string) (string, error) {
 		}
 	}
 
-	return translated, nil
+	return translated, usage, nil
 }
 
 // hashText creates a SHA256 hash of the text for cache lookup
@@ -5,6 +5,8 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+
+	"MrRSS/internal/aiusage"
 )
 
 // SettingsProvider is an interface for retrieving translation settings.
@@ -54,22 +56,29 @@ func NewDynamicTranslatorWithCache(settings SettingsProvider, cache CacheProvide
 
 // Translate translates text using the currently configured translation provider.
 func (t *DynamicTranslator) Translate(text, targetLang string) (string, error) {
+	result, _, err := t.TranslateWithUsage(text, targetLang)
+	return result, err
+}
+
+// TranslateWithUsage translates text like Translate and also returns the token usage
+// reported by the provider, if any.
+func (t *DynamicTranslator) TranslateWithUsage(text, targetLang string) (string, aiusage.Usage, error) {
 	if text == "" {
-		return "", nil
+		return "", aiusage.Usage{Cached: true}, nil
 	}
 
 	translator, provider, err := t.getTranslatorWithProvider()
 	if err != nil {
-		return "", err
+		return "", aiusage.Usage{}, err
 	}
 
 	// Wrap with caching if cache is available
 	if t.cache != nil {
 		cachedTranslator := NewCachedTranslator(translator, t.cache, provider)
-		return cachedTranslator.Translate(text, targetLang)
+		return cachedTranslator.TranslateWithUsage(text, targetLang)
 	}
 
-	return translator.Translate(text, targetLang)
+	return TranslateWithUsage(translator, text, targetLang)
 }
 
 // getTranslatorWithProvider returns the appropriate translator and provider name based on current settings.
@@ -1,6 +1,7 @@
 package translation
 
 import (
+	"MrRSS/internal/aiusage"
 	"MrRSS/internal/utils"
 	"fmt"
 	"net/http"
@@ -13,6 +14,22 @@ type Translator interface {
 	Translate(text, targetLang string) (string, error)
 }
 
+// UsageTranslator is implemented by translators that can report the token usage of a
+// translation. Usage is marked Cached when no API request was made.
+type UsageTranslator interface {
+	TranslateWithUsage(text, targetLang string) (string, aiusage.Usage, error)
+}
+
+// TranslateWithUsage translates text with t, returning the reported token usage if t
+// supports it and zero usage otherwise.
+func TranslateWithUsage(t Translator, text, targetLang string) (string, aiusage.Usage, error) {
+	if ut, ok := t.(UsageTranslator); ok {
+		return ut.TranslateWithUsage(text, targetLang)
+	}
+	translated, err := t.Translate(text, targetLang)
+	return translated, aiusage.Usage{}, err
+}
+
 // DBInterface defines the minimal database interface needed for proxy settings
 type DBInterface interface {
 	GetSetting(key string) (string, error)
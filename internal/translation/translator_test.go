@@ -217,3 +217,46 @@ func TestCreateHTTPClientWithProxy_EnabledAndDisabled(t *testing.T) {
 		t.Fatalf("expected proxy to be configured when enabled")
 	}
 }
+
+// memoryCache is an in-memory TranslationCache used for testing
+type memoryCache struct {
+	entries map[string]string
+}
+
+func (c *memoryCache) GetCachedTranslation(sourceTextHash, targetLang, provider string) (string, bool, error) {
+	v, ok := c.entries[sourceTextHash+targetLang+provider]
+	return v, ok, nil
+}
+
+func (c *memoryCache) SetCachedTranslation(sourceTextHash, sourceText, targetLang, translatedText, provider string) error {
+	c.entries[sourceTextHash+targetLang+provider] = translatedText
+	return nil
+}
+
+func TestCachedTranslator_SkipsTargetLanguage(t *testing.T) {
+	cache := &memoryCache{entries: make(map[string]string)}
+	translator := NewCachedTranslator(NewMockTranslator(), cache, "mock")
+
+	text := "The new release improves the performance of your apps"
+	result, err := translator.Translate(text, "en")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if result != text {
+		t.Errorf("Expected text to be returned unchanged, got '%s'", result)
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("Expected no cache entries, got %d", len(cache.entries))
+	}
+
+	result, err = translator.Translate(text, "fr")
+	if err != nil {
+		t.Fatalf("Translate failed: %v", err)
+	}
+	if result != "[FR] "+text {
+		t.Errorf("Expected translated text, got '%s'", result)
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("Expected 1 cache entry, got %d", len(cache.entries))
+	}
+}
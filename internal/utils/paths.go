@@ -161,6 +161,20 @@ func GetMediaCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
+// GetPodcastDir returns the full path to the directory of downloaded podcast episodes
+func GetPodcastDir() (string, error) {
+	dataDir, err := GetDataDir()
+	if err != nil {
+		return "", err
+	}
+	podcastDir := filepath.Join(dataDir, "podcasts")
+	err = os.MkdirAll(podcastDir, 0755)
+	if err != nil {
+		return "", err
+	}
+	return podcastDir, nil
+}
+
 // IsWindows returns true if the current platform is Windows
 func IsWindows() bool {
 	return runtime.GOOS == "windows"
@@ -0,0 +1,301 @@
+package websub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+// Subscription states
+const (
+	StateDiscovered = "discovered" // The feed advertises a hub, no subscription requested yet
+	StatePending    = "pending"    // Requested, waiting for the hub to verify it
+	StateActive     = "active"     // Verified, the hub pushes new content
+	StateDenied     = "denied"     // The hub refused the subscription
+	StateFailed     = "failed"     // The subscription request failed
+)
+
+const (
+	// CallbackPath is the API path of the callback URL given to hubs
+	CallbackPath = "/api/websub/callback"
+	// LeaseSeconds is the lease requested from hubs, which may grant another one
+	LeaseSeconds = 7 * 24 * 60 * 60
+	// FallbackPollInterval is how often feeds with an active subscription are still
+	// polled, in case a push was missed
+	FallbackPollInterval = 6 * time.Hour
+	// MaxPushSize is the largest pushed content accepted
+	MaxPushSize = 10 << 20
+
+	// renewMargin is how long before the lease expires a subscription is renewed
+	renewMargin = 24 * time.Hour
+	// retryDelay is how long to wait before requesting a subscription again when
+	// the hub did not verify it or the request failed
+	retryDelay = time.Hour
+	// deniedRetryDelay is how long to wait before requesting a denied subscription again
+	deniedRetryDelay = 24 * time.Hour
+	// checkInterval is how often subscriptions are checked for renewal
+	checkInterval = 5 * time.Minute
+)
+
+var (
+	// ErrUnknownSubscription is returned for pushes to feeds without a subscription
+	ErrUnknownSubscription = errors.New("no websub subscription for this feed")
+	// ErrInvalidSignature is returned for pushed content with a missing or wrong signature
+	ErrInvalidSignature = errors.New("invalid websub signature")
+)
+
+// IngestFunc saves the articles of content pushed for a feed
+type IngestFunc func(ctx context.Context, feed models.Feed, body []byte) error
+
+// Manager subscribes feeds to their hubs and handles the requests of hubs
+type Manager struct {
+	db     *database.DB
+	ingest IngestFunc
+	client *http.Client
+}
+
+// NewManager creates a new manager. Pushed content is passed to ingest.
+func NewManager(db *database.DB, ingest IngestFunc) *Manager {
+	return &Manager{
+		db:     db,
+		ingest: ingest,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// CallbackBase returns the public base URL of the server given to hubs, or an
+// empty string when push subscriptions are disabled. They are only available in
+// server mode, with websub_callback_url set to a URL the hubs can reach.
+func (m *Manager) CallbackBase() string {
+	if !utils.IsServerMode() {
+		return ""
+	}
+	base, _ := m.db.GetSetting("websub_callback_url")
+	return strings.TrimRight(strings.TrimSpace(base), "/")
+}
+
+// CallbackURL returns the callback URL of a feed
+func CallbackURL(base string, feedID int64) string {
+	return base + CallbackPath + "?feed=" + strconv.FormatInt(feedID, 10)
+}
+
+// Start checks the subscriptions periodically until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
+		for {
+			m.Renew(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Renew requests the subscriptions of newly discovered hubs, renews the leases
+// about to expire and retries the failed subscriptions
+func (m *Manager) Renew(ctx context.Context) {
+	base := m.CallbackBase()
+	if base == "" {
+		return
+	}
+	subs, err := m.db.GetWebSubSubscriptions()
+	if err != nil {
+		log.Printf("Error getting websub subscriptions: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		if ctx.Err() != nil {
+			return
+		}
+		if !due(sub, now) {
+			continue
+		}
+		if err := m.Subscribe(ctx, sub, base); err != nil {
+			log.Printf("WebSub subscription of feed %d to %s failed: %v", sub.FeedID, sub.Hub, err)
+		}
+	}
+}
+
+// due reports whether a subscription has to be requested
+func due(sub models.WebSubSubscription, now time.Time) bool {
+	switch sub.State {
+	case StateDiscovered:
+		return true
+	case StateActive:
+		if sub.LeaseExpiresAt == nil {
+			return true
+		}
+		return sub.LeaseExpiresAt.Sub(now) < renewMargin && now.Sub(sub.UpdatedAt) >= retryDelay
+	case StateDenied:
+		return now.Sub(sub.UpdatedAt) >= deniedRetryDelay
+	default:
+		return now.Sub(sub.UpdatedAt) >= retryDelay
+	}
+}
+
+// Subscribe requests a subscription, or the renewal of a subscription, from the hub.
+// The hub then verifies it with a request to the callback URL.
+func (m *Manager) Subscribe(ctx context.Context, sub models.WebSubSubscription, base string) error {
+	// Keep the secret of a subscription being renewed, as the hub signs with it until verified
+	if sub.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		sub.Secret = hex.EncodeToString(secret)
+	}
+
+	form := url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {sub.Topic},
+		"hub.callback":      {CallbackURL(base, sub.FeedID)},
+		"hub.secret":        {sub.Secret},
+		"hub.lease_seconds": {strconv.Itoa(LeaseSeconds)},
+	}
+	err := m.post(ctx, sub.Hub, form)
+	if err != nil {
+		sub.State = StateFailed
+		sub.LastError = err.Error()
+	} else {
+		if sub.State != StateActive {
+			sub.State = StatePending
+		}
+		sub.LastError = ""
+	}
+	if dbErr := m.db.UpdateWebSubSubscription(sub); dbErr != nil {
+		return dbErr
+	}
+	return err
+}
+
+// post sends a subscription request to a hub
+func (m *Manager) post(ctx context.Context, hub string, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hub, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("hub returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Verify handles a verification request of the hub for a feed, with the query
+// parameters of the request. It returns the challenge to echo and whether the
+// request is confirmed; unconfirmed requests are answered with 404.
+func (m *Manager) Verify(feedID int64, query url.Values) (string, bool) {
+	sub, err := m.db.GetWebSubSubscription(feedID)
+	if err != nil {
+		log.Printf("Error getting websub subscription of feed %d: %v", feedID, err)
+		return "", false
+	}
+	topic := query.Get("hub.topic")
+	ours := sub != nil && sub.Topic == topic
+
+	switch query.Get("hub.mode") {
+	case "subscribe":
+		challenge := query.Get("hub.challenge")
+		if !ours || challenge == "" || (sub.State != StatePending && sub.State != StateActive) {
+			return "", false
+		}
+		lease, err := strconv.Atoi(query.Get("hub.lease_seconds"))
+		if err != nil || lease <= 0 {
+			lease = LeaseSeconds
+		}
+		expires := time.Now().Add(time.Duration(lease) * time.Second)
+		sub.State = StateActive
+		sub.LeaseExpiresAt = &expires
+		sub.LastError = ""
+		if err := m.db.UpdateWebSubSubscription(*sub); err != nil {
+			log.Printf("Error saving websub subscription of feed %d: %v", feedID, err)
+			return "", false
+		}
+		log.Printf("WebSub subscription of feed %d active until %s", feedID, expires.Format(time.RFC3339))
+		return challenge, true
+
+	case "unsubscribe":
+		// Subscriptions are only dropped with their feed
+		return query.Get("hub.challenge"), !ours
+
+	case "denied":
+		if ours {
+			sub.State = StateDenied
+			sub.LeaseExpiresAt = nil
+			sub.LastError = query.Get("hub.reason")
+			if err := m.db.UpdateWebSubSubscription(*sub); err != nil {
+				log.Printf("Error saving websub subscription of feed %d: %v", feedID, err)
+			}
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// Push handles content pushed by the hub for a feed. Content with a missing or
+// wrong signature is rejected with ErrInvalidSignature; the hub must still get a
+// success response so that it cannot tell.
+func (m *Manager) Push(ctx context.Context, feedID int64, body []byte, signature string) error {
+	sub, err := m.db.GetWebSubSubscription(feedID)
+	if err != nil {
+		return err
+	}
+	if sub == nil || (sub.State != StateActive && sub.State != StatePending) {
+		return ErrUnknownSubscription
+	}
+	if sub.Secret != "" && !ValidSignature(sub.Secret, body, signature) {
+		return ErrInvalidSignature
+	}
+
+	feed, err := m.db.GetFeedByID(feedID)
+	if err != nil {
+		return err
+	}
+	return m.ingest(ctx, *feed, body)
+}
+
+// PushedFeeds returns the IDs of the feeds with an active subscription, which
+// only need to be polled every FallbackPollInterval
+func (m *Manager) PushedFeeds() map[int64]bool {
+	pushed := make(map[int64]bool)
+	if m.CallbackBase() == "" {
+		return pushed
+	}
+	subs, err := m.db.GetWebSubSubscriptions()
+	if err != nil {
+		log.Printf("Error getting websub subscriptions: %v", err)
+		return pushed
+	}
+	now := time.Now()
+	for _, sub := range subs {
+		if sub.State == StateActive && sub.LeaseExpiresAt != nil && sub.LeaseExpiresAt.After(now) {
+			pushed[sub.FeedID] = true
+		}
+	}
+	return pushed
+}
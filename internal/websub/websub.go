@@ -0,0 +1,96 @@
+// Package websub receives real-time feed updates from WebSub (PubSubHubbub) hubs.
+//
+// Feeds advertising a hub with a rel="hub" link are subscribed to with a callback
+// URL served by the API. The hub verifies the intent of the subscription with a
+// GET request echoing a challenge, then pushes new content with POST requests
+// signed with an HMAC of the subscription's secret. Leases are renewed before
+// they expire; feeds with an active subscription are polled less often.
+package websub
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/xml"
+	"hash"
+	"io"
+	"strings"
+)
+
+// FindHub returns the hub and self links advertised by a feed document, as
+// Atom links or atom:link elements of an RSS channel. Links after the first
+// item are ignored. Returns an empty hub if the feed advertises none.
+func FindHub(data []byte) (hub, self string) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	// Only the ASCII attributes of links are read, so the encoding does not matter
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return hub, self
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "item", "entry":
+			return hub, self
+		case "link":
+			var rel, href string
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "rel":
+					rel = attr.Value
+				case "href":
+					href = strings.TrimSpace(attr.Value)
+				}
+			}
+			if href == "" {
+				continue
+			}
+			for _, r := range strings.Fields(rel) {
+				if r == "hub" && hub == "" {
+					hub = href
+				} else if r == "self" && self == "" {
+					self = href
+				}
+			}
+		}
+	}
+}
+
+// ValidSignature reports whether header, the X-Hub-Signature of pushed content,
+// is a valid HMAC of body with the secret. The header has the form method=hex,
+// where method is sha1, sha256, sha384 or sha512.
+func ValidSignature(secret string, body []byte, header string) bool {
+	method, signature, ok := strings.Cut(header, "=")
+	if !ok {
+		return false
+	}
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
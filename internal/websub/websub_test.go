@@ -0,0 +1,208 @@
+package websub
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"MrRSS/internal/database"
+	"MrRSS/internal/models"
+	"MrRSS/internal/utils"
+)
+
+func TestFindHub(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		hub, self string
+	}{
+		{
+			name: "atom",
+			doc: `<?xml version="1.0" encoding="utf-8"?><feed xmlns="http://www.w3.org/2005/Atom">
+<link rel="alternate" href="https://example.com/"/><link rel="hub" href="https://pubsubhubbub.appspot.com/"/>
+<link rel="self" href="https://example.com/feed.atom"/><entry><link rel="hub" href="https://other.example/"/></entry></feed>`,
+			hub:  "https://pubsubhubbub.appspot.com/",
+			self: "https://example.com/feed.atom",
+		},
+		{
+			name: "rss with atom links",
+			doc: `<?xml version="1.0" encoding="ISO-8859-1"?><rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom"><channel>
+<title>Blog</title><link>https://example.com/</link><atom:link rel="self" href="https://example.com/rss"/>
+<atom:link rel="hub" href=" https://websub.example/ "/><item><title>Post</title></item></channel></rss>`,
+			hub:  "https://websub.example/",
+			self: "https://example.com/rss",
+		},
+		{
+			name: "hub after the first item",
+			doc:  `<rss><channel><item><title>Post</title></item><atom:link rel="hub" href="https://late.example/"/></channel></rss>`,
+		},
+		{
+			name: "not xml",
+			doc:  `{"version": "https://jsonfeed.org/version/1"}`,
+		},
+	}
+	for _, tt := range tests {
+		hub, self := FindHub([]byte(tt.doc))
+		if hub != tt.hub || self != tt.self {
+			t.Errorf("%s: FindHub = %q, %q, want %q, %q", tt.name, hub, self, tt.hub, tt.self)
+		}
+	}
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidSignature(t *testing.T) {
+	body := []byte("<feed/>")
+	if !ValidSignature("key", body, sign("key", body)) {
+		t.Error("valid signature rejected")
+	}
+	for _, header := range []string{"", "sha256", sign("other", body), "md5=00", "sha256=zz"} {
+		if ValidSignature("key", body, header) {
+			t.Errorf("signature %q accepted", header)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Now()
+	soon := now.Add(time.Hour)
+	later := now.Add(5 * 24 * time.Hour)
+	tests := []struct {
+		sub  models.WebSubSubscription
+		want bool
+	}{
+		{models.WebSubSubscription{State: StateDiscovered, UpdatedAt: now}, true},
+		{models.WebSubSubscription{State: StateActive, LeaseExpiresAt: &later, UpdatedAt: now.Add(-48 * time.Hour)}, false},
+		{models.WebSubSubscription{State: StateActive, LeaseExpiresAt: &soon, UpdatedAt: now.Add(-48 * time.Hour)}, true},
+		{models.WebSubSubscription{State: StateActive, LeaseExpiresAt: &soon, UpdatedAt: now.Add(-time.Minute)}, false},
+		{models.WebSubSubscription{State: StatePending, UpdatedAt: now.Add(-time.Minute)}, false},
+		{models.WebSubSubscription{State: StateFailed, UpdatedAt: now.Add(-2 * time.Hour)}, true},
+		{models.WebSubSubscription{State: StateDenied, UpdatedAt: now.Add(-2 * time.Hour)}, false},
+	}
+	for i, tt := range tests {
+		if got := due(tt.sub, now); got != tt.want {
+			t.Errorf("%d: due(%s) = %v, want %v", i, tt.sub.State, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionFlow(t *testing.T) {
+	utils.SetServerMode(true)
+	defer utils.SetServerMode(false)
+
+	db, err := database.NewDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	feedID, err := db.AddFeed(&models.Feed{Title: "Blog", URL: "https://example.com/feed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetSetting("websub_callback_url", "https://rss.example.net/")
+
+	var request url.Values
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		request = r.PostForm
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer hub.Close()
+
+	var pushed []byte
+	m := NewManager(db, func(ctx context.Context, feed models.Feed, body []byte) error {
+		if feed.ID != feedID {
+			t.Errorf("ingested feed %d, want %d", feed.ID, feedID)
+		}
+		pushed = body
+		return nil
+	})
+
+	// Nothing is subscribed before a hub is discovered
+	m.Renew(context.Background())
+	if request != nil {
+		t.Fatal("subscription requested without a hub")
+	}
+
+	if err := db.SaveWebSubHub(feedID, hub.URL, "https://example.com/feed"); err != nil {
+		t.Fatal(err)
+	}
+	m.Renew(context.Background())
+	if request.Get("hub.mode") != "subscribe" || request.Get("hub.topic") != "https://example.com/feed" ||
+		request.Get("hub.callback") != "https://rss.example.net/api/websub/callback?feed=1" || request.Get("hub.secret") == "" {
+		t.Fatalf("unexpected subscription request: %v", request)
+	}
+	sub, _ := db.GetWebSubSubscription(feedID)
+	if sub.State != StatePending {
+		t.Fatalf("state after request = %q, want pending", sub.State)
+	}
+
+	// Verification of another topic is refused
+	if _, ok := m.Verify(feedID, url.Values{"hub.mode": {"subscribe"}, "hub.topic": {"https://evil.example/"}, "hub.challenge": {"x"}}); ok {
+		t.Error("verification of another topic confirmed")
+	}
+	challenge, ok := m.Verify(feedID, url.Values{
+		"hub.mode":          {"subscribe"},
+		"hub.topic":         {"https://example.com/feed"},
+		"hub.challenge":     {"c123"},
+		"hub.lease_seconds": {"3600"},
+	})
+	if !ok || challenge != "c123" {
+		t.Fatalf("Verify = %q, %v", challenge, ok)
+	}
+	sub, _ = db.GetWebSubSubscription(feedID)
+	if sub.State != StateActive || sub.LeaseExpiresAt == nil || time.Until(*sub.LeaseExpiresAt) > time.Hour {
+		t.Errorf("subscription after verification: %+v", sub)
+	}
+	if !m.PushedFeeds()[feedID] {
+		t.Error("verified feed not in PushedFeeds")
+	}
+
+	// Pushed content must be signed with the secret
+	body := []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><entry><title>New</title></entry></feed>`)
+	if err := m.Push(context.Background(), feedID, body, sign("wrong", body)); err != ErrInvalidSignature {
+		t.Errorf("Push with a wrong signature = %v", err)
+	}
+	if pushed != nil {
+		t.Error("content with a wrong signature ingested")
+	}
+	if err := m.Push(context.Background(), feedID, body, sign(request.Get("hub.secret"), body)); err != nil {
+		t.Fatalf("Push = %v", err)
+	}
+	if string(pushed) != string(body) {
+		t.Errorf("ingested %q", pushed)
+	}
+	if err := m.Push(context.Background(), feedID+1, body, ""); err != ErrUnknownSubscription {
+		t.Errorf("Push to an unknown feed = %v", err)
+	}
+
+	// Unsubscribing is only confirmed once the feed is deleted
+	unsubscribe := url.Values{"hub.mode": {"unsubscribe"}, "hub.topic": {"https://example.com/feed"}, "hub.challenge": {"bye"}}
+	if _, ok := m.Verify(feedID, unsubscribe); ok {
+		t.Error("unsubscription of a subscribed feed confirmed")
+	}
+	db.DeleteFeed(feedID)
+	if challenge, ok := m.Verify(feedID, unsubscribe); !ok || challenge != "bye" {
+		t.Errorf("unsubscription of a deleted feed: %q, %v", challenge, ok)
+	}
+
+	// Without a callback URL, push is disabled
+	db.SetSetting("websub_callback_url", "")
+	if m.CallbackBase() != "" || len(m.PushedFeeds()) != 0 {
+		t.Error("WebSub enabled without a callback URL")
+	}
+}
@@ -256,9 +256,12 @@ func generateSettingsKeysGo(schema *SettingsSchema) error {
 	}
 	sort.Strings(keys)
 
-	var keyStrings []string
+	var keyStrings, encryptedKeyStrings []string
 	for _, key := range keys {
 		keyStrings = append(keyStrings, fmt.Sprintf("\"%s\"", key))
+		if schema.Settings[key].Encrypted {
+			encryptedKeyStrings = append(encryptedKeyStrings, fmt.Sprintf("\"%s\"", key))
+		}
 	}
 
 	tmpl := `// Copyright 2026 Ch3nyang & MrRSS Team. All rights reserved.
@@ -272,9 +275,14 @@ package config
 func SettingsKeys() []string {
 	return []string{%s}
 }
+
+// EncryptedSettingsKeys returns the setting keys stored encrypted
+func EncryptedSettingsKeys() []string {
+	return []string{%s}
+}
 `
 
-	content := fmt.Sprintf(tmpl, strings.Join(keyStrings, ", "))
+	content := fmt.Sprintf(tmpl, strings.Join(keyStrings, ", "), strings.Join(encryptedKeyStrings, ", "))
 	return os.WriteFile("internal/config/settings_keys.go", []byte(content), 0644)
 }
 